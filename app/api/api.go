@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"path/filepath"
@@ -14,8 +15,14 @@ import (
 	_ "github.com/tkudlicka/portflux-api/app/docs" // docs is generated by Swag CLI, needs to be imported.
 	"github.com/tkudlicka/portflux-api/app/handlers"
 	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/importers"
+	"github.com/tkudlicka/portflux-api/core/models"
 	"github.com/tkudlicka/portflux-api/core/ports"
 	"github.com/tkudlicka/portflux-api/core/services"
+	"github.com/tkudlicka/portflux-api/core/taxrules"
+	"github.com/tkudlicka/portflux-api/infrastructure/mail"
+	"github.com/tkudlicka/portflux-api/infrastructure/marketdata"
+	"github.com/tkudlicka/portflux-api/infrastructure/notification"
 	"github.com/tkudlicka/portflux-api/infrastructure/postgres"
 )
 
@@ -25,7 +32,26 @@ type api struct {
 }
 
 type svs struct {
-	user ports.UserService
+	user            ports.UserService
+	valuation       ports.ValuationService
+	lot             ports.LotService
+	performance     ports.PerformanceService
+	fxRate          ports.FxRateService
+	ecbImport       ports.ECBImportService
+	price           ports.PriceService
+	priceRefresh    ports.PriceRefreshService
+	alert           ports.AlertService
+	companyEvent    ports.CompanyEventService
+	digest          ports.DigestService
+	corporateAction ports.CorporateActionService
+	taxReport       ports.TaxReportService
+	dividendIncome  ports.DividendIncomeService
+	calendar        ports.CalendarService
+	imports         ports.ImportService
+	export          ports.ExportService
+	journal         ports.JournalService
+	transaction     ports.TransactionService
+	brokerAccount   ports.BrokerAccountService
 }
 
 // New creates a new API
@@ -33,6 +59,27 @@ func New(ctx context.Context, cfg config.Config) (a api) {
 	a.config = cfg
 
 	var userRepo ports.UserRepository
+	var portfolioRepo ports.PortfolioRepository
+	var transactionRepo ports.TransactionRepository
+	var lotRepo ports.LotRepository
+	var holdingRepo ports.HoldingRepository
+	var dividendRepo ports.DividendRepository
+	var dividendWithholdingRepo ports.DividendWithholdingRepository
+	var fxRateRepo ports.FxRateRepository
+	var currencyRepo ports.CurrencyRepository
+	var stockRepo ports.StockRepository
+	var priceRepo ports.PriceRepository
+	var cryptoCurrencyRepo ports.CryptoCurrencyRepository
+	var priceRefreshRunRepo ports.PriceRefreshRunRepository
+	var alertRuleRepo ports.AlertRuleRepository
+	var alertRepo ports.AlertRepository
+	var companyEventRepo ports.CompanyEventRepository
+	var digestRepo ports.DigestRepository
+	var corporateActionRepo ports.CorporateActionRepository
+	var calendarFeedRepo ports.CalendarFeedRepository
+	var brokerRepo ports.BrokerRepository
+	var importBatchRepo ports.ImportBatchRepository
+	var brokerAccountRepo ports.BrokerAccountRepository
 	switch a.config.Database {
 	case "postgres":
 		db, err := infrastructure.ConnectPostgresDB(ctx, a.config.DSN)
@@ -48,14 +95,103 @@ func New(ctx context.Context, cfg config.Config) (a api) {
 		}
 
 		userRepo = postgres.NewUserRepository(db)
+		portfolioRepo = postgres.NewPortfolioRepository(db)
+		transactionRepo = postgres.NewTransactionRepository(db)
+		lotRepo = postgres.NewLotRepository(db)
+		holdingRepo = postgres.NewHoldingRepository(db)
+		dividendRepo = postgres.NewDividendRepository(db)
+		dividendWithholdingRepo = postgres.NewDividendWithholdingRepository(db)
+		fxRateRepo = postgres.NewFxRateRepository(db)
+		currencyRepo = postgres.NewCurrencyRepository(db)
+		stockRepo = postgres.NewStockRepository(db)
+		priceRepo = postgres.NewPriceRepository(db)
+		cryptoCurrencyRepo = postgres.NewCryptoCurrencyRepository(db)
+		priceRefreshRunRepo = postgres.NewPriceRefreshRunRepository(db)
+		alertRuleRepo = postgres.NewAlertRuleRepository(db)
+		alertRepo = postgres.NewAlertRepository(db)
+		companyEventRepo = postgres.NewCompanyEventRepository(db)
+		digestRepo = postgres.NewDigestRepository(db)
+		corporateActionRepo = postgres.NewCorporateActionRepository(db)
+		calendarFeedRepo = postgres.NewCalendarFeedRepository(db)
+		brokerRepo = postgres.NewBrokerRepository(db)
+		importBatchRepo = postgres.NewImportBatchRepository(db)
+		brokerAccountRepo = postgres.NewBrokerAccountRepository(db)
 	default:
 		log.Fatalf("database flag %s not valid", a.config.Database)
 	}
 
+	marketDataCfg := a.config
+	if dir := marketDataCfg.MarketData.FixturesDir; dir != "" && !filepath.IsAbs(dir) {
+		_, filePath, _, _ := runtime.Caller(0)
+		marketDataCfg.MarketData.FixturesDir = filepath.Join(filePath, "../../..", dir)
+	}
+	marketDataProvider, err := marketdata.New(marketDataCfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	mailCfg := a.config
+	if dir := mailCfg.Mail.OutboxDir; dir != "" && !filepath.IsAbs(dir) {
+		_, filePath, _, _ := runtime.Caller(0)
+		mailCfg.Mail.OutboxDir = filepath.Join(filePath, "../../..", dir)
+	}
+	mailer, err := mail.New(mailCfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	adjustedTransactionRepo := services.NewAdjustedTransactionRepository(transactionRepo, corporateActionRepo)
+
 	a.services.user = services.NewUserService(a.config, userRepo)
+	a.services.fxRate = services.NewFxRateService(a.config, fxRateRepo)
+	a.services.ecbImport = services.NewECBImportService(a.config, a.services.fxRate, currencyRepo)
+	a.services.price = services.NewPriceService(a.config, priceRepo, stockRepo)
+	a.services.priceRefresh = services.NewPriceRefreshService(a.config, priceRefreshRunRepo, marketDataProvider, a.services.price, adjustedTransactionRepo, stockRepo, cryptoCurrencyRepo)
+	priceSource := services.NewStoredPriceSource(a.config, priceRepo, services.NewLastTradePriceSource(a.config, adjustedTransactionRepo))
+	a.services.valuation = services.NewValuationService(a.config, portfolioRepo, adjustedTransactionRepo, userRepo, brokerAccountRepo, priceSource, a.services.fxRate)
+	a.services.lot = services.NewLotService(a.config, lotRepo, portfolioRepo, adjustedTransactionRepo)
+	a.services.performance = services.NewPerformanceService(a.config, portfolioRepo, holdingRepo, adjustedTransactionRepo, dividendRepo, userRepo, priceSource, a.services.fxRate)
+	notifier := notification.NewLogNotifier()
+	a.services.alert = services.NewAlertService(a.config, alertRuleRepo, alertRepo, portfolioRepo, adjustedTransactionRepo, priceSource, notifier)
+	a.services.companyEvent = services.NewCompanyEventService(a.config, companyEventRepo, stockRepo, portfolioRepo, adjustedTransactionRepo, marketDataProvider, notifier)
+	a.services.digest = services.NewDigestService(a.config, digestRepo, portfolioRepo, userRepo, adjustedTransactionRepo, stockRepo, cryptoCurrencyRepo, companyEventRepo, a.services.performance, priceSource, mailer)
+	a.services.corporateAction = services.NewCorporateActionService(a.config, corporateActionRepo, stockRepo, transactionRepo, holdingRepo, a.services.lot)
+	a.services.taxReport = services.NewTaxReportService(a.config, portfolioRepo, adjustedTransactionRepo, stockRepo, dividendRepo, dividendWithholdingRepo, a.services.fxRate, taxrules.Rules())
+	a.services.dividendIncome = services.NewDividendIncomeService(a.config, portfolioRepo, adjustedTransactionRepo, stockRepo, dividendRepo, userRepo, priceSource, a.services.fxRate)
+	a.services.calendar = services.NewCalendarService(a.config, calendarFeedRepo, userRepo, portfolioRepo, adjustedTransactionRepo, stockRepo, dividendRepo, companyEventRepo)
+	a.services.imports = services.NewImportService(a.config, importBatchRepo, brokerRepo, portfolioRepo, holdingRepo, transactionRepo, stockRepo, dividendRepo, dividendWithholdingRepo, a.services.lot, importers.Parsers())
+	a.services.export = services.NewExportService(a.config, userRepo, portfolioRepo, holdingRepo, brokerRepo, transactionRepo, stockRepo, cryptoCurrencyRepo, dividendRepo, lotRepo, priceRepo, importers.NewPortfolioPerformanceCodec())
+	a.services.journal = services.NewJournalService(a.config, portfolioRepo, adjustedTransactionRepo, userRepo, stockRepo, cryptoCurrencyRepo, dividendRepo, priceRepo, priceSource, a.services.fxRate)
+	a.services.transaction = services.NewTransactionService(a.config, transactionRepo, portfolioRepo, holdingRepo, a.services.lot)
+	a.services.brokerAccount = services.NewBrokerAccountService(a.config, brokerAccountRepo, userRepo, brokerRepo, portfolioRepo, holdingRepo)
 	return a
 }
 
+// ImportECB imports an ECB eurofxref-hist XML or CSV file into the FX rates
+func (a *api) ImportECB(ctx context.Context, file io.Reader) (models.ECBImportResp, error) {
+	return a.services.ecbImport.Import(ctx, file)
+}
+
+// PriceRefresh returns the service refreshing the last prices from the market data provider
+func (a *api) PriceRefresh() ports.PriceRefreshService {
+	return a.services.priceRefresh
+}
+
+// Alerts returns the service evaluating the price alert rules
+func (a *api) Alerts() ports.AlertService {
+	return a.services.alert
+}
+
+// CompanyEvents returns the service syncing the company events and notifying the portfolios holding the stocks
+func (a *api) CompanyEvents() ports.CompanyEventService {
+	return a.services.companyEvent
+}
+
+// Digests returns the service sending the portfolio summary digests
+func (a *api) Digests() ports.DigestService {
+	return a.services.digest
+}
+
 // Run API
 func (a *api) Run(ctx context.Context, cancel context.CancelFunc) func() error {
 	return func() error {
@@ -65,6 +201,25 @@ func (a *api) Run(ctx context.Context, cancel context.CancelFunc) func() error {
 
 		handlers.SetHealthRoutes(ctx, a.config, router)
 		handlers.SetUserRoutes(ctx, a.config, router, a.services.user)
+		handlers.SetValuationRoutes(ctx, a.config, router, a.services.valuation)
+		handlers.SetLotRoutes(ctx, a.config, router, a.services.lot)
+		handlers.SetPerformanceRoutes(ctx, a.config, router, a.services.performance)
+		handlers.SetFxRateRoutes(ctx, a.config, router, a.services.fxRate)
+		handlers.SetECBImportRoutes(ctx, a.config, router, a.services.ecbImport)
+		handlers.SetPriceRoutes(ctx, a.config, router, a.services.price)
+		handlers.SetPriceRefreshRoutes(ctx, a.config, router, a.services.priceRefresh)
+		handlers.SetAlertRoutes(ctx, a.config, router, a.services.alert)
+		handlers.SetCompanyEventRoutes(ctx, a.config, router, a.services.companyEvent)
+		handlers.SetDigestRoutes(ctx, a.config, router, a.services.digest)
+		handlers.SetCorporateActionRoutes(ctx, a.config, router, a.services.corporateAction)
+		handlers.SetTaxReportRoutes(ctx, a.config, router, a.services.taxReport)
+		handlers.SetDividendIncomeRoutes(ctx, a.config, router, a.services.dividendIncome)
+		handlers.SetCalendarRoutes(ctx, a.config, router, a.services.calendar)
+		handlers.SetImportRoutes(ctx, a.config, router, a.services.imports)
+		handlers.SetExportRoutes(ctx, a.config, router, a.services.export)
+		handlers.SetJournalRoutes(ctx, a.config, router, a.services.journal)
+		handlers.SetTransactionRoutes(ctx, a.config, router, a.services.transaction)
+		handlers.SetBrokerAccountRoutes(ctx, a.config, router, a.services.brokerAccount)
 		router.PathPrefix("/swagger").HandlerFunc(httpSwagger.WrapHandler)
 
 		log.Printf("Version: %s", a.config.Version)
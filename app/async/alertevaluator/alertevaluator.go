@@ -0,0 +1,36 @@
+package alertevaluator
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// Run evaluates the active price alert rules at every interval until the context is done
+func Run(ctx context.Context, cancel context.CancelFunc, s ports.AlertService, interval time.Duration) {
+	defer cancel()
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Printf("recovered panic in async process: %v", rec)
+		}
+	}()
+
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		start := time.Now()
+		result, err := s.Evaluate(ctx)
+		if err != nil {
+			log.Printf("alert evaluation failure, error: %s", err)
+			continue
+		}
+
+		log.Printf("Alert evaluation complete, %d rules evaluated, %d triggered, %d notified, %d undelivered, time elapsed: %dms", result.Rules, result.Triggered, result.Notified, result.Undelivered, time.Since(start).Milliseconds())
+	}
+}
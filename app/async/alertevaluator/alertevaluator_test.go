@@ -0,0 +1,47 @@
+package alertevaluator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestRun_EvaluateThenContextCancelled checks that Run evaluates the alert rules at every interval until the context gets cancelled
+func TestRun_EvaluateThenContextCancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	expectedError := context.DeadlineExceeded.Error()
+
+	alertServiceMock := mocks.NewAlertService(t)
+	alertServiceMock.On(testutils.FunctionName(t, ports.AlertService.Evaluate), mock.Anything).Return(models.AlertEvaluationResp{Rules: 1, Triggered: 1, Notified: 1}, nil)
+
+	// Act
+	Run(ctx, cancel, alertServiceMock, 10*time.Millisecond)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+}
+
+// TestRun_FailedEvaluationThenContextCancelled checks that Run keeps running after a failed evaluation until the context gets cancelled
+func TestRun_FailedEvaluationThenContextCancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	expectedError := context.DeadlineExceeded.Error()
+
+	alertServiceMock := mocks.NewAlertService(t)
+	alertServiceMock.On(testutils.FunctionName(t, ports.AlertService.Evaluate), mock.Anything).Return(models.AlertEvaluationResp{}, errors.New("database unavailable"))
+
+	// Act
+	Run(ctx, cancel, alertServiceMock, 10*time.Millisecond)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+}
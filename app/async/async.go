@@ -5,23 +5,48 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/tkudlicka/portflux-api/app/async/alertevaluator"
+	"github.com/tkudlicka/portflux-api/app/async/digestsender"
+	"github.com/tkudlicka/portflux-api/app/async/eventnotifier"
 	"github.com/tkudlicka/portflux-api/app/async/healthchecker"
+	"github.com/tkudlicka/portflux-api/app/async/pricerefresher"
 	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/ports"
 )
 
 type async struct {
-	config config.Config
+	config       config.Config
+	priceRefresh ports.PriceRefreshService
+	alerts       ports.AlertService
+	events       ports.CompanyEventService
+	digests      ports.DigestService
 }
 
-func New(cfg config.Config) async {
+func New(cfg config.Config, priceRefresh ports.PriceRefreshService, alerts ports.AlertService, events ports.CompanyEventService, digests ports.DigestService) async {
 	return async{
-		config: cfg,
+		config:       cfg,
+		priceRefresh: priceRefresh,
+		alerts:       alerts,
+		events:       events,
+		digests:      digests,
 	}
 }
 
 func (a async) Run(ctx context.Context, cancel context.CancelFunc) func() error {
 	return func() error {
 		go healthchecker.Run(ctx, cancel, fmt.Sprintf("http://:%d/health", a.config.Port), a.config.Async.Interval.Duration)
+		if a.priceRefresh != nil {
+			go pricerefresher.Run(ctx, cancel, a.priceRefresh, a.config.Async.Interval.Duration)
+		}
+		if a.alerts != nil {
+			go alertevaluator.Run(ctx, cancel, a.alerts, a.config.Async.Interval.Duration)
+		}
+		if a.events != nil {
+			go eventnotifier.Run(ctx, cancel, a.events, a.config.Async.Interval.Duration)
+		}
+		if a.digests != nil {
+			go digestsender.Run(ctx, cancel, a.digests, a.config.Async.Interval.Duration)
+		}
 
 		for ctx.Err() == nil {
 			<-time.After(1 * time.Second)
@@ -7,18 +7,27 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/test/mocks"
 )
 
 // TestNew_Ok checks that New creates a new async struct with the expected values
 func TestNew_Ok(t *testing.T) {
 	// Arrange
 	expectedConfig := config.Config{}
+	expectedPriceRefresh := mocks.NewPriceRefreshService(t)
+	expectedAlerts := mocks.NewAlertService(t)
+	expectedEvents := mocks.NewCompanyEventService(t)
+	expectedDigests := mocks.NewDigestService(t)
 
 	// Act
-	async := New(expectedConfig)
+	async := New(expectedConfig, expectedPriceRefresh, expectedAlerts, expectedEvents, expectedDigests)
 
 	// Assert
 	assert.Equal(t, expectedConfig, async.config)
+	assert.Equal(t, expectedPriceRefresh, async.priceRefresh)
+	assert.Equal(t, expectedAlerts, async.alerts)
+	assert.Equal(t, expectedEvents, async.events)
+	assert.Equal(t, expectedDigests, async.digests)
 }
 
 // TestRun_ContextCancelled checks that Run finishes and returns the expected error when the context gets cancelled
@@ -0,0 +1,36 @@
+package digestsender
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// Run sends the portfolio digests of the periods that ended at every interval until the context is done
+func Run(ctx context.Context, cancel context.CancelFunc, s ports.DigestService, interval time.Duration) {
+	defer cancel()
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Printf("recovered panic in async process: %v", rec)
+		}
+	}()
+
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		result, err := s.Send(ctx)
+		if err != nil {
+			log.Printf("digest sending failure, error: %s", err)
+			continue
+		}
+		if result.Sent > 0 || result.Undelivered > 0 {
+			log.Printf("Digest sending complete, %d portfolios, %d sent, %d undelivered", result.Portfolios, result.Sent, result.Undelivered)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package digestsender
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestRun_SendThenContextCancelled checks that Run sends the digests at every interval until the context gets cancelled
+func TestRun_SendThenContextCancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	expectedError := context.DeadlineExceeded.Error()
+
+	digestServiceMock := mocks.NewDigestService(t)
+	digestServiceMock.On(testutils.FunctionName(t, ports.DigestService.Send), mock.Anything).Return(models.DigestSendResp{Portfolios: 1, Sent: 1}, nil)
+
+	// Act
+	Run(ctx, cancel, digestServiceMock, 10*time.Millisecond)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+}
+
+// TestRun_FailedSendThenContextCancelled checks that Run keeps running after a failed run until the context gets cancelled
+func TestRun_FailedSendThenContextCancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	expectedError := context.DeadlineExceeded.Error()
+
+	digestServiceMock := mocks.NewDigestService(t)
+	digestServiceMock.On(testutils.FunctionName(t, ports.DigestService.Send), mock.Anything).Return(models.DigestSendResp{}, errors.New("database unavailable"))
+
+	// Act
+	Run(ctx, cancel, digestServiceMock, 10*time.Millisecond)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+}
@@ -0,0 +1,41 @@
+package eventnotifier
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// Run syncs the upcoming company events and notifies the portfolios holding the stocks at every interval until the context is done
+func Run(ctx context.Context, cancel context.CancelFunc, s ports.CompanyEventService, interval time.Duration) {
+	defer cancel()
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Printf("recovered panic in async process: %v", rec)
+		}
+	}()
+
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		synced, err := s.Sync(ctx)
+		if err != nil {
+			log.Printf("company event sync failure, error: %s", err)
+		} else {
+			log.Printf("Company event sync complete, %d events of %d stocks stored, %d stocks failed", synced.Events, synced.Stocks, synced.Failed)
+		}
+
+		result, err := s.Notify(ctx)
+		if err != nil {
+			log.Printf("company event notification failure, error: %s", err)
+			continue
+		}
+		log.Printf("Company event notification complete, %d upcoming events, %d notified, %d undelivered", result.Events, result.Notified, result.Undelivered)
+	}
+}
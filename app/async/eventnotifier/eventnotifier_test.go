@@ -0,0 +1,49 @@
+package eventnotifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestRun_NotifyThenContextCancelled checks that Run syncs and notifies the company events at every interval until the context gets cancelled
+func TestRun_NotifyThenContextCancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	expectedError := context.DeadlineExceeded.Error()
+
+	companyEventServiceMock := mocks.NewCompanyEventService(t)
+	companyEventServiceMock.On(testutils.FunctionName(t, ports.CompanyEventService.Sync), mock.Anything).Return(models.CompanyEventSyncResp{Stocks: 1, Events: 2}, nil)
+	companyEventServiceMock.On(testutils.FunctionName(t, ports.CompanyEventService.Notify), mock.Anything).Return(models.CompanyEventNotifyResp{Events: 1, Notified: 1}, nil)
+
+	// Act
+	Run(ctx, cancel, companyEventServiceMock, 10*time.Millisecond)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+}
+
+// TestRun_FailedSyncThenContextCancelled checks that Run still notifies after a failed sync and keeps running until the context gets cancelled
+func TestRun_FailedSyncThenContextCancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	expectedError := context.DeadlineExceeded.Error()
+
+	companyEventServiceMock := mocks.NewCompanyEventService(t)
+	companyEventServiceMock.On(testutils.FunctionName(t, ports.CompanyEventService.Sync), mock.Anything).Return(models.CompanyEventSyncResp{}, errors.New("provider unavailable"))
+	companyEventServiceMock.On(testutils.FunctionName(t, ports.CompanyEventService.Notify), mock.Anything).Return(models.CompanyEventNotifyResp{}, errors.New("database unavailable"))
+
+	// Act
+	Run(ctx, cancel, companyEventServiceMock, 10*time.Millisecond)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+}
@@ -0,0 +1,38 @@
+package pricerefresher
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// Run refreshes the last prices of the instruments in open positions at every interval until the context is done
+func Run(ctx context.Context, cancel context.CancelFunc, s ports.PriceRefreshService, interval time.Duration) {
+	defer cancel()
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Printf("recovered panic in async process: %v", rec)
+		}
+	}()
+
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		run, err := s.Refresh(ctx)
+		if err != nil {
+			log.Printf("price refresh failure, error: %s", err)
+			continue
+		}
+
+		for _, failure := range run.Failures {
+			log.Printf("price refresh failure for %s %s, error: %s", failure.InstrumentType, failure.InstrumentID, failure.Error)
+		}
+		log.Printf("Price refresh complete, %d of %d instruments updated, %d failed, time elapsed: %dms", run.Updated, run.Instruments, run.Failed, run.DurationMs)
+	}
+}
@@ -0,0 +1,47 @@
+package pricerefresher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestRun_RefreshThenContextCancelled checks that Run refreshes the prices at every interval until the context gets cancelled
+func TestRun_RefreshThenContextCancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	expectedError := context.DeadlineExceeded.Error()
+
+	priceRefreshMock := mocks.NewPriceRefreshService(t)
+	priceRefreshMock.On(testutils.FunctionName(t, ports.PriceRefreshService.Refresh), mock.Anything).Return(models.PriceRefreshRunResp{Instruments: 1, Updated: 1}, nil)
+
+	// Act
+	Run(ctx, cancel, priceRefreshMock, 10*time.Millisecond)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+}
+
+// TestRun_FailedRefreshThenContextCancelled checks that Run keeps running after a failed refresh until the context gets cancelled
+func TestRun_FailedRefreshThenContextCancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	expectedError := context.DeadlineExceeded.Error()
+
+	priceRefreshMock := mocks.NewPriceRefreshService(t)
+	priceRefreshMock.On(testutils.FunctionName(t, ports.PriceRefreshService.Refresh), mock.Anything).Return(models.PriceRefreshRunResp{}, errors.New("provider unavailable"))
+
+	// Act
+	Run(ctx, cancel, priceRefreshMock, 10*time.Millisecond)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+}
@@ -50,6 +50,299 @@ var doc = `{
                 }
             }
         },
+        "/v1/admin/company-event/import": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Imports company events from a CSV file with Symbol, Type and Date columns and the optional Amount, Currency, Numerator, Denominator, Description and Source ones. Importing a file again replaces the events already stored for the same stock, type and day.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "Company events"
+                ],
+                "summary": "Import company events",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file of company events",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CompanyEventImportResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/admin/company-event/sync": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Fetches now from the market data provider the upcoming dividends and splits of the stocks held in open positions",
+                "tags": [
+                    "Company events"
+                ],
+                "summary": "Sync company events",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CompanyEventSyncResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/admin/digest/send": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sends now the digests of the last complete periods not sent yet to the owners of the portfolios with the summary enabled",
+                "tags": [
+                    "Digests"
+                ],
+                "summary": "Send digests",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DigestSendResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/admin/fx-rate/ecb": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Imports an ECB eurofxref-hist XML or CSV file into the FX rates, crossing the rates of the registered currencies through the euro. Importing a file again replaces the rates already stored for the same pair and date.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "FX rates"
+                ],
+                "summary": "Import ECB reference rates",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "ECB eurofxref-hist XML or CSV file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ECBImportResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/admin/price-refresh": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Refreshes now the last prices of the instruments in open positions from the market data provider and returns the stats of the run",
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Refresh prices",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceRefreshRunResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/admin/price-refresh/runs": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the stats of the most recent price refresh runs, newest first",
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get price refresh runs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of runs, defaults to 20 and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PriceRefreshRunResp"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/brokers": {
             "get": {
                 "security": [
@@ -411,26 +704,30 @@ var doc = `{
                 }
             }
         },
-        "/v1/user": {
+        "/v1/calendar/{token}.ics": {
             "get": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
+                "description": "Gets the iCalendar feed of the dividends and company events of the stocks held by the user owning the token",
+                "produces": [
+                    "text/calendar"
                 ],
-                "description": "Gets all the users",
                 "tags": [
-                    "Users"
+                    "Calendar"
                 ],
-                "summary": "Get all users",
-                "responses": {
+                "summary": "Get calendar feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Calendar token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.UserResp"
-                            }
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -439,12 +736,6 @@ var doc = `{
                             "type": "object"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "object"
-                        }
-                    },
                     "408": {
                         "description": "Request Timeout",
                         "schema": {
@@ -458,21 +749,31 @@ var doc = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/v1/fx-rate/many": {
             "post": {
-                "description": "Creates a new user",
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates many FX rates atomically, replacing the rates already stored for the same pair and date",
                 "tags": [
-                    "Users"
+                    "FX rates"
                 ],
-                "summary": "Create user",
+                "summary": "Create many FX rates",
                 "parameters": [
                     {
-                        "description": "New user to be created",
-                        "name": "user",
+                        "description": "New FX rates to be created",
+                        "name": "rates",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateUserReq"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CreateFxRateReq"
+                            }
                         }
                     }
                 ],
@@ -480,7 +781,7 @@ var doc = `{
                     "201": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.CreationResp"
+                            "$ref": "#/definitions/models.MultiCreationResp"
                         }
                     },
                     "400": {
@@ -489,6 +790,12 @@ var doc = `{
                             "type": "object"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
                     "408": {
                         "description": "Request Timeout",
                         "schema": {
@@ -504,32 +811,45 @@ var doc = `{
                 }
             }
         },
-        "/v1/user/email/{email}": {
+        "/v1/fx-rate/{base}/{quote}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Gets a user by email",
+                "description": "Gets the rate of one unit of the base currency in the quote currency on a date, falling back to the nearest previous published rate",
                 "tags": [
-                    "Users"
+                    "FX rates"
                 ],
-                "summary": "Get user by email",
+                "summary": "Get FX rate",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Email",
-                        "name": "email",
+                        "description": "Base currency",
+                        "name": "base",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quote currency",
+                        "name": "quote",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date in YYYY-MM-DD format, defaults to today",
+                        "name": "date",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.UserResp"
+                            "$ref": "#/definitions/models.FxRateResp"
                         }
                     },
                     "400": {
@@ -544,6 +864,12 @@ var doc = `{
                             "type": "object"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
                     "408": {
                         "description": "Request Timeout",
                         "schema": {
@@ -559,29 +885,44 @@ var doc = `{
                 }
             }
         },
-        "/v1/user/login": {
-            "post": {
-                "description": "Logs in an user",
+        "/v1/holding/{id}/performance": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the time-weighted and money-weighted (XIRR) returns of a holding over a date range, plus their annualized variants. The portfolio of the holding must belong to the user of the token.",
                 "tags": [
-                    "Users"
+                    "Holdings"
                 ],
-                "summary": "Login user",
+                "summary": "Get holding performance",
                 "parameters": [
                     {
-                        "description": "Login request",
-                        "name": "login",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.LoginUserReq"
-                        }
+                        "type": "string",
+                        "description": "Holding ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD), defaults to the first transaction",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD), defaults to today",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.LoginUserResp"
+                            "$ref": "#/definitions/models.PerformanceResp"
                         }
                     },
                     "400": {
@@ -590,6 +931,12 @@ var doc = `{
                             "type": "object"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
                     "408": {
                         "description": "Request Timeout",
                         "schema": {
@@ -605,32 +952,32 @@ var doc = `{
                 }
             }
         },
-        "/v1/user/many": {
-            "post": {
-                "description": "Creates many users atomically",
+        "/v1/import/{batchId}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deletes the transactions, dividends and withholding taxes created by an import batch and the batch itself in one database transaction. The batch must have been imported into a portfolio of the user of the token, and the dividends of stocks held in other portfolios are kept.",
                 "tags": [
-                    "Users"
+                    "Imports"
                 ],
-                "summary": "Create many users",
+                "summary": "Roll back import",
                 "parameters": [
                     {
-                        "description": "New users to be created",
-                        "name": "users",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.CreateUserReq"
-                            }
-                        }
+                        "type": "string",
+                        "description": "Import batch ID",
+                        "name": "batchId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
+                    "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MultiCreationResp"
+                            "$ref": "#/definitions/models.ImportRollbackResp"
                         }
                     },
                     "400": {
@@ -639,6 +986,12 @@ var doc = `{
                             "type": "object"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
                     "408": {
                         "description": "Request Timeout",
                         "schema": {
@@ -654,22 +1007,22 @@ var doc = `{
                 }
             }
         },
-        "/v1/user/{id}": {
+        "/v1/portfolio/{id}/alert-rule": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Gets a user by ID",
+                "description": "Gets the price alert rules of a portfolio. The portfolio must belong to the user of the token.",
                 "tags": [
-                    "Users"
+                    "Alerts"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Get alert rules",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID",
+                        "description": "Portfolio ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -679,7 +1032,66 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.UserResp"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AlertRuleResp"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates an active price alert rule for an instrument of a portfolio. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Alerts"
+                ],
+                "summary": "Create alert rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New alert rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateAlertRuleReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreationResp"
                         }
                     },
                     "400": {
@@ -694,6 +1106,12 @@ var doc = `{
                             "type": "object"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
                     "408": {
                         "description": "Request Timeout",
                         "schema": {
@@ -707,35 +1125,41 @@ var doc = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/v1/portfolio/{id}/alert-rule/{ruleId}": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete a user",
+                "description": "Gets a price alert rule of a portfolio. The portfolio must belong to the user of the token.",
                 "tags": [
-                    "Users"
+                    "Alerts"
                 ],
-                "summary": "Delete user",
+                "summary": "Get alert rule",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID",
+                        "description": "Portfolio ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Alert rule ID",
+                        "name": "ruleId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                        "description": "OK",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/models.AlertRuleResp"
                         }
                     },
                     "401": {
@@ -744,6 +1168,12 @@ var doc = `{
                             "type": "object"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
                     "408": {
                         "description": "Request Timeout",
                         "schema": {
@@ -758,47 +1188,45 @@ var doc = `{
                     }
                 }
             },
-            "patch": {
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Updates a user",
+                "description": "Deletes a price alert rule of a portfolio together with its alerts. The portfolio must belong to the user of the token.",
                 "tags": [
-                    "Users"
+                    "Alerts"
                 ],
-                "summary": "Update user",
+                "summary": "Delete alert rule",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID",
+                        "description": "Portfolio ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "User",
-                        "name": "User",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateUserReq"
-                        }
+                        "type": "string",
+                        "description": "Alert rule ID",
+                        "name": "ruleId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK"
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object"
                         }
@@ -816,105 +1244,5073 @@ var doc = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "models.BrokerResp": {
-            "type": "object",
-            "properties": {
-                "brokerid": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "extid": {
-                    "type": "string"
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Updates the condition, threshold or state of a price alert rule of a portfolio. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Alerts"
+                ],
+                "summary": "Update alert rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Alert rule ID",
+                        "name": "ruleId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Alert rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateAlertRuleReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/portfolio/{id}/alerts": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the price alerts triggered in a portfolio, newest first. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Alerts"
+                ],
+                "summary": "Get alerts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AlertResp"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/portfolio/{id}/cash": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the cash balance of a portfolio in each currency, with the deposits, withdrawals, trades, dividends, interest and fees it adds up. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Get cash balances",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CashBalancesResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/portfolio/{id}/cost-basis-method": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Switches the cost basis method of a portfolio and rebuilds its lots and realized gains. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Portfolios"
+                ],
+                "summary": "Update cost basis method",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cost basis method",
+                        "name": "method",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateCostBasisMethodReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LedgerResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/portfolio/{id}/digest": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the summary digest of a portfolio for the last complete week or month: value change, top movers, dividends received and upcoming events. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Digests"
+                ],
+                "summary": "Get portfolio digest",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Period of the digest: weekly or monthly, defaults to weekly",
+                        "name": "period",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DigestResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/portfolio/{id}/dividends/calendar": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the dividends paid to a portfolio and the ones declared or expected to be paid within a date range, by month. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Portfolios"
+                ],
+                "summary": "Get dividend calendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD), defaults to a year ago",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD), defaults to a year from today",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DividendCalendarResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/portfolio/{id}/dividends/forecast": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the dividends a portfolio is expected to receive over the next twelve months from the payment pattern of the held stocks. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Portfolios"
+                ],
+                "summary": "Get dividend forecast",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DividendForecastResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/portfolio/{id}/dividends/yield": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the yield on cost and the current yield of the stocks held by a portfolio from the dividends of the last twelve months. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Portfolios"
+                ],
+                "summary": "Get dividend yield",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DividendYieldResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/portfolio/{id}/export": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Exports the holdings, transactions, dividends and realized gains of a portfolio as a CSV file of a single type, an XLSX workbook with a sheet per type, a JSON archive of the whole portfolio that can be restored into another account, or a Portfolio Performance XML file. The portfolio must belong to the user of the token.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/json",
+                    "application/xml"
+                ],
+                "tags": [
+                    "Exports"
+                ],
+                "summary": "Export portfolio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format: csv, xlsx, json or pp",
+                        "name": "format",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type exported as CSV: holdings, transactions, dividends or realized_gains",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/portfolio/{id}/import": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Imports the trades and dividends of a statement exported by a broker into a portfolio as an import batch, or previews them in a dry run with the parsed, skipped, invalid and duplicate lines. A file already imported into the portfolio is rejected. The portfolio must belong to the user of the token.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "Imports"
+                ],
+                "summary": "Import broker statement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Statement exported by the broker",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Slug of the broker: degiro, interactive-brokers, trading212 or revolut",
+                        "name": "broker",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format of the statement when it was not exported by the broker: sharesight or ofx",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Preview the import without storing it",
+                        "name": "dry_run",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/portfolio/{id}/journal": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Exports the trades and dividends of a portfolio as a Beancount or hledger journal, with commodity declarations, lots held at cost paid from the cash of the portfolio and price directives from the stored price history and FX rates, so its balances reconcile with the portfolio valuation. The portfolio must belong to the user of the token.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Exports"
+                ],
+                "summary": "Export portfolio journal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format: beancount or hledger",
+                        "name": "format",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/portfolio/{id}/lots": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the buy lots of a portfolio with their remaining quantity. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Portfolios"
+                ],
+                "summary": "Get lots",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.LotResp"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/portfolio/{id}/lots/rebuild": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Recomputes the lots and realized gains of a portfolio from its transactions. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Portfolios"
+                ],
+                "summary": "Rebuild lots",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LedgerResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/portfolio/{id}/performance": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the time-weighted and money-weighted (XIRR) returns of a portfolio over a date range, plus their annualized variants. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Portfolios"
+                ],
+                "summary": "Get portfolio performance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD), defaults to the first transaction",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD), defaults to today",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PerformanceResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/portfolio/{id}/realized-gains": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the realized gains of a portfolio, one per sold lot. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Portfolios"
+                ],
+                "summary": "Get realized gains",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RealizedGainResp"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/portfolio/{id}/tax-report": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the gains realized by a portfolio in a financial year with the tax treatment the rules of the portfolio's tax country gave to each sale. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Portfolios"
+                ],
+                "summary": "Get tax report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Year the financial year starts in, defaults to the financial year in progress",
+                        "name": "year",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaxReportResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/portfolio/{id}/tax-report/dividends": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the dividends paid to a portfolio in a financial year with the tax withheld by the issuer countries and the foreign tax credit they give in the portfolio's tax country. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Portfolios"
+                ],
+                "summary": "Get dividend tax report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Year the financial year starts in, defaults to the financial year in progress",
+                        "name": "year",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DividendTaxReportResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/portfolio/{id}/transaction": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the transactions of a portfolio as they were entered. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Get transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TransactionResp"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates a buy, sell, dividend, fee, deposit, withdrawal, interest, transfer or split in a holding of a portfolio. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Create transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New transaction",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTransactionReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreationResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/portfolio/{id}/transaction/{transactionId}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets a transaction of a portfolio. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Get transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "transactionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deletes a transaction of a portfolio. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Delete transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "transactionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Replaces a transaction of a portfolio. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Update transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "transactionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transaction",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateTransactionReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/portfolio/{id}/valuation": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the open positions of a portfolio valued at the latest prices. The portfolio must belong to the user of the token.",
+                "tags": [
+                    "Portfolios"
+                ],
+                "summary": "Get portfolio valuation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValuationResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/price/import": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Imports the daily prices of an instrument from a Yahoo Finance or Stooq CSV file, replacing the prices already stored for the same days",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Import prices",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file with Date, Open, High, Low, Close and Volume columns",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stock or cryptocurrency ID",
+                        "name": "instrumentid",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Instrument type: stock or cryptocurrency",
+                        "name": "instrument_type",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices",
+                        "name": "currency",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Source of the prices, defaults to csv",
+                        "name": "source",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceImportResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/price/last": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sets the intraday last price of an instrument, replacing the previous one",
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Set last price",
+                "parameters": [
+                    {
+                        "description": "Last price",
+                        "name": "price",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SetLastPriceReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/price/many": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates many daily prices atomically, replacing the prices already stored for the same instrument and day",
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Create many prices",
+                "parameters": [
+                    {
+                        "description": "New daily prices to be created",
+                        "name": "prices",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CreatePriceReq"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MultiCreationResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/stock/{id}/corporate-actions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the corporate actions of a stock, including the mergers and spin-offs into it, oldest first",
+                "tags": [
+                    "Corporate actions"
+                ],
+                "summary": "Get corporate actions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CorporateActionResp"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Records a split, reverse split, symbol change, merger or spin-off of a stock. Positions, lots and returns take it into account from then on, without changing the stored transactions.",
+                "tags": [
+                    "Corporate actions"
+                ],
+                "summary": "Create corporate action",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New corporate action",
+                        "name": "action",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateCorporateActionReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreationResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/stock/{id}/corporate-actions/{actionId}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deletes a corporate action of a stock",
+                "tags": [
+                    "Corporate actions"
+                ],
+                "summary": "Delete corporate action",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Corporate action ID",
+                        "name": "actionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/stock/{id}/events": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the earnings, dividend, split and delisting events of a stock over a date range, oldest first",
+                "tags": [
+                    "Company events"
+                ],
+                "summary": "Get company events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CompanyEventResp"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates an event of a stock, replacing the event of the same type already stored for the same day",
+                "tags": [
+                    "Company events"
+                ],
+                "summary": "Create company event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New company event",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateCompanyEventReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreationResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/stock/{id}/events/{eventId}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deletes an event of a stock",
+                "tags": [
+                    "Company events"
+                ],
+                "summary": "Delete company event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company event ID",
+                        "name": "eventId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/stock/{id}/prices": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the prices of a stock over a date range by day, week or month, the business days without a daily price and the intraday last price",
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get stock prices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD), defaults to the first stored price",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD), defaults to today",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interval of the prices: 1d, 1wk or 1mo, defaults to 1d",
+                        "name": "interval",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceHistoryResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets all the users",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get all users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.UserResp"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new user",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create user",
+                "parameters": [
+                    {
+                        "description": "New user to be created",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateUserReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreationResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/email/{email}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets a user by email",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user by email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/login": {
+            "post": {
+                "description": "Logs in an user",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Login user",
+                "parameters": [
+                    {
+                        "description": "Login request",
+                        "name": "login",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginUserReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginUserResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/many": {
+            "post": {
+                "description": "Creates many users atomically",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create many users",
+                "parameters": [
+                    {
+                        "description": "New users to be created",
+                        "name": "users",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CreateUserReq"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MultiCreationResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets a user by ID",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a user",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Updates a user",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User",
+                        "name": "User",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateUserReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{id}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates a portfolio for a user from a JSON archive written by the portfolio export or from a Portfolio Performance XML file, whose securities accounts become holdings at the broker they are named after or else at the broker given. Invalid files create nothing, and a failed restore deletes what it created. Only the user or an admin can restore an archive for the user.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "Exports"
+                ],
+                "summary": "Restore portfolio archive",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format: json (default) or pp",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Slug of the broker of the Portfolio Performance securities accounts not named after a broker",
+                        "name": "broker",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "JSON archive or Portfolio Performance file of a portfolio",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ArchiveRestoreResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{id}/broker-account": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the broker accounts of a user. Only the user or an admin can get them.",
+                "tags": [
+                    "Broker accounts"
+                ],
+                "summary": "Get broker accounts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BrokerAccountResp"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates an account of a user at a broker of the catalog, storing only the masked account number. Only the user or an admin can create it.",
+                "tags": [
+                    "Broker accounts"
+                ],
+                "summary": "Create broker account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New broker account",
+                        "name": "account",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateBrokerAccountReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreationResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{id}/broker-account/{accountId}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets a broker account of a user. Only the user or an admin can get it.",
+                "tags": [
+                    "Broker accounts"
+                ],
+                "summary": "Get broker account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Broker account ID",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BrokerAccountResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deletes a broker account of a user that has no holdings attached. Only the user or an admin can delete it.",
+                "tags": [
+                    "Broker accounts"
+                ],
+                "summary": "Delete broker account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Broker account ID",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Replaces the label, account number, base currency and dates of a broker account of a user. Only the user or an admin can update it.",
+                "tags": [
+                    "Broker accounts"
+                ],
+                "summary": "Update broker account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Broker account ID",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Broker account",
+                        "name": "account",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateBrokerAccountReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{id}/broker-account/{accountId}/holding/{holdingId}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Attaches a holding of a portfolio of the user to an open broker account at the broker of the holding. Only the user or an admin can attach it.",
+                "tags": [
+                    "Broker accounts"
+                ],
+                "summary": "Attach holding to broker account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Broker account ID",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Holding ID",
+                        "name": "holdingId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{id}/broker-account/{accountId}/valuation": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Gets the open positions of the holdings attached to a broker account valued at the latest prices, with its cash per currency. Only the user or an admin can get it.",
+                "tags": [
+                    "Broker accounts"
+                ],
+                "summary": "Get broker account valuation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Broker account ID",
+                        "name": "accountId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BrokerAccountValuationResp"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{id}/calendar-token": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates a new secret token for the iCalendar feed of a user, revoking the previous one. The token is only returned once. Only the user or an admin can rotate it.",
+                "tags": [
+                    "Calendar"
+                ],
+                "summary": "Rotate calendar token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CalendarTokenResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revokes the secret token of the iCalendar feed of a user. Only the user or an admin can revoke it.",
+                "tags": [
+                    "Calendar"
+                ],
+                "summary": "Revoke calendar token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "entities.Money": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.PriceRefreshFailure": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "instrument_type": {
+                    "type": "string"
+                },
+                "instrumentid": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AlertResp": {
+            "type": "object",
+            "properties": {
+                "alert_ruleid": {
+                    "type": "string"
+                },
+                "alertid": {
+                    "type": "string"
+                },
+                "condition": {
+                    "type": "string"
+                },
+                "instrument_type": {
+                    "type": "string"
+                },
+                "instrumentid": {
+                    "type": "string"
+                },
+                "notified_at": {
+                    "type": "string"
+                },
+                "portfolioid": {
+                    "type": "string"
+                },
+                "price": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "threshold": {
+                    "type": "number"
+                },
+                "trigger_date": {
+                    "type": "string"
+                },
+                "triggered_at": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.AlertRuleResp": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "alert_ruleid": {
+                    "type": "string"
+                },
+                "condition": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "instrument_type": {
+                    "type": "string"
+                },
+                "instrumentid": {
+                    "type": "string"
+                },
+                "portfolioid": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ArchiveRestoreResp": {
+            "type": "object",
+            "properties": {
+                "dividends": {
+                    "type": "integer"
+                },
+                "duplicate_dividends": {
+                    "type": "integer"
+                },
+                "holdings": {
+                    "type": "integer"
+                },
+                "new_cryptocurrencies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "new_stocks": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "portfolioid": {
+                    "type": "string"
+                },
+                "skipped_transfers": {
+                    "type": "integer"
+                },
+                "transactions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BrokerAccountResp": {
+            "type": "object",
+            "properties": {
+                "account_label": {
+                    "type": "string"
+                },
+                "account_number": {
+                    "type": "string"
+                },
+                "base_currency": {
+                    "type": "string"
+                },
+                "broker_accountid": {
+                    "type": "string"
+                },
+                "brokerid": {
+                    "type": "string"
+                },
+                "closed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "userid": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BrokerAccountValuationResp": {
+            "type": "object",
+            "properties": {
+                "account_label": {
+                    "type": "string"
+                },
+                "base_currency": {
+                    "type": "string"
+                },
+                "broker_accountid": {
+                    "type": "string"
+                },
+                "brokerid": {
+                    "type": "string"
+                },
+                "cash": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CashBalanceResp"
+                    }
+                },
+                "cash_value": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "cost_basis": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "cost_basis_method": {
+                    "type": "integer"
+                },
+                "market_value": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "positions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PositionResp"
+                    }
+                },
+                "realized_gain": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "total_value": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "unrealized_gain": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "unrealized_gain_pct": {
+                    "type": "number"
+                },
+                "valued_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BrokerResp": {
+            "type": "object",
+            "properties": {
+                "brokerid": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "extid": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CalendarTokenResp": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CashBalanceResp": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "bought": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "deposits": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "dividends": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "fees": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "interest": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "sold": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "withdrawals": {
+                    "$ref": "#/definitions/entities.Money"
+                }
+            }
+        },
+        "models.CashBalancesResp": {
+            "type": "object",
+            "properties": {
+                "balances": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CashBalanceResp"
+                    }
+                },
+                "portfolioid": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CompanyEventImportResp": {
+            "type": "object",
+            "properties": {
+                "imported": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CompanyEventResp": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "company_eventid": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "event_date": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "split_denominator": {
+                    "type": "number"
+                },
+                "split_numerator": {
+                    "type": "number"
+                },
+                "stockid": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CompanyEventSyncResp": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "stocks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CorporateActionResp": {
+            "type": "object",
+            "properties": {
+                "action_type": {
+                    "type": "string"
+                },
+                "corporate_actionid": {
+                    "type": "string"
+                },
+                "cost_allocation": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "denominator": {
+                    "type": "number"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "effective_date": {
+                    "type": "string"
+                },
+                "new_isin": {
+                    "type": "string"
+                },
+                "new_symbol": {
+                    "type": "string"
+                },
+                "numerator": {
+                    "type": "number"
+                },
+                "old_symbol": {
+                    "type": "string"
+                },
+                "stockid": {
+                    "type": "string"
+                },
+                "target_stockid": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateAlertRuleReq": {
+            "type": "object",
+            "properties": {
+                "condition": {
+                    "type": "string"
+                },
+                "instrument_type": {
+                    "type": "string"
+                },
+                "instrumentid": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CreateBrokerAccountReq": {
+            "type": "object",
+            "properties": {
+                "account_label": {
+                    "type": "string"
+                },
+                "account_number": {
+                    "type": "string"
+                },
+                "base_currency": {
+                    "type": "string"
+                },
+                "brokerid": {
+                    "type": "string"
+                },
+                "closed_at": {
+                    "type": "string"
+                },
+                "opened_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateBrokerReq": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "extid": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateCompanyEventReq": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "event_date": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "split_denominator": {
+                    "type": "number"
+                },
+                "split_numerator": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CreateCorporateActionReq": {
+            "type": "object",
+            "properties": {
+                "action_type": {
+                    "type": "string"
+                },
+                "cost_allocation": {
+                    "type": "number"
+                },
+                "denominator": {
+                    "type": "number"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "effective_date": {
+                    "type": "string"
+                },
+                "new_isin": {
+                    "type": "string"
+                },
+                "new_symbol": {
+                    "type": "string"
+                },
+                "numerator": {
+                    "type": "number"
+                },
+                "target_stockid": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateFxRateReq": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string"
+                },
+                "quote_currency": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "rate_date": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreatePriceReq": {
+            "type": "object",
+            "properties": {
+                "close": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "high": {
+                    "type": "number"
+                },
+                "instrument_type": {
+                    "type": "string"
+                },
+                "instrumentid": {
+                    "type": "string"
+                },
+                "low": {
+                    "type": "number"
+                },
+                "open": {
+                    "type": "number"
+                },
+                "price_date": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "volume": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CreateTransactionReq": {
+            "type": "object",
+            "properties": {
+                "cryptocurrencyid": {
+                    "type": "string"
+                },
+                "holdingid": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "stockid": {
+                    "type": "string"
+                },
+                "transaction_date": {
+                    "type": "string"
+                },
+                "transaction_price": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "transaction_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateUserReq": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "firstname": {
+                    "type": "string"
+                },
+                "lastname": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreationResp": {
+            "type": "object",
+            "properties": {
+                "inserted_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DigestEventResp": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "event_date": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "stockid": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DigestMoverResp": {
+            "type": "object",
+            "properties": {
+                "change_pct": {
+                    "type": "number"
+                },
+                "end_price": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "instrument_type": {
+                    "type": "string"
+                },
+                "instrumentid": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "start_price": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "value_change": {
+                    "$ref": "#/definitions/entities.Money"
+                }
+            }
+        },
+        "models.DigestResp": {
+            "type": "object",
+            "properties": {
+                "dividends": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "end_value": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "net_contributions": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "portfolio_name": {
+                    "type": "string"
+                },
+                "portfolioid": {
+                    "type": "string"
+                },
+                "return_pct": {
+                    "type": "number"
+                },
+                "start_value": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "top_movers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DigestMoverResp"
+                    }
+                },
+                "upcoming_events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DigestEventResp"
+                    }
+                },
+                "value_change": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "value_change_pct": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.DigestSendResp": {
+            "type": "object",
+            "properties": {
+                "portfolios": {
+                    "type": "integer"
+                },
+                "sent": {
+                    "type": "integer"
+                },
+                "undelivered": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DividendCalendarResp": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "Currency is the currency of the totals, empty when they are summed in the currencies of the dividends",
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "months": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DividendMonthResp"
+                    }
+                },
+                "portfolioid": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/entities.Money"
+                }
+            }
+        },
+        "models.DividendCountryResp": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string"
+                },
+                "foreign_tax_credit": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "gross": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "net": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "payments": {
+                    "type": "integer"
+                },
+                "reclaimable": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "withholding": {
+                    "$ref": "#/definitions/entities.Money"
+                }
+            }
+        },
+        "models.DividendForecastHoldingResp": {
+            "type": "object",
+            "properties": {
+                "dividend_per_share": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "payments": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "stockid": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/entities.Money"
+                }
+            }
+        },
+        "models.DividendForecastResp": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "Currency is the currency of the totals, empty when they are summed in the currencies of the dividends",
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "holdings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DividendForecastHoldingResp"
+                    }
+                },
+                "months": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DividendMonthResp"
+                    }
+                },
+                "portfolioid": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/entities.Money"
+                }
+            }
+        },
+        "models.DividendIncomeResp": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "base_amount": {
+                    "description": "BaseAmount is the amount in the currency of the portfolio and is zero when it has none",
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "dividend_per_share": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "dividendid": {
+                    "type": "string"
+                },
+                "ex_date": {
+                    "type": "string"
+                },
+                "pay_date": {
+                    "type": "string"
+                },
+                "shares": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "stockid": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DividendMonthResp": {
+            "type": "object",
+            "properties": {
+                "month": {
+                    "type": "string"
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DividendIncomeResp"
+                    }
+                },
+                "total": {
+                    "$ref": "#/definitions/entities.Money"
+                }
+            }
+        },
+        "models.DividendPaymentResp": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string"
+                },
+                "dividend_per_share": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "dividendid": {
+                    "type": "string"
+                },
+                "ex_date": {
+                    "type": "string"
+                },
+                "foreign_tax_credit": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "gross": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "net": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "pay_date": {
+                    "type": "string"
+                },
+                "reclaimable": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "shares": {
+                    "type": "number"
+                },
+                "stockid": {
+                    "type": "string"
+                },
+                "treaty_rate": {
+                    "type": "number"
+                },
+                "withholding": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "withholding_rate": {
+                    "type": "number"
+                },
+                "withholding_recorded": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.DividendTaxReportResp": {
+            "type": "object",
+            "properties": {
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DividendCountryResp"
+                    }
+                },
+                "country": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "foreign_tax_credit": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "gross": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "net": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DividendPaymentResp"
+                    }
+                },
+                "portfolioid": {
+                    "type": "string"
+                },
+                "reclaimable": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "withholding": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DividendYieldHoldingResp": {
+            "type": "object",
+            "properties": {
+                "annual_dividend_per_share": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "annual_income": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "cost_basis": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "current_yield_pct": {
+                    "type": "number"
+                },
+                "market_value": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "priced": {
+                    "type": "boolean"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "stockid": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "yield_on_cost_pct": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.DividendYieldResp": {
+            "type": "object",
+            "properties": {
+                "annual_income": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "cost_basis": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "currency": {
+                    "description": "Currency is the currency of the totals, empty when they are summed in the currencies of the holdings",
+                    "type": "string"
+                },
+                "current_yield_pct": {
+                    "type": "number"
+                },
+                "holdings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DividendYieldHoldingResp"
+                    }
+                },
+                "market_value": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "portfolioid": {
+                    "type": "string"
+                },
+                "yield_on_cost_pct": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.ECBImportResp": {
+            "type": "object",
+            "properties": {
+                "cross_rates": {
+                    "type": "integer"
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "rates": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FxRateResp": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "fx_rateid": {
+                    "type": "string"
+                },
+                "quote_currency": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "rate_date": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ImportIssueResp": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ImportPositionResp": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "difference": {
+                    "type": "number"
+                },
+                "isin": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "portfolio_quantity": {
+                    "type": "number"
+                },
+                "statement_quantity": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ImportResp": {
+            "type": "object",
+            "properties": {
+                "batchid": {
+                    "type": "string"
+                },
+                "broker": {
+                    "type": "string"
+                },
+                "dividend_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "duplicate_of": {
+                    "type": "string"
+                },
+                "duplicates": {
+                    "type": "integer"
+                },
+                "invalid": {
+                    "type": "integer"
+                },
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportIssueResp"
+                    }
+                },
+                "mismatches": {
+                    "type": "integer"
+                },
+                "new_stocks": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "parsed": {
+                    "type": "integer"
+                },
+                "portfolioid": {
+                    "type": "string"
+                },
+                "positions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportPositionResp"
+                    }
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportRowResp"
+                    }
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "transaction_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "withholding_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ImportRollbackResp": {
+            "type": "object",
+            "properties": {
+                "batchid": {
+                    "type": "string"
+                },
+                "dividends": {
+                    "type": "integer"
+                },
+                "portfolioid": {
+                    "type": "string"
+                },
+                "transactions": {
+                    "type": "integer"
+                },
+                "withholdings": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportRowResp": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "amount": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "fee": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "isin": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LastPriceResp": {
+            "type": "object",
+            "properties": {
+                "instrument_type": {
+                    "type": "string"
+                },
+                "instrumentid": {
+                    "type": "string"
+                },
+                "price": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "priced_at": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LedgerResp": {
+            "type": "object",
+            "properties": {
+                "cost_basis_method": {
+                    "type": "integer"
+                },
+                "lots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LotResp"
+                    }
+                },
+                "portfolioid": {
+                    "type": "string"
+                },
+                "realized_gains": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RealizedGainResp"
+                    }
+                }
+            }
+        },
+        "models.LoginUserReq": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LoginUserResp": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.UserResp"
+                }
+            }
+        },
+        "models.LotResp": {
+            "type": "object",
+            "properties": {
+                "acquired_at": {
+                    "type": "string"
+                },
+                "cost_per_unit": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "instrument_type": {
+                    "type": "string"
+                },
+                "instrumentid": {
+                    "type": "string"
+                },
+                "lotid": {
+                    "type": "string"
+                },
+                "portfolioid": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "remaining_quantity": {
+                    "type": "number"
+                },
+                "transactionid": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MultiCreationResp": {
+            "type": "object",
+            "properties": {
+                "inserted_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.PerformanceResp": {
+            "type": "object",
+            "properties": {
+                "dividends": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "end_value": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "holdingid": {
+                    "type": "string"
+                },
+                "money_weighted_return_annualized_pct": {
+                    "type": "number"
+                },
+                "money_weighted_return_pct": {
+                    "type": "number"
+                },
+                "net_contributions": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "portfolioid": {
+                    "type": "string"
+                },
+                "start_value": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "time_weighted_return_annualized_pct": {
+                    "type": "number"
+                },
+                "time_weighted_return_pct": {
+                    "type": "number"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PositionResp": {
+            "type": "object",
+            "properties": {
+                "average_cost": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "base_cost_basis": {
+                    "description": "Base amounts are in the base currency of the valuation and are zero when it has none",
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "base_market_value": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "base_unrealized_gain": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "cost_basis": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "instrument_type": {
+                    "type": "string"
+                },
+                "instrumentid": {
+                    "type": "string"
+                },
+                "last_trade_date": {
+                    "type": "string"
+                },
+                "market_price": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "market_value": {
+                    "$ref": "#/definitions/entities.Money"
                 },
-                "name": {
+                "priced": {
+                    "type": "boolean"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "unrealized_gain": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "unrealized_gain_pct": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.PriceBarResp": {
+            "type": "object",
+            "properties": {
+                "close": {
+                    "type": "number"
+                },
+                "date": {
                     "type": "string"
                 },
-                "slug": {
+                "high": {
+                    "type": "number"
+                },
+                "low": {
+                    "type": "number"
+                },
+                "open": {
+                    "type": "number"
+                },
+                "volume": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.PriceGapResp": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "integer"
+                },
+                "from": {
                     "type": "string"
                 },
-                "updated_at": {
+                "to": {
                     "type": "string"
                 }
             }
         },
-        "models.CreateBrokerReq": {
+        "models.PriceHistoryResp": {
             "type": "object",
             "properties": {
-                "description": {
+                "currency": {
                     "type": "string"
                 },
-                "extid": {
+                "from": {
                     "type": "string"
                 },
-                "name": {
+                "gaps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceGapResp"
+                    }
+                },
+                "instrumentid": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "last_price": {
+                    "$ref": "#/definitions/models.LastPriceResp"
+                },
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceBarResp"
+                    }
+                },
+                "to": {
                     "type": "string"
                 }
             }
         },
-        "models.CreateUserReq": {
+        "models.PriceImportResp": {
             "type": "object",
             "properties": {
-                "email": {
+                "from": {
                     "type": "string"
                 },
-                "firstname": {
-                    "type": "string"
+                "imported": {
+                    "type": "integer"
                 },
-                "lastname": {
+                "instrumentid": {
                     "type": "string"
                 },
-                "password": {
+                "skipped": {
+                    "type": "integer"
+                },
+                "to": {
                     "type": "string"
                 }
             }
         },
-        "models.CreationResp": {
+        "models.PriceRefreshRunResp": {
             "type": "object",
             "properties": {
-                "inserted_id": {
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "failures": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.PriceRefreshFailure"
+                    }
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "instruments": {
+                    "type": "integer"
+                },
+                "price_refresh_runid": {
                     "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "updated": {
+                    "type": "integer"
                 }
             }
         },
-        "models.LoginUserReq": {
+        "models.RealizedGainResp": {
             "type": "object",
             "properties": {
-                "email": {
+                "acquired_at": {
                     "type": "string"
                 },
-                "password": {
+                "buy_transactionid": {
+                    "type": "string"
+                },
+                "cost_basis": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "gain": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "instrument_type": {
+                    "type": "string"
+                },
+                "instrumentid": {
+                    "type": "string"
+                },
+                "lotid": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "integer"
+                },
+                "portfolioid": {
+                    "type": "string"
+                },
+                "proceeds": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "realized_gainid": {
+                    "type": "string"
+                },
+                "sell_transactionid": {
+                    "type": "string"
+                },
+                "sold_at": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.LoginUserResp": {
+        "models.SetLastPriceReq": {
             "type": "object",
             "properties": {
-                "token": {
+                "instrument_type": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/models.UserResp"
+                "instrumentid": {
+                    "type": "string"
+                },
+                "price": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "priced_at": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
                 }
             }
         },
-        "models.MultiCreationResp": {
+        "models.TaxReportResp": {
             "type": "object",
             "properties": {
-                "inserted_ids": {
+                "allowance": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "cost_basis": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "cost_basis_method": {
+                    "type": "integer"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "exempt_gain": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "gain": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "portfolioid": {
+                    "type": "string"
+                },
+                "proceeds": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "sales": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/models.TaxSaleResp"
+                    }
+                },
+                "taxable_gain": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "treatments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaxTreatmentResp"
                     }
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TaxSaleResp": {
+            "type": "object",
+            "properties": {
+                "acquired_at": {
+                    "type": "string"
+                },
+                "buy_transactionid": {
+                    "type": "string"
+                },
+                "cost_basis": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "gain": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "holding_days": {
+                    "type": "integer"
+                },
+                "instrument_type": {
+                    "type": "string"
+                },
+                "instrumentid": {
+                    "type": "string"
+                },
+                "proceeds": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "rule": {
+                    "type": "string"
+                },
+                "sell_transactionid": {
+                    "type": "string"
+                },
+                "sold_at": {
+                    "type": "string"
+                },
+                "taxable_gain": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "treatment": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TaxTreatmentResp": {
+            "type": "object",
+            "properties": {
+                "gain": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "proceeds": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "sales": {
+                    "type": "integer"
+                },
+                "taxable_gain": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "treatment": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TransactionResp": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "cryptocurrencyid": {
+                    "type": "string"
+                },
+                "extid": {
+                    "type": "string"
+                },
+                "holdingid": {
+                    "type": "string"
+                },
+                "import_batchid": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "stockid": {
+                    "type": "string"
+                },
+                "transaction_date": {
+                    "type": "string"
+                },
+                "transaction_price": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "transaction_type": {
+                    "type": "string"
+                },
+                "transactionid": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UpdateAlertRuleReq": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "condition": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.UpdateBrokerAccountReq": {
+            "type": "object",
+            "properties": {
+                "account_label": {
+                    "type": "string"
+                },
+                "account_number": {
+                    "type": "string"
+                },
+                "base_currency": {
+                    "type": "string"
+                },
+                "closed_at": {
+                    "type": "string"
+                },
+                "opened_at": {
+                    "type": "string"
                 }
             }
         },
@@ -929,9 +6325,46 @@ var doc = `{
                 }
             }
         },
+        "models.UpdateCostBasisMethodReq": {
+            "type": "object",
+            "properties": {
+                "method": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.UpdateTransactionReq": {
+            "type": "object",
+            "properties": {
+                "cryptocurrencyid": {
+                    "type": "string"
+                },
+                "holdingid": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "stockid": {
+                    "type": "string"
+                },
+                "transaction_date": {
+                    "type": "string"
+                },
+                "transaction_price": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "transaction_type": {
+                    "type": "string"
+                }
+            }
+        },
         "models.UpdateUserReq": {
             "type": "object",
             "properties": {
+                "base_currency": {
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
@@ -952,6 +6385,9 @@ var doc = `{
         "models.UserResp": {
             "type": "object",
             "properties": {
+                "base_currency": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
@@ -971,6 +6407,45 @@ var doc = `{
                     "type": "string"
                 }
             }
+        },
+        "models.ValuationResp": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "description": "BaseCurrency is the currency of the totals, empty when they are summed in the currencies of the positions",
+                    "type": "string"
+                },
+                "cost_basis": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "cost_basis_method": {
+                    "type": "integer"
+                },
+                "market_value": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "portfolioid": {
+                    "type": "string"
+                },
+                "positions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PositionResp"
+                    }
+                },
+                "realized_gain": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "unrealized_gain": {
+                    "$ref": "#/definitions/entities.Money"
+                },
+                "unrealized_gain_pct": {
+                    "type": "number"
+                },
+                "valued_at": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// SetAlertRoutes creates alert routes
+func SetAlertRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.AlertService) {
+	r.Handle("/v1/portfolio/{id}/alert-rule", middlewares.JWT(createAlertRule(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodPost)
+	r.Handle("/v1/portfolio/{id}/alert-rule", middlewares.JWT(getAlertRules(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/portfolio/{id}/alert-rule/{ruleId}", middlewares.JWT(getAlertRule(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/portfolio/{id}/alert-rule/{ruleId}", middlewares.JWT(updateAlertRule(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodPatch)
+	r.Handle("/v1/portfolio/{id}/alert-rule/{ruleId}", middlewares.JWT(deleteAlertRule(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodDelete)
+	r.Handle("/v1/portfolio/{id}/alerts", middlewares.JWT(getAlerts(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+}
+
+// @Summary Create alert rule
+// @Description Creates an active price alert rule for an instrument of a portfolio. The portfolio must belong to the user of the token.
+// @Tags Alerts
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Param rule body models.CreateAlertRuleReq true "New alert rule"
+// @Success 201 {object} models.CreationResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/alert-rule [post]
+func createAlertRule(ctx context.Context, cfg config.Config, s ports.AlertService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		var rule models.CreateAlertRuleReq
+		err = json.Unmarshal(body, &rule)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		result, err := s.CreateRule(ctx, requestUserID(r), params["id"], rule)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusCreated, result)
+	})
+}
+
+// @Summary Get alert rules
+// @Description Gets the price alert rules of a portfolio. The portfolio must belong to the user of the token.
+// @Tags Alerts
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Success 200 {array} models.AlertRuleResp "OK"
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/alert-rule [get]
+func getAlertRules(ctx context.Context, cfg config.Config, s ports.AlertService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		rules, err := s.GetRules(ctx, requestUserID(r), params["id"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, rules)
+	})
+}
+
+// @Summary Get alert rule
+// @Description Gets a price alert rule of a portfolio. The portfolio must belong to the user of the token.
+// @Tags Alerts
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Param ruleId path string true "Alert rule ID"
+// @Success 200 {object} models.AlertRuleResp "OK"
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/alert-rule/{ruleId} [get]
+func getAlertRule(ctx context.Context, cfg config.Config, s ports.AlertService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		rule, err := s.GetRule(ctx, requestUserID(r), params["id"], params["ruleId"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, rule)
+	})
+}
+
+// @Summary Update alert rule
+// @Description Updates the condition, threshold or state of a price alert rule of a portfolio. The portfolio must belong to the user of the token.
+// @Tags Alerts
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Param ruleId path string true "Alert rule ID"
+// @Param rule body models.UpdateAlertRuleReq true "Alert rule"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/alert-rule/{ruleId} [patch]
+func updateAlertRule(ctx context.Context, cfg config.Config, s ports.AlertService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		var rule models.UpdateAlertRuleReq
+		err = json.Unmarshal(body, &rule)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		err = s.UpdateRule(ctx, requestUserID(r), params["id"], params["ruleId"], rule)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusOK, nil)
+	})
+}
+
+// @Summary Delete alert rule
+// @Description Deletes a price alert rule of a portfolio together with its alerts. The portfolio must belong to the user of the token.
+// @Tags Alerts
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Param ruleId path string true "Alert rule ID"
+// @Success 200 "OK"
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/alert-rule/{ruleId} [delete]
+func deleteAlertRule(ctx context.Context, cfg config.Config, s ports.AlertService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.DeleteRule(ctx, requestUserID(r), params["id"], params["ruleId"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
+
+// @Summary Get alerts
+// @Description Gets the price alerts triggered in a portfolio, newest first. The portfolio must belong to the user of the token.
+// @Tags Alerts
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Success 200 {array} models.AlertResp "OK"
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/alerts [get]
+func getAlerts(ctx context.Context, cfg config.Config, s ports.AlertService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		alerts, err := s.GetAlerts(ctx, requestUserID(r), params["id"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, alerts)
+	})
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// SetBrokerAccountRoutes creates broker account routes
+func SetBrokerAccountRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.BrokerAccountService) {
+	r.Handle("/v1/user/{id}/broker-account", middlewares.JWT(createBrokerAccount(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodPost)
+	r.Handle("/v1/user/{id}/broker-account", middlewares.JWT(getBrokerAccounts(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/user/{id}/broker-account/{accountId}", middlewares.JWT(getBrokerAccount(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/user/{id}/broker-account/{accountId}", middlewares.JWT(updateBrokerAccount(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodPatch)
+	r.Handle("/v1/user/{id}/broker-account/{accountId}", middlewares.JWT(deleteBrokerAccount(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodDelete)
+	r.Handle("/v1/user/{id}/broker-account/{accountId}/holding/{holdingId}", middlewares.JWT(attachBrokerAccountHolding(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodPut)
+}
+
+// @Summary Create broker account
+// @Description Creates an account of a user at a broker of the catalog, storing only the masked account number. Only the user or an admin can create it.
+// @Tags Broker accounts
+// @Security Bearer
+// @Param id path string true "User ID"
+// @Param account body models.CreateBrokerAccountReq true "New broker account"
+// @Success 201 {object} models.CreationResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/user/{id}/broker-account [post]
+func createBrokerAccount(ctx context.Context, cfg config.Config, s ports.BrokerAccountService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		if err := authorizeUser(r, params["id"]); err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+		var account models.CreateBrokerAccountReq
+		err = json.Unmarshal(body, &account)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		result, err := s.Create(ctx, params["id"], account)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusCreated, result)
+	})
+}
+
+// @Summary Get broker accounts
+// @Description Gets the broker accounts of a user. Only the user or an admin can get them.
+// @Tags Broker accounts
+// @Security Bearer
+// @Param id path string true "User ID"
+// @Success 200 {array} models.BrokerAccountResp "OK"
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/user/{id}/broker-account [get]
+func getBrokerAccounts(ctx context.Context, cfg config.Config, s ports.BrokerAccountService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		if err := authorizeUser(r, params["id"]); err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		accounts, err := s.GetAll(ctx, params["id"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, accounts)
+	})
+}
+
+// @Summary Get broker account
+// @Description Gets a broker account of a user. Only the user or an admin can get it.
+// @Tags Broker accounts
+// @Security Bearer
+// @Param id path string true "User ID"
+// @Param accountId path string true "Broker account ID"
+// @Success 200 {object} models.BrokerAccountResp "OK"
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/user/{id}/broker-account/{accountId} [get]
+func getBrokerAccount(ctx context.Context, cfg config.Config, s ports.BrokerAccountService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		if err := authorizeUser(r, params["id"]); err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		account, err := s.GetByID(ctx, params["id"], params["accountId"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, account)
+	})
+}
+
+// @Summary Update broker account
+// @Description Replaces the label, account number, base currency and dates of a broker account of a user. Only the user or an admin can update it.
+// @Tags Broker accounts
+// @Security Bearer
+// @Param id path string true "User ID"
+// @Param accountId path string true "Broker account ID"
+// @Param account body models.UpdateBrokerAccountReq true "Broker account"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/user/{id}/broker-account/{accountId} [patch]
+func updateBrokerAccount(ctx context.Context, cfg config.Config, s ports.BrokerAccountService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		if err := authorizeUser(r, params["id"]); err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+		var account models.UpdateBrokerAccountReq
+		err = json.Unmarshal(body, &account)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		err = s.Update(ctx, params["id"], params["accountId"], account)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusOK, nil)
+	})
+}
+
+// @Summary Delete broker account
+// @Description Deletes a broker account of a user that has no holdings attached. Only the user or an admin can delete it.
+// @Tags Broker accounts
+// @Security Bearer
+// @Param id path string true "User ID"
+// @Param accountId path string true "Broker account ID"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/user/{id}/broker-account/{accountId} [delete]
+func deleteBrokerAccount(ctx context.Context, cfg config.Config, s ports.BrokerAccountService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		if err := authorizeUser(r, params["id"]); err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		err := s.Delete(ctx, params["id"], params["accountId"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
+
+// @Summary Attach holding to broker account
+// @Description Attaches a holding of a portfolio of the user to an open broker account at the broker of the holding. Only the user or an admin can attach it.
+// @Tags Broker accounts
+// @Security Bearer
+// @Param id path string true "User ID"
+// @Param accountId path string true "Broker account ID"
+// @Param holdingId path string true "Holding ID"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/user/{id}/broker-account/{accountId}/holding/{holdingId} [put]
+func attachBrokerAccountHolding(ctx context.Context, cfg config.Config, s ports.BrokerAccountService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		if err := authorizeUser(r, params["id"]); err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		err := s.AttachHolding(ctx, params["id"], params["accountId"], params["holdingId"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
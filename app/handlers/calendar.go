@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// SetCalendarRoutes creates calendar feed routes
+func SetCalendarRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.CalendarService) {
+	r.Handle("/v1/user/{id}/calendar-token", middlewares.JWT(rotateCalendarToken(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodPost)
+	r.Handle("/v1/user/{id}/calendar-token", middlewares.JWT(revokeCalendarToken(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodDelete)
+	r.Handle("/v1/calendar/{token}.ics", getCalendarFeed(ctx, cfg, s)).Methods(http.MethodGet)
+}
+
+// @Summary Rotate calendar token
+// @Description Creates a new secret token for the iCalendar feed of a user, revoking the previous one. The token is only returned once. Only the user or an admin can rotate it.
+// @Tags Calendar
+// @Security Bearer
+// @Param id path string true "User ID"
+// @Success 200 {object} models.CalendarTokenResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/user/{id}/calendar-token [post]
+func rotateCalendarToken(ctx context.Context, cfg config.Config, s ports.CalendarService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		if err := authorizeUser(r, params["id"]); err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		token, err := s.RotateToken(ctx, params["id"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, token)
+	})
+}
+
+// @Summary Revoke calendar token
+// @Description Revokes the secret token of the iCalendar feed of a user. Only the user or an admin can revoke it.
+// @Tags Calendar
+// @Security Bearer
+// @Param id path string true "User ID"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/user/{id}/calendar-token [delete]
+func revokeCalendarToken(ctx context.Context, cfg config.Config, s ports.CalendarService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		if err := authorizeUser(r, params["id"]); err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		err := s.RevokeToken(ctx, params["id"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
+
+// @Summary Get calendar feed
+// @Description Gets the iCalendar feed of the dividends and company events of the stocks held by the user owning the token
+// @Tags Calendar
+// @Produce text/calendar
+// @Param token path string true "Calendar token"
+// @Success 200 {string} string "OK"
+// @Failure 400 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/calendar/{token}.ics [get]
+func getCalendarFeed(ctx context.Context, cfg config.Config, s ports.CalendarService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		feed, err := s.Feed(ctx, params["token"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", `inline; filename="portflux.ics"`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(feed)
+	})
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/context"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// requestClaims returns the claims of the JWT a request was authorized with by the JWT middleware
+func requestClaims(r *http.Request) jwt.MapClaims {
+	claims, _ := context.Get(r, "decoded").(jwt.MapClaims)
+	return claims
+}
+
+// requestUserID returns the ID of the user the JWT of a request was issued to, or an empty string when it was not issued to a user
+func requestUserID(r *http.Request) string {
+	userID, _ := requestClaims(r)["user_id"].(string)
+	return userID
+}
+
+// authorizeUser returns an unauthorized error unless the JWT of a request was issued to the user or carries the admin claim
+func authorizeUser(r *http.Request, userID string) error {
+	if admin, _ := requestClaims(r)["admin"].(bool); admin {
+		return nil
+	}
+	if requestUserID(r) != userID {
+		return wrappers.NewUnauthorizedErr(fmt.Errorf("token not issued to user %s", userID))
+	}
+	return nil
+}
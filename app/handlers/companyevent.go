@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// SetCompanyEventRoutes creates company event routes
+func SetCompanyEventRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.CompanyEventService) {
+	r.Handle("/v1/stock/{id}/events", middlewares.JWT(createCompanyEvent(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{"admin": true})).Methods(http.MethodPost)
+	r.Handle("/v1/stock/{id}/events", middlewares.JWT(getCompanyEvents(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/stock/{id}/events/{eventId}", middlewares.JWT(deleteCompanyEvent(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{"admin": true})).Methods(http.MethodDelete)
+	r.Handle("/v1/admin/company-event/import", middlewares.JWT(importCompanyEvents(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{"admin": true})).Methods(http.MethodPost)
+	r.Handle("/v1/admin/company-event/sync", middlewares.JWT(syncCompanyEvents(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{"admin": true})).Methods(http.MethodPost)
+}
+
+// @Summary Create company event
+// @Description Creates an event of a stock, replacing the event of the same type already stored for the same day
+// @Tags Company events
+// @Security Bearer
+// @Param id path string true "Stock ID"
+// @Param event body models.CreateCompanyEventReq true "New company event"
+// @Success 201 {object} models.CreationResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/stock/{id}/events [post]
+func createCompanyEvent(ctx context.Context, cfg config.Config, s ports.CompanyEventService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		var event models.CreateCompanyEventReq
+		err = json.Unmarshal(body, &event)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		result, err := s.Create(ctx, params["id"], event)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusCreated, result)
+	})
+}
+
+// @Summary Get company events
+// @Description Gets the earnings, dividend, split and delisting events of a stock over a date range, oldest first
+// @Tags Company events
+// @Security Bearer
+// @Param id path string true "Stock ID"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date (YYYY-MM-DD)"
+// @Success 200 {array} models.CompanyEventResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/stock/{id}/events [get]
+func getCompanyEvents(ctx context.Context, cfg config.Config, s ports.CompanyEventService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var req models.CompanyEventsReq
+		var err error
+		if req.From, err = dateQueryParam(r, "from"); err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		if req.To, err = dateQueryParam(r, "to"); err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		events, err := s.GetByStockID(ctx, params["id"], req)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, events)
+	})
+}
+
+// @Summary Delete company event
+// @Description Deletes an event of a stock
+// @Tags Company events
+// @Security Bearer
+// @Param id path string true "Stock ID"
+// @Param eventId path string true "Company event ID"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/stock/{id}/events/{eventId} [delete]
+func deleteCompanyEvent(ctx context.Context, cfg config.Config, s ports.CompanyEventService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.Delete(ctx, params["id"], params["eventId"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
+
+// @Summary Import company events
+// @Description Imports company events from a CSV file with Symbol, Type and Date columns and the optional Amount, Currency, Numerator, Denominator, Description and Source ones. Importing a file again replaces the events already stored for the same stock, type and day.
+// @Tags Company events
+// @Security Bearer
+// @Accept multipart/form-data
+// @Param file formData file true "CSV file of company events"
+// @Success 200 {object} models.CompanyEventImportResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/admin/company-event/import [post]
+func importCompanyEvents(ctx context.Context, cfg config.Config, s ports.CompanyEventService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			utils.ResponseError(w, r, nil, wrappers.NewValidationErr(fmt.Errorf("file cannot be read: %w", err)))
+			return
+		}
+		defer file.Close()
+
+		result, err := s.Import(ctx, file)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, result)
+	})
+}
+
+// @Summary Sync company events
+// @Description Fetches now from the market data provider the upcoming dividends and splits of the stocks held in open positions
+// @Tags Company events
+// @Security Bearer
+// @Success 200 {object} models.CompanyEventSyncResp "OK"
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/admin/company-event/sync [post]
+func syncCompanyEvents(ctx context.Context, cfg config.Config, s ports.CompanyEventService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		result, err := s.Sync(ctx)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, result)
+	})
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// SetCorporateActionRoutes creates corporate action routes
+func SetCorporateActionRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.CorporateActionService) {
+	r.Handle("/v1/stock/{id}/corporate-actions", middlewares.JWT(createCorporateAction(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{"admin": true})).Methods(http.MethodPost)
+	r.Handle("/v1/stock/{id}/corporate-actions", middlewares.JWT(getCorporateActions(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/stock/{id}/corporate-actions/{actionId}", middlewares.JWT(deleteCorporateAction(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{"admin": true})).Methods(http.MethodDelete)
+}
+
+// @Summary Create corporate action
+// @Description Records a split, reverse split, symbol change, merger or spin-off of a stock. Positions, lots and returns take it into account from then on, without changing the stored transactions.
+// @Tags Corporate actions
+// @Security Bearer
+// @Param id path string true "Stock ID"
+// @Param action body models.CreateCorporateActionReq true "New corporate action"
+// @Success 201 {object} models.CreationResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/stock/{id}/corporate-actions [post]
+func createCorporateAction(ctx context.Context, cfg config.Config, s ports.CorporateActionService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		var action models.CreateCorporateActionReq
+		err = json.Unmarshal(body, &action)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		result, err := s.Create(ctx, params["id"], action)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusCreated, result)
+	})
+}
+
+// @Summary Get corporate actions
+// @Description Gets the corporate actions of a stock, including the mergers and spin-offs into it, oldest first
+// @Tags Corporate actions
+// @Security Bearer
+// @Param id path string true "Stock ID"
+// @Success 200 {array} models.CorporateActionResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/stock/{id}/corporate-actions [get]
+func getCorporateActions(ctx context.Context, cfg config.Config, s ports.CorporateActionService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		actions, err := s.GetByStockID(ctx, params["id"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, actions)
+	})
+}
+
+// @Summary Delete corporate action
+// @Description Deletes a corporate action of a stock
+// @Tags Corporate actions
+// @Security Bearer
+// @Param id path string true "Stock ID"
+// @Param actionId path string true "Corporate action ID"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/stock/{id}/corporate-actions/{actionId} [delete]
+func deleteCorporateAction(ctx context.Context, cfg config.Config, s ports.CorporateActionService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.Delete(ctx, params["id"], params["actionId"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// SetDigestRoutes creates digest routes
+func SetDigestRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.DigestService) {
+	r.Handle("/v1/portfolio/{id}/digest", middlewares.JWT(getDigest(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/admin/digest/send", middlewares.JWT(sendDigests(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{"admin": true})).Methods(http.MethodPost)
+}
+
+// @Summary Get portfolio digest
+// @Description Gets the summary digest of a portfolio for the last complete week or month: value change, top movers, dividends received and upcoming events. The portfolio must belong to the user of the token.
+// @Tags Digests
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Param period query string false "Period of the digest: weekly or monthly, defaults to weekly"
+// @Success 200 {object} models.DigestResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/digest [get]
+func getDigest(ctx context.Context, cfg config.Config, s ports.DigestService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		req := models.DigestReq{
+			Period: r.URL.Query().Get("period"),
+		}
+
+		var params = mux.Vars(r)
+		digest, err := s.Generate(ctx, requestUserID(r), params["id"], req)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, digest)
+	})
+}
+
+// @Summary Send digests
+// @Description Sends now the digests of the last complete periods not sent yet to the owners of the portfolios with the summary enabled
+// @Tags Digests
+// @Security Bearer
+// @Success 200 {object} models.DigestSendResp "OK"
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/admin/digest/send [post]
+func sendDigests(ctx context.Context, cfg config.Config, s ports.DigestService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		result, err := s.Send(ctx)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, result)
+	})
+}
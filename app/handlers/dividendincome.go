@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// SetDividendIncomeRoutes creates dividend income routes
+func SetDividendIncomeRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.DividendIncomeService) {
+	r.Handle("/v1/portfolio/{id}/dividends/calendar", middlewares.JWT(getDividendCalendar(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/portfolio/{id}/dividends/forecast", middlewares.JWT(getDividendForecast(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/portfolio/{id}/dividends/yield", middlewares.JWT(getDividendYield(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+}
+
+// @Summary Get dividend calendar
+// @Description Gets the dividends paid to a portfolio and the ones declared or expected to be paid within a date range, by month. The portfolio must belong to the user of the token.
+// @Tags Portfolios
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Param from query string false "Start date (YYYY-MM-DD), defaults to a year ago"
+// @Param to query string false "End date (YYYY-MM-DD), defaults to a year from today"
+// @Success 200 {object} models.DividendCalendarResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/dividends/calendar [get]
+func getDividendCalendar(ctx context.Context, cfg config.Config, s ports.DividendIncomeService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var req models.DividendCalendarReq
+		var err error
+		if req.From, err = dateQueryParam(r, "from"); err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		if req.To, err = dateQueryParam(r, "to"); err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		calendar, err := s.GetCalendar(ctx, requestUserID(r), params["id"], req)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, calendar)
+	})
+}
+
+// @Summary Get dividend forecast
+// @Description Gets the dividends a portfolio is expected to receive over the next twelve months from the payment pattern of the held stocks. The portfolio must belong to the user of the token.
+// @Tags Portfolios
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Success 200 {object} models.DividendForecastResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/dividends/forecast [get]
+func getDividendForecast(ctx context.Context, cfg config.Config, s ports.DividendIncomeService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		forecast, err := s.GetForecast(ctx, requestUserID(r), params["id"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, forecast)
+	})
+}
+
+// @Summary Get dividend yield
+// @Description Gets the yield on cost and the current yield of the stocks held by a portfolio from the dividends of the last twelve months. The portfolio must belong to the user of the token.
+// @Tags Portfolios
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Success 200 {object} models.DividendYieldResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/dividends/yield [get]
+func getDividendYield(ctx context.Context, cfg config.Config, s ports.DividendIncomeService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		yield, err := s.GetYield(ctx, requestUserID(r), params["id"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, yield)
+	})
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// maxUploadSize is the maximum size of the files uploaded to the import endpoints
+const maxUploadSize = 32 << 20
+
+// SetECBImportRoutes creates ECB reference rates import routes
+func SetECBImportRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.ECBImportService) {
+	r.Handle("/v1/admin/fx-rate/ecb", middlewares.JWT(importECBRates(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{"admin": true})).Methods(http.MethodPost)
+}
+
+// @Summary Import ECB reference rates
+// @Description Imports an ECB eurofxref-hist XML or CSV file into the FX rates, crossing the rates of the registered currencies through the euro. Importing a file again replaces the rates already stored for the same pair and date.
+// @Tags FX rates
+// @Security Bearer
+// @Accept multipart/form-data
+// @Param file formData file true "ECB eurofxref-hist XML or CSV file"
+// @Success 200 {object} models.ECBImportResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/admin/fx-rate/ecb [post]
+func importECBRates(ctx context.Context, cfg config.Config, s ports.ECBImportService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			utils.ResponseError(w, r, nil, wrappers.NewValidationErr(fmt.Errorf("file cannot be read: %w", err)))
+			return
+		}
+		defer file.Close()
+
+		result, err := s.Import(ctx, file)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, result)
+	})
+}
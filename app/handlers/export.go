@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// maxArchiveSize is the maximum size of the portfolio archives uploaded to be restored
+const maxArchiveSize = 512 << 20
+
+// exportContentTypes are the content types of the formats of a portfolio export
+var exportContentTypes = map[string]string{
+	models.ExportFormatCSV:  "text/csv; charset=utf-8",
+	models.ExportFormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	models.ExportFormatJSON: "application/json",
+	models.ExportFormatPP:   "application/xml",
+}
+
+// SetExportRoutes creates portfolio export routes
+func SetExportRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.ExportService) {
+	r.Handle("/v1/portfolio/{id}/export", middlewares.JWT(exportPortfolio(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/user/{id}/archive", middlewares.JWT(restoreArchive(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodPost)
+}
+
+// @Summary Export portfolio
+// @Description Exports the holdings, transactions, dividends and realized gains of a portfolio as a CSV file of a single type, an XLSX workbook with a sheet per type, a JSON archive of the whole portfolio that can be restored into another account, or a Portfolio Performance XML file. The portfolio must belong to the user of the token.
+// @Tags Exports
+// @Security Bearer
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/json,application/xml
+// @Param id path string true "Portfolio ID"
+// @Param format query string true "Format: csv, xlsx, json or pp"
+// @Param type query string false "Type exported as CSV: holdings, transactions, dividends or realized_gains"
+// @Success 200 {file} file "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/export [get]
+func exportPortfolio(ctx context.Context, cfg config.Config, s ports.ExportService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		req := models.ExportReq{Format: r.URL.Query().Get("format"), Type: r.URL.Query().Get("type")}
+		name := fmt.Sprintf("portfolio-%s.%s", params["id"], req.Format)
+		switch req.Format {
+		case models.ExportFormatCSV:
+			name = fmt.Sprintf("portfolio-%s-%s.csv", params["id"], req.Type)
+		case models.ExportFormatPP:
+			name = fmt.Sprintf("portfolio-%s.xml", params["id"])
+		}
+
+		export := &exportWriter{w: w, contentType: exportContentTypes[req.Format], name: name}
+		err := s.Export(ctx, requestUserID(r), params["id"], req, export)
+		if err != nil && !export.started {
+			utils.ResponseError(w, r, nil, err)
+		}
+	})
+}
+
+// exportWriter sends the headers of an export with its first bytes, so an export failing before writing anything still gets an
+// error response. An export failing afterwards ends with a truncated body.
+type exportWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	name        string
+	started     bool
+}
+
+func (ew *exportWriter) Write(p []byte) (int, error) {
+	if !ew.started {
+		ew.started = true
+		ew.w.Header().Set("Content-Type", ew.contentType)
+		ew.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", ew.name))
+		ew.w.WriteHeader(http.StatusOK)
+	}
+	return ew.w.Write(p)
+}
+
+// @Summary Restore portfolio archive
+// @Description Creates a portfolio for a user from a JSON archive written by the portfolio export or from a Portfolio Performance XML file, whose securities accounts become holdings at the broker they are named after or else at the broker given. Invalid files create nothing, and a failed restore deletes what it created. Only the user or an admin can restore an archive for the user.
+// @Tags Exports
+// @Security Bearer
+// @Accept multipart/form-data
+// @Param id path string true "User ID"
+// @Param format query string false "Format: json (default) or pp"
+// @Param broker query string false "Slug of the broker of the Portfolio Performance securities accounts not named after a broker"
+// @Param file formData file true "JSON archive or Portfolio Performance file of a portfolio"
+// @Success 201 {object} models.ArchiveRestoreResp "Created"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/user/{id}/archive [post]
+func restoreArchive(ctx context.Context, cfg config.Config, s ports.ExportService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		if err := authorizeUser(r, params["id"]); err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxArchiveSize)
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			utils.ResponseError(w, r, nil, wrappers.NewValidationErr(fmt.Errorf("file cannot be read: %w", err)))
+			return
+		}
+		defer file.Close()
+
+		req := models.RestoreReq{Format: r.URL.Query().Get("format"), Broker: r.URL.Query().Get("broker")}
+		if req.Format == "" {
+			req.Format = models.ExportFormatJSON
+		}
+
+		result, err := s.Restore(ctx, params["id"], req, file)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusCreated, result)
+	})
+}
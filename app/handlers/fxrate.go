@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// SetFxRateRoutes creates fx rate routes
+func SetFxRateRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.FxRateService) {
+	r.Handle("/v1/fx-rate/many", middlewares.JWT(createManyFxRates(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{"admin": true})).Methods(http.MethodPost)
+	r.Handle("/v1/fx-rate/{base}/{quote}", middlewares.JWT(getFxRate(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+}
+
+// @Summary Create many FX rates
+// @Description Creates many FX rates atomically, replacing the rates already stored for the same pair and date
+// @Tags FX rates
+// @Security Bearer
+// @Param rates body []models.CreateFxRateReq true "New FX rates to be created"
+// @Success 201 {object} models.MultiCreationResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/fx-rate/many [post]
+func createManyFxRates(ctx context.Context, cfg config.Config, s ports.FxRateService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		var rates []models.CreateFxRateReq
+		err = json.Unmarshal(body, &rates)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		result, err := s.CreateMany(ctx, rates)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusCreated, result)
+	})
+}
+
+// @Summary Get FX rate
+// @Description Gets the rate of one unit of the base currency in the quote currency on a date, falling back to the nearest previous published rate
+// @Tags FX rates
+// @Security Bearer
+// @Param base path string true "Base currency"
+// @Param quote path string true "Quote currency"
+// @Param date query string false "Date in YYYY-MM-DD format, defaults to today"
+// @Success 200 {object} models.FxRateResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/fx-rate/{base}/{quote} [get]
+func getFxRate(ctx context.Context, cfg config.Config, s ports.FxRateService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		date, err := dateQueryParam(r, "date")
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		rate, err := s.GetRate(ctx, params["base"], params["quote"], date)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, rate)
+	})
+}
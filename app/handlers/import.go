@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// SetImportRoutes creates broker statement import routes
+func SetImportRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.ImportService) {
+	r.Handle("/v1/portfolio/{id}/import", middlewares.JWT(importStatement(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodPost)
+	r.Handle("/v1/import/{batchId}", middlewares.JWT(rollbackImport(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodDelete)
+}
+
+// @Summary Import broker statement
+// @Description Imports the trades and dividends of a statement exported by a broker into a portfolio as an import batch, or previews them in a dry run with the parsed, skipped, invalid and duplicate lines. A file already imported into the portfolio is rejected. The portfolio must belong to the user of the token.
+// @Tags Imports
+// @Security Bearer
+// @Accept multipart/form-data
+// @Param id path string true "Portfolio ID"
+// @Param file formData file true "Statement exported by the broker"
+// @Param broker formData string true "Slug of the broker: degiro, interactive-brokers, trading212 or revolut"
+// @Param format formData string false "Format of the statement when it was not exported by the broker: sharesight or ofx"
+// @Param dry_run formData bool false "Preview the import without storing it"
+// @Success 200 {object} models.ImportResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/import [post]
+func importStatement(ctx context.Context, cfg config.Config, s ports.ImportService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			utils.ResponseError(w, r, nil, wrappers.NewValidationErr(fmt.Errorf("file cannot be read: %w", err)))
+			return
+		}
+		defer file.Close()
+
+		req := models.ImportReq{Broker: r.FormValue("broker"), Format: r.FormValue("format"), FileName: header.Filename}
+		if value := r.FormValue("dry_run"); value != "" {
+			if req.DryRun, err = strconv.ParseBool(value); err != nil {
+				utils.ResponseError(w, r, nil, wrappers.NewValidationErr(fmt.Errorf("invalid dry_run %q", value)))
+				return
+			}
+		}
+
+		var params = mux.Vars(r)
+		result, err := s.Import(ctx, requestUserID(r), params["id"], req, file)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, result)
+	})
+}
+
+// @Summary Roll back import
+// @Description Deletes the transactions, dividends and withholding taxes created by an import batch and the batch itself in one database transaction. The batch must have been imported into a portfolio of the user of the token, and the dividends of stocks held in other portfolios are kept.
+// @Tags Imports
+// @Security Bearer
+// @Param batchId path string true "Import batch ID"
+// @Success 200 {object} models.ImportRollbackResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/import/{batchId} [delete]
+func rollbackImport(ctx context.Context, cfg config.Config, s ports.ImportService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		result, err := s.Rollback(ctx, requestUserID(r), params["batchId"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, result)
+	})
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// journalExtensions are the file extensions of the formats of a plain-text accounting journal
+var journalExtensions = map[string]string{
+	models.JournalFormatBeancount: "beancount",
+	models.JournalFormatHledger:   "journal",
+}
+
+// SetJournalRoutes creates plain-text accounting journal routes
+func SetJournalRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.JournalService) {
+	r.Handle("/v1/portfolio/{id}/journal", middlewares.JWT(exportJournal(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+}
+
+// @Summary Export portfolio journal
+// @Description Exports the trades and dividends of a portfolio as a Beancount or hledger journal, with commodity declarations, lots held at cost paid from the cash of the portfolio and price directives from the stored price history and FX rates, so its balances reconcile with the portfolio valuation. The portfolio must belong to the user of the token.
+// @Tags Exports
+// @Security Bearer
+// @Produce text/plain
+// @Param id path string true "Portfolio ID"
+// @Param format query string true "Format: beancount or hledger"
+// @Success 200 {file} file "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/journal [get]
+func exportJournal(ctx context.Context, cfg config.Config, s ports.JournalService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		req := models.JournalReq{Format: r.URL.Query().Get("format")}
+		name := fmt.Sprintf("portfolio-%s.%s", params["id"], journalExtensions[req.Format])
+
+		export := &exportWriter{w: w, contentType: "text/plain; charset=utf-8", name: name}
+		err := s.Export(ctx, requestUserID(r), params["id"], req, export)
+		if err != nil && !export.started {
+			utils.ResponseError(w, r, nil, err)
+		}
+	})
+}
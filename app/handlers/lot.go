@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// SetLotRoutes creates lot routes
+func SetLotRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.LotService) {
+	r.Handle("/v1/portfolio/{id}/lots", middlewares.JWT(getLots(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/portfolio/{id}/lots/rebuild", middlewares.JWT(rebuildLots(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodPost)
+	r.Handle("/v1/portfolio/{id}/realized-gains", middlewares.JWT(getRealizedGains(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/portfolio/{id}/cost-basis-method", middlewares.JWT(updateCostBasisMethod(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodPut)
+}
+
+// @Summary Get lots
+// @Description Gets the buy lots of a portfolio with their remaining quantity. The portfolio must belong to the user of the token.
+// @Tags Portfolios
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Success 200 {array} models.LotResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/lots [get]
+func getLots(ctx context.Context, cfg config.Config, s ports.LotService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		lots, err := s.GetLots(ctx, requestUserID(r), params["id"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, lots)
+	})
+}
+
+// @Summary Rebuild lots
+// @Description Recomputes the lots and realized gains of a portfolio from its transactions. The portfolio must belong to the user of the token.
+// @Tags Portfolios
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Success 200 {object} models.LedgerResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/lots/rebuild [post]
+func rebuildLots(ctx context.Context, cfg config.Config, s ports.LotService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		ledger, err := s.Rebuild(ctx, requestUserID(r), params["id"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, ledger)
+	})
+}
+
+// @Summary Get realized gains
+// @Description Gets the realized gains of a portfolio, one per sold lot. The portfolio must belong to the user of the token.
+// @Tags Portfolios
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Success 200 {array} models.RealizedGainResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/realized-gains [get]
+func getRealizedGains(ctx context.Context, cfg config.Config, s ports.LotService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		gains, err := s.GetRealizedGains(ctx, requestUserID(r), params["id"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, gains)
+	})
+}
+
+// @Summary Update cost basis method
+// @Description Switches the cost basis method of a portfolio and rebuilds its lots and realized gains. The portfolio must belong to the user of the token.
+// @Tags Portfolios
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Param method body models.UpdateCostBasisMethodReq true "Cost basis method"
+// @Success 200 {object} models.LedgerResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/cost-basis-method [put]
+func updateCostBasisMethod(ctx context.Context, cfg config.Config, s ports.LotService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		var method models.UpdateCostBasisMethodReq
+		err = json.Unmarshal(body, &method)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		ledger, err := s.UpdateCostBasisMethod(ctx, requestUserID(r), params["id"], method)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusOK, ledger)
+	})
+}
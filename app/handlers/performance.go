@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// dateLayout is the layout of the date query parameters
+const dateLayout = "2006-01-02"
+
+// SetPerformanceRoutes creates performance routes
+func SetPerformanceRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.PerformanceService) {
+	r.Handle("/v1/portfolio/{id}/performance", middlewares.JWT(getPortfolioPerformance(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/holding/{id}/performance", middlewares.JWT(getHoldingPerformance(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+}
+
+// @Summary Get portfolio performance
+// @Description Gets the time-weighted and money-weighted (XIRR) returns of a portfolio over a date range, plus their annualized variants. The portfolio must belong to the user of the token.
+// @Tags Portfolios
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Param from query string false "Start date (YYYY-MM-DD), defaults to the first transaction"
+// @Param to query string false "End date (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} models.PerformanceResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/performance [get]
+func getPortfolioPerformance(ctx context.Context, cfg config.Config, s ports.PerformanceService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		req, err := performanceReq(r)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		performance, err := s.GetPortfolioPerformance(ctx, requestUserID(r), params["id"], req)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, performance)
+	})
+}
+
+// @Summary Get holding performance
+// @Description Gets the time-weighted and money-weighted (XIRR) returns of a holding over a date range, plus their annualized variants. The portfolio of the holding must belong to the user of the token.
+// @Tags Holdings
+// @Security Bearer
+// @Param id path string true "Holding ID"
+// @Param from query string false "Start date (YYYY-MM-DD), defaults to the first transaction"
+// @Param to query string false "End date (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} models.PerformanceResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/holding/{id}/performance [get]
+func getHoldingPerformance(ctx context.Context, cfg config.Config, s ports.PerformanceService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		req, err := performanceReq(r)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		performance, err := s.GetHoldingPerformance(ctx, requestUserID(r), params["id"], req)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, performance)
+	})
+}
+
+// performanceReq reads the from and to query parameters of a request
+func performanceReq(r *http.Request) (req models.PerformanceReq, err error) {
+	req.From, err = dateQueryParam(r, "from")
+	if err != nil {
+		return
+	}
+	req.To, err = dateQueryParam(r, "to")
+	return
+}
+
+// dateQueryParam parses an optional date query parameter, returning the zero time when it is missing
+func dateQueryParam(r *http.Request, name string) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, wrappers.NewValidationErr(fmt.Errorf("%s must be a date in YYYY-MM-DD format", name))
+	}
+	return date, nil
+}
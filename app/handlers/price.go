@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// SetPriceRoutes creates price routes
+func SetPriceRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.PriceService) {
+	r.Handle("/v1/stock/{id}/prices", middlewares.JWT(getStockPrices(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/price/many", middlewares.JWT(createManyPrices(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{"admin": true})).Methods(http.MethodPost)
+	r.Handle("/v1/price/import", middlewares.JWT(importPrices(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{"admin": true})).Methods(http.MethodPost)
+	r.Handle("/v1/price/last", middlewares.JWT(setLastPrice(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{"admin": true})).Methods(http.MethodPut)
+}
+
+// @Summary Get stock prices
+// @Description Gets the prices of a stock over a date range by day, week or month, the business days without a daily price and the intraday last price
+// @Tags Prices
+// @Security Bearer
+// @Param id path string true "Stock ID"
+// @Param from query string false "Start date (YYYY-MM-DD), defaults to the first stored price"
+// @Param to query string false "End date (YYYY-MM-DD), defaults to today"
+// @Param interval query string false "Interval of the prices: 1d, 1wk or 1mo, defaults to 1d"
+// @Success 200 {object} models.PriceHistoryResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/stock/{id}/prices [get]
+func getStockPrices(ctx context.Context, cfg config.Config, s ports.PriceService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var req models.PriceHistoryReq
+		var err error
+		if req.From, err = dateQueryParam(r, "from"); err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		if req.To, err = dateQueryParam(r, "to"); err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		req.Interval = r.URL.Query().Get("interval")
+
+		var params = mux.Vars(r)
+		prices, err := s.GetStockPrices(ctx, params["id"], req)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, prices)
+	})
+}
+
+// @Summary Create many prices
+// @Description Creates many daily prices atomically, replacing the prices already stored for the same instrument and day
+// @Tags Prices
+// @Security Bearer
+// @Param prices body []models.CreatePriceReq true "New daily prices to be created"
+// @Success 201 {object} models.MultiCreationResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/price/many [post]
+func createManyPrices(ctx context.Context, cfg config.Config, s ports.PriceService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		var prices []models.CreatePriceReq
+		err = json.Unmarshal(body, &prices)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		result, err := s.CreateMany(ctx, prices)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusCreated, result)
+	})
+}
+
+// @Summary Import prices
+// @Description Imports the daily prices of an instrument from a Yahoo Finance or Stooq CSV file, replacing the prices already stored for the same days
+// @Tags Prices
+// @Security Bearer
+// @Accept multipart/form-data
+// @Param file formData file true "CSV file with Date, Open, High, Low, Close and Volume columns"
+// @Param instrumentid formData string true "Stock or cryptocurrency ID"
+// @Param instrument_type formData string true "Instrument type: stock or cryptocurrency"
+// @Param currency formData string true "Currency of the prices"
+// @Param source formData string false "Source of the prices, defaults to csv"
+// @Success 200 {object} models.PriceImportResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/price/import [post]
+func importPrices(ctx context.Context, cfg config.Config, s ports.PriceService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			utils.ResponseError(w, r, nil, wrappers.NewValidationErr(fmt.Errorf("file cannot be read: %w", err)))
+			return
+		}
+		defer file.Close()
+
+		req := models.ImportPricesReq{
+			InstrumentID:   r.FormValue("instrumentid"),
+			InstrumentType: r.FormValue("instrument_type"),
+			Currency:       r.FormValue("currency"),
+			Source:         r.FormValue("source"),
+		}
+		result, err := s.ImportCSV(ctx, req, file)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, result)
+	})
+}
+
+// @Summary Set last price
+// @Description Sets the intraday last price of an instrument, replacing the previous one
+// @Tags Prices
+// @Security Bearer
+// @Param price body models.SetLastPriceReq true "Last price"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/price/last [put]
+func setLastPrice(ctx context.Context, cfg config.Config, s ports.PriceService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		var price models.SetLastPriceReq
+		err = json.Unmarshal(body, &price)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		err = s.SetLastPrice(ctx, price)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusOK, nil)
+	})
+}
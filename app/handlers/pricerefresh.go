@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// SetPriceRefreshRoutes creates price refresh routes
+func SetPriceRefreshRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.PriceRefreshService) {
+	r.Handle("/v1/admin/price-refresh", middlewares.JWT(refreshPrices(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{"admin": true})).Methods(http.MethodPost)
+	r.Handle("/v1/admin/price-refresh/runs", middlewares.JWT(getPriceRefreshRuns(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{"admin": true})).Methods(http.MethodGet)
+}
+
+// @Summary Refresh prices
+// @Description Refreshes now the last prices of the instruments in open positions from the market data provider and returns the stats of the run
+// @Tags Prices
+// @Security Bearer
+// @Success 200 {object} models.PriceRefreshRunResp "OK"
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/admin/price-refresh [post]
+func refreshPrices(ctx context.Context, cfg config.Config, s ports.PriceRefreshService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		run, err := s.Refresh(ctx)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, run)
+	})
+}
+
+// @Summary Get price refresh runs
+// @Description Gets the stats of the most recent price refresh runs, newest first
+// @Tags Prices
+// @Security Bearer
+// @Param limit query int false "Number of runs, defaults to 20 and at most 100"
+// @Success 200 {array} models.PriceRefreshRunResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/admin/price-refresh/runs [get]
+func getPriceRefreshRuns(ctx context.Context, cfg config.Config, s ports.PriceRefreshService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var limit int
+		if value := r.URL.Query().Get("limit"); value != "" {
+			var err error
+			if limit, err = strconv.Atoi(value); err != nil {
+				utils.ResponseError(w, r, nil, wrappers.NewValidationErr(fmt.Errorf("invalid limit %q", value)))
+				return
+			}
+		}
+
+		runs, err := s.GetRuns(ctx, limit)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, runs)
+	})
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// SetTaxReportRoutes creates tax report routes
+func SetTaxReportRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.TaxReportService) {
+	r.Handle("/v1/portfolio/{id}/tax-report", middlewares.JWT(getTaxReport(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/portfolio/{id}/tax-report/dividends", middlewares.JWT(getDividendTaxReport(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+}
+
+// @Summary Get tax report
+// @Description Gets the gains realized by a portfolio in a financial year with the tax treatment the rules of the portfolio's tax country gave to each sale. The portfolio must belong to the user of the token.
+// @Tags Portfolios
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Param year query int false "Year the financial year starts in, defaults to the financial year in progress"
+// @Success 200 {object} models.TaxReportResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/tax-report [get]
+func getTaxReport(ctx context.Context, cfg config.Config, s ports.TaxReportService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		req, err := taxReportReq(r)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		report, err := s.GetReport(ctx, requestUserID(r), params["id"], req)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, report)
+	})
+}
+
+// @Summary Get dividend tax report
+// @Description Gets the dividends paid to a portfolio in a financial year with the tax withheld by the issuer countries and the foreign tax credit they give in the portfolio's tax country. The portfolio must belong to the user of the token.
+// @Tags Portfolios
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Param year query int false "Year the financial year starts in, defaults to the financial year in progress"
+// @Success 200 {object} models.DividendTaxReportResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/tax-report/dividends [get]
+func getDividendTaxReport(ctx context.Context, cfg config.Config, s ports.TaxReportService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		req, err := taxReportReq(r)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		report, err := s.GetDividends(ctx, requestUserID(r), params["id"], req)
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, report)
+	})
+}
+
+// taxReportReq reads the year of a tax report from the query
+func taxReportReq(r *http.Request) (req models.TaxReportReq, err error) {
+	if value := r.URL.Query().Get("year"); value != "" {
+		if req.Year, err = strconv.Atoi(value); err != nil {
+			err = wrappers.NewValidationErr(fmt.Errorf("invalid year %q", value))
+		}
+	}
+	return
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// SetTransactionRoutes creates transaction routes
+func SetTransactionRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.TransactionService) {
+	r.Handle("/v1/portfolio/{id}/transaction", middlewares.JWT(createTransaction(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodPost)
+	r.Handle("/v1/portfolio/{id}/transaction", middlewares.JWT(getTransactions(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/portfolio/{id}/transaction/{transactionId}", middlewares.JWT(getTransaction(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/portfolio/{id}/transaction/{transactionId}", middlewares.JWT(updateTransaction(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodPatch)
+	r.Handle("/v1/portfolio/{id}/transaction/{transactionId}", middlewares.JWT(deleteTransaction(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodDelete)
+	r.Handle("/v1/portfolio/{id}/cash", middlewares.JWT(getCashBalances(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+}
+
+// @Summary Create transaction
+// @Description Creates a buy, sell, dividend, fee, deposit, withdrawal, interest, transfer or split in a holding of a portfolio. The portfolio must belong to the user of the token.
+// @Tags Transactions
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Param transaction body models.CreateTransactionReq true "New transaction"
+// @Success 201 {object} models.CreationResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/transaction [post]
+func createTransaction(ctx context.Context, cfg config.Config, s ports.TransactionService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		var transaction models.CreateTransactionReq
+		err = json.Unmarshal(body, &transaction)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		result, err := s.Create(ctx, requestUserID(r), params["id"], transaction)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusCreated, result)
+	})
+}
+
+// @Summary Get transactions
+// @Description Gets the transactions of a portfolio as they were entered. The portfolio must belong to the user of the token.
+// @Tags Transactions
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Success 200 {array} models.TransactionResp "OK"
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/transaction [get]
+func getTransactions(ctx context.Context, cfg config.Config, s ports.TransactionService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		transactions, err := s.GetAll(ctx, requestUserID(r), params["id"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, transactions)
+	})
+}
+
+// @Summary Get transaction
+// @Description Gets a transaction of a portfolio. The portfolio must belong to the user of the token.
+// @Tags Transactions
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Param transactionId path string true "Transaction ID"
+// @Success 200 {object} models.TransactionResp "OK"
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/transaction/{transactionId} [get]
+func getTransaction(ctx context.Context, cfg config.Config, s ports.TransactionService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		transaction, err := s.GetByID(ctx, requestUserID(r), params["id"], params["transactionId"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, transaction)
+	})
+}
+
+// @Summary Update transaction
+// @Description Replaces a transaction of a portfolio. The portfolio must belong to the user of the token.
+// @Tags Transactions
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Param transactionId path string true "Transaction ID"
+// @Param transaction body models.UpdateTransactionReq true "Transaction"
+// @Success 200 "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/transaction/{transactionId} [patch]
+func updateTransaction(ctx context.Context, cfg config.Config, s ports.TransactionService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		var params = mux.Vars(r)
+		var transaction models.UpdateTransactionReq
+		err = json.Unmarshal(body, &transaction)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+
+		err = s.Update(ctx, requestUserID(r), params["id"], params["transactionId"], transaction)
+		if err != nil {
+			utils.ResponseError(w, r, body, err)
+			return
+		}
+		utils.ResponseJSON(w, r, body, http.StatusOK, nil)
+	})
+}
+
+// @Summary Delete transaction
+// @Description Deletes a transaction of a portfolio. The portfolio must belong to the user of the token.
+// @Tags Transactions
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Param transactionId path string true "Transaction ID"
+// @Success 200 "OK"
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/transaction/{transactionId} [delete]
+func deleteTransaction(ctx context.Context, cfg config.Config, s ports.TransactionService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		err := s.Delete(ctx, requestUserID(r), params["id"], params["transactionId"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, nil)
+	})
+}
+
+// @Summary Get cash balances
+// @Description Gets the cash balance of a portfolio in each currency, with the deposits, withdrawals, trades, dividends, interest and fees it adds up. The portfolio must belong to the user of the token.
+// @Tags Transactions
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Success 200 {object} models.CashBalancesResp "OK"
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/cash [get]
+func getCashBalances(ctx context.Context, cfg config.Config, s ports.TransactionService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		balances, err := s.GetCashBalances(ctx, requestUserID(r), params["id"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, balances)
+	})
+}
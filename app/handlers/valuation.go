@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/scv-go-tools/v3/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// SetValuationRoutes creates valuation routes
+func SetValuationRoutes(ctx context.Context, cfg config.Config, r *mux.Router, s ports.ValuationService) {
+	r.Handle("/v1/portfolio/{id}/valuation", middlewares.JWT(getPortfolioValuation(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+	r.Handle("/v1/user/{id}/broker-account/{accountId}/valuation", middlewares.JWT(getBrokerAccountValuation(ctx, cfg, s), cfg.JWTSecret, jwt.MapClaims{})).Methods(http.MethodGet)
+}
+
+// @Summary Get portfolio valuation
+// @Description Gets the open positions of a portfolio valued at the latest prices. The portfolio must belong to the user of the token.
+// @Tags Portfolios
+// @Security Bearer
+// @Param id path string true "Portfolio ID"
+// @Success 200 {object} models.ValuationResp "OK"
+// @Failure 400 {object} object
+// @Failure 401 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/portfolio/{id}/valuation [get]
+func getPortfolioValuation(ctx context.Context, cfg config.Config, s ports.ValuationService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		valuation, err := s.GetPortfolioValuation(ctx, requestUserID(r), params["id"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, valuation)
+	})
+}
+
+// @Summary Get broker account valuation
+// @Description Gets the open positions of the holdings attached to a broker account valued at the latest prices, with its cash per currency. Only the user or an admin can get it.
+// @Tags Broker accounts
+// @Security Bearer
+// @Param id path string true "User ID"
+// @Param accountId path string true "Broker account ID"
+// @Success 200 {object} models.BrokerAccountValuationResp "OK"
+// @Failure 401 {object} object
+// @Failure 404 {object} object
+// @Failure 408 {object} object
+// @Failure 500 {object} object
+// @Router /v1/user/{id}/broker-account/{accountId}/valuation [get]
+func getBrokerAccountValuation(ctx context.Context, cfg config.Config, s ports.ValuationService) http.Handler {
+	return middlewares.Recover(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+		defer cancel()
+
+		var params = mux.Vars(r)
+		if err := authorizeUser(r, params["id"]); err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		valuation, err := s.GetBrokerAccountValuation(ctx, params["id"], params["accountId"])
+		if err != nil {
+			utils.ResponseError(w, r, nil, err)
+			return
+		}
+		utils.ResponseJSON(w, r, nil, http.StatusOK, valuation)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/jessevdk/go-flags"
@@ -26,8 +27,15 @@ func main() {
 		Database    string `long:"db" description:"The database adapter to use" choice:"mongo" choice:"postgres" required:"true"`
 		DSN         string `long:"dsn" description:"DSN of the selected database" required:"true"`
 	}
+	var importECB struct {
+		File string `long:"file" description:"Path of the ECB eurofxref-hist XML or CSV file" required:"true"`
+	}
+
+	parser := flags.NewParser(&opts, flags.Default)
+	parser.SubcommandsOptional = true
+	parser.AddCommand("import-ecb", "Import ECB reference rates", "Imports an ECB eurofxref-hist XML or CSV file into the FX rates and exits", &importECB)
 
-	args, err := flags.Parse(&opts)
+	args, err := parser.Parse()
 	if err != nil {
 		log.Fatal(fmt.Errorf("provided flags not valid: %s, %w", args, err))
 	}
@@ -37,6 +45,13 @@ func main() {
 		log.Fatal(fmt.Errorf("cannot parse config file for env %s: %w", opts.Environment, err))
 	}
 
+	if parser.Active != nil && parser.Active.Name == "import-ecb" {
+		if err := runImportECB(cfg, importECB.File); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	var g multierror.Group
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -44,7 +59,7 @@ func main() {
 	g.Go(a.Run(ctx, cancel))
 
 	if cfg.Async.Run {
-		async := async.New(cfg)
+		async := async.New(cfg, a.PriceRefresh(), a.Alerts(), a.CompanyEvents(), a.Digests())
 		g.Go(async.Run(ctx, cancel))
 	}
 
@@ -52,3 +67,22 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// runImportECB imports an ECB reference rates file into the database of the configuration
+func runImportECB(cfg config.Config, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("cannot open ECB file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	ctx := context.Background()
+	a := api.New(ctx, cfg)
+	result, err := a.ImportECB(ctx, file)
+	if err != nil {
+		return fmt.Errorf("cannot import ECB file %s: %w", path, err)
+	}
+
+	log.Printf("Imported %d ECB rates and %d cross rates for %d days from %s to %s", result.Rates, result.CrossRates, result.Days, result.From.Format("2006-01-02"), result.To.Format("2006-01-02"))
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"path"
 
 	"github.com/sergicanet9/scv-go-tools/v3/api/utils"
+	"github.com/shopspring/decimal"
 )
 
 type Async struct {
@@ -12,6 +13,76 @@ type Async struct {
 	Interval utils.Duration
 }
 
+// FX configures currency conversion
+type FX struct {
+	// PivotCurrency is the currency used to cross two currencies without a direct rate
+	PivotCurrency string
+	// Lookback is how far before a date a rate is still used for that date
+	Lookback utils.Duration
+}
+
+// MarketData configures the market data provider and the guards around its calls
+type MarketData struct {
+	// Provider is the name of the registered market data adapter
+	Provider string
+	// FixturesDir is the directory of the symbol files read by the file provider
+	FixturesDir string
+	// CacheTTL is how long a provider response is reused
+	CacheTTL utils.Duration
+	// RequestsPerMinute is the most provider calls made per minute, unlimited when zero
+	RequestsPerMinute int
+	// FailureThreshold is the number of consecutive failed calls that opens the circuit breaker, never opened when zero
+	FailureThreshold int
+	// BreakerCooldown is how long the circuit breaker stays open before a call is tried again
+	BreakerCooldown utils.Duration
+}
+
+// CompanyEvents configures the company event alerts
+type CompanyEvents struct {
+	// NoticeDays is how many days before an event the owners of the portfolios holding the stock are notified
+	NoticeDays int
+	// SyncHorizon is how far ahead the events are fetched from the market data provider
+	SyncHorizon utils.Duration
+}
+
+// Mail configures how the emails are sent
+type Mail struct {
+	// Adapter is the name of the mailer: smtp, or outbox to write the emails to files
+	Adapter string
+	// From is the sender address of the emails
+	From string
+	// Host is the address of the SMTP server
+	Host string
+	// Port is the port of the SMTP server
+	Port int
+	// Username authenticates against the SMTP server, no authentication when empty
+	Username string
+	// Password authenticates against the SMTP server
+	Password string
+	// OutboxDir is the directory the outbox mailer writes the emails to
+	OutboxDir string
+}
+
+// Digest configures the portfolio summary digests
+type Digest struct {
+	// Periods are the digests sent to the portfolios with the summary enabled: weekly, monthly or both
+	Periods []string
+	// TopMovers is the number of instruments listed by their price move
+	TopMovers int
+	// EventDays is how many days ahead the upcoming events are listed
+	EventDays int
+}
+
+// Withholding configures the tax withheld from dividends, keyed by ISO 3166-1 alpha-2 country codes
+type Withholding struct {
+	// Rates are the statutory rates withheld by the issuer countries
+	Rates map[string]decimal.Decimal
+	// TreatyRates are the rates of the tax treaties, keyed by the issuer country and then by the country of residence
+	TreatyRates map[string]map[string]decimal.Decimal
+	// ReliefAtSource are the issuer countries withholding the treaty rate at payment instead of refunding the excess on a claim
+	ReliefAtSource []string
+}
+
 type Config struct {
 	// set in flags
 	Version     string
@@ -29,6 +100,12 @@ type config struct {
 	JWTSecret             string
 	Timeout               utils.Duration
 	Async                 Async
+	FX                    FX
+	MarketData            MarketData
+	CompanyEvents         CompanyEvents
+	Mail                  Mail
+	Digest                Digest
+	Withholding           Withholding
 }
 
 // ReadConfig from the project´s JSON config files.
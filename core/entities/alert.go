@@ -0,0 +1,52 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// EntityNameAlertRule contains the name of the entity
+const EntityNameAlertRule = "alert_rule"
+
+// EntityNameAlert contains the name of the entity
+const EntityNameAlert = "alert"
+
+// Alert rule conditions. Above and below compare the latest price with a price threshold, while the moves compare the change
+// of the latest price in percent, against the previous daily close or the average cost of the position, with a signed threshold:
+// a positive threshold triggers on a rise of at least that much and a negative one on a fall of at least that much.
+const (
+	AlertConditionAbove         = "above"
+	AlertConditionBelow         = "below"
+	AlertConditionDayMove       = "day_move"
+	AlertConditionCostBasisMove = "cost_basis_move"
+)
+
+// AlertRule struct
+type AlertRule struct {
+	AlertRuleID    string          `bson:"_alert_ruleid,omitempty"`
+	PortfolioID    string          `bson:"portfolioid"`
+	InstrumentID   string          `bson:"instrumentid"`
+	InstrumentType string          `bson:"instrument_type"`
+	Condition      string          `bson:"condition"`
+	Threshold      decimal.Decimal `bson:"threshold"`
+	Active         bool            `bson:"active"`
+	CreatedAt      time.Time       `bson:"created_at"`
+	UpdatedAt      time.Time       `bson:"updated_at"`
+}
+
+// Alert struct holds an alert rule triggered on a day, with the price and the value that triggered it
+type Alert struct {
+	AlertID        string          `bson:"_alertid,omitempty"`
+	AlertRuleID    string          `bson:"alert_ruleid"`
+	PortfolioID    string          `bson:"portfolioid"`
+	InstrumentID   string          `bson:"instrumentid"`
+	InstrumentType string          `bson:"instrument_type"`
+	Condition      string          `bson:"condition"`
+	Threshold      decimal.Decimal `bson:"threshold"`
+	Price          Money           `bson:"price"`
+	Value          decimal.Decimal `bson:"value"`
+	TriggerDate    time.Time       `bson:"trigger_date"`
+	TriggeredAt    time.Time       `bson:"triggered_at"`
+	NotifiedAt     time.Time       `bson:"notified_at"`
+}
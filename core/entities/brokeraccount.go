@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"time"
+)
+
+// EntityNameBrokerAccount contains the name of the entity
+const EntityNameBrokerAccount = "broker_account"
+
+// BrokerAccount struct holds an account of a user at a broker of the catalog, which the holdings of the user at that broker are
+// attached to. AccountNumber is stored masked, and ClosedAt is zero while the account is open.
+type BrokerAccount struct {
+	BrokerAccountID string    `bson:"_broker_accountid,omitempty"`
+	UserID          string    `bson:"userid"`
+	BrokerID        string    `bson:"brokerid"`
+	AccountLabel    string    `bson:"account_label"`
+	AccountNumber   string    `bson:"account_number"`
+	BaseCurrency    string    `bson:"base_currency"`
+	OpenedAt        time.Time `bson:"opened_at"`
+	ClosedAt        time.Time `bson:"closed_at"`
+	CreatedAt       time.Time `bson:"created_at"`
+	UpdatedAt       time.Time `bson:"updated_at"`
+}
+
+// Open reports whether the account was not closed on a day
+func (a BrokerAccount) Open(day time.Time) bool {
+	return a.ClosedAt.IsZero() || day.Before(a.ClosedAt)
+}
@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"time"
+)
+
+// EntityNameCalendarFeed contains the name of the entity
+const EntityNameCalendarFeed = "calendar_feed"
+
+// CalendarFeed struct holds the calendar feed of a user. Only the SHA-256 hash of its secret token is stored.
+type CalendarFeed struct {
+	CalendarFeedID string    `bson:"_calendar_feedid,omitempty"`
+	UserID         string    `bson:"userid"`
+	TokenHash      string    `bson:"token_hash"`
+	CreatedAt      time.Time `bson:"created_at"`
+	UpdatedAt      time.Time `bson:"updated_at"`
+}
+
+// CalendarEvent struct is an all-day event of a calendar feed. UID stays the same across the versions of an event,
+// so calendar apps replace it when it changes.
+type CalendarEvent struct {
+	UID         string
+	Date        time.Time
+	Summary     string
+	Description string
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// EntityNameCompanyEvent contains the name of the entity
+const EntityNameCompanyEvent = "company_event"
+
+// Company event types
+const (
+	CompanyEventEarnings        = "earnings"
+	CompanyEventExDividend      = "ex_dividend"
+	CompanyEventDividendPayment = "dividend_payment"
+	CompanyEventSplit           = "split"
+	CompanyEventDelisting       = "delisting"
+)
+
+// CompanyEvent struct holds a dated event of a stock. Dividend events carry the amount per share and splits the ratio where
+// SplitNumerator new shares replace SplitDenominator old ones.
+type CompanyEvent struct {
+	CompanyEventID   string          `bson:"_company_eventid,omitempty"`
+	StockID          string          `bson:"stockid"`
+	EventType        string          `bson:"event_type"`
+	EventDate        time.Time       `bson:"event_date"`
+	Amount           Money           `bson:"amount"`
+	SplitNumerator   decimal.Decimal `bson:"split_numerator"`
+	SplitDenominator decimal.Decimal `bson:"split_denominator"`
+	Description      string          `bson:"description"`
+	Source           string          `bson:"source"`
+	CreatedAt        time.Time       `bson:"created_at"`
+	UpdatedAt        time.Time       `bson:"updated_at"`
+}
@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// EntityNameCorporateAction contains the name of the entity
+const EntityNameCorporateAction = "corporate_action"
+
+// Corporate action types
+const (
+	CorporateActionSplit        = "split"
+	CorporateActionReverseSplit = "reverse_split"
+	CorporateActionSymbolChange = "symbol_change"
+	CorporateActionMerger       = "merger"
+	CorporateActionSpinOff      = "spin_off"
+)
+
+// CorporateAction struct holds a change to a stock effective from a day. Splits, mergers and spin-offs give Numerator new shares
+// for every Denominator shares held, of the stock itself for splits and of TargetStockID for mergers and spin-offs. A spin-off
+// moves CostAllocation, a fraction between zero and one, of the cost basis to the spun-off stock. Symbol changes record the
+// previous and new ticker symbol and the new ISIN.
+type CorporateAction struct {
+	CorporateActionID string          `bson:"_corporate_actionid,omitempty"`
+	StockID           string          `bson:"stockid"`
+	ActionType        string          `bson:"action_type"`
+	EffectiveDate     time.Time       `bson:"effective_date"`
+	Numerator         decimal.Decimal `bson:"numerator"`
+	Denominator       decimal.Decimal `bson:"denominator"`
+	TargetStockID     string          `bson:"target_stockid"`
+	CostAllocation    decimal.Decimal `bson:"cost_allocation"`
+	OldSymbol         string          `bson:"old_symbol"`
+	NewSymbol         string          `bson:"new_symbol"`
+	NewISIN           string          `bson:"new_isin"`
+	Description       string          `bson:"description"`
+	CreatedAt         time.Time       `bson:"created_at"`
+	UpdatedAt         time.Time       `bson:"updated_at"`
+}
@@ -0,0 +1,25 @@
+package entities
+
+import (
+	"time"
+)
+
+// EntityNameDigest contains the name of the entity
+const EntityNameDigest = "digest"
+
+// Periods of the portfolio summary digests
+const (
+	DigestPeriodWeekly  = "weekly"
+	DigestPeriodMonthly = "monthly"
+)
+
+// Digest struct records a summary digest sent to the owner of a portfolio for a period
+type Digest struct {
+	DigestID    string    `bson:"_digestid,omitempty"`
+	PortfolioID string    `bson:"portfolioid"`
+	Period      string    `bson:"period"`
+	PeriodStart time.Time `bson:"period_start"`
+	PeriodEnd   time.Time `bson:"period_end"`
+	SentTo      string    `bson:"sent_to"`
+	SentAt      time.Time `bson:"sent_at"`
+}
@@ -2,17 +2,55 @@ package entities
 
 import (
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 // EntityNameDividend contains the name of the entity
 const EntityNameDividend = "dividend"
 
-// Dividend struct
+// Dividend struct. DividendPerShare is the gross amount declared per share in the currency it is paid in,
+// before any tax is withheld. RecordDate and PayDate are zero when unknown.
+// ImportBatchID is set on the dividends created by an import.
 type Dividend struct {
 	DividendID       string    `bson:"dividendid,omitempty"`
 	StockID          string    `bson:"stockid,omitempty"`
-	DividendPerShare int       `bson:"dividend_per_share"`
-	DividendDate     time.Time `bson:"dividend_date"`
+	DividendPerShare Money     `bson:"dividend_per_share"`
+	ExDate           time.Time `bson:"ex_date"`
+	RecordDate       time.Time `bson:"record_date"`
+	PayDate          time.Time `bson:"pay_date"`
+	ImportBatchID    string    `bson:"import_batchid"`
 	CreatedAt        time.Time `bson:"created_at"`
 	UpdatedAt        time.Time `bson:"updated_at"`
 }
+
+// PortfolioDividend struct is a dividend of a stock traded in a portfolio with the shares the portfolio held on its ex-date.
+// Withholding is the tax withheld from the payment to the portfolio, which is zero when none is recorded.
+type PortfolioDividend struct {
+	Dividend
+	Shares      decimal.Decimal `bson:"shares"`
+	Withholding Money           `bson:"withholding"`
+}
+
+// EntityNameDividendWithholding contains the name of the entity
+const EntityNameDividendWithholding = "dividend_withholding"
+
+// DividendWithholding struct is the tax withheld from a dividend paid to a portfolio, as reported by the broker, in the currency of
+// the dividend. ImportBatchID is set on the withholdings created by an import.
+type DividendWithholding struct {
+	DividendWithholdingID string    `bson:"dividend_withholdingid,omitempty"`
+	PortfolioID           string    `bson:"portfolioid"`
+	DividendID            string    `bson:"dividendid"`
+	Amount                Money     `bson:"amount"`
+	ImportBatchID         string    `bson:"import_batchid"`
+	CreatedAt             time.Time `bson:"created_at"`
+	UpdatedAt             time.Time `bson:"updated_at"`
+}
+
+// PaidOn returns the day the dividend is paid, falling back to the ex-date when the pay date is unknown
+func (d Dividend) PaidOn() time.Time {
+	if d.PayDate.IsZero() {
+		return d.ExDate
+	}
+	return d.PayDate
+}
@@ -0,0 +1,22 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// EntityNameFxRate contains the name of the entity
+const EntityNameFxRate = "fx_rate"
+
+// FxRate is the price of one unit of the base currency in the quote currency on a date
+type FxRate struct {
+	FxRateID      string          `bson:"_fx_rateid,omitempty"`
+	BaseCurrency  string          `bson:"base_currency"`
+	QuoteCurrency string          `bson:"quote_currency"`
+	RateDate      time.Time       `bson:"rate_date"`
+	Rate          decimal.Decimal `bson:"rate"`
+	Source        string          `bson:"source"`
+	CreatedAt     time.Time       `bson:"created_at"`
+	UpdatedAt     time.Time       `bson:"updated_at"`
+}
@@ -2,28 +2,31 @@ package entities
 
 import (
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 // EntityNameHolding contains the name of the entity
 const EntityNameHolding = "holding"
 
-// Holding struct
+// Holding struct. BrokerID is the broker of the catalog and BrokerAccountID the account of the owner of the portfolio at that broker,
+// empty until the holding is attached to one.
 type Holding struct {
-	HoldingID          string    `bson:"_holdingid,omitempty"`
-	PortfolioID        string    `bson:"portfolioid"`
-	BrokerID           string    `bson:"brokerid"`
-	Extid              string    `bson:"extid"`
-	Name               string    `bson:"name"`
-	Description        string    `bson:"description"`
-	Slug               string    `bson:"slug"`
-	TradeDate          time.Time `bson:"trade_date"`
-	TradeType          string    `bson:"trade_type"`
-	Quantity           int       `bson:"quantity"`
-	SharePrice         int       `bson:"share_price"`
-	ExchangeRate       int       `bson:"exchange_rate"`
-	ExchangeCurrencyID string    `bson:"exchange_currencyid"`
-	BrokerageUnitPrice int       `bson:"brokerage_unit_price"`
-	BrokerageCurrency  string    `bson:"brokerage_currency"`
-	CreatedAt          time.Time `bson:"created_at"`
-	UpdatedAt          time.Time `bson:"updated_at"`
+	HoldingID          string          `bson:"_holdingid,omitempty"`
+	PortfolioID        string          `bson:"portfolioid"`
+	BrokerID           string          `bson:"brokerid"`
+	BrokerAccountID    string          `bson:"broker_accountid"`
+	Extid              string          `bson:"extid"`
+	Name               string          `bson:"name"`
+	Description        string          `bson:"description"`
+	Slug               string          `bson:"slug"`
+	TradeDate          time.Time       `bson:"trade_date"`
+	TradeType          string          `bson:"trade_type"`
+	Quantity           decimal.Decimal `bson:"quantity"`
+	SharePrice         Money           `bson:"share_price"`
+	ExchangeRate       decimal.Decimal `bson:"exchange_rate"`
+	ExchangeCurrencyID string          `bson:"exchange_currencyid"`
+	BrokerageUnitPrice Money           `bson:"brokerage_unit_price"`
+	CreatedAt          time.Time       `bson:"created_at"`
+	UpdatedAt          time.Time       `bson:"updated_at"`
 }
@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"time"
+)
+
+// EntityNameImportBatch contains the name of the entity
+const EntityNameImportBatch = "import_batch"
+
+// ImportBatch struct records an import of a broker statement into a portfolio. Fingerprint is the SHA-256 hash of the
+// statement file, so the same file is not imported twice into a portfolio.
+type ImportBatch struct {
+	ImportBatchID string    `bson:"import_batchid,omitempty"`
+	PortfolioID   string    `bson:"portfolioid"`
+	BrokerID      string    `bson:"brokerid"`
+	FileName      string    `bson:"file_name"`
+	Fingerprint   string    `bson:"fingerprint"`
+	CreatedAt     time.Time `bson:"created_at"`
+	UpdatedAt     time.Time `bson:"updated_at"`
+}
+
+// ImportRollback struct counts what rolling back an import batch deleted
+type ImportRollback struct {
+	Transactions int64
+	Dividends    int64
+	Withholdings int64
+}
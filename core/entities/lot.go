@@ -0,0 +1,25 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// EntityNameLot contains the name of the entity
+const EntityNameLot = "lot"
+
+// Lot struct
+type Lot struct {
+	LotID             string          `bson:"_lotid,omitempty"`
+	PortfolioID       string          `bson:"portfolioid"`
+	TransactionID     string          `bson:"transactionid"`
+	InstrumentID      string          `bson:"instrumentid"`
+	InstrumentType    string          `bson:"instrument_type"`
+	AcquiredAt        time.Time       `bson:"acquired_at"`
+	Quantity          decimal.Decimal `bson:"quantity"`
+	RemainingQuantity decimal.Decimal `bson:"remaining_quantity"`
+	CostPerUnit       Money           `bson:"cost_per_unit"`
+	CreatedAt         time.Time       `bson:"created_at"`
+	UpdatedAt         time.Time       `bson:"updated_at"`
+}
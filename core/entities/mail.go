@@ -0,0 +1,9 @@
+package entities
+
+// Mail struct holds an email with a plain text and an HTML body
+type Mail struct {
+	To      string `bson:"to"`
+	Subject string `bson:"subject"`
+	Text    string `bson:"text"`
+	HTML    string `bson:"html"`
+}
@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Quote struct holds the latest price of a symbol reported by a market data provider
+type Quote struct {
+	Symbol   string    `bson:"symbol"`
+	Price    Money     `bson:"price"`
+	QuotedAt time.Time `bson:"quoted_at"`
+}
+
+// Bar struct holds the open, high, low and close prices and the traded volume of a symbol on a day
+type Bar struct {
+	Date     time.Time       `bson:"date"`
+	Open     decimal.Decimal `bson:"open"`
+	High     decimal.Decimal `bson:"high"`
+	Low      decimal.Decimal `bson:"low"`
+	Close    decimal.Decimal `bson:"close"`
+	Volume   decimal.Decimal `bson:"volume"`
+	Currency string          `bson:"currency"`
+}
+
+// DividendEvent struct holds a dividend per share announced for a symbol
+type DividendEvent struct {
+	ExDate  time.Time `bson:"ex_date"`
+	PayDate time.Time `bson:"pay_date"`
+	Amount  Money     `bson:"amount"`
+}
+
+// Split struct holds a stock split of a symbol, where Numerator new shares replace Denominator old ones
+type Split struct {
+	Date        time.Time       `bson:"date"`
+	Numerator   decimal.Decimal `bson:"numerator"`
+	Denominator decimal.Decimal `bson:"denominator"`
+}
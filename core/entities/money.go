@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"fmt"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+)
+
+// Money is an exact decimal amount in a currency identified by its ISO 4217 code
+type Money struct {
+	Amount   decimal.Decimal `bson:"amount" json:"amount"`
+	Currency string          `bson:"currency" json:"currency"`
+}
+
+// NewMoney creates a new amount of money
+func NewMoney(amount decimal.Decimal, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount.IsZero()
+}
+
+// Add returns the sum of two amounts of money. The zero value takes the currency of the other amount, and amounts in different
+// currencies cannot be added: they must be converted to a common currency first.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency == "" && m.Amount.IsZero() {
+		return o, nil
+	}
+	if o.Currency == "" && o.Amount.IsZero() {
+		return m, nil
+	}
+	if m.Currency != o.Currency {
+		return Money{}, wrappers.NewValidationErr(fmt.Errorf("cannot add %s to %s, amounts in different currencies must be converted to a common currency first", o, m))
+	}
+	return Money{Amount: m.Amount.Add(o.Amount), Currency: m.Currency}, nil
+}
+
+// Sub returns the difference of two amounts of money, following the same currency rules as Add
+func (m Money) Sub(o Money) (Money, error) {
+	return m.Add(Money{Amount: o.Amount.Neg(), Currency: o.Currency})
+}
+
+// Mul returns the amount multiplied by a quantity, in the same currency
+func (m Money) Mul(quantity decimal.Decimal) Money {
+	return Money{Amount: m.Amount.Mul(quantity), Currency: m.Currency}
+}
+
+// String returns the amount followed by its currency
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Amount.String()
+	}
+	return m.Amount.String() + " " + m.Currency
+}
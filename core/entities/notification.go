@@ -0,0 +1,13 @@
+package entities
+
+import (
+	"time"
+)
+
+// Notification struct holds a message to a user
+type Notification struct {
+	UserID    string    `bson:"userid"`
+	Subject   string    `bson:"subject"`
+	Message   string    `bson:"message"`
+	CreatedAt time.Time `bson:"created_at"`
+}
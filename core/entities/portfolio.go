@@ -7,6 +7,13 @@ import (
 // EntityNamePortfolio contains the name of the entity
 const EntityNamePortfolio = "portfolio"
 
+// Cost basis methods selected by Portfolio.PerformenceCalculation
+const (
+	CostBasisFIFO = iota + 1
+	CostBasisLIFO
+	CostBasisAverage
+)
+
 // User Portfolio
 type Portfolio struct {
 	PortfolioID            string    `bson:"_portfolioid,omitempty"`
@@ -19,6 +26,7 @@ type Portfolio struct {
 	Summary                bool      `bson:"summary"`
 	PriceAlert             bool      `bson:"price_alert"`
 	CompanyEventAlert      bool      `bson:"company_event_alert"`
+	BaseCurrency           string    `bson:"base_currency"`
 	CreatedAt              time.Time `bson:"created_at"`
 	UpdatedAt              time.Time `bson:"updated_at"`
 }
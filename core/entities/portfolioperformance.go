@@ -0,0 +1,121 @@
+package entities
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Types of the transactions of a Portfolio Performance file
+const (
+	PPTransactionBuy              = "BUY"
+	PPTransactionSell             = "SELL"
+	PPTransactionDeliveryInbound  = "DELIVERY_INBOUND"
+	PPTransactionDeliveryOutbound = "DELIVERY_OUTBOUND"
+	PPTransactionTransferIn       = "TRANSFER_IN"
+	PPTransactionTransferOut      = "TRANSFER_OUT"
+	PPTransactionDividends        = "DIVIDENDS"
+	PPTransactionDeposit          = "DEPOSIT"
+	PPTransactionRemoval          = "REMOVAL"
+	PPTransactionFees             = "FEES"
+	PPTransactionInterest         = "INTEREST"
+)
+
+// Quote feeds of the securities of a Portfolio Performance file. PPFeedCoinGecko is written for the cryptocurrencies and PPFeedManual
+// for the stocks.
+const (
+	PPFeedCoinGecko = "COINGECKO"
+	PPFeedManual    = "MANUAL"
+)
+
+// ppCryptoFeeds are the quote feeds of the cryptocurrencies of a Portfolio Performance file
+var ppCryptoFeeds = []string{PPFeedCoinGecko, "KRAKEN", "BINANCE", "BITFINEX", "COINBASE"}
+
+// PPClient struct holds the securities, deposit accounts and securities accounts of a Portfolio Performance file. References between
+// them are kept as the UUIDs of the referenced items.
+type PPClient struct {
+	Version      int
+	BaseCurrency string
+	Securities   []PPSecurity
+	Accounts     []PPAccount
+	Portfolios   []PPPortfolio
+}
+
+// PPSecurity struct is a security of a Portfolio Performance file with its price history. Feed is the quote feed of the security,
+// which tells cryptocurrencies apart.
+type PPSecurity struct {
+	UUID         string
+	Name         string
+	CurrencyCode string
+	ISIN         string
+	TickerSymbol string
+	WKN          string
+	Feed         string
+	Prices       []PPPrice
+	Retired      bool
+}
+
+// IsCrypto reports whether a security is a cryptocurrency, by its quote feed
+func (s PPSecurity) IsCrypto() bool {
+	for _, feed := range ppCryptoFeeds {
+		if strings.EqualFold(s.Feed, feed) {
+			return true
+		}
+	}
+	return false
+}
+
+// PPPrice struct is a daily quote of a security of a Portfolio Performance file
+type PPPrice struct {
+	Date  time.Time
+	Value decimal.Decimal
+}
+
+// PPAccount struct is a deposit account of a Portfolio Performance file
+type PPAccount struct {
+	UUID         string
+	Name         string
+	CurrencyCode string
+	Transactions []PPTransaction
+	Retired      bool
+}
+
+// PPPortfolio struct is a securities account of a Portfolio Performance file, whose trades are settled in its reference account
+type PPPortfolio struct {
+	UUID             string
+	Name             string
+	ReferenceAccount string
+	Transactions     []PPTransaction
+	Retired          bool
+}
+
+// PPTransaction struct is a transaction of a deposit or securities account of a Portfolio Performance file. Amount is the amount
+// settled, including the fees and taxes of buys and net of the ones of sells and dividends. GrossValue is the value of the shares in
+// the currency of the security when it differs from the one of the transaction. CrossEntry is the UUID of the other side of a buy or
+// sell, the transaction of the securities account for the one of the deposit account and the other way round.
+type PPTransaction struct {
+	UUID       string
+	Type       string
+	Date       time.Time
+	ExDate     time.Time
+	Security   string
+	Shares     decimal.Decimal
+	Amount     Money
+	Fees       Money
+	Taxes      Money
+	GrossValue Money
+	Note       string
+	CrossEntry string
+}
+
+// NewPPUUID returns a UUID derived from the parts, so the items written to a Portfolio Performance file keep their UUID from one export
+// to the next
+func NewPPUUID(parts ...string) string {
+	sum := sha1.Sum([]byte(strings.Join(parts, "\x00")))
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// EntityNamePrice contains the name of the entity
+const EntityNamePrice = "price"
+
+// Price struct holds the daily open, high, low and close prices and the traded volume of an instrument
+type Price struct {
+	PriceID        string          `bson:"_priceid,omitempty"`
+	InstrumentID   string          `bson:"instrumentid"`
+	InstrumentType string          `bson:"instrument_type"`
+	PriceDate      time.Time       `bson:"price_date"`
+	Open           decimal.Decimal `bson:"open"`
+	High           decimal.Decimal `bson:"high"`
+	Low            decimal.Decimal `bson:"low"`
+	Close          decimal.Decimal `bson:"close"`
+	Volume         decimal.Decimal `bson:"volume"`
+	Currency       string          `bson:"currency"`
+	Source         string          `bson:"source"`
+	CreatedAt      time.Time       `bson:"created_at"`
+	UpdatedAt      time.Time       `bson:"updated_at"`
+}
+
+// LastPrice struct holds the latest intraday price of an instrument
+type LastPrice struct {
+	InstrumentID   string    `bson:"instrumentid"`
+	InstrumentType string    `bson:"instrument_type"`
+	Price          Money     `bson:"price"`
+	PricedAt       time.Time `bson:"priced_at"`
+	Source         string    `bson:"source"`
+	UpdatedAt      time.Time `bson:"updated_at"`
+}
@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"time"
+)
+
+// EntityNamePriceRefreshRun contains the name of the entity
+const EntityNamePriceRefreshRun = "price_refresh_run"
+
+// PriceRefreshRun struct holds the outcome of a refresh of the last prices of the instruments in open positions
+type PriceRefreshRun struct {
+	PriceRefreshRunID string                `bson:"_price_refresh_runid,omitempty"`
+	Provider          string                `bson:"provider"`
+	StartedAt         time.Time             `bson:"started_at"`
+	FinishedAt        time.Time             `bson:"finished_at"`
+	DurationMs        int64                 `bson:"duration_ms"`
+	Instruments       int                   `bson:"instruments"`
+	Updated           int                   `bson:"updated"`
+	Failed            int                   `bson:"failed"`
+	Failures          []PriceRefreshFailure `bson:"failures"`
+}
+
+// PriceRefreshFailure struct holds why the last price of an instrument could not be refreshed
+type PriceRefreshFailure struct {
+	InstrumentID   string `bson:"instrumentid" json:"instrumentid"`
+	InstrumentType string `bson:"instrument_type" json:"instrument_type"`
+	Symbol         string `bson:"symbol" json:"symbol"`
+	Error          string `bson:"error" json:"error"`
+}
@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// EntityNameRealizedGain contains the name of the entity
+const EntityNameRealizedGain = "realized_gain"
+
+// RealizedGain struct
+type RealizedGain struct {
+	RealizedGainID    string          `bson:"_realized_gainid,omitempty"`
+	PortfolioID       string          `bson:"portfolioid"`
+	LotID             string          `bson:"lotid"`
+	BuyTransactionID  string          `bson:"buy_transactionid"`
+	SellTransactionID string          `bson:"sell_transactionid"`
+	InstrumentID      string          `bson:"instrumentid"`
+	InstrumentType    string          `bson:"instrument_type"`
+	Method            int             `bson:"method"`
+	Quantity          decimal.Decimal `bson:"quantity"`
+	AcquiredAt        time.Time       `bson:"acquired_at"`
+	SoldAt            time.Time       `bson:"sold_at"`
+	CostBasis         Money           `bson:"cost_basis"`
+	Proceeds          Money           `bson:"proceeds"`
+	Gain              Money           `bson:"gain"`
+	CreatedAt         time.Time       `bson:"created_at"`
+	UpdatedAt         time.Time       `bson:"updated_at"`
+}
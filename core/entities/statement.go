@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Kinds of the rows of a broker statement
+const (
+	StatementRowTrade          = "trade"
+	StatementRowDividend       = "dividend"
+	StatementRowWithholdingTax = "withholding_tax"
+	StatementRowFee            = "fee"
+	StatementRowDeposit        = "deposit"
+	StatementRowWithdrawal     = "withdrawal"
+	StatementRowInterest       = "interest"
+)
+
+// Statement struct holds the rows parsed from a statement exported by a broker together with the lines of the file that were skipped,
+// as they hold nothing to import, and the ones that could not be parsed. Positions are the ones the statement reports as held, for the
+// statements that list them.
+type Statement struct {
+	Rows      []StatementRow
+	Positions []StatementPosition
+	Skipped   []StatementIssue
+	Invalid   []StatementIssue
+}
+
+// StatementRow struct is a trade, dividend, fee or cash movement of a broker statement. The quantity of trades is negative for sales,
+// and their price is per unit. Dividends carry their gross amount and, when the broker reports it, their gross price per share and the
+// quantity they were paid on. Amounts of taxes, fees and cash movements are positive, their kind telling their direction.
+type StatementRow struct {
+	Line        int
+	Kind        string
+	Date        time.Time
+	Symbol      string
+	ISIN        string
+	Name        string
+	Quantity    decimal.Decimal
+	Price       Money
+	Amount      Money
+	Fee         Money
+	Reference   string
+	Description string
+}
+
+// StatementPosition struct is a position a broker statement reports as held on its date, which is negative for short positions. Price
+// is the last price the broker valued it at.
+type StatementPosition struct {
+	Line     int
+	Date     time.Time
+	Symbol   string
+	ISIN     string
+	Name     string
+	Quantity decimal.Decimal
+	Price    Money
+}
+
+// StatementIssue struct is a line of a broker statement that was not imported and the reason why
+type StatementIssue struct {
+	Line   int
+	Reason string
+}
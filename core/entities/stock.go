@@ -15,6 +15,7 @@ type Stock struct {
 	TickerSymbol string    `bson:"ticker_symbol"`
 	CompanyName  string    `bson:"company_name"`
 	Slug         string    `bson:"slug"`
+	Country      string    `bson:"country"`
 	CreatedAt    time.Time `bson:"created_at"`
 	UpdatedAt    time.Time `bson:"updated_at"`
 }
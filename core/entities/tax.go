@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Tax treatments of a sale
+const (
+	TaxTreatmentTaxable             = "taxable"
+	TaxTreatmentShortTerm           = "short_term"
+	TaxTreatmentLongTerm            = "long_term"
+	TaxTreatmentPrivateSale         = "private_sale"
+	TaxTreatmentTimeTestExempt      = "time_test_exempt"
+	TaxTreatmentHoldingPeriodExempt = "holding_period_exempt"
+	TaxTreatmentThresholdExempt     = "threshold_exempt"
+)
+
+// TaxSale struct holds a sale of a lot with its amounts in the currency of a tax rule, together with the treatment the rule gave to it.
+// Cost basis is converted at the rate of the purchase date and proceeds at the rate of the sale date.
+type TaxSale struct {
+	BuyTransactionID  string          `bson:"buy_transactionid"`
+	SellTransactionID string          `bson:"sell_transactionid"`
+	InstrumentID      string          `bson:"instrumentid"`
+	InstrumentType    string          `bson:"instrument_type"`
+	Quantity          decimal.Decimal `bson:"quantity"`
+	AcquiredAt        time.Time       `bson:"acquired_at"`
+	SoldAt            time.Time       `bson:"sold_at"`
+	HoldingDays       int             `bson:"holding_days"`
+	CostBasis         Money           `bson:"cost_basis"`
+	Proceeds          Money           `bson:"proceeds"`
+	Gain              Money           `bson:"gain"`
+	Treatment         string          `bson:"treatment"`
+	Rule              string          `bson:"rule"`
+	TaxableGain       Money           `bson:"taxable_gain"`
+}
+
+// TaxAssessment struct holds the sales of a financial year as assessed by a tax rule and the allowance it deducts from their taxable gains
+type TaxAssessment struct {
+	Sales     []TaxSale `bson:"sales"`
+	Allowance Money     `bson:"allowance"`
+}
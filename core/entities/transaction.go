@@ -2,19 +2,116 @@ package entities
 
 import (
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 // EntityNameTransaction contains the name of the entity
 const EntityNameTransaction = "transaction"
 
-// Transaction struct
+// Types of the transactions
+const (
+	TransactionTypeBuy         = "BUY"
+	TransactionTypeSell        = "SELL"
+	TransactionTypeDividend    = "DIVIDEND"
+	TransactionTypeFee         = "FEE"
+	TransactionTypeDeposit     = "DEPOSIT"
+	TransactionTypeWithdrawal  = "WITHDRAWAL"
+	TransactionTypeInterest    = "INTEREST"
+	TransactionTypeTransferIn  = "TRANSFER_IN"
+	TransactionTypeTransferOut = "TRANSFER_OUT"
+	TransactionTypeSplit       = "SPLIT"
+)
+
+// TransactionTypes are the types of the transactions in the order they are listed
+var TransactionTypes = []string{
+	TransactionTypeBuy,
+	TransactionTypeSell,
+	TransactionTypeDividend,
+	TransactionTypeFee,
+	TransactionTypeDeposit,
+	TransactionTypeWithdrawal,
+	TransactionTypeInterest,
+	TransactionTypeTransferIn,
+	TransactionTypeTransferOut,
+	TransactionTypeSplit,
+}
+
+// Transaction struct. Extid is the reference of the trade at the broker and ImportBatchID the import batch that created it, both
+// empty for transactions entered by hand.
+//
+// Buys, sells and transfers move shares of an instrument at a price per share, their quantity being negative for sells and transfers
+// out. Splits add the shares of their quantity to the ones held, or remove them for reverse splits, without a price. Dividends, fees,
+// deposits, withdrawals and interest move cash only: their quantity is zero and their price is their amount, which is positive. Their
+// instrument is the one they were paid or charged for, if any.
+//
+// Adjustments are not stored: they are the corporate actions and split transactions applied to a transaction read adjusted, oldest
+// first.
 type Transaction struct {
-	TransactionID    string    `bson:"transactionid,omitempty"`
-	StockID          string    `bson:"stockid"`
-	CryptocurrencyID string    `bson:"cryptocurrencyid"`
-	Quantity         string    `bson:"quantity"`
-	TransactionPrice string    `bson:"transaction_price"`
-	TransactionDate  time.Time `bson:"transaction_date"`
-	CreatedAt        time.Time `bson:"created_at"`
-	UpdatedAt        time.Time `bson:"updated_at"`
+	TransactionID    string                  `bson:"transactionid,omitempty"`
+	HoldingID        string                  `bson:"holdingid"`
+	StockID          string                  `bson:"stockid"`
+	CryptocurrencyID string                  `bson:"cryptocurrencyid"`
+	TransactionType  string                  `bson:"transaction_type"`
+	Quantity         decimal.Decimal         `bson:"quantity"`
+	TransactionPrice Money                   `bson:"transaction_price"`
+	TransactionDate  time.Time               `bson:"transaction_date"`
+	Extid            string                  `bson:"extid"`
+	ImportBatchID    string                  `bson:"import_batchid"`
+	CreatedAt        time.Time               `bson:"created_at"`
+	UpdatedAt        time.Time               `bson:"updated_at"`
+	Adjustments      []TransactionAdjustment `bson:"-"`
+}
+
+// TransactionAdjustment struct holds the day an adjustment of a transaction took effect, and the stock and quantity of the
+// transaction before it
+type TransactionAdjustment struct {
+	EffectiveDate time.Time
+	StockID       string
+	Quantity      decimal.Decimal
+}
+
+// Type returns the type of the transaction, which is a buy or a sell by the sign of its quantity for the transactions stored before
+// they had a type
+func (t Transaction) Type() string {
+	if t.TransactionType != "" {
+		return t.TransactionType
+	}
+	if t.Quantity.IsNegative() {
+		return TransactionTypeSell
+	}
+	return TransactionTypeBuy
+}
+
+// MovesShares reports whether the transaction changes the shares held of its instrument, as buys, sells, transfers and splits do
+func (t Transaction) MovesShares() bool {
+	switch t.Type() {
+	case TransactionTypeBuy, TransactionTypeSell, TransactionTypeTransferIn, TransactionTypeTransferOut, TransactionTypeSplit:
+		return true
+	}
+	return false
+}
+
+// CashFlow returns the cash the transaction moves into its holding, negative when it moves cash out. Buys pay their cost and sells
+// receive their proceeds, while transfers and splits move no cash.
+func (t Transaction) CashFlow() Money {
+	switch t.Type() {
+	case TransactionTypeBuy, TransactionTypeSell:
+		return t.TransactionPrice.Mul(t.Quantity.Neg())
+	case TransactionTypeDividend, TransactionTypeDeposit, TransactionTypeInterest:
+		return t.TransactionPrice
+	case TransactionTypeFee, TransactionTypeWithdrawal:
+		return NewMoney(t.TransactionPrice.Amount.Neg(), t.TransactionPrice.Currency)
+	}
+	return NewMoney(decimal.Zero, t.TransactionPrice.Currency)
+}
+
+// SharesOn returns the stock and quantity of a transaction as they were on a day, before the adjustments that took effect after it
+func (t Transaction) SharesOn(day time.Time) (string, decimal.Decimal) {
+	for _, a := range t.Adjustments {
+		if a.EffectiveDate.After(day) {
+			return a.StockID, a.Quantity
+		}
+	}
+	return t.StockID, t.Quantity
 }
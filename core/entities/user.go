@@ -14,6 +14,7 @@ type User struct {
 	Lastname     string    `bson:"lastname"`
 	Email        string    `bson:"email"`
 	PasswordHash string    `bson:"password_hash"`
+	BaseCurrency string    `bson:"base_currency"`
 	CreatedAt    time.Time `bson:"created_at"`
 	UpdatedAt    time.Time `bson:"updated_at"`
 }
@@ -0,0 +1,145 @@
+package importers
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// degiroDateLayouts are the date layouts of the Degiro statements
+var degiroDateLayouts = []string{"02-01-2006", "2006-01-02"}
+
+// degiroParser adapter of the parser of the Degiro statements
+type degiroParser struct{}
+
+// NewDegiroParser creates the parser of the Degiro Transactions.csv and Account.csv exports. Trades are read from the transactions
+// export, where the currency of each amount is in the column after it, and dividends, withholding taxes, fees other than the ones of
+// the trades and cash movements from the account statement, where the currency of each change is in the column before its amount.
+func NewDegiroParser() ports.StatementParser {
+	return &degiroParser{}
+}
+
+func (p *degiroParser) Broker() string {
+	return "degiro"
+}
+
+func (p *degiroParser) Parse(file io.Reader) (statement entities.Statement, err error) {
+	err = readCSV(file, "Degiro", func(r csvRecord) error {
+		var row entities.StatementRow
+		var ok bool
+		var parseErr error
+		switch {
+		case r.has("quantity", "price"):
+			row, parseErr = p.trade(r)
+			ok = true
+		case r.has("description", "change"):
+			row, ok, parseErr = p.movement(r)
+		default:
+			return wrappers.NewValidationErr(fmt.Errorf("invalid Degiro statement: the header must be the one of the Transactions.csv or Account.csv exports"))
+		}
+
+		switch {
+		case parseErr != nil:
+			statement.Invalid = append(statement.Invalid, invalid(r.line, parseErr))
+		case !ok:
+			statement.Skipped = append(statement.Skipped, skipped(r.line, "%q is not a dividend, tax, fee or cash movement", r.field("description")))
+		default:
+			statement.Rows = append(statement.Rows, row)
+		}
+		return nil
+	})
+	return
+}
+
+// trade parses a line of the transactions export
+func (p *degiroParser) trade(r csvRecord) (row entities.StatementRow, err error) {
+	row = entities.StatementRow{Line: r.line, Kind: entities.StatementRowTrade, Name: r.field("product"), ISIN: r.field("isin"), Reference: r.field("order id")}
+	if row.Date, err = parseDate(r.field("date"), degiroDateLayouts...); err != nil {
+		return
+	}
+	if row.ISIN == "" {
+		err = fmt.Errorf("ISIN cannot be empty")
+		return
+	}
+	if row.Quantity, err = parseNumber(r.field("quantity"), true); err != nil {
+		return
+	}
+	if row.Quantity.IsZero() {
+		err = fmt.Errorf("quantity cannot be zero")
+		return
+	}
+
+	price, err := parseNumber(r.field("price"), true)
+	if err != nil {
+		return
+	}
+	row.Price = entities.NewMoney(price, currency(r.next("price")))
+
+	feeColumn := "transaction and/or third party fees"
+	if !r.has(feeColumn) {
+		feeColumn = "transaction costs"
+	}
+	fee, err := parseNumber(r.field(feeColumn), true)
+	if err != nil {
+		return
+	}
+	if !fee.IsZero() {
+		row.Fee = entities.NewMoney(fee.Abs(), currency(r.next(feeColumn)))
+	}
+	return
+}
+
+// movement parses a line of the account statement, reporting false for the lines that are not dividends, taxes, fees or cash
+// movements, such as the trades and their fees, which are read from the transactions export, and the currency conversions
+func (p *degiroParser) movement(r csvRecord) (row entities.StatementRow, ok bool, err error) {
+	description := strings.ToLower(r.field("description"))
+	var kind string
+	switch {
+	case strings.Contains(description, "dividend") && strings.Contains(description, "tax"):
+		kind = entities.StatementRowWithholdingTax
+	case strings.Contains(description, "dividend"):
+		kind = entities.StatementRowDividend
+	case strings.Contains(description, "deposit"):
+		kind = entities.StatementRowDeposit
+	case strings.Contains(description, "withdrawal"):
+		kind = entities.StatementRowWithdrawal
+	case strings.Contains(description, "interest"):
+		kind = entities.StatementRowInterest
+	case strings.Contains(description, "fee") && !strings.Contains(description, "transaction"):
+		kind = entities.StatementRowFee
+	default:
+		return
+	}
+
+	date, err := parseDate(r.field("date"), degiroDateLayouts...)
+	if err != nil {
+		return
+	}
+	amount, err := parseNumber(r.next("change"), true)
+	if err != nil {
+		return
+	}
+	if kind == entities.StatementRowInterest && amount.IsNegative() {
+		kind = entities.StatementRowFee
+	}
+
+	row = cashRow(r.line, kind, date, amount, r.field("change"))
+	row.Name = r.field("product")
+	row.ISIN = r.field("isin")
+	row.Reference = r.field("order id")
+	row.Description = r.field("description")
+	if kind == entities.StatementRowDividend && row.ISIN == "" {
+		err = fmt.Errorf("ISIN cannot be empty")
+		return
+	}
+	if row.Amount.IsZero() {
+		err = fmt.Errorf("amount cannot be zero")
+		return
+	}
+	ok = true
+	return
+}
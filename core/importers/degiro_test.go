@@ -0,0 +1,83 @@
+package importers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestDegiroParse_Transactions checks that Parse reads the trades of the transactions export with their fees
+func TestDegiroParse_Transactions(t *testing.T) {
+	// Arrange
+	file := strings.NewReader("Date,Time,Product,ISIN,Reference exchange,Venue,Quantity,Price,,Local value,,Value,,Exchange rate,Transaction and/or third party fees,,Total,,Order ID\n" +
+		"05-03-2024,15:31,APPLE INC. - COMMON ST,US0378331005,NDQ,XNAS,10,\"170,1200\",USD,\"-1701,20\",USD,\"-1566,04\",EUR,\"1,0862\",\"-1,00\",EUR,\"-1567,04\",EUR,a1b2\n" +
+		"12-04-2024,09:05,APPLE INC. - COMMON ST,US0378331005,NDQ,XNAS,-4,\"175,00\",USD,\"700,00\",USD,\"644,00\",EUR,\"1,0870\",,,\"644,00\",EUR,c3d4\n" +
+		"13-04-2024,09:05,BROKEN,,NDQ,XNAS,1,\"1,00\",USD,,,,,,,,,,e5f6\n")
+
+	// Act
+	statement, err := NewDegiroParser().Parse(file)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, statement.Rows, 2)
+	buy := statement.Rows[0]
+	assert.Equal(t, entities.StatementRowTrade, buy.Kind)
+	assert.Equal(t, 2, buy.Line)
+	assert.Equal(t, time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), buy.Date)
+	assert.Equal(t, "US0378331005", buy.ISIN)
+	assert.Equal(t, "APPLE INC. - COMMON ST", buy.Name)
+	assert.Equal(t, "10", buy.Quantity.String())
+	assert.Equal(t, "170.12 USD", buy.Price.String())
+	assert.Equal(t, "1 EUR", buy.Fee.String())
+	assert.Equal(t, "a1b2", buy.Reference)
+	assert.True(t, statement.Rows[1].Quantity.Equal(decimal.NewFromInt(-4)))
+	assert.True(t, statement.Rows[1].Fee.IsZero())
+	assert.Equal(t, []entities.StatementIssue{{Line: 4, Reason: "ISIN cannot be empty"}}, statement.Invalid)
+}
+
+// TestDegiroParse_Account checks that Parse reads the dividends, taxes, fees and cash movements of the account statement and skips the rest
+func TestDegiroParse_Account(t *testing.T) {
+	// Arrange
+	file := strings.NewReader("Date,Time,Value date,Product,ISIN,Description,FX,Change,,Balance,,Order Id\n" +
+		"15-05-2024,07:34,14-05-2024,APPLE INC. - COMMON ST,US0378331005,Dividend,,USD,\"2,40\",USD,\"2,40\",\n" +
+		"15-05-2024,07:34,14-05-2024,APPLE INC. - COMMON ST,US0378331005,Dividend Tax,,USD,\"-0,36\",USD,\"2,04\",\n" +
+		"01-03-2024,10:00,01-03-2024,,,iDEAL Deposit,,EUR,\"2000,00\",EUR,\"2000,00\",\n" +
+		"05-03-2024,15:31,05-03-2024,APPLE INC. - COMMON ST,US0378331005,DEGIRO Transaction and/or third party fees,,EUR,\"-1,00\",EUR,\"432,96\",a1b2\n" +
+		"02-01-2024,10:00,31-12-2023,,,DEGIRO Exchange Connection Fee 2024 (Nasdaq - NDQ),,EUR,\"-2,50\",EUR,\"430,46\",\n" +
+		"05-03-2024,15:31,05-03-2024,,,FX Debit,\"1,0862\",EUR,\"-1566,04\",EUR,\"433,96\",a1b2\n")
+
+	// Act
+	statement, err := NewDegiroParser().Parse(file)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Empty(t, statement.Invalid)
+	kinds := []string{}
+	for _, row := range statement.Rows {
+		kinds = append(kinds, row.Kind)
+	}
+	assert.Equal(t, []string{entities.StatementRowDividend, entities.StatementRowWithholdingTax, entities.StatementRowDeposit, entities.StatementRowFee}, kinds)
+	assert.Equal(t, "2.4 USD", statement.Rows[0].Amount.String())
+	assert.Equal(t, time.Date(2024, time.May, 15, 0, 0, 0, 0, time.UTC), statement.Rows[0].Date)
+	assert.Equal(t, "0.36 USD", statement.Rows[1].Amount.String())
+	assert.Equal(t, "2000 EUR", statement.Rows[2].Amount.String())
+	assert.Equal(t, "2.5 EUR", statement.Rows[3].Amount.String())
+	assert.Equal(t, []int{5, 7}, []int{statement.Skipped[0].Line, statement.Skipped[1].Line})
+}
+
+// TestDegiroParse_UnknownHeader checks that Parse returns an error for files that are not Degiro exports
+func TestDegiroParse_UnknownHeader(t *testing.T) {
+	// Arrange
+	file := strings.NewReader("Symbol,Amount\nAAPL,10\n")
+
+	// Act
+	_, err := NewDegiroParser().Parse(file)
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
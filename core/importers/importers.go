@@ -0,0 +1,238 @@
+// Package importers holds the parsers of the statements exported by the brokers. Each parser is registered by the slug of its broker
+// and maps the rows of the statement into trades, dividends, fees and cash movements, and the positions it lists when it has them.
+// It also holds the reader and writer of the XML files of Portfolio Performance, which the portfolio export and restore use.
+package importers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]ports.StatementParser{}
+)
+
+func init() {
+	Register(NewDegiroParser())
+	Register(NewInteractiveBrokersParser())
+	Register(NewTrading212Parser())
+	Register(NewRevolutParser())
+	Register(NewSharesightParser())
+	Register(NewOFXParser())
+}
+
+// Register makes a statement parser selectable by the slug of its broker, replacing any parser registered for the same broker
+func Register(parser ports.StatementParser) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[strings.ToLower(parser.Broker())] = parser
+}
+
+// Brokers returns the sorted slugs of the brokers with a registered statement parser
+func Brokers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	brokers := make([]string, 0, len(registry))
+	for broker := range registry {
+		brokers = append(brokers, broker)
+	}
+	sort.Strings(brokers)
+	return brokers
+}
+
+// Parsers returns the registered statement parsers by broker slug
+func Parsers() map[string]ports.StatementParser {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	parsers := make(map[string]ports.StatementParser, len(registry))
+	for broker, parser := range registry {
+		parsers[broker] = parser
+	}
+	return parsers
+}
+
+// csvRecord is a line of a CSV statement with the columns of its header
+type csvRecord struct {
+	line    int
+	values  []string
+	columns map[string]int
+}
+
+// field returns the trimmed value of the first of the named columns the record has
+func (r csvRecord) field(names ...string) string {
+	for _, name := range names {
+		if i, ok := r.columns[name]; ok && i < len(r.values) {
+			return strings.TrimSpace(r.values[i])
+		}
+	}
+	return ""
+}
+
+// next returns the trimmed value of the column after the named one, where some brokers put the currency of an amount
+func (r csvRecord) next(name string) string {
+	if i, ok := r.columns[name]; ok && i+1 < len(r.values) {
+		return strings.TrimSpace(r.values[i+1])
+	}
+	return ""
+}
+
+// has reports whether the header of the record has all the named columns
+func (r csvRecord) has(names ...string) bool {
+	for _, name := range names {
+		if _, ok := r.columns[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// csvReader returns a reader of a CSV statement accepting lines of any length
+func csvReader(file io.Reader) *csv.Reader {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.LazyQuotes = true
+	return reader
+}
+
+// readCSV reads a CSV statement with a header on its first line, calling read with every other non-empty line
+func readCSV(file io.Reader, broker string, read func(r csvRecord) error) error {
+	reader := csvReader(file)
+	header, err := reader.Read()
+	if err != nil {
+		return wrappers.NewValidationErr(fmt.Errorf("invalid %s statement: %w", broker, err))
+	}
+	columns := csvColumns(header)
+
+	for line := 2; ; line++ {
+		values, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return wrappers.NewValidationErr(fmt.Errorf("invalid %s statement: %w", broker, err))
+		}
+		if blank(values) {
+			continue
+		}
+		if err := read(csvRecord{line: line, values: values, columns: columns}); err != nil {
+			return err
+		}
+	}
+}
+
+// csvColumns indexes the columns of a header by their lower case name, keeping the first of the columns with the same name
+func csvColumns(header []string) map[string]int {
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")))
+		if _, ok := columns[name]; name != "" && !ok {
+			columns[name] = i
+		}
+	}
+	return columns
+}
+
+// blank reports whether all the values of a line are empty
+func blank(values []string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseNumber parses a number written with thousands separators, a currency code or symbol and, for the brokers writing them so,
+// a decimal comma. Empty values are zero.
+func parseNumber(value string, decimalComma bool) (decimal.Decimal, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9', r == '.', r == ',', r == '-':
+			return r
+		case r == '+' || r == ' ' || r == '\u00a0' || r == '\'' || (r >= 'A' && r <= 'Z') || strings.ContainsRune("$€£¥", r):
+			return -1
+		default:
+			return 'x'
+		}
+	}, strings.TrimSpace(value))
+	if cleaned == "" {
+		return decimal.Zero, nil
+	}
+
+	decimalSeparator := "."
+	if decimalComma {
+		decimalSeparator = ","
+	}
+	if strings.Contains(cleaned, ",") && strings.Contains(cleaned, ".") {
+		decimalSeparator = ","
+		if strings.LastIndex(cleaned, ".") > strings.LastIndex(cleaned, ",") {
+			decimalSeparator = "."
+		}
+	}
+	if decimalSeparator == "," {
+		cleaned = strings.ReplaceAll(strings.ReplaceAll(cleaned, ".", ""), ",", ".")
+	} else {
+		cleaned = strings.ReplaceAll(cleaned, ",", "")
+	}
+
+	number, err := decimal.NewFromString(cleaned)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid number %q", value)
+	}
+	return number, nil
+}
+
+// parseDate parses the day of a date written in any of the layouts, ignoring the time written after it
+func parseDate(value string, layouts ...string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range layouts {
+		if len(value) < len(layout) {
+			continue
+		}
+		if d, err := time.Parse(layout, value[:len(layout)]); err == nil {
+			return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// currency returns a currency code in upper case
+func currency(value string) string {
+	return strings.ToUpper(strings.TrimSpace(value))
+}
+
+// cashRow returns a dividend, tax, fee or cash movement row of an amount in a currency
+func cashRow(line int, kind string, date time.Time, amount decimal.Decimal, code string) entities.StatementRow {
+	return entities.StatementRow{
+		Line:   line,
+		Kind:   kind,
+		Date:   date,
+		Amount: entities.NewMoney(amount.Abs(), currency(code)),
+	}
+}
+
+// invalid returns the issue of a line that could not be parsed
+func invalid(line int, err error) entities.StatementIssue {
+	return entities.StatementIssue{Line: line, Reason: err.Error()}
+}
+
+// skipped returns the issue of a line holding nothing to import
+func skipped(line int, format string, args ...interface{}) entities.StatementIssue {
+	return entities.StatementIssue{Line: line, Reason: fmt.Sprintf(format, args...)}
+}
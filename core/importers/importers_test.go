@@ -0,0 +1,76 @@
+package importers
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestParsers_Ok checks that the parsers of the brokers and of the Sharesight and OFX formats are registered by their slug
+func TestParsers_Ok(t *testing.T) {
+	// Act
+	parsers := Parsers()
+
+	// Assert
+	assert.Equal(t, []string{"degiro", "interactive-brokers", "ofx", "revolut", "sharesight", "trading212"}, Brokers())
+	assert.Len(t, parsers, 6)
+	for broker, parser := range parsers {
+		assert.Equal(t, broker, parser.Broker())
+	}
+}
+
+// TestRegister_Ok checks that a registered parser is returned for its broker in lower case
+func TestRegister_Ok(t *testing.T) {
+	// Arrange
+	parserMock := mocks.NewStatementParser(t)
+	parserMock.On("Broker").Return("XTB")
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "xtb")
+		registryMu.Unlock()
+	}()
+
+	// Act
+	Register(parserMock)
+
+	// Assert
+	assert.Equal(t, parserMock, Parsers()["xtb"])
+}
+
+// TestParseNumber_Ok checks that parseNumber reads numbers with currencies, thousands separators and decimal commas
+func TestParseNumber_Ok(t *testing.T) {
+	// Arrange
+	cases := []struct {
+		value        string
+		decimalComma bool
+		expected     string
+	}{
+		{"", false, "0"},
+		{"1,234.56", false, "1234.56"},
+		{"USD 12.50", false, "12.5"},
+		{"-$5.00", false, "-5"},
+		{"€1 234,5", true, "1234.5"},
+		{"-118,48", true, "-118.48"},
+		{"1.234,56", false, "1234.56"},
+	}
+
+	for _, c := range cases {
+		// Act
+		number, err := parseNumber(c.value, c.decimalComma)
+
+		// Assert
+		assert.Nil(t, err, c.value)
+		assert.True(t, decimal.RequireFromString(c.expected).Equal(number), c.value)
+	}
+}
+
+// TestParseNumber_Invalid checks that parseNumber returns an error for values that are not numbers
+func TestParseNumber_Invalid(t *testing.T) {
+	// Act
+	_, err := parseNumber("n/a", false)
+
+	// Assert
+	assert.Equal(t, `invalid number "n/a"`, err.Error())
+}
@@ -0,0 +1,208 @@
+package importers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// ibkrDateLayouts are the date layouts of the Interactive Brokers Flex statements, which write the time after a semicolon or a comma
+var ibkrDateLayouts = []string{"2006-01-02", "20060102", "01/02/2006"}
+
+// ibkrMarkers are the first values of the lines opening and closing the files, accounts and sections of the Flex statements
+var ibkrMarkers = map[string]bool{"BOF": true, "EOF": true, "BOA": true, "EOA": true, "BOS": true, "EOS": true}
+
+// interactiveBrokersParser adapter of the parser of the Interactive Brokers Flex statements
+type interactiveBrokersParser struct{}
+
+// NewInteractiveBrokersParser creates the parser of the Interactive Brokers Flex Query CSV statements with the Trades and the Cash
+// Transactions sections. Every section starts with its own header, with or without the HEADER and DATA markers of the statements with
+// section codes. Only the trades of stocks are read, and the summary lines of the trades are skipped.
+func NewInteractiveBrokersParser() ports.StatementParser {
+	return &interactiveBrokersParser{}
+}
+
+func (p *interactiveBrokersParser) Broker() string {
+	return "interactive-brokers"
+}
+
+func (p *interactiveBrokersParser) Parse(file io.Reader) (statement entities.Statement, err error) {
+	reader := csvReader(file)
+
+	var columns map[string]int
+	for line := 1; ; line++ {
+		values, readErr := reader.Read()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			err = wrappers.NewValidationErr(fmt.Errorf("invalid Interactive Brokers statement: %w", readErr))
+			return
+		}
+		if blank(values) || ibkrMarkers[strings.ToUpper(strings.TrimSpace(values[0]))] {
+			continue
+		}
+
+		switch strings.ToUpper(strings.TrimSpace(values[0])) {
+		case "HEADER":
+			columns = csvColumns(ibkrSectionValues(values))
+			continue
+		case "DATA":
+			values = ibkrSectionValues(values)
+		default:
+			if header := csvColumns(values); ibkrHeader(header) {
+				columns = header
+				continue
+			}
+		}
+		if columns == nil {
+			err = wrappers.NewValidationErr(fmt.Errorf("invalid Interactive Brokers statement: line %d comes before the header of its section", line))
+			return
+		}
+
+		r := csvRecord{line: line, values: values, columns: columns}
+		var row entities.StatementRow
+		var reason string
+		var parseErr error
+		if r.has("tradeprice") {
+			row, reason, parseErr = p.trade(r)
+		} else {
+			row, reason, parseErr = p.cashTransaction(r)
+		}
+
+		switch {
+		case parseErr != nil:
+			statement.Invalid = append(statement.Invalid, invalid(line, parseErr))
+		case reason != "":
+			statement.Skipped = append(statement.Skipped, skipped(line, "%s", reason))
+		default:
+			statement.Rows = append(statement.Rows, row)
+		}
+	}
+
+	if columns == nil {
+		err = wrappers.NewValidationErr(fmt.Errorf("invalid Interactive Brokers statement: no Trades or Cash Transactions section found"))
+	}
+	return
+}
+
+// ibkrHeader reports whether the columns are the header of a Trades or a Cash Transactions section
+func ibkrHeader(columns map[string]int) bool {
+	_, trades := columns["tradeprice"]
+	_, amount := columns["amount"]
+	_, kind := columns["type"]
+	return trades || amount && kind
+}
+
+// ibkrSectionValues returns the values of a line of a statement with section codes, after its marker and the code of its section
+func ibkrSectionValues(values []string) []string {
+	if len(values) < 2 {
+		return nil
+	}
+	return values[2:]
+}
+
+// trade parses a line of the Trades section, returning the reason to skip the lines of other asset classes and the summary lines
+func (p *interactiveBrokersParser) trade(r csvRecord) (row entities.StatementRow, reason string, err error) {
+	if assetClass := strings.ToUpper(r.field("assetclass")); assetClass != "" && assetClass != "STK" {
+		reason = fmt.Sprintf("asset class %s not supported", assetClass)
+		return
+	}
+	if level := strings.ToUpper(r.field("levelofdetail")); level != "" && level != "EXECUTION" && level != "ORDER" {
+		reason = fmt.Sprintf("%s summary line", strings.ToLower(level))
+		return
+	}
+
+	row = entities.StatementRow{Line: r.line, Kind: entities.StatementRowTrade, Symbol: r.field("symbol"), ISIN: r.field("isin"), Name: r.field("description"), Reference: r.field("transactionid", "tradeid", "ibexecid")}
+	if row.Date, err = parseDate(r.field("tradedate", "date/time"), ibkrDateLayouts...); err != nil {
+		return
+	}
+	if row.Symbol == "" && row.ISIN == "" {
+		err = fmt.Errorf("symbol or ISIN required")
+		return
+	}
+	if row.Quantity, err = parseNumber(r.field("quantity"), false); err != nil {
+		return
+	}
+	if row.Quantity.IsZero() {
+		err = fmt.Errorf("quantity cannot be zero")
+		return
+	}
+	if side := strings.ToUpper(r.field("buy/sell")); side == "SELL" && row.Quantity.IsPositive() {
+		row.Quantity = row.Quantity.Neg()
+	}
+
+	price, err := parseNumber(r.field("tradeprice"), false)
+	if err != nil {
+		return
+	}
+	code := currency(r.field("currencyprimary", "currency"))
+	row.Price = entities.NewMoney(price, code)
+
+	commission, err := parseNumber(r.field("ibcommission", "commission"), false)
+	if err != nil {
+		return
+	}
+	if !commission.IsZero() {
+		commissionCurrency := currency(r.field("ibcommissioncurrency", "commissioncurrency"))
+		if commissionCurrency == "" {
+			commissionCurrency = code
+		}
+		row.Fee = entities.NewMoney(commission.Abs(), commissionCurrency)
+	}
+	return
+}
+
+// cashTransaction parses a line of the Cash Transactions section, returning the reason to skip the lines of other types
+func (p *interactiveBrokersParser) cashTransaction(r csvRecord) (row entities.StatementRow, reason string, err error) {
+	amount, err := parseNumber(r.field("amount"), false)
+	if err != nil {
+		return
+	}
+
+	var kind string
+	switch cashType := strings.ToLower(r.field("type")); {
+	case strings.Contains(cashType, "withholding"):
+		kind = entities.StatementRowWithholdingTax
+	case strings.Contains(cashType, "dividend"):
+		kind = entities.StatementRowDividend
+	case strings.Contains(cashType, "deposit") || strings.Contains(cashType, "withdrawal"):
+		kind = entities.StatementRowDeposit
+		if amount.IsNegative() {
+			kind = entities.StatementRowWithdrawal
+		}
+	case strings.Contains(cashType, "interest"):
+		kind = entities.StatementRowInterest
+		if amount.IsNegative() {
+			kind = entities.StatementRowFee
+		}
+	case strings.Contains(cashType, "fee") || strings.Contains(cashType, "commission"):
+		kind = entities.StatementRowFee
+	default:
+		reason = fmt.Sprintf("cash transaction type %q not supported", r.field("type"))
+		return
+	}
+
+	date, err := parseDate(r.field("settledate", "date/time", "reportdate", "date"), ibkrDateLayouts...)
+	if err != nil {
+		return
+	}
+	row = cashRow(r.line, kind, date, amount, r.field("currencyprimary", "currency"))
+	row.Symbol = r.field("symbol")
+	row.ISIN = r.field("isin")
+	row.Description = r.field("description")
+	row.Reference = r.field("transactionid")
+	if kind == entities.StatementRowDividend && row.Symbol == "" && row.ISIN == "" {
+		err = fmt.Errorf("symbol or ISIN required")
+		return
+	}
+	if row.Amount.IsZero() {
+		err = fmt.Errorf("amount cannot be zero")
+	}
+	return
+}
@@ -0,0 +1,97 @@
+package importers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestInteractiveBrokersParse_Sections checks that Parse reads the trades of stocks and the cash transactions of the sections of a Flex statement
+func TestInteractiveBrokersParse_Sections(t *testing.T) {
+	// Arrange
+	file := strings.NewReader(`"ClientAccountID","CurrencyPrimary","AssetClass","Symbol","Description","ISIN","TradeDate","Quantity","TradePrice","IBCommission","IBCommissionCurrency","Buy/Sell","LevelOfDetail","TransactionID"
+"U1234567","USD","STK","MSFT","MICROSOFT CORP","US5949181045","20240305","5","402.5","-1","USD","BUY","EXECUTION","1001"
+"U1234567","USD","STK","MSFT","MICROSOFT CORP","US5949181045","20240410","2","420","-1","USD","SELL","EXECUTION","1002"
+"U1234567","USD","OPT","MSFT 240621C00450000","MSFT CALL","","20240411","1","3.2","-0.65","USD","BUY","EXECUTION","1003"
+"U1234567","USD","STK","MSFT","MICROSOFT CORP","US5949181045","20240305","5","402.5","-1","USD","BUY","CLOSED_LOT","1004"
+"ClientAccountID","CurrencyPrimary","Symbol","ISIN","Description","SettleDate","Amount","Type","TransactionID"
+"U1234567","USD","MSFT","US5949181045","MSFT(US5949181045) CASH DIVIDEND USD 0.75 PER SHARE","20240613","2.25","Dividends","2001"
+"U1234567","USD","MSFT","US5949181045","MSFT(US5949181045) CASH DIVIDEND - US TAX","20240613","-0.34","Withholding Tax","2002"
+"U1234567","EUR","","","CASH RECEIPTS","20240301","5000","Deposits/Withdrawals","2003"
+"U1234567","USD","","","USD CREDIT INT FOR MAR-2024","20240403","1.12","Broker Interest Received","2004"
+"U1234567","USD","","","BALANCE OF MONTHLY MINIMUM FEE","20240403","-3","Other Fees","2005"
+"U1234567","USD","","","TRANSFER","20240403","1","Price Adjustments","2006"
+`)
+
+	// Act
+	statement, err := NewInteractiveBrokersParser().Parse(file)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Empty(t, statement.Invalid)
+	assert.Len(t, statement.Rows, 7)
+	buy, sell := statement.Rows[0], statement.Rows[1]
+	assert.Equal(t, entities.StatementRowTrade, buy.Kind)
+	assert.Equal(t, "MSFT", buy.Symbol)
+	assert.Equal(t, time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), buy.Date)
+	assert.Equal(t, "402.5 USD", buy.Price.String())
+	assert.Equal(t, "1 USD", buy.Fee.String())
+	assert.Equal(t, "1001", buy.Reference)
+	assert.Equal(t, "-2", sell.Quantity.String())
+
+	kinds := []string{}
+	for _, row := range statement.Rows[2:] {
+		kinds = append(kinds, row.Kind)
+	}
+	assert.Equal(t, []string{entities.StatementRowDividend, entities.StatementRowWithholdingTax, entities.StatementRowDeposit, entities.StatementRowInterest, entities.StatementRowFee}, kinds)
+	assert.Equal(t, "2.25 USD", statement.Rows[2].Amount.String())
+	assert.Equal(t, "0.34 USD", statement.Rows[3].Amount.String())
+
+	assert.Equal(t, []entities.StatementIssue{
+		{Line: 4, Reason: "asset class OPT not supported"},
+		{Line: 5, Reason: "closed_lot summary line"},
+		{Line: 12, Reason: `cash transaction type "Price Adjustments" not supported`},
+	}, statement.Skipped)
+}
+
+// TestInteractiveBrokersParse_SectionCodes checks that Parse reads the statements with HEADER and DATA markers
+func TestInteractiveBrokersParse_SectionCodes(t *testing.T) {
+	// Arrange
+	file := strings.NewReader(`"BOF","U1234567","Trades","1","20240101","20241231","20250102;083000","1"
+"BOA","U1234567"
+"BOS","TRNT","Trades; trade date basis"
+"HEADER","TRNT","CurrencyPrimary","Symbol","TradeDate","Quantity","TradePrice"
+"DATA","TRNT","EUR","SAP","2024-05-02;10:15:00","3","172.4"
+"EOS","TRNT","1"
+"EOA","U1234567"
+"EOF","U1234567"
+`)
+
+	// Act
+	statement, err := NewInteractiveBrokersParser().Parse(file)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, statement.Rows, 1)
+	assert.Equal(t, 5, statement.Rows[0].Line)
+	assert.Equal(t, "SAP", statement.Rows[0].Symbol)
+	assert.Equal(t, time.Date(2024, time.May, 2, 0, 0, 0, 0, time.UTC), statement.Rows[0].Date)
+	assert.Equal(t, "172.4 EUR", statement.Rows[0].Price.String())
+	assert.Empty(t, statement.Skipped)
+}
+
+// TestInteractiveBrokersParse_NoSection checks that Parse returns an error for files without a Trades or Cash Transactions section
+func TestInteractiveBrokersParse_NoSection(t *testing.T) {
+	// Arrange
+	file := strings.NewReader("Symbol,Position\nMSFT,5\n")
+
+	// Act
+	_, err := NewInteractiveBrokersParser().Parse(file)
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
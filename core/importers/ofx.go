@@ -0,0 +1,493 @@
+package importers
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// ofxDateLayout is the layout of the dates of the OFX files, which may be followed by a time, milliseconds and a time zone
+const ofxDateLayout = "20060102"
+
+// ofxTrades are the aggregates of the buys and sells of an investment statement, reporting whether they are sells
+var ofxTrades = map[string]bool{
+	"BUYSTOCK":  false,
+	"BUYMF":     false,
+	"BUYOTHER":  false,
+	"SELLSTOCK": true,
+	"SELLMF":    true,
+	"SELLOTHER": true,
+}
+
+// ofxPositions are the aggregates of the positions of an investment statement
+var ofxPositions = map[string]bool{
+	"POSSTOCK": true,
+	"POSMF":    true,
+	"POSDEBT":  true,
+	"POSOPT":   true,
+	"POSOTHER": true,
+}
+
+// ofxNode is an element of an OFX file, holding either a value or the elements it aggregates
+type ofxNode struct {
+	name     string
+	value    string
+	line     int
+	children []*ofxNode
+}
+
+// child returns the element at a path of names below the node, or nil when it has none
+func (n *ofxNode) child(path ...string) *ofxNode {
+	for _, name := range path {
+		if n == nil {
+			return nil
+		}
+		var found *ofxNode
+		for _, c := range n.children {
+			if c.name == name {
+				found = c
+				break
+			}
+		}
+		n = found
+	}
+	return n
+}
+
+// text returns the value of the element at a path of names below the node, or empty when it has none
+func (n *ofxNode) text(path ...string) string {
+	if c := n.child(path...); c != nil {
+		return c.value
+	}
+	return ""
+}
+
+// all returns the elements with a name below the node, at any depth and in the order of the file
+func (n *ofxNode) all(name string) []*ofxNode {
+	var nodes []*ofxNode
+	for _, c := range n.children {
+		if c.name == name {
+			nodes = append(nodes, c)
+		}
+		nodes = append(nodes, c.all(name)...)
+	}
+	return nodes
+}
+
+// ofxSecurity is a security of the security list of an OFX file
+type ofxSecurity struct {
+	symbol string
+	isin   string
+	name   string
+}
+
+// ofxParser adapter of the parser of the OFX investment statements
+type ofxParser struct{}
+
+// NewOFXParser creates the parser of the investment statements in the OFX format, both the SGML files of its version 1 and the XML files
+// of its version 2, and of the QFX files of Quicken, which are OFX files under another name. Banks rather than brokers give these files,
+// so they are imported into the holding of any broker by choosing their format. Buys and sells of stocks, funds and other securities are
+// imported as trades with their commissions, fees and taxes as their fee, and income as dividends, interest and withholding taxes.
+// Reinvested income is imported as a dividend and the buy it paid for. The positions listed in the statement are returned for their
+// reconciliation with the portfolio.
+func NewOFXParser() ports.StatementParser {
+	return &ofxParser{}
+}
+
+func (p *ofxParser) Broker() string {
+	return "ofx"
+}
+
+func (p *ofxParser) Parse(file io.Reader) (statement entities.Statement, err error) {
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return
+	}
+	root, err := parseOFX(string(content))
+	if err != nil {
+		err = wrappers.NewValidationErr(fmt.Errorf("invalid OFX statement: %w", err))
+		return
+	}
+	statements := root.all("INVSTMTRS")
+	if len(statements) == 0 {
+		err = wrappers.NewValidationErr(fmt.Errorf("invalid OFX statement: no investment statement found"))
+		return
+	}
+
+	securities := ofxSecurities(root)
+	for _, s := range statements {
+		code := currency(s.text("CURDEF"))
+		asOf, dateErr := parseDate(s.text("DTASOF"), ofxDateLayout)
+		if dateErr != nil {
+			statement.Invalid = append(statement.Invalid, invalid(s.line, dateErr))
+			continue
+		}
+
+		for _, t := range s.child("INVTRANLIST").children {
+			if t.value != "" {
+				continue
+			}
+			rows, ok, parseErr := p.rows(t, code, securities)
+			switch {
+			case parseErr != nil:
+				statement.Invalid = append(statement.Invalid, invalid(t.line, parseErr))
+			case !ok:
+				statement.Skipped = append(statement.Skipped, skipped(t.line, "%s transactions not supported", t.name))
+			default:
+				statement.Rows = append(statement.Rows, rows...)
+			}
+		}
+
+		for _, pos := range s.child("INVPOSLIST").children {
+			if !ofxPositions[pos.name] {
+				continue
+			}
+			position, parseErr := p.position(pos, code, asOf, securities)
+			if parseErr != nil {
+				statement.Invalid = append(statement.Invalid, invalid(pos.line, parseErr))
+				continue
+			}
+			statement.Positions = append(statement.Positions, position)
+		}
+	}
+	return
+}
+
+// rows parses a transaction of an investment statement, reporting false for the transactions that are not trades, income,
+// reinvestments or cash movements
+func (p *ofxParser) rows(t *ofxNode, code string, securities map[string]ofxSecurity) (rows []entities.StatementRow, ok bool, err error) {
+	switch {
+	case t.name == "INCOME":
+		rows, err = p.income(t, code, securities)
+	case t.name == "REINVEST":
+		rows, err = p.reinvest(t, code, securities)
+	case t.name == "INVBANKTRAN":
+		rows, err = p.cash(t, code)
+	default:
+		sell, isTrade := ofxTrades[t.name]
+		if !isTrade {
+			return
+		}
+		detail := t.child("INVBUY")
+		if sell {
+			detail = t.child("INVSELL")
+		}
+		if detail == nil {
+			err = fmt.Errorf("%s has no details", t.name)
+			return
+		}
+		var row entities.StatementRow
+		row, err = p.trade(t.line, detail, code, securities)
+		if sell {
+			row.Quantity = row.Quantity.Abs().Neg()
+		}
+		rows = []entities.StatementRow{row}
+	}
+	ok = true
+	return
+}
+
+// trade parses the details of a buy or a sell, or of a reinvestment, into a trade whose quantity is positive
+func (p *ofxParser) trade(line int, detail *ofxNode, code string, securities map[string]ofxSecurity) (row entities.StatementRow, err error) {
+	if row, err = p.transaction(line, entities.StatementRowTrade, detail, securities); err != nil {
+		return
+	}
+	code = ofxCurrency(detail, code)
+
+	if row.Quantity, err = ofxNumber(detail.text("UNITS")); err != nil {
+		return
+	}
+	if row.Quantity.IsZero() {
+		err = fmt.Errorf("units cannot be zero")
+		return
+	}
+	row.Quantity = row.Quantity.Abs()
+
+	fee := decimal.Zero
+	for _, name := range []string{"COMMISSION", "FEES", "TAXES", "LOAD"} {
+		charge, chargeErr := ofxNumber(detail.text(name))
+		if chargeErr != nil {
+			err = chargeErr
+			return
+		}
+		fee = fee.Add(charge.Abs())
+	}
+	if !fee.IsZero() {
+		row.Fee = entities.NewMoney(fee, code)
+	}
+
+	price, err := ofxNumber(detail.text("UNITPRICE"))
+	if err != nil {
+		return
+	}
+	if price.IsZero() {
+		// Some banks leave the unit price out, the total then tells it once the charges are taken out of the amount paid for buys, which
+		// is negative, or added back to the amount received for sells
+		total, totalErr := ofxNumber(detail.text("TOTAL"))
+		if totalErr != nil {
+			err = totalErr
+			return
+		}
+		if total.IsNegative() {
+			price = total.Abs().Sub(fee).Div(row.Quantity)
+		} else {
+			price = total.Add(fee).Div(row.Quantity)
+		}
+	}
+	row.Price = entities.NewMoney(price, code)
+	return
+}
+
+// income parses the income paid by a security into a dividend or interest, and the tax withheld from it
+func (p *ofxParser) income(t *ofxNode, code string, securities map[string]ofxSecurity) (rows []entities.StatementRow, err error) {
+	kind := entities.StatementRowDividend
+	switch incomeType := strings.ToUpper(t.text("INCOMETYPE")); incomeType {
+	case "DIV":
+	case "INTEREST":
+		kind = entities.StatementRowInterest
+	default:
+		err = fmt.Errorf("income type %q not supported", incomeType)
+		return
+	}
+
+	row, err := p.transaction(t.line, kind, t, securities)
+	if err != nil {
+		return
+	}
+	code = ofxCurrency(t, code)
+	total, err := ofxNumber(t.text("TOTAL"))
+	if err != nil {
+		return
+	}
+	row.Amount = entities.NewMoney(total.Abs(), code)
+	rows = append(rows, row)
+
+	withholding, err := ofxNumber(t.text("WITHHOLDING"))
+	if err != nil {
+		return
+	}
+	if !withholding.IsZero() {
+		tax := cashRow(t.line, entities.StatementRowWithholdingTax, row.Date, withholding, code)
+		tax.Symbol, tax.ISIN, tax.Name, tax.Reference = row.Symbol, row.ISIN, row.Name, row.Reference
+		rows = append(rows, tax)
+	}
+	return
+}
+
+// reinvest parses a reinvestment of income into the dividend or interest it reinvested and the buy it paid for
+func (p *ofxParser) reinvest(t *ofxNode, code string, securities map[string]ofxSecurity) (rows []entities.StatementRow, err error) {
+	income := &ofxNode{name: t.name, line: t.line}
+	for _, c := range t.children {
+		if c.name != "TOTAL" {
+			income.children = append(income.children, c)
+		}
+	}
+	buy, err := p.trade(t.line, t, code, securities)
+	if err != nil {
+		return
+	}
+
+	total, err := ofxNumber(t.text("TOTAL"))
+	if err != nil {
+		return
+	}
+	if total.IsZero() {
+		total = buy.Price.Amount.Mul(buy.Quantity).Add(buy.Fee.Amount)
+	}
+	income.children = append(income.children, &ofxNode{name: "TOTAL", value: total.String()})
+	if rows, err = p.income(income, code, securities); err != nil {
+		return
+	}
+	rows = append(rows, buy)
+	return
+}
+
+// cash parses a bank transaction of an investment account into a deposit, withdrawal, fee or interest
+func (p *ofxParser) cash(t *ofxNode, code string) (rows []entities.StatementRow, err error) {
+	detail := t.child("STMTTRN")
+	if detail == nil {
+		err = fmt.Errorf("INVBANKTRAN has no details")
+		return
+	}
+	date, err := parseDate(detail.text("DTPOSTED"), ofxDateLayout)
+	if err != nil {
+		return
+	}
+	amount, err := ofxNumber(detail.text("TRNAMT"))
+	if err != nil {
+		return
+	}
+
+	kind := entities.StatementRowDeposit
+	switch {
+	case strings.EqualFold(detail.text("TRNTYPE"), "INT"):
+		kind = entities.StatementRowInterest
+	case strings.EqualFold(detail.text("TRNTYPE"), "FEE"), strings.EqualFold(detail.text("TRNTYPE"), "SRVCHG"):
+		kind = entities.StatementRowFee
+	case amount.IsNegative():
+		kind = entities.StatementRowWithdrawal
+	}
+	row := cashRow(t.line, kind, date, amount, ofxCurrency(detail, code))
+	row.Reference = detail.text("FITID")
+	row.Description = strings.TrimSpace(detail.text("NAME") + " " + detail.text("MEMO"))
+	rows = append(rows, row)
+	return
+}
+
+// transaction returns a row of a kind with the date, reference, memo and security of the transaction of an element
+func (p *ofxParser) transaction(line int, kind string, n *ofxNode, securities map[string]ofxSecurity) (row entities.StatementRow, err error) {
+	row = entities.StatementRow{
+		Line:        line,
+		Kind:        kind,
+		Reference:   n.text("INVTRAN", "FITID"),
+		Description: n.text("INVTRAN", "MEMO"),
+	}
+	if row.Date, err = parseDate(n.text("INVTRAN", "DTTRADE"), ofxDateLayout); err != nil {
+		return
+	}
+	security, err := ofxSecurityOf(n, securities)
+	if err != nil {
+		return
+	}
+	row.Symbol, row.ISIN, row.Name = security.symbol, security.isin, security.name
+	return
+}
+
+// position parses a position of an investment statement held on the date of the statement
+func (p *ofxParser) position(pos *ofxNode, code string, asOf time.Time, securities map[string]ofxSecurity) (position entities.StatementPosition, err error) {
+	detail := pos.child("INVPOS")
+	if detail == nil {
+		err = fmt.Errorf("%s has no details", pos.name)
+		return
+	}
+	security, err := ofxSecurityOf(detail, securities)
+	if err != nil {
+		return
+	}
+	position = entities.StatementPosition{Line: pos.line, Date: asOf, Symbol: security.symbol, ISIN: security.isin, Name: security.name}
+
+	if position.Quantity, err = ofxNumber(detail.text("UNITS")); err != nil {
+		return
+	}
+	if strings.EqualFold(detail.text("POSTYPE"), "SHORT") {
+		position.Quantity = position.Quantity.Abs().Neg()
+	}
+	price, err := ofxNumber(detail.text("UNITPRICE"))
+	if err != nil {
+		return
+	}
+	position.Price = entities.NewMoney(price, ofxCurrency(detail, code))
+	return
+}
+
+// ofxSecurities indexes the securities of the security list of an OFX file by their identifier
+func ofxSecurities(root *ofxNode) map[string]ofxSecurity {
+	securities := map[string]ofxSecurity{}
+	for _, info := range root.all("SECINFO") {
+		id, idType := info.text("SECID", "UNIQUEID"), strings.ToUpper(info.text("SECID", "UNIQUEIDTYPE"))
+		security := ofxSecurity{symbol: strings.ToUpper(info.text("TICKER")), name: info.text("SECNAME")}
+		if idType == "ISIN" {
+			security.isin = strings.ToUpper(id)
+		}
+		if security.symbol == "" && security.isin == "" {
+			security.symbol = strings.ToUpper(id)
+		}
+		securities[idType+":"+id] = security
+	}
+	return securities
+}
+
+// ofxSecurityOf returns the security of the identifier of an element, which must be in the security list of the file
+func ofxSecurityOf(n *ofxNode, securities map[string]ofxSecurity) (ofxSecurity, error) {
+	id, idType := n.text("SECID", "UNIQUEID"), strings.ToUpper(n.text("SECID", "UNIQUEIDTYPE"))
+	if id == "" {
+		return ofxSecurity{}, fmt.Errorf("security identifier cannot be empty")
+	}
+	security, ok := securities[idType+":"+id]
+	if !ok {
+		return ofxSecurity{}, fmt.Errorf("security %s %s not in the security list", idType, id)
+	}
+	return security, nil
+}
+
+// ofxCurrency returns the currency of the amounts of an element, which is the default currency of the statement unless the element
+// has a currency of its own. Amounts with an original currency are already converted into the default currency.
+func ofxCurrency(n *ofxNode, code string) string {
+	if c := currency(n.text("CURRENCY", "CURSYM")); c != "" {
+		return c
+	}
+	return code
+}
+
+// ofxNumber parses an amount of an OFX file, which has no thousands separators and may have a decimal comma
+func ofxNumber(value string) (decimal.Decimal, error) {
+	return parseNumber(value, strings.Contains(value, ",") && !strings.Contains(value, "."))
+}
+
+// parseOFX reads the elements of an OFX file from its OFX element on, skipping the header before it. Elements holding a value need
+// no end tag, as in the SGML files, and the end tags of the elements holding one are ignored, as in the XML files.
+func parseOFX(content string) (*ofxNode, error) {
+	start := strings.Index(strings.ToUpper(content), "<OFX>")
+	if start < 0 {
+		return nil, fmt.Errorf("OFX element not found")
+	}
+	line := 1 + strings.Count(content[:start], "\n")
+	root := &ofxNode{line: line}
+	stack := []*ofxNode{root}
+
+	rest := content[start:]
+	for {
+		open := strings.IndexByte(rest, '<')
+		if open < 0 {
+			break
+		}
+		line += strings.Count(rest[:open], "\n")
+		rest = rest[open:]
+		end := strings.IndexByte(rest, '>')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated tag on line %d", line)
+		}
+		tag := strings.TrimSpace(rest[1:end])
+		tagLine := line
+		line += strings.Count(tag, "\n")
+		rest = rest[end+1:]
+
+		switch {
+		case tag == "" || strings.HasPrefix(tag, "?") || strings.HasPrefix(tag, "!"):
+		case strings.HasPrefix(tag, "/"):
+			name := strings.ToUpper(strings.TrimSpace(tag[1:]))
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].name == name {
+					stack = stack[:i]
+					break
+				}
+			}
+		default:
+			closed := strings.HasSuffix(tag, "/")
+			node := &ofxNode{name: strings.ToUpper(strings.Fields(strings.TrimSuffix(tag, "/"))[0]), line: tagLine}
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, node)
+			if closed {
+				continue
+			}
+
+			next := strings.IndexByte(rest, '<')
+			if next < 0 {
+				next = len(rest)
+			}
+			if value := strings.TrimSpace(rest[:next]); value != "" {
+				node.value = html.UnescapeString(value)
+				continue
+			}
+			stack = append(stack, node)
+		}
+	}
+	return root, nil
+}
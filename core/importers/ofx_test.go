@@ -0,0 +1,242 @@
+package importers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// ofxSGMLFile is an OFX 1.x investment statement, whose elements holding a value have no end tag
+const ofxSGMLFile = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+SECURITY:NONE
+ENCODING:USASCII
+CHARSET:1252
+COMPRESSION:NONE
+OLDFILEUID:NONE
+NEWFILEUID:NONE
+
+<OFX>
+<INVSTMTMSGSRSV1>
+<INVSTMTTRNRS>
+<TRNUID>1001
+<INVSTMTRS>
+<DTASOF>20240331120000.000[-5:EST]
+<CURDEF>USD
+<INVACCTFROM><BROKERID>bank.example.com<ACCTID>12345</INVACCTFROM>
+<INVTRANLIST>
+<DTSTART>20240101
+<DTEND>20240331
+<BUYSTOCK>
+<INVBUY>
+<INVTRAN><FITID>T-1<DTTRADE>20240105<MEMO>Buy Apple</INVTRAN>
+<SECID><UNIQUEID>037833100<UNIQUEIDTYPE>CUSIP</SECID>
+<UNITS>10
+<UNITPRICE>185.50
+<COMMISSION>4.95
+<FEES>0.05
+<TOTAL>-1860.00
+<SUBACCTSEC>CASH<SUBACCTFUND>CASH
+</INVBUY>
+<BUYTYPE>BUY
+</BUYSTOCK>
+<SELLSTOCK>
+<INVSELL>
+<INVTRAN><FITID>T-2<DTTRADE>20240212</INVTRAN>
+<SECID><UNIQUEID>037833100<UNIQUEIDTYPE>CUSIP</SECID>
+<UNITS>-4
+<COMMISSION>5
+<TOTAL>755
+<SUBACCTSEC>CASH<SUBACCTFUND>CASH
+</INVSELL>
+<SELLTYPE>SELL
+</SELLSTOCK>
+<INCOME>
+<INVTRAN><FITID>T-3<DTTRADE>20240215</INVTRAN>
+<SECID><UNIQUEID>037833100<UNIQUEIDTYPE>CUSIP</SECID>
+<INCOMETYPE>DIV
+<TOTAL>1.44
+<SUBACCTSEC>CASH<SUBACCTFUND>CASH
+<WITHHOLDING>0.22
+</INCOME>
+<REINVEST>
+<INVTRAN><FITID>T-4<DTTRADE>20240320</INVTRAN>
+<SECID><UNIQUEID>IE00B4L5Y983<UNIQUEIDTYPE>ISIN</SECID>
+<INCOMETYPE>DIV
+<TOTAL>-25.00
+<SUBACCTSEC>CASH
+<UNITS>0.25
+<UNITPRICE>100
+<CURRENCY><CURRATE>1.08<CURSYM>EUR</CURRENCY>
+</REINVEST>
+<INVBANKTRAN>
+<STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20240102<TRNAMT>2000<FITID>C-1<NAME>Deposit</STMTTRN>
+<SUBACCTFUND>CASH
+</INVBANKTRAN>
+<TRANSFER>
+<INVTRAN><FITID>T-5<DTTRADE>20240325</INVTRAN>
+<SECID><UNIQUEID>037833100<UNIQUEIDTYPE>CUSIP</SECID>
+<SUBACCTSEC>CASH<UNITS>1<TFERACTION>IN<POSTYPE>LONG
+</TRANSFER>
+<INCOME>
+<INVTRAN><FITID>T-6<DTTRADE>20240328</INVTRAN>
+<SECID><UNIQUEID>999999999<UNIQUEIDTYPE>CUSIP</SECID>
+<INCOMETYPE>DIV<TOTAL>3<SUBACCTSEC>CASH<SUBACCTFUND>CASH
+</INCOME>
+</INVTRANLIST>
+<INVPOSLIST>
+<POSSTOCK><INVPOS>
+<SECID><UNIQUEID>037833100<UNIQUEIDTYPE>CUSIP</SECID>
+<HELDINACCT>CASH<POSTYPE>LONG<UNITS>6<UNITPRICE>171.48<MKTVAL>1028.88<DTPRICEASOF>20240328
+</INVPOS></POSSTOCK>
+<POSMF><INVPOS>
+<SECID><UNIQUEID>IE00B4L5Y983<UNIQUEIDTYPE>ISIN</SECID>
+<HELDINACCT>CASH<POSTYPE>LONG<UNITS>0.25<UNITPRICE>101.2<MKTVAL>25.3<DTPRICEASOF>20240328
+<CURRENCY><CURRATE>1.08<CURSYM>EUR</CURRENCY>
+</INVPOS></POSMF>
+</INVPOSLIST>
+</INVSTMTRS>
+</INVSTMTTRNRS>
+</INVSTMTMSGSRSV1>
+<SECLISTMSGSRSV1>
+<SECLIST>
+<STOCKINFO><SECINFO><SECID><UNIQUEID>037833100<UNIQUEIDTYPE>CUSIP</SECID><SECNAME>Apple Inc.<TICKER>AAPL</SECINFO></STOCKINFO>
+<MFINFO><SECINFO><SECID><UNIQUEID>IE00B4L5Y983<UNIQUEIDTYPE>ISIN</SECID><SECNAME>iShares Core MSCI World &amp; Co</SECINFO></MFINFO>
+</SECLIST>
+</SECLISTMSGSRSV1>
+</OFX>
+`
+
+// TestOFXParse_SGML checks that Parse reads the trades, income, reinvestments, cash movements and positions of an OFX 1.x statement
+func TestOFXParse_SGML(t *testing.T) {
+	// Act
+	statement, err := NewOFXParser().Parse(strings.NewReader(ofxSGMLFile))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, statement.Rows, 7)
+
+	buy := statement.Rows[0]
+	assert.Equal(t, entities.StatementRowTrade, buy.Kind)
+	assert.Equal(t, 22, buy.Line)
+	assert.Equal(t, time.Date(2024, time.January, 5, 0, 0, 0, 0, time.UTC), buy.Date)
+	assert.Equal(t, "AAPL", buy.Symbol)
+	assert.Equal(t, "Apple Inc.", buy.Name)
+	assert.Equal(t, "10", buy.Quantity.String())
+	assert.Equal(t, "185.5 USD", buy.Price.String())
+	assert.Equal(t, "5 USD", buy.Fee.String())
+	assert.Equal(t, "T-1", buy.Reference)
+	assert.Equal(t, "Buy Apple", buy.Description)
+
+	sell := statement.Rows[1]
+	assert.Equal(t, "-4", sell.Quantity.String())
+	assert.Equal(t, "190 USD", sell.Price.String())
+
+	dividend, tax := statement.Rows[2], statement.Rows[3]
+	assert.Equal(t, entities.StatementRowDividend, dividend.Kind)
+	assert.Equal(t, "1.44 USD", dividend.Amount.String())
+	assert.Equal(t, entities.StatementRowWithholdingTax, tax.Kind)
+	assert.Equal(t, "0.22 USD", tax.Amount.String())
+	assert.Equal(t, "AAPL", tax.Symbol)
+
+	reinvested, reinvestment := statement.Rows[4], statement.Rows[5]
+	assert.Equal(t, entities.StatementRowDividend, reinvested.Kind)
+	assert.Equal(t, "IE00B4L5Y983", reinvested.ISIN)
+	assert.Equal(t, "iShares Core MSCI World & Co", reinvested.Name)
+	assert.Equal(t, "25 EUR", reinvested.Amount.String())
+	assert.Equal(t, entities.StatementRowTrade, reinvestment.Kind)
+	assert.Equal(t, "0.25", reinvestment.Quantity.String())
+	assert.Equal(t, "100 EUR", reinvestment.Price.String())
+
+	deposit := statement.Rows[6]
+	assert.Equal(t, entities.StatementRowDeposit, deposit.Kind)
+	assert.Equal(t, "2000 USD", deposit.Amount.String())
+
+	assert.Equal(t, []entities.StatementIssue{{Line: 68, Reason: "TRANSFER transactions not supported"}}, statement.Skipped)
+	assert.Equal(t, []entities.StatementIssue{{Line: 73, Reason: "security CUSIP 999999999 not in the security list"}}, statement.Invalid)
+
+	assert.Len(t, statement.Positions, 2)
+	apple := statement.Positions[0]
+	assert.Equal(t, time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC), apple.Date)
+	assert.Equal(t, "AAPL", apple.Symbol)
+	assert.Equal(t, "6", apple.Quantity.String())
+	assert.Equal(t, "171.48 USD", apple.Price.String())
+	assert.Equal(t, "101.2 EUR", statement.Positions[1].Price.String())
+}
+
+// TestOFXParse_XML checks that Parse reads an OFX 2.x statement, whose elements all have an end tag
+func TestOFXParse_XML(t *testing.T) {
+	// Arrange
+	file := `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+  <INVSTMTMSGSRSV1>
+    <INVSTMTTRNRS>
+      <INVSTMTRS>
+        <DTASOF>20240630</DTASOF>
+        <CURDEF>EUR</CURDEF>
+        <INVTRANLIST>
+          <SELLMF>
+            <INVSELL>
+              <INVTRAN><FITID>S-1</FITID><DTTRADE>20240610</DTTRADE><MEMO></MEMO></INVTRAN>
+              <SECID><UNIQUEID>IE00B4L5Y983</UNIQUEID><UNIQUEIDTYPE>ISIN</UNIQUEIDTYPE></SECID>
+              <UNITS>2</UNITS>
+              <UNITPRICE>90,5</UNITPRICE>
+              <TOTAL>181</TOTAL>
+            </INVSELL>
+            <SELLTYPE>SELL</SELLTYPE>
+          </SELLMF>
+        </INVTRANLIST>
+        <INVPOSLIST>
+          <POSMF><INVPOS><SECID><UNIQUEID>IE00B4L5Y983</UNIQUEID><UNIQUEIDTYPE>ISIN</UNIQUEIDTYPE></SECID><POSTYPE>SHORT</POSTYPE><UNITS>2</UNITS><UNITPRICE>91</UNITPRICE></INVPOS></POSMF>
+        </INVPOSLIST>
+      </INVSTMTRS>
+    </INVSTMTTRNRS>
+  </INVSTMTMSGSRSV1>
+  <SECLISTMSGSRSV1>
+    <SECLIST>
+      <MFINFO><SECINFO><SECID><UNIQUEID>IE00B4L5Y983</UNIQUEID><UNIQUEIDTYPE>ISIN</UNIQUEIDTYPE></SECID><SECNAME>MSCI World</SECNAME><TICKER>SWDA</TICKER></SECINFO></MFINFO>
+    </SECLIST>
+  </SECLISTMSGSRSV1>
+</OFX>`
+
+	// Act
+	statement, err := NewOFXParser().Parse(strings.NewReader(file))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, statement.Rows, 1)
+	sell := statement.Rows[0]
+	assert.Equal(t, 10, sell.Line)
+	assert.Equal(t, "SWDA", sell.Symbol)
+	assert.Equal(t, "IE00B4L5Y983", sell.ISIN)
+	assert.Equal(t, "-2", sell.Quantity.String())
+	assert.Equal(t, "90.5 EUR", sell.Price.String())
+	assert.Empty(t, statement.Skipped)
+	assert.Empty(t, statement.Invalid)
+	assert.Equal(t, "-2", statement.Positions[0].Quantity.String())
+	assert.Equal(t, time.Date(2024, time.June, 30, 0, 0, 0, 0, time.UTC), statement.Positions[0].Date)
+}
+
+// TestOFXParse_InvalidFile checks that Parse returns a validation error for files that are not OFX investment statements
+func TestOFXParse_InvalidFile(t *testing.T) {
+	files := map[string]string{
+		"not ofx":          "Date,Type\n",
+		"bank statement":   "<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><CURDEF>USD</STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>",
+		"unterminated tag": "<OFX><INVSTMTMSGSRSV1><INVSTMTRS",
+	}
+	for name, file := range files {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			_, err := NewOFXParser().Parse(strings.NewReader(file))
+
+			// Assert
+			assert.ErrorIs(t, err, wrappers.ValidationErr)
+		})
+	}
+}
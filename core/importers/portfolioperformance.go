@@ -0,0 +1,641 @@
+package importers
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// Scales of the numbers of a Portfolio Performance file, which stores amounts in hundredths and shares and quotes in hundred millionths
+var (
+	ppAmountScale = decimal.New(1, 2)
+	ppSharesScale = decimal.New(1, 8)
+	ppQuoteScale  = decimal.New(1, 8)
+)
+
+// ppMinVersion is the first version of the Portfolio Performance files storing shares and quotes with eight decimal places, and
+// ppVersion the version of the files written
+const (
+	ppMinVersion = 51
+	ppVersion    = 57
+)
+
+// ppDateLayouts are the layouts of the dates and times of a Portfolio Performance file
+var ppDateLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"}
+
+// portfolioPerformanceCodec adapter of the reader and writer of the Portfolio Performance XML files
+type portfolioPerformanceCodec struct{}
+
+// NewPortfolioPerformanceCodec creates the reader and writer of the XML files of Portfolio Performance. The files are written by
+// XStream, which serializes every item once and refers to it afterwards by a path relative to the referring element, or by its id in
+// the files saved with ids, so the file is read as a tree whose references are followed.
+func NewPortfolioPerformanceCodec() ports.PortfolioPerformanceCodec {
+	return &portfolioPerformanceCodec{}
+}
+
+func (c *portfolioPerformanceCodec) Decode(file io.Reader) (client entities.PPClient, err error) {
+	doc, err := readPPDocument(file)
+	if err != nil {
+		return
+	}
+	root := doc.root.child("client")
+	if root == nil {
+		err = wrappers.NewValidationErr(fmt.Errorf("invalid Portfolio Performance file: client element not found"))
+		return
+	}
+
+	if client.Version, err = strconv.Atoi(root.text("version")); err != nil {
+		err = wrappers.NewValidationErr(fmt.Errorf("invalid Portfolio Performance file: invalid version %q", root.text("version")))
+		return
+	}
+	if client.Version < ppMinVersion {
+		err = wrappers.NewValidationErr(fmt.Errorf("Portfolio Performance file version %d not supported, save it with a recent version of Portfolio Performance", client.Version))
+		return
+	}
+	client.BaseCurrency = root.text("baseCurrency")
+
+	for _, n := range root.list("securities", "security") {
+		security := entities.PPSecurity{
+			UUID:         n.text("uuid"),
+			Name:         n.text("name"),
+			CurrencyCode: n.text("currencyCode"),
+			ISIN:         n.text("isin"),
+			TickerSymbol: n.text("tickerSymbol"),
+			WKN:          n.text("wkn"),
+			Feed:         n.text("feed"),
+			Retired:      n.text("isRetired") == "true",
+		}
+		for _, p := range n.list("prices", "price") {
+			price, err := parsePPPrice(p)
+			if err != nil {
+				return entities.PPClient{}, wrappers.NewValidationErr(fmt.Errorf("invalid Portfolio Performance file: security %s: %s", security.Name, err))
+			}
+			security.Prices = append(security.Prices, price)
+		}
+		client.Securities = append(client.Securities, security)
+	}
+
+	for _, n := range root.list("accounts", "account") {
+		account := entities.PPAccount{UUID: n.text("uuid"), Name: n.text("name"), CurrencyCode: n.text("currencyCode"), Retired: n.text("isRetired") == "true"}
+		for _, t := range n.list("transactions", "account-transaction") {
+			transaction, err := parsePPTransaction(t, account.CurrencyCode)
+			if err != nil {
+				return entities.PPClient{}, wrappers.NewValidationErr(fmt.Errorf("invalid Portfolio Performance file: account %s: %s", account.Name, err))
+			}
+			account.Transactions = append(account.Transactions, transaction)
+		}
+		client.Accounts = append(client.Accounts, account)
+	}
+
+	for _, n := range root.list("portfolios", "portfolio") {
+		portfolio := entities.PPPortfolio{UUID: n.text("uuid"), Name: n.text("name"), Retired: n.text("isRetired") == "true"}
+		if account := n.child("referenceAccount"); account != nil {
+			portfolio.ReferenceAccount = account.text("uuid")
+		}
+		for _, t := range n.list("transactions", "portfolio-transaction") {
+			transaction, err := parsePPTransaction(t, "")
+			if err != nil {
+				return entities.PPClient{}, wrappers.NewValidationErr(fmt.Errorf("invalid Portfolio Performance file: portfolio %s: %s", portfolio.Name, err))
+			}
+			portfolio.Transactions = append(portfolio.Transactions, transaction)
+		}
+		client.Portfolios = append(client.Portfolios, portfolio)
+	}
+
+	return
+}
+
+func (c *portfolioPerformanceCodec) Encode(w io.Writer, client entities.PPClient) error {
+	pw := &ppWriter{
+		w:                     bufio.NewWriter(w),
+		client:                client,
+		written:               map[string][]string{},
+		accounts:              map[string]int{},
+		accountTransactions:   map[string]ppOwned{},
+		portfolioTransactions: map[string]ppOwned{},
+	}
+	for i, a := range client.Accounts {
+		pw.accounts[a.UUID] = i
+		for _, t := range a.Transactions {
+			pw.accountTransactions[t.UUID] = ppOwned{owner: i, transaction: t}
+		}
+	}
+	for i, p := range client.Portfolios {
+		for _, t := range p.Transactions {
+			pw.portfolioTransactions[t.UUID] = ppOwned{owner: i, transaction: t}
+		}
+	}
+
+	pw.line(`<?xml version="1.0" encoding="UTF-8"?>`)
+	pw.open("client", "")
+	pw.element("version", strconv.Itoa(ppVersion))
+	pw.element("baseCurrency", client.BaseCurrency)
+
+	pw.open("securities", "")
+	for _, s := range client.Securities {
+		pw.open("security", "")
+		pw.register("security/" + s.UUID)
+		pw.element("uuid", s.UUID)
+		pw.element("name", s.Name)
+		pw.element("currencyCode", s.CurrencyCode)
+		pw.optional("isin", s.ISIN)
+		pw.optional("tickerSymbol", s.TickerSymbol)
+		pw.optional("wkn", s.WKN)
+		pw.element("feed", s.Feed)
+		pw.open("prices", "")
+		for _, p := range s.Prices {
+			pw.empty("price", fmt.Sprintf(` t="%s" v="%s"`, p.Date.Format("2006-01-02"), p.Value.Mul(ppQuoteScale).Round(0)))
+		}
+		pw.close()
+		pw.element("isRetired", strconv.FormatBool(s.Retired))
+		pw.close()
+	}
+	pw.close()
+	pw.empty("watchlists", "")
+
+	pw.open("accounts", "")
+	for i := range client.Accounts {
+		pw.account("account", i)
+	}
+	pw.close()
+
+	pw.open("portfolios", "")
+	for i := range client.Portfolios {
+		pw.portfolio("portfolio", i)
+	}
+	pw.close()
+	pw.empty("plans", "")
+	pw.empty("taxonomies", "")
+	pw.empty("dashboards", "")
+	pw.empty("properties", "")
+	pw.close()
+
+	if pw.err != nil {
+		return pw.err
+	}
+	return pw.w.Flush()
+}
+
+// parsePPPrice parses a quote of a security
+func parsePPPrice(n *ppNode) (entities.PPPrice, error) {
+	date, err := parseDate(n.attrs["t"], ppDateLayouts...)
+	if err != nil {
+		return entities.PPPrice{}, err
+	}
+	value, err := decimal.NewFromString(n.attrs["v"])
+	if err != nil {
+		return entities.PPPrice{}, fmt.Errorf("invalid quote %q", n.attrs["v"])
+	}
+	return entities.PPPrice{Date: date, Value: value.Div(ppQuoteScale)}, nil
+}
+
+// parsePPTransaction parses a transaction of a deposit or securities account, whose currency defaults to the one of its account
+func parsePPTransaction(n *ppNode, currencyCode string) (t entities.PPTransaction, err error) {
+	t = entities.PPTransaction{UUID: n.text("uuid"), Type: n.text("type"), Note: n.text("note")}
+	if t.Date, err = parseDate(n.text("date"), ppDateLayouts...); err != nil {
+		return
+	}
+	if exDate := n.text("exDate"); exDate != "" {
+		if t.ExDate, err = parseDate(exDate, ppDateLayouts...); err != nil {
+			return
+		}
+	}
+	if code := n.text("currencyCode"); code != "" {
+		currencyCode = code
+	}
+	if security := n.child("security"); security != nil {
+		t.Security = security.text("uuid")
+	}
+	// the cross entry of a buy or sell holds both of its sides, the other one being the side whose UUID differs
+	if cross := n.child("crossEntry"); cross != nil {
+		for _, side := range []string{"portfolioTransaction", "accountTransaction"} {
+			if other := cross.child(side); other != nil && other.text("uuid") != t.UUID {
+				t.CrossEntry = other.text("uuid")
+			}
+		}
+	}
+	if t.Shares, err = ppNumber(n.text("shares"), ppSharesScale); err != nil {
+		return
+	}
+	amount, err := ppNumber(n.text("amount"), ppAmountScale)
+	if err != nil {
+		return
+	}
+	t.Amount = entities.NewMoney(amount, currencyCode)
+
+	for _, u := range n.list("units", "unit") {
+		value := u.child("amount")
+		if value == nil {
+			continue
+		}
+		amount, err := ppMoney(value)
+		if err != nil {
+			return t, err
+		}
+		switch u.attrs["type"] {
+		case "FEE":
+			if t.Fees, err = t.Fees.Add(amount); err != nil {
+				return t, err
+			}
+		case "TAX":
+			if t.Taxes, err = t.Taxes.Add(amount); err != nil {
+				return t, err
+			}
+		case "GROSS_VALUE":
+			if forex := u.child("forex"); forex != nil {
+				if t.GrossValue, err = ppMoney(forex); err != nil {
+					return t, err
+				}
+			}
+		}
+	}
+	return
+}
+
+// ppMoney parses an amount of a unit of a transaction, written as attributes
+func ppMoney(n *ppNode) (entities.Money, error) {
+	amount, err := ppNumber(n.attrs["amount"], ppAmountScale)
+	if err != nil {
+		return entities.Money{}, err
+	}
+	return entities.NewMoney(amount, n.attrs["currency"]), nil
+}
+
+// ppNumber parses a number stored multiplied by scale. Empty values are zero.
+func ppNumber(value string, scale decimal.Decimal) (decimal.Decimal, error) {
+	if value == "" {
+		return decimal.Zero, nil
+	}
+	number, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid number %q", value)
+	}
+	return number.Div(scale), nil
+}
+
+// ppStep returns the step of a reference to the nth element with a name, which XStream writes without an index for the first one
+func ppStep(name string, n int) string {
+	if n == 1 {
+		return name
+	}
+	return fmt.Sprintf("%s[%d]", name, n)
+}
+
+// ppNode is an element of a Portfolio Performance file
+type ppNode struct {
+	name     string
+	attrs    map[string]string
+	value    string
+	parent   *ppNode
+	children []*ppNode
+	doc      *ppDocument
+}
+
+// ppDocument is a Portfolio Performance file read as a tree, whose root holds the client element
+type ppDocument struct {
+	root *ppNode
+	ids  map[string]*ppNode
+}
+
+// readPPDocument reads the elements of a Portfolio Performance file into a tree
+func readPPDocument(file io.Reader) (*ppDocument, error) {
+	doc := &ppDocument{ids: map[string]*ppNode{}}
+	doc.root = &ppNode{doc: doc}
+
+	dec := xml.NewDecoder(file)
+	current := doc.root
+	for {
+		token, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, wrappers.NewValidationErr(fmt.Errorf("invalid Portfolio Performance file: %w", err))
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			n := &ppNode{name: t.Name.Local, attrs: map[string]string{}, parent: current, doc: doc}
+			for _, a := range t.Attr {
+				n.attrs[a.Name.Local] = a.Value
+			}
+			if id, ok := n.attrs["id"]; ok {
+				doc.ids[id] = n
+			}
+			current.children = append(current.children, n)
+			current = n
+		case xml.EndElement:
+			current.value = strings.TrimSpace(current.value)
+			current = current.parent
+		case xml.CharData:
+			current.value += string(t)
+		}
+	}
+	return doc, nil
+}
+
+// resolve returns the element a reference refers to, or the element itself when it is not a reference
+func (n *ppNode) resolve() *ppNode {
+	for hops := 0; n != nil && hops < 16; hops++ {
+		reference, ok := n.attrs["reference"]
+		if !ok {
+			return n
+		}
+		if target, ok := n.doc.ids[reference]; ok {
+			n = target
+			continue
+		}
+
+		target := n
+		if strings.HasPrefix(reference, "/") {
+			target = n.doc.root
+		}
+		for _, step := range strings.Split(reference, "/") {
+			switch step {
+			case "", ".":
+			case "..":
+				target = target.parent
+			default:
+				name, index := step, 1
+				if i := strings.Index(step, "["); i >= 0 && strings.HasSuffix(step, "]") {
+					name = step[:i]
+					index, _ = strconv.Atoi(step[i+1 : len(step)-1])
+				}
+				target = target.nth(name, index)
+			}
+			if target == nil {
+				return nil
+			}
+		}
+		n = target
+	}
+	return n
+}
+
+// nth returns the nth child element with a name, counting from one, without resolving it
+func (n *ppNode) nth(name string, index int) *ppNode {
+	for _, c := range n.children {
+		if c.name == name {
+			index--
+			if index == 0 {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// child returns the first child element with a name, resolved
+func (n *ppNode) child(name string) *ppNode {
+	if n = n.resolve(); n == nil {
+		return nil
+	}
+	if c := n.nth(name, 1); c != nil {
+		return c.resolve()
+	}
+	return nil
+}
+
+// text returns the text of the first child element with a name
+func (n *ppNode) text(name string) string {
+	if c := n.child(name); c != nil {
+		return c.value
+	}
+	return ""
+}
+
+// list returns the resolved elements with a name of the list of a child element
+func (n *ppNode) list(listName, name string) []*ppNode {
+	list := n.child(listName)
+	if list == nil {
+		return nil
+	}
+	var items []*ppNode
+	for _, c := range list.children {
+		if c.name != name {
+			continue
+		}
+		if item := c.resolve(); item != nil {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ppOwned is a transaction of a Portfolio Performance file with the position of its account in the accounts of its kind
+type ppOwned struct {
+	owner       int
+	transaction entities.PPTransaction
+}
+
+// ppWriter writes the elements of a Portfolio Performance file, keeping the first error. As XStream does, every item is written in
+// full where it is first found and referred to afterwards by its path relative to the referring element, so the portfolio and the
+// transactions of a buy or sell are written inside the one of the deposit account that is written first.
+type ppWriter struct {
+	w      *bufio.Writer
+	err    error
+	client entities.PPClient
+	// names and path are the names and the steps of the open elements, and counts the number of children of each one by name
+	names   []string
+	path    []string
+	counts  []map[string]int
+	written map[string][]string
+
+	accounts              map[string]int
+	accountTransactions   map[string]ppOwned
+	portfolioTransactions map[string]ppOwned
+}
+
+func (pw *ppWriter) line(line string) {
+	if pw.err != nil {
+		return
+	}
+	_, pw.err = pw.w.WriteString(strings.Repeat("  ", len(pw.path)) + line + "\n")
+}
+
+// step returns the step of the next child element with a name of the open element
+func (pw *ppWriter) step(name string) string {
+	if len(pw.counts) == 0 {
+		return name
+	}
+	counts := pw.counts[len(pw.counts)-1]
+	counts[name]++
+	return ppStep(name, counts[name])
+}
+
+// open writes the start of an element with its attributes, written with a leading space
+func (pw *ppWriter) open(name, attrs string) {
+	step := pw.step(name)
+	pw.line("<" + name + attrs + ">")
+	pw.names = append(pw.names, name)
+	pw.path = append(pw.path, step)
+	pw.counts = append(pw.counts, map[string]int{})
+}
+
+// close writes the end of the open element
+func (pw *ppWriter) close() {
+	name := pw.names[len(pw.names)-1]
+	pw.names, pw.path, pw.counts = pw.names[:len(pw.names)-1], pw.path[:len(pw.path)-1], pw.counts[:len(pw.counts)-1]
+	pw.line("</" + name + ">")
+}
+
+// empty writes an element without content
+func (pw *ppWriter) empty(name, attrs string) {
+	pw.step(name)
+	pw.line("<" + name + attrs + "/>")
+}
+
+// element writes an element holding a text
+func (pw *ppWriter) element(name, text string) {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(text))
+	pw.step(name)
+	pw.line(fmt.Sprintf("<%s>%s</%s>", name, sb.String(), name))
+}
+
+// optional writes an element holding a text unless the text is empty
+func (pw *ppWriter) optional(name, text string) {
+	if text != "" {
+		pw.element(name, text)
+	}
+}
+
+// register records the open element as the one an item is written in
+func (pw *ppWriter) register(key string) {
+	pw.written[key] = append([]string(nil), pw.path...)
+}
+
+// reference writes an element referring to an item already written and reports whether it was
+func (pw *ppWriter) reference(name, attrs, key string) bool {
+	target, ok := pw.written[key]
+	if !ok {
+		return false
+	}
+	from := append(append([]string(nil), pw.path...), pw.step(name))
+	common := 0
+	for common < len(from) && common < len(target) && from[common] == target[common] {
+		common++
+	}
+	var steps []string
+	for range from[common:] {
+		steps = append(steps, "..")
+	}
+	steps = append(steps, target[common:]...)
+	pw.line(fmt.Sprintf(`<%s%s reference="%s"/>`, name, attrs, strings.Join(steps, "/")))
+	return true
+}
+
+// account writes the nth deposit account
+func (pw *ppWriter) account(name string, n int) {
+	a := pw.client.Accounts[n]
+	if pw.reference(name, "", "account/"+a.UUID) {
+		return
+	}
+	pw.open(name, "")
+	pw.register("account/" + a.UUID)
+	pw.element("uuid", a.UUID)
+	pw.element("name", a.Name)
+	pw.element("currencyCode", a.CurrencyCode)
+	pw.element("isRetired", strconv.FormatBool(a.Retired))
+	pw.open("transactions", "")
+	for _, t := range a.Transactions {
+		pw.transaction("account-transaction", t, true)
+	}
+	pw.close()
+	pw.close()
+}
+
+// portfolio writes the nth securities account
+func (pw *ppWriter) portfolio(name string, n int) {
+	p := pw.client.Portfolios[n]
+	if pw.reference(name, "", "portfolio/"+p.UUID) {
+		return
+	}
+	pw.open(name, "")
+	pw.register("portfolio/" + p.UUID)
+	pw.element("uuid", p.UUID)
+	pw.element("name", p.Name)
+	pw.element("isRetired", strconv.FormatBool(p.Retired))
+	if n, ok := pw.accounts[p.ReferenceAccount]; ok {
+		pw.account("referenceAccount", n)
+	}
+	pw.open("transactions", "")
+	for _, t := range p.Transactions {
+		pw.transaction("portfolio-transaction", t, false)
+	}
+	pw.close()
+	pw.close()
+}
+
+// transaction writes a transaction of a deposit or securities account, with the buy or sell it is a side of
+func (pw *ppWriter) transaction(name string, t entities.PPTransaction, account bool) {
+	if pw.reference(name, "", "transaction/"+t.UUID) {
+		return
+	}
+	pw.open(name, "")
+	pw.register("transaction/" + t.UUID)
+	pw.element("uuid", t.UUID)
+	pw.element("date", t.Date.Format("2006-01-02T15:04"))
+	pw.element("currencyCode", t.Amount.Currency)
+	pw.element("amount", t.Amount.Amount.Mul(ppAmountScale).Round(0).String())
+	if t.Security != "" {
+		pw.reference("security", "", "security/"+t.Security)
+	}
+	if t.CrossEntry != "" {
+		pw.crossEntry(t, account)
+	}
+	pw.element("shares", t.Shares.Mul(ppSharesScale).Round(0).String())
+	pw.optional("note", t.Note)
+
+	units := []struct {
+		kind   string
+		amount entities.Money
+	}{{"FEE", t.Fees}, {"TAX", t.Taxes}}
+	pw.open("units", "")
+	for _, u := range units {
+		if !u.amount.IsZero() {
+			pw.open("unit", fmt.Sprintf(` type="%s"`, u.kind))
+			pw.empty("amount", fmt.Sprintf(` currency="%s" amount="%s"`, u.amount.Currency, u.amount.Amount.Mul(ppAmountScale).Round(0)))
+			pw.close()
+		}
+	}
+	pw.close()
+	pw.element("type", t.Type)
+	pw.close()
+}
+
+// crossEntry writes the buy or sell a transaction is a side of, which is left out when its other side is not in the file
+func (pw *ppWriter) crossEntry(t entities.PPTransaction, account bool) {
+	accountUUID, portfolioUUID := t.UUID, t.CrossEntry
+	if !account {
+		accountUUID, portfolioUUID = t.CrossEntry, t.UUID
+	}
+	a, ok := pw.accountTransactions[accountUUID]
+	if !ok {
+		return
+	}
+	p, ok := pw.portfolioTransactions[portfolioUUID]
+	if !ok {
+		return
+	}
+
+	if pw.reference("crossEntry", ` class="buysell"`, "crossEntry/"+accountUUID) {
+		return
+	}
+	pw.open("crossEntry", ` class="buysell"`)
+	pw.register("crossEntry/" + accountUUID)
+	pw.portfolio("portfolio", p.owner)
+	pw.transaction("portfolioTransaction", p.transaction, false)
+	pw.account("account", a.owner)
+	pw.transaction("accountTransaction", a.transaction, true)
+	pw.close()
+}
@@ -0,0 +1,276 @@
+package importers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// ppFile is a Portfolio Performance file as saved by the application, whose transactions refer to their security and to their
+// counterpart in the other account by relative paths
+const ppFile = `<?xml version="1.0" encoding="UTF-8"?>
+<client>
+  <version>56</version>
+  <baseCurrency>EUR</baseCurrency>
+  <securities>
+    <security>
+      <uuid>sec-apple</uuid>
+      <name>Apple Inc.</name>
+      <currencyCode>USD</currencyCode>
+      <isin>US0378331005</isin>
+      <tickerSymbol>AAPL</tickerSymbol>
+      <feed>YAHOO</feed>
+      <prices>
+        <price t="2024-01-02" v="18564000000"/>
+        <price t="2024-01-03" v="18432000000"/>
+      </prices>
+      <isRetired>false</isRetired>
+    </security>
+    <security>
+      <uuid>sec-bitcoin</uuid>
+      <name>Bitcoin</name>
+      <currencyCode>EUR</currencyCode>
+      <tickerSymbol>BTC</tickerSymbol>
+      <feed>COINGECKO</feed>
+      <prices/>
+      <isRetired>false</isRetired>
+    </security>
+  </securities>
+  <watchlists/>
+  <accounts>
+    <account>
+      <uuid>acc-cash</uuid>
+      <name>Broker Cash</name>
+      <currencyCode>EUR</currencyCode>
+      <isRetired>false</isRetired>
+      <transactions>
+        <account-transaction>
+          <uuid>tx-buy-cash</uuid>
+          <date>2024-01-02T00:00</date>
+          <currencyCode>EUR</currencyCode>
+          <amount>170500</amount>
+          <security reference="../../../../../securities/security"/>
+          <crossEntry class="buysell">
+            <portfolio>
+              <uuid>pf-main</uuid>
+              <name>Broker Depot</name>
+              <referenceAccount reference="../../../../.."/>
+              <transactions>
+                <portfolio-transaction>
+                  <uuid>tx-buy</uuid>
+                  <date>2024-01-02T00:00</date>
+                  <currencyCode>EUR</currencyCode>
+                  <amount>170500</amount>
+                  <security reference="../../../../../../../../../securities/security"/>
+                  <crossEntry class="buysell" reference="../../../.."/>
+                  <shares>1000000000</shares>
+                  <units>
+                    <unit type="GROSS_VALUE">
+                      <amount currency="EUR" amount="170000"/>
+                      <forex currency="USD" amount="185640"/>
+                      <exchangeRate>0.9157</exchangeRate>
+                    </unit>
+                    <unit type="FEE">
+                      <amount currency="EUR" amount="500"/>
+                    </unit>
+                  </units>
+                  <type>BUY</type>
+                </portfolio-transaction>
+                <portfolio-transaction>
+                  <uuid>tx-btc</uuid>
+                  <date>2024-01-03T10:15:00</date>
+                  <currencyCode>EUR</currencyCode>
+                  <amount>400000</amount>
+                  <security reference="../../../../../../../../../securities/security[2]"/>
+                  <shares>10000000</shares>
+                  <units/>
+                  <type>DELIVERY_INBOUND</type>
+                </portfolio-transaction>
+              </transactions>
+              <isRetired>false</isRetired>
+            </portfolio>
+            <portfolioTransaction reference="../portfolio/transactions/portfolio-transaction"/>
+            <account reference="../../../.."/>
+            <accountTransaction reference="../.."/>
+          </crossEntry>
+          <shares>0</shares>
+          <units/>
+          <type>BUY</type>
+        </account-transaction>
+        <account-transaction>
+          <uuid>tx-div</uuid>
+          <date>2024-02-16T00:00</date>
+          <exDate>2024-02-09T00:00</exDate>
+          <amount>1785</amount>
+          <security reference="../../../../../securities/security"/>
+          <shares>1000000000</shares>
+          <units>
+            <unit type="TAX">
+              <amount currency="EUR" amount="315"/>
+            </unit>
+          </units>
+          <type>DIVIDENDS</type>
+        </account-transaction>
+      </transactions>
+    </account>
+  </accounts>
+  <portfolios>
+    <portfolio reference="../../accounts/account/transactions/account-transaction/crossEntry/portfolio"/>
+  </portfolios>
+  <plans/>
+</client>
+`
+
+// TestPortfolioPerformanceDecode_Ok checks that Decode follows the references of a file and scales its amounts, shares and quotes
+func TestPortfolioPerformanceDecode_Ok(t *testing.T) {
+	// Act
+	client, err := NewPortfolioPerformanceCodec().Decode(strings.NewReader(ppFile))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 56, client.Version)
+	assert.Equal(t, "EUR", client.BaseCurrency)
+
+	assert.Len(t, client.Securities, 2)
+	apple := client.Securities[0]
+	assert.Equal(t, "US0378331005", apple.ISIN)
+	assert.Equal(t, "AAPL", apple.TickerSymbol)
+	assert.Equal(t, "185.64", apple.Prices[0].Value.String())
+	assert.Equal(t, time.Date(2024, time.January, 3, 0, 0, 0, 0, time.UTC), apple.Prices[1].Date)
+	assert.False(t, apple.IsCrypto())
+	assert.True(t, client.Securities[1].IsCrypto())
+
+	assert.Len(t, client.Accounts, 1)
+	account := client.Accounts[0]
+	assert.Len(t, account.Transactions, 2)
+	assert.Equal(t, "sec-apple", account.Transactions[0].Security)
+	assert.Equal(t, "tx-buy", account.Transactions[0].CrossEntry)
+	dividend := account.Transactions[1]
+	assert.Equal(t, entities.PPTransactionDividends, dividend.Type)
+	assert.Equal(t, "17.85 EUR", dividend.Amount.String())
+	assert.Equal(t, "3.15 EUR", dividend.Taxes.String())
+	assert.Equal(t, time.Date(2024, time.February, 9, 0, 0, 0, 0, time.UTC), dividend.ExDate)
+
+	assert.Len(t, client.Portfolios, 1)
+	portfolio := client.Portfolios[0]
+	assert.Equal(t, "Broker Depot", portfolio.Name)
+	assert.Equal(t, "acc-cash", portfolio.ReferenceAccount)
+	assert.Len(t, portfolio.Transactions, 2)
+	buy := portfolio.Transactions[0]
+	assert.Equal(t, entities.PPTransactionBuy, buy.Type)
+	assert.Equal(t, "sec-apple", buy.Security)
+	assert.Equal(t, "10", buy.Shares.String())
+	assert.Equal(t, "1705 EUR", buy.Amount.String())
+	assert.Equal(t, "5 EUR", buy.Fees.String())
+	assert.Equal(t, "1856.4 USD", buy.GrossValue.String())
+	assert.Equal(t, "tx-buy-cash", buy.CrossEntry)
+	delivery := portfolio.Transactions[1]
+	assert.Equal(t, "sec-bitcoin", delivery.Security)
+	assert.Empty(t, delivery.CrossEntry)
+	assert.Equal(t, "0.1", delivery.Shares.String())
+	assert.Equal(t, time.Date(2024, time.January, 3, 0, 0, 0, 0, time.UTC), delivery.Date)
+}
+
+// TestPortfolioPerformanceDecode_IDReferences checks that Decode follows the references of a file saved with ids
+func TestPortfolioPerformanceDecode_IDReferences(t *testing.T) {
+	// Arrange
+	file := `<client id="1"><version>57</version><baseCurrency>USD</baseCurrency>
+<securities id="2"><security id="3"><uuid>sec-ko</uuid><name>Coca-Cola</name><currencyCode>USD</currencyCode></security></securities>
+<accounts id="4"><account id="5"><uuid>acc</uuid><name>Cash</name><currencyCode>USD</currencyCode><transactions id="6"/></account></accounts>
+<portfolios id="7"><portfolio id="8"><uuid>pf</uuid><name>Depot</name><referenceAccount reference="5"/><transactions id="9">
+<portfolio-transaction id="10"><uuid>tx</uuid><date>2024-03-01T00:00</date><currencyCode>USD</currencyCode><amount>12000</amount>
+<security reference="3"/><shares>200000000</shares><type>BUY</type></portfolio-transaction></transactions></portfolio></portfolios></client>`
+
+	// Act
+	client, err := NewPortfolioPerformanceCodec().Decode(strings.NewReader(file))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "acc", client.Portfolios[0].ReferenceAccount)
+	assert.Equal(t, "sec-ko", client.Portfolios[0].Transactions[0].Security)
+	assert.Equal(t, "2", client.Portfolios[0].Transactions[0].Shares.String())
+}
+
+// TestPortfolioPerformanceDecode_InvalidFile checks that Decode returns a validation error for files it cannot read
+func TestPortfolioPerformanceDecode_InvalidFile(t *testing.T) {
+	files := map[string]string{
+		"not xml":     "Date,Type\n",
+		"no client":   "<portfolio><version>57</version></portfolio>",
+		"old version": "<client><version>42</version></client>",
+		"bad amount":  "<client><version>57</version><accounts><account><transactions><account-transaction><date>2024-01-01</date><amount>x</amount></account-transaction></transactions></account></accounts></client>",
+	}
+	for name, file := range files {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			_, err := NewPortfolioPerformanceCodec().Decode(strings.NewReader(file))
+
+			// Assert
+			assert.ErrorIs(t, err, wrappers.ValidationErr)
+		})
+	}
+}
+
+// TestPortfolioPerformanceEncode_Ok checks that Encode writes a file Decode reads back, with references to the securities and accounts
+// and the securities account written inside the first buy settled in its deposit account
+func TestPortfolioPerformanceEncode_Ok(t *testing.T) {
+	// Arrange
+	date := time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC)
+	client := entities.PPClient{
+		BaseCurrency: "USD",
+		Securities: []entities.PPSecurity{
+			{UUID: "sec-1", Name: "Apple & Co", CurrencyCode: "USD", TickerSymbol: "AAPL", Feed: entities.PPFeedManual, Prices: []entities.PPPrice{{Date: date, Value: decimal.RequireFromString("185.64")}}},
+			{UUID: "sec-2", Name: "Coca-Cola", CurrencyCode: "USD", TickerSymbol: "KO", Feed: entities.PPFeedManual},
+		},
+		Accounts: []entities.PPAccount{{
+			UUID: "acc-1", Name: "Cash", CurrencyCode: "USD",
+			Transactions: []entities.PPTransaction{
+				{UUID: "tx-3", Type: entities.PPTransactionBuy, Date: date, Security: "sec-1", Amount: entities.NewMoney(decimal.RequireFromString("1856.4"), "USD"), CrossEntry: "tx-4"},
+				{UUID: "tx-2", Type: entities.PPTransactionDividends, Date: date, Security: "sec-2", Shares: decimal.NewFromInt(10), Amount: entities.NewMoney(decimal.RequireFromString("4.25"), "USD"), Taxes: entities.NewMoney(decimal.RequireFromString("0.75"), "USD")},
+			},
+		}},
+		Portfolios: []entities.PPPortfolio{{
+			UUID: "pf-1", Name: "Depot", ReferenceAccount: "acc-1",
+			Transactions: []entities.PPTransaction{
+				{UUID: "tx-1", Type: entities.PPTransactionDeliveryInbound, Date: date, Security: "sec-2", Shares: decimal.RequireFromString("10.5"), Amount: entities.NewMoney(decimal.RequireFromString("630.33"), "USD")},
+				{UUID: "tx-4", Type: entities.PPTransactionBuy, Date: date, Security: "sec-1", Shares: decimal.NewFromInt(10), Amount: entities.NewMoney(decimal.RequireFromString("1856.4"), "USD"), CrossEntry: "tx-3"},
+			},
+		}},
+	}
+	var buf bytes.Buffer
+
+	// Act
+	err := NewPortfolioPerformanceCodec().Encode(&buf, client)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), "<name>Apple &amp; Co</name>")
+	assert.Contains(t, buf.String(), `<security reference="../../../../../securities/security[2]"/>`)
+	assert.Contains(t, buf.String(), `<referenceAccount reference="../../../../.."/>`)
+	assert.Contains(t, buf.String(), `<crossEntry class="buysell" reference="../../../.."/>`)
+	assert.Contains(t, buf.String(), `<portfolioTransaction reference="../portfolio/transactions/portfolio-transaction[2]"/>`)
+	assert.Contains(t, buf.String(), `<portfolio reference="../../accounts/account/transactions/account-transaction/crossEntry/portfolio"/>`)
+
+	decoded, err := NewPortfolioPerformanceCodec().Decode(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, ppVersion, decoded.Version)
+	assert.Equal(t, "185.64", decoded.Securities[0].Prices[0].Value.String())
+	assert.Equal(t, "acc-1", decoded.Portfolios[0].ReferenceAccount)
+	delivery := decoded.Portfolios[0].Transactions[0]
+	assert.Equal(t, "sec-2", delivery.Security)
+	assert.Equal(t, "10.5", delivery.Shares.String())
+	assert.Equal(t, "630.33 USD", delivery.Amount.String())
+	buy := decoded.Portfolios[0].Transactions[1]
+	assert.Equal(t, entities.PPTransactionBuy, buy.Type)
+	assert.Equal(t, "10", buy.Shares.String())
+	assert.Equal(t, "tx-3", buy.CrossEntry)
+	assert.Equal(t, "tx-4", decoded.Accounts[0].Transactions[0].CrossEntry)
+	dividend := decoded.Accounts[0].Transactions[1]
+	assert.Equal(t, "sec-2", dividend.Security)
+	assert.Equal(t, "0.75 USD", dividend.Taxes.String())
+}
@@ -0,0 +1,111 @@
+package importers
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// revolutDateLayouts are the date layouts of the Revolut exports
+var revolutDateLayouts = []string{"2006-01-02"}
+
+// revolutParser adapter of the parser of the Revolut exports
+type revolutParser struct{}
+
+// NewRevolutParser creates the parser of the Revolut stocks account statement CSV exports, whose amounts are written with the currency
+// code or symbol in front of them. The statements have no ISINs, so instruments are found by their ticker.
+func NewRevolutParser() ports.StatementParser {
+	return &revolutParser{}
+}
+
+func (p *revolutParser) Broker() string {
+	return "revolut"
+}
+
+func (p *revolutParser) Parse(file io.Reader) (statement entities.Statement, err error) {
+	err = readCSV(file, "Revolut", func(r csvRecord) error {
+		if !r.has("date", "type") {
+			return wrappers.NewValidationErr(fmt.Errorf("invalid Revolut statement: Date and Type columns are required"))
+		}
+
+		row, ok, parseErr := p.row(r)
+		switch {
+		case parseErr != nil:
+			statement.Invalid = append(statement.Invalid, invalid(r.line, parseErr))
+		case !ok:
+			statement.Skipped = append(statement.Skipped, skipped(r.line, "type %q not supported", r.field("type")))
+		default:
+			statement.Rows = append(statement.Rows, row)
+		}
+		return nil
+	})
+	return
+}
+
+// row parses a line of the export, reporting false for the types that are not trades, dividends, fees or cash movements, such as
+// the stock splits and the transfers between accounts
+func (p *revolutParser) row(r csvRecord) (row entities.StatementRow, ok bool, err error) {
+	kind := strings.ToUpper(r.field("type"))
+	row = entities.StatementRow{Line: r.line, Symbol: r.field("ticker"), Description: r.field("type")}
+	switch {
+	case strings.HasPrefix(kind, "BUY") || strings.HasPrefix(kind, "SELL"):
+		row.Kind = entities.StatementRowTrade
+	case strings.HasPrefix(kind, "DIVIDEND"):
+		row.Kind = entities.StatementRowDividend
+	case strings.Contains(kind, "TOP-UP") || strings.Contains(kind, "DEPOSIT"):
+		row.Kind = entities.StatementRowDeposit
+	case strings.Contains(kind, "WITHDRAWAL"):
+		row.Kind = entities.StatementRowWithdrawal
+	case strings.Contains(kind, "FEE"):
+		row.Kind = entities.StatementRowFee
+	default:
+		return
+	}
+	ok = true
+
+	if row.Date, err = parseDate(r.field("date"), revolutDateLayouts...); err != nil {
+		return
+	}
+	code := currency(r.field("currency"))
+	total, err := parseNumber(r.field("total amount"), false)
+	if err != nil {
+		return
+	}
+
+	if row.Kind != entities.StatementRowTrade {
+		row.Amount = entities.NewMoney(total.Abs(), code)
+		if row.Kind == entities.StatementRowDividend && row.Symbol == "" {
+			err = fmt.Errorf("ticker cannot be empty")
+			return
+		}
+		if row.Amount.IsZero() {
+			err = fmt.Errorf("total amount cannot be zero")
+		}
+		return
+	}
+
+	if row.Symbol == "" {
+		err = fmt.Errorf("ticker cannot be empty")
+		return
+	}
+	if row.Quantity, err = parseNumber(r.field("quantity"), false); err != nil {
+		return
+	}
+	if row.Quantity.IsZero() {
+		err = fmt.Errorf("quantity cannot be zero")
+		return
+	}
+	price, err := parseNumber(r.field("price per share"), false)
+	if err != nil {
+		return
+	}
+	row.Price = entities.NewMoney(price, code)
+	if strings.HasPrefix(kind, "SELL") {
+		row.Quantity = row.Quantity.Abs().Neg()
+	}
+	return
+}
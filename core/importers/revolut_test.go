@@ -0,0 +1,49 @@
+package importers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestRevolutParse_Ok checks that Parse reads the trades, dividends, fees and cash movements with their currency prefixes and skips the rest
+func TestRevolutParse_Ok(t *testing.T) {
+	// Arrange
+	file := strings.NewReader("Date,Ticker,Type,Quantity,Price per share,Total Amount,Currency,FX Rate\n" +
+		"2024-01-10T09:12:44.120Z,,CASH TOP-UP,,,USD 500,USD,1.09\n" +
+		"2024-01-11T14:30:01.005Z,TSLA,BUY - MARKET,2,USD 232.10,USD 464.20,USD,1.09\n" +
+		"2024-02-11T14:30:01.005Z,TSLA,SELL - LIMIT,1,$250.00,$250.00,USD,1.08\n" +
+		"2024-03-01T10:00:00.000Z,KO,DIVIDEND,,,USD 0.41,USD,1.08\n" +
+		"2024-03-05T10:00:00.000Z,,CUSTODY FEE,,,USD -0.12,USD,1.08\n" +
+		"2024-03-06T10:00:00.000Z,TSLA,STOCK SPLIT,2,,USD 0,USD,1.08\n" +
+		"2024-03-07T10:00:00.000Z,,BUY - MARKET,1,USD 10,USD 10,USD,1.08\n")
+
+	// Act
+	statement, err := NewRevolutParser().Parse(file)
+
+	// Assert
+	assert.Nil(t, err)
+	kinds := []string{}
+	for _, row := range statement.Rows {
+		kinds = append(kinds, row.Kind)
+	}
+	assert.Equal(t, []string{entities.StatementRowDeposit, entities.StatementRowTrade, entities.StatementRowTrade, entities.StatementRowDividend, entities.StatementRowFee}, kinds)
+	assert.Equal(t, "500 USD", statement.Rows[0].Amount.String())
+
+	buy := statement.Rows[1]
+	assert.Equal(t, time.Date(2024, time.January, 11, 0, 0, 0, 0, time.UTC), buy.Date)
+	assert.Equal(t, "TSLA", buy.Symbol)
+	assert.Equal(t, "2", buy.Quantity.String())
+	assert.Equal(t, "232.1 USD", buy.Price.String())
+	assert.Equal(t, "-1", statement.Rows[2].Quantity.String())
+	assert.Equal(t, "250 USD", statement.Rows[2].Price.String())
+	assert.Equal(t, "KO", statement.Rows[3].Symbol)
+	assert.Equal(t, "0.41 USD", statement.Rows[3].Amount.String())
+	assert.Equal(t, "0.12 USD", statement.Rows[4].Amount.String())
+
+	assert.Equal(t, []entities.StatementIssue{{Line: 7, Reason: `type "STOCK SPLIT" not supported`}}, statement.Skipped)
+	assert.Equal(t, []entities.StatementIssue{{Line: 8, Reason: "ticker cannot be empty"}}, statement.Invalid)
+}
@@ -0,0 +1,129 @@
+package importers
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// sharesightDateLayouts are the date layouts of the Sharesight trade files, which write days first outside of the ISO layout
+var sharesightDateLayouts = []string{"2006-01-02", "02/01/2006", "2/1/2006"}
+
+// sharesightMarketCurrencies are the currencies of the trades on the markets of Sharesight, used when the file has no currency column
+var sharesightMarketCurrencies = map[string]string{
+	"NASDAQ": "USD",
+	"NYSE":   "USD",
+	"BATS":   "USD",
+	"OTC":    "USD",
+	"ASX":    "AUD",
+	"NZX":    "NZD",
+	"LSE":    "GBP",
+	"TSX":    "CAD",
+	"TSXV":   "CAD",
+	"HKG":    "HKD",
+	"SGX":    "SGD",
+	"XETRA":  "EUR",
+	"FRA":    "EUR",
+	"PAR":    "EUR",
+	"AMS":    "EUR",
+}
+
+// sharesightParser adapter of the parser of the Sharesight trade files
+type sharesightParser struct{}
+
+// NewSharesightParser creates the parser of the trades exported from Sharesight, either with its trade import template or with its
+// All Trades report. Sharesight is a tracker rather than a broker, so its files are imported into the holding of any broker by
+// choosing its format. Opening balances are imported as buys, and the corporate actions Sharesight records as trades are skipped.
+func NewSharesightParser() ports.StatementParser {
+	return &sharesightParser{}
+}
+
+func (p *sharesightParser) Broker() string {
+	return "sharesight"
+}
+
+func (p *sharesightParser) Parse(file io.Reader) (statement entities.Statement, err error) {
+	err = readCSV(file, "Sharesight", func(r csvRecord) error {
+		if !(r.has("trade date") || r.has("date")) || !(r.has("instrument code") || r.has("code")) || !(r.has("transaction type") || r.has("type")) {
+			return wrappers.NewValidationErr(fmt.Errorf("invalid Sharesight file: Trade Date, Instrument Code and Transaction Type columns are required"))
+		}
+
+		row, ok, parseErr := p.row(r)
+		switch {
+		case parseErr != nil:
+			statement.Invalid = append(statement.Invalid, invalid(r.line, parseErr))
+		case !ok:
+			statement.Skipped = append(statement.Skipped, skipped(r.line, "type %q not supported", r.field("transaction type", "type")))
+		default:
+			statement.Rows = append(statement.Rows, row)
+		}
+		return nil
+	})
+	return
+}
+
+// row parses a line of the file, reporting false for the types that are not buys, sells or opening balances
+func (p *sharesightParser) row(r csvRecord) (row entities.StatementRow, ok bool, err error) {
+	kind := strings.ToUpper(r.field("transaction type", "type"))
+	if kind != "BUY" && kind != "SELL" && kind != "OPENING BALANCE" {
+		return
+	}
+	ok = true
+	row = entities.StatementRow{
+		Line:        r.line,
+		Kind:        entities.StatementRowTrade,
+		Symbol:      strings.ToUpper(r.field("instrument code", "code")),
+		Name:        r.field("name"),
+		Description: r.field("comments", "comments (optional)"),
+	}
+
+	if row.Date, err = parseDate(r.field("trade date", "date"), sharesightDateLayouts...); err != nil {
+		return
+	}
+	if row.Symbol == "" {
+		err = fmt.Errorf("instrument code cannot be empty")
+		return
+	}
+	if row.Quantity, err = parseNumber(r.field("quantity"), false); err != nil {
+		return
+	}
+	if row.Quantity.IsZero() {
+		err = fmt.Errorf("quantity cannot be zero")
+		return
+	}
+	if kind == "SELL" {
+		row.Quantity = row.Quantity.Abs().Neg()
+	}
+
+	market := currency(r.field("market code", "market"))
+	code := currency(r.field("currency"))
+	if code == "" {
+		code = sharesightMarketCurrencies[market]
+	}
+	if code == "" {
+		err = fmt.Errorf("currency of market %q unknown", market)
+		return
+	}
+	price, err := parseNumber(r.field("price in dollars", "price"), false)
+	if err != nil {
+		return
+	}
+	row.Price = entities.NewMoney(price, code)
+
+	brokerage, err := parseNumber(r.field("brokerage", "brokerage (optional)"), false)
+	if err != nil {
+		return
+	}
+	if !brokerage.IsZero() {
+		feeCode := currency(r.field("brokerage currency", "brokerage currency (optional)"))
+		if feeCode == "" {
+			feeCode = code
+		}
+		row.Fee = entities.NewMoney(brokerage.Abs(), feeCode)
+	}
+	return
+}
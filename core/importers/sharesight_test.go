@@ -0,0 +1,71 @@
+package importers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestSharesightParse_ImportTemplate checks that Parse reads the trades of the trade import template and skips the corporate actions
+func TestSharesightParse_ImportTemplate(t *testing.T) {
+	// Arrange
+	file := strings.NewReader("Trade Date,Instrument Code,Market Code,Quantity,Price in Dollars,Transaction Type,Exchange Rate (optional),Brokerage (optional),Brokerage Currency (optional),Comments (optional)\n" +
+		"15/01/2024,CBA,ASX,100,\"1,150.20\",Opening Balance,,,,\n" +
+		"2024-02-01,AAPL,NASDAQ,10,185.5,Buy,1.52,5,USD,First buy\n" +
+		"2024-03-01,AAPL,NASDAQ,4,190,Sell,1.51,,,\n" +
+		"2024-04-01,AAPL,NASDAQ,2,,Split,,,,\n" +
+		"2024-05-01,XYZ,MOON,1,10,Buy,,,,\n")
+
+	// Act
+	statement, err := NewSharesightParser().Parse(file)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, statement.Rows, 3)
+	opening := statement.Rows[0]
+	assert.Equal(t, entities.StatementRowTrade, opening.Kind)
+	assert.Equal(t, time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC), opening.Date)
+	assert.Equal(t, "CBA", opening.Symbol)
+	assert.Equal(t, "1150.2 AUD", opening.Price.String())
+	assert.True(t, opening.Fee.IsZero())
+
+	buy := statement.Rows[1]
+	assert.Equal(t, "10", buy.Quantity.String())
+	assert.Equal(t, "185.5 USD", buy.Price.String())
+	assert.Equal(t, "5 USD", buy.Fee.String())
+	assert.Equal(t, "First buy", buy.Description)
+	assert.Equal(t, "-4", statement.Rows[2].Quantity.String())
+
+	assert.Equal(t, []entities.StatementIssue{{Line: 5, Reason: `type "Split" not supported`}}, statement.Skipped)
+	assert.Equal(t, []entities.StatementIssue{{Line: 6, Reason: `currency of market "MOON" unknown`}}, statement.Invalid)
+}
+
+// TestSharesightParse_AllTradesReport checks that Parse reads the trades of the All Trades report with their currency and name
+func TestSharesightParse_AllTradesReport(t *testing.T) {
+	// Arrange
+	file := strings.NewReader("Market,Code,Name,Type,Date,Quantity,Price,Brokerage,Currency,Exchange Rate,Value\n" +
+		"LSE,VOD,Vodafone Group,Buy,2024-01-10,500,0.69,10,GBP,1,355\n")
+
+	// Act
+	statement, err := NewSharesightParser().Parse(file)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, statement.Rows, 1)
+	assert.Equal(t, "Vodafone Group", statement.Rows[0].Name)
+	assert.Equal(t, "0.69 GBP", statement.Rows[0].Price.String())
+	assert.Equal(t, "10 GBP", statement.Rows[0].Fee.String())
+}
+
+// TestSharesightParse_InvalidHeader checks that Parse returns a validation error when the file has no trade columns
+func TestSharesightParse_InvalidHeader(t *testing.T) {
+	// Act
+	_, err := NewSharesightParser().Parse(strings.NewReader("Date,Amount\n2024-01-10,5\n"))
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.ValidationErr)
+}
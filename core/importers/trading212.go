@@ -0,0 +1,153 @@
+package importers
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// trading212DateLayouts are the date layouts of the Trading 212 exports
+var trading212DateLayouts = []string{"2006-01-02"}
+
+// trading212FeeColumns are the columns of the fees and taxes charged on the trades, each with its currency in a "Currency (name)" column
+var trading212FeeColumns = []string{"currency conversion fee", "transaction fee", "stamp duty reserve tax", "stamp duty", "french transaction tax", "finra fee"}
+
+// trading212Parser adapter of the parser of the Trading 212 exports
+type trading212Parser struct{}
+
+// NewTrading212Parser creates the parser of the Trading 212 history CSV exports. Trades and dividends are priced per share in the currency
+// of the instrument, while their fees, the deposits, withdrawals and interest are in the currency of the account. The tax withheld from
+// a dividend is read as a withholding tax of its stock on its day.
+func NewTrading212Parser() ports.StatementParser {
+	return &trading212Parser{}
+}
+
+func (p *trading212Parser) Broker() string {
+	return "trading212"
+}
+
+func (p *trading212Parser) Parse(file io.Reader) (statement entities.Statement, err error) {
+	err = readCSV(file, "Trading 212", func(r csvRecord) error {
+		if !r.has("action", "time") {
+			return wrappers.NewValidationErr(fmt.Errorf("invalid Trading 212 statement: Action and Time columns are required"))
+		}
+
+		rows, ok, parseErr := p.rows(r)
+		switch {
+		case parseErr != nil:
+			statement.Invalid = append(statement.Invalid, invalid(r.line, parseErr))
+		case !ok:
+			statement.Skipped = append(statement.Skipped, skipped(r.line, "action %q not supported", r.field("action")))
+		default:
+			statement.Rows = append(statement.Rows, rows...)
+		}
+		return nil
+	})
+	return
+}
+
+// rows parses a line of the export into its row and, for the dividends, the tax withheld from them in the withholding tax column
+func (p *trading212Parser) rows(r csvRecord) (rows []entities.StatementRow, ok bool, err error) {
+	row, ok, err := p.row(r)
+	if !ok || err != nil {
+		return
+	}
+	rows = append(rows, row)
+	if row.Kind != entities.StatementRowDividend {
+		return
+	}
+
+	withholding, err := parseNumber(r.field("withholding tax"), false)
+	if err != nil || withholding.IsZero() {
+		return
+	}
+	code := r.field("currency (withholding tax)")
+	if code == "" {
+		code = row.Price.Currency
+	}
+	tax := cashRow(r.line, entities.StatementRowWithholdingTax, row.Date, withholding, code)
+	tax.Symbol, tax.ISIN, tax.Name, tax.Reference = row.Symbol, row.ISIN, row.Name, row.Reference
+	rows = append(rows, tax)
+	return
+}
+
+// row parses a line of the export, reporting false for the actions that are not trades, dividends or cash movements, such as the
+// currency conversions
+func (p *trading212Parser) row(r csvRecord) (row entities.StatementRow, ok bool, err error) {
+	action := strings.ToLower(r.field("action"))
+	row = entities.StatementRow{Line: r.line, Symbol: r.field("ticker"), ISIN: r.field("isin"), Name: r.field("name"), Reference: r.field("id"), Description: r.field("notes")}
+	switch {
+	case strings.HasSuffix(action, "buy") || strings.HasSuffix(action, "sell"):
+		row.Kind = entities.StatementRowTrade
+	case strings.HasPrefix(action, "dividend"):
+		row.Kind = entities.StatementRowDividend
+	case strings.Contains(action, "deposit"):
+		row.Kind = entities.StatementRowDeposit
+	case strings.Contains(action, "withdrawal"):
+		row.Kind = entities.StatementRowWithdrawal
+	case strings.Contains(action, "interest"):
+		row.Kind = entities.StatementRowInterest
+	default:
+		return
+	}
+	ok = true
+
+	if row.Date, err = parseDate(r.field("time"), trading212DateLayouts...); err != nil {
+		return
+	}
+
+	if row.Kind != entities.StatementRowTrade && row.Kind != entities.StatementRowDividend {
+		total, parseErr := parseNumber(r.field("total"), false)
+		if err = parseErr; err != nil {
+			return
+		}
+		row.Amount = entities.NewMoney(total.Abs(), currency(r.field("currency (total)")))
+		if row.Amount.IsZero() {
+			err = fmt.Errorf("total cannot be zero")
+		}
+		return
+	}
+
+	if row.Symbol == "" && row.ISIN == "" {
+		err = fmt.Errorf("ticker or ISIN required")
+		return
+	}
+	if row.Quantity, err = parseNumber(r.field("no. of shares"), false); err != nil {
+		return
+	}
+	if !row.Quantity.IsPositive() {
+		err = fmt.Errorf("number of shares must be positive")
+		return
+	}
+	price, err := parseNumber(r.field("price / share"), false)
+	if err != nil {
+		return
+	}
+	row.Price = entities.NewMoney(price, currency(r.field("currency (price / share)")))
+
+	if row.Kind == entities.StatementRowDividend {
+		row.Amount = row.Price.Mul(row.Quantity)
+		return
+	}
+	if strings.HasSuffix(action, "sell") {
+		row.Quantity = row.Quantity.Neg()
+	}
+	for _, column := range trading212FeeColumns {
+		fee, parseErr := parseNumber(r.field(column), false)
+		if err = parseErr; err != nil {
+			return
+		}
+		if fee.IsZero() {
+			continue
+		}
+		feeCurrency := currency(r.field(fmt.Sprintf("currency (%s)", column)))
+		if row.Fee.IsZero() || row.Fee.Currency == feeCurrency {
+			row.Fee = entities.NewMoney(row.Fee.Amount.Add(fee.Abs()), feeCurrency)
+		}
+	}
+	return
+}
@@ -0,0 +1,58 @@
+package importers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestTrading212Parse_Ok checks that Parse reads the trades with their fees, the dividends with their withholding taxes and the cash
+// movements and skips the rest
+func TestTrading212Parse_Ok(t *testing.T) {
+	// Arrange
+	file := strings.NewReader("Action,Time,ISIN,Ticker,Name,No. of shares,Price / share,Currency (Price / share),Exchange rate,Result,Currency (Result),Total,Currency (Total),Withholding tax,Currency (Withholding tax),Currency conversion fee,Currency (Currency conversion fee),Notes,ID\n" +
+		"Deposit,2024-02-01 08:00:00,,,,,,,,,,1000.00,EUR,,,,,,d-1\n" +
+		"Market buy,2024-02-02 15:45:12,US0378331005,AAPL,Apple,2.5,185.20,USD,1.08,,,428.96,EUR,,,0.64,EUR,,t-1\n" +
+		"Market sell,2024-03-02 15:45:12,US0378331005,AAPL,Apple,1,180.00,USD,1.08,-4.90,EUR,166.02,EUR,,,0.25,EUR,,t-2\n" +
+		"Dividend (Ordinary),2024-05-16 11:02:01,US0378331005,AAPL,Apple,1.5,0.24,USD,1.08,,,0.28,EUR,0.05,USD,,,,\n" +
+		"Currency conversion,2024-05-17 09:00:00,,,,,,,,,,10.00,EUR,,,,,,c-1\n" +
+		"Interest on cash,2024-05-31 23:59:59,,,,,,,,,,1.37,EUR,,,,,,i-1\n" +
+		"Limit buy,2024-06-03 15:45:12,US0378331005,AAPL,Apple,0,185.20,USD,1.08,,,0,EUR,,,,,,t-3\n")
+
+	// Act
+	statement, err := NewTrading212Parser().Parse(file)
+
+	// Assert
+	assert.Nil(t, err)
+	kinds := []string{}
+	for _, row := range statement.Rows {
+		kinds = append(kinds, row.Kind)
+	}
+	assert.Equal(t, []string{entities.StatementRowDeposit, entities.StatementRowTrade, entities.StatementRowTrade, entities.StatementRowDividend, entities.StatementRowWithholdingTax, entities.StatementRowInterest}, kinds)
+	assert.Equal(t, "1000 EUR", statement.Rows[0].Amount.String())
+
+	buy := statement.Rows[1]
+	assert.Equal(t, time.Date(2024, time.February, 2, 0, 0, 0, 0, time.UTC), buy.Date)
+	assert.Equal(t, "AAPL", buy.Symbol)
+	assert.Equal(t, "US0378331005", buy.ISIN)
+	assert.Equal(t, "2.5", buy.Quantity.String())
+	assert.Equal(t, "185.2 USD", buy.Price.String())
+	assert.Equal(t, "0.64 EUR", buy.Fee.String())
+	assert.Equal(t, "-1", statement.Rows[2].Quantity.String())
+
+	dividend := statement.Rows[3]
+	assert.Equal(t, "0.24 USD", dividend.Price.String())
+	assert.Equal(t, "0.36 USD", dividend.Amount.String())
+
+	tax := statement.Rows[4]
+	assert.Equal(t, dividend.Date, tax.Date)
+	assert.Equal(t, "AAPL", tax.Symbol)
+	assert.Equal(t, "US0378331005", tax.ISIN)
+	assert.Equal(t, "0.05 USD", tax.Amount.String())
+
+	assert.Equal(t, []entities.StatementIssue{{Line: 6, Reason: `action "Currency conversion" not supported`}}, statement.Skipped)
+	assert.Equal(t, []entities.StatementIssue{{Line: 8, Reason: "number of shares must be positive"}}, statement.Invalid)
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// AlertRuleResp alert rule response struct
+type AlertRuleResp struct {
+	AlertRuleID    string          `json:"alert_ruleid"`
+	PortfolioID    string          `json:"portfolioid"`
+	InstrumentID   string          `json:"instrumentid"`
+	InstrumentType string          `json:"instrument_type"`
+	Condition      string          `json:"condition"`
+	Threshold      decimal.Decimal `json:"threshold"`
+	Active         bool            `json:"active"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// CreateAlertRuleReq alert rule request struct
+type CreateAlertRuleReq struct {
+	AlertRuleID    string          `json:"-"`
+	PortfolioID    string          `json:"-"`
+	InstrumentID   string          `json:"instrumentid"`
+	InstrumentType string          `json:"instrument_type"`
+	Condition      string          `json:"condition"`
+	Threshold      decimal.Decimal `json:"threshold"`
+	Active         bool            `json:"-"`
+	CreatedAt      time.Time       `json:"-"`
+	UpdatedAt      time.Time       `json:"-"`
+}
+
+// Validate checks that a given CreateAlertRuleReq is valid
+func (req CreateAlertRuleReq) Validate() error {
+	var msgs []string
+
+	msgs = append(msgs, instrumentMsgs(req.InstrumentID, req.InstrumentType)...)
+	msgs = append(msgs, alertConditionMsgs(req.Condition, req.Threshold)...)
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// UpdateAlertRuleReq update alert rule request struct
+type UpdateAlertRuleReq struct {
+	Condition *string          `json:"condition"`
+	Threshold *decimal.Decimal `json:"threshold"`
+	Active    *bool            `json:"active"`
+}
+
+// AlertResp alert response struct
+type AlertResp struct {
+	AlertID        string          `json:"alertid"`
+	AlertRuleID    string          `json:"alert_ruleid"`
+	PortfolioID    string          `json:"portfolioid"`
+	InstrumentID   string          `json:"instrumentid"`
+	InstrumentType string          `json:"instrument_type"`
+	Condition      string          `json:"condition"`
+	Threshold      decimal.Decimal `json:"threshold"`
+	Price          entities.Money  `json:"price"`
+	Value          decimal.Decimal `json:"value"`
+	TriggerDate    time.Time       `json:"trigger_date"`
+	TriggeredAt    time.Time       `json:"triggered_at"`
+	NotifiedAt     time.Time       `json:"notified_at"`
+}
+
+// AlertEvaluationResp alert evaluation response struct
+type AlertEvaluationResp struct {
+	Rules       int `json:"rules"`
+	Triggered   int `json:"triggered"`
+	Notified    int `json:"notified"`
+	Undelivered int `json:"undelivered"`
+}
+
+// alertConditionMsgs returns the validation messages of an alert condition and its threshold
+func alertConditionMsgs(condition string, threshold decimal.Decimal) []string {
+	switch condition {
+	case entities.AlertConditionAbove, entities.AlertConditionBelow:
+		if !threshold.IsPositive() {
+			return []string{"threshold must be a positive price"}
+		}
+	case entities.AlertConditionDayMove, entities.AlertConditionCostBasisMove:
+		if threshold.IsZero() {
+			return []string{"threshold must be a non-zero percentage"}
+		}
+	default:
+		return []string{fmt.Sprintf("condition must be one of %s, %s, %s or %s", entities.AlertConditionAbove, entities.AlertConditionBelow, entities.AlertConditionDayMove, entities.AlertConditionCostBasisMove)}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestValidateCreateAlertRuleReq_Ok checks that Validate does not return an error when valid requests are received
+func TestValidateCreateAlertRuleReq_Ok(t *testing.T) {
+	// Arrange
+	reqs := []CreateAlertRuleReq{
+		{InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Condition: entities.AlertConditionAbove, Threshold: decimal.NewFromInt(200)},
+		{InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Condition: entities.AlertConditionBelow, Threshold: decimal.RequireFromString("150.5")},
+		{InstrumentID: "crypto-a", InstrumentType: entities.EntityNameCryptoCurrency, Condition: entities.AlertConditionDayMove, Threshold: decimal.NewFromInt(-5)},
+		{InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Condition: entities.AlertConditionCostBasisMove, Threshold: decimal.NewFromInt(20)},
+	}
+
+	for _, req := range reqs {
+		// Act
+		err := req.Validate()
+
+		// Assert
+		assert.Nil(t, err, req.Condition)
+	}
+}
+
+// TestValidateCreateAlertRuleReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateCreateAlertRuleReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := CreateAlertRuleReq{}
+	expectedError := "instrument id cannot be empty | instrument type must be stock or cryptocurrency | condition must be one of above, below, day_move or cost_basis_move"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidateCreateAlertRuleReq_InvalidThreshold checks that Validate returns an error when the threshold does not suit the condition
+func TestValidateCreateAlertRuleReq_InvalidThreshold(t *testing.T) {
+	// Arrange
+	cases := map[string]string{
+		entities.AlertConditionAbove:         "threshold must be a positive price",
+		entities.AlertConditionBelow:         "threshold must be a positive price",
+		entities.AlertConditionDayMove:       "threshold must be a non-zero percentage",
+		entities.AlertConditionCostBasisMove: "threshold must be a non-zero percentage",
+	}
+
+	for condition, expectedError := range cases {
+		req := CreateAlertRuleReq{InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Condition: condition}
+
+		// Act
+		err := req.Validate()
+
+		// Assert
+		assert.NotEmpty(t, err, condition)
+		assert.IsType(t, wrappers.ValidationErr, err)
+		assert.Equal(t, expectedError, err.Error())
+	}
+}
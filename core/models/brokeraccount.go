@@ -0,0 +1,133 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// brokerAccountNumberVisible is how many trailing characters of an account number are left unmasked
+const brokerAccountNumberVisible = 4
+
+// BrokerAccountResp broker account response struct
+type BrokerAccountResp struct {
+	BrokerAccountID string    `json:"broker_accountid"`
+	UserID          string    `json:"userid"`
+	BrokerID        string    `json:"brokerid"`
+	AccountLabel    string    `json:"account_label"`
+	AccountNumber   string    `json:"account_number"`
+	BaseCurrency    string    `json:"base_currency"`
+	OpenedAt        time.Time `json:"opened_at"`
+	ClosedAt        time.Time `json:"closed_at"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// CreateBrokerAccountReq broker account request struct. AccountNumber is masked before it is stored, so only its last characters
+// are kept.
+type CreateBrokerAccountReq struct {
+	BrokerAccountID string    `json:"-"`
+	UserID          string    `json:"-"`
+	BrokerID        string    `json:"brokerid"`
+	AccountLabel    string    `json:"account_label"`
+	AccountNumber   string    `json:"account_number"`
+	BaseCurrency    string    `json:"base_currency"`
+	OpenedAt        time.Time `json:"opened_at"`
+	ClosedAt        time.Time `json:"closed_at"`
+	CreatedAt       time.Time `json:"-"`
+	UpdatedAt       time.Time `json:"-"`
+}
+
+// Validate checks that a given CreateBrokerAccountReq is valid
+func (req CreateBrokerAccountReq) Validate() error {
+	var msgs []string
+
+	if req.BrokerID == "" {
+		msgs = append(msgs, "broker id cannot be empty")
+	}
+	msgs = append(msgs, brokerAccountMsgs(req.AccountLabel, req.BaseCurrency, req.OpenedAt, req.ClosedAt)...)
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// UpdateBrokerAccountReq update broker account request struct. The broker of an account cannot be changed, and an empty account
+// number keeps the one stored.
+type UpdateBrokerAccountReq struct {
+	AccountLabel  string    `json:"account_label"`
+	AccountNumber string    `json:"account_number"`
+	BaseCurrency  string    `json:"base_currency"`
+	OpenedAt      time.Time `json:"opened_at"`
+	ClosedAt      time.Time `json:"closed_at"`
+}
+
+// Validate checks that a given UpdateBrokerAccountReq is valid
+func (req UpdateBrokerAccountReq) Validate() error {
+	msgs := brokerAccountMsgs(req.AccountLabel, req.BaseCurrency, req.OpenedAt, req.ClosedAt)
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// BrokerAccountValuationResp broker account valuation response struct. The positions are those of the holdings attached to the
+// account, and the totals, the cash and the total value are in the base currency of the account.
+type BrokerAccountValuationResp struct {
+	BrokerAccountID   string            `json:"broker_accountid"`
+	BrokerID          string            `json:"brokerid"`
+	AccountLabel      string            `json:"account_label"`
+	CostBasisMethod   int               `json:"cost_basis_method"`
+	BaseCurrency      string            `json:"base_currency"`
+	Positions         []PositionResp    `json:"positions"`
+	CostBasis         entities.Money    `json:"cost_basis"`
+	MarketValue       entities.Money    `json:"market_value"`
+	UnrealizedGain    entities.Money    `json:"unrealized_gain"`
+	UnrealizedGainPct float64           `json:"unrealized_gain_pct"`
+	RealizedGain      entities.Money    `json:"realized_gain"`
+	Cash              []CashBalanceResp `json:"cash"`
+	CashValue         entities.Money    `json:"cash_value"`
+	TotalValue        entities.Money    `json:"total_value"`
+	ValuedAt          time.Time         `json:"valued_at"`
+}
+
+// MaskAccountNumber returns an account number with all but its last characters replaced by asterisks, ignoring spaces. Numbers too
+// short to keep any character are masked entirely.
+func MaskAccountNumber(number string) string {
+	number = strings.Join(strings.Fields(number), "")
+	runes := []rune(number)
+	if len(runes) == 0 {
+		return ""
+	}
+
+	visible := brokerAccountNumberVisible
+	if len(runes) <= visible {
+		visible = 0
+	}
+	return strings.Repeat("*", len(runes)-visible) + string(runes[len(runes)-visible:])
+}
+
+// brokerAccountMsgs returns the validation messages of the fields shared by the broker account requests
+func brokerAccountMsgs(label, baseCurrency string, openedAt, closedAt time.Time) []string {
+	var msgs []string
+
+	if strings.TrimSpace(label) == "" {
+		msgs = append(msgs, "account label cannot be empty")
+	}
+	msgs = append(msgs, currencyCodeMsgs("base currency", baseCurrency)...)
+	if openedAt == (time.Time{}) {
+		msgs = append(msgs, "opened date cannot be empty")
+	}
+	if closedAt != (time.Time{}) && closedAt.Before(openedAt) {
+		msgs = append(msgs, "closed date cannot be before opened date")
+	}
+
+	return msgs
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateCreateBrokerAccountReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateCreateBrokerAccountReq_Ok(t *testing.T) {
+	// Arrange
+	openedAt := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	reqs := []CreateBrokerAccountReq{
+		{BrokerID: "broker-a", AccountLabel: "ISA", BaseCurrency: "GBP", OpenedAt: openedAt},
+		{BrokerID: "broker-a", AccountLabel: "Old", AccountNumber: "DE89 3704 0044", BaseCurrency: "EUR", OpenedAt: openedAt, ClosedAt: openedAt.AddDate(2, 0, 0)},
+	}
+
+	for _, req := range reqs {
+		// Act
+		err := req.Validate()
+
+		// Assert
+		assert.Nil(t, err, req.AccountLabel)
+	}
+}
+
+// TestValidateCreateBrokerAccountReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateCreateBrokerAccountReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := CreateBrokerAccountReq{BaseCurrency: "eur", ClosedAt: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)}
+	expectedError := "broker id cannot be empty | account label cannot be empty | base currency must be a 3-letter ISO 4217 code | opened date cannot be empty"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidateUpdateBrokerAccountReq_ClosedBeforeOpened checks that Validate returns an error when the account closes before it opens
+func TestValidateUpdateBrokerAccountReq_ClosedBeforeOpened(t *testing.T) {
+	// Arrange
+	openedAt := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	req := UpdateBrokerAccountReq{AccountLabel: "ISA", BaseCurrency: "GBP", OpenedAt: openedAt, ClosedAt: openedAt.AddDate(0, 0, -1)}
+	expectedError := "closed date cannot be before opened date"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestMaskAccountNumber_Ok checks that MaskAccountNumber keeps only the last characters of an account number
+func TestMaskAccountNumber_Ok(t *testing.T) {
+	// Arrange
+	cases := map[string]string{
+		"":                  "",
+		"12345678":          "****5678",
+		"DE89 3704 0044 05": "**********4405",
+		"123":               "***",
+	}
+
+	for number, expected := range cases {
+		// Act
+		masked := MaskAccountNumber(number)
+
+		// Assert
+		assert.Equal(t, expected, masked, number)
+	}
+}
@@ -0,0 +1,7 @@
+package models
+
+// CalendarTokenResp calendar feed token response struct
+type CalendarTokenResp struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
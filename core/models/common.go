@@ -1,5 +1,11 @@
 package models
 
+import (
+	"fmt"
+
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
 // CreationResp creation response struct
 type CreationResp struct {
 	InsertedID string `json:"inserted_id"`
@@ -9,3 +15,52 @@ type CreationResp struct {
 type MultiCreationResp struct {
 	InsertedIDs []string `json:"inserted_ids"`
 }
+
+// moneyMsgs returns the validation messages of a required amount of money
+func moneyMsgs(field string, m entities.Money) []string {
+	var msgs []string
+
+	if m.IsZero() {
+		msgs = append(msgs, fmt.Sprintf("%s cannot be zero", field))
+	}
+	msgs = append(msgs, currencyCodeMsgs(field+" currency", m.Currency)...)
+
+	return msgs
+}
+
+// currencyCodeMsgs returns the validation messages of a required currency code
+func currencyCodeMsgs(field, code string) []string {
+	if code == "" {
+		return []string{fmt.Sprintf("%s cannot be empty", field)}
+	}
+	if !validCurrencyCode(code) {
+		return []string{fmt.Sprintf("%s must be a 3-letter ISO 4217 code", field)}
+	}
+	return nil
+}
+
+// validCurrencyCode reports whether code looks like an ISO 4217 currency code
+func validCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// validCountryCode reports whether code looks like an ISO 3166-1 alpha-2 country code
+func validCountryCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
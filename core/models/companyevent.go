@@ -0,0 +1,111 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// CompanyEventResp company event response struct
+type CompanyEventResp struct {
+	CompanyEventID   string          `json:"company_eventid"`
+	StockID          string          `json:"stockid"`
+	EventType        string          `json:"event_type"`
+	EventDate        time.Time       `json:"event_date"`
+	Amount           entities.Money  `json:"amount"`
+	SplitNumerator   decimal.Decimal `json:"split_numerator"`
+	SplitDenominator decimal.Decimal `json:"split_denominator"`
+	Description      string          `json:"description"`
+	Source           string          `json:"source"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// CreateCompanyEventReq company event request struct
+type CreateCompanyEventReq struct {
+	CompanyEventID   string          `json:"-"`
+	StockID          string          `json:"-"`
+	EventType        string          `json:"event_type"`
+	EventDate        time.Time       `json:"event_date"`
+	Amount           entities.Money  `json:"amount"`
+	SplitNumerator   decimal.Decimal `json:"split_numerator"`
+	SplitDenominator decimal.Decimal `json:"split_denominator"`
+	Description      string          `json:"description"`
+	Source           string          `json:"source"`
+	CreatedAt        time.Time       `json:"-"`
+	UpdatedAt        time.Time       `json:"-"`
+}
+
+// Validate checks that a given CreateCompanyEventReq is valid
+func (req CreateCompanyEventReq) Validate() error {
+	var msgs []string
+
+	if req.StockID == "" {
+		msgs = append(msgs, "stock id cannot be empty")
+	}
+	if req.EventDate == (time.Time{}) {
+		msgs = append(msgs, "event date cannot be empty")
+	}
+
+	switch req.EventType {
+	case entities.CompanyEventEarnings, entities.CompanyEventDelisting:
+	case entities.CompanyEventExDividend, entities.CompanyEventDividendPayment:
+		if !req.Amount.Amount.IsZero() || req.Amount.Currency != "" {
+			if !req.Amount.Amount.IsPositive() {
+				msgs = append(msgs, "amount must be positive")
+			}
+			msgs = append(msgs, currencyCodeMsgs("amount currency", req.Amount.Currency)...)
+		}
+	case entities.CompanyEventSplit:
+		if !req.SplitNumerator.IsPositive() || !req.SplitDenominator.IsPositive() {
+			msgs = append(msgs, "split numerator and denominator must be positive")
+		}
+	default:
+		msgs = append(msgs, fmt.Sprintf("event type must be one of %s", strings.Join(companyEventTypes, ", ")))
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+	return nil
+}
+
+// companyEventTypes are the valid company event types
+var companyEventTypes = []string{entities.CompanyEventEarnings, entities.CompanyEventExDividend, entities.CompanyEventDividendPayment, entities.CompanyEventSplit, entities.CompanyEventDelisting}
+
+// CompanyEventsReq company events query struct, both dates included and unbounded when empty
+type CompanyEventsReq struct {
+	From time.Time
+	To   time.Time
+}
+
+// Validate checks that a given CompanyEventsReq is valid
+func (req CompanyEventsReq) Validate() error {
+	if !req.From.IsZero() && !req.To.IsZero() && req.To.Before(req.From) {
+		return wrappers.NewValidationErr(fmt.Errorf("to cannot be before from"))
+	}
+	return nil
+}
+
+// CompanyEventImportResp company event import response struct
+type CompanyEventImportResp struct {
+	Imported int `json:"imported"`
+}
+
+// CompanyEventSyncResp company event sync response struct
+type CompanyEventSyncResp struct {
+	Stocks int `json:"stocks"`
+	Events int `json:"events"`
+	Failed int `json:"failed"`
+}
+
+// CompanyEventNotifyResp company event notification run response struct
+type CompanyEventNotifyResp struct {
+	Events      int `json:"events"`
+	Notified    int `json:"notified"`
+	Undelivered int `json:"undelivered"`
+}
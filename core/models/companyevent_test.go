@@ -0,0 +1,80 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestValidateCreateCompanyEventReq_Ok checks that Validate does not return an error when valid requests are received
+func TestValidateCreateCompanyEventReq_Ok(t *testing.T) {
+	// Arrange
+	date := time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC)
+	reqs := []CreateCompanyEventReq{
+		{StockID: "stock-a", EventType: entities.CompanyEventEarnings, EventDate: date},
+		{StockID: "stock-a", EventType: entities.CompanyEventExDividend, EventDate: date, Amount: entities.NewMoney(decimal.RequireFromString("0.24"), "USD")},
+		{StockID: "stock-a", EventType: entities.CompanyEventDividendPayment, EventDate: date},
+		{StockID: "stock-a", EventType: entities.CompanyEventSplit, EventDate: date, SplitNumerator: decimal.NewFromInt(4), SplitDenominator: decimal.NewFromInt(1)},
+		{StockID: "stock-a", EventType: entities.CompanyEventDelisting, EventDate: date},
+	}
+
+	for _, req := range reqs {
+		// Act
+		err := req.Validate()
+
+		// Assert
+		assert.Nil(t, err, req.EventType)
+	}
+}
+
+// TestValidateCreateCompanyEventReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateCreateCompanyEventReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := CreateCompanyEventReq{}
+	expectedError := "stock id cannot be empty | event date cannot be empty | event type must be one of earnings, ex_dividend, dividend_payment, split, delisting"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidateCreateCompanyEventReq_InvalidDetails checks that Validate returns an error when the amount of a dividend or the ratio of a split is not valid
+func TestValidateCreateCompanyEventReq_InvalidDetails(t *testing.T) {
+	// Arrange
+	date := time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC)
+	cases := map[string]CreateCompanyEventReq{
+		"amount must be positive | amount currency cannot be empty": {StockID: "stock-a", EventType: entities.CompanyEventExDividend, EventDate: date, Amount: entities.NewMoney(decimal.NewFromInt(-1), "")},
+		"split numerator and denominator must be positive":          {StockID: "stock-a", EventType: entities.CompanyEventSplit, EventDate: date, SplitNumerator: decimal.NewFromInt(2)},
+		"amount currency must be a 3-letter ISO 4217 code":          {StockID: "stock-a", EventType: entities.CompanyEventDividendPayment, EventDate: date, Amount: entities.NewMoney(decimal.NewFromInt(1), "DOLLAR")},
+	}
+
+	for expectedError, req := range cases {
+		// Act
+		err := req.Validate()
+
+		// Assert
+		assert.IsType(t, wrappers.ValidationErr, err)
+		assert.Equal(t, expectedError, err.Error())
+	}
+}
+
+// TestValidateCompanyEventsReq_InvalidRange checks that Validate returns an error when the range ends before it starts
+func TestValidateCompanyEventsReq_InvalidRange(t *testing.T) {
+	// Arrange
+	req := CompanyEventsReq{From: time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC), To: time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC)}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, "to cannot be before from", err.Error())
+}
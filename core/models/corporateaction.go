@@ -0,0 +1,134 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// CorporateActionResp corporate action response struct
+type CorporateActionResp struct {
+	CorporateActionID string          `json:"corporate_actionid"`
+	StockID           string          `json:"stockid"`
+	ActionType        string          `json:"action_type"`
+	EffectiveDate     time.Time       `json:"effective_date"`
+	Numerator         decimal.Decimal `json:"numerator"`
+	Denominator       decimal.Decimal `json:"denominator"`
+	TargetStockID     string          `json:"target_stockid,omitempty"`
+	CostAllocation    decimal.Decimal `json:"cost_allocation"`
+	OldSymbol         string          `json:"old_symbol,omitempty"`
+	NewSymbol         string          `json:"new_symbol,omitempty"`
+	NewISIN           string          `json:"new_isin,omitempty"`
+	Description       string          `json:"description"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
+
+// CreateCorporateActionReq corporate action request struct
+type CreateCorporateActionReq struct {
+	CorporateActionID string          `json:"-"`
+	StockID           string          `json:"-"`
+	ActionType        string          `json:"action_type"`
+	EffectiveDate     time.Time       `json:"effective_date"`
+	Numerator         decimal.Decimal `json:"numerator"`
+	Denominator       decimal.Decimal `json:"denominator"`
+	TargetStockID     string          `json:"target_stockid"`
+	CostAllocation    decimal.Decimal `json:"cost_allocation"`
+	OldSymbol         string          `json:"-"`
+	NewSymbol         string          `json:"new_symbol"`
+	NewISIN           string          `json:"new_isin"`
+	Description       string          `json:"description"`
+	CreatedAt         time.Time       `json:"-"`
+	UpdatedAt         time.Time       `json:"-"`
+}
+
+// Validate checks that a given CreateCorporateActionReq is valid
+func (req CreateCorporateActionReq) Validate() error {
+	var msgs []string
+
+	if req.StockID == "" {
+		msgs = append(msgs, "stock id cannot be empty")
+	}
+	if req.EffectiveDate == (time.Time{}) {
+		msgs = append(msgs, "effective date cannot be empty")
+	}
+
+	ratioMsgs := func() []string {
+		if !req.Numerator.IsPositive() || !req.Denominator.IsPositive() {
+			return []string{"numerator and denominator must be positive"}
+		}
+		return nil
+	}
+	targetMsgs := func() []string {
+		if req.TargetStockID == "" {
+			return []string{"target stock id cannot be empty"}
+		}
+		if req.TargetStockID == req.StockID {
+			return []string{"target stock id must be another stock"}
+		}
+		return nil
+	}
+
+	switch req.ActionType {
+	case entities.CorporateActionSplit:
+		msgs = append(msgs, ratioMsgs()...)
+		if req.Numerator.LessThanOrEqual(req.Denominator) {
+			msgs = append(msgs, "a split must have a numerator greater than its denominator")
+		}
+	case entities.CorporateActionReverseSplit:
+		msgs = append(msgs, ratioMsgs()...)
+		if req.Numerator.GreaterThanOrEqual(req.Denominator) {
+			msgs = append(msgs, "a reverse split must have a numerator lower than its denominator")
+		}
+	case entities.CorporateActionSymbolChange:
+		if strings.TrimSpace(req.NewSymbol) == "" && strings.TrimSpace(req.NewISIN) == "" {
+			msgs = append(msgs, "new symbol or new isin cannot be empty")
+		}
+		if req.NewISIN != "" && !validISIN(req.NewISIN) {
+			msgs = append(msgs, "new isin must be 2 letters followed by 9 letters or digits and a check digit")
+		}
+		if req.EffectiveDate.After(time.Now().UTC()) {
+			msgs = append(msgs, "effective date of a symbol change cannot be in the future")
+		}
+	case entities.CorporateActionMerger:
+		msgs = append(msgs, ratioMsgs()...)
+		msgs = append(msgs, targetMsgs()...)
+	case entities.CorporateActionSpinOff:
+		msgs = append(msgs, ratioMsgs()...)
+		msgs = append(msgs, targetMsgs()...)
+		if !req.CostAllocation.IsPositive() || req.CostAllocation.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+			msgs = append(msgs, "cost allocation must be greater than 0 and lower than 1")
+		}
+	default:
+		msgs = append(msgs, fmt.Sprintf("action type must be one of %s", strings.Join(corporateActionTypes, ", ")))
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+	return nil
+}
+
+// corporateActionTypes are the valid corporate action types
+var corporateActionTypes = []string{entities.CorporateActionSplit, entities.CorporateActionReverseSplit, entities.CorporateActionSymbolChange, entities.CorporateActionMerger, entities.CorporateActionSpinOff}
+
+// validISIN reports whether value has the shape of an ISIN: a country code, nine alphanumeric characters and a check digit
+func validISIN(value string) bool {
+	if len(value) != 12 {
+		return false
+	}
+	for i, c := range value {
+		switch {
+		case i < 2 && c >= 'A' && c <= 'Z':
+		case i >= 2 && i < 11 && (c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'):
+		case i == 11 && c >= '0' && c <= '9':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestValidateCreateCorporateActionReq_Ok checks that Validate does not return an error when valid requests are received
+func TestValidateCreateCorporateActionReq_Ok(t *testing.T) {
+	// Arrange
+	date := time.Date(2024, time.June, 10, 0, 0, 0, 0, time.UTC)
+	reqs := []CreateCorporateActionReq{
+		{StockID: "stock-a", ActionType: entities.CorporateActionSplit, EffectiveDate: date, Numerator: decimal.NewFromInt(10), Denominator: decimal.NewFromInt(1)},
+		{StockID: "stock-a", ActionType: entities.CorporateActionReverseSplit, EffectiveDate: date, Numerator: decimal.NewFromInt(1), Denominator: decimal.NewFromInt(20)},
+		{StockID: "stock-a", ActionType: entities.CorporateActionSymbolChange, EffectiveDate: date, NewSymbol: "META"},
+		{StockID: "stock-a", ActionType: entities.CorporateActionSymbolChange, EffectiveDate: date, NewISIN: "US30303M1027"},
+		{StockID: "stock-a", ActionType: entities.CorporateActionMerger, EffectiveDate: date, Numerator: decimal.RequireFromString("0.5"), Denominator: decimal.NewFromInt(1), TargetStockID: "stock-b"},
+		{StockID: "stock-a", ActionType: entities.CorporateActionSpinOff, EffectiveDate: date, Numerator: decimal.NewFromInt(1), Denominator: decimal.NewFromInt(3), TargetStockID: "stock-b", CostAllocation: decimal.RequireFromString("0.2")},
+	}
+
+	for _, req := range reqs {
+		// Act
+		err := req.Validate()
+
+		// Assert
+		assert.Nil(t, err, req.ActionType)
+	}
+}
+
+// TestValidateCreateCorporateActionReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateCreateCorporateActionReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := CreateCorporateActionReq{}
+	expectedError := "stock id cannot be empty | effective date cannot be empty | action type must be one of split, reverse_split, symbol_change, merger, spin_off"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidateCreateCorporateActionReq_InvalidDetails checks that Validate returns an error when the details of the action do not match its type
+func TestValidateCreateCorporateActionReq_InvalidDetails(t *testing.T) {
+	// Arrange
+	date := time.Date(2024, time.June, 10, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		req           CreateCorporateActionReq
+		expectedError string
+	}{
+		{CreateCorporateActionReq{StockID: "stock-a", ActionType: entities.CorporateActionSplit, EffectiveDate: date, Numerator: decimal.NewFromInt(1), Denominator: decimal.NewFromInt(2)}, "a split must have a numerator greater than its denominator"},
+		{CreateCorporateActionReq{StockID: "stock-a", ActionType: entities.CorporateActionReverseSplit, EffectiveDate: date, Numerator: decimal.NewFromInt(2), Denominator: decimal.NewFromInt(1)}, "a reverse split must have a numerator lower than its denominator"},
+		{CreateCorporateActionReq{StockID: "stock-a", ActionType: entities.CorporateActionSymbolChange, EffectiveDate: time.Now().UTC().AddDate(0, 0, 7)}, "new symbol or new isin cannot be empty | effective date of a symbol change cannot be in the future"},
+		{CreateCorporateActionReq{StockID: "stock-a", ActionType: entities.CorporateActionSymbolChange, EffectiveDate: date, NewISIN: "US123"}, "new isin must be 2 letters followed by 9 letters or digits and a check digit"},
+		{CreateCorporateActionReq{StockID: "stock-a", ActionType: entities.CorporateActionMerger, EffectiveDate: date, TargetStockID: "stock-a"}, "numerator and denominator must be positive | target stock id must be another stock"},
+		{CreateCorporateActionReq{StockID: "stock-a", ActionType: entities.CorporateActionSpinOff, EffectiveDate: date, Numerator: decimal.NewFromInt(1), Denominator: decimal.NewFromInt(1), CostAllocation: decimal.NewFromInt(1)}, "target stock id cannot be empty | cost allocation must be greater than 0 and lower than 1"},
+	}
+
+	for _, c := range cases {
+		// Act
+		err := c.req.Validate()
+
+		// Assert
+		assert.IsType(t, wrappers.ValidationErr, err, c.req.ActionType)
+		assert.Equal(t, c.expectedError, err.Error())
+	}
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// DigestReq portfolio digest query struct
+type DigestReq struct {
+	Period string `json:"period"`
+}
+
+// Validate checks that a given DigestReq is valid
+func (req DigestReq) Validate() error {
+	var msgs []string
+
+	if req.Period != "" && !ValidDigestPeriod(req.Period) {
+		msgs = append(msgs, fmt.Sprintf("period must be one of %s", strings.Join(digestPeriods, ", ")))
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+	return nil
+}
+
+// digestPeriods are the valid digest periods
+var digestPeriods = []string{entities.DigestPeriodWeekly, entities.DigestPeriodMonthly}
+
+// ValidDigestPeriod reports whether period is one of the digest periods
+func ValidDigestPeriod(period string) bool {
+	for _, p := range digestPeriods {
+		if p == period {
+			return true
+		}
+	}
+	return false
+}
+
+// DigestResp portfolio summary digest response struct
+type DigestResp struct {
+	PortfolioID      string            `json:"portfolioid"`
+	PortfolioName    string            `json:"portfolio_name"`
+	Period           string            `json:"period"`
+	From             time.Time         `json:"from"`
+	To               time.Time         `json:"to"`
+	StartValue       entities.Money    `json:"start_value"`
+	EndValue         entities.Money    `json:"end_value"`
+	ValueChange      entities.Money    `json:"value_change"`
+	ValueChangePct   float64           `json:"value_change_pct"`
+	NetContributions entities.Money    `json:"net_contributions"`
+	ReturnPct        float64           `json:"return_pct"`
+	Dividends        entities.Money    `json:"dividends"`
+	TopMovers        []DigestMoverResp `json:"top_movers"`
+	UpcomingEvents   []DigestEventResp `json:"upcoming_events"`
+}
+
+// DigestMoverResp price move of an instrument held at the end of a digest period
+type DigestMoverResp struct {
+	InstrumentID   string          `json:"instrumentid"`
+	InstrumentType string          `json:"instrument_type"`
+	Symbol         string          `json:"symbol"`
+	Quantity       decimal.Decimal `json:"quantity"`
+	StartPrice     entities.Money  `json:"start_price"`
+	EndPrice       entities.Money  `json:"end_price"`
+	ChangePct      decimal.Decimal `json:"change_pct"`
+	ValueChange    entities.Money  `json:"value_change"`
+}
+
+// DigestEventResp upcoming event of a stock held by a portfolio
+type DigestEventResp struct {
+	StockID     string         `json:"stockid"`
+	Symbol      string         `json:"symbol"`
+	EventType   string         `json:"event_type"`
+	EventDate   time.Time      `json:"event_date"`
+	Amount      entities.Money `json:"amount"`
+	Description string         `json:"description"`
+}
+
+// DigestSendResp outcome of a digest run
+type DigestSendResp struct {
+	Portfolios  int `json:"portfolios"`
+	Sent        int `json:"sent"`
+	Undelivered int `json:"undelivered"`
+}
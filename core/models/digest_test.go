@@ -0,0 +1,42 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestValidateDigestReq_Ok checks that Validate does not return an error when valid requests are received
+func TestValidateDigestReq_Ok(t *testing.T) {
+	// Arrange
+	reqs := []DigestReq{
+		{},
+		{Period: entities.DigestPeriodWeekly},
+		{Period: entities.DigestPeriodMonthly},
+	}
+
+	for _, req := range reqs {
+		// Act
+		err := req.Validate()
+
+		// Assert
+		assert.Nil(t, err, req.Period)
+	}
+}
+
+// TestValidateDigestReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateDigestReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := DigestReq{Period: "daily"}
+	expectedError := "period must be one of weekly, monthly"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
@@ -6,26 +6,31 @@ import (
 	"time"
 
 	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
 )
 
 // DividendResp dividend response struct
 type DividendResp struct {
-	DividendID       string    `json:"-"`
-	StockID          string    `json:"stockid"`
-	DividendPerShare int       `json:"dividend_per_share"`
-	DividendDate     time.Time `json:"dividend_date"`
-	CreatedAt        time.Time `json:"-"`
-	UpdatedAt        time.Time `json:"-"`
+	DividendID       string         `json:"-"`
+	StockID          string         `json:"stockid"`
+	DividendPerShare entities.Money `json:"dividend_per_share"`
+	ExDate           time.Time      `json:"ex_date"`
+	RecordDate       time.Time      `json:"record_date"`
+	PayDate          time.Time      `json:"pay_date"`
+	CreatedAt        time.Time      `json:"-"`
+	UpdatedAt        time.Time      `json:"-"`
 }
 
 // CreateDividendReq dividend request struct
 type CreateDividendReq struct {
-	DividendID       string    `json:"-"`
-	StockID          string    `json:"stockid"`
-	DividendPerShare int       `json:"dividend_per_share"`
-	DividendDate     time.Time `json:"dividend_date"`
-	CreatedAt        time.Time `json:"-"`
-	UpdatedAt        time.Time `json:"-"`
+	DividendID       string         `json:"-"`
+	StockID          string         `json:"stockid"`
+	DividendPerShare entities.Money `json:"dividend_per_share"`
+	ExDate           time.Time      `json:"ex_date"`
+	RecordDate       time.Time      `json:"record_date"`
+	PayDate          time.Time      `json:"pay_date"`
+	CreatedAt        time.Time      `json:"-"`
+	UpdatedAt        time.Time      `json:"-"`
 }
 
 func (req CreateDividendReq) Validate() error {
@@ -34,13 +39,9 @@ func (req CreateDividendReq) Validate() error {
 	if req.StockID == "" {
 		msgs = append(msgs, "stock id cannot be empty")
 	}
-	if req.DividendPerShare == 0 {
-		msgs = append(msgs, "dividend per share cannot be zero")
-	}
+	msgs = append(msgs, moneyMsgs("dividend per share", req.DividendPerShare)...)
 
-	if req.DividendDate == (time.Time{}) {
-		msgs = append(msgs, "dividend date cannot be empty")
-	}
+	msgs = append(msgs, dividendDateMsgs(req.ExDate, req.RecordDate, req.PayDate)...)
 
 	if len(msgs) > 0 {
 		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
@@ -51,12 +52,14 @@ func (req CreateDividendReq) Validate() error {
 
 // UpdateDividendReq update dividend request struct
 type UpdateDividendReq struct {
-	DividendID       string    `json:"-"`
-	StockID          string    `json:"stockid"`
-	DividendPerShare int       `json:"dividend_per_share"`
-	DividendDate     time.Time `json:"dividend_date"`
-	CreatedAt        time.Time `json:"-"`
-	UpdatedAt        time.Time `json:"-"`
+	DividendID       string         `json:"-"`
+	StockID          string         `json:"stockid"`
+	DividendPerShare entities.Money `json:"dividend_per_share"`
+	ExDate           time.Time      `json:"ex_date"`
+	RecordDate       time.Time      `json:"record_date"`
+	PayDate          time.Time      `json:"pay_date"`
+	CreatedAt        time.Time      `json:"-"`
+	UpdatedAt        time.Time      `json:"-"`
 }
 
 func (req UpdateDividendReq) Validate() error {
@@ -65,13 +68,9 @@ func (req UpdateDividendReq) Validate() error {
 	if req.StockID == "" {
 		msgs = append(msgs, "stock id cannot be empty")
 	}
-	if req.DividendPerShare == 0 {
-		msgs = append(msgs, "dividend per share cannot be zero")
-	}
+	msgs = append(msgs, moneyMsgs("dividend per share", req.DividendPerShare)...)
 
-	if req.DividendDate == (time.Time{}) {
-		msgs = append(msgs, "dividend date cannot be empty")
-	}
+	msgs = append(msgs, dividendDateMsgs(req.ExDate, req.RecordDate, req.PayDate)...)
 
 	if len(msgs) > 0 {
 		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
@@ -79,3 +78,19 @@ func (req UpdateDividendReq) Validate() error {
 
 	return nil
 }
+
+// dividendDateMsgs validates the dates of a dividend, of which only the ex-date is required
+func dividendDateMsgs(exDate, recordDate, payDate time.Time) []string {
+	if exDate.IsZero() {
+		return []string{"ex date cannot be empty"}
+	}
+
+	var msgs []string
+	if !recordDate.IsZero() && recordDate.Before(exDate) {
+		msgs = append(msgs, "record date cannot be before ex date")
+	}
+	if !payDate.IsZero() && payDate.Before(exDate) {
+		msgs = append(msgs, "pay date cannot be before ex date")
+	}
+	return msgs
+}
@@ -5,7 +5,9 @@ import (
 	"time"
 
 	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
 )
 
 // TestValidateCreateDividendReq_Ok checks that Validate does not return an error when a valid request is received
@@ -13,8 +15,10 @@ func TestValidateCreateDividendReq_Ok(t *testing.T) {
 	// Arrange
 	req := CreateDividendReq{
 		StockID:          "5",
-		DividendPerShare: 5,
-		DividendDate:     time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC),
+		DividendPerShare: entities.NewMoney(decimal.NewFromInt(5), "CZK"),
+		ExDate:           time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC),
+		RecordDate:       time.Date(2023, time.August, 4, 0, 0, 0, 0, time.UTC),
+		PayDate:          time.Date(2023, time.August, 17, 0, 0, 0, 0, time.UTC),
 	}
 
 	// Act
@@ -28,7 +32,7 @@ func TestValidateCreateDividendReq_Ok(t *testing.T) {
 func TestValidateCreateDividendReq_InvalidRequest(t *testing.T) {
 	// Arrange
 	req := CreateDividendReq{}
-	expectedError := "stock id cannot be empty | dividend per share cannot be zero | dividend date cannot be empty"
+	expectedError := "stock id cannot be empty | dividend per share cannot be zero | dividend per share currency cannot be empty | ex date cannot be empty"
 
 	// Act
 	err := req.Validate()
@@ -44,8 +48,10 @@ func TestValidateUpdateDividendReq_Ok(t *testing.T) {
 	// Arrange
 	req := UpdateDividendReq{
 		StockID:          "5",
-		DividendPerShare: 5,
-		DividendDate:     time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC),
+		DividendPerShare: entities.NewMoney(decimal.NewFromInt(5), "CZK"),
+		ExDate:           time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC),
+		RecordDate:       time.Date(2023, time.August, 4, 0, 0, 0, 0, time.UTC),
+		PayDate:          time.Date(2023, time.August, 17, 0, 0, 0, 0, time.UTC),
 	}
 
 	// Act
@@ -59,7 +65,28 @@ func TestValidateUpdateDividendReq_Ok(t *testing.T) {
 func TestValidateUpdateDividendReq_InvalidRequest(t *testing.T) {
 	// Arrange
 	req := UpdateDividendReq{}
-	expectedError := "stock id cannot be empty | dividend per share cannot be zero | dividend date cannot be empty"
+	expectedError := "stock id cannot be empty | dividend per share cannot be zero | dividend per share currency cannot be empty | ex date cannot be empty"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidateCreateDividendReq_DatesBeforeExDate checks that Validate returns an error when the record or pay date is before the ex-date
+func TestValidateCreateDividendReq_DatesBeforeExDate(t *testing.T) {
+	// Arrange
+	req := CreateDividendReq{
+		StockID:          "5",
+		DividendPerShare: entities.NewMoney(decimal.NewFromInt(5), "CZK"),
+		ExDate:           time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC),
+		RecordDate:       time.Date(2023, time.August, 2, 0, 0, 0, 0, time.UTC),
+		PayDate:          time.Date(2023, time.July, 17, 0, 0, 0, 0, time.UTC),
+	}
+	expectedError := "record date cannot be before ex date | pay date cannot be before ex date"
 
 	// Act
 	err := req.Validate()
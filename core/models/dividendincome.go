@@ -0,0 +1,123 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+const (
+	// DividendStatusPaid is the status of a recorded dividend already paid
+	DividendStatusPaid = "paid"
+	// DividendStatusDeclared is the status of a recorded dividend to be paid
+	DividendStatusDeclared = "declared"
+	// DividendStatusForecast is the status of a dividend expected from the payment pattern of a stock
+	DividendStatusForecast = "forecast"
+)
+
+// DividendCalendarReq dividend calendar date range request struct
+type DividendCalendarReq struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// Validate checks that a given DividendCalendarReq is valid
+func (req DividendCalendarReq) Validate() error {
+	var msgs []string
+
+	if !req.From.IsZero() && !req.To.IsZero() && req.To.Before(req.From) {
+		msgs = append(msgs, "to cannot be before from")
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// DividendIncomeResp dividend paid or expected to be paid to a portfolio response struct
+type DividendIncomeResp struct {
+	DividendID       string          `json:"dividendid,omitempty"`
+	StockID          string          `json:"stockid"`
+	Symbol           string          `json:"symbol"`
+	Status           string          `json:"status"`
+	ExDate           time.Time       `json:"ex_date"`
+	PayDate          time.Time       `json:"pay_date"`
+	Shares           decimal.Decimal `json:"shares"`
+	DividendPerShare entities.Money  `json:"dividend_per_share"`
+	Amount           entities.Money  `json:"amount"`
+	// BaseAmount is the amount in the currency of the portfolio and is zero when it has none
+	BaseAmount entities.Money `json:"base_amount"`
+}
+
+// DividendMonthResp dividends of a calendar month response struct
+type DividendMonthResp struct {
+	Month    time.Time            `json:"month"`
+	Total    entities.Money       `json:"total"`
+	Payments []DividendIncomeResp `json:"payments"`
+}
+
+// DividendCalendarResp dividend calendar response struct
+type DividendCalendarResp struct {
+	PortfolioID string `json:"portfolioid"`
+	// Currency is the currency of the totals, empty when they are summed in the currency all the dividends are paid in
+	Currency string              `json:"currency"`
+	From     time.Time           `json:"from"`
+	To       time.Time           `json:"to"`
+	Total    entities.Money      `json:"total"`
+	Months   []DividendMonthResp `json:"months"`
+}
+
+// DividendForecastHoldingResp dividends expected from a holding response struct
+type DividendForecastHoldingResp struct {
+	StockID          string          `json:"stockid"`
+	Symbol           string          `json:"symbol"`
+	Quantity         decimal.Decimal `json:"quantity"`
+	Payments         int             `json:"payments"`
+	DividendPerShare entities.Money  `json:"dividend_per_share"`
+	Total            entities.Money  `json:"total"`
+}
+
+// DividendForecastResp dividends expected over the next twelve months response struct
+type DividendForecastResp struct {
+	PortfolioID string `json:"portfolioid"`
+	// Currency is the currency of the totals, empty when they are summed in the currency all the dividends are paid in
+	Currency string                        `json:"currency"`
+	From     time.Time                     `json:"from"`
+	To       time.Time                     `json:"to"`
+	Total    entities.Money                `json:"total"`
+	Months   []DividendMonthResp           `json:"months"`
+	Holdings []DividendForecastHoldingResp `json:"holdings"`
+}
+
+// DividendYieldHoldingResp dividend yield of a holding response struct
+type DividendYieldHoldingResp struct {
+	StockID                string          `json:"stockid"`
+	Symbol                 string          `json:"symbol"`
+	Quantity               decimal.Decimal `json:"quantity"`
+	AnnualDividendPerShare entities.Money  `json:"annual_dividend_per_share"`
+	AnnualIncome           entities.Money  `json:"annual_income"`
+	CostBasis              entities.Money  `json:"cost_basis"`
+	MarketValue            entities.Money  `json:"market_value"`
+	Priced                 bool            `json:"priced"`
+	YieldOnCostPct         float64         `json:"yield_on_cost_pct"`
+	CurrentYieldPct        float64         `json:"current_yield_pct"`
+}
+
+// DividendYieldResp dividend yield of a portfolio response struct
+type DividendYieldResp struct {
+	PortfolioID string `json:"portfolioid"`
+	// Currency is the currency of the totals, empty when they are summed in the currency all the holdings are in
+	Currency        string                     `json:"currency"`
+	AnnualIncome    entities.Money             `json:"annual_income"`
+	CostBasis       entities.Money             `json:"cost_basis"`
+	MarketValue     entities.Money             `json:"market_value"`
+	YieldOnCostPct  float64                    `json:"yield_on_cost_pct"`
+	CurrentYieldPct float64                    `json:"current_yield_pct"`
+	Holdings        []DividendYieldHoldingResp `json:"holdings"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateDividendCalendarReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateDividendCalendarReq_Ok(t *testing.T) {
+	// Arrange
+	req := DividendCalendarReq{From: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateDividendCalendarReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateDividendCalendarReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := DividendCalendarReq{From: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	expectedError := "to cannot be before from"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
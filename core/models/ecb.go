@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// ECBImportResp ECB reference rates import response struct
+type ECBImportResp struct {
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	Days       int       `json:"days"`
+	Rates      int       `json:"rates"`
+	CrossRates int       `json:"cross_rates"`
+}
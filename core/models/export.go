@@ -0,0 +1,242 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// Formats of a portfolio export
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+	ExportFormatJSON = "json"
+	ExportFormatPP   = "pp"
+)
+
+// Types of the data of a portfolio export, each one a CSV file, a sheet of the workbook or a list of the archive
+const (
+	ExportTypeHoldings      = "holdings"
+	ExportTypeTransactions  = "transactions"
+	ExportTypeDividends     = "dividends"
+	ExportTypeRealizedGains = "realized_gains"
+)
+
+// ArchiveVersion is the version of the JSON archive of a portfolio written by the export and read by the restore
+const ArchiveVersion = 1
+
+// ExportReq portfolio export request struct. Type is required by the CSV format, which exports a single type.
+type ExportReq struct {
+	Format string `json:"format"`
+	Type   string `json:"type"`
+}
+
+// Validate checks that a given ExportReq is valid
+func (req ExportReq) Validate() error {
+	var msgs []string
+
+	switch req.Format {
+	case "":
+		msgs = append(msgs, "format cannot be empty")
+	case ExportFormatCSV:
+		switch req.Type {
+		case "":
+			msgs = append(msgs, "type cannot be empty with the csv format")
+		case ExportTypeHoldings, ExportTypeTransactions, ExportTypeDividends, ExportTypeRealizedGains:
+		default:
+			msgs = append(msgs, fmt.Sprintf("type must be one of %s, %s, %s or %s", ExportTypeHoldings, ExportTypeTransactions, ExportTypeDividends, ExportTypeRealizedGains))
+		}
+	case ExportFormatXLSX, ExportFormatJSON, ExportFormatPP:
+	default:
+		msgs = append(msgs, fmt.Sprintf("format must be one of %s, %s, %s or %s", ExportFormatCSV, ExportFormatXLSX, ExportFormatJSON, ExportFormatPP))
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+	return nil
+}
+
+// RestoreReq portfolio restore request struct. Format is the format of the file restored, a JSON archive written by the export or a
+// Portfolio Performance XML file, and Broker the slug of the broker of the securities accounts of a Portfolio Performance file that
+// are not named after a broker.
+type RestoreReq struct {
+	Format string `json:"format"`
+	Broker string `json:"broker"`
+}
+
+// Validate checks that a given RestoreReq is valid
+func (req RestoreReq) Validate() error {
+	var msgs []string
+
+	switch req.Format {
+	case ExportFormatJSON, ExportFormatPP:
+	default:
+		msgs = append(msgs, fmt.Sprintf("format must be one of %s or %s", ExportFormatJSON, ExportFormatPP))
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+	return nil
+}
+
+// ArchivePortfolio portfolio of a JSON archive
+type ArchivePortfolio struct {
+	Name                   string    `json:"name"`
+	Extid                  string    `json:"extid"`
+	TaxCountryID           string    `json:"tax_countryid"`
+	FinancialYear          time.Time `json:"financial_year"`
+	PerformenceCalculation int       `json:"performence_calculation"`
+	Summary                bool      `json:"summary"`
+	PriceAlert             bool      `json:"price_alert"`
+	CompanyEventAlert      bool      `json:"company_event_alert"`
+	BaseCurrency           string    `json:"base_currency"`
+}
+
+// Validate checks that a given ArchivePortfolio is valid
+func (p ArchivePortfolio) Validate() error {
+	var msgs []string
+
+	if strings.TrimSpace(p.Name) == "" {
+		msgs = append(msgs, "portfolio name cannot be empty")
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+	return nil
+}
+
+// ArchiveHolding holding of a JSON archive. Broker is the slug of the broker, used when no broker has its ID.
+type ArchiveHolding struct {
+	HoldingID          string          `json:"holdingid"`
+	BrokerID           string          `json:"brokerid"`
+	Broker             string          `json:"broker"`
+	Extid              string          `json:"extid"`
+	Name               string          `json:"name"`
+	Description        string          `json:"description"`
+	Slug               string          `json:"slug"`
+	TradeDate          time.Time       `json:"trade_date"`
+	TradeType          string          `json:"trade_type"`
+	Quantity           decimal.Decimal `json:"quantity"`
+	SharePrice         entities.Money  `json:"share_price"`
+	ExchangeRate       decimal.Decimal `json:"exchange_rate"`
+	ExchangeCurrencyID string          `json:"exchange_currencyid"`
+	BrokerageUnitPrice entities.Money  `json:"brokerage_unit_price"`
+}
+
+// Validate checks that a given ArchiveHolding is valid
+func (h ArchiveHolding) Validate() error {
+	var msgs []string
+
+	if h.HoldingID == "" {
+		msgs = append(msgs, "holding id cannot be empty")
+	}
+	if h.BrokerID == "" && h.Broker == "" {
+		msgs = append(msgs, "either broker id or broker must be provided")
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+	return nil
+}
+
+// ArchiveTransaction transaction of a JSON archive. Symbol, ISIN and Name describe the stock or cryptocurrency, so it is found or
+// created when no instrument has its ID. An empty type is a buy or a sell by the sign of the quantity, as in the archives written
+// before transactions had a type.
+type ArchiveTransaction struct {
+	TransactionID    string          `json:"transactionid"`
+	HoldingID        string          `json:"holdingid"`
+	StockID          string          `json:"stockid,omitempty"`
+	CryptocurrencyID string          `json:"cryptocurrencyid,omitempty"`
+	TransactionType  string          `json:"transaction_type,omitempty"`
+	Symbol           string          `json:"symbol"`
+	ISIN             string          `json:"isin,omitempty"`
+	Name             string          `json:"name"`
+	Quantity         decimal.Decimal `json:"quantity"`
+	TransactionPrice entities.Money  `json:"transaction_price"`
+	TransactionDate  time.Time       `json:"transaction_date"`
+	Extid            string          `json:"extid,omitempty"`
+}
+
+// Validate checks that a given ArchiveTransaction is valid
+func (t ArchiveTransaction) Validate() error {
+	var msgs []string
+
+	if t.HoldingID == "" {
+		msgs = append(msgs, "holding id cannot be empty")
+	}
+	msgs = append(msgs, transactionMsgs(t.TransactionType, t.StockID, t.CryptocurrencyID, t.Quantity, t.TransactionPrice, t.TransactionDate)...)
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+	return nil
+}
+
+// ArchiveDividend dividend of a stock held in the portfolio of a JSON archive, with the shares held on its ex-date and the amount
+// they were paid
+type ArchiveDividend struct {
+	DividendID       string          `json:"dividendid"`
+	StockID          string          `json:"stockid"`
+	Symbol           string          `json:"symbol"`
+	ISIN             string          `json:"isin,omitempty"`
+	DividendPerShare entities.Money  `json:"dividend_per_share"`
+	ExDate           time.Time       `json:"ex_date"`
+	RecordDate       time.Time       `json:"record_date"`
+	PayDate          time.Time       `json:"pay_date"`
+	Shares           decimal.Decimal `json:"shares"`
+	Amount           entities.Money  `json:"amount"`
+}
+
+// Validate checks that a given ArchiveDividend is valid
+func (d ArchiveDividend) Validate() error {
+	var msgs []string
+
+	if d.StockID == "" {
+		msgs = append(msgs, "stock id cannot be empty")
+	}
+	msgs = append(msgs, moneyMsgs("dividend per share", d.DividendPerShare)...)
+	if d.ExDate.IsZero() {
+		msgs = append(msgs, "ex date cannot be empty")
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+	return nil
+}
+
+// ArchiveRealizedGain realized gain of a JSON archive, as of the last rebuild of the lot ledger of the portfolio
+type ArchiveRealizedGain struct {
+	BuyTransactionID  string          `json:"buy_transactionid"`
+	SellTransactionID string          `json:"sell_transactionid"`
+	InstrumentID      string          `json:"instrumentid"`
+	InstrumentType    string          `json:"instrument_type"`
+	Symbol            string          `json:"symbol"`
+	Method            int             `json:"method"`
+	Quantity          decimal.Decimal `json:"quantity"`
+	AcquiredAt        time.Time       `json:"acquired_at"`
+	SoldAt            time.Time       `json:"sold_at"`
+	CostBasis         entities.Money  `json:"cost_basis"`
+	Proceeds          entities.Money  `json:"proceeds"`
+	Gain              entities.Money  `json:"gain"`
+}
+
+// ArchiveRestoreResp JSON archive restore response struct
+type ArchiveRestoreResp struct {
+	PortfolioID         string   `json:"portfolioid"`
+	Holdings            int      `json:"holdings"`
+	Transactions        int      `json:"transactions"`
+	Dividends           int      `json:"dividends"`
+	DuplicateDividends  int      `json:"duplicate_dividends"`
+	NewStocks           []string `json:"new_stocks"`
+	NewCryptocurrencies []string `json:"new_cryptocurrencies"`
+	SkippedTransfers    int      `json:"skipped_transfers"`
+}
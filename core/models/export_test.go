@@ -0,0 +1,144 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestValidateExportReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateExportReq_Ok(t *testing.T) {
+	// Arrange
+	reqs := []ExportReq{
+		{Format: ExportFormatCSV, Type: ExportTypeRealizedGains},
+		{Format: ExportFormatXLSX},
+		{Format: ExportFormatJSON},
+		{Format: ExportFormatPP},
+	}
+
+	for _, req := range reqs {
+		// Act
+		err := req.Validate()
+
+		// Assert
+		assert.Nil(t, err)
+	}
+}
+
+// TestValidateExportReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateExportReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	cases := map[ExportReq]string{
+		{}:                        "format cannot be empty",
+		{Format: "pdf"}:           "format must be one of csv, xlsx, json or pp",
+		{Format: ExportFormatCSV}: "type cannot be empty with the csv format",
+		{Format: ExportFormatCSV, Type: "accounts"}: "type must be one of holdings, transactions, dividends or realized_gains",
+	}
+
+	for req, expectedError := range cases {
+		// Act
+		err := req.Validate()
+
+		// Assert
+		assert.NotEmpty(t, err)
+		assert.IsType(t, wrappers.ValidationErr, err)
+		assert.Equal(t, expectedError, err.Error())
+	}
+}
+
+// TestValidateRestoreReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateRestoreReq_Ok(t *testing.T) {
+	// Arrange
+	reqs := []RestoreReq{
+		{Format: ExportFormatJSON},
+		{Format: ExportFormatPP, Broker: "degiro"},
+	}
+
+	for _, req := range reqs {
+		// Act
+		err := req.Validate()
+
+		// Assert
+		assert.Nil(t, err)
+	}
+}
+
+// TestValidateRestoreReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateRestoreReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := RestoreReq{Format: ExportFormatXLSX}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, "format must be one of json or pp", err.Error())
+}
+
+// TestValidateArchiveTransaction_Ok checks that Validate does not return an error when a valid transaction is received
+func TestValidateArchiveTransaction_Ok(t *testing.T) {
+	// Arrange
+	transaction := ArchiveTransaction{
+		HoldingID:        "holding-id",
+		StockID:          "stock-id",
+		Quantity:         decimal.NewFromInt(2),
+		TransactionPrice: entities.NewMoney(decimal.NewFromInt(10), "USD"),
+		TransactionDate:  time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+	}
+
+	// Act
+	err := transaction.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateArchiveTransaction_InvalidTransaction checks that Validate returns an error when the received transaction is not valid
+func TestValidateArchiveTransaction_InvalidTransaction(t *testing.T) {
+	// Arrange
+	transaction := ArchiveTransaction{TransactionPrice: entities.NewMoney(decimal.NewFromInt(10), "USD")}
+	expectedError := "holding id cannot be empty | either stock id or cryptocurrency id must be provided | quantity cannot be zero | transaction date cannot be empty"
+
+	// Act
+	err := transaction.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidateArchiveHolding_InvalidHolding checks that Validate returns an error when the received holding is not valid
+func TestValidateArchiveHolding_InvalidHolding(t *testing.T) {
+	// Arrange
+	holding := ArchiveHolding{}
+	expectedError := "holding id cannot be empty | either broker id or broker must be provided"
+
+	// Act
+	err := holding.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidateArchiveDividend_InvalidDividend checks that Validate returns an error when the received dividend is not valid
+func TestValidateArchiveDividend_InvalidDividend(t *testing.T) {
+	// Arrange
+	dividend := ArchiveDividend{DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.24"), "USD")}
+	expectedError := "stock id cannot be empty | ex date cannot be empty"
+
+	// Act
+	err := dividend.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
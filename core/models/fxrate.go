@@ -0,0 +1,57 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+)
+
+// FxRateResp fx rate response struct
+type FxRateResp struct {
+	FxRateID      string          `json:"fx_rateid"`
+	BaseCurrency  string          `json:"base_currency"`
+	QuoteCurrency string          `json:"quote_currency"`
+	RateDate      time.Time       `json:"rate_date"`
+	Rate          decimal.Decimal `json:"rate"`
+	Source        string          `json:"source"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// CreateFxRateReq fx rate request struct
+type CreateFxRateReq struct {
+	FxRateID      string          `json:"-"`
+	BaseCurrency  string          `json:"base_currency"`
+	QuoteCurrency string          `json:"quote_currency"`
+	RateDate      time.Time       `json:"rate_date"`
+	Rate          decimal.Decimal `json:"rate"`
+	Source        string          `json:"source"`
+	CreatedAt     time.Time       `json:"-"`
+	UpdatedAt     time.Time       `json:"-"`
+}
+
+// Validate checks that a given CreateFxRateReq is valid
+func (req CreateFxRateReq) Validate() error {
+	var msgs []string
+
+	msgs = append(msgs, currencyCodeMsgs("base currency", req.BaseCurrency)...)
+	msgs = append(msgs, currencyCodeMsgs("quote currency", req.QuoteCurrency)...)
+	if req.BaseCurrency != "" && req.BaseCurrency == req.QuoteCurrency {
+		msgs = append(msgs, "base currency and quote currency must differ")
+	}
+	if req.RateDate == (time.Time{}) {
+		msgs = append(msgs, "rate date cannot be empty")
+	}
+	if !req.Rate.IsPositive() {
+		msgs = append(msgs, "rate must be positive")
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
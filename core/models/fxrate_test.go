@@ -0,0 +1,61 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateCreateFxRateReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateCreateFxRateReq_Ok(t *testing.T) {
+	// Arrange
+	req := CreateFxRateReq{
+		BaseCurrency:  "EUR",
+		QuoteCurrency: "CZK",
+		RateDate:      time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC),
+		Rate:          decimal.RequireFromString("24.105"),
+	}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateCreateFxRateReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateCreateFxRateReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := CreateFxRateReq{}
+	expectedError := "base currency cannot be empty | quote currency cannot be empty | rate date cannot be empty | rate must be positive"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidateCreateFxRateReq_SameCurrency checks that Validate returns an error when the base and quote currencies are equal
+func TestValidateCreateFxRateReq_SameCurrency(t *testing.T) {
+	// Arrange
+	req := CreateFxRateReq{
+		BaseCurrency:  "EUR",
+		QuoteCurrency: "EUR",
+		RateDate:      time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC),
+		Rate:          decimal.NewFromInt(1),
+	}
+	expectedError := "base currency and quote currency must differ"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
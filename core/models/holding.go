@@ -6,48 +6,50 @@ import (
 	"time"
 
 	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
 )
 
 // HoldingResp holding response struct
 type HoldingResp struct {
-	HoldingID          string    `json:"holdingid"`
-	PortfolioID        string    `json:"portfolioid"`
-	BrokerID           string    `json:"brokerid"`
-	Extid              string    `json:"extid"`
-	Name               string    `json:"name"`
-	Description        string    `json:"description"`
-	Slug               string    `json:"slug"`
-	TradeDate          time.Time `json:"trade_date"`
-	TradeType          string    `json:"trade_type"`
-	Quantity           int       `json:"quantity"`
-	SharePrice         int       `json:"share_price"`
-	ExchangeRate       int       `json:"exchange_rate"`
-	ExchangeCurrencyID string    `json:"exchange_currencyid"`
-	BrokerageUnitPrice int       `json:"brokerage_unit_price"`
-	BrokerageCurrency  string    `json:"brokerage_currency"`
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
+	HoldingID          string          `json:"holdingid"`
+	PortfolioID        string          `json:"portfolioid"`
+	BrokerID           string          `json:"brokerid"`
+	BrokerAccountID    string          `json:"broker_accountid"`
+	Extid              string          `json:"extid"`
+	Name               string          `json:"name"`
+	Description        string          `json:"description"`
+	Slug               string          `json:"slug"`
+	TradeDate          time.Time       `json:"trade_date"`
+	TradeType          string          `json:"trade_type"`
+	Quantity           decimal.Decimal `json:"quantity"`
+	SharePrice         entities.Money  `json:"share_price"`
+	ExchangeRate       decimal.Decimal `json:"exchange_rate"`
+	ExchangeCurrencyID string          `json:"exchange_currencyid"`
+	BrokerageUnitPrice entities.Money  `json:"brokerage_unit_price"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
 }
 
 // CreateHoldingReq holding request struct
 type CreateHoldingReq struct {
-	HoldingID          string    `json:"-"`
-	PortfolioID        string    `json:"portfolioid"`
-	BrokerID           string    `json:"brokerid"`
-	Extid              string    `json:"extid"`
-	Name               string    `json:"name"`
-	Description        string    `json:"description"`
-	Slug               string    `json:"-"`
-	TradeDate          time.Time `json:"trade_date"`
-	TradeType          string    `json:"trade_type"`
-	Quantity           int       `json:"quantity"`
-	SharePrice         int       `json:"share_price"`
-	ExchangeRate       int       `json:"exchange_rate"`
-	ExchangeCurrencyID string    `json:"exchange_currencyid"`
-	BrokerageUnitPrice int       `json:"brokerage_unit_price"`
-	BrokerageCurrency  string    `json:"brokerage_currency"`
-	CreatedAt          time.Time `json:"-"`
-	UpdatedAt          time.Time `json:"-"`
+	HoldingID          string          `json:"-"`
+	PortfolioID        string          `json:"portfolioid"`
+	BrokerID           string          `json:"brokerid"`
+	BrokerAccountID    string          `json:"broker_accountid"`
+	Extid              string          `json:"extid"`
+	Name               string          `json:"name"`
+	Description        string          `json:"description"`
+	Slug               string          `json:"-"`
+	TradeDate          time.Time       `json:"trade_date"`
+	TradeType          string          `json:"trade_type"`
+	Quantity           decimal.Decimal `json:"quantity"`
+	SharePrice         entities.Money  `json:"share_price"`
+	ExchangeRate       decimal.Decimal `json:"exchange_rate"`
+	ExchangeCurrencyID string          `json:"exchange_currencyid"`
+	BrokerageUnitPrice entities.Money  `json:"brokerage_unit_price"`
+	CreatedAt          time.Time       `json:"-"`
+	UpdatedAt          time.Time       `json:"-"`
 }
 
 func (req CreateHoldingReq) Validate() error {
@@ -71,24 +73,17 @@ func (req CreateHoldingReq) Validate() error {
 	if req.TradeType == "" {
 		msgs = append(msgs, "trade type cannot be empty")
 	}
-	if req.Quantity == 0 {
+	if req.Quantity.IsZero() {
 		msgs = append(msgs, "quantity cannot be zero")
 	}
-	if req.SharePrice == 0 {
-		msgs = append(msgs, "share price cannot be zero")
-	}
-	if req.ExchangeRate == 0 {
+	msgs = append(msgs, moneyMsgs("share price", req.SharePrice)...)
+	if req.ExchangeRate.IsZero() {
 		msgs = append(msgs, "exchange rate cannot be zero")
 	}
 	if req.ExchangeCurrencyID == "" {
 		msgs = append(msgs, "exchange currency id cannot be empty")
 	}
-	if req.BrokerageUnitPrice == 0 {
-		msgs = append(msgs, "brokerage unit price cannot be zero")
-	}
-	if req.BrokerageCurrency == "" {
-		msgs = append(msgs, "brokerage currency cannot be empty")
-	}
+	msgs = append(msgs, moneyMsgs("brokerage unit price", req.BrokerageUnitPrice)...)
 
 	if len(msgs) > 0 {
 		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
@@ -99,23 +94,23 @@ func (req CreateHoldingReq) Validate() error {
 
 // UpdateHoldingReq update holding request struct
 type UpdateHoldingReq struct {
-	HoldingID          string    `json:"-"`
-	PortfolioID        string    `json:"portfolioid"`
-	BrokerID           string    `json:"brokerid"`
-	Extid              string    `json:"-"`
-	Name               string    `json:"name"`
-	Description        string    `json:"description"`
-	Slug               string    `json:"-"`
-	TradeDate          time.Time `json:"trade_date"`
-	TradeType          string    `json:"trade_type"`
-	Quantity           int       `json:"quantity"`
-	SharePrice         int       `json:"share_price"`
-	ExchangeRate       int       `json:"exchange_rate"`
-	ExchangeCurrencyID string    `json:"exchange_currencyid"`
-	BrokerageUnitPrice int       `json:"brokerage_unit_price"`
-	BrokerageCurrency  string    `json:"brokerage_currency"`
-	CreatedAt          time.Time `json:"-"`
-	UpdatedAt          time.Time `json:"-"`
+	HoldingID          string          `json:"-"`
+	PortfolioID        string          `json:"portfolioid"`
+	BrokerID           string          `json:"brokerid"`
+	BrokerAccountID    string          `json:"broker_accountid"`
+	Extid              string          `json:"-"`
+	Name               string          `json:"name"`
+	Description        string          `json:"description"`
+	Slug               string          `json:"-"`
+	TradeDate          time.Time       `json:"trade_date"`
+	TradeType          string          `json:"trade_type"`
+	Quantity           decimal.Decimal `json:"quantity"`
+	SharePrice         entities.Money  `json:"share_price"`
+	ExchangeRate       decimal.Decimal `json:"exchange_rate"`
+	ExchangeCurrencyID string          `json:"exchange_currencyid"`
+	BrokerageUnitPrice entities.Money  `json:"brokerage_unit_price"`
+	CreatedAt          time.Time       `json:"-"`
+	UpdatedAt          time.Time       `json:"-"`
 }
 
 func (req UpdateHoldingReq) Validate() error {
@@ -136,24 +131,17 @@ func (req UpdateHoldingReq) Validate() error {
 	if req.TradeType == "" {
 		msgs = append(msgs, "trade type cannot be empty")
 	}
-	if req.Quantity == 0 {
+	if req.Quantity.IsZero() {
 		msgs = append(msgs, "quantity cannot be zero")
 	}
-	if req.SharePrice == 0 {
-		msgs = append(msgs, "share price cannot be zero")
-	}
-	if req.ExchangeRate == 0 {
+	msgs = append(msgs, moneyMsgs("share price", req.SharePrice)...)
+	if req.ExchangeRate.IsZero() {
 		msgs = append(msgs, "exchange rate cannot be zero")
 	}
 	if req.ExchangeCurrencyID == "" {
 		msgs = append(msgs, "exchange currency id cannot be empty")
 	}
-	if req.BrokerageUnitPrice == 0 {
-		msgs = append(msgs, "brokerage unit price cannot be zero")
-	}
-	if req.BrokerageCurrency == "" {
-		msgs = append(msgs, "brokerage currency cannot be empty")
-	}
+	msgs = append(msgs, moneyMsgs("brokerage unit price", req.BrokerageUnitPrice)...)
 
 	if len(msgs) > 0 {
 		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
@@ -5,7 +5,9 @@ import (
 	"time"
 
 	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
 )
 
 // TestValidateCreateHoldingReq_Ok checks that Validate does not return an error when a valid request is received
@@ -20,12 +22,11 @@ func TestValidateCreateHoldingReq_Ok(t *testing.T) {
 		Slug:               "Slug",
 		TradeDate:          time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC),
 		TradeType:          "TradeType",
-		Quantity:           1,
-		SharePrice:         1,
-		ExchangeRate:       1,
+		Quantity:           decimal.NewFromInt(1),
+		SharePrice:         entities.NewMoney(decimal.NewFromInt(1), "CZK"),
+		ExchangeRate:       decimal.NewFromInt(1),
 		ExchangeCurrencyID: "czk",
-		BrokerageUnitPrice: 10,
-		BrokerageCurrency:  "czk",
+		BrokerageUnitPrice: entities.NewMoney(decimal.NewFromInt(10), "CZK"),
 	}
 
 	// Act
@@ -39,7 +40,7 @@ func TestValidateCreateHoldingReq_Ok(t *testing.T) {
 func TestValidateCreateHoldingReq_InvalidRequest(t *testing.T) {
 	// Arrange
 	req := CreateHoldingReq{}
-	expectedError := "portfolio id cannot be empty | broker id cannot be empty | external id cannot be empty | name cannot be empty | trade date cannot be empty | trade type cannot be empty | quantity cannot be zero | share price cannot be zero | exchange rate cannot be zero | exchange currency id cannot be empty | brokerage unit price cannot be zero | brokerage currency cannot be empty"
+	expectedError := "portfolio id cannot be empty | broker id cannot be empty | external id cannot be empty | name cannot be empty | trade date cannot be empty | trade type cannot be empty | quantity cannot be zero | share price cannot be zero | share price currency cannot be empty | exchange rate cannot be zero | exchange currency id cannot be empty | brokerage unit price cannot be zero | brokerage unit price currency cannot be empty"
 
 	// Act
 	err := req.Validate()
@@ -62,12 +63,11 @@ func TestValidateUpdateHoldingReq_Ok(t *testing.T) {
 		Slug:               "Slug",
 		TradeDate:          time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC),
 		TradeType:          "TradeType",
-		Quantity:           1,
-		SharePrice:         1,
-		ExchangeRate:       1,
+		Quantity:           decimal.NewFromInt(1),
+		SharePrice:         entities.NewMoney(decimal.NewFromInt(1), "CZK"),
+		ExchangeRate:       decimal.NewFromInt(1),
 		ExchangeCurrencyID: "czk",
-		BrokerageUnitPrice: 10,
-		BrokerageCurrency:  "czk",
+		BrokerageUnitPrice: entities.NewMoney(decimal.NewFromInt(10), "CZK"),
 	}
 
 	// Act
@@ -81,7 +81,7 @@ func TestValidateUpdateHoldingReq_Ok(t *testing.T) {
 func TestValidateUpdateHoldingReq_InvalidRequest(t *testing.T) {
 	// Arrange
 	req := UpdateHoldingReq{}
-	expectedError := "portfolio id cannot be empty | broker id cannot be empty | name cannot be empty | trade date cannot be empty | trade type cannot be empty | quantity cannot be zero | share price cannot be zero | exchange rate cannot be zero | exchange currency id cannot be empty | brokerage unit price cannot be zero | brokerage currency cannot be empty"
+	expectedError := "portfolio id cannot be empty | broker id cannot be empty | name cannot be empty | trade date cannot be empty | trade type cannot be empty | quantity cannot be zero | share price cannot be zero | share price currency cannot be empty | exchange rate cannot be zero | exchange currency id cannot be empty | brokerage unit price cannot be zero | brokerage unit price currency cannot be empty"
 
 	// Act
 	err := req.Validate()
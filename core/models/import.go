@@ -0,0 +1,121 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// Actions taken on the rows of an imported statement
+const (
+	ImportActionCreate    = "create"
+	ImportActionSkip      = "skip"
+	ImportActionDuplicate = "duplicate"
+)
+
+// Statuses of the lines of an imported statement that hold no row
+const (
+	ImportIssueSkipped = "skipped"
+	ImportIssueInvalid = "invalid"
+)
+
+// Statuses of the positions of an imported statement reconciled with the portfolio
+const (
+	ImportPositionMatched    = "matched"
+	ImportPositionMismatched = "mismatched"
+)
+
+// ImportReq broker statement import request struct. Format is the format of the statement, named by the slug of the broker or
+// tracker exporting it, and defaults to the format of the broker statements.
+type ImportReq struct {
+	Broker   string `json:"broker"`
+	Format   string `json:"format"`
+	FileName string `json:"file_name"`
+	DryRun   bool   `json:"dry_run"`
+}
+
+// Validate checks that a given ImportReq is valid
+func (req ImportReq) Validate() error {
+	var msgs []string
+
+	if strings.TrimSpace(req.Broker) == "" {
+		msgs = append(msgs, "broker cannot be empty")
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+	return nil
+}
+
+// ImportResp broker statement import response struct. DuplicateOf is the import batch the same file was already imported by, and
+// Mismatches counts the positions of the statement the portfolio does not hold in the same quantity.
+type ImportResp struct {
+	BatchID        string               `json:"batchid,omitempty"`
+	PortfolioID    string               `json:"portfolioid"`
+	Broker         string               `json:"broker"`
+	DryRun         bool                 `json:"dry_run"`
+	DuplicateOf    string               `json:"duplicate_of,omitempty"`
+	Parsed         int                  `json:"parsed"`
+	Skipped        int                  `json:"skipped"`
+	Invalid        int                  `json:"invalid"`
+	Duplicates     int                  `json:"duplicates"`
+	Rows           []ImportRowResp      `json:"rows"`
+	Issues         []ImportIssueResp    `json:"issues"`
+	Positions      []ImportPositionResp `json:"positions"`
+	Mismatches     int                  `json:"mismatches"`
+	NewStocks      []string             `json:"new_stocks"`
+	TransactionIDs []string             `json:"transaction_ids"`
+	DividendIDs    []string             `json:"dividend_ids"`
+	WithholdingIDs []string             `json:"withholding_ids"`
+}
+
+// ImportRowResp broker statement row response struct
+type ImportRowResp struct {
+	Line     int             `json:"line"`
+	Kind     string          `json:"kind"`
+	Date     time.Time       `json:"date"`
+	Symbol   string          `json:"symbol,omitempty"`
+	ISIN     string          `json:"isin,omitempty"`
+	Name     string          `json:"name,omitempty"`
+	Quantity decimal.Decimal `json:"quantity"`
+	Price    entities.Money  `json:"price"`
+	Amount   entities.Money  `json:"amount"`
+	Fee      entities.Money  `json:"fee"`
+	Action   string          `json:"action"`
+	Reason   string          `json:"reason,omitempty"`
+}
+
+// ImportIssueResp broker statement line response struct, for the lines skipped or not parsed
+type ImportIssueResp struct {
+	Line   int    `json:"line"`
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// ImportPositionResp broker statement position response struct, comparing the quantity the statement lists with the quantity the
+// holding at the broker holds once the statement is imported
+type ImportPositionResp struct {
+	Line       int             `json:"line"`
+	Date       time.Time       `json:"date"`
+	Symbol     string          `json:"symbol,omitempty"`
+	ISIN       string          `json:"isin,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	Statement  decimal.Decimal `json:"statement_quantity"`
+	Portfolio  decimal.Decimal `json:"portfolio_quantity"`
+	Difference decimal.Decimal `json:"difference"`
+	Status     string          `json:"status"`
+}
+
+// ImportRollbackResp import batch rollback response struct, counting the transactions, dividends and withholdings deleted
+type ImportRollbackResp struct {
+	BatchID      string `json:"batchid"`
+	PortfolioID  string `json:"portfolioid"`
+	Transactions int64  `json:"transactions"`
+	Dividends    int64  `json:"dividends"`
+	Withholdings int64  `json:"withholdings"`
+}
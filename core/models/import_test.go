@@ -0,0 +1,35 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateImportReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateImportReq_Ok(t *testing.T) {
+	// Arrange
+	req := ImportReq{Broker: "degiro", DryRun: true}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateImportReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateImportReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := ImportReq{Broker: " "}
+	expectedError := "broker cannot be empty"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
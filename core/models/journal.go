@@ -0,0 +1,37 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+)
+
+// Formats of a plain-text accounting journal of a portfolio
+const (
+	JournalFormatBeancount = "beancount"
+	JournalFormatHledger   = "hledger"
+)
+
+// JournalReq plain-text accounting journal export request struct
+type JournalReq struct {
+	Format string `json:"format"`
+}
+
+// Validate checks that a given JournalReq is valid
+func (req JournalReq) Validate() error {
+	var msgs []string
+
+	switch req.Format {
+	case "":
+		msgs = append(msgs, "format cannot be empty")
+	case JournalFormatBeancount, JournalFormatHledger:
+	default:
+		msgs = append(msgs, fmt.Sprintf("format must be one of %s or %s", JournalFormatBeancount, JournalFormatHledger))
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+	return nil
+}
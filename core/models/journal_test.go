@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateJournalReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateJournalReq_Ok(t *testing.T) {
+	// Arrange
+	reqs := []JournalReq{
+		{Format: JournalFormatBeancount},
+		{Format: JournalFormatHledger},
+	}
+
+	for _, req := range reqs {
+		// Act
+		err := req.Validate()
+
+		// Assert
+		assert.Nil(t, err)
+	}
+}
+
+// TestValidateJournalReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateJournalReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	cases := map[JournalReq]string{
+		{}:                 "format cannot be empty",
+		{Format: "ledger"}: "format must be one of beancount or hledger",
+	}
+
+	for req, expectedError := range cases {
+		// Act
+		err := req.Validate()
+
+		// Assert
+		assert.NotEmpty(t, err)
+		assert.IsType(t, wrappers.ValidationErr, err)
+		assert.Equal(t, expectedError, err.Error())
+	}
+}
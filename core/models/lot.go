@@ -0,0 +1,83 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// LotResp lot response struct
+type LotResp struct {
+	LotID             string          `json:"lotid"`
+	PortfolioID       string          `json:"portfolioid"`
+	TransactionID     string          `json:"transactionid"`
+	InstrumentID      string          `json:"instrumentid"`
+	InstrumentType    string          `json:"instrument_type"`
+	AcquiredAt        time.Time       `json:"acquired_at"`
+	Quantity          decimal.Decimal `json:"quantity"`
+	RemainingQuantity decimal.Decimal `json:"remaining_quantity"`
+	CostPerUnit       entities.Money  `json:"cost_per_unit"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
+
+// RealizedGainResp realized gain response struct
+type RealizedGainResp struct {
+	RealizedGainID    string          `json:"realized_gainid"`
+	PortfolioID       string          `json:"portfolioid"`
+	LotID             string          `json:"lotid"`
+	BuyTransactionID  string          `json:"buy_transactionid"`
+	SellTransactionID string          `json:"sell_transactionid"`
+	InstrumentID      string          `json:"instrumentid"`
+	InstrumentType    string          `json:"instrument_type"`
+	Method            int             `json:"method"`
+	Quantity          decimal.Decimal `json:"quantity"`
+	AcquiredAt        time.Time       `json:"acquired_at"`
+	SoldAt            time.Time       `json:"sold_at"`
+	CostBasis         entities.Money  `json:"cost_basis"`
+	Proceeds          entities.Money  `json:"proceeds"`
+	Gain              entities.Money  `json:"gain"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
+
+// LedgerResp lot ledger response struct
+type LedgerResp struct {
+	PortfolioID     string             `json:"portfolioid"`
+	CostBasisMethod int                `json:"cost_basis_method"`
+	Lots            []LotResp          `json:"lots"`
+	RealizedGains   []RealizedGainResp `json:"realized_gains"`
+}
+
+// UpdateCostBasisMethodReq update cost basis method request struct
+type UpdateCostBasisMethodReq struct {
+	Method int `json:"method"`
+}
+
+func (req UpdateCostBasisMethodReq) Validate() error {
+	var msgs []string
+
+	if req.Method == 0 {
+		msgs = append(msgs, "method cannot be zero")
+	} else if !validCostBasisMethod(req.Method) {
+		msgs = append(msgs, costBasisMethodMsg("method"))
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+func validCostBasisMethod(method int) bool {
+	return method == entities.CostBasisFIFO || method == entities.CostBasisLIFO || method == entities.CostBasisAverage
+}
+
+func costBasisMethodMsg(field string) string {
+	return fmt.Sprintf("%s must be %d (FIFO), %d (LIFO) or %d (average cost)", field, entities.CostBasisFIFO, entities.CostBasisLIFO, entities.CostBasisAverage)
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateUpdateCostBasisMethodReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateUpdateCostBasisMethodReq_Ok(t *testing.T) {
+	// Arrange
+	req := UpdateCostBasisMethodReq{Method: 2}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateUpdateCostBasisMethodReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateUpdateCostBasisMethodReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := UpdateCostBasisMethodReq{Method: 4}
+	expectedError := "method must be 1 (FIFO), 2 (LIFO) or 3 (average cost)"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
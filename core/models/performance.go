@@ -0,0 +1,49 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// PerformanceReq performance date range request struct
+type PerformanceReq struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+func (req PerformanceReq) Validate() error {
+	var msgs []string
+
+	if !req.From.IsZero() && !req.To.IsZero() && req.To.Before(req.From) {
+		msgs = append(msgs, "to cannot be before from")
+	}
+	if req.To.After(time.Now().UTC()) {
+		msgs = append(msgs, "to cannot be in the future")
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// PerformanceResp time-weighted and money-weighted return response struct
+type PerformanceResp struct {
+	PortfolioID                      string         `json:"portfolioid,omitempty"`
+	HoldingID                        string         `json:"holdingid,omitempty"`
+	From                             time.Time      `json:"from"`
+	To                               time.Time      `json:"to"`
+	StartValue                       entities.Money `json:"start_value"`
+	EndValue                         entities.Money `json:"end_value"`
+	NetContributions                 entities.Money `json:"net_contributions"`
+	Dividends                        entities.Money `json:"dividends"`
+	TimeWeightedReturnPct            float64        `json:"time_weighted_return_pct"`
+	TimeWeightedReturnAnnualizedPct  float64        `json:"time_weighted_return_annualized_pct"`
+	MoneyWeightedReturnPct           float64        `json:"money_weighted_return_pct"`
+	MoneyWeightedReturnAnnualizedPct float64        `json:"money_weighted_return_annualized_pct"`
+}
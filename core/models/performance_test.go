@@ -0,0 +1,42 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidatePerformanceReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidatePerformanceReq_Ok(t *testing.T) {
+	// Arrange
+	req := PerformanceReq{
+		From: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidatePerformanceReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidatePerformanceReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := PerformanceReq{
+		From: time.Now().UTC().AddDate(2, 0, 0),
+		To:   time.Now().UTC().AddDate(1, 0, 0),
+	}
+	expectedError := "to cannot be before from | to cannot be in the future"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
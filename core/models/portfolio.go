@@ -20,6 +20,7 @@ type PortfolioResp struct {
 	Summary                bool      `json:"summary"`
 	PriceAlert             bool      `json:"price_alert"`
 	CompanyEventAlert      bool      `json:"company_event_alert"`
+	BaseCurrency           string    `json:"base_currency"`
 	CreatedAt              time.Time `json:"created_at"`
 	UpdatedAt              time.Time `json:"updated_at"`
 }
@@ -36,6 +37,7 @@ type CreatePortfolioReq struct {
 	Summary                bool      `json:"summary"`
 	PriceAlert             bool      `json:"price_alert"`
 	CompanyEventAlert      bool      `json:"company_event_alert"`
+	BaseCurrency           string    `json:"base_currency"`
 	CreatedAt              time.Time `json:"-"`
 	UpdatedAt              time.Time `json:"-"`
 }
@@ -60,6 +62,11 @@ func (req CreatePortfolioReq) Validate() error {
 	}
 	if req.PerformenceCalculation == 0 {
 		msgs = append(msgs, "performance calculation cannot be zero")
+	} else if !validCostBasisMethod(req.PerformenceCalculation) {
+		msgs = append(msgs, costBasisMethodMsg("performance calculation"))
+	}
+	if req.BaseCurrency != "" && !validCurrencyCode(req.BaseCurrency) {
+		msgs = append(msgs, "base currency must be a 3-letter ISO 4217 code")
 	}
 
 	if len(msgs) > 0 {
@@ -80,6 +87,7 @@ type UpdatePortfolioReq struct {
 	Summary                bool      `json:"summary"`
 	PriceAlert             bool      `json:"price_alert"`
 	CompanyEventAlert      bool      `json:"company_event_alert"`
+	BaseCurrency           string    `json:"base_currency"`
 }
 
 func (req UpdatePortfolioReq) Validate() error {
@@ -102,6 +110,11 @@ func (req UpdatePortfolioReq) Validate() error {
 	}
 	if req.PerformenceCalculation == 0 {
 		msgs = append(msgs, "performance calculation cannot be zero")
+	} else if !validCostBasisMethod(req.PerformenceCalculation) {
+		msgs = append(msgs, costBasisMethodMsg("performance calculation"))
+	}
+	if req.BaseCurrency != "" && !validCurrencyCode(req.BaseCurrency) {
+		msgs = append(msgs, "base currency must be a 3-letter ISO 4217 code")
 	}
 
 	if len(msgs) > 0 {
@@ -0,0 +1,219 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// Price history intervals
+const (
+	PriceIntervalDay   = "1d"
+	PriceIntervalWeek  = "1wk"
+	PriceIntervalMonth = "1mo"
+)
+
+// PriceResp price response struct
+type PriceResp struct {
+	PriceID        string          `json:"priceid"`
+	InstrumentID   string          `json:"instrumentid"`
+	InstrumentType string          `json:"instrument_type"`
+	PriceDate      time.Time       `json:"price_date"`
+	Open           decimal.Decimal `json:"open"`
+	High           decimal.Decimal `json:"high"`
+	Low            decimal.Decimal `json:"low"`
+	Close          decimal.Decimal `json:"close"`
+	Volume         decimal.Decimal `json:"volume"`
+	Currency       string          `json:"currency"`
+	Source         string          `json:"source"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// CreatePriceReq price request struct
+type CreatePriceReq struct {
+	PriceID        string          `json:"-"`
+	InstrumentID   string          `json:"instrumentid"`
+	InstrumentType string          `json:"instrument_type"`
+	PriceDate      time.Time       `json:"price_date"`
+	Open           decimal.Decimal `json:"open"`
+	High           decimal.Decimal `json:"high"`
+	Low            decimal.Decimal `json:"low"`
+	Close          decimal.Decimal `json:"close"`
+	Volume         decimal.Decimal `json:"volume"`
+	Currency       string          `json:"currency"`
+	Source         string          `json:"source"`
+	CreatedAt      time.Time       `json:"-"`
+	UpdatedAt      time.Time       `json:"-"`
+}
+
+// Validate checks that a given CreatePriceReq is valid
+func (req CreatePriceReq) Validate() error {
+	var msgs []string
+
+	msgs = append(msgs, instrumentMsgs(req.InstrumentID, req.InstrumentType)...)
+	if req.PriceDate == (time.Time{}) {
+		msgs = append(msgs, "price date cannot be empty")
+	}
+	if !req.Close.IsPositive() {
+		msgs = append(msgs, "close must be positive")
+	}
+	if req.Open.IsNegative() || req.High.IsNegative() || req.Low.IsNegative() || req.Volume.IsNegative() {
+		msgs = append(msgs, "open, high, low and volume cannot be negative")
+	}
+	if !req.High.IsZero() && req.High.LessThan(req.Low) {
+		msgs = append(msgs, "high cannot be lower than low")
+	}
+	msgs = append(msgs, currencyCodeMsgs("currency", req.Currency)...)
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// ImportPricesReq price import request struct, describing the instrument whose prices a CSV file holds
+type ImportPricesReq struct {
+	InstrumentID   string `json:"instrumentid"`
+	InstrumentType string `json:"instrument_type"`
+	Currency       string `json:"currency"`
+	Source         string `json:"source"`
+}
+
+// Validate checks that a given ImportPricesReq is valid
+func (req ImportPricesReq) Validate() error {
+	var msgs []string
+
+	msgs = append(msgs, instrumentMsgs(req.InstrumentID, req.InstrumentType)...)
+	msgs = append(msgs, currencyCodeMsgs("currency", req.Currency)...)
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// PriceImportResp price import response struct
+type PriceImportResp struct {
+	InstrumentID string    `json:"instrumentid"`
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	Imported     int       `json:"imported"`
+	Skipped      int       `json:"skipped"`
+}
+
+// LastPriceResp last price response struct
+type LastPriceResp struct {
+	InstrumentID   string         `json:"instrumentid"`
+	InstrumentType string         `json:"instrument_type"`
+	Price          entities.Money `json:"price"`
+	PricedAt       time.Time      `json:"priced_at"`
+	Source         string         `json:"source"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// SetLastPriceReq last price request struct
+type SetLastPriceReq struct {
+	InstrumentID   string         `json:"instrumentid"`
+	InstrumentType string         `json:"instrument_type"`
+	Price          entities.Money `json:"price"`
+	PricedAt       time.Time      `json:"priced_at"`
+	Source         string         `json:"source"`
+	UpdatedAt      time.Time      `json:"-"`
+}
+
+// Validate checks that a given SetLastPriceReq is valid
+func (req SetLastPriceReq) Validate() error {
+	var msgs []string
+
+	msgs = append(msgs, instrumentMsgs(req.InstrumentID, req.InstrumentType)...)
+	msgs = append(msgs, moneyMsgs("price", req.Price)...)
+	if req.Price.Amount.IsNegative() {
+		msgs = append(msgs, "price cannot be negative")
+	}
+	if req.PricedAt == (time.Time{}) {
+		msgs = append(msgs, "priced at cannot be empty")
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// PriceHistoryReq price history request struct
+type PriceHistoryReq struct {
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Interval string    `json:"interval"`
+}
+
+// Validate checks that a given PriceHistoryReq is valid
+func (req PriceHistoryReq) Validate() error {
+	var msgs []string
+
+	if !req.From.IsZero() && !req.To.IsZero() && req.To.Before(req.From) {
+		msgs = append(msgs, "to cannot be before from")
+	}
+	switch req.Interval {
+	case "", PriceIntervalDay, PriceIntervalWeek, PriceIntervalMonth:
+	default:
+		msgs = append(msgs, fmt.Sprintf("interval must be one of %s, %s or %s", PriceIntervalDay, PriceIntervalWeek, PriceIntervalMonth))
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// PriceBarResp price bar response struct, holding the prices of a day, week or month starting on its date
+type PriceBarResp struct {
+	Date   time.Time       `json:"date"`
+	Open   decimal.Decimal `json:"open"`
+	High   decimal.Decimal `json:"high"`
+	Low    decimal.Decimal `json:"low"`
+	Close  decimal.Decimal `json:"close"`
+	Volume decimal.Decimal `json:"volume"`
+}
+
+// PriceGapResp price gap response struct, a run of business days without a daily price
+type PriceGapResp struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	Days int       `json:"days"`
+}
+
+// PriceHistoryResp price history response struct
+type PriceHistoryResp struct {
+	InstrumentID string         `json:"instrumentid"`
+	Interval     string         `json:"interval"`
+	Currency     string         `json:"currency"`
+	From         time.Time      `json:"from"`
+	To           time.Time      `json:"to"`
+	Prices       []PriceBarResp `json:"prices"`
+	Gaps         []PriceGapResp `json:"gaps"`
+	LastPrice    *LastPriceResp `json:"last_price,omitempty"`
+}
+
+// instrumentMsgs returns the validation messages of a required stock or cryptocurrency reference
+func instrumentMsgs(instrumentID, instrumentType string) []string {
+	var msgs []string
+
+	if instrumentID == "" {
+		msgs = append(msgs, "instrument id cannot be empty")
+	}
+	if instrumentType != entities.EntityNameStock && instrumentType != entities.EntityNameCryptoCurrency {
+		msgs = append(msgs, fmt.Sprintf("instrument type must be %s or %s", entities.EntityNameStock, entities.EntityNameCryptoCurrency))
+	}
+
+	return msgs
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestValidateCreatePriceReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateCreatePriceReq_Ok(t *testing.T) {
+	// Arrange
+	req := CreatePriceReq{
+		InstrumentID:   "stock-a",
+		InstrumentType: entities.EntityNameStock,
+		PriceDate:      time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC),
+		Open:           decimal.NewFromInt(10),
+		High:           decimal.NewFromInt(12),
+		Low:            decimal.NewFromInt(9),
+		Close:          decimal.NewFromInt(11),
+		Volume:         decimal.NewFromInt(1000),
+		Currency:       "USD",
+	}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateCreatePriceReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateCreatePriceReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := CreatePriceReq{}
+	expectedError := "instrument id cannot be empty | instrument type must be stock or cryptocurrency | price date cannot be empty | close must be positive | currency cannot be empty"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidateCreatePriceReq_InvalidRange checks that Validate returns an error when the high price is lower than the low one
+func TestValidateCreatePriceReq_InvalidRange(t *testing.T) {
+	// Arrange
+	req := CreatePriceReq{
+		InstrumentID:   "stock-a",
+		InstrumentType: entities.EntityNameStock,
+		PriceDate:      time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC),
+		High:           decimal.NewFromInt(9),
+		Low:            decimal.NewFromInt(12),
+		Close:          decimal.NewFromInt(11),
+		Currency:       "USD",
+	}
+	expectedError := "high cannot be lower than low"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidateImportPricesReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateImportPricesReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := ImportPricesReq{InstrumentID: "stock-a", InstrumentType: "bond", Currency: "USD"}
+	expectedError := "instrument type must be stock or cryptocurrency"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidateSetLastPriceReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateSetLastPriceReq_Ok(t *testing.T) {
+	// Arrange
+	req := SetLastPriceReq{
+		InstrumentID:   "stock-a",
+		InstrumentType: entities.EntityNameStock,
+		Price:          entities.NewMoney(decimal.RequireFromString("10.5"), "USD"),
+		PricedAt:       time.Date(2023, time.August, 3, 15, 0, 0, 0, time.UTC),
+	}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateSetLastPriceReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateSetLastPriceReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := SetLastPriceReq{
+		InstrumentID:   "stock-a",
+		InstrumentType: entities.EntityNameStock,
+		Price:          entities.NewMoney(decimal.NewFromInt(-1), "USD"),
+	}
+	expectedError := "price cannot be negative | priced at cannot be empty"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidatePriceHistoryReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidatePriceHistoryReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := PriceHistoryReq{
+		From:     time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC),
+		Interval: "1y",
+	}
+	expectedError := "to cannot be before from | interval must be one of 1d, 1wk or 1mo"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
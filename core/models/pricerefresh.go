@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// PriceRefreshRunResp price refresh run response struct
+type PriceRefreshRunResp struct {
+	PriceRefreshRunID string                         `json:"price_refresh_runid"`
+	Provider          string                         `json:"provider"`
+	StartedAt         time.Time                      `json:"started_at"`
+	FinishedAt        time.Time                      `json:"finished_at"`
+	DurationMs        int64                          `json:"duration_ms"`
+	Instruments       int                            `json:"instruments"`
+	Updated           int                            `json:"updated"`
+	Failed            int                            `json:"failed"`
+	Failures          []entities.PriceRefreshFailure `json:"failures"`
+}
@@ -16,6 +16,7 @@ type StockResp struct {
 	TickerSymbol string    `json:"ticker_symbol"`
 	CompanyName  string    `json:"company_name"`
 	Slug         string    `json:"slug"`
+	Country      string    `json:"country"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
@@ -28,6 +29,7 @@ type CreateStockReq struct {
 	TickerSymbol string    `json:"ticker_symbol"`
 	CompanyName  string    `json:"company_name"`
 	Slug         string    `json:"-"`
+	Country      string    `json:"country"`
 	CreatedAt    time.Time `json:"-"`
 	UpdatedAt    time.Time `json:"-"`
 }
@@ -47,6 +49,9 @@ func (req CreateStockReq) Validate() error {
 	if req.CompanyName == "" {
 		msgs = append(msgs, "company name cannot be empty")
 	}
+	if req.Country != "" && !validCountryCode(req.Country) {
+		msgs = append(msgs, "country must be a 2-letter ISO 3166-1 code")
+	}
 
 	if len(msgs) > 0 {
 		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
@@ -63,6 +68,7 @@ type UpdateStockReq struct {
 	TickerSymbol string    `json:"ticker_symbol"`
 	CompanyName  string    `json:"company_name"`
 	Slug         string    `json:"-"`
+	Country      string    `json:"country"`
 	CreatedAt    time.Time `json:"-"`
 	UpdatedAt    time.Time `json:"-"`
 }
@@ -82,6 +88,9 @@ func (req UpdateStockReq) Validate() error {
 	if req.CompanyName == "" {
 		msgs = append(msgs, "company name cannot be empty")
 	}
+	if req.Country != "" && !validCountryCode(req.Country) {
+		msgs = append(msgs, "country must be a 2-letter ISO 3166-1 code")
+	}
 
 	if len(msgs) > 0 {
 		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
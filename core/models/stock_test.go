@@ -39,6 +39,27 @@ func TestValidateCreateStockReq_InvalidRequest(t *testing.T) {
 	assert.Equal(t, expectedError, err.Error())
 }
 
+// TestValidateCreateStockReq_InvalidCountry checks that Validate returns an error when the country is not an ISO 3166-1 alpha-2 code
+func TestValidateCreateStockReq_InvalidCountry(t *testing.T) {
+	// Arrange
+	req := CreateStockReq{
+		HoldingID:    "HoldingID",
+		Extid:        "Extid",
+		TickerSymbol: "TickerSymbol",
+		CompanyName:  "CompanyName",
+		Country:      "USA",
+	}
+	expectedError := "country must be a 2-letter ISO 3166-1 code"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
 // TestValidateCreateStockReq_Ok checks that Validate does not return an error when a valid request is received
 func TestValidateUpdateStockReq_Ok(t *testing.T) {
 	// Arrange
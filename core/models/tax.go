@@ -0,0 +1,123 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TaxReportReq tax report query struct
+type TaxReportReq struct {
+	Year int `json:"year"`
+}
+
+// Validate checks that a given TaxReportReq is valid
+func (req TaxReportReq) Validate() error {
+	var msgs []string
+
+	if req.Year < 0 || req.Year > 9999 {
+		msgs = append(msgs, "year must be between 1 and 9999")
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
+	}
+	return nil
+}
+
+// TaxReportResp capital gains tax report response struct
+type TaxReportResp struct {
+	PortfolioID     string             `json:"portfolioid"`
+	Country         string             `json:"country"`
+	Year            int                `json:"year"`
+	From            time.Time          `json:"from"`
+	To              time.Time          `json:"to"`
+	Currency        string             `json:"currency"`
+	CostBasisMethod int                `json:"cost_basis_method"`
+	Proceeds        entities.Money     `json:"proceeds"`
+	CostBasis       entities.Money     `json:"cost_basis"`
+	Gain            entities.Money     `json:"gain"`
+	ExemptGain      entities.Money     `json:"exempt_gain"`
+	Allowance       entities.Money     `json:"allowance"`
+	TaxableGain     entities.Money     `json:"taxable_gain"`
+	Treatments      []TaxTreatmentResp `json:"treatments"`
+	Sales           []TaxSaleResp      `json:"sales"`
+}
+
+// TaxTreatmentResp totals of the sales given a tax treatment response struct
+type TaxTreatmentResp struct {
+	Treatment   string         `json:"treatment"`
+	Sales       int            `json:"sales"`
+	Proceeds    entities.Money `json:"proceeds"`
+	Gain        entities.Money `json:"gain"`
+	TaxableGain entities.Money `json:"taxable_gain"`
+}
+
+// TaxSaleResp sale of a tax report response struct
+type TaxSaleResp struct {
+	BuyTransactionID  string          `json:"buy_transactionid"`
+	SellTransactionID string          `json:"sell_transactionid"`
+	InstrumentID      string          `json:"instrumentid"`
+	InstrumentType    string          `json:"instrument_type"`
+	Quantity          decimal.Decimal `json:"quantity"`
+	AcquiredAt        time.Time       `json:"acquired_at"`
+	SoldAt            time.Time       `json:"sold_at"`
+	HoldingDays       int             `json:"holding_days"`
+	CostBasis         entities.Money  `json:"cost_basis"`
+	Proceeds          entities.Money  `json:"proceeds"`
+	Gain              entities.Money  `json:"gain"`
+	Treatment         string          `json:"treatment"`
+	Rule              string          `json:"rule"`
+	TaxableGain       entities.Money  `json:"taxable_gain"`
+}
+
+// DividendTaxReportResp dividend withholding tax report response struct
+type DividendTaxReportResp struct {
+	PortfolioID      string                `json:"portfolioid"`
+	Country          string                `json:"country"`
+	Year             int                   `json:"year"`
+	From             time.Time             `json:"from"`
+	To               time.Time             `json:"to"`
+	Currency         string                `json:"currency"`
+	Gross            entities.Money        `json:"gross"`
+	Withholding      entities.Money        `json:"withholding"`
+	Net              entities.Money        `json:"net"`
+	ForeignTaxCredit entities.Money        `json:"foreign_tax_credit"`
+	Reclaimable      entities.Money        `json:"reclaimable"`
+	Countries        []DividendCountryResp `json:"countries"`
+	Payments         []DividendPaymentResp `json:"payments"`
+}
+
+// DividendCountryResp totals of the dividends paid by the issuers of a country response struct
+type DividendCountryResp struct {
+	Country          string         `json:"country"`
+	Payments         int            `json:"payments"`
+	Gross            entities.Money `json:"gross"`
+	Withholding      entities.Money `json:"withholding"`
+	Net              entities.Money `json:"net"`
+	ForeignTaxCredit entities.Money `json:"foreign_tax_credit"`
+	Reclaimable      entities.Money `json:"reclaimable"`
+}
+
+// DividendPaymentResp dividend paid to a portfolio response struct
+type DividendPaymentResp struct {
+	DividendID          string          `json:"dividendid"`
+	StockID             string          `json:"stockid"`
+	Country             string          `json:"country"`
+	ExDate              time.Time       `json:"ex_date"`
+	PayDate             time.Time       `json:"pay_date"`
+	Shares              decimal.Decimal `json:"shares"`
+	DividendPerShare    entities.Money  `json:"dividend_per_share"`
+	Gross               entities.Money  `json:"gross"`
+	WithholdingRate     decimal.Decimal `json:"withholding_rate"`
+	Withholding         entities.Money  `json:"withholding"`
+	WithholdingRecorded bool            `json:"withholding_recorded"`
+	Net                 entities.Money  `json:"net"`
+	TreatyRate          decimal.Decimal `json:"treaty_rate"`
+	ForeignTaxCredit    entities.Money  `json:"foreign_tax_credit"`
+	Reclaimable         entities.Money  `json:"reclaimable"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateTaxReportReq_Ok checks that Validate does not return an error when valid requests are received
+func TestValidateTaxReportReq_Ok(t *testing.T) {
+	// Arrange
+	reqs := []TaxReportReq{{}, {Year: 2024}}
+
+	for _, req := range reqs {
+		// Act
+		err := req.Validate()
+
+		// Assert
+		assert.Nil(t, err, req.Year)
+	}
+}
+
+// TestValidateTaxReportReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateTaxReportReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := TaxReportReq{Year: -1}
+	expectedError := "year must be between 1 and 9999"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
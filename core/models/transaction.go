@@ -6,47 +6,45 @@ import (
 	"time"
 
 	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
 )
 
 // TransactionResp transaction response struct
 type TransactionResp struct {
-	TransactionID    string    `json:"transactionid"`
-	StockID          string    `json:"stockid"`
-	CryptocurrencyID string    `json:"cryptocurrencyid"`
-	Quantity         string    `json:"quantity"`
-	TransactionPrice string    `json:"transaction_price"`
-	TransactionDate  time.Time `json:"transaction_date"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	TransactionID    string          `json:"transactionid"`
+	HoldingID        string          `json:"holdingid"`
+	StockID          string          `json:"stockid"`
+	CryptocurrencyID string          `json:"cryptocurrencyid"`
+	TransactionType  string          `json:"transaction_type"`
+	Quantity         decimal.Decimal `json:"quantity"`
+	TransactionPrice entities.Money  `json:"transaction_price"`
+	TransactionDate  time.Time       `json:"transaction_date"`
+	Extid            string          `json:"extid,omitempty"`
+	ImportBatchID    string          `json:"import_batchid,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
 }
 
-// CreateTransactionReq broker request struct
+// CreateTransactionReq transaction request struct. TransactionType is one of the types of entities.TransactionTypes, a transaction
+// without a type being a buy or a sell by the sign of its quantity. For dividends, fees, deposits, withdrawals and interest the
+// transaction price is their amount and the quantity is zero.
 type CreateTransactionReq struct {
-	TransactionID    string    `json:"-"`
-	StockID          string    `json:"stockid"`
-	CryptocurrencyID string    `json:"cryptocurrencyid"`
-	Quantity         int16     `json:"quantity"`
-	TransactionPrice string    `json:"transaction_price"`
-	TransactionDate  time.Time `json:"transaction_date"`
-	CreatedAt        time.Time `json:"-"`
-	UpdatedAt        time.Time `json:"-"`
+	TransactionID    string          `json:"-"`
+	HoldingID        string          `json:"holdingid"`
+	StockID          string          `json:"stockid"`
+	CryptocurrencyID string          `json:"cryptocurrencyid"`
+	TransactionType  string          `json:"transaction_type"`
+	Quantity         decimal.Decimal `json:"quantity"`
+	TransactionPrice entities.Money  `json:"transaction_price"`
+	TransactionDate  time.Time       `json:"transaction_date"`
+	CreatedAt        time.Time       `json:"-"`
+	UpdatedAt        time.Time       `json:"-"`
 }
 
+// Validate checks that a given CreateTransactionReq is valid for its type
 func (req CreateTransactionReq) Validate() error {
-	var msgs []string
-
-	if req.StockID == "" && req.CryptocurrencyID == "" {
-		msgs = append(msgs, "either stock id or cryptocurrency id must be provided")
-	}
-	if req.Quantity == 0 {
-		msgs = append(msgs, "quantity cannot be zero")
-	}
-	if req.TransactionPrice == "" {
-		msgs = append(msgs, "transaction price cannot be empty")
-	}
-	if req.TransactionDate == (time.Time{}) {
-		msgs = append(msgs, "transaction date cannot be empty")
-	}
+	msgs := transactionMsgs(req.TransactionType, req.StockID, req.CryptocurrencyID, req.Quantity, req.TransactionPrice, req.TransactionDate)
 
 	if len(msgs) > 0 {
 		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
@@ -57,31 +55,21 @@ func (req CreateTransactionReq) Validate() error {
 
 // UpdateTransactionReq update transaction request struct
 type UpdateTransactionReq struct {
-	TransactionID    string    `json:"-"`
-	StockID          string    `json:"stockid"`
-	CryptocurrencyID string    `json:"cryptocurrencyid"`
-	Quantity         int16     `json:"quantity"`
-	TransactionPrice string    `json:"transaction_price"`
-	TransactionDate  time.Time `json:"transaction_date"`
-	CreatedAt        time.Time `json:"-"`
-	UpdatedAt        time.Time `json:"-"`
+	TransactionID    string          `json:"-"`
+	HoldingID        string          `json:"holdingid"`
+	StockID          string          `json:"stockid"`
+	CryptocurrencyID string          `json:"cryptocurrencyid"`
+	TransactionType  string          `json:"transaction_type"`
+	Quantity         decimal.Decimal `json:"quantity"`
+	TransactionPrice entities.Money  `json:"transaction_price"`
+	TransactionDate  time.Time       `json:"transaction_date"`
+	CreatedAt        time.Time       `json:"-"`
+	UpdatedAt        time.Time       `json:"-"`
 }
 
+// Validate checks that a given UpdateTransactionReq is valid for its type
 func (req UpdateTransactionReq) Validate() error {
-	var msgs []string
-
-	if req.StockID == "" && req.CryptocurrencyID == "" {
-		msgs = append(msgs, "either stock id or cryptocurrency id must be provided")
-	}
-	if req.Quantity == 0 {
-		msgs = append(msgs, "quantity cannot be zero")
-	}
-	if req.TransactionPrice == "" {
-		msgs = append(msgs, "transaction price cannot be empty")
-	}
-	if req.TransactionDate == (time.Time{}) {
-		msgs = append(msgs, "transaction date cannot be empty")
-	}
+	msgs := transactionMsgs(req.TransactionType, req.StockID, req.CryptocurrencyID, req.Quantity, req.TransactionPrice, req.TransactionDate)
 
 	if len(msgs) > 0 {
 		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
@@ -89,3 +77,71 @@ func (req UpdateTransactionReq) Validate() error {
 
 	return nil
 }
+
+// CashBalanceResp cash balance response struct, adding up the cash the transactions of a portfolio moved in one currency
+type CashBalanceResp struct {
+	Currency    string         `json:"currency"`
+	Balance     entities.Money `json:"balance"`
+	Deposits    entities.Money `json:"deposits"`
+	Withdrawals entities.Money `json:"withdrawals"`
+	Bought      entities.Money `json:"bought"`
+	Sold        entities.Money `json:"sold"`
+	Dividends   entities.Money `json:"dividends"`
+	Interest    entities.Money `json:"interest"`
+	Fees        entities.Money `json:"fees"`
+}
+
+// CashBalancesResp cash balances response struct of a portfolio, with a balance per currency
+type CashBalancesResp struct {
+	PortfolioID string            `json:"portfolioid"`
+	Balances    []CashBalanceResp `json:"balances"`
+}
+
+// transactionMsgs returns the validation messages of a transaction of a type. Trades and transfers need an instrument, a quantity and
+// a price, splits an instrument and a quantity without a price, and cash movements an amount without a quantity, dividends being paid
+// for an instrument and deposits and withdrawals for none.
+func transactionMsgs(transactionType, stockID, cryptocurrencyID string, quantity decimal.Decimal, price entities.Money, date time.Time) []string {
+	var msgs []string
+	instrument := stockID != "" || cryptocurrencyID != ""
+
+	switch transactionType {
+	case "", entities.TransactionTypeBuy, entities.TransactionTypeSell, entities.TransactionTypeTransferIn, entities.TransactionTypeTransferOut:
+		if !instrument {
+			msgs = append(msgs, "either stock id or cryptocurrency id must be provided")
+		}
+		if quantity.IsZero() {
+			msgs = append(msgs, "quantity cannot be zero")
+		}
+		msgs = append(msgs, moneyMsgs("transaction price", price)...)
+	case entities.TransactionTypeSplit:
+		if !instrument {
+			msgs = append(msgs, "either stock id or cryptocurrency id must be provided")
+		}
+		if quantity.IsZero() {
+			msgs = append(msgs, "quantity cannot be zero")
+		}
+		if !price.Amount.IsZero() {
+			msgs = append(msgs, "transaction price must be zero for SPLIT transactions")
+		}
+	case entities.TransactionTypeDividend, entities.TransactionTypeFee, entities.TransactionTypeInterest, entities.TransactionTypeDeposit, entities.TransactionTypeWithdrawal:
+		if transactionType == entities.TransactionTypeDividend && !instrument {
+			msgs = append(msgs, "either stock id or cryptocurrency id must be provided")
+		}
+		if (transactionType == entities.TransactionTypeDeposit || transactionType == entities.TransactionTypeWithdrawal) && instrument {
+			msgs = append(msgs, fmt.Sprintf("%s transactions cannot have a stock or cryptocurrency", transactionType))
+		}
+		if !quantity.IsZero() {
+			msgs = append(msgs, fmt.Sprintf("quantity must be zero for %s transactions", transactionType))
+		}
+		msgs = append(msgs, moneyMsgs("transaction price", price)...)
+	default:
+		msgs = append(msgs, fmt.Sprintf("transaction type must be one of %s", strings.Join(entities.TransactionTypes, ", ")))
+	}
+	if price.Amount.IsNegative() {
+		msgs = append(msgs, "transaction price cannot be negative")
+	}
+	if date == (time.Time{}) {
+		msgs = append(msgs, "transaction date cannot be empty")
+	}
+	return msgs
+}
@@ -5,7 +5,9 @@ import (
 	"time"
 
 	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
 )
 
 // TestValidateCreateTransactionReq_Ok checks that Validate does not return an error when a valid request is received
@@ -14,8 +16,8 @@ func TestValidateCreateTransactionReq_Ok(t *testing.T) {
 	req := CreateTransactionReq{
 		StockID:          "StockID",
 		CryptocurrencyID: "CryptocurrencyID",
-		Quantity:         10,
-		TransactionPrice: "TransactionPrice",
+		Quantity:         decimal.NewFromInt(10),
+		TransactionPrice: entities.NewMoney(decimal.RequireFromString("12.5"), "CZK"),
 		TransactionDate:  time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC),
 	}
 
@@ -30,7 +32,7 @@ func TestValidateCreateTransactionReq_Ok(t *testing.T) {
 func TestValidateCreateTransactionReq_InvalidRequest(t *testing.T) {
 	// Arrange
 	req := CreateTransactionReq{}
-	expectedError := "either stock id or cryptocurrency id must be provided | quantity cannot be zero | transaction price cannot be empty | transaction date cannot be empty"
+	expectedError := "either stock id or cryptocurrency id must be provided | quantity cannot be zero | transaction price cannot be zero | transaction price currency cannot be empty | transaction date cannot be empty"
 
 	// Act
 	err := req.Validate()
@@ -41,14 +43,98 @@ func TestValidateCreateTransactionReq_InvalidRequest(t *testing.T) {
 	assert.Equal(t, expectedError, err.Error())
 }
 
+// TestValidateCreateTransactionReq_InvalidCurrency checks that Validate returns an error when the transaction price currency is not an ISO 4217 code
+func TestValidateCreateTransactionReq_InvalidCurrency(t *testing.T) {
+	// Arrange
+	req := CreateTransactionReq{
+		StockID:          "StockID",
+		Quantity:         decimal.NewFromInt(10),
+		TransactionPrice: entities.NewMoney(decimal.RequireFromString("12.5"), "czk"),
+		TransactionDate:  time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC),
+	}
+	expectedError := "transaction price currency must be a 3-letter ISO 4217 code"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidateCreateTransactionReq_Types checks that Validate accepts the transactions of every type with the fields their type needs
+func TestValidateCreateTransactionReq_Types(t *testing.T) {
+	// Arrange
+	date := time.Date(2024, time.May, 2, 0, 0, 0, 0, time.UTC)
+	amount := entities.NewMoney(decimal.RequireFromString("25.5"), "USD")
+	reqs := []CreateTransactionReq{
+		{TransactionType: entities.TransactionTypeBuy, StockID: "StockID", Quantity: decimal.NewFromInt(10), TransactionPrice: amount, TransactionDate: date},
+		{TransactionType: entities.TransactionTypeSell, StockID: "StockID", Quantity: decimal.NewFromInt(-10), TransactionPrice: amount, TransactionDate: date},
+		{TransactionType: entities.TransactionTypeTransferIn, CryptocurrencyID: "CryptocurrencyID", Quantity: decimal.NewFromInt(1), TransactionPrice: amount, TransactionDate: date},
+		{TransactionType: entities.TransactionTypeTransferOut, CryptocurrencyID: "CryptocurrencyID", Quantity: decimal.NewFromInt(1), TransactionPrice: amount, TransactionDate: date},
+		{TransactionType: entities.TransactionTypeSplit, StockID: "StockID", Quantity: decimal.NewFromInt(30), TransactionDate: date},
+		{TransactionType: entities.TransactionTypeDividend, StockID: "StockID", TransactionPrice: amount, TransactionDate: date},
+		{TransactionType: entities.TransactionTypeFee, TransactionPrice: amount, TransactionDate: date},
+		{TransactionType: entities.TransactionTypeFee, StockID: "StockID", TransactionPrice: amount, TransactionDate: date},
+		{TransactionType: entities.TransactionTypeInterest, TransactionPrice: amount, TransactionDate: date},
+		{TransactionType: entities.TransactionTypeDeposit, TransactionPrice: amount, TransactionDate: date},
+		{TransactionType: entities.TransactionTypeWithdrawal, TransactionPrice: amount, TransactionDate: date},
+	}
+
+	for _, req := range reqs {
+		// Act
+		err := req.Validate()
+
+		// Assert
+		assert.Nil(t, err, req.TransactionType)
+	}
+}
+
+// TestValidateCreateTransactionReq_InvalidForType checks that Validate returns the errors of the fields a type does not allow
+func TestValidateCreateTransactionReq_InvalidForType(t *testing.T) {
+	// Arrange
+	date := time.Date(2024, time.May, 2, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		req           CreateTransactionReq
+		expectedError string
+	}{
+		{
+			req:           CreateTransactionReq{TransactionType: entities.TransactionTypeDeposit, StockID: "StockID", Quantity: decimal.NewFromInt(1), TransactionPrice: entities.NewMoney(decimal.NewFromInt(-100), "USD"), TransactionDate: date},
+			expectedError: "DEPOSIT transactions cannot have a stock or cryptocurrency | quantity must be zero for DEPOSIT transactions | transaction price cannot be negative",
+		},
+		{
+			req:           CreateTransactionReq{TransactionType: entities.TransactionTypeDividend, TransactionPrice: entities.NewMoney(decimal.NewFromInt(5), "USD"), TransactionDate: date},
+			expectedError: "either stock id or cryptocurrency id must be provided",
+		},
+		{
+			req:           CreateTransactionReq{TransactionType: entities.TransactionTypeSplit, StockID: "StockID", TransactionPrice: entities.NewMoney(decimal.NewFromInt(5), "USD"), TransactionDate: date},
+			expectedError: "quantity cannot be zero | transaction price must be zero for SPLIT transactions",
+		},
+		{
+			req:           CreateTransactionReq{TransactionType: "SWAP", TransactionDate: date},
+			expectedError: "transaction type must be one of BUY, SELL, DIVIDEND, FEE, DEPOSIT, WITHDRAWAL, INTEREST, TRANSFER_IN, TRANSFER_OUT, SPLIT",
+		},
+	}
+
+	for _, c := range cases {
+		// Act
+		err := c.req.Validate()
+
+		// Assert
+		assert.IsType(t, wrappers.ValidationErr, err)
+		assert.Equal(t, c.expectedError, err.Error())
+	}
+}
+
 // TestValidateCreateTransactionReq_Ok checks that Validate does not return an error when a valid request is received
 func TestValidateUpdateTransactionReq_Ok(t *testing.T) {
 	// Arrange
 	req := UpdateTransactionReq{
 		StockID:          "StockID",
 		CryptocurrencyID: "CryptocurrencyID",
-		Quantity:         10,
-		TransactionPrice: "TransactionPrice",
+		Quantity:         decimal.NewFromInt(10),
+		TransactionPrice: entities.NewMoney(decimal.RequireFromString("12.5"), "CZK"),
 		TransactionDate:  time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC),
 	}
 
@@ -63,7 +149,7 @@ func TestValidateUpdateTransactionReq_Ok(t *testing.T) {
 func TestValidateUpdateTransactionReq_InvalidRequest(t *testing.T) {
 	// Arrange
 	req := UpdateTransactionReq{}
-	expectedError := "either stock id or cryptocurrency id must be provided | quantity cannot be zero | transaction price cannot be empty | transaction date cannot be empty"
+	expectedError := "either stock id or cryptocurrency id must be provided | quantity cannot be zero | transaction price cannot be zero | transaction price currency cannot be empty | transaction date cannot be empty"
 
 	// Act
 	err := req.Validate()
@@ -15,6 +15,7 @@ type UserResp struct {
 	Lastname     string    `json:"lastname"`
 	Email        string    `json:"email"`
 	PasswordHash string    `json:"-"`
+	BaseCurrency string    `json:"base_currency"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
@@ -26,6 +27,7 @@ type CreateUserReq struct {
 	Lastname     string    `json:"lastname"`
 	Email        string    `json:"email"`
 	PasswordHash string    `json:"password"`
+	BaseCurrency string    `json:"base_currency"`
 	CreatedAt    time.Time `json:"-"`
 	UpdatedAt    time.Time `json:"-"`
 }
@@ -40,6 +42,9 @@ func (req CreateUserReq) Validate() error {
 	if req.PasswordHash == "" {
 		msgs = append(msgs, "password cannot be empty")
 	}
+	if req.BaseCurrency != "" && !validCurrencyCode(req.BaseCurrency) {
+		msgs = append(msgs, "base currency must be a 3-letter ISO 4217 code")
+	}
 
 	if len(msgs) > 0 {
 		return wrappers.NewValidationErr(fmt.Errorf(strings.Join(msgs, " | ")))
@@ -80,12 +85,22 @@ type LoginUserResp struct {
 
 // UpdateUserReq update user request struct
 type UpdateUserReq struct {
-	UserID      string     `json:"-"`
-	Firstname   *string    `json:"firstname"`
-	Lastname    *string    `json:"lastname"`
-	Email       *string    `json:"email"`
-	OldPassword *string    `json:"old_password"`
-	NewPassword *string    `json:"new_password"`
-	CreatedAt   *time.Time `json:"-"`
-	UpdatedAt   *time.Time `json:"-"`
+	UserID       string     `json:"-"`
+	Firstname    *string    `json:"firstname"`
+	Lastname     *string    `json:"lastname"`
+	Email        *string    `json:"email"`
+	OldPassword  *string    `json:"old_password"`
+	NewPassword  *string    `json:"new_password"`
+	BaseCurrency *string    `json:"base_currency"`
+	CreatedAt    *time.Time `json:"-"`
+	UpdatedAt    *time.Time `json:"-"`
+}
+
+// Validate checks that a given UpdateUserReq is valid
+func (req UpdateUserReq) Validate() error {
+	if req.BaseCurrency != nil && *req.BaseCurrency != "" && !validCurrencyCode(*req.BaseCurrency) {
+		return wrappers.NewValidationErr(fmt.Errorf("base currency must be a 3-letter ISO 4217 code"))
+	}
+
+	return nil
 }
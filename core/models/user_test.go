@@ -66,3 +66,36 @@ func TestValidateLoginUserReq_InvalidRequest(t *testing.T) {
 	assert.IsType(t, wrappers.ValidationErr, err)
 	assert.Equal(t, expectedError, err.Error())
 }
+
+// TestValidateCreateUserReq_InvalidBaseCurrency checks that Validate returns an error when the base currency is not an ISO 4217 code
+func TestValidateCreateUserReq_InvalidBaseCurrency(t *testing.T) {
+	// Arrange
+	req := CreateUserReq{
+		Email:        "test@test.com",
+		PasswordHash: "test",
+		BaseCurrency: "euro",
+	}
+	expectedError := "base currency must be a 3-letter ISO 4217 code"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestValidateUpdateUserReq_InvalidBaseCurrency checks that Validate returns an error when the base currency is not an ISO 4217 code
+func TestValidateUpdateUserReq_InvalidBaseCurrency(t *testing.T) {
+	// Arrange
+	currency := "euro"
+	req := UpdateUserReq{BaseCurrency: &currency}
+	expectedError := "base currency must be a 3-letter ISO 4217 code"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
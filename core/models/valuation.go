@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// PositionResp open position response struct
+type PositionResp struct {
+	InstrumentID      string          `json:"instrumentid"`
+	InstrumentType    string          `json:"instrument_type"`
+	Quantity          decimal.Decimal `json:"quantity"`
+	AverageCost       entities.Money  `json:"average_cost"`
+	CostBasis         entities.Money  `json:"cost_basis"`
+	MarketPrice       entities.Money  `json:"market_price"`
+	MarketValue       entities.Money  `json:"market_value"`
+	UnrealizedGain    entities.Money  `json:"unrealized_gain"`
+	UnrealizedGainPct float64         `json:"unrealized_gain_pct"`
+	// Base amounts are in the base currency of the valuation and are zero when it has none
+	BaseCostBasis      entities.Money `json:"base_cost_basis"`
+	BaseMarketValue    entities.Money `json:"base_market_value"`
+	BaseUnrealizedGain entities.Money `json:"base_unrealized_gain"`
+	Priced             bool           `json:"priced"`
+	LastTradeDate      time.Time      `json:"last_trade_date"`
+}
+
+// ValuationResp portfolio valuation response struct
+type ValuationResp struct {
+	PortfolioID     string `json:"portfolioid"`
+	CostBasisMethod int    `json:"cost_basis_method"`
+	// BaseCurrency is the currency of the totals, empty when they are summed in the currencies of the positions
+	BaseCurrency      string         `json:"base_currency"`
+	Positions         []PositionResp `json:"positions"`
+	CostBasis         entities.Money `json:"cost_basis"`
+	MarketValue       entities.Money `json:"market_value"`
+	UnrealizedGain    entities.Money `json:"unrealized_gain"`
+	UnrealizedGainPct float64        `json:"unrealized_gain_pct"`
+	RealizedGain      entities.Money `json:"realized_gain"`
+	ValuedAt          time.Time      `json:"valued_at"`
+}
@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/repository"
+	"github.com/tkudlicka/portflux-api/core/models"
+)
+
+// AlertRuleRepository interface
+type AlertRuleRepository interface {
+	repository.Repository
+	GetByPortfolioID(ctx context.Context, portfolioID string) ([]interface{}, error)
+}
+
+// AlertRepository interface
+type AlertRepository interface {
+	repository.Repository
+	CreateUnique(ctx context.Context, alert interface{}) (string, bool, error)
+	GetByPortfolioID(ctx context.Context, portfolioID string) ([]interface{}, error)
+	GetPending(ctx context.Context) ([]interface{}, error)
+	MarkNotified(ctx context.Context, ID string, notifiedAt time.Time) error
+}
+
+// AlertService interface
+type AlertService interface {
+	CreateRule(ctx context.Context, userID, portfolioID string, rule models.CreateAlertRuleReq) (models.CreationResp, error)
+	GetRules(ctx context.Context, userID, portfolioID string) ([]models.AlertRuleResp, error)
+	GetRule(ctx context.Context, userID, portfolioID, ruleID string) (models.AlertRuleResp, error)
+	UpdateRule(ctx context.Context, userID, portfolioID, ruleID string, rule models.UpdateAlertRuleReq) error
+	DeleteRule(ctx context.Context, userID, portfolioID, ruleID string) error
+	GetAlerts(ctx context.Context, userID, portfolioID string) ([]models.AlertResp, error)
+	Evaluate(ctx context.Context) (models.AlertEvaluationResp, error)
+}
@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/scv-go-tools/v3/repository"
+	"github.com/tkudlicka/portflux-api/core/models"
+)
+
+// BrokerAccountRepository interface
+type BrokerAccountRepository interface {
+	repository.Repository
+	GetByUserID(ctx context.Context, userID string) ([]interface{}, error)
+}
+
+// BrokerAccountService interface
+type BrokerAccountService interface {
+	Create(ctx context.Context, userID string, account models.CreateBrokerAccountReq) (models.CreationResp, error)
+	GetAll(ctx context.Context, userID string) ([]models.BrokerAccountResp, error)
+	GetByID(ctx context.Context, userID, ID string) (models.BrokerAccountResp, error)
+	Update(ctx context.Context, userID, ID string, account models.UpdateBrokerAccountReq) error
+	Delete(ctx context.Context, userID, ID string) error
+	AttachHolding(ctx context.Context, userID, ID, holdingID string) error
+}
@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/scv-go-tools/v3/repository"
+	"github.com/tkudlicka/portflux-api/core/models"
+)
+
+// CalendarFeedRepository interface
+type CalendarFeedRepository interface {
+	repository.Repository
+	Upsert(ctx context.Context, feed interface{}) (string, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (interface{}, error)
+	DeleteByUserID(ctx context.Context, userID string) error
+}
+
+// CalendarService interface
+type CalendarService interface {
+	RotateToken(ctx context.Context, userID string) (models.CalendarTokenResp, error)
+	RevokeToken(ctx context.Context, userID string) error
+	Feed(ctx context.Context, token string) ([]byte, error)
+}
@@ -0,0 +1,30 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/repository"
+	"github.com/tkudlicka/portflux-api/core/models"
+)
+
+// CompanyEventRepository interface
+type CompanyEventRepository interface {
+	repository.Repository
+	Upsert(ctx context.Context, event interface{}) (string, error)
+	GetByStockID(ctx context.Context, stockID string, from, to time.Time) ([]interface{}, error)
+	GetRange(ctx context.Context, from, to time.Time) ([]interface{}, error)
+	GetNotifiedPortfolios(ctx context.Context, eventID string) ([]string, error)
+	MarkNotified(ctx context.Context, eventID, portfolioID string, notifiedAt time.Time) error
+}
+
+// CompanyEventService interface
+type CompanyEventService interface {
+	Create(ctx context.Context, stockID string, event models.CreateCompanyEventReq) (models.CreationResp, error)
+	GetByStockID(ctx context.Context, stockID string, req models.CompanyEventsReq) ([]models.CompanyEventResp, error)
+	Delete(ctx context.Context, stockID, eventID string) error
+	Import(ctx context.Context, file io.Reader) (models.CompanyEventImportResp, error)
+	Sync(ctx context.Context) (models.CompanyEventSyncResp, error)
+	Notify(ctx context.Context) (models.CompanyEventNotifyResp, error)
+}
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/scv-go-tools/v3/repository"
+	"github.com/tkudlicka/portflux-api/core/models"
+)
+
+// CorporateActionRepository interface
+type CorporateActionRepository interface {
+	repository.Repository
+	GetByStockID(ctx context.Context, stockID string) ([]interface{}, error)
+}
+
+// CorporateActionService interface
+type CorporateActionService interface {
+	Create(ctx context.Context, stockID string, action models.CreateCorporateActionReq) (models.CreationResp, error)
+	GetByStockID(ctx context.Context, stockID string) ([]models.CorporateActionResp, error)
+	Delete(ctx context.Context, stockID, actionID string) error
+}
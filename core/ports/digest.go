@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/repository"
+	"github.com/tkudlicka/portflux-api/core/models"
+)
+
+// DigestRepository interface
+type DigestRepository interface {
+	repository.Repository
+	Exists(ctx context.Context, portfolioID, period string, periodEnd time.Time) (bool, error)
+}
+
+// DigestService interface
+type DigestService interface {
+	Generate(ctx context.Context, userID, portfolioID string, req models.DigestReq) (models.DigestResp, error)
+	Send(ctx context.Context) (models.DigestSendResp, error)
+}
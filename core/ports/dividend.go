@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/sergicanet9/scv-go-tools/v3/repository"
+	"github.com/tkudlicka/portflux-api/core/entities"
 	"github.com/tkudlicka/portflux-api/core/models"
 )
 
@@ -11,6 +12,14 @@ import (
 type DividendRepository interface {
 	repository.Repository
 	CreateMany(ctx context.Context, entities []interface{}) ([]string, error)
+	StreamByPortfolioID(ctx context.Context, portfolioID string, fn func(entities.PortfolioDividend) error) error
+}
+
+// DividendWithholdingRepository interface
+type DividendWithholdingRepository interface {
+	repository.Repository
+	CreateMany(ctx context.Context, entities []interface{}) ([]string, error)
+	GetByPortfolioID(ctx context.Context, portfolioID string) ([]interface{}, error)
 }
 
 // DividendService interface
@@ -22,3 +31,10 @@ type DividendService interface {
 	Update(ctx context.Context, ID string, user models.UpdateUserReq) error
 	Delete(ctx context.Context, ID string) error
 }
+
+// DividendIncomeService interface
+type DividendIncomeService interface {
+	GetCalendar(ctx context.Context, userID, portfolioID string, req models.DividendCalendarReq) (models.DividendCalendarResp, error)
+	GetForecast(ctx context.Context, userID, portfolioID string) (models.DividendForecastResp, error)
+	GetYield(ctx context.Context, userID, portfolioID string) (models.DividendYieldResp, error)
+}
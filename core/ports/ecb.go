@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+	"io"
+
+	"github.com/tkudlicka/portflux-api/core/models"
+)
+
+// ECBImportService interface
+type ECBImportService interface {
+	Import(ctx context.Context, file io.Reader) (models.ECBImportResp, error)
+}
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"io"
+
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+)
+
+// PortfolioPerformanceCodec interface of the reader and writer of the XML files of Portfolio Performance
+type PortfolioPerformanceCodec interface {
+	Decode(file io.Reader) (entities.PPClient, error)
+	Encode(w io.Writer, client entities.PPClient) error
+}
+
+// ExportService interface
+type ExportService interface {
+	Export(ctx context.Context, userID, portfolioID string, req models.ExportReq, w io.Writer) error
+	Restore(ctx context.Context, userID string, req models.RestoreReq, archive io.ReadSeeker) (models.ArchiveRestoreResp, error)
+}
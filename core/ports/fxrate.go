@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/repository"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+)
+
+// FxRateRepository interface
+type FxRateRepository interface {
+	repository.Repository
+	CreateMany(ctx context.Context, entities []interface{}) ([]string, error)
+	GetLatest(ctx context.Context, baseCurrency, quoteCurrency string, date time.Time) (interface{}, error)
+}
+
+// CurrencyConverter interface
+type CurrencyConverter interface {
+	Convert(ctx context.Context, amount entities.Money, currency string, date time.Time) (entities.Money, error)
+}
+
+// FxRateService interface
+type FxRateService interface {
+	CurrencyConverter
+	CreateMany(ctx context.Context, rates []models.CreateFxRateReq) (models.MultiCreationResp, error)
+	GetRate(ctx context.Context, baseCurrency, quoteCurrency string, date time.Time) (models.FxRateResp, error)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/sergicanet9/scv-go-tools/v3/repository"
+	"github.com/tkudlicka/portflux-api/core/entities"
 	"github.com/tkudlicka/portflux-api/core/models"
 )
 
@@ -11,6 +12,7 @@ import (
 type HoldingRepository interface {
 	repository.Repository
 	CreateMany(ctx context.Context, entities []interface{}) ([]string, error)
+	StreamByPortfolioID(ctx context.Context, portfolioID string, fn func(entities.Holding) error) error
 }
 
 // HodingService interface
@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"context"
+	"io"
+
+	"github.com/sergicanet9/scv-go-tools/v3/repository"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+)
+
+// StatementParser interface of the parsers of the statements exported by a broker
+type StatementParser interface {
+	Broker() string
+	Parse(file io.Reader) (entities.Statement, error)
+}
+
+// ImportBatchRepository interface
+type ImportBatchRepository interface {
+	repository.Repository
+	GetByFingerprint(ctx context.Context, portfolioID, fingerprint string) (interface{}, error)
+	Rollback(ctx context.Context, ID string) (entities.ImportRollback, error)
+}
+
+// ImportService interface
+type ImportService interface {
+	Import(ctx context.Context, userID, portfolioID string, req models.ImportReq, file io.Reader) (models.ImportResp, error)
+	Rollback(ctx context.Context, userID, batchID string) (models.ImportRollbackResp, error)
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+	"io"
+
+	"github.com/tkudlicka/portflux-api/core/models"
+)
+
+// JournalService interface
+type JournalService interface {
+	Export(ctx context.Context, userID, portfolioID string, req models.JournalReq, w io.Writer) error
+}
@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/scv-go-tools/v3/repository"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+)
+
+// LotRepository interface
+type LotRepository interface {
+	repository.Repository
+	CreateMany(ctx context.Context, entities []interface{}) ([]string, error)
+	GetByPortfolioID(ctx context.Context, portfolioID string) ([]interface{}, error)
+	GetRealizedGainsByPortfolioID(ctx context.Context, portfolioID string) ([]interface{}, error)
+	StreamRealizedGainsByPortfolioID(ctx context.Context, portfolioID string, fn func(entities.RealizedGain) error) error
+	ReplaceLedger(ctx context.Context, portfolioID string, lots []interface{}, gains []interface{}) error
+}
+
+// LotService interface
+type LotService interface {
+	GetLots(ctx context.Context, userID, portfolioID string) ([]models.LotResp, error)
+	GetRealizedGains(ctx context.Context, userID, portfolioID string) ([]models.RealizedGainResp, error)
+	Rebuild(ctx context.Context, userID, portfolioID string) (models.LedgerResp, error)
+	UpdateCostBasisMethod(ctx context.Context, userID, portfolioID string, req models.UpdateCostBasisMethodReq) (models.LedgerResp, error)
+	Refresh(ctx context.Context, portfolioID string) error
+}
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// Mailer interface
+type Mailer interface {
+	Send(ctx context.Context, mail entities.Mail) error
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// MarketDataProvider interface
+type MarketDataProvider interface {
+	Name() string
+	Quote(ctx context.Context, symbol string) (entities.Quote, error)
+	Bars(ctx context.Context, symbol string, from, to time.Time) ([]entities.Bar, error)
+	Dividends(ctx context.Context, symbol string, from, to time.Time) ([]entities.DividendEvent, error)
+	Splits(ctx context.Context, symbol string, from, to time.Time) ([]entities.Split, error)
+}
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// Notifier interface
+type Notifier interface {
+	Notify(ctx context.Context, notification entities.Notification) error
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tkudlicka/portflux-api/core/models"
+)
+
+// PerformanceService interface
+type PerformanceService interface {
+	GetPortfolioPerformance(ctx context.Context, userID, portfolioID string, req models.PerformanceReq) (models.PerformanceResp, error)
+	GetHoldingPerformance(ctx context.Context, userID, holdingID string, req models.PerformanceReq) (models.PerformanceResp, error)
+}
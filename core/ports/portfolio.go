@@ -22,3 +22,9 @@ type PortfolioService interface {
 	Update(ctx context.Context, ID string, user models.UpdateUserReq) error
 	Delete(ctx context.Context, ID string) error
 }
+
+// ValuationService interface
+type ValuationService interface {
+	GetPortfolioValuation(ctx context.Context, userID, portfolioID string) (models.ValuationResp, error)
+	GetBrokerAccountValuation(ctx context.Context, userID, brokerAccountID string) (models.BrokerAccountValuationResp, error)
+}
@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/repository"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+)
+
+// PriceSource interface
+type PriceSource interface {
+	LatestPrice(ctx context.Context, instrumentID string) (entities.Money, error)
+	PriceAt(ctx context.Context, instrumentID string, date time.Time) (entities.Money, error)
+}
+
+// PriceRepository interface
+type PriceRepository interface {
+	repository.Repository
+	CreateMany(ctx context.Context, entities []interface{}) ([]string, error)
+	GetRange(ctx context.Context, instrumentID string, from, to time.Time) ([]interface{}, error)
+	GetLatest(ctx context.Context, instrumentID string, date time.Time) (interface{}, error)
+	SetLastPrice(ctx context.Context, lastPrice interface{}) error
+	GetLastPrice(ctx context.Context, instrumentID string) (interface{}, error)
+}
+
+// PriceService interface
+type PriceService interface {
+	CreateMany(ctx context.Context, prices []models.CreatePriceReq) (models.MultiCreationResp, error)
+	ImportCSV(ctx context.Context, req models.ImportPricesReq, file io.Reader) (models.PriceImportResp, error)
+	SetLastPrice(ctx context.Context, req models.SetLastPriceReq) error
+	GetStockPrices(ctx context.Context, stockID string, req models.PriceHistoryReq) (models.PriceHistoryResp, error)
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/scv-go-tools/v3/repository"
+	"github.com/tkudlicka/portflux-api/core/models"
+)
+
+// PriceRefreshRunRepository interface
+type PriceRefreshRunRepository interface {
+	repository.Repository
+	GetRecent(ctx context.Context, limit int) ([]interface{}, error)
+}
+
+// PriceRefreshService interface
+type PriceRefreshService interface {
+	Refresh(ctx context.Context) (models.PriceRefreshRunResp, error)
+	GetRuns(ctx context.Context, limit int) ([]models.PriceRefreshRunResp, error)
+}
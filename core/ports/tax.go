@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+)
+
+// TaxRule interface of the capital gains tax rules of a country
+type TaxRule interface {
+	Country() string
+	Currency() string
+	CostBasisMethod() int
+	Assess(sales []entities.TaxSale, from, to time.Time) entities.TaxAssessment
+}
+
+// TaxReportService interface
+type TaxReportService interface {
+	GetReport(ctx context.Context, userID, portfolioID string, req models.TaxReportReq) (models.TaxReportResp, error)
+	GetDividends(ctx context.Context, userID, portfolioID string, req models.TaxReportReq) (models.DividendTaxReportResp, error)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/sergicanet9/scv-go-tools/v3/repository"
+	"github.com/tkudlicka/portflux-api/core/entities"
 	"github.com/tkudlicka/portflux-api/core/models"
 )
 
@@ -11,14 +12,17 @@ import (
 type TransactionRepository interface {
 	repository.Repository
 	CreateMany(ctx context.Context, entities []interface{}) ([]string, error)
+	GetByPortfolioID(ctx context.Context, portfolioID string) ([]interface{}, error)
+	GetByBrokerAccountID(ctx context.Context, brokerAccountID string) ([]interface{}, error)
+	StreamByPortfolioID(ctx context.Context, portfolioID string, fn func(entities.Transaction) error) error
 }
 
 // TransactionService interface
 type TransactionService interface {
-	Create(ctx context.Context, user models.CreateUserReq) (models.CreationResp, error)
-	CreateMany(ctx context.Context, users []models.CreateUserReq) (models.MultiCreationResp, error)
-	GetAll(ctx context.Context) ([]models.UserResp, error)
-	GetByID(ctx context.Context, ID string) (models.UserResp, error)
-	Update(ctx context.Context, ID string, user models.UpdateUserReq) error
-	Delete(ctx context.Context, ID string) error
+	Create(ctx context.Context, userID, portfolioID string, transaction models.CreateTransactionReq) (models.CreationResp, error)
+	GetAll(ctx context.Context, userID, portfolioID string) ([]models.TransactionResp, error)
+	GetByID(ctx context.Context, userID, portfolioID, ID string) (models.TransactionResp, error)
+	Update(ctx context.Context, userID, portfolioID, ID string, transaction models.UpdateTransactionReq) error
+	Delete(ctx context.Context, userID, portfolioID, ID string) error
+	GetCashBalances(ctx context.Context, userID, portfolioID string) (models.CashBalancesResp, error)
 }
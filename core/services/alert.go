@@ -0,0 +1,407 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// alertService adapter of an alert service
+type alertService struct {
+	config                config.Config
+	ruleRepository        ports.AlertRuleRepository
+	repository            ports.AlertRepository
+	portfolioRepository   ports.PortfolioRepository
+	transactionRepository ports.TransactionRepository
+	priceSource           ports.PriceSource
+	notifier              ports.Notifier
+}
+
+// NewAlertService creates a new alert service
+func NewAlertService(cfg config.Config, ruleRepo ports.AlertRuleRepository, repo ports.AlertRepository, portfolioRepo ports.PortfolioRepository, transactionRepo ports.TransactionRepository, priceSource ports.PriceSource, notifier ports.Notifier) ports.AlertService {
+	return &alertService{
+		config:                cfg,
+		ruleRepository:        ruleRepo,
+		repository:            repo,
+		portfolioRepository:   portfolioRepo,
+		transactionRepository: transactionRepo,
+		priceSource:           priceSource,
+		notifier:              notifier,
+	}
+}
+
+// CreateRule creates an active alert rule in a portfolio of the user
+func (s *alertService) CreateRule(ctx context.Context, userID, portfolioID string, rule models.CreateAlertRuleReq) (resp models.CreationResp, err error) {
+	if err = rule.Validate(); err != nil {
+		return
+	}
+
+	if _, err = userPortfolio(ctx, s.portfolioRepository, userID, portfolioID); err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	rule.PortfolioID = portfolioID
+	rule.Active = true
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+	insertedID, err := s.ruleRepository.Create(ctx, entities.AlertRule(rule))
+	if err != nil {
+		return
+	}
+
+	resp = models.CreationResp{
+		InsertedID: insertedID,
+	}
+	return
+}
+
+// GetRules returns the alert rules of a portfolio of the user
+func (s *alertService) GetRules(ctx context.Context, userID, portfolioID string) (resp []models.AlertRuleResp, err error) {
+	resp = []models.AlertRuleResp{}
+	if _, err = userPortfolio(ctx, s.portfolioRepository, userID, portfolioID); err != nil {
+		return
+	}
+	result, err := s.ruleRepository.GetByPortfolioID(ctx, portfolioID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	for _, v := range result {
+		resp = append(resp, models.AlertRuleResp(*(v.(*entities.AlertRule))))
+	}
+	return
+}
+
+// GetRule returns an alert rule of a portfolio of the user
+func (s *alertService) GetRule(ctx context.Context, userID, portfolioID, ruleID string) (resp models.AlertRuleResp, err error) {
+	if _, err = userPortfolio(ctx, s.portfolioRepository, userID, portfolioID); err != nil {
+		return
+	}
+	return s.rule(ctx, portfolioID, ruleID)
+}
+
+// rule returns an alert rule of a portfolio
+func (s *alertService) rule(ctx context.Context, portfolioID, ruleID string) (resp models.AlertRuleResp, err error) {
+	result, err := s.ruleRepository.GetByID(ctx, ruleID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", ruleID))
+		}
+		return
+	}
+
+	rule := result.(*entities.AlertRule)
+	if rule.PortfolioID != portfolioID {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", ruleID))
+		return
+	}
+
+	resp = models.AlertRuleResp(*rule)
+	return
+}
+
+// UpdateRule changes the condition, threshold or state of an alert rule of a portfolio of the user
+func (s *alertService) UpdateRule(ctx context.Context, userID, portfolioID, ruleID string, rule models.UpdateAlertRuleReq) (err error) {
+	dbRule, err := s.GetRule(ctx, userID, portfolioID, ruleID)
+	if err != nil {
+		return
+	}
+
+	if rule.Condition != nil {
+		dbRule.Condition = *rule.Condition
+	}
+	if rule.Threshold != nil {
+		dbRule.Threshold = *rule.Threshold
+	}
+	if rule.Active != nil {
+		dbRule.Active = *rule.Active
+	}
+	if err = models.CreateAlertRuleReq(dbRule).Validate(); err != nil {
+		return
+	}
+
+	dbRule.AlertRuleID = ""
+	dbRule.UpdatedAt = time.Now().UTC()
+
+	err = s.ruleRepository.Update(ctx, ruleID, entities.AlertRule(dbRule))
+	return
+}
+
+// DeleteRule deletes an alert rule of a portfolio of the user
+func (s *alertService) DeleteRule(ctx context.Context, userID, portfolioID, ruleID string) (err error) {
+	if _, err = s.GetRule(ctx, userID, portfolioID, ruleID); err != nil {
+		return
+	}
+
+	err = s.ruleRepository.Delete(ctx, ruleID)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", ruleID))
+	}
+	return
+}
+
+// GetAlerts returns the alerts triggered in a portfolio of the user, newest first
+func (s *alertService) GetAlerts(ctx context.Context, userID, portfolioID string) (resp []models.AlertResp, err error) {
+	resp = []models.AlertResp{}
+	if _, err = userPortfolio(ctx, s.portfolioRepository, userID, portfolioID); err != nil {
+		return
+	}
+	result, err := s.repository.GetByPortfolioID(ctx, portfolioID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	for _, v := range result {
+		resp = append(resp, models.AlertResp(*(v.(*entities.Alert))))
+	}
+	return
+}
+
+// alertPortfolio is a portfolio whose rules are being evaluated, with its open positions folded on first use
+type alertPortfolio struct {
+	portfolio entities.Portfolio
+	positions map[string]position
+}
+
+// Evaluate checks the active alert rules of the portfolios with price alerts enabled against the latest prices. A rule triggers
+// at most once a day, and every alert not delivered yet, including the ones of previous runs, is sent to the portfolio owner.
+func (s *alertService) Evaluate(ctx context.Context) (resp models.AlertEvaluationResp, err error) {
+	result, err := s.ruleRepository.Get(ctx, map[string]interface{}{"active": true}, nil, nil)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return
+	}
+	err = nil
+
+	now := time.Now().UTC()
+	portfolios := map[string]*alertPortfolio{}
+	for _, v := range result {
+		rule := *(v.(*entities.AlertRule))
+
+		p, portfolioErr := s.alertPortfolio(ctx, portfolios, rule.PortfolioID)
+		if errors.Is(portfolioErr, wrappers.NonExistentErr) {
+			continue
+		}
+		if portfolioErr != nil {
+			err = portfolioErr
+			return
+		}
+		if !p.portfolio.PriceAlert {
+			continue
+		}
+		resp.Rules++
+
+		alert, triggered, checkErr := s.check(ctx, rule, p, now)
+		if checkErr != nil {
+			err = checkErr
+			return
+		}
+		if !triggered {
+			continue
+		}
+
+		_, created, createErr := s.repository.CreateUnique(ctx, alert)
+		if createErr != nil {
+			err = createErr
+			return
+		}
+		if created {
+			resp.Triggered++
+		}
+	}
+
+	resp.Notified, resp.Undelivered, err = s.deliver(ctx, portfolios)
+	return
+}
+
+// alertPortfolio returns a portfolio from the ones already loaded during an evaluation, loading it when missing
+func (s *alertService) alertPortfolio(ctx context.Context, portfolios map[string]*alertPortfolio, portfolioID string) (*alertPortfolio, error) {
+	if p, ok := portfolios[portfolioID]; ok {
+		return p, nil
+	}
+
+	result, err := s.portfolioRepository.GetByID(ctx, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &alertPortfolio{portfolio: *(result.(*entities.Portfolio))}
+	portfolios[portfolioID] = p
+	return p, nil
+}
+
+// check reports whether a rule is triggered by the latest price of its instrument, returning the alert to store when it is.
+// Rules on instruments without a price, or without the previous close or the open position a move needs, do not trigger.
+func (s *alertService) check(ctx context.Context, rule entities.AlertRule, p *alertPortfolio, now time.Time) (alert entities.Alert, triggered bool, err error) {
+	price, err := s.priceSource.LatestPrice(ctx, rule.InstrumentID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	var value decimal.Decimal
+	switch rule.Condition {
+	case entities.AlertConditionAbove:
+		value = price.Amount
+		triggered = price.Amount.GreaterThanOrEqual(rule.Threshold)
+	case entities.AlertConditionBelow:
+		value = price.Amount
+		triggered = price.Amount.LessThanOrEqual(rule.Threshold)
+	case entities.AlertConditionDayMove:
+		previous, previousErr := s.priceSource.PriceAt(ctx, rule.InstrumentID, startOfDay(now).Add(-oneDay))
+		if previousErr != nil {
+			if !errors.Is(previousErr, wrappers.NonExistentErr) {
+				err = previousErr
+			}
+			return
+		}
+		if !previous.Amount.IsPositive() || (previous.Currency != "" && price.Currency != "" && previous.Currency != price.Currency) {
+			return
+		}
+		value = changePct(price.Amount, previous.Amount)
+		triggered = moveReached(value, rule.Threshold)
+	case entities.AlertConditionCostBasisMove:
+		positions, positionsErr := s.positions(ctx, p)
+		if positionsErr != nil {
+			err = positionsErr
+			return
+		}
+		pos, ok := positions[rule.InstrumentID]
+		if !ok || !pos.averageCost().IsPositive() || (pos.currency != "" && price.Currency != "" && pos.currency != price.Currency) {
+			return
+		}
+		value = changePct(price.Amount, pos.averageCost())
+		triggered = moveReached(value, rule.Threshold)
+	}
+	if !triggered {
+		return
+	}
+
+	alert = entities.Alert{
+		AlertRuleID:    rule.AlertRuleID,
+		PortfolioID:    rule.PortfolioID,
+		InstrumentID:   rule.InstrumentID,
+		InstrumentType: rule.InstrumentType,
+		Condition:      rule.Condition,
+		Threshold:      rule.Threshold,
+		Price:          price,
+		Value:          value,
+		TriggerDate:    startOfDay(now),
+		TriggeredAt:    now,
+	}
+	return
+}
+
+// positions returns the open positions of a portfolio by instrument, folding its transactions on first use
+func (s *alertService) positions(ctx context.Context, p *alertPortfolio) (map[string]position, error) {
+	if p.positions != nil {
+		return p.positions, nil
+	}
+
+	result, err := s.transactionRepository.GetByPortfolioID(ctx, p.portfolio.PortfolioID)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return nil, err
+	}
+	transactions := make([]entities.Transaction, len(result))
+	for i, v := range result {
+		transactions[i] = *(v.(*entities.Transaction))
+	}
+
+	ledger, err := buildLedger(transactions, costBasisMethod(p.portfolio))
+	if err != nil {
+		return nil, err
+	}
+
+	p.positions = map[string]position{}
+	for _, pos := range ledger.positions {
+		p.positions[pos.instrumentID] = pos
+	}
+	return p.positions, nil
+}
+
+// deliver sends the alerts not delivered yet to the owners of their portfolios. An alert that cannot be delivered is kept
+// to be sent again on the next evaluation.
+func (s *alertService) deliver(ctx context.Context, portfolios map[string]*alertPortfolio) (notified, undelivered int, err error) {
+	result, err := s.repository.GetPending(ctx)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	for _, v := range result {
+		alert := *(v.(*entities.Alert))
+
+		p, portfolioErr := s.alertPortfolio(ctx, portfolios, alert.PortfolioID)
+		if portfolioErr != nil {
+			log.Printf("cannot deliver alert %s, error: %s", alert.AlertID, portfolioErr)
+			undelivered++
+			continue
+		}
+
+		notifyErr := s.notifier.Notify(ctx, alertNotification(p.portfolio, alert))
+		if notifyErr == nil {
+			notifyErr = s.repository.MarkNotified(ctx, alert.AlertID, time.Now().UTC())
+		}
+		if notifyErr != nil {
+			log.Printf("cannot deliver alert %s, error: %s", alert.AlertID, notifyErr)
+			undelivered++
+			continue
+		}
+		notified++
+	}
+	return
+}
+
+// alertNotification returns the message telling the owner of a portfolio about a triggered alert
+func alertNotification(portfolio entities.Portfolio, alert entities.Alert) entities.Notification {
+	var reason string
+	switch alert.Condition {
+	case entities.AlertConditionAbove:
+		reason = fmt.Sprintf("rose to %s, at or above %s", alert.Price, alert.Threshold)
+	case entities.AlertConditionBelow:
+		reason = fmt.Sprintf("fell to %s, at or below %s", alert.Price, alert.Threshold)
+	case entities.AlertConditionDayMove:
+		reason = fmt.Sprintf("moved %s%% since the previous close to %s", alert.Value, alert.Price)
+	case entities.AlertConditionCostBasisMove:
+		reason = fmt.Sprintf("moved %s%% from its average cost to %s", alert.Value, alert.Price)
+	}
+
+	return entities.Notification{
+		UserID:    portfolio.Userid,
+		Subject:   fmt.Sprintf("Price alert for %s %s in %s", alert.InstrumentType, alert.InstrumentID, portfolio.Name),
+		Message:   fmt.Sprintf("The price of %s %s %s on %s.", alert.InstrumentType, alert.InstrumentID, reason, alert.TriggeredAt.Format(time.RFC1123)),
+		CreatedAt: alert.TriggeredAt,
+	}
+}
+
+// changePct returns the change from reference to value in percent, rounded to two places
+func changePct(value, reference decimal.Decimal) decimal.Decimal {
+	return value.Sub(reference).Div(reference).Mul(decimal.NewFromInt(100)).Round(2)
+}
+
+// moveReached reports whether a change in percent reaches a signed threshold, a rise for a positive one and a fall for a negative one
+func moveReached(change, threshold decimal.Decimal) bool {
+	if threshold.IsPositive() {
+		return change.GreaterThanOrEqual(threshold)
+	}
+	return change.LessThanOrEqual(threshold)
+}
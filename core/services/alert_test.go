@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestNewAlertService_Ok checks that NewAlertService creates a new alertService struct
+func TestNewAlertService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+
+	// Act
+	service := NewAlertService(cfg, mocks.NewAlertRuleRepository(t), mocks.NewAlertRepository(t), mocks.NewPortfolioRepository(t), mocks.NewTransactionRepository(t), mocks.NewPriceSource(t), mocks.NewNotifier(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestCreateRule_Ok checks that CreateRule creates an active rule in the portfolio
+func TestCreateRule_Ok(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	ruleRepositoryMock := mocks.NewAlertRuleRepository(t)
+	ruleRepositoryMock.On(testutils.FunctionName(t, ports.AlertRuleRepository.Create), context.Background(), mock.MatchedBy(func(rule entities.AlertRule) bool {
+		return rule.PortfolioID == "portfolio-a" && rule.InstrumentID == "stock-a" && rule.Condition == entities.AlertConditionAbove && rule.Active && !rule.CreatedAt.IsZero()
+	})).Return("rule-a", nil).Once()
+
+	service := &alertService{
+		config:              config.Config{},
+		ruleRepository:      ruleRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+	}
+	req := models.CreateAlertRuleReq{InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Condition: entities.AlertConditionAbove, Threshold: decimal.NewFromInt(200)}
+
+	// Act
+	resp, err := service.CreateRule(context.Background(), "user-a", "portfolio-a", req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "rule-a", resp.InsertedID)
+}
+
+// TestCreateRule_PortfolioNotFound checks that CreateRule returns a non existent error when the portfolio does not exist
+func TestCreateRule_PortfolioNotFound(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &alertService{
+		config:              config.Config{},
+		portfolioRepository: portfolioRepositoryMock,
+	}
+	req := models.CreateAlertRuleReq{InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Condition: entities.AlertConditionAbove, Threshold: decimal.NewFromInt(200)}
+	expectedError := "ID portfolio-a not found"
+
+	// Act
+	_, err := service.CreateRule(context.Background(), "user-a", "portfolio-a", req)
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestCreateRule_OtherUser checks that CreateRule returns an unauthorized error and creates nothing when the portfolio belongs to
+// another user
+func TestCreateRule_OtherUser(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-b"}, nil).Once()
+
+	service := &alertService{
+		config:              config.Config{},
+		ruleRepository:      mocks.NewAlertRuleRepository(t),
+		portfolioRepository: portfolioRepositoryMock,
+	}
+	req := models.CreateAlertRuleReq{InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Condition: entities.AlertConditionAbove, Threshold: decimal.NewFromInt(200)}
+	expectedError := "portfolio portfolio-a belongs to another user"
+
+	// Act
+	_, err := service.CreateRule(context.Background(), "user-a", "portfolio-a", req)
+
+	// Assert
+	assert.IsType(t, wrappers.UnauthorizedErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestDeleteRule_OtherUser checks that DeleteRule returns an unauthorized error and keeps the rule when the portfolio belongs to
+// another user
+func TestDeleteRule_OtherUser(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-b"}, nil).Once()
+
+	service := &alertService{
+		config:              config.Config{},
+		ruleRepository:      mocks.NewAlertRuleRepository(t),
+		portfolioRepository: portfolioRepositoryMock,
+	}
+
+	// Act
+	err := service.DeleteRule(context.Background(), "user-a", "portfolio-a", "rule-a")
+
+	// Assert
+	assert.IsType(t, wrappers.UnauthorizedErr, err)
+}
+
+// TestCreateRule_InvalidRequest checks that CreateRule returns a validation error when the request is not valid
+func TestCreateRule_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := &alertService{config: config.Config{}}
+	req := models.CreateAlertRuleReq{InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Condition: entities.AlertConditionDayMove}
+
+	// Act
+	_, err := service.CreateRule(context.Background(), "user-a", "portfolio-a", req)
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
+
+// TestGetRule_OtherPortfolio checks that GetRule returns a non existent error when the rule belongs to another portfolio
+func TestGetRule_OtherPortfolio(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	ruleRepositoryMock := mocks.NewAlertRuleRepository(t)
+	ruleRepositoryMock.On(testutils.FunctionName(t, ports.AlertRuleRepository.GetByID), context.Background(), "rule-a").Return(&entities.AlertRule{AlertRuleID: "rule-a", PortfolioID: "portfolio-b"}, nil).Once()
+
+	service := &alertService{
+		config:              config.Config{},
+		ruleRepository:      ruleRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+	}
+	expectedError := "ID rule-a not found"
+
+	// Act
+	_, err := service.GetRule(context.Background(), "user-a", "portfolio-a", "rule-a")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetRules_NoRules checks that GetRules returns an empty list when the portfolio has no rules
+func TestGetRules_NoRules(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	ruleRepositoryMock := mocks.NewAlertRuleRepository(t)
+	ruleRepositoryMock.On(testutils.FunctionName(t, ports.AlertRuleRepository.GetByPortfolioID), context.Background(), "portfolio-a").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &alertService{
+		config:              config.Config{},
+		ruleRepository:      ruleRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetRules(context.Background(), "user-a", "portfolio-a")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []models.AlertRuleResp{}, resp)
+}
+
+// TestUpdateRule_Ok checks that UpdateRule merges the changes into the stored rule
+func TestUpdateRule_Ok(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	ruleRepositoryMock := mocks.NewAlertRuleRepository(t)
+	ruleRepositoryMock.On(testutils.FunctionName(t, ports.AlertRuleRepository.GetByID), context.Background(), "rule-a").Return(&entities.AlertRule{AlertRuleID: "rule-a", PortfolioID: "portfolio-a", InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Condition: entities.AlertConditionAbove, Threshold: decimal.NewFromInt(200), Active: true}, nil).Once()
+	ruleRepositoryMock.On(testutils.FunctionName(t, ports.AlertRuleRepository.Update), context.Background(), "rule-a", mock.MatchedBy(func(rule entities.AlertRule) bool {
+		return rule.Condition == entities.AlertConditionAbove && rule.Threshold.Equal(decimal.NewFromInt(250)) && !rule.Active && !rule.UpdatedAt.IsZero()
+	})).Return(nil).Once()
+
+	service := &alertService{
+		config:              config.Config{},
+		ruleRepository:      ruleRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+	}
+	threshold := decimal.NewFromInt(250)
+	active := false
+
+	// Act
+	err := service.UpdateRule(context.Background(), "user-a", "portfolio-a", "rule-a", models.UpdateAlertRuleReq{Threshold: &threshold, Active: &active})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestUpdateRule_InvalidThreshold checks that UpdateRule returns a validation error when the merged rule is not valid
+func TestUpdateRule_InvalidThreshold(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	ruleRepositoryMock := mocks.NewAlertRuleRepository(t)
+	ruleRepositoryMock.On(testutils.FunctionName(t, ports.AlertRuleRepository.GetByID), context.Background(), "rule-a").Return(&entities.AlertRule{AlertRuleID: "rule-a", PortfolioID: "portfolio-a", InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Condition: entities.AlertConditionDayMove, Threshold: decimal.NewFromInt(-5), Active: true}, nil).Once()
+
+	service := &alertService{
+		config:              config.Config{},
+		ruleRepository:      ruleRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+	}
+	condition := entities.AlertConditionBelow
+	expectedError := "threshold must be a positive price"
+
+	// Act
+	err := service.UpdateRule(context.Background(), "user-a", "portfolio-a", "rule-a", models.UpdateAlertRuleReq{Condition: &condition})
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestDeleteRule_Ok checks that DeleteRule deletes a rule of the portfolio
+func TestDeleteRule_Ok(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	ruleRepositoryMock := mocks.NewAlertRuleRepository(t)
+	ruleRepositoryMock.On(testutils.FunctionName(t, ports.AlertRuleRepository.GetByID), context.Background(), "rule-a").Return(&entities.AlertRule{AlertRuleID: "rule-a", PortfolioID: "portfolio-a"}, nil).Once()
+	ruleRepositoryMock.On(testutils.FunctionName(t, ports.AlertRuleRepository.Delete), context.Background(), "rule-a").Return(nil).Once()
+
+	service := &alertService{
+		config:              config.Config{},
+		ruleRepository:      ruleRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+	}
+
+	// Act
+	err := service.DeleteRule(context.Background(), "user-a", "portfolio-a", "rule-a")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestEvaluate_Conditions checks that Evaluate triggers the rules whose condition is met, skips the portfolios without price
+// alerts and the instruments without a price, and delivers the pending alerts to the portfolio owner
+func TestEvaluate_Conditions(t *testing.T) {
+	// Arrange
+	ruleRepositoryMock := mocks.NewAlertRuleRepository(t)
+	ruleRepositoryMock.On(testutils.FunctionName(t, ports.AlertRuleRepository.Get), context.Background(), map[string]interface{}{"active": true}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.AlertRule{AlertRuleID: "rule-above", PortfolioID: "portfolio-a", InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Condition: entities.AlertConditionAbove, Threshold: decimal.NewFromInt(180)},
+		&entities.AlertRule{AlertRuleID: "rule-below", PortfolioID: "portfolio-a", InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Condition: entities.AlertConditionBelow, Threshold: decimal.NewFromInt(150)},
+		&entities.AlertRule{AlertRuleID: "rule-day", PortfolioID: "portfolio-a", InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Condition: entities.AlertConditionDayMove, Threshold: decimal.NewFromInt(-5)},
+		&entities.AlertRule{AlertRuleID: "rule-cost", PortfolioID: "portfolio-a", InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Condition: entities.AlertConditionCostBasisMove, Threshold: decimal.NewFromInt(20)},
+		&entities.AlertRule{AlertRuleID: "rule-unpriced", PortfolioID: "portfolio-a", InstrumentID: "stock-b", InstrumentType: entities.EntityNameStock, Condition: entities.AlertConditionAbove, Threshold: decimal.NewFromInt(1)},
+		&entities.AlertRule{AlertRuleID: "rule-disabled", PortfolioID: "portfolio-b", InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Condition: entities.AlertConditionAbove, Threshold: decimal.NewFromInt(1)},
+	}, nil).Once()
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a", Name: "Main", PriceAlert: true}, nil).Once()
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-b").Return(&entities.Portfolio{PortfolioID: "portfolio-b", PriceAlert: false}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-a").Return([]interface{}{
+		&entities.Transaction{TransactionID: "transaction-a", HoldingID: "holding-a", StockID: "stock-a", Quantity: decimal.NewFromInt(10), TransactionPrice: entities.NewMoney(decimal.NewFromInt(150), "USD"), TransactionDate: time.Date(2023, time.January, 2, 0, 0, 0, 0, time.UTC)},
+	}, nil).Once()
+
+	priceSourceMock := mocks.NewPriceSource(t)
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.LatestPrice), context.Background(), "stock-a").Return(entities.NewMoney(decimal.NewFromInt(181), "USD"), nil)
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.LatestPrice), context.Background(), "stock-b").Return(entities.Money{}, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.PriceAt), context.Background(), "stock-a", mock.Anything).Return(entities.NewMoney(decimal.NewFromInt(200), "USD"), nil).Once()
+
+	alertRepositoryMock := mocks.NewAlertRepository(t)
+	alertRepositoryMock.On(testutils.FunctionName(t, ports.AlertRepository.CreateUnique), context.Background(), mock.MatchedBy(func(alert entities.Alert) bool {
+		return alert.AlertRuleID == "rule-above" && alert.Value.Equal(decimal.NewFromInt(181)) && alert.TriggerDate.Equal(startOfDay(alert.TriggeredAt))
+	})).Return("alert-above", true, nil).Once()
+	alertRepositoryMock.On(testutils.FunctionName(t, ports.AlertRepository.CreateUnique), context.Background(), mock.MatchedBy(func(alert entities.Alert) bool {
+		return alert.AlertRuleID == "rule-day" && alert.Value.String() == "-9.5"
+	})).Return("alert-day", true, nil).Once()
+	alertRepositoryMock.On(testutils.FunctionName(t, ports.AlertRepository.CreateUnique), context.Background(), mock.MatchedBy(func(alert entities.Alert) bool {
+		return alert.AlertRuleID == "rule-cost" && alert.Value.String() == "20.67"
+	})).Return("", false, nil).Once()
+	alertRepositoryMock.On(testutils.FunctionName(t, ports.AlertRepository.GetPending), context.Background()).Return([]interface{}{
+		&entities.Alert{AlertID: "alert-above", PortfolioID: "portfolio-a", InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Condition: entities.AlertConditionAbove, Threshold: decimal.NewFromInt(180), Price: entities.NewMoney(decimal.NewFromInt(181), "USD")},
+		&entities.Alert{AlertID: "alert-day", PortfolioID: "portfolio-a", InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Condition: entities.AlertConditionDayMove, Threshold: decimal.NewFromInt(-5), Value: decimal.RequireFromString("-9.5")},
+	}, nil).Once()
+	alertRepositoryMock.On(testutils.FunctionName(t, ports.AlertRepository.MarkNotified), context.Background(), "alert-above", mock.Anything).Return(nil).Once()
+
+	notifierMock := mocks.NewNotifier(t)
+	notifierMock.On(testutils.FunctionName(t, ports.Notifier.Notify), context.Background(), mock.MatchedBy(func(n entities.Notification) bool {
+		return n.UserID == "user-a" && n.Subject == "Price alert for stock stock-a in Main"
+	})).Return(nil).Once()
+	notifierMock.On(testutils.FunctionName(t, ports.Notifier.Notify), context.Background(), mock.Anything).Return(errors.New("unreachable")).Once()
+
+	service := &alertService{
+		config:                config.Config{},
+		ruleRepository:        ruleRepositoryMock,
+		repository:            alertRepositoryMock,
+		portfolioRepository:   portfolioRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		priceSource:           priceSourceMock,
+		notifier:              notifierMock,
+	}
+	expectedResp := models.AlertEvaluationResp{Rules: 5, Triggered: 2, Notified: 1, Undelivered: 1}
+
+	// Act
+	resp, err := service.Evaluate(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResp, resp)
+}
+
+// TestEvaluate_NoRules checks that Evaluate still delivers the pending alerts when there are no active rules
+func TestEvaluate_NoRules(t *testing.T) {
+	// Arrange
+	ruleRepositoryMock := mocks.NewAlertRuleRepository(t)
+	ruleRepositoryMock.On(testutils.FunctionName(t, ports.AlertRuleRepository.Get), context.Background(), map[string]interface{}{"active": true}, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	alertRepositoryMock := mocks.NewAlertRepository(t)
+	alertRepositoryMock.On(testutils.FunctionName(t, ports.AlertRepository.GetPending), context.Background()).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &alertService{
+		config:         config.Config{},
+		ruleRepository: ruleRepositoryMock,
+		repository:     alertRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Evaluate(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.AlertEvaluationResp{}, resp)
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// brokerAccountService adapter of a broker account service
+type brokerAccountService struct {
+	config              config.Config
+	repository          ports.BrokerAccountRepository
+	userRepository      ports.UserRepository
+	brokerRepository    ports.BrokerRepository
+	portfolioRepository ports.PortfolioRepository
+	holdingRepository   ports.HoldingRepository
+}
+
+// NewBrokerAccountService creates a new broker account service
+func NewBrokerAccountService(cfg config.Config, repo ports.BrokerAccountRepository, userRepo ports.UserRepository, brokerRepo ports.BrokerRepository, portfolioRepo ports.PortfolioRepository, holdingRepo ports.HoldingRepository) ports.BrokerAccountService {
+	return &brokerAccountService{
+		config:              cfg,
+		repository:          repo,
+		userRepository:      userRepo,
+		brokerRepository:    brokerRepo,
+		portfolioRepository: portfolioRepo,
+		holdingRepository:   holdingRepo,
+	}
+}
+
+// Create creates an account of a user at a broker of the catalog, whose label must be unique among the accounts of the user at
+// that broker. Only the masked account number is stored.
+func (s *brokerAccountService) Create(ctx context.Context, userID string, account models.CreateBrokerAccountReq) (resp models.CreationResp, err error) {
+	if err = account.Validate(); err != nil {
+		return
+	}
+
+	if _, err = s.userRepository.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", userID))
+		}
+		return
+	}
+	if _, err = s.brokerRepository.GetByID(ctx, account.BrokerID); err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewValidationErr(fmt.Errorf("broker %s not found", account.BrokerID))
+		}
+		return
+	}
+
+	label := strings.TrimSpace(account.AccountLabel)
+	if err = s.checkLabel(ctx, userID, account.BrokerID, "", label); err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	insertedID, err := s.repository.Create(ctx, entities.BrokerAccount{
+		UserID:        userID,
+		BrokerID:      account.BrokerID,
+		AccountLabel:  label,
+		AccountNumber: models.MaskAccountNumber(account.AccountNumber),
+		BaseCurrency:  account.BaseCurrency,
+		OpenedAt:      account.OpenedAt,
+		ClosedAt:      account.ClosedAt,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	if err != nil {
+		return
+	}
+
+	resp = models.CreationResp{
+		InsertedID: insertedID,
+	}
+	return
+}
+
+// GetAll returns the broker accounts of a user, oldest first
+func (s *brokerAccountService) GetAll(ctx context.Context, userID string) (resp []models.BrokerAccountResp, err error) {
+	resp = []models.BrokerAccountResp{}
+	result, err := s.repository.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	for _, v := range result {
+		resp = append(resp, models.BrokerAccountResp(*(v.(*entities.BrokerAccount))))
+	}
+	return
+}
+
+// GetByID returns a broker account of a user
+func (s *brokerAccountService) GetByID(ctx context.Context, userID, ID string) (resp models.BrokerAccountResp, err error) {
+	result, err := s.repository.GetByID(ctx, ID)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return
+	}
+	if err != nil || result.(*entities.BrokerAccount).UserID != userID {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", ID))
+		return
+	}
+
+	resp = models.BrokerAccountResp(*(result.(*entities.BrokerAccount)))
+	return
+}
+
+// Update replaces the label, currency and dates of a broker account of a user, and its account number unless none is given
+func (s *brokerAccountService) Update(ctx context.Context, userID, ID string, account models.UpdateBrokerAccountReq) (err error) {
+	dbAccount, err := s.GetByID(ctx, userID, ID)
+	if err != nil {
+		return
+	}
+	if err = account.Validate(); err != nil {
+		return
+	}
+
+	label := strings.TrimSpace(account.AccountLabel)
+	if err = s.checkLabel(ctx, userID, dbAccount.BrokerID, ID, label); err != nil {
+		return
+	}
+
+	dbAccount.AccountLabel = label
+	if account.AccountNumber != "" {
+		dbAccount.AccountNumber = models.MaskAccountNumber(account.AccountNumber)
+	}
+	dbAccount.BaseCurrency = account.BaseCurrency
+	dbAccount.OpenedAt = account.OpenedAt
+	dbAccount.ClosedAt = account.ClosedAt
+	dbAccount.UpdatedAt = time.Now().UTC()
+
+	err = s.repository.Update(ctx, ID, entities.BrokerAccount(dbAccount))
+	return
+}
+
+// Delete deletes a broker account of a user, which cannot have any holding attached
+func (s *brokerAccountService) Delete(ctx context.Context, userID, ID string) (err error) {
+	if _, err = s.GetByID(ctx, userID, ID); err != nil {
+		return
+	}
+
+	holdings, err := s.holdingRepository.Get(ctx, map[string]interface{}{"broker_accountid": ID}, nil, nil)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return
+	}
+	if len(holdings) > 0 {
+		err = wrappers.NewValidationErr(fmt.Errorf("broker account %s has %d holdings attached", ID, len(holdings)))
+		return
+	}
+
+	err = s.repository.Delete(ctx, ID)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", ID))
+	}
+	return
+}
+
+// AttachHolding attaches a holding of a portfolio of the user to one of their open broker accounts at the broker of the holding,
+// detaching it from the account it was attached to before
+func (s *brokerAccountService) AttachHolding(ctx context.Context, userID, ID, holdingID string) (err error) {
+	account, err := s.GetByID(ctx, userID, ID)
+	if err != nil {
+		return
+	}
+
+	result, err := s.holdingRepository.GetByID(ctx, holdingID)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return
+	}
+	if err != nil {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("holding %s not found", holdingID))
+		return
+	}
+	holding := *(result.(*entities.Holding))
+
+	portfolio, err := s.portfolioRepository.GetByID(ctx, holding.PortfolioID)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return
+	}
+	if err != nil || portfolio.(*entities.Portfolio).Userid != userID {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("holding %s not found", holdingID))
+		return
+	}
+
+	now := time.Now().UTC()
+	if holding.BrokerID != account.BrokerID {
+		return wrappers.NewValidationErr(fmt.Errorf("holding %s is not at broker %s of broker account %s", holdingID, account.BrokerID, ID))
+	}
+	if !entities.BrokerAccount(account).Open(now) {
+		return wrappers.NewValidationErr(fmt.Errorf("broker account %s is closed", ID))
+	}
+
+	holding.BrokerAccountID = ID
+	holding.UpdatedAt = now
+	err = s.holdingRepository.Update(ctx, holdingID, holding)
+	return
+}
+
+// checkLabel checks that no other account of a user at a broker than the one with an ID has a label, ignoring case
+func (s *brokerAccountService) checkLabel(ctx context.Context, userID, brokerID, ID, label string) error {
+	result, err := s.repository.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			return nil
+		}
+		return err
+	}
+
+	for _, v := range result {
+		a := v.(*entities.BrokerAccount)
+		if a.BrokerAccountID != ID && a.BrokerID == brokerID && strings.EqualFold(a.AccountLabel, label) {
+			return wrappers.NewValidationErr(fmt.Errorf("broker account %s already exists at broker %s", label, brokerID))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestNewBrokerAccountService_Ok checks that NewBrokerAccountService creates a new brokerAccountService struct
+func TestNewBrokerAccountService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+
+	// Act
+	service := NewBrokerAccountService(cfg, mocks.NewBrokerAccountRepository(t), mocks.NewUserRepository(t), mocks.NewBrokerRepository(t), mocks.NewPortfolioRepository(t), mocks.NewHoldingRepository(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestCreateBrokerAccount_Ok checks that Create stores the account with its number masked
+func TestCreateBrokerAccount_Ok(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "user-a").Return(&entities.User{UserID: "user-a"}, nil).Once()
+
+	brokerRepositoryMock := mocks.NewBrokerRepository(t)
+	brokerRepositoryMock.On(testutils.FunctionName(t, ports.BrokerRepository.GetByID), context.Background(), "broker-a").Return(&entities.Broker{BrokerID: "broker-a"}, nil).Once()
+
+	brokerAccountRepositoryMock := mocks.NewBrokerAccountRepository(t)
+	brokerAccountRepositoryMock.On(testutils.FunctionName(t, ports.BrokerAccountRepository.GetByUserID), context.Background(), "user-a").Return([]interface{}{
+		&entities.BrokerAccount{BrokerAccountID: "account-b", UserID: "user-a", BrokerID: "broker-b", AccountLabel: "ISA"},
+	}, nil).Once()
+	brokerAccountRepositoryMock.On(testutils.FunctionName(t, ports.BrokerAccountRepository.Create), context.Background(), mock.MatchedBy(func(account entities.BrokerAccount) bool {
+		return account.UserID == "user-a" && account.BrokerID == "broker-a" && account.AccountLabel == "ISA" && account.AccountNumber == "****5678" && !account.CreatedAt.IsZero()
+	})).Return("account-a", nil).Once()
+
+	service := &brokerAccountService{
+		config:           config.Config{},
+		repository:       brokerAccountRepositoryMock,
+		userRepository:   userRepositoryMock,
+		brokerRepository: brokerRepositoryMock,
+	}
+	req := models.CreateBrokerAccountReq{BrokerID: "broker-a", AccountLabel: " ISA ", AccountNumber: "1234 5678", BaseCurrency: "GBP", OpenedAt: time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)}
+
+	// Act
+	resp, err := service.Create(context.Background(), "user-a", req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "account-a", resp.InsertedID)
+}
+
+// TestCreateBrokerAccount_DuplicateLabel checks that Create returns a validation error when the user already has an account with the
+// same label at the broker
+func TestCreateBrokerAccount_DuplicateLabel(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "user-a").Return(&entities.User{UserID: "user-a"}, nil).Once()
+
+	brokerRepositoryMock := mocks.NewBrokerRepository(t)
+	brokerRepositoryMock.On(testutils.FunctionName(t, ports.BrokerRepository.GetByID), context.Background(), "broker-a").Return(&entities.Broker{BrokerID: "broker-a"}, nil).Once()
+
+	brokerAccountRepositoryMock := mocks.NewBrokerAccountRepository(t)
+	brokerAccountRepositoryMock.On(testutils.FunctionName(t, ports.BrokerAccountRepository.GetByUserID), context.Background(), "user-a").Return([]interface{}{
+		&entities.BrokerAccount{BrokerAccountID: "account-b", UserID: "user-a", BrokerID: "broker-a", AccountLabel: "isa"},
+	}, nil).Once()
+
+	service := &brokerAccountService{
+		config:           config.Config{},
+		repository:       brokerAccountRepositoryMock,
+		userRepository:   userRepositoryMock,
+		brokerRepository: brokerRepositoryMock,
+	}
+	req := models.CreateBrokerAccountReq{BrokerID: "broker-a", AccountLabel: "ISA", BaseCurrency: "GBP", OpenedAt: time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)}
+	expectedError := "broker account ISA already exists at broker broker-a"
+
+	// Act
+	_, err := service.Create(context.Background(), "user-a", req)
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestCreateBrokerAccount_BrokerNotFound checks that Create returns a validation error when the broker is not in the catalog
+func TestCreateBrokerAccount_BrokerNotFound(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "user-a").Return(&entities.User{UserID: "user-a"}, nil).Once()
+
+	brokerRepositoryMock := mocks.NewBrokerRepository(t)
+	brokerRepositoryMock.On(testutils.FunctionName(t, ports.BrokerRepository.GetByID), context.Background(), "broker-a").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &brokerAccountService{
+		config:           config.Config{},
+		userRepository:   userRepositoryMock,
+		brokerRepository: brokerRepositoryMock,
+	}
+	req := models.CreateBrokerAccountReq{BrokerID: "broker-a", AccountLabel: "ISA", BaseCurrency: "GBP", OpenedAt: time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)}
+	expectedError := "broker broker-a not found"
+
+	// Act
+	_, err := service.Create(context.Background(), "user-a", req)
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetBrokerAccountByID_OtherUser checks that GetByID returns a non existent error when the account belongs to another user
+func TestGetBrokerAccountByID_OtherUser(t *testing.T) {
+	// Arrange
+	brokerAccountRepositoryMock := mocks.NewBrokerAccountRepository(t)
+	brokerAccountRepositoryMock.On(testutils.FunctionName(t, ports.BrokerAccountRepository.GetByID), context.Background(), "account-a").Return(&entities.BrokerAccount{BrokerAccountID: "account-a", UserID: "user-b"}, nil).Once()
+
+	service := &brokerAccountService{
+		config:     config.Config{},
+		repository: brokerAccountRepositoryMock,
+	}
+	expectedError := "ID account-a not found"
+
+	// Act
+	_, err := service.GetByID(context.Background(), "user-a", "account-a")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestUpdateBrokerAccount_KeepsAccountNumber checks that Update keeps the stored account number when none is given
+func TestUpdateBrokerAccount_KeepsAccountNumber(t *testing.T) {
+	// Arrange
+	openedAt := time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)
+	closedAt := time.Date(2023, time.June, 30, 0, 0, 0, 0, time.UTC)
+	stored := &entities.BrokerAccount{BrokerAccountID: "account-a", UserID: "user-a", BrokerID: "broker-a", AccountLabel: "ISA", AccountNumber: "****5678", BaseCurrency: "GBP", OpenedAt: openedAt}
+
+	brokerAccountRepositoryMock := mocks.NewBrokerAccountRepository(t)
+	brokerAccountRepositoryMock.On(testutils.FunctionName(t, ports.BrokerAccountRepository.GetByID), context.Background(), "account-a").Return(stored, nil).Once()
+	brokerAccountRepositoryMock.On(testutils.FunctionName(t, ports.BrokerAccountRepository.GetByUserID), context.Background(), "user-a").Return([]interface{}{stored}, nil).Once()
+	brokerAccountRepositoryMock.On(testutils.FunctionName(t, ports.BrokerAccountRepository.Update), context.Background(), "account-a", mock.MatchedBy(func(account entities.BrokerAccount) bool {
+		return account.AccountLabel == "ISA" && account.AccountNumber == "****5678" && account.ClosedAt.Equal(closedAt)
+	})).Return(nil).Once()
+
+	service := &brokerAccountService{
+		config:     config.Config{},
+		repository: brokerAccountRepositoryMock,
+	}
+	req := models.UpdateBrokerAccountReq{AccountLabel: "ISA", BaseCurrency: "GBP", OpenedAt: openedAt, ClosedAt: closedAt}
+
+	// Act
+	err := service.Update(context.Background(), "user-a", "account-a", req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestDeleteBrokerAccount_HoldingsAttached checks that Delete returns a validation error when holdings are attached to the account
+func TestDeleteBrokerAccount_HoldingsAttached(t *testing.T) {
+	// Arrange
+	brokerAccountRepositoryMock := mocks.NewBrokerAccountRepository(t)
+	brokerAccountRepositoryMock.On(testutils.FunctionName(t, ports.BrokerAccountRepository.GetByID), context.Background(), "account-a").Return(&entities.BrokerAccount{BrokerAccountID: "account-a", UserID: "user-a"}, nil).Once()
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.Get), context.Background(), map[string]interface{}{"broker_accountid": "account-a"}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Holding{HoldingID: "holding-a", BrokerAccountID: "account-a"},
+	}, nil).Once()
+
+	service := &brokerAccountService{
+		config:            config.Config{},
+		repository:        brokerAccountRepositoryMock,
+		holdingRepository: holdingRepositoryMock,
+	}
+	expectedError := "broker account account-a has 1 holdings attached"
+
+	// Act
+	err := service.Delete(context.Background(), "user-a", "account-a")
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestAttachBrokerAccountHolding_Ok checks that AttachHolding stores the account in a holding of the user at the broker of the account
+func TestAttachBrokerAccountHolding_Ok(t *testing.T) {
+	// Arrange
+	brokerAccountRepositoryMock := mocks.NewBrokerAccountRepository(t)
+	brokerAccountRepositoryMock.On(testutils.FunctionName(t, ports.BrokerAccountRepository.GetByID), context.Background(), "account-a").Return(&entities.BrokerAccount{BrokerAccountID: "account-a", UserID: "user-a", BrokerID: "broker-a"}, nil).Once()
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.GetByID), context.Background(), "holding-a").Return(&entities.Holding{HoldingID: "holding-a", PortfolioID: "portfolio-a", BrokerID: "broker-a"}, nil).Once()
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.Update), context.Background(), "holding-a", mock.MatchedBy(func(holding entities.Holding) bool {
+		return holding.BrokerAccountID == "account-a" && !holding.UpdatedAt.IsZero()
+	})).Return(nil).Once()
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	service := &brokerAccountService{
+		config:              config.Config{},
+		repository:          brokerAccountRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+		holdingRepository:   holdingRepositoryMock,
+	}
+
+	// Act
+	err := service.AttachHolding(context.Background(), "user-a", "account-a", "holding-a")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestAttachBrokerAccountHolding_OtherBroker checks that AttachHolding returns a validation error when the holding is at another broker
+func TestAttachBrokerAccountHolding_OtherBroker(t *testing.T) {
+	// Arrange
+	brokerAccountRepositoryMock := mocks.NewBrokerAccountRepository(t)
+	brokerAccountRepositoryMock.On(testutils.FunctionName(t, ports.BrokerAccountRepository.GetByID), context.Background(), "account-a").Return(&entities.BrokerAccount{BrokerAccountID: "account-a", UserID: "user-a", BrokerID: "broker-a"}, nil).Once()
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.GetByID), context.Background(), "holding-a").Return(&entities.Holding{HoldingID: "holding-a", PortfolioID: "portfolio-a", BrokerID: "broker-b"}, nil).Once()
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	service := &brokerAccountService{
+		config:              config.Config{},
+		repository:          brokerAccountRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+		holdingRepository:   holdingRepositoryMock,
+	}
+	expectedError := "holding holding-a is not at broker broker-a of broker account account-a"
+
+	// Act
+	err := service.AttachHolding(context.Background(), "user-a", "account-a", "holding-a")
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestAttachBrokerAccountHolding_Closed checks that AttachHolding returns a validation error when the account is closed
+func TestAttachBrokerAccountHolding_Closed(t *testing.T) {
+	// Arrange
+	closedAt := time.Date(2023, time.June, 30, 0, 0, 0, 0, time.UTC)
+
+	brokerAccountRepositoryMock := mocks.NewBrokerAccountRepository(t)
+	brokerAccountRepositoryMock.On(testutils.FunctionName(t, ports.BrokerAccountRepository.GetByID), context.Background(), "account-a").Return(&entities.BrokerAccount{BrokerAccountID: "account-a", UserID: "user-a", BrokerID: "broker-a", ClosedAt: closedAt}, nil).Once()
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.GetByID), context.Background(), "holding-a").Return(&entities.Holding{HoldingID: "holding-a", PortfolioID: "portfolio-a", BrokerID: "broker-a"}, nil).Once()
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	service := &brokerAccountService{
+		config:              config.Config{},
+		repository:          brokerAccountRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+		holdingRepository:   holdingRepositoryMock,
+	}
+	expectedError := "broker account account-a is closed"
+
+	// Act
+	err := service.AttachHolding(context.Background(), "user-a", "account-a", "holding-a")
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+const (
+	// calendarTokenBytes is the number of random bytes of a calendar feed token, which is written in hexadecimal
+	calendarTokenBytes = 32
+	// calendarPastDays is how many days before today the events of a calendar feed start
+	calendarPastDays = 90
+	// calendarFutureDays is how many days after today the events of a calendar feed end
+	calendarFutureDays = 365
+	// calendarDomain qualifies the UIDs of the events of a calendar feed
+	calendarDomain = "portflux.com"
+	// calendarLineOctets is the longest line of an iCalendar file before it is folded
+	calendarLineOctets = 75
+)
+
+// calendarService adapter of a calendar feed service
+type calendarService struct {
+	config                 config.Config
+	repository             ports.CalendarFeedRepository
+	userRepository         ports.UserRepository
+	portfolioRepository    ports.PortfolioRepository
+	transactionRepository  ports.TransactionRepository
+	stockRepository        ports.StockRepository
+	dividendRepository     ports.DividendRepository
+	companyEventRepository ports.CompanyEventRepository
+}
+
+// NewCalendarService creates a new calendar feed service
+func NewCalendarService(cfg config.Config, repo ports.CalendarFeedRepository, userRepo ports.UserRepository, portfolioRepo ports.PortfolioRepository, transactionRepo ports.TransactionRepository, stockRepo ports.StockRepository, dividendRepo ports.DividendRepository, companyEventRepo ports.CompanyEventRepository) ports.CalendarService {
+	return &calendarService{
+		config:                 cfg,
+		repository:             repo,
+		userRepository:         userRepo,
+		portfolioRepository:    portfolioRepo,
+		transactionRepository:  transactionRepo,
+		stockRepository:        stockRepo,
+		dividendRepository:     dividendRepo,
+		companyEventRepository: companyEventRepo,
+	}
+}
+
+// RotateToken creates a new secret token for the calendar feed of a user, replacing the previous one. The token is returned
+// only once, as just its hash is stored.
+func (s *calendarService) RotateToken(ctx context.Context, userID string) (resp models.CalendarTokenResp, err error) {
+	_, err = s.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", userID))
+		}
+		return
+	}
+
+	secret := make([]byte, calendarTokenBytes)
+	if _, err = rand.Read(secret); err != nil {
+		return
+	}
+	token := hex.EncodeToString(secret)
+
+	now := time.Now().UTC()
+	_, err = s.repository.Upsert(ctx, entities.CalendarFeed{UserID: userID, TokenHash: calendarTokenHash(token), CreatedAt: now, UpdatedAt: now})
+	if err != nil {
+		return
+	}
+
+	resp = models.CalendarTokenResp{
+		Token: token,
+		URL:   fmt.Sprintf("/v1/calendar/%s.ics", token),
+	}
+	return
+}
+
+// RevokeToken deletes the calendar feed of a user, so its token no longer gives access to the feed
+func (s *calendarService) RevokeToken(ctx context.Context, userID string) error {
+	err := s.repository.DeleteByUserID(ctx, userID)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("calendar feed of user %s not found", userID))
+	}
+	return err
+}
+
+// Feed renders the iCalendar file of the feed with the given token. It holds the ex-dividend and pay dates of the dividends and the
+// company events of the stocks held in the portfolios of the user, from 90 days ago to a year ahead. Company events on the same day as
+// a recorded dividend of the same kind are left out, as the dividend already covers them.
+func (s *calendarService) Feed(ctx context.Context, token string) ([]byte, error) {
+	if !validCalendarToken(token) {
+		return nil, wrappers.NewNonExistentErr(fmt.Errorf("calendar feed not found"))
+	}
+	result, err := s.repository.GetByTokenHash(ctx, calendarTokenHash(token))
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("calendar feed not found"))
+		}
+		return nil, err
+	}
+	feed := result.(*entities.CalendarFeed)
+
+	stockIDs, err := s.heldStocks(ctx, feed.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := startOfDay(time.Now().UTC())
+	from, to := today.AddDate(0, 0, -calendarPastDays), today.AddDate(0, 0, calendarFutureDays)
+
+	var events []entities.CalendarEvent
+	for _, stockID := range stockIDs {
+		stockEvents, err := s.stockEvents(ctx, stockID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, stockEvents...)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Date.Equal(events[j].Date) {
+			return events[i].Date.Before(events[j].Date)
+		}
+		return events[i].UID < events[j].UID
+	})
+
+	return icsCalendar(events), nil
+}
+
+// heldStocks returns the sorted IDs of the stocks held in any of the portfolios of a user
+func (s *calendarService) heldStocks(ctx context.Context, userID string) ([]string, error) {
+	result, err := s.portfolioRepository.Get(ctx, map[string]interface{}{"userid": userID}, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	held := map[string]bool{}
+	for _, v := range result {
+		_, transactions, err := loadPortfolioTransactions(ctx, s.portfolioRepository, s.transactionRepository, v.(*entities.Portfolio).PortfolioID)
+		if err != nil {
+			return nil, err
+		}
+
+		quantities := map[string]decimal.Decimal{}
+		for _, t := range transactions {
+			if t.StockID != "" {
+				quantities[t.StockID] = quantities[t.StockID].Add(t.Quantity)
+			}
+		}
+		for stockID, quantity := range quantities {
+			if quantity.IsPositive() {
+				held[stockID] = true
+			}
+		}
+	}
+
+	stockIDs := make([]string, 0, len(held))
+	for stockID := range held {
+		stockIDs = append(stockIDs, stockID)
+	}
+	sort.Strings(stockIDs)
+	return stockIDs, nil
+}
+
+// stockEvents returns the calendar events of the dividends and the company events of a stock between two days
+func (s *calendarService) stockEvents(ctx context.Context, stockID string, from, to time.Time) ([]entities.CalendarEvent, error) {
+	symbol := stockID
+	if stock, err := s.stockRepository.GetByID(ctx, stockID); err == nil && stock.(*entities.Stock).TickerSymbol != "" {
+		symbol = stock.(*entities.Stock).TickerSymbol
+	}
+	within := func(d time.Time) bool {
+		d = startOfDay(d)
+		return !d.IsZero() && !d.Before(from) && !d.After(to)
+	}
+
+	var events []entities.CalendarEvent
+	covered := map[string]bool{}
+	dividends, err := s.dividendRepository.Get(ctx, map[string]interface{}{"stockid": stockID}, nil, nil)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return nil, err
+	}
+	for _, v := range dividends {
+		d := v.(*entities.Dividend)
+		if within(d.ExDate) {
+			events = append(events, entities.CalendarEvent{
+				UID:       fmt.Sprintf("dividend-%s-ex@%s", d.DividendID, calendarDomain),
+				Date:      startOfDay(d.ExDate),
+				Summary:   fmt.Sprintf("%s ex-dividend %s", symbol, d.DividendPerShare),
+				UpdatedAt: d.UpdatedAt,
+			})
+			covered[entities.CompanyEventExDividend+startOfDay(d.ExDate).Format("2006-01-02")] = true
+		}
+		if within(d.PayDate) {
+			events = append(events, entities.CalendarEvent{
+				UID:       fmt.Sprintf("dividend-%s-pay@%s", d.DividendID, calendarDomain),
+				Date:      startOfDay(d.PayDate),
+				Summary:   fmt.Sprintf("%s dividend payment %s", symbol, d.DividendPerShare),
+				UpdatedAt: d.UpdatedAt,
+			})
+			covered[entities.CompanyEventDividendPayment+startOfDay(d.PayDate).Format("2006-01-02")] = true
+		}
+	}
+
+	companyEvents, err := s.companyEventRepository.GetByStockID(ctx, stockID, from, to)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return nil, err
+	}
+	for _, v := range companyEvents {
+		e := v.(*entities.CompanyEvent)
+		if covered[e.EventType+startOfDay(e.EventDate).Format("2006-01-02")] {
+			continue
+		}
+		events = append(events, entities.CalendarEvent{
+			UID:         fmt.Sprintf("company-event-%s@%s", e.CompanyEventID, calendarDomain),
+			Date:        startOfDay(e.EventDate),
+			Summary:     companyEventSummary(symbol, *e),
+			Description: e.Description,
+			UpdatedAt:   e.UpdatedAt,
+		})
+	}
+
+	return events, nil
+}
+
+// companyEventSummary returns the title of the calendar event of a company event
+func companyEventSummary(symbol string, e entities.CompanyEvent) string {
+	switch e.EventType {
+	case entities.CompanyEventEarnings:
+		return fmt.Sprintf("%s earnings", symbol)
+	case entities.CompanyEventExDividend:
+		return fmt.Sprintf("%s ex-dividend %s", symbol, e.Amount)
+	case entities.CompanyEventDividendPayment:
+		return fmt.Sprintf("%s dividend payment %s", symbol, e.Amount)
+	case entities.CompanyEventSplit:
+		return fmt.Sprintf("%s split %s:%s", symbol, e.SplitNumerator, e.SplitDenominator)
+	case entities.CompanyEventDelisting:
+		return fmt.Sprintf("%s delisting", symbol)
+	default:
+		return fmt.Sprintf("%s %s", symbol, e.EventType)
+	}
+}
+
+// calendarTokenHash returns the hexadecimal SHA-256 hash a calendar feed token is stored as
+func calendarTokenHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// validCalendarToken reports whether a token has the format of the calendar feed tokens
+func validCalendarToken(token string) bool {
+	if len(token) != calendarTokenBytes*2 {
+		return false
+	}
+	_, err := hex.DecodeString(token)
+	return err == nil && strings.ToLower(token) == token
+}
+
+// icsCalendar renders all-day events as an RFC 5545 iCalendar file
+func icsCalendar(events []entities.CalendarEvent) []byte {
+	var b bytes.Buffer
+	line := func(content string) {
+		b.WriteString(icsFold(content))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//portflux.com//Portflux API//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:Portflux")
+	for _, e := range events {
+		stamp := e.UpdatedAt.UTC()
+		if stamp.IsZero() {
+			stamp = e.Date
+		}
+		line("BEGIN:VEVENT")
+		line("UID:" + e.UID)
+		line("DTSTAMP:" + stamp.Format("20060102T150405Z"))
+		line("DTSTART;VALUE=DATE:" + e.Date.Format("20060102"))
+		line("DTEND;VALUE=DATE:" + e.Date.Add(oneDay).Format("20060102"))
+		line("SUMMARY:" + icsText(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION:" + icsText(e.Description))
+		}
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	return b.Bytes()
+}
+
+// icsText escapes a text value of an iCalendar property
+func icsText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+// icsFold folds a content line longer than 75 octets into continuation lines starting with a space, without splitting characters
+func icsFold(content string) string {
+	var b strings.Builder
+	limit := calendarLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		limit = calendarLineOctets - 1
+	}
+	b.WriteString(content)
+	return b.String()
+}
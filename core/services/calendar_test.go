@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestNewCalendarService_Ok checks that NewCalendarService creates a new calendarService struct
+func TestNewCalendarService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+
+	// Act
+	service := NewCalendarService(cfg, mocks.NewCalendarFeedRepository(t), mocks.NewUserRepository(t), mocks.NewPortfolioRepository(t), mocks.NewTransactionRepository(t), mocks.NewStockRepository(t), mocks.NewDividendRepository(t), mocks.NewCompanyEventRepository(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestRotateCalendarToken_Ok checks that RotateToken stores the hash of a new token and returns the token with the feed URL
+func TestRotateCalendarToken_Ok(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "user-a").Return(&entities.User{UserID: "user-a"}, nil).Once()
+
+	var stored entities.CalendarFeed
+	calendarFeedRepositoryMock := mocks.NewCalendarFeedRepository(t)
+	calendarFeedRepositoryMock.On(testutils.FunctionName(t, ports.CalendarFeedRepository.Upsert), context.Background(), mock.AnythingOfType("entities.CalendarFeed")).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(entities.CalendarFeed)
+	}).Return("feed-a", nil).Once()
+
+	service := &calendarService{
+		config:         config.Config{},
+		repository:     calendarFeedRepositoryMock,
+		userRepository: userRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.RotateToken(context.Background(), "user-a")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, validCalendarToken(resp.Token))
+	assert.Equal(t, "/v1/calendar/"+resp.Token+".ics", resp.URL)
+	assert.Equal(t, "user-a", stored.UserID)
+	assert.Equal(t, calendarTokenHash(resp.Token), stored.TokenHash)
+	assert.NotEqual(t, resp.Token, stored.TokenHash)
+}
+
+// TestRotateCalendarToken_UserNotFound checks that RotateToken returns a non existent error when the user does not exist
+func TestRotateCalendarToken_UserNotFound(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "user-a").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &calendarService{
+		config:         config.Config{},
+		userRepository: userRepositoryMock,
+	}
+
+	// Act
+	_, err := service.RotateToken(context.Background(), "user-a")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, "ID user-a not found", err.Error())
+}
+
+// TestCalendarFeed_InvalidToken checks that Feed returns a non existent error without a lookup when the token has the wrong format
+func TestCalendarFeed_InvalidToken(t *testing.T) {
+	// Arrange
+	service := &calendarService{
+		config:     config.Config{},
+		repository: mocks.NewCalendarFeedRepository(t),
+	}
+
+	// Act
+	_, err := service.Feed(context.Background(), "not-a-token")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, "calendar feed not found", err.Error())
+}
+
+// TestCalendarFeed_Ok checks that Feed renders the dividends and company events of the held stocks with stable UIDs,
+// leaving out the sold stocks and the company events already covered by a dividend
+func TestCalendarFeed_Ok(t *testing.T) {
+	// Arrange
+	token := strings.Repeat("ab", calendarTokenBytes)
+	today := startOfDay(time.Now().UTC())
+	from, to := today.AddDate(0, 0, -calendarPastDays), today.AddDate(0, 0, calendarFutureDays)
+	exDate, payDate := today.AddDate(0, 0, 10), today.AddDate(0, 0, 24)
+	updatedAt := time.Date(2024, time.March, 1, 12, 30, 0, 0, time.UTC)
+
+	calendarFeedRepositoryMock := mocks.NewCalendarFeedRepository(t)
+	calendarFeedRepositoryMock.On(testutils.FunctionName(t, ports.CalendarFeedRepository.GetByTokenHash), context.Background(), calendarTokenHash(token)).Return(&entities.CalendarFeed{CalendarFeedID: "feed-a", UserID: "user-a"}, nil).Once()
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.Get), context.Background(), map[string]interface{}{"userid": "user-a"}, (*int)(nil), (*int)(nil)).Return([]interface{}{&entities.Portfolio{PortfolioID: "portfolio-a"}}, nil).Once()
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-a").Return([]interface{}{
+		&entities.Transaction{StockID: "stock-a", Quantity: decimal.NewFromInt(10), TransactionDate: today.AddDate(-1, 0, 0)},
+		&entities.Transaction{StockID: "stock-b", Quantity: decimal.NewFromInt(5), TransactionDate: today.AddDate(-1, 0, 0)},
+		&entities.Transaction{StockID: "stock-b", Quantity: decimal.NewFromInt(-5), TransactionDate: today.AddDate(0, -1, 0)},
+	}, nil).Once()
+
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-a").Return(&entities.Stock{StockID: "stock-a", TickerSymbol: "KO"}, nil).Once()
+
+	dividendRepositoryMock := mocks.NewDividendRepository(t)
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-a"}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Dividend{DividendID: "dividend-a", StockID: "stock-a", DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.485"), "USD"), ExDate: exDate, PayDate: payDate, UpdatedAt: updatedAt},
+		&entities.Dividend{DividendID: "dividend-old", StockID: "stock-a", DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.46"), "USD"), ExDate: today.AddDate(-1, 0, 0)},
+	}, nil).Once()
+
+	companyEventRepositoryMock := mocks.NewCompanyEventRepository(t)
+	companyEventRepositoryMock.On(testutils.FunctionName(t, ports.CompanyEventRepository.GetByStockID), context.Background(), "stock-a", from, to).Return([]interface{}{
+		&entities.CompanyEvent{CompanyEventID: "event-a", StockID: "stock-a", EventType: entities.CompanyEventEarnings, EventDate: today.AddDate(0, 0, 3), Description: "Q1 results; call at 8:30, webcast", UpdatedAt: updatedAt},
+		&entities.CompanyEvent{CompanyEventID: "event-b", StockID: "stock-a", EventType: entities.CompanyEventExDividend, EventDate: exDate, Amount: entities.NewMoney(decimal.RequireFromString("0.485"), "USD")},
+	}, nil).Once()
+
+	service := &calendarService{
+		config:                 config.Config{},
+		repository:             calendarFeedRepositoryMock,
+		portfolioRepository:    portfolioRepositoryMock,
+		transactionRepository:  transactionRepositoryMock,
+		stockRepository:        stockRepositoryMock,
+		dividendRepository:     dividendRepositoryMock,
+		companyEventRepository: companyEventRepositoryMock,
+	}
+
+	// Act
+	feed, err := service.Feed(context.Background(), token)
+
+	// Assert
+	assert.Nil(t, err)
+	ics := string(feed)
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.Equal(t, 3, strings.Count(ics, "BEGIN:VEVENT"))
+
+	earnings := strings.Index(ics, "UID:company-event-event-a@portflux.com")
+	ex := strings.Index(ics, "UID:dividend-dividend-a-ex@portflux.com")
+	pay := strings.Index(ics, "UID:dividend-dividend-a-pay@portflux.com")
+	assert.True(t, earnings > 0 && earnings < ex && ex < pay)
+	assert.NotContains(t, ics, "event-b")
+	assert.NotContains(t, ics, "dividend-old")
+
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:"+exDate.Format("20060102")+"\r\nDTEND;VALUE=DATE:"+exDate.AddDate(0, 0, 1).Format("20060102"))
+	assert.Contains(t, ics, "DTSTAMP:20240301T123000Z")
+	assert.Contains(t, ics, "SUMMARY:KO ex-dividend 0.485 USD")
+	assert.Contains(t, ics, "SUMMARY:KO dividend payment 0.485 USD")
+	assert.Contains(t, ics, `DESCRIPTION:Q1 results\; call at 8:30\, webcast`)
+}
+
+// TestCalendarFeed_NotFound checks that Feed returns a non existent error when no feed has the token
+func TestCalendarFeed_NotFound(t *testing.T) {
+	// Arrange
+	token := strings.Repeat("0f", calendarTokenBytes)
+
+	calendarFeedRepositoryMock := mocks.NewCalendarFeedRepository(t)
+	calendarFeedRepositoryMock.On(testutils.FunctionName(t, ports.CalendarFeedRepository.GetByTokenHash), context.Background(), calendarTokenHash(token)).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &calendarService{
+		config:     config.Config{},
+		repository: calendarFeedRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Feed(context.Background(), token)
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, "calendar feed not found", err.Error())
+}
+
+// TestICSFold_Ok checks that icsFold folds lines longer than 75 octets without splitting multi-byte characters
+func TestICSFold_Ok(t *testing.T) {
+	// Arrange
+	content := "SUMMARY:" + strings.Repeat("é", 60)
+
+	// Act
+	folded := icsFold(content)
+
+	// Assert
+	lines := strings.Split(folded, "\r\n")
+	assert.Len(t, lines, 2)
+	for i, line := range lines {
+		assert.LessOrEqual(t, len(line), calendarLineOctets)
+		assert.True(t, strings.ToValidUTF8(line, "?") == line, i)
+	}
+	assert.True(t, strings.HasPrefix(lines[1], " "))
+	assert.Equal(t, content, lines[0]+lines[1][1:])
+}
@@ -0,0 +1,485 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+const (
+	// companyEventSourceCSV is the source of the company events imported from CSV files that do not name one
+	companyEventSourceCSV = "csv"
+	// defaultCompanyEventSyncHorizon is how far ahead the events are fetched when the config does not set it
+	defaultCompanyEventSyncHorizon = 90 * oneDay
+)
+
+// companyEventService adapter of a company event service
+type companyEventService struct {
+	config                config.Config
+	repository            ports.CompanyEventRepository
+	stockRepository       ports.StockRepository
+	portfolioRepository   ports.PortfolioRepository
+	transactionRepository ports.TransactionRepository
+	provider              ports.MarketDataProvider
+	notifier              ports.Notifier
+}
+
+// NewCompanyEventService creates a new company event service
+func NewCompanyEventService(cfg config.Config, repo ports.CompanyEventRepository, stockRepo ports.StockRepository, portfolioRepo ports.PortfolioRepository, transactionRepo ports.TransactionRepository, provider ports.MarketDataProvider, notifier ports.Notifier) ports.CompanyEventService {
+	return &companyEventService{
+		config:                cfg,
+		repository:            repo,
+		stockRepository:       stockRepo,
+		portfolioRepository:   portfolioRepo,
+		transactionRepository: transactionRepo,
+		provider:              provider,
+		notifier:              notifier,
+	}
+}
+
+// Create stores an event of a stock, replacing the event of the same type already stored for the same day
+func (s *companyEventService) Create(ctx context.Context, stockID string, event models.CreateCompanyEventReq) (resp models.CreationResp, err error) {
+	event.StockID = stockID
+	if err = event.Validate(); err != nil {
+		return
+	}
+
+	if _, err = s.stock(ctx, stockID); err != nil {
+		return
+	}
+
+	insertedID, err := s.upsert(ctx, event)
+	if err != nil {
+		return
+	}
+
+	resp = models.CreationResp{
+		InsertedID: insertedID,
+	}
+	return
+}
+
+// GetByStockID returns the events of a stock over a date range, oldest first
+func (s *companyEventService) GetByStockID(ctx context.Context, stockID string, req models.CompanyEventsReq) (resp []models.CompanyEventResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	if _, err = s.stock(ctx, stockID); err != nil {
+		return
+	}
+
+	resp = []models.CompanyEventResp{}
+	result, err := s.repository.GetByStockID(ctx, stockID, startOfDay(req.From), startOfDay(req.To))
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	for _, v := range result {
+		resp = append(resp, models.CompanyEventResp(*(v.(*entities.CompanyEvent))))
+	}
+	return
+}
+
+// Delete deletes an event of a stock
+func (s *companyEventService) Delete(ctx context.Context, stockID, eventID string) (err error) {
+	result, err := s.repository.GetByID(ctx, eventID)
+	if err == nil && result.(*entities.CompanyEvent).StockID != stockID {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", eventID))
+	}
+	if err == nil {
+		err = s.repository.Delete(ctx, eventID)
+	}
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", eventID))
+	}
+	return
+}
+
+// Import loads company events from a CSV file with Symbol, Type and Date columns and the optional Amount, Currency,
+// Numerator, Denominator, Description and Source ones. Stocks are found by their ticker symbol and events already stored
+// for the same stock, type and day are replaced, so a file can be imported again.
+func (s *companyEventService) Import(ctx context.Context, file io.Reader) (resp models.CompanyEventImportResp, err error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		err = wrappers.NewValidationErr(fmt.Errorf("invalid company event CSV file: %w", err))
+		return
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")))] = i
+	}
+	for _, name := range []string{"Symbol", "Type", "Date"} {
+		if _, ok := columns[strings.ToLower(name)]; !ok {
+			err = wrappers.NewValidationErr(fmt.Errorf("invalid company event CSV file: a %s column is required", name))
+			return
+		}
+	}
+
+	stocks, err := s.stocksBySymbol(ctx)
+	if err != nil {
+		return
+	}
+
+	var events []models.CreateCompanyEventReq
+	for line := 2; ; line++ {
+		record, readErr := reader.Read()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			err = wrappers.NewValidationErr(fmt.Errorf("invalid company event CSV file: %w", readErr))
+			return
+		}
+
+		event, parseErr := parseCompanyEventRecord(record, columns, stocks)
+		if parseErr == nil {
+			parseErr = event.Validate()
+		}
+		if parseErr != nil {
+			err = wrappers.NewValidationErr(fmt.Errorf("line %d: %s", line, parseErr))
+			return
+		}
+		events = append(events, event)
+	}
+
+	for _, event := range events {
+		if _, err = s.upsert(ctx, event); err != nil {
+			return
+		}
+		resp.Imported++
+	}
+	return
+}
+
+// Sync fetches from the market data provider the upcoming dividends and splits of the stocks held in open positions.
+// A stock that cannot be synced does not stop the others, it is counted as failed.
+func (s *companyEventService) Sync(ctx context.Context) (resp models.CompanyEventSyncResp, err error) {
+	instruments, err := openInstruments(ctx, s.transactionRepository)
+	if err != nil {
+		return
+	}
+
+	horizon := s.config.CompanyEvents.SyncHorizon.Duration
+	if horizon <= 0 {
+		horizon = defaultCompanyEventSyncHorizon
+	}
+	from := startOfDay(time.Now().UTC())
+	to := from.Add(horizon)
+
+	for _, instrument := range instruments {
+		if instrument.instrumentType != entities.EntityNameStock {
+			continue
+		}
+		resp.Stocks++
+
+		events, syncErr := s.sync(ctx, instrument.id, from, to)
+		if syncErr != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+				return
+			}
+			log.Printf("company event sync failure for stock %s, error: %s", instrument.id, syncErr)
+			resp.Failed++
+			continue
+		}
+		resp.Events += events
+	}
+	return
+}
+
+// sync stores the dividends and splits of a stock reported by the market data provider, returning how many events were stored
+func (s *companyEventService) sync(ctx context.Context, stockID string, from, to time.Time) (int, error) {
+	stock, err := s.stockRepository.GetByID(ctx, stockID)
+	if err != nil {
+		return 0, err
+	}
+	symbol := stock.(*entities.Stock).TickerSymbol
+	if symbol == "" {
+		return 0, fmt.Errorf("stock %s has no symbol", stockID)
+	}
+
+	dividends, err := s.provider.Dividends(ctx, symbol, from, to)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return 0, err
+	}
+	splits, err := s.provider.Splits(ctx, symbol, from, to)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return 0, err
+	}
+
+	source := s.provider.Name()
+	var events []models.CreateCompanyEventReq
+	for _, d := range dividends {
+		events = append(events, models.CreateCompanyEventReq{StockID: stockID, EventType: entities.CompanyEventExDividend, EventDate: d.ExDate, Amount: d.Amount, Source: source})
+		if !d.PayDate.IsZero() {
+			events = append(events, models.CreateCompanyEventReq{StockID: stockID, EventType: entities.CompanyEventDividendPayment, EventDate: d.PayDate, Amount: d.Amount, Source: source})
+		}
+	}
+	for _, split := range splits {
+		events = append(events, models.CreateCompanyEventReq{StockID: stockID, EventType: entities.CompanyEventSplit, EventDate: split.Date, SplitNumerator: split.Numerator, SplitDenominator: split.Denominator, Source: source})
+	}
+
+	for _, event := range events {
+		if err := event.Validate(); err != nil {
+			return 0, err
+		}
+		if _, err := s.upsert(ctx, event); err != nil {
+			return 0, err
+		}
+	}
+	return len(events), nil
+}
+
+// Notify tells the owners of the portfolios with company event alerts enabled about the events due within the notice days
+// of the stocks they hold. Every event is sent once per portfolio, and an event that cannot be delivered is sent again on the next run.
+func (s *companyEventService) Notify(ctx context.Context) (resp models.CompanyEventNotifyResp, err error) {
+	from := startOfDay(time.Now().UTC())
+	to := from.Add(time.Duration(s.config.CompanyEvents.NoticeDays) * oneDay)
+
+	result, err := s.repository.GetRange(ctx, from, to)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	holders, err := s.holders(ctx)
+	if err != nil {
+		return
+	}
+
+	stocks := map[string]entities.Stock{}
+	for _, v := range result {
+		event := *(v.(*entities.CompanyEvent))
+		resp.Events++
+
+		notified, notifiedErr := s.repository.GetNotifiedPortfolios(ctx, event.CompanyEventID)
+		if notifiedErr != nil && !errors.Is(notifiedErr, wrappers.NonExistentErr) {
+			err = notifiedErr
+			return
+		}
+		done := map[string]bool{}
+		for _, portfolioID := range notified {
+			done[portfolioID] = true
+		}
+
+		for _, p := range holders[event.StockID] {
+			if done[p.PortfolioID] {
+				continue
+			}
+
+			stock, ok := stocks[event.StockID]
+			if !ok {
+				if found, stockErr := s.stockRepository.GetByID(ctx, event.StockID); stockErr == nil {
+					stock = *(found.(*entities.Stock))
+				} else {
+					stock = entities.Stock{StockID: event.StockID}
+				}
+				stocks[event.StockID] = stock
+			}
+
+			notifyErr := s.notifier.Notify(ctx, companyEventNotification(p, stock, event))
+			if notifyErr == nil {
+				notifyErr = s.repository.MarkNotified(ctx, event.CompanyEventID, p.PortfolioID, time.Now().UTC())
+			}
+			if notifyErr != nil {
+				log.Printf("cannot deliver company event %s to portfolio %s, error: %s", event.CompanyEventID, p.PortfolioID, notifyErr)
+				resp.Undelivered++
+				continue
+			}
+			resp.Notified++
+		}
+	}
+	return
+}
+
+// holders returns by stock ID the portfolios with company event alerts enabled holding an open position in it
+func (s *companyEventService) holders(ctx context.Context) (map[string][]entities.Portfolio, error) {
+	holders := map[string][]entities.Portfolio{}
+	result, err := s.portfolioRepository.Get(ctx, map[string]interface{}{"company_event_alert": true}, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			return holders, nil
+		}
+		return nil, err
+	}
+
+	for _, v := range result {
+		portfolio := *(v.(*entities.Portfolio))
+		if !portfolio.CompanyEventAlert {
+			continue
+		}
+
+		transactions, err := s.transactionRepository.GetByPortfolioID(ctx, portfolio.PortfolioID)
+		if err != nil {
+			if errors.Is(err, wrappers.NonExistentErr) {
+				continue
+			}
+			return nil, err
+		}
+		quantities := map[string]decimal.Decimal{}
+		for _, t := range transactions {
+			transaction := t.(*entities.Transaction)
+			if transaction.StockID != "" {
+				quantities[transaction.StockID] = quantities[transaction.StockID].Add(transaction.Quantity)
+			}
+		}
+		for stockID, quantity := range quantities {
+			if quantity.IsPositive() {
+				holders[stockID] = append(holders[stockID], portfolio)
+			}
+		}
+	}
+	return holders, nil
+}
+
+// stock returns a stock, with a non existent error naming its ID when it does not exist
+func (s *companyEventService) stock(ctx context.Context, stockID string) (entities.Stock, error) {
+	result, err := s.stockRepository.GetByID(ctx, stockID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", stockID))
+		}
+		return entities.Stock{}, err
+	}
+	return *(result.(*entities.Stock)), nil
+}
+
+// stocksBySymbol returns the IDs of the stocks by their upper case ticker symbol
+func (s *companyEventService) stocksBySymbol(ctx context.Context) (map[string]string, error) {
+	stocks := map[string]string{}
+	result, err := s.stockRepository.Get(ctx, map[string]interface{}{}, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			return stocks, nil
+		}
+		return nil, err
+	}
+
+	for _, v := range result {
+		stock := v.(*entities.Stock)
+		if stock.TickerSymbol != "" {
+			stocks[strings.ToUpper(stock.TickerSymbol)] = stock.StockID
+		}
+	}
+	return stocks, nil
+}
+
+// upsert stores a valid event, replacing the event of the same stock and type already stored for the same day
+func (s *companyEventService) upsert(ctx context.Context, event models.CreateCompanyEventReq) (string, error) {
+	now := time.Now().UTC()
+	event.EventDate = startOfDay(event.EventDate)
+	event.CreatedAt = now
+	event.UpdatedAt = now
+
+	return s.repository.Upsert(ctx, entities.CompanyEvent(event))
+}
+
+// parseCompanyEventRecord parses a row of a company event CSV file
+func parseCompanyEventRecord(record []string, columns map[string]int, stocks map[string]string) (event models.CreateCompanyEventReq, err error) {
+	field := func(name string) string {
+		i, found := columns[name]
+		if !found || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	symbol := field("symbol")
+	stockID, ok := stocks[strings.ToUpper(symbol)]
+	if !ok {
+		err = fmt.Errorf("unknown symbol %q", symbol)
+		return
+	}
+
+	event = models.CreateCompanyEventReq{
+		StockID:     stockID,
+		EventType:   strings.ToLower(field("type")),
+		Description: field("description"),
+		Source:      field("source"),
+	}
+	if event.Source == "" {
+		event.Source = companyEventSourceCSV
+	}
+
+	value := field("date")
+	if event.EventDate, err = time.Parse("2006-01-02", value); err != nil {
+		err = fmt.Errorf("invalid date %q", value)
+		return
+	}
+
+	for name, target := range map[string]*decimal.Decimal{"amount": &event.Amount.Amount, "numerator": &event.SplitNumerator, "denominator": &event.SplitDenominator} {
+		value := field(name)
+		if value == "" {
+			continue
+		}
+		if *target, err = decimal.NewFromString(value); err != nil {
+			err = fmt.Errorf("invalid %s %q", name, value)
+			return
+		}
+	}
+	event.Amount.Currency = strings.ToUpper(field("currency"))
+
+	return
+}
+
+// companyEventNotification returns the message telling the owner of a portfolio about an upcoming event of a stock it holds
+func companyEventNotification(portfolio entities.Portfolio, stock entities.Stock, event entities.CompanyEvent) entities.Notification {
+	name := stock.TickerSymbol
+	if name == "" {
+		name = stock.StockID
+	}
+	if stock.CompanyName != "" {
+		name = fmt.Sprintf("%s (%s)", stock.CompanyName, name)
+	}
+
+	var what string
+	switch event.EventType {
+	case entities.CompanyEventEarnings:
+		what = "reports earnings"
+	case entities.CompanyEventExDividend:
+		what = "goes ex-dividend"
+	case entities.CompanyEventDividendPayment:
+		what = "pays a dividend"
+	case entities.CompanyEventSplit:
+		what = fmt.Sprintf("splits %s for %s", event.SplitNumerator, event.SplitDenominator)
+	case entities.CompanyEventDelisting:
+		what = "is delisted"
+	}
+	if (event.EventType == entities.CompanyEventExDividend || event.EventType == entities.CompanyEventDividendPayment) && event.Amount.Amount.IsPositive() {
+		what = fmt.Sprintf("%s of %s per share", what, event.Amount)
+	}
+
+	message := fmt.Sprintf("%s %s on %s.", name, what, event.EventDate.Format("2006-01-02"))
+	if event.Description != "" {
+		message = fmt.Sprintf("%s %s", message, event.Description)
+	}
+
+	return entities.Notification{
+		UserID:    portfolio.Userid,
+		Subject:   fmt.Sprintf("Upcoming %s for %s in %s", strings.ReplaceAll(event.EventType, "_", " "), name, portfolio.Name),
+		Message:   message,
+		CreatedAt: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestNewCompanyEventService_Ok checks that NewCompanyEventService creates a new companyEventService struct
+func TestNewCompanyEventService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+
+	// Act
+	service := NewCompanyEventService(cfg, mocks.NewCompanyEventRepository(t), mocks.NewStockRepository(t), mocks.NewPortfolioRepository(t), mocks.NewTransactionRepository(t), mocks.NewMarketDataProvider(t), mocks.NewNotifier(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestCreateCompanyEvent_Ok checks that Create stores the event of the stock on the start of its day
+func TestCreateCompanyEvent_Ok(t *testing.T) {
+	// Arrange
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-a").Return(&entities.Stock{StockID: "stock-a"}, nil).Once()
+
+	repositoryMock := mocks.NewCompanyEventRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.CompanyEventRepository.Upsert), context.Background(), mock.MatchedBy(func(event entities.CompanyEvent) bool {
+		return event.StockID == "stock-a" && event.EventType == entities.CompanyEventEarnings && event.EventDate.Equal(time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC)) && !event.CreatedAt.IsZero()
+	})).Return("event-a", nil).Once()
+
+	service := &companyEventService{
+		config:          config.Config{},
+		repository:      repositoryMock,
+		stockRepository: stockRepositoryMock,
+	}
+	req := models.CreateCompanyEventReq{EventType: entities.CompanyEventEarnings, EventDate: time.Date(2023, time.August, 3, 21, 30, 0, 0, time.UTC)}
+
+	// Act
+	resp, err := service.Create(context.Background(), "stock-a", req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "event-a", resp.InsertedID)
+}
+
+// TestCreateCompanyEvent_StockNotFound checks that Create returns a non existent error when the stock does not exist
+func TestCreateCompanyEvent_StockNotFound(t *testing.T) {
+	// Arrange
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-a").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &companyEventService{
+		config:          config.Config{},
+		stockRepository: stockRepositoryMock,
+	}
+	req := models.CreateCompanyEventReq{EventType: entities.CompanyEventEarnings, EventDate: time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC)}
+	expectedError := "ID stock-a not found"
+
+	// Act
+	_, err := service.Create(context.Background(), "stock-a", req)
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestDeleteCompanyEvent_OtherStock checks that Delete returns a non existent error when the event belongs to another stock
+func TestDeleteCompanyEvent_OtherStock(t *testing.T) {
+	// Arrange
+	repositoryMock := mocks.NewCompanyEventRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.CompanyEventRepository.GetByID), context.Background(), "event-a").Return(&entities.CompanyEvent{CompanyEventID: "event-a", StockID: "stock-b"}, nil).Once()
+
+	service := &companyEventService{
+		config:     config.Config{},
+		repository: repositoryMock,
+	}
+	expectedError := "ID event-a not found"
+
+	// Act
+	err := service.Delete(context.Background(), "stock-a", "event-a")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestImportCompanyEvents_Ok checks that Import stores the events of the file for the stocks found by their symbol
+func TestImportCompanyEvents_Ok(t *testing.T) {
+	// Arrange
+	file := strings.NewReader("\uFEFFSymbol,Type,Date,Amount,Currency,Numerator,Denominator,Description\n" +
+		"aapl,Earnings,2023-08-03,,,,,Q3 results\n" +
+		"AAPL,ex_dividend,2023-08-11,0.24,usd,,,\n" +
+		"MSFT,split,2023-09-01,,,2,1,\n")
+
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Stock{StockID: "stock-a", TickerSymbol: "AAPL"},
+		&entities.Stock{StockID: "stock-b", TickerSymbol: "MSFT"},
+	}, nil).Once()
+
+	repositoryMock := mocks.NewCompanyEventRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.CompanyEventRepository.Upsert), context.Background(), mock.MatchedBy(func(event entities.CompanyEvent) bool {
+		return event.StockID == "stock-a" && event.EventType == entities.CompanyEventEarnings && event.Description == "Q3 results" && event.Source == "csv"
+	})).Return("event-a", nil).Once()
+	repositoryMock.On(testutils.FunctionName(t, ports.CompanyEventRepository.Upsert), context.Background(), mock.MatchedBy(func(event entities.CompanyEvent) bool {
+		return event.StockID == "stock-a" && event.EventType == entities.CompanyEventExDividend && event.Amount.String() == "0.24 USD"
+	})).Return("event-b", nil).Once()
+	repositoryMock.On(testutils.FunctionName(t, ports.CompanyEventRepository.Upsert), context.Background(), mock.MatchedBy(func(event entities.CompanyEvent) bool {
+		return event.StockID == "stock-b" && event.EventType == entities.CompanyEventSplit && event.SplitNumerator.Equal(decimal.NewFromInt(2))
+	})).Return("event-c", nil).Once()
+
+	service := &companyEventService{
+		config:          config.Config{},
+		repository:      repositoryMock,
+		stockRepository: stockRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), file)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.CompanyEventImportResp{Imported: 3}, resp)
+}
+
+// TestImportCompanyEvents_UnknownSymbol checks that Import returns a validation error naming the line of an unknown symbol and stores nothing
+func TestImportCompanyEvents_UnknownSymbol(t *testing.T) {
+	// Arrange
+	file := strings.NewReader("Symbol,Type,Date\nAAPL,earnings,2023-08-03\nTSLA,earnings,2023-10-18\n")
+
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Stock{StockID: "stock-a", TickerSymbol: "AAPL"},
+	}, nil).Once()
+
+	service := &companyEventService{
+		config:          config.Config{},
+		repository:      mocks.NewCompanyEventRepository(t),
+		stockRepository: stockRepositoryMock,
+	}
+	expectedError := `line 3: unknown symbol "TSLA"`
+
+	// Act
+	_, err := service.Import(context.Background(), file)
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestImportCompanyEvents_MissingColumn checks that Import returns a validation error when a required column is missing
+func TestImportCompanyEvents_MissingColumn(t *testing.T) {
+	// Arrange
+	file := strings.NewReader("Symbol,Date\nAAPL,2023-08-03\n")
+	service := &companyEventService{config: config.Config{}}
+	expectedError := "invalid company event CSV file: a Type column is required"
+
+	// Act
+	_, err := service.Import(context.Background(), file)
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestSyncCompanyEvents_Ok checks that Sync stores the dividends and splits of the stocks held in open positions and counts the failed ones
+func TestSyncCompanyEvents_Ok(t *testing.T) {
+	// Arrange
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Transaction{HoldingID: "holding-a", StockID: "stock-a", Quantity: decimal.NewFromInt(10)},
+		&entities.Transaction{HoldingID: "holding-a", StockID: "stock-b", Quantity: decimal.NewFromInt(5)},
+		&entities.Transaction{HoldingID: "holding-b", CryptocurrencyID: "crypto-a", Quantity: decimal.NewFromInt(1)},
+	}, nil).Once()
+
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-a").Return(&entities.Stock{StockID: "stock-a", TickerSymbol: "AAPL"}, nil).Once()
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-b").Return(&entities.Stock{StockID: "stock-b", TickerSymbol: "MSFT"}, nil).Once()
+
+	exDate := time.Date(2023, time.August, 11, 0, 0, 0, 0, time.UTC)
+	providerMock := mocks.NewMarketDataProvider(t)
+	providerMock.On(testutils.FunctionName(t, ports.MarketDataProvider.Name)).Return("file")
+	providerMock.On(testutils.FunctionName(t, ports.MarketDataProvider.Dividends), context.Background(), "AAPL", mock.Anything, mock.Anything).Return([]entities.DividendEvent{
+		{ExDate: exDate, PayDate: exDate.AddDate(0, 0, 6), Amount: entities.NewMoney(decimal.RequireFromString("0.24"), "USD")},
+	}, nil).Once()
+	providerMock.On(testutils.FunctionName(t, ports.MarketDataProvider.Splits), context.Background(), "AAPL", mock.Anything, mock.Anything).Return(nil, wrappers.NewNonExistentErr(errors.New("no splits"))).Once()
+	providerMock.On(testutils.FunctionName(t, ports.MarketDataProvider.Dividends), context.Background(), "MSFT", mock.Anything, mock.Anything).Return(nil, errors.New("timeout")).Once()
+
+	repositoryMock := mocks.NewCompanyEventRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.CompanyEventRepository.Upsert), context.Background(), mock.MatchedBy(func(event entities.CompanyEvent) bool {
+		return event.StockID == "stock-a" && event.EventType == entities.CompanyEventExDividend && event.EventDate.Equal(exDate) && event.Source == "file"
+	})).Return("event-a", nil).Once()
+	repositoryMock.On(testutils.FunctionName(t, ports.CompanyEventRepository.Upsert), context.Background(), mock.MatchedBy(func(event entities.CompanyEvent) bool {
+		return event.StockID == "stock-a" && event.EventType == entities.CompanyEventDividendPayment && event.EventDate.Equal(exDate.AddDate(0, 0, 6))
+	})).Return("event-b", nil).Once()
+
+	service := &companyEventService{
+		config:                config.Config{},
+		repository:            repositoryMock,
+		stockRepository:       stockRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		provider:              providerMock,
+	}
+
+	// Act
+	resp, err := service.Sync(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.CompanyEventSyncResp{Stocks: 2, Events: 2, Failed: 1}, resp)
+}
+
+// TestNotifyCompanyEvents_Ok checks that Notify tells the portfolios holding the stock about an upcoming event once,
+// skipping the portfolios already notified and the ones without a position, and counts the undelivered notifications
+func TestNotifyCompanyEvents_Ok(t *testing.T) {
+	// Arrange
+	today := startOfDay(time.Now().UTC())
+	cfg := config.Config{}
+	cfg.CompanyEvents.NoticeDays = 3
+
+	repositoryMock := mocks.NewCompanyEventRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.CompanyEventRepository.GetRange), context.Background(), today, today.AddDate(0, 0, 3)).Return([]interface{}{
+		&entities.CompanyEvent{CompanyEventID: "event-a", StockID: "stock-a", EventType: entities.CompanyEventExDividend, EventDate: today.AddDate(0, 0, 2), Amount: entities.NewMoney(decimal.RequireFromString("0.24"), "USD")},
+	}, nil).Once()
+	repositoryMock.On(testutils.FunctionName(t, ports.CompanyEventRepository.GetNotifiedPortfolios), context.Background(), "event-a").Return([]string{"portfolio-b"}, nil).Once()
+	repositoryMock.On(testutils.FunctionName(t, ports.CompanyEventRepository.MarkNotified), context.Background(), "event-a", "portfolio-a", mock.Anything).Return(nil).Once()
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.Get), context.Background(), map[string]interface{}{"company_event_alert": true}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a", Name: "Main", CompanyEventAlert: true},
+		&entities.Portfolio{PortfolioID: "portfolio-b", Userid: "user-b", CompanyEventAlert: true},
+		&entities.Portfolio{PortfolioID: "portfolio-c", Userid: "user-c", CompanyEventAlert: true},
+		&entities.Portfolio{PortfolioID: "portfolio-d", Userid: "user-d", CompanyEventAlert: true},
+	}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-a").Return([]interface{}{&entities.Transaction{StockID: "stock-a", Quantity: decimal.NewFromInt(10)}}, nil).Once()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-b").Return([]interface{}{&entities.Transaction{StockID: "stock-a", Quantity: decimal.NewFromInt(10)}}, nil).Once()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-c").Return([]interface{}{
+		&entities.Transaction{StockID: "stock-a", Quantity: decimal.NewFromInt(10)},
+		&entities.Transaction{StockID: "stock-a", Quantity: decimal.NewFromInt(-10)},
+	}, nil).Once()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-d").Return([]interface{}{&entities.Transaction{StockID: "stock-a", Quantity: decimal.NewFromInt(1)}}, nil).Once()
+
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-a").Return(&entities.Stock{StockID: "stock-a", TickerSymbol: "AAPL", CompanyName: "Apple"}, nil).Once()
+
+	notifierMock := mocks.NewNotifier(t)
+	notifierMock.On(testutils.FunctionName(t, ports.Notifier.Notify), context.Background(), mock.MatchedBy(func(n entities.Notification) bool {
+		return n.UserID == "user-a" && n.Subject == "Upcoming ex dividend for Apple (AAPL) in Main" && strings.HasPrefix(n.Message, "Apple (AAPL) goes ex-dividend of 0.24 USD per share on ")
+	})).Return(nil).Once()
+	notifierMock.On(testutils.FunctionName(t, ports.Notifier.Notify), context.Background(), mock.MatchedBy(func(n entities.Notification) bool {
+		return n.UserID == "user-d"
+	})).Return(errors.New("unreachable")).Once()
+
+	service := &companyEventService{
+		config:                cfg,
+		repository:            repositoryMock,
+		stockRepository:       stockRepositoryMock,
+		portfolioRepository:   portfolioRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		notifier:              notifierMock,
+	}
+
+	// Act
+	resp, err := service.Notify(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.CompanyEventNotifyResp{Events: 1, Notified: 1, Undelivered: 1}, resp)
+}
+
+// TestNotifyCompanyEvents_NoEvents checks that Notify does nothing when there are no upcoming events
+func TestNotifyCompanyEvents_NoEvents(t *testing.T) {
+	// Arrange
+	repositoryMock := mocks.NewCompanyEventRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.CompanyEventRepository.GetRange), context.Background(), mock.Anything, mock.Anything).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &companyEventService{
+		config:     config.Config{},
+		repository: repositoryMock,
+	}
+
+	// Act
+	resp, err := service.Notify(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.CompanyEventNotifyResp{}, resp)
+}
@@ -0,0 +1,491 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// corporateActionService adapter of a corporate action service
+type corporateActionService struct {
+	config                config.Config
+	repository            ports.CorporateActionRepository
+	stockRepository       ports.StockRepository
+	transactionRepository ports.TransactionRepository
+	holdingRepository     ports.HoldingRepository
+	lotService            ports.LotService
+}
+
+// NewCorporateActionService creates a new corporate action service. The transactions are read as stored, to find the portfolios
+// holding the stocks of an action, whose lot ledger the lot service refreshes whenever an action is created or deleted.
+func NewCorporateActionService(cfg config.Config, repo ports.CorporateActionRepository, stockRepo ports.StockRepository, transactionRepo ports.TransactionRepository, holdingRepo ports.HoldingRepository, lotService ports.LotService) ports.CorporateActionService {
+	return &corporateActionService{
+		config:                cfg,
+		repository:            repo,
+		stockRepository:       stockRepo,
+		transactionRepository: transactionRepo,
+		holdingRepository:     holdingRepo,
+		lotService:            lotService,
+	}
+}
+
+// Create records a corporate action of a stock. A symbol change also renames the stock, keeping its previous symbol in the action.
+func (s *corporateActionService) Create(ctx context.Context, stockID string, action models.CreateCorporateActionReq) (resp models.CreationResp, err error) {
+	action.StockID = stockID
+	action.NewSymbol = strings.ToUpper(strings.TrimSpace(action.NewSymbol))
+	action.NewISIN = strings.ToUpper(strings.TrimSpace(action.NewISIN))
+	if err = action.Validate(); err != nil {
+		return
+	}
+
+	stock, err := s.stock(ctx, stockID)
+	if err != nil {
+		return
+	}
+	if action.TargetStockID != "" {
+		if _, err = s.stock(ctx, action.TargetStockID); err != nil {
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	action.EffectiveDate = startOfDay(action.EffectiveDate)
+	action.CreatedAt = now
+	action.UpdatedAt = now
+
+	if action.ActionType == entities.CorporateActionSymbolChange {
+		action.OldSymbol = stock.TickerSymbol
+		if action.NewSymbol != "" {
+			stock.TickerSymbol = action.NewSymbol
+			stock.UpdatedAt = now
+			if err = s.stockRepository.Update(ctx, stockID, stock); err != nil {
+				return
+			}
+		}
+	}
+
+	insertedID, err := s.repository.Create(ctx, entities.CorporateAction(action))
+	if err != nil {
+		return
+	}
+	if err = s.refreshLedgers(ctx, action.StockID, action.TargetStockID); err != nil {
+		return
+	}
+
+	resp = models.CreationResp{
+		InsertedID: insertedID,
+	}
+	return
+}
+
+// GetByStockID returns the corporate actions of a stock, oldest first
+func (s *corporateActionService) GetByStockID(ctx context.Context, stockID string) (resp []models.CorporateActionResp, err error) {
+	if _, err = s.stock(ctx, stockID); err != nil {
+		return
+	}
+
+	resp = []models.CorporateActionResp{}
+	result, err := s.repository.GetByStockID(ctx, stockID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	for _, v := range result {
+		resp = append(resp, models.CorporateActionResp(*(v.(*entities.CorporateAction))))
+	}
+	return
+}
+
+// Delete deletes a corporate action of a stock. The symbol of a renamed stock is not restored.
+func (s *corporateActionService) Delete(ctx context.Context, stockID, actionID string) (err error) {
+	result, err := s.repository.GetByID(ctx, actionID)
+	if err == nil && result.(*entities.CorporateAction).StockID != stockID {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", actionID))
+	}
+	if err == nil {
+		err = s.repository.Delete(ctx, actionID)
+	}
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", actionID))
+		}
+		return
+	}
+
+	action := result.(*entities.CorporateAction)
+	return s.refreshLedgers(ctx, action.StockID, action.TargetStockID)
+}
+
+// refreshLedgers refreshes the lot ledger of every portfolio with transactions of the stocks, or of the stocks they are linked to
+// by a merger or a spin-off, as an action on one of them changes how the transactions of the others are adjusted
+func (s *corporateActionService) refreshLedgers(ctx context.Context, stockIDs ...string) error {
+	actions, err := s.repository.Get(ctx, map[string]interface{}{}, nil, nil)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return err
+	}
+
+	stocks := map[string]bool{}
+	for _, stockID := range stockIDs {
+		if stockID != "" {
+			stocks[stockID] = true
+		}
+	}
+	for linked := true; linked; {
+		linked = false
+		for _, v := range actions {
+			action := v.(*entities.CorporateAction)
+			if action.TargetStockID == "" || stocks[action.StockID] == stocks[action.TargetStockID] {
+				continue
+			}
+			stocks[action.StockID], stocks[action.TargetStockID] = true, true
+			linked = true
+		}
+	}
+
+	holdings := map[string]bool{}
+	for stockID := range stocks {
+		result, err := s.transactionRepository.Get(ctx, map[string]interface{}{"stockid": stockID}, nil, nil)
+		if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+			return err
+		}
+		for _, v := range result {
+			holdings[v.(*entities.Transaction).HoldingID] = true
+		}
+	}
+
+	portfolios := map[string]bool{}
+	for holdingID := range holdings {
+		result, err := s.holdingRepository.GetByID(ctx, holdingID)
+		if err != nil {
+			return err
+		}
+		portfolios[result.(*entities.Holding).PortfolioID] = true
+	}
+
+	portfolioIDs := make([]string, 0, len(portfolios))
+	for portfolioID := range portfolios {
+		portfolioIDs = append(portfolioIDs, portfolioID)
+	}
+	sort.Strings(portfolioIDs)
+	for _, portfolioID := range portfolioIDs {
+		if err := s.lotService.Refresh(ctx, portfolioID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stock returns a stock, with a non existent error naming its ID when it does not exist
+func (s *corporateActionService) stock(ctx context.Context, stockID string) (entities.Stock, error) {
+	result, err := s.stockRepository.GetByID(ctx, stockID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", stockID))
+		}
+		return entities.Stock{}, err
+	}
+	return *(result.(*entities.Stock)), nil
+}
+
+// adjustedTransactionRepository adapter of a transaction repository that reads the transactions adjusted by the corporate actions
+type adjustedTransactionRepository struct {
+	ports.TransactionRepository
+	corporateActionRepository ports.CorporateActionRepository
+}
+
+// NewAdjustedTransactionRepository creates a transaction repository whose reads return the transactions adjusted by the corporate
+// actions, as computed by adjustTransactions, so positions, lots and returns follow splits, mergers and spin-offs while the stored
+// transactions stay as they were entered. Writes go to the wrapped repository unchanged.
+func NewAdjustedTransactionRepository(transactionRepo ports.TransactionRepository, corporateActionRepo ports.CorporateActionRepository) ports.TransactionRepository {
+	return &adjustedTransactionRepository{
+		TransactionRepository:     transactionRepo,
+		corporateActionRepository: corporateActionRepo,
+	}
+}
+
+// Get returns the adjusted transactions matching the filter
+func (r *adjustedTransactionRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	result, err := r.TransactionRepository.Get(ctx, filter, skip, take)
+	if err != nil {
+		return nil, err
+	}
+	return r.adjust(ctx, result)
+}
+
+// GetByID returns an adjusted transaction, or a split transaction as it was stored
+func (r *adjustedTransactionRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	result, err := r.TransactionRepository.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	adjusted, err := r.adjust(ctx, []interface{}{result})
+	if err != nil {
+		return nil, err
+	}
+	if len(adjusted) == 0 {
+		return result, nil
+	}
+	return adjusted[0], nil
+}
+
+// GetByPortfolioID returns the adjusted transactions of a portfolio
+func (r *adjustedTransactionRepository) GetByPortfolioID(ctx context.Context, portfolioID string) ([]interface{}, error) {
+	result, err := r.TransactionRepository.GetByPortfolioID(ctx, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+	return r.adjust(ctx, result)
+}
+
+// GetByBrokerAccountID returns the adjusted transactions of the holdings attached to a broker account
+func (r *adjustedTransactionRepository) GetByBrokerAccountID(ctx context.Context, brokerAccountID string) ([]interface{}, error) {
+	result, err := r.TransactionRepository.GetByBrokerAccountID(ctx, brokerAccountID)
+	if err != nil {
+		return nil, err
+	}
+	return r.adjust(ctx, result)
+}
+
+// StreamByPortfolioID calls fn with each adjusted transaction of a portfolio. Adjusting needs all the transactions of the portfolio,
+// so they are read at once rather than streamed.
+func (r *adjustedTransactionRepository) StreamByPortfolioID(ctx context.Context, portfolioID string, fn func(entities.Transaction) error) error {
+	result, err := r.GetByPortfolioID(ctx, portfolioID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			return nil
+		}
+		return err
+	}
+	for _, v := range result {
+		if err := fn(*(v.(*entities.Transaction))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// adjust applies all the corporate actions to the transactions read from the wrapped repository
+func (r *adjustedTransactionRepository) adjust(ctx context.Context, result []interface{}) ([]interface{}, error) {
+	actions, err := r.corporateActionRepository.Get(ctx, map[string]interface{}{}, nil, nil)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return nil, err
+	}
+
+	corporateActions := make([]entities.CorporateAction, len(actions))
+	for i, v := range actions {
+		corporateActions[i] = *(v.(*entities.CorporateAction))
+	}
+	transactions := make([]entities.Transaction, len(result))
+	for i, v := range result {
+		transactions[i] = *(v.(*entities.Transaction))
+	}
+
+	adjusted := adjustTransactions(transactions, corporateActions)
+	resp := make([]interface{}, len(adjusted))
+	for i := range adjusted {
+		resp[i] = &adjusted[i]
+	}
+	return resp, nil
+}
+
+// adjustTransactions returns copies of the transactions as if the corporate actions had been in place when they were made.
+// The actions are applied in the order they took effect, each to the transactions of its stock made before its effective day:
+//   - splits and reverse splits scale the quantity by the ratio and the price by its inverse, keeping the amount
+//   - mergers do the same and move the transactions to the acquiring stock
+//   - spin-offs add a transaction of the spun-off stock for each purchase of the parent stock still held on the effective day, with
+//     the quantity scaled by the ratio, and split the amount between both by the cost allocation, so the holding period of the new
+//     shares is the one of the parent. The shares sold before are taken first in, first out from the purchases like the lots of the
+//     ledger, and those purchases are split in the part sold, left as it is with the sales, and the part held
+//   - symbol changes leave the transactions as they are
+//
+// Split transactions are applied the same way, in date order with the actions and after the ones taking effect on their day, to the
+// earlier transactions of their holding and instrument: their quantity over the shares held before their day is the ratio of the
+// split. They are left out of the result, as are the splits of an instrument that was not held. Transactions that move cash only
+// are never adjusted.
+//
+// Each adjustment is recorded in the transaction with the stock and quantity it had before, so the shares held on an earlier day,
+// on which dividends are paid, can still be told. Quantities and amounts are kept as whole as possible, so lots, cost basis and
+// realized gains add up to the original ones.
+// The original transactions come first, in their order, followed by the ones added for spin-offs.
+func adjustTransactions(transactions []entities.Transaction, actions []entities.CorporateAction) []entities.Transaction {
+	adjusted := make([]entities.Transaction, 0, len(transactions))
+	var splits []entities.Transaction
+	for _, t := range transactions {
+		if t.Type() == entities.TransactionTypeSplit {
+			splits = append(splits, t)
+			continue
+		}
+		adjusted = append(adjusted, t)
+	}
+
+	sorted := make([]entities.CorporateAction, len(actions))
+	copy(sorted, actions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].EffectiveDate.Before(sorted[j].EffectiveDate)
+	})
+	sort.SliceStable(splits, func(i, j int) bool {
+		return splits[i].TransactionDate.Before(splits[j].TransactionDate)
+	})
+
+	for len(sorted) > 0 || len(splits) > 0 {
+		if len(splits) == 0 || (len(sorted) > 0 && !startOfDay(splits[0].TransactionDate).Before(startOfDay(sorted[0].EffectiveDate))) {
+			adjusted = applyCorporateAction(adjusted, sorted[0])
+			sorted = sorted[1:]
+			continue
+		}
+		applySplitTransaction(adjusted, splits[0])
+		splits = splits[1:]
+	}
+
+	return adjusted
+}
+
+// applyCorporateAction adjusts the transactions made before the effective day of a corporate action, returning them followed by
+// the ones the action adds
+func applyCorporateAction(adjusted []entities.Transaction, action entities.CorporateAction) []entities.Transaction {
+	if action.ActionType == entities.CorporateActionSymbolChange || !action.Numerator.IsPositive() || !action.Denominator.IsPositive() {
+		return adjusted
+	}
+	effective := startOfDay(action.EffectiveDate)
+	var held map[int]decimal.Decimal
+	if action.ActionType == entities.CorporateActionSpinOff {
+		held = heldPurchases(adjusted, action.StockID, effective)
+	}
+
+	var added []entities.Transaction
+	for i := range adjusted {
+		t := &adjusted[i]
+		if t.StockID != action.StockID || !t.MovesShares() || !t.TransactionDate.Before(effective) {
+			continue
+		}
+
+		switch action.ActionType {
+		case entities.CorporateActionSplit, entities.CorporateActionReverseSplit:
+			t.Adjustments = append(t.Adjustments, entities.TransactionAdjustment{EffectiveDate: effective, StockID: t.StockID, Quantity: t.Quantity})
+			t.Quantity, t.TransactionPrice.Amount = scaleTrade(t.Quantity, t.TransactionPrice.Amount, action.Numerator, action.Denominator)
+		case entities.CorporateActionMerger:
+			t.Adjustments = append(t.Adjustments, entities.TransactionAdjustment{EffectiveDate: effective, StockID: t.StockID, Quantity: t.Quantity})
+			t.Quantity, t.TransactionPrice.Amount = scaleTrade(t.Quantity, t.TransactionPrice.Amount, action.Numerator, action.Denominator)
+			t.StockID = action.TargetStockID
+		case entities.CorporateActionSpinOff:
+			kept := held[i]
+			if !kept.IsPositive() {
+				continue
+			}
+			parent := t
+			if kept.LessThan(t.Quantity) {
+				// the part sold before the spin-off stays as it is and the part held is split off to its own transaction
+				part := partOf(*t, kept)
+				*t = partOf(*t, t.Quantity.Sub(kept))
+				parent = &part
+			}
+
+			spunOff := *parent
+			spunOff.StockID = action.TargetStockID
+			spunOff.Adjustments = []entities.TransactionAdjustment{{EffectiveDate: effective, StockID: parent.StockID, Quantity: decimal.Zero}}
+			spunOff.Quantity, spunOff.TransactionPrice.Amount = scaleTrade(parent.Quantity, parent.TransactionPrice.Amount.Mul(action.CostAllocation), action.Numerator, action.Denominator)
+			parent.TransactionPrice.Amount = parent.TransactionPrice.Amount.Mul(decimal.NewFromInt(1).Sub(action.CostAllocation))
+			if parent != t {
+				added = append(added, *parent)
+			}
+			added = append(added, spunOff)
+		}
+	}
+	return append(adjusted, added...)
+}
+
+// heldPurchases returns the quantity of each purchase of a stock made before a day that is still held on that day, by the index of
+// the purchase. The sales and transfers out are taken from the purchases first in, first out, as buildLedger does with the lots.
+func heldPurchases(transactions []entities.Transaction, stockID string, day time.Time) map[int]decimal.Decimal {
+	var indexes []int
+	for i, t := range transactions {
+		if t.StockID == stockID && t.MovesShares() && t.TransactionDate.Before(day) {
+			indexes = append(indexes, i)
+		}
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return transactions[indexes[i]].TransactionDate.Before(transactions[indexes[j]].TransactionDate)
+	})
+
+	held := map[int]decimal.Decimal{}
+	var open []int
+	for _, i := range indexes {
+		t := transactions[i]
+		if t.Quantity.IsPositive() {
+			held[i] = t.Quantity
+			open = append(open, i)
+			continue
+		}
+
+		sold := t.Quantity.Neg()
+		for sold.IsPositive() && len(open) > 0 {
+			first := open[0]
+			taken := decimal.Min(sold, held[first])
+			held[first] = held[first].Sub(taken)
+			sold = sold.Sub(taken)
+			if !held[first].IsPositive() {
+				open = open[1:]
+			}
+		}
+	}
+	return held
+}
+
+// partOf returns a copy of a transaction for part of its quantity, with the quantities of its adjustments in the same proportion
+func partOf(t entities.Transaction, quantity decimal.Decimal) entities.Transaction {
+	part := t
+	part.Quantity = quantity
+	part.Adjustments = make([]entities.TransactionAdjustment, len(t.Adjustments))
+	for i, a := range t.Adjustments {
+		a.Quantity = a.Quantity.Mul(quantity).Div(t.Quantity)
+		part.Adjustments[i] = a
+	}
+	return part
+}
+
+// applySplitTransaction scales the transactions of the holding and instrument of a split transaction made before its day by the
+// ratio of the shares held after the split to the ones held before it
+func applySplitTransaction(adjusted []entities.Transaction, split entities.Transaction) {
+	instrumentID, _ := instrumentOf(split)
+	day := startOfDay(split.TransactionDate)
+
+	var earlier []*entities.Transaction
+	held := decimal.Zero
+	for i := range adjusted {
+		t := &adjusted[i]
+		if id, _ := instrumentOf(*t); id != instrumentID || t.HoldingID != split.HoldingID || !t.MovesShares() || !t.TransactionDate.Before(day) {
+			continue
+		}
+		earlier = append(earlier, t)
+		held = held.Add(t.Quantity)
+	}
+
+	after := held.Add(split.Quantity)
+	if !held.IsPositive() || !after.IsPositive() {
+		return
+	}
+	for _, t := range earlier {
+		t.Adjustments = append(t.Adjustments, entities.TransactionAdjustment{EffectiveDate: day, StockID: t.StockID, Quantity: t.Quantity})
+		t.Quantity, t.TransactionPrice.Amount = scaleTrade(t.Quantity, t.TransactionPrice.Amount, after, held)
+	}
+}
+
+// scaleTrade returns the quantity multiplied by the ratio of numerator to denominator and the price divided by it. Both are
+// multiplied before dividing, so ratios such as 1 for 3 keep whole quantities whole.
+func scaleTrade(quantity, price, numerator, denominator decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	return quantity.Mul(numerator).Div(denominator), price.Mul(denominator).Div(numerator)
+}
@@ -0,0 +1,416 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestNewCorporateActionService_Ok checks that NewCorporateActionService creates a new corporateActionService struct
+func TestNewCorporateActionService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+
+	// Act
+	service := NewCorporateActionService(cfg, mocks.NewCorporateActionRepository(t), mocks.NewStockRepository(t), mocks.NewTransactionRepository(t), mocks.NewHoldingRepository(t), mocks.NewLotService(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestCreateCorporateAction_Split checks that Create stores a split on the start of its effective day and refreshes the lot ledger
+// of the portfolios holding the stock
+func TestCreateCorporateAction_Split(t *testing.T) {
+	// Arrange
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-a").Return(&entities.Stock{StockID: "stock-a", TickerSymbol: "NVDA"}, nil).Once()
+
+	repositoryMock := mocks.NewCorporateActionRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.CorporateActionRepository.Create), context.Background(), mock.MatchedBy(func(action entities.CorporateAction) bool {
+		return action.StockID == "stock-a" && action.ActionType == entities.CorporateActionSplit && action.EffectiveDate.Equal(time.Date(2024, time.June, 10, 0, 0, 0, 0, time.UTC)) && action.OldSymbol == "" && !action.CreatedAt.IsZero()
+	})).Return("action-a", nil).Once()
+	repositoryMock.On(testutils.FunctionName(t, ports.CorporateActionRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return([]interface{}{&entities.CorporateAction{CorporateActionID: "action-a", StockID: "stock-a", ActionType: entities.CorporateActionSplit}}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-a"}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Transaction{TransactionID: "transaction-a", HoldingID: "holding-a", StockID: "stock-a"},
+		&entities.Transaction{TransactionID: "transaction-b", HoldingID: "holding-a", StockID: "stock-a"},
+	}, nil).Once()
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.GetByID), context.Background(), "holding-a").Return(&entities.Holding{HoldingID: "holding-a", PortfolioID: "portfolio-a"}, nil).Once()
+
+	lotServiceMock := mocks.NewLotService(t)
+	lotServiceMock.On(testutils.FunctionName(t, ports.LotService.Refresh), context.Background(), "portfolio-a").Return(nil).Once()
+
+	service := &corporateActionService{
+		config:                config.Config{},
+		repository:            repositoryMock,
+		stockRepository:       stockRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		holdingRepository:     holdingRepositoryMock,
+		lotService:            lotServiceMock,
+	}
+	req := models.CreateCorporateActionReq{ActionType: entities.CorporateActionSplit, EffectiveDate: time.Date(2024, time.June, 10, 13, 0, 0, 0, time.UTC), Numerator: decimal.NewFromInt(10), Denominator: decimal.NewFromInt(1)}
+
+	// Act
+	resp, err := service.Create(context.Background(), "stock-a", req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "action-a", resp.InsertedID)
+}
+
+// TestCreateCorporateAction_SymbolChange checks that Create renames the stock and keeps its previous symbol in the action
+func TestCreateCorporateAction_SymbolChange(t *testing.T) {
+	// Arrange
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-a").Return(&entities.Stock{StockID: "stock-a", TickerSymbol: "FB"}, nil).Once()
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.Update), context.Background(), "stock-a", mock.MatchedBy(func(stock entities.Stock) bool {
+		return stock.TickerSymbol == "META" && !stock.UpdatedAt.IsZero()
+	})).Return(nil).Once()
+
+	repositoryMock := mocks.NewCorporateActionRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.CorporateActionRepository.Create), context.Background(), mock.MatchedBy(func(action entities.CorporateAction) bool {
+		return action.OldSymbol == "FB" && action.NewSymbol == "META"
+	})).Return("action-a", nil).Once()
+	repositoryMock.On(testutils.FunctionName(t, ports.CorporateActionRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return([]interface{}{&entities.CorporateAction{CorporateActionID: "action-a", StockID: "stock-a", ActionType: entities.CorporateActionSymbolChange}}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-a"}, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &corporateActionService{
+		config:                config.Config{},
+		repository:            repositoryMock,
+		stockRepository:       stockRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		lotService:            mocks.NewLotService(t),
+	}
+	req := models.CreateCorporateActionReq{ActionType: entities.CorporateActionSymbolChange, EffectiveDate: time.Date(2022, time.June, 9, 0, 0, 0, 0, time.UTC), NewSymbol: " meta "}
+
+	// Act
+	resp, err := service.Create(context.Background(), "stock-a", req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "action-a", resp.InsertedID)
+}
+
+// TestCreateCorporateAction_TargetNotFound checks that Create returns a non existent error when the target stock does not exist
+func TestCreateCorporateAction_TargetNotFound(t *testing.T) {
+	// Arrange
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-a").Return(&entities.Stock{StockID: "stock-a"}, nil).Once()
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-b").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &corporateActionService{
+		config:          config.Config{},
+		stockRepository: stockRepositoryMock,
+	}
+	req := models.CreateCorporateActionReq{ActionType: entities.CorporateActionMerger, EffectiveDate: time.Date(2024, time.June, 10, 0, 0, 0, 0, time.UTC), Numerator: decimal.NewFromInt(1), Denominator: decimal.NewFromInt(2), TargetStockID: "stock-b"}
+	expectedError := "ID stock-b not found"
+
+	// Act
+	_, err := service.Create(context.Background(), "stock-a", req)
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetCorporateActionsByStockID_Empty checks that GetByStockID returns an empty list when the stock has no corporate actions
+func TestGetCorporateActionsByStockID_Empty(t *testing.T) {
+	// Arrange
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-a").Return(&entities.Stock{StockID: "stock-a"}, nil).Once()
+
+	repositoryMock := mocks.NewCorporateActionRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.CorporateActionRepository.GetByStockID), context.Background(), "stock-a").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &corporateActionService{
+		config:          config.Config{},
+		repository:      repositoryMock,
+		stockRepository: stockRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetByStockID(context.Background(), "stock-a")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []models.CorporateActionResp{}, resp)
+}
+
+// TestDeleteCorporateAction_OtherStock checks that Delete returns a non existent error when the action belongs to another stock
+func TestDeleteCorporateAction_OtherStock(t *testing.T) {
+	// Arrange
+	repositoryMock := mocks.NewCorporateActionRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.CorporateActionRepository.GetByID), context.Background(), "action-a").Return(&entities.CorporateAction{CorporateActionID: "action-a", StockID: "stock-b"}, nil).Once()
+
+	service := &corporateActionService{
+		config:     config.Config{},
+		repository: repositoryMock,
+	}
+	expectedError := "ID action-a not found"
+
+	// Act
+	err := service.Delete(context.Background(), "stock-a", "action-a")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestDeleteCorporateAction_Merger checks that Delete refreshes the lot ledger of the portfolios holding either stock of a merger,
+// or a stock linked to them by another action
+func TestDeleteCorporateAction_Merger(t *testing.T) {
+	// Arrange
+	repositoryMock := mocks.NewCorporateActionRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.CorporateActionRepository.GetByID), context.Background(), "action-a").Return(&entities.CorporateAction{CorporateActionID: "action-a", StockID: "stock-a", TargetStockID: "stock-b", ActionType: entities.CorporateActionMerger}, nil).Once()
+	repositoryMock.On(testutils.FunctionName(t, ports.CorporateActionRepository.Delete), context.Background(), "action-a").Return(nil).Once()
+	repositoryMock.On(testutils.FunctionName(t, ports.CorporateActionRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.CorporateAction{CorporateActionID: "action-b", StockID: "stock-b", TargetStockID: "stock-c", ActionType: entities.CorporateActionSpinOff},
+		&entities.CorporateAction{CorporateActionID: "action-c", StockID: "stock-d", ActionType: entities.CorporateActionSplit},
+	}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-a"}, (*int)(nil), (*int)(nil)).Return([]interface{}{&entities.Transaction{TransactionID: "transaction-a", HoldingID: "holding-a", StockID: "stock-a"}}, nil).Once()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-b"}, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-c"}, (*int)(nil), (*int)(nil)).Return([]interface{}{&entities.Transaction{TransactionID: "transaction-c", HoldingID: "holding-c", StockID: "stock-c"}}, nil).Once()
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.GetByID), context.Background(), "holding-a").Return(&entities.Holding{HoldingID: "holding-a", PortfolioID: "portfolio-b"}, nil).Once()
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.GetByID), context.Background(), "holding-c").Return(&entities.Holding{HoldingID: "holding-c", PortfolioID: "portfolio-a"}, nil).Once()
+
+	var refreshed []string
+	lotServiceMock := mocks.NewLotService(t)
+	lotServiceMock.On(testutils.FunctionName(t, ports.LotService.Refresh), context.Background(), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		refreshed = append(refreshed, args.String(1))
+	}).Return(nil).Twice()
+
+	service := &corporateActionService{
+		config:                config.Config{},
+		repository:            repositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		holdingRepository:     holdingRepositoryMock,
+		lotService:            lotServiceMock,
+	}
+
+	// Act
+	err := service.Delete(context.Background(), "stock-a", "action-a")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"portfolio-a", "portfolio-b"}, refreshed)
+}
+
+// TestAdjustTransactions_SplitAndMerger checks that adjustTransactions scales the transactions made before each action and moves merged ones to the acquiring stock
+func TestAdjustTransactions_SplitAndMerger(t *testing.T) {
+	// Arrange
+	transactions := []entities.Transaction{
+		{TransactionID: "t1", StockID: "stock-a", Quantity: decimal.NewFromInt(10), TransactionPrice: entities.Money{Amount: decimal.NewFromInt(1000), Currency: "USD"}, TransactionDate: time.Date(2024, time.January, 5, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "t2", StockID: "stock-a", Quantity: decimal.NewFromInt(-50), TransactionPrice: entities.Money{Amount: decimal.NewFromInt(120), Currency: "USD"}, TransactionDate: time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "t3", StockID: "stock-c", Quantity: decimal.NewFromInt(3), TransactionPrice: entities.Money{Amount: decimal.NewFromInt(50), Currency: "USD"}, TransactionDate: time.Date(2024, time.January, 5, 0, 0, 0, 0, time.UTC)},
+	}
+	actions := []entities.CorporateAction{
+		{StockID: "stock-a", ActionType: entities.CorporateActionMerger, EffectiveDate: time.Date(2024, time.September, 2, 0, 0, 0, 0, time.UTC), Numerator: decimal.NewFromInt(1), Denominator: decimal.NewFromInt(2), TargetStockID: "stock-b"},
+		{StockID: "stock-a", ActionType: entities.CorporateActionSplit, EffectiveDate: time.Date(2024, time.June, 10, 0, 0, 0, 0, time.UTC), Numerator: decimal.NewFromInt(10), Denominator: decimal.NewFromInt(1)},
+		{StockID: "stock-a", ActionType: entities.CorporateActionSymbolChange, EffectiveDate: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), NewSymbol: "NEW"},
+	}
+
+	// Act
+	adjusted := adjustTransactions(transactions, actions)
+
+	// Assert
+	assert.Len(t, adjusted, 3)
+	assert.Equal(t, "stock-b", adjusted[0].StockID)
+	assert.True(t, decimal.NewFromInt(50).Equal(adjusted[0].Quantity))
+	assert.True(t, decimal.NewFromInt(200).Equal(adjusted[0].TransactionPrice.Amount))
+	assert.Equal(t, "stock-b", adjusted[1].StockID)
+	assert.True(t, decimal.NewFromInt(-25).Equal(adjusted[1].Quantity))
+	assert.True(t, decimal.NewFromInt(240).Equal(adjusted[1].TransactionPrice.Amount))
+	assert.Equal(t, transactions[2], adjusted[2])
+	assert.Equal(t, "stock-a", transactions[0].StockID)
+	assert.True(t, decimal.NewFromInt(10).Equal(transactions[0].Quantity))
+}
+
+// TestAdjustTransactions_SpinOff checks that adjustTransactions adds a transaction of the spun-off stock and splits the cost between both stocks
+func TestAdjustTransactions_SpinOff(t *testing.T) {
+	// Arrange
+	transactions := []entities.Transaction{
+		{TransactionID: "t1", StockID: "stock-a", Quantity: decimal.NewFromInt(30), TransactionPrice: entities.Money{Amount: decimal.NewFromInt(100), Currency: "USD"}, TransactionDate: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "t2", StockID: "stock-a", Quantity: decimal.NewFromInt(5), TransactionPrice: entities.Money{Amount: decimal.NewFromInt(90), Currency: "USD"}, TransactionDate: time.Date(2024, time.April, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	actions := []entities.CorporateAction{
+		{StockID: "stock-a", ActionType: entities.CorporateActionSpinOff, EffectiveDate: time.Date(2024, time.April, 2, 0, 0, 0, 0, time.UTC), Numerator: decimal.NewFromInt(1), Denominator: decimal.NewFromInt(3), TargetStockID: "stock-b", CostAllocation: decimal.RequireFromString("0.2")},
+	}
+
+	// Act
+	adjusted := adjustTransactions(transactions, actions)
+
+	// Assert
+	assert.Len(t, adjusted, 3)
+	assert.Equal(t, "stock-a", adjusted[0].StockID)
+	assert.True(t, decimal.NewFromInt(30).Equal(adjusted[0].Quantity))
+	assert.True(t, decimal.NewFromInt(80).Equal(adjusted[0].TransactionPrice.Amount))
+	assert.Equal(t, transactions[1], adjusted[1])
+	assert.Equal(t, "stock-b", adjusted[2].StockID)
+	assert.Equal(t, transactions[0].TransactionDate, adjusted[2].TransactionDate)
+	assert.True(t, decimal.NewFromInt(10).Equal(adjusted[2].Quantity))
+	assert.True(t, decimal.NewFromInt(60).Equal(adjusted[2].TransactionPrice.Amount))
+}
+
+// TestAdjustTransactions_SpinOffAfterSell checks that adjustTransactions only spins off the shares still held on the effective day
+// and leaves the earlier sales and the shares they sold as they were
+func TestAdjustTransactions_SpinOffAfterSell(t *testing.T) {
+	// Arrange
+	transactions := []entities.Transaction{
+		{TransactionID: "t1", HoldingID: "holding-a", StockID: "stock-a", TransactionType: entities.TransactionTypeBuy, Quantity: decimal.NewFromInt(10), TransactionPrice: entities.NewMoney(decimal.NewFromInt(100), "USD"), TransactionDate: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "t2", HoldingID: "holding-a", StockID: "stock-a", TransactionType: entities.TransactionTypeBuy, Quantity: decimal.NewFromInt(20), TransactionPrice: entities.NewMoney(decimal.NewFromInt(90), "USD"), TransactionDate: time.Date(2023, time.April, 3, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "t3", HoldingID: "holding-a", StockID: "stock-a", TransactionType: entities.TransactionTypeSell, Quantity: decimal.NewFromInt(-15), TransactionPrice: entities.NewMoney(decimal.NewFromInt(120), "USD"), TransactionDate: time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	actions := []entities.CorporateAction{
+		{StockID: "stock-a", ActionType: entities.CorporateActionSpinOff, EffectiveDate: time.Date(2024, time.April, 2, 0, 0, 0, 0, time.UTC), Numerator: decimal.NewFromInt(1), Denominator: decimal.NewFromInt(3), TargetStockID: "stock-b", CostAllocation: decimal.RequireFromString("0.2")},
+	}
+
+	// Act
+	adjusted := adjustTransactions(transactions, actions)
+
+	// Assert
+	assert.Len(t, adjusted, 5)
+	assert.Equal(t, transactions[0], adjusted[0])
+	assert.Equal(t, "t2", adjusted[1].TransactionID)
+	assert.Equal(t, "5", adjusted[1].Quantity.String())
+	assert.Equal(t, "90", adjusted[1].TransactionPrice.Amount.String())
+	assert.Equal(t, transactions[2], adjusted[2])
+	assert.Equal(t, "t2", adjusted[3].TransactionID)
+	assert.Equal(t, "stock-a", adjusted[3].StockID)
+	assert.Equal(t, "15", adjusted[3].Quantity.String())
+	assert.Equal(t, "72", adjusted[3].TransactionPrice.Amount.String())
+	assert.Equal(t, "stock-b", adjusted[4].StockID)
+	assert.Equal(t, transactions[1].TransactionDate, adjusted[4].TransactionDate)
+	assert.Equal(t, "5", adjusted[4].Quantity.String())
+	assert.Equal(t, "54", adjusted[4].TransactionPrice.Amount.String())
+
+	ledger, err := buildLedger(adjusted, entities.CostBasisFIFO)
+	assert.Nil(t, err)
+	assert.Len(t, ledger.gains, 2)
+	assert.Equal(t, "200", ledger.gains[0].Gain.Amount.String())
+	assert.Equal(t, "150", ledger.gains[1].Gain.Amount.String())
+}
+
+// TestAdjustTransactions_SplitTransaction checks that adjustTransactions scales the earlier transactions of the holding by the shares a split transaction adds
+// and leaves out the split and the cash transactions
+func TestAdjustTransactions_SplitTransaction(t *testing.T) {
+	// Arrange
+	transactions := []entities.Transaction{
+		{TransactionID: "t1", HoldingID: "holding-a", StockID: "stock-a", TransactionType: entities.TransactionTypeBuy, Quantity: decimal.NewFromInt(4), TransactionPrice: entities.NewMoney(decimal.NewFromInt(300), "USD"), TransactionDate: time.Date(2024, time.January, 5, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "t2", HoldingID: "holding-a", StockID: "stock-a", TransactionType: entities.TransactionTypeSplit, Quantity: decimal.NewFromInt(8), TransactionDate: time.Date(2024, time.June, 10, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "t3", HoldingID: "holding-a", StockID: "stock-a", TransactionType: entities.TransactionTypeDividend, TransactionPrice: entities.NewMoney(decimal.NewFromInt(6), "USD"), TransactionDate: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "t4", HoldingID: "holding-b", StockID: "stock-a", TransactionType: entities.TransactionTypeBuy, Quantity: decimal.NewFromInt(2), TransactionPrice: entities.NewMoney(decimal.NewFromInt(310), "USD"), TransactionDate: time.Date(2024, time.February, 5, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "t5", HoldingID: "holding-a", StockID: "stock-a", TransactionType: entities.TransactionTypeSell, Quantity: decimal.NewFromInt(-3), TransactionPrice: entities.NewMoney(decimal.NewFromInt(110), "USD"), TransactionDate: time.Date(2024, time.June, 10, 0, 0, 0, 0, time.UTC)},
+	}
+	actions := []entities.CorporateAction{
+		{StockID: "stock-a", ActionType: entities.CorporateActionReverseSplit, EffectiveDate: time.Date(2024, time.September, 2, 0, 0, 0, 0, time.UTC), Numerator: decimal.NewFromInt(1), Denominator: decimal.NewFromInt(3)},
+	}
+
+	// Act
+	adjusted := adjustTransactions(transactions, actions)
+
+	// Assert
+	assert.Len(t, adjusted, 4)
+	assert.Equal(t, "t1", adjusted[0].TransactionID)
+	assert.Equal(t, "4", adjusted[0].Quantity.String())
+	assert.Equal(t, "300", adjusted[0].TransactionPrice.Amount.String())
+	assert.Equal(t, transactions[2], adjusted[1])
+	assert.Equal(t, "t4", adjusted[2].TransactionID)
+	assert.Equal(t, "0.6666666666666667", adjusted[2].Quantity.String())
+	assert.Equal(t, "-1", adjusted[3].Quantity.String())
+	assert.Equal(t, "330", adjusted[3].TransactionPrice.Amount.String())
+}
+
+// TestAdjustedTransactionRepository_GetByPortfolioID checks that the adjusted transaction repository returns the transactions adjusted by the corporate actions
+func TestAdjustedTransactionRepository_GetByPortfolioID(t *testing.T) {
+	// Arrange
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-a").Return([]interface{}{
+		&entities.Transaction{TransactionID: "t1", StockID: "stock-a", Quantity: decimal.NewFromInt(2), TransactionPrice: entities.Money{Amount: decimal.NewFromInt(400), Currency: "USD"}, TransactionDate: time.Date(2020, time.January, 2, 0, 0, 0, 0, time.UTC)},
+	}, nil).Once()
+
+	corporateActionRepositoryMock := mocks.NewCorporateActionRepository(t)
+	corporateActionRepositoryMock.On(testutils.FunctionName(t, ports.CorporateActionRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.CorporateAction{StockID: "stock-a", ActionType: entities.CorporateActionSplit, EffectiveDate: time.Date(2020, time.August, 31, 0, 0, 0, 0, time.UTC), Numerator: decimal.NewFromInt(4), Denominator: decimal.NewFromInt(1)},
+	}, nil).Once()
+
+	repository := NewAdjustedTransactionRepository(transactionRepositoryMock, corporateActionRepositoryMock)
+
+	// Act
+	result, err := repository.GetByPortfolioID(context.Background(), "portfolio-a")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+	transaction := result[0].(*entities.Transaction)
+	assert.True(t, decimal.NewFromInt(8).Equal(transaction.Quantity))
+	assert.True(t, decimal.NewFromInt(100).Equal(transaction.TransactionPrice.Amount))
+}
+
+// TestAdjustedTransactionRepository_StreamByPortfolioID checks that the adjusted transaction repository streams the adjusted transactions
+func TestAdjustedTransactionRepository_StreamByPortfolioID(t *testing.T) {
+	// Arrange
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-a").Return([]interface{}{
+		&entities.Transaction{TransactionID: "t1", StockID: "stock-a", Quantity: decimal.NewFromInt(2), TransactionPrice: entities.Money{Amount: decimal.NewFromInt(400), Currency: "USD"}, TransactionDate: time.Date(2020, time.January, 2, 0, 0, 0, 0, time.UTC)},
+	}, nil).Once()
+
+	corporateActionRepositoryMock := mocks.NewCorporateActionRepository(t)
+	corporateActionRepositoryMock.On(testutils.FunctionName(t, ports.CorporateActionRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.CorporateAction{StockID: "stock-a", ActionType: entities.CorporateActionSplit, EffectiveDate: time.Date(2020, time.August, 31, 0, 0, 0, 0, time.UTC), Numerator: decimal.NewFromInt(4), Denominator: decimal.NewFromInt(1)},
+	}, nil).Once()
+
+	repository := NewAdjustedTransactionRepository(transactionRepositoryMock, corporateActionRepositoryMock)
+
+	// Act
+	var result []entities.Transaction
+	err := repository.StreamByPortfolioID(context.Background(), "portfolio-a", func(transaction entities.Transaction) error {
+		result = append(result, transaction)
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+	assert.True(t, decimal.NewFromInt(8).Equal(result[0].Quantity))
+}
+
+// TestAdjustedTransactionRepository_NoActions checks that the adjusted transaction repository returns the stored transactions when there are no corporate actions
+func TestAdjustedTransactionRepository_NoActions(t *testing.T) {
+	// Arrange
+	expected := []interface{}{&entities.Transaction{TransactionID: "t1", StockID: "stock-a", Quantity: decimal.NewFromInt(2)}}
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-a"}, (*int)(nil), (*int)(nil)).Return(expected, nil).Once()
+
+	corporateActionRepositoryMock := mocks.NewCorporateActionRepository(t)
+	corporateActionRepositoryMock.On(testutils.FunctionName(t, ports.CorporateActionRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	repository := NewAdjustedTransactionRepository(transactionRepositoryMock, corporateActionRepositoryMock)
+
+	// Act
+	result, err := repository.Get(context.Background(), map[string]interface{}{"stockid": "stock-a"}, nil, nil)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+}
@@ -0,0 +1,384 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	_ "embed" // embeds the digest templates
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"log"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+const (
+	// defaultDigestTopMovers is the number of top movers listed when the config does not set it
+	defaultDigestTopMovers = 5
+	// defaultDigestEventDays is how many days ahead the upcoming events are listed when the config does not set it
+	defaultDigestEventDays = 14
+)
+
+var (
+	//go:embed templates/digest.txt.tmpl
+	digestTextSource string
+	//go:embed templates/digest.html.tmpl
+	digestHTMLSource string
+
+	// digestFuncs are the helpers available to the digest templates
+	digestFuncs = map[string]interface{}{
+		"date": func(t time.Time) string {
+			return t.Format("2006-01-02")
+		},
+		"pct": func(v float64) string {
+			return fmt.Sprintf("%+.2f%%", v)
+		},
+		"label": func(s string) string {
+			return strings.ReplaceAll(s, "_", " ")
+		},
+	}
+
+	digestTextTemplate = texttemplate.Must(texttemplate.New("digest.txt").Funcs(digestFuncs).Parse(digestTextSource))
+	digestHTMLTemplate = htmltemplate.Must(htmltemplate.New("digest.html").Funcs(digestFuncs).Parse(digestHTMLSource))
+)
+
+// digestService adapter of a portfolio digest service
+type digestService struct {
+	config                   config.Config
+	repository               ports.DigestRepository
+	portfolioRepository      ports.PortfolioRepository
+	userRepository           ports.UserRepository
+	transactionRepository    ports.TransactionRepository
+	stockRepository          ports.StockRepository
+	cryptoCurrencyRepository ports.CryptoCurrencyRepository
+	companyEventRepository   ports.CompanyEventRepository
+	performanceService       ports.PerformanceService
+	priceSource              ports.PriceSource
+	mailer                   ports.Mailer
+}
+
+// NewDigestService creates a new portfolio digest service
+func NewDigestService(cfg config.Config, repo ports.DigestRepository, portfolioRepo ports.PortfolioRepository, userRepo ports.UserRepository, transactionRepo ports.TransactionRepository, stockRepo ports.StockRepository, cryptoCurrencyRepo ports.CryptoCurrencyRepository, companyEventRepo ports.CompanyEventRepository, performanceService ports.PerformanceService, priceSource ports.PriceSource, mailer ports.Mailer) ports.DigestService {
+	return &digestService{
+		config:                   cfg,
+		repository:               repo,
+		portfolioRepository:      portfolioRepo,
+		userRepository:           userRepo,
+		transactionRepository:    transactionRepo,
+		stockRepository:          stockRepo,
+		cryptoCurrencyRepository: cryptoCurrencyRepo,
+		companyEventRepository:   companyEventRepo,
+		performanceService:       performanceService,
+		priceSource:              priceSource,
+		mailer:                   mailer,
+	}
+}
+
+// Generate returns the digest of a portfolio of the user for the last complete week or month, weekly when no period is requested
+func (s *digestService) Generate(ctx context.Context, userID, portfolioID string, req models.DigestReq) (resp models.DigestResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if req.Period == "" {
+		req.Period = entities.DigestPeriodWeekly
+	}
+
+	portfolio, transactions, err := loadPortfolioTransactions(ctx, s.portfolioRepository, s.transactionRepository, portfolioID)
+	if err != nil {
+		return
+	}
+	if err = checkOwner(portfolio, userID); err != nil {
+		return
+	}
+
+	from, to := digestPeriod(req.Period, time.Now().UTC())
+	return s.digest(ctx, portfolio, transactions, req.Period, from, to)
+}
+
+// Send mails the digests of the last complete periods of the config to the owners of the portfolios with the summary enabled.
+// Every digest is sent once per portfolio and period, and a digest that cannot be delivered is sent again on the next run.
+func (s *digestService) Send(ctx context.Context) (resp models.DigestSendResp, err error) {
+	var periods []string
+	for _, period := range s.config.Digest.Periods {
+		if models.ValidDigestPeriod(period) {
+			periods = append(periods, period)
+		}
+	}
+	if len(periods) == 0 {
+		return
+	}
+
+	result, err := s.portfolioRepository.Get(ctx, map[string]interface{}{"summary": true}, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	now := time.Now().UTC()
+	for _, v := range result {
+		portfolio := *(v.(*entities.Portfolio))
+		if !portfolio.Summary {
+			continue
+		}
+		resp.Portfolios++
+
+		for _, period := range periods {
+			from, to := digestPeriod(period, now)
+			sent, existsErr := s.repository.Exists(ctx, portfolio.PortfolioID, period, to)
+			if existsErr != nil {
+				err = existsErr
+				return
+			}
+			if sent {
+				continue
+			}
+
+			sendErr := s.send(ctx, portfolio, period, from, to)
+			if sendErr != nil {
+				if ctx.Err() != nil {
+					err = ctx.Err()
+					return
+				}
+				log.Printf("cannot deliver %s digest to portfolio %s, error: %s", period, portfolio.PortfolioID, sendErr)
+				resp.Undelivered++
+				continue
+			}
+			resp.Sent++
+		}
+	}
+	return
+}
+
+// send mails the digest of a portfolio for a period to its owner and records it as sent
+func (s *digestService) send(ctx context.Context, portfolio entities.Portfolio, period string, from, to time.Time) error {
+	user, err := s.userRepository.GetByID(ctx, portfolio.Userid)
+	if err != nil {
+		return err
+	}
+	owner := *(user.(*entities.User))
+	if owner.Email == "" {
+		return fmt.Errorf("user %s has no email", owner.UserID)
+	}
+
+	_, transactions, err := loadPortfolioTransactions(ctx, s.portfolioRepository, s.transactionRepository, portfolio.PortfolioID)
+	if err != nil {
+		return err
+	}
+	digest, err := s.digest(ctx, portfolio, transactions, period, from, to)
+	if err != nil {
+		return err
+	}
+
+	mail, err := digestMail(owner, digest)
+	if err != nil {
+		return err
+	}
+	if err := s.mailer.Send(ctx, mail); err != nil {
+		return err
+	}
+
+	_, err = s.repository.Create(ctx, entities.Digest{
+		PortfolioID: portfolio.PortfolioID,
+		Period:      period,
+		PeriodStart: from,
+		PeriodEnd:   to,
+		SentTo:      owner.Email,
+		SentAt:      time.Now().UTC(),
+	})
+	return err
+}
+
+// digest computes the digest of a portfolio between two days, both included
+func (s *digestService) digest(ctx context.Context, portfolio entities.Portfolio, transactions []entities.Transaction, period string, from, to time.Time) (resp models.DigestResp, err error) {
+	performance, err := s.performanceService.GetPortfolioPerformance(ctx, portfolio.Userid, portfolio.PortfolioID, models.PerformanceReq{From: from, To: to})
+	if err != nil {
+		return
+	}
+	valueChange, err := performance.EndValue.Sub(performance.StartValue)
+	if err != nil {
+		return
+	}
+
+	resp = models.DigestResp{
+		PortfolioID:      portfolio.PortfolioID,
+		PortfolioName:    portfolio.Name,
+		Period:           period,
+		From:             from,
+		To:               to,
+		StartValue:       performance.StartValue,
+		EndValue:         performance.EndValue,
+		ValueChange:      valueChange,
+		NetContributions: performance.NetContributions,
+		ReturnPct:        performance.TimeWeightedReturnPct,
+		Dividends:        performance.Dividends,
+		TopMovers:        []models.DigestMoverResp{},
+		UpcomingEvents:   []models.DigestEventResp{},
+	}
+	resp.ValueChangePct = percentage(resp.ValueChange.Amount, performance.StartValue.Amount)
+
+	var held []entities.Transaction
+	for _, t := range transactions {
+		if t.TransactionDate.Before(to.Add(oneDay)) {
+			held = append(held, t)
+		}
+	}
+	ledger, err := buildLedger(held, costBasisMethod(portfolio))
+	if err != nil {
+		return
+	}
+
+	if resp.TopMovers, err = s.topMovers(ctx, ledger.positions, from, to); err != nil {
+		return
+	}
+	resp.UpcomingEvents, err = s.upcomingEvents(ctx, ledger.positions)
+	return
+}
+
+// topMovers returns the open positions whose price moved the most over a period, from the last close before it to its last day
+func (s *digestService) topMovers(ctx context.Context, positions []position, from, to time.Time) ([]models.DigestMoverResp, error) {
+	movers := []models.DigestMoverResp{}
+	for _, p := range positions {
+		if !p.quantity.IsPositive() {
+			continue
+		}
+
+		start, err := s.priceSource.PriceAt(ctx, p.instrumentID, from.Add(-oneDay))
+		if err == nil {
+			var end, change entities.Money
+			if end, err = s.priceSource.PriceAt(ctx, p.instrumentID, to); err == nil && start.Amount.IsPositive() {
+				if change, err = end.Sub(start); err != nil {
+					return nil, err
+				}
+				movers = append(movers, models.DigestMoverResp{
+					InstrumentID:   p.instrumentID,
+					InstrumentType: p.instrumentType,
+					Symbol:         s.symbol(ctx, p.instrumentID, p.instrumentType),
+					Quantity:       p.quantity,
+					StartPrice:     start,
+					EndPrice:       end,
+					ChangePct:      changePct(end.Amount, start.Amount),
+					ValueChange:    change.Mul(p.quantity),
+				})
+			}
+		}
+		if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(movers, func(i, j int) bool {
+		if a, b := movers[i].ChangePct.Abs(), movers[j].ChangePct.Abs(); !a.Equal(b) {
+			return a.GreaterThan(b)
+		}
+		return movers[i].InstrumentID < movers[j].InstrumentID
+	})
+
+	limit := s.config.Digest.TopMovers
+	if limit <= 0 {
+		limit = defaultDigestTopMovers
+	}
+	if len(movers) > limit {
+		movers = movers[:limit]
+	}
+	return movers, nil
+}
+
+// upcomingEvents returns the events of the stocks held in open positions from today to the event days of the config, soonest first
+func (s *digestService) upcomingEvents(ctx context.Context, positions []position) ([]models.DigestEventResp, error) {
+	events := []models.DigestEventResp{}
+	held := map[string]bool{}
+	for _, p := range positions {
+		if p.quantity.IsPositive() && p.instrumentType == entities.EntityNameStock {
+			held[p.instrumentID] = true
+		}
+	}
+	if len(held) == 0 {
+		return events, nil
+	}
+
+	days := s.config.Digest.EventDays
+	if days <= 0 {
+		days = defaultDigestEventDays
+	}
+	from := startOfDay(time.Now().UTC())
+	result, err := s.companyEventRepository.GetRange(ctx, from, from.AddDate(0, 0, days))
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return events, err
+	}
+
+	for _, v := range result {
+		event := v.(*entities.CompanyEvent)
+		if !held[event.StockID] {
+			continue
+		}
+		events = append(events, models.DigestEventResp{
+			StockID:     event.StockID,
+			Symbol:      s.symbol(ctx, event.StockID, entities.EntityNameStock),
+			EventType:   event.EventType,
+			EventDate:   event.EventDate,
+			Amount:      event.Amount,
+			Description: event.Description,
+		})
+	}
+	return events, nil
+}
+
+// symbol returns the symbol of an instrument, or its ID when it has none
+func (s *digestService) symbol(ctx context.Context, instrumentID, instrumentType string) string {
+	symbol, err := instrumentSymbol(ctx, s.stockRepository, s.cryptoCurrencyRepository, openInstrument{id: instrumentID, instrumentType: instrumentType})
+	if err != nil {
+		return instrumentID
+	}
+	return symbol
+}
+
+// digestPeriod returns the first and last day of the last complete week, from Monday to Sunday, or month before now
+func digestPeriod(period string, now time.Time) (from, to time.Time) {
+	today := startOfDay(now)
+	if period == entities.DigestPeriodMonthly {
+		first := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return first.AddDate(0, -1, 0), first.AddDate(0, 0, -1)
+	}
+
+	to = today.AddDate(0, 0, -((int(today.Weekday())+6)%7 + 1))
+	return to.AddDate(0, 0, -6), to
+}
+
+// digestMailData is the data the digest templates are rendered with
+type digestMailData struct {
+	Firstname string
+	Digest    models.DigestResp
+}
+
+// digestMail renders the digest of a portfolio as a mail to its owner
+func digestMail(owner entities.User, digest models.DigestResp) (entities.Mail, error) {
+	data := digestMailData{Firstname: owner.Firstname, Digest: digest}
+
+	var text, html bytes.Buffer
+	if err := digestTextTemplate.Execute(&text, data); err != nil {
+		return entities.Mail{}, err
+	}
+	if err := digestHTMLTemplate.Execute(&html, data); err != nil {
+		return entities.Mail{}, err
+	}
+
+	return entities.Mail{
+		To:      owner.Email,
+		Subject: fmt.Sprintf("Your %s summary of %s", digest.Period, digest.PortfolioName),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestNewDigestService_Ok checks that NewDigestService creates a new digestService struct
+func TestNewDigestService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+
+	// Act
+	service := NewDigestService(cfg, mocks.NewDigestRepository(t), mocks.NewPortfolioRepository(t), mocks.NewUserRepository(t), mocks.NewTransactionRepository(t), mocks.NewStockRepository(t), mocks.NewCryptoCurrencyRepository(t), mocks.NewCompanyEventRepository(t), mocks.NewPerformanceService(t), mocks.NewPriceSource(t), mocks.NewMailer(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestDigestPeriod_Ok checks that digestPeriod returns the last complete week from Monday to Sunday and the last complete month
+func TestDigestPeriod_Ok(t *testing.T) {
+	// Arrange
+	cases := []struct {
+		period       string
+		now          time.Time
+		expectedFrom time.Time
+		expectedTo   time.Time
+	}{
+		{entities.DigestPeriodWeekly, time.Date(2023, time.August, 14, 8, 0, 0, 0, time.UTC), time.Date(2023, time.August, 7, 0, 0, 0, 0, time.UTC), time.Date(2023, time.August, 13, 0, 0, 0, 0, time.UTC)},
+		{entities.DigestPeriodWeekly, time.Date(2023, time.August, 13, 8, 0, 0, 0, time.UTC), time.Date(2023, time.July, 31, 0, 0, 0, 0, time.UTC), time.Date(2023, time.August, 6, 0, 0, 0, 0, time.UTC)},
+		{entities.DigestPeriodMonthly, time.Date(2023, time.March, 1, 8, 0, 0, 0, time.UTC), time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, time.February, 28, 0, 0, 0, 0, time.UTC)},
+		{entities.DigestPeriodMonthly, time.Date(2023, time.January, 20, 8, 0, 0, 0, time.UTC), time.Date(2022, time.December, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, time.December, 31, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, c := range cases {
+		// Act
+		from, to := digestPeriod(c.period, c.now)
+
+		// Assert
+		assert.Equal(t, c.expectedFrom, from, c.now)
+		assert.Equal(t, c.expectedTo, to, c.now)
+	}
+}
+
+// TestGenerateDigest_Ok checks that Generate returns the value change, top movers, dividends and upcoming events of the portfolio
+func TestGenerateDigest_Ok(t *testing.T) {
+	// Arrange
+	from, to := digestPeriod(entities.DigestPeriodWeekly, time.Now().UTC())
+	today := startOfDay(time.Now().UTC())
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a", Name: "Growth"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-a").Return([]interface{}{
+		&entities.Transaction{StockID: "stock-a", Quantity: decimal.NewFromInt(10), TransactionPrice: entities.NewMoney(decimal.NewFromInt(100), "USD"), TransactionDate: from.AddDate(0, -1, 0)},
+		&entities.Transaction{StockID: "stock-b", Quantity: decimal.NewFromInt(5), TransactionPrice: entities.NewMoney(decimal.NewFromInt(50), "USD"), TransactionDate: from.AddDate(0, -1, 0)},
+		&entities.Transaction{StockID: "stock-c", Quantity: decimal.NewFromInt(1), TransactionPrice: entities.NewMoney(decimal.NewFromInt(10), "USD"), TransactionDate: to.Add(oneDay)},
+	}, nil).Once()
+
+	performanceServiceMock := mocks.NewPerformanceService(t)
+	performanceServiceMock.On(testutils.FunctionName(t, ports.PerformanceService.GetPortfolioPerformance), context.Background(), "user-a", "portfolio-a", models.PerformanceReq{From: from, To: to}).Return(models.PerformanceResp{
+		StartValue:            entities.NewMoney(decimal.NewFromInt(1000), "USD"),
+		EndValue:              entities.NewMoney(decimal.NewFromInt(1100), "USD"),
+		NetContributions:      entities.NewMoney(decimal.NewFromInt(50), "USD"),
+		Dividends:             entities.NewMoney(decimal.NewFromInt(3), "USD"),
+		TimeWeightedReturnPct: 4.5,
+	}, nil).Once()
+
+	priceSourceMock := mocks.NewPriceSource(t)
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.PriceAt), context.Background(), "stock-a", from.Add(-oneDay)).Return(entities.NewMoney(decimal.NewFromInt(100), "USD"), nil).Once()
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.PriceAt), context.Background(), "stock-a", to).Return(entities.NewMoney(decimal.NewFromInt(105), "USD"), nil).Once()
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.PriceAt), context.Background(), "stock-b", from.Add(-oneDay)).Return(entities.NewMoney(decimal.NewFromInt(50), "USD"), nil).Once()
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.PriceAt), context.Background(), "stock-b", to).Return(entities.NewMoney(decimal.NewFromInt(40), "USD"), nil).Once()
+
+	companyEventRepositoryMock := mocks.NewCompanyEventRepository(t)
+	companyEventRepositoryMock.On(testutils.FunctionName(t, ports.CompanyEventRepository.GetRange), context.Background(), today, today.AddDate(0, 0, defaultDigestEventDays)).Return([]interface{}{
+		&entities.CompanyEvent{StockID: "stock-a", EventType: entities.CompanyEventEarnings, EventDate: today.AddDate(0, 0, 2)},
+		&entities.CompanyEvent{StockID: "stock-z", EventType: entities.CompanyEventEarnings, EventDate: today.AddDate(0, 0, 3)},
+	}, nil).Once()
+
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-a").Return(&entities.Stock{StockID: "stock-a", TickerSymbol: "AAPL"}, nil)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-b").Return(&entities.Stock{StockID: "stock-b", TickerSymbol: "MSFT"}, nil)
+
+	service := &digestService{
+		config:                 config.Config{},
+		portfolioRepository:    portfolioRepositoryMock,
+		transactionRepository:  transactionRepositoryMock,
+		stockRepository:        stockRepositoryMock,
+		companyEventRepository: companyEventRepositoryMock,
+		performanceService:     performanceServiceMock,
+		priceSource:            priceSourceMock,
+	}
+
+	// Act
+	resp, err := service.Generate(context.Background(), "user-a", "portfolio-a", models.DigestReq{})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, entities.DigestPeriodWeekly, resp.Period)
+	assert.Equal(t, "Growth", resp.PortfolioName)
+	assert.Equal(t, "100 USD", resp.ValueChange.String())
+	assert.Equal(t, 10.0, resp.ValueChangePct)
+	assert.Equal(t, 4.5, resp.ReturnPct)
+	assert.Equal(t, "3 USD", resp.Dividends.String())
+	if assert.Len(t, resp.TopMovers, 2) {
+		assert.Equal(t, "MSFT", resp.TopMovers[0].Symbol)
+		assert.Equal(t, "-20", resp.TopMovers[0].ChangePct.String())
+		assert.Equal(t, "-50 USD", resp.TopMovers[0].ValueChange.String())
+		assert.Equal(t, "AAPL", resp.TopMovers[1].Symbol)
+		assert.Equal(t, "5", resp.TopMovers[1].ChangePct.String())
+	}
+	if assert.Len(t, resp.UpcomingEvents, 1) {
+		assert.Equal(t, "AAPL", resp.UpcomingEvents[0].Symbol)
+		assert.Equal(t, entities.CompanyEventEarnings, resp.UpcomingEvents[0].EventType)
+	}
+}
+
+// TestGenerateDigest_InvalidPeriod checks that Generate returns a validation error when the period is not valid
+func TestGenerateDigest_InvalidPeriod(t *testing.T) {
+	// Arrange
+	service := &digestService{}
+
+	// Act
+	_, err := service.Generate(context.Background(), "user-a", "portfolio-a", models.DigestReq{Period: "daily"})
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
+
+// TestGenerateDigest_PortfolioNotFound checks that Generate returns a non existent error when the portfolio does not exist
+func TestGenerateDigest_PortfolioNotFound(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &digestService{
+		portfolioRepository: portfolioRepositoryMock,
+	}
+	expectedError := "ID portfolio-a not found"
+
+	// Act
+	_, err := service.Generate(context.Background(), "user-a", "portfolio-a", models.DigestReq{Period: entities.DigestPeriodMonthly})
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGenerateDigest_OtherUser checks that Generate returns an unauthorized error when the portfolio belongs to another user
+func TestGenerateDigest_OtherUser(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-a").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &digestService{
+		portfolioRepository:   portfolioRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Generate(context.Background(), "user-b", "portfolio-a", models.DigestReq{Period: entities.DigestPeriodMonthly})
+
+	// Assert
+	assert.Equal(t, wrappers.NewUnauthorizedErr(fmt.Errorf("portfolio portfolio-a belongs to another user")), err)
+}
+
+// newDigestSendService returns a digest service sending the weekly digest of one portfolio with the summary enabled and no transactions
+func newDigestSendService(t *testing.T, repositoryMock *mocks.DigestRepository, mailerMock *mocks.Mailer) *digestService {
+	portfolio := &entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a", Name: "Growth", Summary: true}
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.Get), context.Background(), map[string]interface{}{"summary": true}, (*int)(nil), (*int)(nil)).Return([]interface{}{portfolio}, nil).Once()
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(portfolio, nil).Maybe()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "user-a").Return(&entities.User{UserID: "user-a", Firstname: "Jane", Email: "jane@example.com"}, nil).Maybe()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-a").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Maybe()
+
+	performanceServiceMock := mocks.NewPerformanceService(t)
+	performanceServiceMock.On(testutils.FunctionName(t, ports.PerformanceService.GetPortfolioPerformance), context.Background(), "user-a", "portfolio-a", mock.Anything).Return(models.PerformanceResp{
+		StartValue: entities.NewMoney(decimal.NewFromInt(1000), "EUR"),
+		EndValue:   entities.NewMoney(decimal.NewFromInt(990), "EUR"),
+	}, nil).Maybe()
+
+	cfg := config.Config{}
+	cfg.Digest.Periods = []string{entities.DigestPeriodWeekly}
+
+	return &digestService{
+		config:                cfg,
+		repository:            repositoryMock,
+		portfolioRepository:   portfolioRepositoryMock,
+		userRepository:        userRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		performanceService:    performanceServiceMock,
+		mailer:                mailerMock,
+	}
+}
+
+// TestSendDigests_Ok checks that Send mails the digest to the owner of the portfolio and records it as sent
+func TestSendDigests_Ok(t *testing.T) {
+	// Arrange
+	from, to := digestPeriod(entities.DigestPeriodWeekly, time.Now().UTC())
+
+	repositoryMock := mocks.NewDigestRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.DigestRepository.Exists), context.Background(), "portfolio-a", entities.DigestPeriodWeekly, to).Return(false, nil).Once()
+	repositoryMock.On(testutils.FunctionName(t, ports.DigestRepository.Create), context.Background(), mock.MatchedBy(func(digest entities.Digest) bool {
+		return digest.PortfolioID == "portfolio-a" && digest.Period == entities.DigestPeriodWeekly && digest.PeriodStart.Equal(from) && digest.PeriodEnd.Equal(to) && digest.SentTo == "jane@example.com"
+	})).Return("digest-a", nil).Once()
+
+	mailerMock := mocks.NewMailer(t)
+	mailerMock.On(testutils.FunctionName(t, ports.Mailer.Send), context.Background(), mock.MatchedBy(func(mail entities.Mail) bool {
+		return mail.To == "jane@example.com" && mail.Subject == "Your weekly summary of Growth" && strings.Contains(mail.Text, "Hello Jane") && strings.Contains(mail.Text, "-10 EUR (-1.00%)") && strings.Contains(mail.HTML, "<strong>Growth</strong>")
+	})).Return(nil).Once()
+
+	service := newDigestSendService(t, repositoryMock, mailerMock)
+
+	// Act
+	resp, err := service.Send(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.DigestSendResp{Portfolios: 1, Sent: 1}, resp)
+}
+
+// TestSendDigests_AlreadySent checks that Send does not mail a digest already sent for the period
+func TestSendDigests_AlreadySent(t *testing.T) {
+	// Arrange
+	repositoryMock := mocks.NewDigestRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.DigestRepository.Exists), context.Background(), "portfolio-a", entities.DigestPeriodWeekly, mock.Anything).Return(true, nil).Once()
+
+	service := newDigestSendService(t, repositoryMock, mocks.NewMailer(t))
+
+	// Act
+	resp, err := service.Send(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.DigestSendResp{Portfolios: 1}, resp)
+}
+
+// TestSendDigests_Undelivered checks that Send does not record a digest the mailer could not send, so it is sent again on the next run
+func TestSendDigests_Undelivered(t *testing.T) {
+	// Arrange
+	repositoryMock := mocks.NewDigestRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.DigestRepository.Exists), context.Background(), "portfolio-a", entities.DigestPeriodWeekly, mock.Anything).Return(false, nil).Once()
+
+	mailerMock := mocks.NewMailer(t)
+	mailerMock.On(testutils.FunctionName(t, ports.Mailer.Send), context.Background(), mock.Anything).Return(errors.New("connection refused")).Once()
+
+	service := newDigestSendService(t, repositoryMock, mailerMock)
+
+	// Act
+	resp, err := service.Send(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.DigestSendResp{Portfolios: 1, Undelivered: 1}, resp)
+}
+
+// TestDigestMail_Ok checks that digestMail renders the top movers and upcoming events and escapes the HTML body
+func TestDigestMail_Ok(t *testing.T) {
+	// Arrange
+	owner := entities.User{Firstname: "Jane", Email: "jane@example.com"}
+	digest := models.DigestResp{
+		PortfolioName: "Tech & Co",
+		Period:        entities.DigestPeriodMonthly,
+		From:          time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC),
+		To:            time.Date(2023, time.July, 31, 0, 0, 0, 0, time.UTC),
+		TopMovers: []models.DigestMoverResp{
+			{Symbol: "NVDA", StartPrice: entities.NewMoney(decimal.NewFromInt(400), "USD"), EndPrice: entities.NewMoney(decimal.NewFromInt(440), "USD"), ChangePct: decimal.NewFromInt(10), Quantity: decimal.NewFromInt(2), ValueChange: entities.NewMoney(decimal.NewFromInt(80), "USD")},
+		},
+		UpcomingEvents: []models.DigestEventResp{
+			{Symbol: "AAPL", EventType: entities.CompanyEventExDividend, EventDate: time.Date(2023, time.August, 11, 0, 0, 0, 0, time.UTC), Amount: entities.NewMoney(decimal.RequireFromString("0.24"), "USD")},
+		},
+	}
+
+	// Act
+	mail, err := digestMail(owner, digest)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "jane@example.com", mail.To)
+	assert.Equal(t, "Your monthly summary of Tech & Co", mail.Subject)
+	assert.Contains(t, mail.Text, "from 2023-07-01 to 2023-07-31")
+	assert.Contains(t, mail.Text, "NVDA: 400 USD -> 440 USD (10%), 80 USD on 2 units")
+	assert.Contains(t, mail.Text, "2023-08-11 AAPL ex dividend of 0.24 USD per share")
+	assert.Contains(t, mail.HTML, "Tech &amp; Co")
+	assert.Contains(t, mail.HTML, "<td>NVDA</td>")
+}
@@ -0,0 +1,483 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// dividendPatternMonths is how many months after its last payment a stock is still expected to keep paying dividends
+const dividendPatternMonths = 18
+
+// dividendIncomeService adapter of a dividend income service
+type dividendIncomeService struct {
+	config                config.Config
+	portfolioRepository   ports.PortfolioRepository
+	transactionRepository ports.TransactionRepository
+	stockRepository       ports.StockRepository
+	dividendRepository    ports.DividendRepository
+	userRepository        ports.UserRepository
+	priceSource           ports.PriceSource
+	converter             ports.CurrencyConverter
+}
+
+// NewDividendIncomeService creates a new dividend income service
+func NewDividendIncomeService(cfg config.Config, portfolioRepo ports.PortfolioRepository, transactionRepo ports.TransactionRepository, stockRepo ports.StockRepository, dividendRepo ports.DividendRepository, userRepo ports.UserRepository, priceSource ports.PriceSource, converter ports.CurrencyConverter) ports.DividendIncomeService {
+	return &dividendIncomeService{
+		config:                cfg,
+		portfolioRepository:   portfolioRepo,
+		transactionRepository: transactionRepo,
+		stockRepository:       stockRepo,
+		dividendRepository:    dividendRepo,
+		userRepository:        userRepo,
+		priceSource:           priceSource,
+		converter:             converter,
+	}
+}
+
+// GetCalendar returns the dividends paid to a portfolio and the ones expected to be paid within a date range, grouped by the month
+// of their pay date. The range defaults to the twelve months before and after today. The portfolio must belong to the user.
+func (s *dividendIncomeService) GetCalendar(ctx context.Context, userID, portfolioID string, req models.DividendCalendarReq) (resp models.DividendCalendarResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	portfolio, transactions, err := loadPortfolioTransactions(ctx, s.portfolioRepository, s.transactionRepository, portfolioID)
+	if err != nil {
+		return
+	}
+	if err = checkOwner(portfolio, userID); err != nil {
+		return
+	}
+	currency, err := baseCurrency(ctx, s.userRepository, portfolio)
+	if err != nil {
+		return
+	}
+
+	today := startOfDay(time.Now().UTC())
+	from := startOfDay(req.From)
+	if req.From.IsZero() {
+		from = today.AddDate(-1, 0, 0)
+	}
+	to := startOfDay(req.To)
+	if req.To.IsZero() {
+		to = today.AddDate(1, 0, 0)
+	}
+
+	payments, err := s.payments(ctx, transactions, currency, from, to, today)
+	if err != nil {
+		return
+	}
+	months, err := dividendMonths(payments, currency)
+	if err != nil {
+		return
+	}
+
+	resp = models.DividendCalendarResp{
+		PortfolioID: portfolioID,
+		Currency:    currency,
+		From:        from,
+		To:          to,
+		Total:       entities.NewMoney(decimal.Zero, currency),
+		Months:      months,
+	}
+	for _, m := range resp.Months {
+		if resp.Total, err = resp.Total.Add(m.Total); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// GetForecast returns the dividends a portfolio is expected to receive over the next twelve months. The declared dividends are
+// expected as recorded, and the payments of the twelve months up to the last recorded dividend of each held stock are expected
+// again every year at the amount of that last dividend, for the quantity held today. The portfolio must belong to the user.
+func (s *dividendIncomeService) GetForecast(ctx context.Context, userID, portfolioID string) (resp models.DividendForecastResp, err error) {
+	portfolio, transactions, err := loadPortfolioTransactions(ctx, s.portfolioRepository, s.transactionRepository, portfolioID)
+	if err != nil {
+		return
+	}
+	if err = checkOwner(portfolio, userID); err != nil {
+		return
+	}
+	currency, err := baseCurrency(ctx, s.userRepository, portfolio)
+	if err != nil {
+		return
+	}
+
+	today := startOfDay(time.Now().UTC())
+	from, to := today.Add(oneDay), today.AddDate(1, 0, 0)
+	payments, err := s.payments(ctx, transactions, currency, from, to, today)
+	if err != nil {
+		return
+	}
+	months, err := dividendMonths(payments, currency)
+	if err != nil {
+		return
+	}
+
+	resp = models.DividendForecastResp{
+		PortfolioID: portfolioID,
+		Currency:    currency,
+		From:        from,
+		To:          to,
+		Total:       entities.NewMoney(decimal.Zero, currency),
+		Months:      months,
+		Holdings:    []models.DividendForecastHoldingResp{},
+	}
+
+	holdings := map[string]int{}
+	for _, p := range payments {
+		amount := p.Amount
+		if currency != "" {
+			amount = p.BaseAmount
+		}
+		if resp.Total, err = resp.Total.Add(amount); err != nil {
+			return
+		}
+
+		i, ok := holdings[p.StockID]
+		if !ok {
+			i = len(resp.Holdings)
+			holdings[p.StockID] = i
+			resp.Holdings = append(resp.Holdings, models.DividendForecastHoldingResp{StockID: p.StockID, Symbol: p.Symbol, Total: entities.NewMoney(decimal.Zero, amount.Currency)})
+		}
+		holding := &resp.Holdings[i]
+		holding.Quantity = p.Shares
+		holding.Payments++
+		holding.DividendPerShare = p.DividendPerShare
+		if holding.Total, err = holding.Total.Add(amount); err != nil {
+			return
+		}
+	}
+	sort.SliceStable(resp.Holdings, func(i, j int) bool {
+		return resp.Holdings[i].Symbol < resp.Holdings[j].Symbol
+	})
+
+	return
+}
+
+// GetYield returns the yield on cost and the current yield of the stocks held by a portfolio, from the dividends with an ex-date in
+// the last twelve months. Dividends are converted to the currency of the cost and of the price at the latest rates, as are the totals
+// when the portfolio has a base currency. The portfolio must belong to the user.
+func (s *dividendIncomeService) GetYield(ctx context.Context, userID, portfolioID string) (resp models.DividendYieldResp, err error) {
+	portfolio, transactions, err := loadPortfolioTransactions(ctx, s.portfolioRepository, s.transactionRepository, portfolioID)
+	if err != nil {
+		return
+	}
+	if err = checkOwner(portfolio, userID); err != nil {
+		return
+	}
+	currency, err := baseCurrency(ctx, s.userRepository, portfolio)
+	if err != nil {
+		return
+	}
+
+	ledger, err := buildLedger(transactions, costBasisMethod(portfolio))
+	if err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	today := startOfDay(now)
+	resp = models.DividendYieldResp{
+		PortfolioID:  portfolioID,
+		Currency:     currency,
+		AnnualIncome: entities.NewMoney(decimal.Zero, currency),
+		CostBasis:    entities.NewMoney(decimal.Zero, currency),
+		MarketValue:  entities.NewMoney(decimal.Zero, currency),
+		Holdings:     []models.DividendYieldHoldingResp{},
+	}
+
+	pricedIncome := resp.AnnualIncome
+	for _, p := range ledger.positions {
+		if p.instrumentType != entities.EntityNameStock || !p.quantity.IsPositive() {
+			continue
+		}
+
+		dividends, divErr := s.dividends(ctx, p.instrumentID)
+		if divErr != nil {
+			err = divErr
+			return
+		}
+
+		holding := models.DividendYieldHoldingResp{
+			StockID:   p.instrumentID,
+			Symbol:    s.symbol(ctx, p.instrumentID),
+			Quantity:  p.quantity,
+			CostBasis: entities.NewMoney(p.costBasis, p.currency),
+		}
+		for _, d := range dividends {
+			if exDate := startOfDay(d.ExDate); exDate.After(today.AddDate(-1, 0, 0)) && !exDate.After(today) {
+				if holding.AnnualDividendPerShare, err = holding.AnnualDividendPerShare.Add(d.DividendPerShare); err != nil {
+					return
+				}
+			}
+		}
+		if holding.AnnualDividendPerShare.IsZero() && holding.AnnualDividendPerShare.Currency == "" {
+			holding.AnnualDividendPerShare = entities.NewMoney(holding.AnnualDividendPerShare.Amount, p.currency)
+		}
+		holding.AnnualIncome = holding.AnnualDividendPerShare.Mul(p.quantity)
+
+		incomeAtCost, convertErr := s.convert(ctx, holding.AnnualIncome, holding.CostBasis.Currency, now)
+		if convertErr != nil {
+			err = convertErr
+			return
+		}
+		holding.YieldOnCostPct = percentage(incomeAtCost.Amount, holding.CostBasis.Amount)
+
+		price, priceErr := s.priceSource.LatestPrice(ctx, p.instrumentID)
+		switch {
+		case priceErr == nil:
+			holding.Priced = true
+			holding.MarketValue = price.Mul(p.quantity)
+			incomeAtPrice, convertErr := s.convert(ctx, holding.AnnualIncome, holding.MarketValue.Currency, now)
+			if convertErr != nil {
+				err = convertErr
+				return
+			}
+			holding.CurrentYieldPct = percentage(incomeAtPrice.Amount, holding.MarketValue.Amount)
+		case errors.Is(priceErr, wrappers.NonExistentErr):
+			// the holding is reported without a current yield until a price is available
+		default:
+			err = priceErr
+			return
+		}
+
+		income, convertErr := s.convert(ctx, holding.AnnualIncome, currency, now)
+		if convertErr != nil {
+			err = convertErr
+			return
+		}
+		cost, convertErr := s.convert(ctx, holding.CostBasis, currency, now)
+		if convertErr != nil {
+			err = convertErr
+			return
+		}
+		if resp.AnnualIncome, err = resp.AnnualIncome.Add(income); err != nil {
+			return
+		}
+		if resp.CostBasis, err = resp.CostBasis.Add(cost); err != nil {
+			return
+		}
+		if holding.Priced {
+			value, convertErr := s.convert(ctx, holding.MarketValue, currency, now)
+			if convertErr != nil {
+				err = convertErr
+				return
+			}
+			if resp.MarketValue, err = resp.MarketValue.Add(value); err != nil {
+				return
+			}
+			if pricedIncome, err = pricedIncome.Add(income); err != nil {
+				return
+			}
+		}
+
+		resp.Holdings = append(resp.Holdings, holding)
+	}
+	resp.YieldOnCostPct = percentage(resp.AnnualIncome.Amount, resp.CostBasis.Amount)
+	resp.CurrentYieldPct = percentage(pricedIncome.Amount, resp.MarketValue.Amount)
+
+	return
+}
+
+// payments returns the dividends of the traded stocks paid to a portfolio within a date range, together with the declared dividends
+// and the ones expected from the payment pattern of the stocks held today, soonest first. Dividends are converted to the currency at
+// the rates of their pay dates, or at the latest rates when they are still to be paid.
+func (s *dividendIncomeService) payments(ctx context.Context, transactions []entities.Transaction, currency string, from, to, today time.Time) ([]models.DividendIncomeResp, error) {
+	var stockIDs []string
+	held := map[string]decimal.Decimal{}
+	for _, t := range transactions {
+		if t.StockID == "" {
+			continue
+		}
+		if _, ok := held[t.StockID]; !ok {
+			stockIDs = append(stockIDs, t.StockID)
+		}
+		held[t.StockID] = held[t.StockID].Add(t.Quantity)
+	}
+
+	payments := []models.DividendIncomeResp{}
+	for _, stockID := range stockIDs {
+		dividends, err := s.dividends(ctx, stockID)
+		if err != nil {
+			return nil, err
+		}
+		if len(dividends) == 0 {
+			continue
+		}
+		symbol := s.symbol(ctx, stockID)
+
+		for _, d := range dividends {
+			paid := startOfDay(d.PaidOn())
+			if paid.Before(from) || paid.After(to) {
+				continue
+			}
+			shares := sharesEntitled(transactions, d)
+			if !shares.IsPositive() {
+				continue
+			}
+			status := models.DividendStatusPaid
+			if paid.After(today) {
+				status = models.DividendStatusDeclared
+			}
+			payments = append(payments, dividendIncome(d, symbol, status, shares))
+		}
+
+		if !held[stockID].IsPositive() {
+			continue
+		}
+		for _, d := range dividendForecast(dividends, today, to) {
+			if paid := startOfDay(d.PayDate); !paid.Before(from) {
+				payments = append(payments, dividendIncome(d, symbol, models.DividendStatusForecast, held[stockID]))
+			}
+		}
+	}
+
+	for i := range payments {
+		if currency == "" {
+			break
+		}
+		date := payments[i].PayDate
+		if date.After(today) {
+			date = today
+		}
+		amount, err := s.convert(ctx, payments[i].Amount, currency, date)
+		if err != nil {
+			return nil, err
+		}
+		payments[i].BaseAmount = amount
+	}
+
+	sort.SliceStable(payments, func(i, j int) bool {
+		if !payments[i].PayDate.Equal(payments[j].PayDate) {
+			return payments[i].PayDate.Before(payments[j].PayDate)
+		}
+		return payments[i].Symbol < payments[j].Symbol
+	})
+	return payments, nil
+}
+
+// dividends returns the recorded dividends of a stock sorted by the day they are paid on
+func (s *dividendIncomeService) dividends(ctx context.Context, stockID string) ([]entities.Dividend, error) {
+	result, err := s.dividendRepository.Get(ctx, map[string]interface{}{"stockid": stockID}, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	dividends := make([]entities.Dividend, len(result))
+	for i, v := range result {
+		dividends[i] = *(v.(*entities.Dividend))
+	}
+	sort.SliceStable(dividends, func(i, j int) bool {
+		return dividends[i].PaidOn().Before(dividends[j].PaidOn())
+	})
+	return dividends, nil
+}
+
+// symbol returns the ticker symbol of a stock, or its ID when it has none
+func (s *dividendIncomeService) symbol(ctx context.Context, stockID string) string {
+	stock, err := s.stockRepository.GetByID(ctx, stockID)
+	if err != nil || stock.(*entities.Stock).TickerSymbol == "" {
+		return stockID
+	}
+	return stock.(*entities.Stock).TickerSymbol
+}
+
+// convert returns an amount in a currency at the rate of a date, leaving it as it is when no currency is given
+func (s *dividendIncomeService) convert(ctx context.Context, amount entities.Money, currency string, date time.Time) (entities.Money, error) {
+	switch {
+	case currency == "" || amount.Currency == currency:
+		return amount, nil
+	case amount.IsZero():
+		return entities.NewMoney(decimal.Zero, currency), nil
+	}
+	return s.converter.Convert(ctx, amount, currency, date)
+}
+
+// dividendForecast returns the dividends of a stock expected after today and up to a day. The payments of the twelve months up to
+// the last recorded dividend are expected again every year at the amount of that dividend, unless it was paid so long ago that the
+// stock is no longer expected to pay.
+func dividendForecast(dividends []entities.Dividend, today, to time.Time) []entities.Dividend {
+	if len(dividends) == 0 {
+		return nil
+	}
+	latest := dividends[len(dividends)-1]
+	last := startOfDay(latest.PaidOn())
+	if last.Before(today.AddDate(0, -dividendPatternMonths, 0)) {
+		return nil
+	}
+
+	var forecast []entities.Dividend
+	for _, d := range dividends {
+		paid := startOfDay(d.PaidOn())
+		if !paid.After(last.AddDate(-1, 0, 0)) {
+			continue
+		}
+		for years := 1; !paid.AddDate(years, 0, 0).After(to); years++ {
+			next := paid.AddDate(years, 0, 0)
+			if !next.After(today) {
+				continue
+			}
+			forecast = append(forecast, entities.Dividend{
+				StockID:          d.StockID,
+				DividendPerShare: latest.DividendPerShare,
+				ExDate:           startOfDay(d.ExDate).AddDate(years, 0, 0),
+				PayDate:          next,
+			})
+		}
+	}
+	return forecast
+}
+
+// dividendIncome returns the income of a dividend paid on a number of shares
+func dividendIncome(d entities.Dividend, symbol, status string, shares decimal.Decimal) models.DividendIncomeResp {
+	return models.DividendIncomeResp{
+		DividendID:       d.DividendID,
+		StockID:          d.StockID,
+		Symbol:           symbol,
+		Status:           status,
+		ExDate:           d.ExDate,
+		PayDate:          startOfDay(d.PaidOn()),
+		Shares:           shares,
+		DividendPerShare: d.DividendPerShare,
+		Amount:           d.DividendPerShare.Mul(shares),
+	}
+}
+
+// dividendMonths groups chronologically sorted dividends by the month of their pay date, totalling them in the currency or, without
+// one, in the currency they are paid in, which must then be the same for all of them
+func dividendMonths(payments []models.DividendIncomeResp, currency string) ([]models.DividendMonthResp, error) {
+	months := []models.DividendMonthResp{}
+	for _, p := range payments {
+		month := periodStart(p.PayDate, models.PriceIntervalMonth)
+		if n := len(months); n == 0 || !months[n-1].Month.Equal(month) {
+			months = append(months, models.DividendMonthResp{Month: month, Total: entities.NewMoney(decimal.Zero, currency)})
+		}
+		m := &months[len(months)-1]
+		amount := p.Amount
+		if currency != "" {
+			amount = p.BaseAmount
+		}
+		total, err := m.Total.Add(amount)
+		if err != nil {
+			return nil, err
+		}
+		m.Total = total
+		m.Payments = append(m.Payments, p)
+	}
+	return months, nil
+}
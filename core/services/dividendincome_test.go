@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// quarterlyDividends returns the quarterly dividends of a stock paid from fourteen months ago, the last one declared and paid next month
+func quarterlyDividends(today time.Time) []interface{} {
+	var dividends []interface{}
+	for _, months := range []int{-14, -11, -8, -5, -2} {
+		payDate := today.AddDate(0, months, 0)
+		dividends = append(dividends, &entities.Dividend{StockID: "stock-a", DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.5"), "USD"), ExDate: payDate.AddDate(0, 0, -7), PayDate: payDate})
+	}
+	payDate := today.AddDate(0, 1, 0)
+	return append(dividends, &entities.Dividend{DividendID: "declared", StockID: "stock-a", DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.6"), "USD"), ExDate: payDate.AddDate(0, 0, -7), PayDate: payDate})
+}
+
+// newDividendIncomeTestService returns a dividend income service over a portfolio holding 10 shares bought two years ago and 10 more six months ago
+func newDividendIncomeTestService(t *testing.T, portfolio entities.Portfolio, today time.Time) *dividendIncomeService {
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolio.PortfolioID).Return(&portfolio, nil).Once()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	if portfolio.BaseCurrency == "" {
+		userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), portfolio.Userid).Return(&entities.User{UserID: portfolio.Userid}, nil).Once()
+	}
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolio.PortfolioID).Return([]interface{}{
+		&entities.Transaction{TransactionID: "1", StockID: "stock-a", Quantity: decimal.NewFromInt(10), TransactionPrice: entities.NewMoney(decimal.NewFromInt(100), "USD"), TransactionDate: today.AddDate(-2, 0, 0)},
+		&entities.Transaction{TransactionID: "2", StockID: "stock-a", Quantity: decimal.NewFromInt(10), TransactionPrice: entities.NewMoney(decimal.NewFromInt(120), "USD"), TransactionDate: today.AddDate(0, -6, 0)},
+	}, nil).Once()
+
+	dividendRepositoryMock := mocks.NewDividendRepository(t)
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-a"}, (*int)(nil), (*int)(nil)).Return(quarterlyDividends(today), nil).Once()
+
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-a").Return(&entities.Stock{StockID: "stock-a", TickerSymbol: "KO"}, nil).Once()
+
+	return &dividendIncomeService{
+		config:                config.Config{},
+		portfolioRepository:   portfolioRepositoryMock,
+		userRepository:        userRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		stockRepository:       stockRepositoryMock,
+		dividendRepository:    dividendRepositoryMock,
+	}
+}
+
+// TestNewDividendIncomeService_Ok checks that NewDividendIncomeService creates a new dividendIncomeService struct
+func TestNewDividendIncomeService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+
+	// Act
+	service := NewDividendIncomeService(cfg, mocks.NewPortfolioRepository(t), mocks.NewTransactionRepository(t), mocks.NewStockRepository(t), mocks.NewDividendRepository(t), mocks.NewUserRepository(t), mocks.NewPriceSource(t), mocks.NewCurrencyConverter(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestGetDividendCalendar_Ok checks that GetCalendar lists the paid, declared and expected dividends of the year before and after today
+func TestGetDividendCalendar_Ok(t *testing.T) {
+	// Arrange
+	today := startOfDay(time.Now().UTC())
+	service := newDividendIncomeTestService(t, entities.Portfolio{PortfolioID: "portfolio-id", Userid: "user-a"}, today)
+
+	// Act
+	resp, err := service.GetCalendar(context.Background(), "user-a", "portfolio-id", models.DividendCalendarReq{})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, today.AddDate(-1, 0, 0), resp.From)
+	assert.Equal(t, today.AddDate(1, 0, 0), resp.To)
+	assert.Equal(t, "78 USD", resp.Total.String())
+
+	var statuses []string
+	var amounts []string
+	for _, m := range resp.Months {
+		for _, p := range m.Payments {
+			statuses = append(statuses, p.Status)
+			amounts = append(amounts, p.Amount.String())
+		}
+	}
+	assert.Equal(t, []string{models.DividendStatusPaid, models.DividendStatusPaid, models.DividendStatusPaid, models.DividendStatusPaid, models.DividendStatusDeclared, models.DividendStatusForecast, models.DividendStatusForecast, models.DividendStatusForecast}, statuses)
+	assert.Equal(t, []string{"5 USD", "5 USD", "10 USD", "10 USD", "12 USD", "12 USD", "12 USD", "12 USD"}, amounts)
+	assert.Equal(t, "KO", resp.Months[0].Payments[0].Symbol)
+}
+
+// TestGetDividendCalendar_InvalidRequest checks that GetCalendar returns an error when the range is not valid
+func TestGetDividendCalendar_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := &dividendIncomeService{
+		config: config.Config{},
+	}
+	req := models.DividendCalendarReq{From: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	expectedError := "to cannot be before from"
+
+	// Act
+	_, err := service.GetCalendar(context.Background(), "user-a", "portfolio-id", req)
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetDividendForecast_Ok checks that GetForecast expects the declared dividend and the payments of the last year again at the latest amount
+func TestGetDividendForecast_Ok(t *testing.T) {
+	// Arrange
+	today := startOfDay(time.Now().UTC())
+	service := newDividendIncomeTestService(t, entities.Portfolio{PortfolioID: "portfolio-id", Userid: "user-a"}, today)
+
+	// Act
+	resp, err := service.GetForecast(context.Background(), "user-a", "portfolio-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, today.Add(oneDay), resp.From)
+	assert.Equal(t, today.AddDate(1, 0, 0), resp.To)
+	assert.Equal(t, "48 USD", resp.Total.String())
+	assert.Len(t, resp.Holdings, 1)
+	assert.Equal(t, "KO", resp.Holdings[0].Symbol)
+	assert.Equal(t, 4, resp.Holdings[0].Payments)
+	assert.Equal(t, "20", resp.Holdings[0].Quantity.String())
+	assert.Equal(t, "0.6 USD", resp.Holdings[0].DividendPerShare.String())
+	assert.Equal(t, "48 USD", resp.Holdings[0].Total.String())
+}
+
+// TestGetDividendYield_Ok checks that GetYield compares the dividends of the last year with the cost and the value of the holdings
+func TestGetDividendYield_Ok(t *testing.T) {
+	// Arrange
+	today := startOfDay(time.Now().UTC())
+	service := newDividendIncomeTestService(t, entities.Portfolio{PortfolioID: "portfolio-id", Userid: "user-a", BaseCurrency: "CZK"}, today)
+
+	priceSourceMock := mocks.NewPriceSource(t)
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.LatestPrice), context.Background(), "stock-a").Return(entities.NewMoney(decimal.NewFromInt(125), "USD"), nil).Once()
+	service.priceSource = priceSourceMock
+
+	converterMock := mocks.NewCurrencyConverter(t)
+	converterMock.On(testutils.FunctionName(t, ports.CurrencyConverter.Convert), context.Background(), mock.AnythingOfType("entities.Money"), "CZK", mock.AnythingOfType("time.Time")).Return(func(ctx context.Context, amount entities.Money, currency string, date time.Time) (entities.Money, error) {
+		return entities.NewMoney(amount.Amount.Mul(decimal.NewFromInt(20)), currency), nil
+	}).Times(3)
+	service.converter = converterMock
+
+	// Act
+	resp, err := service.GetYield(context.Background(), "user-a", "portfolio-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp.Holdings, 1)
+	holding := resp.Holdings[0]
+	assert.Equal(t, "2 USD", holding.AnnualDividendPerShare.String())
+	assert.Equal(t, "40 USD", holding.AnnualIncome.String())
+	assert.Equal(t, "2200 USD", holding.CostBasis.String())
+	assert.Equal(t, "2500 USD", holding.MarketValue.String())
+	assert.InDelta(t, 1.8182, holding.YieldOnCostPct, 1e-4)
+	assert.InDelta(t, 1.6, holding.CurrentYieldPct, 1e-9)
+	assert.Equal(t, "800 CZK", resp.AnnualIncome.String())
+	assert.Equal(t, "44000 CZK", resp.CostBasis.String())
+	assert.Equal(t, "50000 CZK", resp.MarketValue.String())
+	assert.InDelta(t, 1.8182, resp.YieldOnCostPct, 1e-4)
+	assert.InDelta(t, 1.6, resp.CurrentYieldPct, 1e-9)
+}
+
+// TestGetDividendYield_OtherUser checks that GetYield returns an unauthorized error when the portfolio belongs to another user
+func TestGetDividendYield_OtherUser(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-id").Return(&entities.Portfolio{PortfolioID: "portfolio-id", Userid: "user-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-id").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &dividendIncomeService{
+		config:                config.Config{},
+		portfolioRepository:   portfolioRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+	}
+
+	// Act
+	_, err := service.GetYield(context.Background(), "user-b", "portfolio-id")
+
+	// Assert
+	assert.Equal(t, wrappers.NewUnauthorizedErr(fmt.Errorf("portfolio portfolio-id belongs to another user")), err)
+}
+
+// TestDividendForecast_Stale checks that dividendForecast expects nothing from a stock whose last dividend was paid long ago
+func TestDividendForecast_Stale(t *testing.T) {
+	// Arrange
+	today := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	dividends := []entities.Dividend{
+		{StockID: "stock-a", DividendPerShare: entities.NewMoney(decimal.NewFromInt(1), "USD"), ExDate: time.Date(2022, time.May, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	// Act
+	forecast := dividendForecast(dividends, today, today.AddDate(1, 0, 0))
+
+	// Assert
+	assert.Empty(t, forecast)
+}
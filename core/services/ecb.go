@@ -0,0 +1,291 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+const (
+	// ecbBaseCurrency is the currency the ECB reference rates are quoted against
+	ecbBaseCurrency = "EUR"
+	// ecbSource is the source of the reference rates published by the ECB
+	ecbSource = "ecb"
+	// ecbCrossSource is the source of the rates crossed through the euro from the ECB reference rates
+	ecbCrossSource = "ecb-cross"
+	// ecbMissingRate marks a currency without a rate on a day in the CSV files
+	ecbMissingRate = "N/A"
+)
+
+// ecbImportService adapter of an ECB reference rates import service
+type ecbImportService struct {
+	config             config.Config
+	fxRateService      ports.FxRateService
+	currencyRepository ports.CurrencyRepository
+}
+
+// NewECBImportService creates a new ECB reference rates import service
+func NewECBImportService(cfg config.Config, fxRateService ports.FxRateService, currencyRepo ports.CurrencyRepository) ports.ECBImportService {
+	return &ecbImportService{
+		config:             cfg,
+		fxRateService:      fxRateService,
+		currencyRepository: currencyRepo,
+	}
+}
+
+// ecbDay holds the reference rates published on a day, as units of each currency per euro
+type ecbDay struct {
+	date  time.Time
+	rates map[string]decimal.Decimal
+}
+
+// Import loads an ECB eurofxref-hist XML or CSV file into the FX rate store. Every reference rate is stored against the euro
+// and the rates between the currencies of the currency store are crossed through the euro. Rates are stored in batches and
+// replace the ones already stored for the same pair and date, so a file can be imported again after a failure.
+func (s *ecbImportService) Import(ctx context.Context, file io.Reader) (resp models.ECBImportResp, err error) {
+	days, err := parseECB(file)
+	if err != nil {
+		return
+	}
+	if len(days) == 0 {
+		err = wrappers.NewValidationErr(fmt.Errorf("file contains no reference rates"))
+		return
+	}
+
+	tracked, err := s.trackedCurrencies(ctx)
+	if err != nil {
+		return
+	}
+
+	var batch []models.CreateFxRateReq
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		_, err := s.fxRateService.CreateMany(ctx, batch)
+		batch = batch[:0]
+		return err
+	}
+
+	for _, day := range days {
+		codes := make([]string, 0, len(day.rates))
+		for code := range day.rates {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+
+		for _, code := range codes {
+			batch = append(batch, models.CreateFxRateReq{BaseCurrency: ecbBaseCurrency, QuoteCurrency: code, RateDate: day.date, Rate: day.rates[code], Source: ecbSource})
+			resp.Rates++
+		}
+
+		for _, from := range tracked {
+			fromRate, ok := day.rates[from]
+			if !ok {
+				continue
+			}
+			for _, to := range tracked {
+				toRate, ok := day.rates[to]
+				if !ok || from == to {
+					continue
+				}
+				batch = append(batch, models.CreateFxRateReq{BaseCurrency: from, QuoteCurrency: to, RateDate: day.date, Rate: toRate.DivRound(fromRate, fxRatePlaces), Source: ecbCrossSource})
+				resp.CrossRates++
+			}
+		}
+
+		if len(batch) >= importBatchSize {
+			if err = flush(); err != nil {
+				return
+			}
+		}
+	}
+	if err = flush(); err != nil {
+		return
+	}
+
+	resp.From = days[0].date
+	resp.To = days[len(days)-1].date
+	resp.Days = len(days)
+	return
+}
+
+// trackedCurrencies returns the sorted codes of the currencies in the currency store other than the euro
+func (s *ecbImportService) trackedCurrencies(ctx context.Context) ([]string, error) {
+	result, err := s.currencyRepository.Get(ctx, map[string]interface{}{}, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var codes []string
+	for _, v := range result {
+		code := strings.ToUpper(strings.TrimSpace(v.(*entities.Currency).Code))
+		if code == "" || code == ecbBaseCurrency || seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	return codes, nil
+}
+
+// parseECB parses an ECB eurofxref-hist file, telling the XML format from the CSV one by its first character.
+// The days are returned in chronological order.
+func parseECB(file io.Reader) ([]ecbDay, error) {
+	r := bufio.NewReader(file)
+	for {
+		c, _, err := r.ReadRune()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if !unicode.IsSpace(c) && c != '\uFEFF' {
+			r.UnreadRune()
+			break
+		}
+	}
+
+	peek, err := r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+
+	var days []ecbDay
+	if peek[0] == '<' {
+		days, err = parseECBXML(r)
+	} else {
+		days, err = parseECBCSV(r)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(days, func(i, j int) bool {
+		return days[i].date.Before(days[j].date)
+	})
+	return days, nil
+}
+
+// ecbEnvelope is the layout of the ECB XML files, where the rates of each day are nested in a cube of that day
+type ecbEnvelope struct {
+	Cube struct {
+		Days []struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string `xml:"currency,attr"`
+				Rate     string `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+// parseECBXML parses the rates of an ECB XML file
+func parseECBXML(r io.Reader) ([]ecbDay, error) {
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, wrappers.NewValidationErr(fmt.Errorf("invalid ECB XML file: %w", err))
+	}
+
+	var days []ecbDay
+	for _, d := range envelope.Cube.Days {
+		day, err := newECBDay(d.Time)
+		if err != nil {
+			return nil, err
+		}
+		for _, rate := range d.Rates {
+			if err := day.add(rate.Currency, rate.Rate); err != nil {
+				return nil, err
+			}
+		}
+		days = append(days, day)
+	}
+
+	return days, nil
+}
+
+// parseECBCSV parses the rates of an ECB CSV file, whose header holds the currency codes and each row the rates of a day
+func parseECBCSV(r io.Reader) ([]ecbDay, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, wrappers.NewValidationErr(fmt.Errorf("invalid ECB CSV file: %w", err))
+	}
+	if len(header) < 2 || !strings.EqualFold(strings.TrimSpace(header[0]), "date") {
+		return nil, wrappers.NewValidationErr(fmt.Errorf("invalid ECB CSV file: the first column must be Date"))
+	}
+
+	var days []ecbDay
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, wrappers.NewValidationErr(fmt.Errorf("invalid ECB CSV file: %w", err))
+		}
+
+		day, err := newECBDay(record[0])
+		if err != nil {
+			return nil, err
+		}
+		for i := 1; i < len(record) && i < len(header); i++ {
+			if err := day.add(header[i], record[i]); err != nil {
+				return nil, err
+			}
+		}
+		days = append(days, day)
+	}
+
+	return days, nil
+}
+
+// newECBDay returns an empty day for a date in YYYY-MM-DD format
+func newECBDay(value string) (ecbDay, error) {
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(value))
+	if err != nil {
+		return ecbDay{}, wrappers.NewValidationErr(fmt.Errorf("invalid ECB rate date %q", value))
+	}
+	return ecbDay{date: date, rates: map[string]decimal.Decimal{}}, nil
+}
+
+// add adds the rate of a currency to the day, skipping the empty and missing ones
+func (d ecbDay) add(currency, value string) error {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	value = strings.TrimSpace(value)
+	if currency == "" || value == "" || value == ecbMissingRate {
+		return nil
+	}
+
+	rate, err := decimal.NewFromString(value)
+	if err != nil || !rate.IsPositive() {
+		return wrappers.NewValidationErr(fmt.Errorf("invalid ECB %s rate %q on %s", currency, value, d.date.Format("2006-01-02")))
+	}
+	d.rates[currency] = rate
+	return nil
+}
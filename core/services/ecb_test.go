@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+const ecbXML = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender>
+		<gesmes:name>European Central Bank</gesmes:name>
+	</gesmes:Sender>
+	<Cube>
+		<Cube time="2023-08-04">
+			<Cube currency="USD" rate="1.1"/>
+			<Cube currency="CZK" rate="24.2"/>
+		</Cube>
+		<Cube time="2023-08-03">
+			<Cube currency="USD" rate="1.0946"/>
+			<Cube currency="CZK" rate="24.105"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+const ecbCSV = `Date,USD,CZK,CYP,
+2023-08-04,1.1,24.2,N/A,
+2023-08-03,1.0946,24.105,N/A,
+`
+
+// TestNewECBImportService_Ok checks that NewECBImportService creates a new ecbImportService struct
+func TestNewECBImportService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+
+	// Act
+	service := NewECBImportService(cfg, mocks.NewFxRateService(t), mocks.NewCurrencyRepository(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestImportECB_XML checks that Import stores the euro rates of an XML file and crosses the rates of the registered currencies
+func TestImportECB_XML(t *testing.T) {
+	// Arrange
+	currencyRepositoryMock := mocks.NewCurrencyRepository(t)
+	currencyRepositoryMock.On(testutils.FunctionName(t, ports.CurrencyRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Currency{Code: "EUR"},
+		&entities.Currency{Code: "USD"},
+		&entities.Currency{Code: "CZK"},
+	}, nil).Once()
+
+	var stored []models.CreateFxRateReq
+	fxRateServiceMock := mocks.NewFxRateService(t)
+	fxRateServiceMock.On(testutils.FunctionName(t, ports.FxRateService.CreateMany), context.Background(), mock.AnythingOfType("[]models.CreateFxRateReq")).Run(func(args mock.Arguments) {
+		stored = append(stored, args.Get(1).([]models.CreateFxRateReq)...)
+	}).Return(models.MultiCreationResp{}, nil).Once()
+
+	service := &ecbImportService{
+		config:             config.Config{},
+		fxRateService:      fxRateServiceMock,
+		currencyRepository: currencyRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), strings.NewReader(ecbXML))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.ECBImportResp{From: time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC), To: time.Date(2023, time.August, 4, 0, 0, 0, 0, time.UTC), Days: 2, Rates: 4, CrossRates: 4}, resp)
+	assert.Len(t, stored, 8)
+	assert.Equal(t, "EUR", stored[0].BaseCurrency)
+	assert.Equal(t, "CZK", stored[0].QuoteCurrency)
+	assert.Equal(t, "24.105", stored[0].Rate.String())
+	assert.Equal(t, ecbSource, stored[0].Source)
+	assert.Equal(t, "CZK", stored[2].BaseCurrency)
+	assert.Equal(t, "USD", stored[2].QuoteCurrency)
+	assert.Equal(t, "0.045409666", stored[2].Rate.String())
+	assert.Equal(t, ecbCrossSource, stored[2].Source)
+}
+
+// TestImportECB_CSV checks that Import stores the rates of a CSV file, skipping the missing ones
+func TestImportECB_CSV(t *testing.T) {
+	// Arrange
+	currencyRepositoryMock := mocks.NewCurrencyRepository(t)
+	currencyRepositoryMock.On(testutils.FunctionName(t, ports.CurrencyRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	fxRateServiceMock := mocks.NewFxRateService(t)
+	fxRateServiceMock.On(testutils.FunctionName(t, ports.FxRateService.CreateMany), context.Background(), mock.MatchedBy(func(rates []models.CreateFxRateReq) bool {
+		return len(rates) == 4 && rates[3].QuoteCurrency == "USD" && rates[3].Rate.String() == "1.1"
+	})).Return(models.MultiCreationResp{}, nil).Once()
+
+	service := &ecbImportService{
+		config:             config.Config{},
+		fxRateService:      fxRateServiceMock,
+		currencyRepository: currencyRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), strings.NewReader("\uFEFF"+ecbCSV))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 2, resp.Days)
+	assert.Equal(t, 4, resp.Rates)
+	assert.Equal(t, 0, resp.CrossRates)
+}
+
+// TestImportECB_InvalidRate checks that Import returns a validation error when a rate cannot be parsed
+func TestImportECB_InvalidRate(t *testing.T) {
+	// Arrange
+	service := &ecbImportService{
+		config: config.Config{},
+	}
+	file := "Date,USD,\n2023-08-04,abc,\n"
+	expectedError := `invalid ECB USD rate "abc" on 2023-08-04`
+
+	// Act
+	_, err := service.Import(context.Background(), strings.NewReader(file))
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestImportECB_Empty checks that Import returns a validation error when the file has no rates
+func TestImportECB_Empty(t *testing.T) {
+	// Arrange
+	service := &ecbImportService{
+		config: config.Config{},
+	}
+	expectedError := "file contains no reference rates"
+
+	// Act
+	_, err := service.Import(context.Background(), strings.NewReader("  \n"))
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestImportECB_CreateManyError checks that Import returns an error when the CreateMany function from the fx rate service fails
+func TestImportECB_CreateManyError(t *testing.T) {
+	// Arrange
+	expectedError := "service-error"
+
+	currencyRepositoryMock := mocks.NewCurrencyRepository(t)
+	currencyRepositoryMock.On(testutils.FunctionName(t, ports.CurrencyRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	fxRateServiceMock := mocks.NewFxRateService(t)
+	fxRateServiceMock.On(testutils.FunctionName(t, ports.FxRateService.CreateMany), context.Background(), mock.AnythingOfType("[]models.CreateFxRateReq")).Return(models.MultiCreationResp{}, fmt.Errorf(expectedError)).Once()
+
+	service := &ecbImportService{
+		config:             config.Config{},
+		fxRateService:      fxRateServiceMock,
+		currencyRepository: currencyRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Import(context.Background(), strings.NewReader(ecbCSV))
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
@@ -0,0 +1,1033 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/xuri/excelize/v2"
+)
+
+// restoreChunkSize is the number of transactions of an archive written at once by a restore
+const restoreChunkSize = 500
+
+// exportService adapter of a portfolio export service
+type exportService struct {
+	config                   config.Config
+	userRepository           ports.UserRepository
+	portfolioRepository      ports.PortfolioRepository
+	holdingRepository        ports.HoldingRepository
+	brokerRepository         ports.BrokerRepository
+	transactionRepository    ports.TransactionRepository
+	stockRepository          ports.StockRepository
+	cryptoCurrencyRepository ports.CryptoCurrencyRepository
+	dividendRepository       ports.DividendRepository
+	lotRepository            ports.LotRepository
+	priceRepository          ports.PriceRepository
+	ppCodec                  ports.PortfolioPerformanceCodec
+}
+
+// NewExportService creates a new portfolio export service, reading and writing the Portfolio Performance files with ppCodec
+func NewExportService(cfg config.Config, userRepo ports.UserRepository, portfolioRepo ports.PortfolioRepository, holdingRepo ports.HoldingRepository, brokerRepo ports.BrokerRepository, transactionRepo ports.TransactionRepository, stockRepo ports.StockRepository, cryptoCurrencyRepo ports.CryptoCurrencyRepository, dividendRepo ports.DividendRepository, lotRepo ports.LotRepository, priceRepo ports.PriceRepository, ppCodec ports.PortfolioPerformanceCodec) ports.ExportService {
+	return &exportService{
+		config:                   cfg,
+		userRepository:           userRepo,
+		portfolioRepository:      portfolioRepo,
+		holdingRepository:        holdingRepo,
+		brokerRepository:         brokerRepo,
+		transactionRepository:    transactionRepo,
+		stockRepository:          stockRepo,
+		cryptoCurrencyRepository: cryptoCurrencyRepo,
+		dividendRepository:       dividendRepo,
+		lotRepository:            lotRepo,
+		priceRepository:          priceRepo,
+		ppCodec:                  ppCodec,
+	}
+}
+
+// exportTable is a type of the data of a portfolio export. Its items are streamed from their repository as archive items together
+// with the values of their row in a CSV file or a sheet of the workbook.
+type exportTable struct {
+	name   string
+	sheet  string
+	header []string
+	stream func(ctx context.Context, portfolioID string, lookup *exportLookup, fn func(item interface{}, row []interface{}) error) error
+}
+
+// Export writes the holdings, transactions, dividends and realized gains of a portfolio. The CSV format writes the single type
+// requested, the XLSX format a workbook with a sheet per type, the JSON format an archive of the whole portfolio that can be
+// restored into another account and the PP format a Portfolio Performance XML file. Items are streamed from the repositories, so a portfolio is never loaded fully in memory.
+// The portfolio must belong to the user.
+func (s *exportService) Export(ctx context.Context, userID, portfolioID string, req models.ExportReq, w io.Writer) (err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	portfolio, err := userPortfolio(ctx, s.portfolioRepository, userID, portfolioID)
+	if err != nil {
+		return
+	}
+	lookup := newExportLookup(s.brokerRepository, s.stockRepository, s.cryptoCurrencyRepository)
+
+	switch req.Format {
+	case models.ExportFormatCSV:
+		for _, table := range s.tables() {
+			if table.name == req.Type {
+				err = writeCSV(ctx, portfolioID, table, lookup, w)
+			}
+		}
+	case models.ExportFormatXLSX:
+		err = writeXLSX(ctx, portfolioID, s.tables(), lookup, w)
+	case models.ExportFormatJSON:
+		err = writeArchive(ctx, portfolio, s.tables(), lookup, w)
+	case models.ExportFormatPP:
+		err = s.writePortfolioPerformance(ctx, portfolio, lookup, w)
+	}
+	return
+}
+
+// Restore creates a portfolio for a user from a JSON archive written by the export. The archive is read twice, first to validate it
+// and then to write it, so an invalid archive creates nothing. Holdings keep their broker, found by its ID or else by its slug, and
+// transactions their stock or cryptocurrency, found by its ID, ISIN or symbol and created when none is found. Dividends already
+// recorded for their stock on their pay day are skipped, and realized gains are rebuilt from the restored transactions rather than
+// read from the archive. Everything created is deleted again when the restore fails. A Portfolio Performance file is converted into
+// an archive first, so it is restored the same way.
+func (s *exportService) Restore(ctx context.Context, userID string, req models.RestoreReq, archive io.ReadSeeker) (resp models.ArchiveRestoreResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if _, err = s.userRepository.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", userID))
+		}
+		return
+	}
+
+	skippedTransfers := 0
+	if req.Format == models.ExportFormatPP {
+		if archive, skippedTransfers, err = s.portfolioPerformanceArchive(ctx, req, archive); err != nil {
+			return
+		}
+	}
+
+	brokers := map[string]string{}
+	if err = s.validateArchive(ctx, archive, brokers); err != nil {
+		return
+	}
+	if _, err = archive.Seek(0, io.SeekStart); err != nil {
+		return
+	}
+
+	restore := &archiveRestore{service: s, userID: userID, brokers: brokers, holdings: map[string]string{}, stocks: map[string]string{}, cryptoCurrencies: map[string]string{}, paid: map[string]map[string]bool{}}
+	if err = restore.run(ctx, archive); err != nil {
+		restore.undo(ctx)
+		return
+	}
+
+	resp = restore.resp
+	resp.SkippedTransfers = skippedTransfers
+	return
+}
+
+// tables returns the types of the data of a portfolio export in the order they are written
+func (s *exportService) tables() []exportTable {
+	return []exportTable{
+		{
+			name:   models.ExportTypeHoldings,
+			sheet:  "Holdings",
+			header: []string{"holdingid", "broker", "name", "description", "slug", "trade_date", "trade_type", "quantity", "share_price", "share_price_currency", "exchange_rate", "exchange_currencyid", "brokerage_unit_price", "brokerage_unit_price_currency", "extid"},
+			stream: s.streamHoldings,
+		},
+		{
+			name:   models.ExportTypeTransactions,
+			sheet:  "Transactions",
+			header: []string{"transactionid", "holdingid", "transaction_date", "transaction_type", "symbol", "isin", "name", "quantity", "price", "currency", "extid"},
+			stream: s.streamTransactions,
+		},
+		{
+			name:   models.ExportTypeDividends,
+			sheet:  "Dividends",
+			header: []string{"dividendid", "symbol", "isin", "ex_date", "record_date", "pay_date", "dividend_per_share", "currency", "shares", "amount"},
+			stream: s.streamDividends,
+		},
+		{
+			name:   models.ExportTypeRealizedGains,
+			sheet:  "Realized gains",
+			header: []string{"sell_transactionid", "buy_transactionid", "symbol", "instrument_type", "method", "acquired_at", "sold_at", "quantity", "cost_basis", "proceeds", "gain", "currency"},
+			stream: s.streamRealizedGains,
+		},
+	}
+}
+
+func (s *exportService) streamHoldings(ctx context.Context, portfolioID string, lookup *exportLookup, fn func(item interface{}, row []interface{}) error) error {
+	return s.holdingRepository.StreamByPortfolioID(ctx, portfolioID, func(h entities.Holding) error {
+		broker, err := lookup.broker(ctx, h.BrokerID)
+		if err != nil {
+			return err
+		}
+
+		item := models.ArchiveHolding{
+			HoldingID:          h.HoldingID,
+			BrokerID:           h.BrokerID,
+			Broker:             broker.Slug,
+			Extid:              h.Extid,
+			Name:               h.Name,
+			Description:        h.Description,
+			Slug:               h.Slug,
+			TradeDate:          h.TradeDate,
+			TradeType:          h.TradeType,
+			Quantity:           h.Quantity,
+			SharePrice:         h.SharePrice,
+			ExchangeRate:       h.ExchangeRate,
+			ExchangeCurrencyID: h.ExchangeCurrencyID,
+			BrokerageUnitPrice: h.BrokerageUnitPrice,
+		}
+		return fn(item, []interface{}{item.HoldingID, item.Broker, item.Name, item.Description, item.Slug, item.TradeDate, item.TradeType, item.Quantity, item.SharePrice.Amount, item.SharePrice.Currency, item.ExchangeRate, item.ExchangeCurrencyID, item.BrokerageUnitPrice.Amount, item.BrokerageUnitPrice.Currency, item.Extid})
+	})
+}
+
+func (s *exportService) streamTransactions(ctx context.Context, portfolioID string, lookup *exportLookup, fn func(item interface{}, row []interface{}) error) error {
+	return s.transactionRepository.StreamByPortfolioID(ctx, portfolioID, func(t entities.Transaction) error {
+		item := models.ArchiveTransaction{
+			TransactionID:    t.TransactionID,
+			HoldingID:        t.HoldingID,
+			StockID:          t.StockID,
+			CryptocurrencyID: t.CryptocurrencyID,
+			TransactionType:  t.Type(),
+			Quantity:         t.Quantity,
+			TransactionPrice: t.TransactionPrice,
+			TransactionDate:  t.TransactionDate,
+			Extid:            t.Extid,
+		}
+		if t.StockID != "" {
+			stock, err := lookup.stock(ctx, t.StockID)
+			if err != nil {
+				return err
+			}
+			item.Symbol, item.ISIN, item.Name = stock.TickerSymbol, stock.Extid, stock.CompanyName
+		} else if t.CryptocurrencyID != "" {
+			cryptoCurrency, err := lookup.cryptoCurrency(ctx, t.CryptocurrencyID)
+			if err != nil {
+				return err
+			}
+			item.Symbol, item.Name = cryptoCurrency.Symbol, cryptoCurrency.Name
+		}
+
+		return fn(item, []interface{}{item.TransactionID, item.HoldingID, item.TransactionDate, item.TransactionType, item.Symbol, item.ISIN, item.Name, item.Quantity, item.TransactionPrice.Amount, item.TransactionPrice.Currency, item.Extid})
+	})
+}
+
+func (s *exportService) streamDividends(ctx context.Context, portfolioID string, lookup *exportLookup, fn func(item interface{}, row []interface{}) error) error {
+	return s.dividendRepository.StreamByPortfolioID(ctx, portfolioID, func(d entities.PortfolioDividend) error {
+		stock, err := lookup.stock(ctx, d.StockID)
+		if err != nil {
+			return err
+		}
+
+		item := models.ArchiveDividend{
+			DividendID:       d.DividendID,
+			StockID:          d.StockID,
+			Symbol:           stock.TickerSymbol,
+			ISIN:             stock.Extid,
+			DividendPerShare: d.DividendPerShare,
+			ExDate:           d.ExDate,
+			RecordDate:       d.RecordDate,
+			PayDate:          d.PayDate,
+			Shares:           d.Shares,
+			Amount:           d.DividendPerShare.Mul(d.Shares),
+		}
+		return fn(item, []interface{}{item.DividendID, item.Symbol, item.ISIN, item.ExDate, item.RecordDate, item.PayDate, item.DividendPerShare.Amount, item.DividendPerShare.Currency, item.Shares, item.Amount.Amount})
+	})
+}
+
+func (s *exportService) streamRealizedGains(ctx context.Context, portfolioID string, lookup *exportLookup, fn func(item interface{}, row []interface{}) error) error {
+	return s.lotRepository.StreamRealizedGainsByPortfolioID(ctx, portfolioID, func(g entities.RealizedGain) error {
+		item := models.ArchiveRealizedGain{
+			BuyTransactionID:  g.BuyTransactionID,
+			SellTransactionID: g.SellTransactionID,
+			InstrumentID:      g.InstrumentID,
+			InstrumentType:    g.InstrumentType,
+			Method:            g.Method,
+			Quantity:          g.Quantity,
+			AcquiredAt:        g.AcquiredAt,
+			SoldAt:            g.SoldAt,
+			CostBasis:         g.CostBasis,
+			Proceeds:          g.Proceeds,
+			Gain:              g.Gain,
+		}
+		if g.InstrumentType == entities.EntityNameStock {
+			stock, err := lookup.stock(ctx, g.InstrumentID)
+			if err != nil {
+				return err
+			}
+			item.Symbol = stock.TickerSymbol
+		} else {
+			cryptoCurrency, err := lookup.cryptoCurrency(ctx, g.InstrumentID)
+			if err != nil {
+				return err
+			}
+			item.Symbol = cryptoCurrency.Symbol
+		}
+
+		return fn(item, []interface{}{item.SellTransactionID, item.BuyTransactionID, item.Symbol, item.InstrumentType, item.Method, item.AcquiredAt, item.SoldAt, item.Quantity, item.CostBasis.Amount, item.Proceeds.Amount, item.Gain.Amount, item.Gain.Currency})
+	})
+}
+
+// writeCSV writes the items of a single type of a portfolio as a CSV file with a header line
+func writeCSV(ctx context.Context, portfolioID string, table exportTable, lookup *exportLookup, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(table.header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(table.header))
+	err := table.stream(ctx, portfolioID, lookup, func(_ interface{}, row []interface{}) error {
+		for i, v := range row {
+			record[i] = csvValue(v)
+		}
+		return cw.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeXLSX writes a workbook with a sheet per type of a portfolio. Its sheets are written with stream writers, which keep the rows
+// of large sheets in temporary files rather than in memory.
+func writeXLSX(ctx context.Context, portfolioID string, tables []exportTable, lookup *exportLookup, w io.Writer) (err error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	dateStyle, err := f.NewStyle(&excelize.Style{NumFmt: 14})
+	if err != nil {
+		return
+	}
+
+	for i, table := range tables {
+		if i == 0 {
+			err = f.SetSheetName(f.GetSheetName(0), table.sheet)
+		} else {
+			_, err = f.NewSheet(table.sheet)
+		}
+		if err != nil {
+			return
+		}
+
+		var sw *excelize.StreamWriter
+		if sw, err = f.NewStreamWriter(table.sheet); err != nil {
+			return
+		}
+		header := make([]interface{}, len(table.header))
+		for j, name := range table.header {
+			header[j] = name
+		}
+		if err = sw.SetRow("A1", header); err != nil {
+			return
+		}
+
+		line := 1
+		err = table.stream(ctx, portfolioID, lookup, func(_ interface{}, row []interface{}) error {
+			line++
+			cell, err := excelize.CoordinatesToCellName(1, line)
+			if err != nil {
+				return err
+			}
+			values := make([]interface{}, len(row))
+			for j, v := range row {
+				values[j] = xlsxValue(v, dateStyle)
+			}
+			return sw.SetRow(cell, values)
+		})
+		if err != nil {
+			return
+		}
+		if err = sw.Flush(); err != nil {
+			return
+		}
+	}
+
+	return f.Write(w)
+}
+
+// writeArchive writes the JSON archive of a portfolio, item by item as they are streamed from the repositories
+func writeArchive(ctx context.Context, portfolio entities.Portfolio, tables []exportTable, lookup *exportLookup, w io.Writer) error {
+	head, err := json.Marshal(struct {
+		Version    int                     `json:"version"`
+		ExportedAt time.Time               `json:"exported_at"`
+		Portfolio  models.ArchivePortfolio `json:"portfolio"`
+	}{
+		Version:    models.ArchiveVersion,
+		ExportedAt: time.Now().UTC(),
+		Portfolio: models.ArchivePortfolio{
+			Name:                   portfolio.Name,
+			Extid:                  portfolio.Extid,
+			TaxCountryID:           portfolio.TaxCountryID,
+			FinancialYear:          portfolio.FinancialYear,
+			PerformenceCalculation: portfolio.PerformenceCalculation,
+			Summary:                portfolio.Summary,
+			PriceAlert:             portfolio.PriceAlert,
+			CompanyEventAlert:      portfolio.CompanyEventAlert,
+			BaseCurrency:           portfolio.BaseCurrency,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	if _, err = bw.Write(head[:len(head)-1]); err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if _, err = fmt.Fprintf(bw, ",%q:[", table.name); err != nil {
+			return err
+		}
+		items := 0
+		err = table.stream(ctx, portfolio.PortfolioID, lookup, func(item interface{}, _ []interface{}) error {
+			b, err := json.Marshal(item)
+			if err != nil {
+				return err
+			}
+			if items > 0 {
+				if err = bw.WriteByte(','); err != nil {
+					return err
+				}
+			}
+			items++
+			_, err = bw.Write(b)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if _, err = bw.WriteString("]"); err != nil {
+			return err
+		}
+	}
+	if _, err = bw.WriteString("}\n"); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// csvValue formats a value of a row for a CSV file, with decimals in full and dates as days
+func csvValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case decimal.Decimal:
+		return v.String()
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format("2006-01-02")
+	case int:
+		return strconv.Itoa(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// xlsxValue converts a value of a row for a sheet, with decimals as numbers and dates styled as days
+func xlsxValue(v interface{}, dateStyle int) interface{} {
+	switch v := v.(type) {
+	case decimal.Decimal:
+		return v.InexactFloat64()
+	case time.Time:
+		if v.IsZero() {
+			return nil
+		}
+		return excelize.Cell{StyleID: dateStyle, Value: v}
+	default:
+		return v
+	}
+}
+
+// exportLookup reads the brokers, stocks and cryptocurrencies referenced by the items of an export, each one once. Those not found
+// are returned with their ID only.
+type exportLookup struct {
+	brokerRepository         ports.BrokerRepository
+	stockRepository          ports.StockRepository
+	cryptoCurrencyRepository ports.CryptoCurrencyRepository
+	brokers                  map[string]entities.Broker
+	stocks                   map[string]entities.Stock
+	cryptoCurrencies         map[string]entities.CryptoCurrency
+}
+
+func newExportLookup(brokerRepo ports.BrokerRepository, stockRepo ports.StockRepository, cryptoCurrencyRepo ports.CryptoCurrencyRepository) *exportLookup {
+	return &exportLookup{
+		brokerRepository:         brokerRepo,
+		stockRepository:          stockRepo,
+		cryptoCurrencyRepository: cryptoCurrencyRepo,
+		brokers:                  map[string]entities.Broker{},
+		stocks:                   map[string]entities.Stock{},
+		cryptoCurrencies:         map[string]entities.CryptoCurrency{},
+	}
+}
+
+func (l *exportLookup) broker(ctx context.Context, ID string) (entities.Broker, error) {
+	if broker, ok := l.brokers[ID]; ok {
+		return broker, nil
+	}
+
+	broker := entities.Broker{BrokerID: ID}
+	result, err := l.brokerRepository.GetByID(ctx, ID)
+	if err == nil {
+		broker = *result.(*entities.Broker)
+	} else if !errors.Is(err, wrappers.NonExistentErr) {
+		return entities.Broker{}, err
+	}
+	l.brokers[ID] = broker
+	return broker, nil
+}
+
+func (l *exportLookup) stock(ctx context.Context, ID string) (entities.Stock, error) {
+	if stock, ok := l.stocks[ID]; ok {
+		return stock, nil
+	}
+
+	stock := entities.Stock{StockID: ID}
+	result, err := l.stockRepository.GetByID(ctx, ID)
+	if err == nil {
+		stock = *result.(*entities.Stock)
+	} else if !errors.Is(err, wrappers.NonExistentErr) {
+		return entities.Stock{}, err
+	}
+	l.stocks[ID] = stock
+	return stock, nil
+}
+
+func (l *exportLookup) cryptoCurrency(ctx context.Context, ID string) (entities.CryptoCurrency, error) {
+	if cryptoCurrency, ok := l.cryptoCurrencies[ID]; ok {
+		return cryptoCurrency, nil
+	}
+
+	cryptoCurrency := entities.CryptoCurrency{CryptoCurrencyID: ID}
+	result, err := l.cryptoCurrencyRepository.GetByID(ctx, ID)
+	if err == nil {
+		cryptoCurrency = *result.(*entities.CryptoCurrency)
+	} else if !errors.Is(err, wrappers.NonExistentErr) {
+		return entities.CryptoCurrency{}, err
+	}
+	l.cryptoCurrencies[ID] = cryptoCurrency
+	return cryptoCurrency, nil
+}
+
+// archiveReader reads a JSON archive of a portfolio item by item, calling its functions in the order the items appear. Realized
+// gains are skipped, as they are derived from the transactions.
+type archiveReader struct {
+	portfolio   func(models.ArchivePortfolio) error
+	holding     func(int, models.ArchiveHolding) error
+	transaction func(int, models.ArchiveTransaction) error
+	dividend    func(int, models.ArchiveDividend) error
+}
+
+func (ar archiveReader) read(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	version := 0
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return invalidArchive(err)
+		}
+
+		switch token {
+		case "version":
+			if err = dec.Decode(&version); err != nil {
+				return invalidArchive(err)
+			}
+			if version != models.ArchiveVersion {
+				return wrappers.NewValidationErr(fmt.Errorf("unsupported archive version %d, expected %d", version, models.ArchiveVersion))
+			}
+		case "portfolio":
+			var p models.ArchivePortfolio
+			if err = dec.Decode(&p); err != nil {
+				return invalidArchive(err)
+			}
+			if err = p.Validate(); err != nil {
+				return err
+			}
+			err = ar.portfolio(p)
+		case models.ExportTypeHoldings:
+			err = readArchiveList(dec, func(n int) error {
+				var h models.ArchiveHolding
+				if err := dec.Decode(&h); err != nil {
+					return invalidArchive(err)
+				}
+				if err := h.Validate(); err != nil {
+					return wrappers.NewValidationErr(fmt.Errorf("holding %d: %s", n, err))
+				}
+				return ar.holding(n, h)
+			})
+		case models.ExportTypeTransactions:
+			err = readArchiveList(dec, func(n int) error {
+				var t models.ArchiveTransaction
+				if err := dec.Decode(&t); err != nil {
+					return invalidArchive(err)
+				}
+				if err := t.Validate(); err != nil {
+					return wrappers.NewValidationErr(fmt.Errorf("transaction %d: %s", n, err))
+				}
+				return ar.transaction(n, t)
+			})
+		case models.ExportTypeDividends:
+			err = readArchiveList(dec, func(n int) error {
+				var d models.ArchiveDividend
+				if err := dec.Decode(&d); err != nil {
+					return invalidArchive(err)
+				}
+				if err := d.Validate(); err != nil {
+					return wrappers.NewValidationErr(fmt.Errorf("dividend %d: %s", n, err))
+				}
+				return ar.dividend(n, d)
+			})
+		case models.ExportTypeRealizedGains:
+			err = readArchiveList(dec, func(int) error {
+				var skipped json.RawMessage
+				if err := dec.Decode(&skipped); err != nil {
+					return invalidArchive(err)
+				}
+				return nil
+			})
+		default:
+			var skipped json.RawMessage
+			if err = dec.Decode(&skipped); err != nil {
+				err = invalidArchive(err)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := expectDelim(dec, '}'); err != nil {
+		return err
+	}
+	if version == 0 {
+		return wrappers.NewValidationErr(fmt.Errorf("archive version cannot be empty"))
+	}
+	return nil
+}
+
+// readArchiveList reads a list of an archive, calling item to decode each of its items
+func readArchiveList(dec *json.Decoder, item func(n int) error) error {
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+	for n := 1; dec.More(); n++ {
+		if err := item(n); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return invalidArchive(err)
+	}
+	if token != delim {
+		return invalidArchive(fmt.Errorf("expected %s", delim))
+	}
+	return nil
+}
+
+func invalidArchive(err error) error {
+	return wrappers.NewValidationErr(fmt.Errorf("invalid archive: %s", err))
+}
+
+// validateArchive reads an archive without writing anything, checking that it has a portfolio followed by its holdings, that every
+// transaction belongs to one of its holdings and every dividend to one of their stocks, and that the brokers of the holdings exist.
+// The IDs of the brokers are returned keyed by the broker ID and slug of the archive.
+func (s *exportService) validateArchive(ctx context.Context, archive io.Reader, brokers map[string]string) error {
+	hasPortfolio := false
+	holdings, stocks := map[string]bool{}, map[string]bool{}
+
+	err := archiveReader{
+		portfolio: func(models.ArchivePortfolio) error {
+			if hasPortfolio {
+				return wrappers.NewValidationErr(fmt.Errorf("archive cannot have more than one portfolio"))
+			}
+			hasPortfolio = true
+			return nil
+		},
+		holding: func(n int, h models.ArchiveHolding) error {
+			if !hasPortfolio {
+				return wrappers.NewValidationErr(fmt.Errorf("holding %d: holdings must follow the portfolio", n))
+			}
+			if holdings[h.HoldingID] {
+				return wrappers.NewValidationErr(fmt.Errorf("holding %d: duplicate holding id %s", n, h.HoldingID))
+			}
+			holdings[h.HoldingID] = true
+			return s.resolveBroker(ctx, n, h, brokers)
+		},
+		transaction: func(n int, t models.ArchiveTransaction) error {
+			if !holdings[t.HoldingID] {
+				return wrappers.NewValidationErr(fmt.Errorf("transaction %d: holding %s not found in the archive", n, t.HoldingID))
+			}
+			if t.StockID != "" {
+				stocks[t.StockID] = true
+			}
+			return nil
+		},
+		dividend: func(n int, d models.ArchiveDividend) error {
+			if !stocks[d.StockID] {
+				return wrappers.NewValidationErr(fmt.Errorf("dividend %d: stock %s has no transaction in the archive", n, d.StockID))
+			}
+			return nil
+		},
+	}.read(archive)
+	if err != nil {
+		return err
+	}
+
+	if !hasPortfolio {
+		return wrappers.NewValidationErr(fmt.Errorf("archive has no portfolio"))
+	}
+	return nil
+}
+
+// resolveBroker finds the broker of a holding of an archive by its ID, or else by its slug
+func (s *exportService) resolveBroker(ctx context.Context, n int, h models.ArchiveHolding, brokers map[string]string) error {
+	key := h.BrokerID + "|" + h.Broker
+	if _, ok := brokers[key]; ok {
+		return nil
+	}
+
+	if h.BrokerID != "" {
+		result, err := s.brokerRepository.GetByID(ctx, h.BrokerID)
+		if err == nil {
+			brokers[key] = result.(*entities.Broker).BrokerID
+			return nil
+		}
+		if !errors.Is(err, wrappers.NonExistentErr) {
+			return err
+		}
+	}
+	if h.Broker != "" {
+		result, err := s.brokerRepository.GetBySlug(ctx, h.Broker)
+		if err == nil {
+			brokers[key] = result.(*entities.Broker).BrokerID
+			return nil
+		}
+		if !errors.Is(err, wrappers.NonExistentErr) {
+			return err
+		}
+	}
+	return wrappers.NewValidationErr(fmt.Errorf("holding %d: broker %s not found", n, strings.TrimSpace(h.BrokerID+" "+h.Broker)))
+}
+
+// archiveRestore writes a validated archive, keeping the IDs of what it creates so it can be undone
+type archiveRestore struct {
+	service *exportService
+	userID  string
+	brokers map[string]string
+
+	portfolio        entities.Portfolio
+	holdings         map[string]string
+	stocks           map[string]string
+	cryptoCurrencies map[string]string
+	bySymbol         map[string]string
+	byISIN           map[string]string
+	paid             map[string]map[string]bool
+	pending          []interface{}
+
+	createdHoldings         []string
+	createdStocks           []string
+	createdCryptoCurrencies []string
+	createdTransactions     []string
+	createdDividends        []string
+
+	resp models.ArchiveRestoreResp
+}
+
+func (ar *archiveRestore) run(ctx context.Context, archive io.Reader) error {
+	err := archiveReader{
+		portfolio:   func(p models.ArchivePortfolio) error { return ar.restorePortfolio(ctx, p) },
+		holding:     func(_ int, h models.ArchiveHolding) error { return ar.restoreHolding(ctx, h) },
+		transaction: func(_ int, t models.ArchiveTransaction) error { return ar.restoreTransaction(ctx, t) },
+		dividend:    func(_ int, d models.ArchiveDividend) error { return ar.restoreDividend(ctx, d) },
+	}.read(archive)
+	if err != nil {
+		return err
+	}
+	if err = ar.flushTransactions(ctx); err != nil {
+		return err
+	}
+
+	_, transactions, err := loadPortfolioTransactions(ctx, ar.service.portfolioRepository, ar.service.transactionRepository, ar.portfolio.PortfolioID)
+	if err != nil {
+		return err
+	}
+	return replaceLedger(ctx, ar.service.lotRepository, ar.portfolio.PortfolioID, costBasisMethod(ar.portfolio), transactions)
+}
+
+func (ar *archiveRestore) restorePortfolio(ctx context.Context, p models.ArchivePortfolio) (err error) {
+	now := time.Now().UTC()
+	ar.portfolio = entities.Portfolio{
+		Userid:                 ar.userID,
+		Name:                   p.Name,
+		Extid:                  p.Extid,
+		TaxCountryID:           p.TaxCountryID,
+		FinancialYear:          p.FinancialYear,
+		PerformenceCalculation: p.PerformenceCalculation,
+		Summary:                p.Summary,
+		PriceAlert:             p.PriceAlert,
+		CompanyEventAlert:      p.CompanyEventAlert,
+		BaseCurrency:           p.BaseCurrency,
+		CreatedAt:              now,
+		UpdatedAt:              now,
+	}
+	if ar.portfolio.PortfolioID, err = ar.service.portfolioRepository.Create(ctx, ar.portfolio); err != nil {
+		return
+	}
+
+	ar.resp = models.ArchiveRestoreResp{PortfolioID: ar.portfolio.PortfolioID, NewStocks: []string{}, NewCryptocurrencies: []string{}}
+	return
+}
+
+func (ar *archiveRestore) restoreHolding(ctx context.Context, h models.ArchiveHolding) error {
+	now := time.Now().UTC()
+	ID, err := ar.service.holdingRepository.Create(ctx, entities.Holding{
+		PortfolioID:        ar.portfolio.PortfolioID,
+		BrokerID:           ar.brokers[h.BrokerID+"|"+h.Broker],
+		Extid:              h.Extid,
+		Name:               h.Name,
+		Description:        h.Description,
+		Slug:               h.Slug,
+		TradeDate:          h.TradeDate,
+		TradeType:          h.TradeType,
+		Quantity:           h.Quantity,
+		SharePrice:         h.SharePrice,
+		ExchangeRate:       h.ExchangeRate,
+		ExchangeCurrencyID: h.ExchangeCurrencyID,
+		BrokerageUnitPrice: h.BrokerageUnitPrice,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	})
+	if err != nil {
+		return err
+	}
+
+	ar.holdings[h.HoldingID] = ID
+	ar.createdHoldings = append(ar.createdHoldings, ID)
+	ar.resp.Holdings++
+	return nil
+}
+
+func (ar *archiveRestore) restoreTransaction(ctx context.Context, t models.ArchiveTransaction) error {
+	now := time.Now().UTC()
+	transaction := entities.Transaction{
+		HoldingID:        ar.holdings[t.HoldingID],
+		TransactionType:  t.TransactionType,
+		Quantity:         t.Quantity,
+		TransactionPrice: t.TransactionPrice,
+		TransactionDate:  t.TransactionDate,
+		Extid:            t.Extid,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	var err error
+	if t.StockID != "" {
+		transaction.StockID, err = ar.stock(ctx, transaction.HoldingID, t)
+	} else if t.CryptocurrencyID != "" {
+		transaction.CryptocurrencyID, err = ar.cryptoCurrency(ctx, transaction.HoldingID, t)
+	}
+	if err != nil {
+		return err
+	}
+
+	ar.pending = append(ar.pending, transaction)
+	if len(ar.pending) < restoreChunkSize {
+		return nil
+	}
+	return ar.flushTransactions(ctx)
+}
+
+func (ar *archiveRestore) flushTransactions(ctx context.Context) error {
+	if len(ar.pending) == 0 {
+		return nil
+	}
+
+	IDs, err := ar.service.transactionRepository.CreateMany(ctx, ar.pending)
+	if err != nil {
+		return err
+	}
+	ar.createdTransactions = append(ar.createdTransactions, IDs...)
+	ar.resp.Transactions += len(ar.pending)
+	ar.pending = ar.pending[:0]
+	return nil
+}
+
+func (ar *archiveRestore) restoreDividend(ctx context.Context, d models.ArchiveDividend) error {
+	stockID := ar.stocks[d.StockID]
+	paid, ok := ar.paid[stockID]
+	if !ok {
+		paid = map[string]bool{}
+		result, err := ar.service.dividendRepository.Get(ctx, map[string]interface{}{"stockid": stockID}, nil, nil)
+		if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+			return err
+		}
+		for _, v := range result {
+			paid[startOfDay(v.(*entities.Dividend).PaidOn()).Format("2006-01-02")] = true
+		}
+		ar.paid[stockID] = paid
+	}
+
+	dividend := entities.Dividend{
+		StockID:          stockID,
+		DividendPerShare: d.DividendPerShare,
+		ExDate:           d.ExDate,
+		RecordDate:       d.RecordDate,
+		PayDate:          d.PayDate,
+	}
+	day := startOfDay(dividend.PaidOn()).Format("2006-01-02")
+	if paid[day] {
+		ar.resp.DuplicateDividends++
+		return nil
+	}
+
+	now := time.Now().UTC()
+	dividend.CreatedAt, dividend.UpdatedAt = now, now
+	ID, err := ar.service.dividendRepository.Create(ctx, dividend)
+	if err != nil {
+		return err
+	}
+	paid[day] = true
+	ar.createdDividends = append(ar.createdDividends, ID)
+	ar.resp.Dividends++
+	return nil
+}
+
+// stock returns the ID of the stock of a transaction of the archive, found by its ID, ISIN or symbol and created when none is found
+func (ar *archiveRestore) stock(ctx context.Context, holdingID string, t models.ArchiveTransaction) (string, error) {
+	if ID, ok := ar.stocks[t.StockID]; ok {
+		return ID, nil
+	}
+
+	_, err := ar.service.stockRepository.GetByID(ctx, t.StockID)
+	if err == nil {
+		ar.stocks[t.StockID] = t.StockID
+		return t.StockID, nil
+	}
+	if !errors.Is(err, wrappers.NonExistentErr) {
+		return "", err
+	}
+
+	if ar.bySymbol == nil {
+		if ar.bySymbol, ar.byISIN, err = stockIndex(ctx, ar.service.stockRepository); err != nil {
+			return "", err
+		}
+	}
+	symbol, isin := strings.ToUpper(strings.TrimSpace(t.Symbol)), strings.ToUpper(strings.TrimSpace(t.ISIN))
+	if ID, ok := ar.byISIN[isin]; ok && isin != "" {
+		ar.stocks[t.StockID] = ID
+		return ID, nil
+	}
+	if ID, ok := ar.bySymbol[symbol]; ok && symbol != "" {
+		ar.stocks[t.StockID] = ID
+		return ID, nil
+	}
+
+	now := time.Now().UTC()
+	ID, err := ar.service.stockRepository.Create(ctx, entities.Stock{HoldingID: holdingID, Extid: t.ISIN, TickerSymbol: t.Symbol, CompanyName: t.Name, Slug: strings.ToLower(t.Symbol), CreatedAt: now, UpdatedAt: now})
+	if err != nil {
+		return "", err
+	}
+	ar.stocks[t.StockID] = ID
+	if isin != "" {
+		ar.byISIN[isin] = ID
+	}
+	if symbol != "" {
+		ar.bySymbol[symbol] = ID
+	}
+	ar.createdStocks = append(ar.createdStocks, ID)
+	ar.resp.NewStocks = append(ar.resp.NewStocks, t.Symbol)
+	return ID, nil
+}
+
+// cryptoCurrency returns the ID of the cryptocurrency of a transaction of the archive, found by its ID or symbol and created when
+// none is found
+func (ar *archiveRestore) cryptoCurrency(ctx context.Context, holdingID string, t models.ArchiveTransaction) (string, error) {
+	if ID, ok := ar.cryptoCurrencies[t.CryptocurrencyID]; ok {
+		return ID, nil
+	}
+
+	_, err := ar.service.cryptoCurrencyRepository.GetByID(ctx, t.CryptocurrencyID)
+	if err == nil {
+		ar.cryptoCurrencies[t.CryptocurrencyID] = t.CryptocurrencyID
+		return t.CryptocurrencyID, nil
+	}
+	if !errors.Is(err, wrappers.NonExistentErr) {
+		return "", err
+	}
+
+	if t.Symbol != "" {
+		result, err := ar.service.cryptoCurrencyRepository.Get(ctx, map[string]interface{}{"symbol": t.Symbol}, nil, nil)
+		if err == nil {
+			ID := result[0].(*entities.CryptoCurrency).CryptoCurrencyID
+			ar.cryptoCurrencies[t.CryptocurrencyID] = ID
+			return ID, nil
+		}
+		if !errors.Is(err, wrappers.NonExistentErr) {
+			return "", err
+		}
+	}
+
+	now := time.Now().UTC()
+	ID, err := ar.service.cryptoCurrencyRepository.Create(ctx, entities.CryptoCurrency{HoldingID: holdingID, Symbol: t.Symbol, Name: t.Name, CreatedAt: now, UpdatedAt: now})
+	if err != nil {
+		return "", err
+	}
+	ar.cryptoCurrencies[t.CryptocurrencyID] = ID
+	ar.createdCryptoCurrencies = append(ar.createdCryptoCurrencies, ID)
+	ar.resp.NewCryptocurrencies = append(ar.resp.NewCryptocurrencies, t.Symbol)
+	return ID, nil
+}
+
+// undo deletes what a failed restore created, in the reverse order of their references. It is best effort, so a restore returns
+// the error that failed it rather than an error of its undo.
+func (ar *archiveRestore) undo(ctx context.Context) {
+	for _, ID := range ar.createdDividends {
+		_ = ar.service.dividendRepository.Delete(ctx, ID)
+	}
+	for _, ID := range ar.createdTransactions {
+		_ = ar.service.transactionRepository.Delete(ctx, ID)
+	}
+	for _, ID := range ar.createdStocks {
+		_ = ar.service.stockRepository.Delete(ctx, ID)
+	}
+	for _, ID := range ar.createdCryptoCurrencies {
+		_ = ar.service.cryptoCurrencyRepository.Delete(ctx, ID)
+	}
+	for _, ID := range ar.createdHoldings {
+		_ = ar.service.holdingRepository.Delete(ctx, ID)
+	}
+	if ar.portfolio.PortfolioID != "" {
+		_ = ar.service.portfolioRepository.Delete(ctx, ar.portfolio.PortfolioID)
+	}
+}
@@ -0,0 +1,635 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+	"github.com/xuri/excelize/v2"
+)
+
+// TestNewExportService_Ok checks that NewExportService creates a new exportService struct
+func TestNewExportService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+
+	// Act
+	service := NewExportService(cfg, mocks.NewUserRepository(t), mocks.NewPortfolioRepository(t), mocks.NewHoldingRepository(t), mocks.NewBrokerRepository(t), mocks.NewTransactionRepository(t), mocks.NewStockRepository(t), mocks.NewCryptoCurrencyRepository(t), mocks.NewDividendRepository(t), mocks.NewLotRepository(t), mocks.NewPriceRepository(t), mocks.NewPortfolioPerformanceCodec(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// exportMocks returns an export service of a portfolio with a holding at Degiro, a buy of AAPL, a sale of DOGE, a dividend of
+// AAPL and a realized gain of DOGE, expecting each type to be streamed at most once
+func exportMocks(t *testing.T) *exportService {
+	buyDate := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a", Name: "Retirement", BaseCurrency: "EUR", PerformenceCalculation: entities.CostBasisFIFO}, nil).Once()
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.StreamByPortfolioID), context.Background(), "portfolio-a", mock.Anything).Return(func(_ context.Context, _ string, fn func(entities.Holding) error) error {
+		return fn(entities.Holding{HoldingID: "holding-a", BrokerID: "broker-a", Name: "Degiro", Slug: "degiro", TradeDate: buyDate})
+	}).Maybe()
+
+	brokerRepositoryMock := mocks.NewBrokerRepository(t)
+	brokerRepositoryMock.On(testutils.FunctionName(t, ports.BrokerRepository.GetByID), context.Background(), "broker-a").Return(&entities.Broker{BrokerID: "broker-a", Name: "Degiro", Slug: "degiro"}, nil).Maybe()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.StreamByPortfolioID), context.Background(), "portfolio-a", mock.Anything).Return(func(_ context.Context, _ string, fn func(entities.Transaction) error) error {
+		if err := fn(entities.Transaction{TransactionID: "transaction-a", HoldingID: "holding-a", StockID: "stock-aapl", Quantity: decimal.NewFromInt(10), TransactionPrice: entities.NewMoney(decimal.RequireFromString("170.5"), "USD"), TransactionDate: buyDate, Extid: "T-1"}); err != nil {
+			return err
+		}
+		return fn(entities.Transaction{TransactionID: "transaction-b", HoldingID: "holding-a", CryptocurrencyID: "crypto-doge", Quantity: decimal.NewFromInt(-100), TransactionPrice: entities.NewMoney(decimal.RequireFromString("0.2"), "USD"), TransactionDate: buyDate.AddDate(0, 1, 0)})
+	}).Maybe()
+
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-aapl").Return(&entities.Stock{StockID: "stock-aapl", TickerSymbol: "AAPL", Extid: "US0378331005", CompanyName: "Apple Inc."}, nil).Maybe()
+
+	cryptoCurrencyRepositoryMock := mocks.NewCryptoCurrencyRepository(t)
+	cryptoCurrencyRepositoryMock.On(testutils.FunctionName(t, ports.CryptoCurrencyRepository.GetByID), context.Background(), "crypto-doge").Return(&entities.CryptoCurrency{CryptoCurrencyID: "crypto-doge", Symbol: "DOGE", Name: "Dogecoin"}, nil).Maybe()
+
+	dividendRepositoryMock := mocks.NewDividendRepository(t)
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.StreamByPortfolioID), context.Background(), "portfolio-a", mock.Anything).Return(func(_ context.Context, _ string, fn func(entities.PortfolioDividend) error) error {
+		return fn(entities.PortfolioDividend{Dividend: entities.Dividend{DividendID: "dividend-a", StockID: "stock-aapl", DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.24"), "USD"), ExDate: time.Date(2024, time.May, 10, 0, 0, 0, 0, time.UTC), PayDate: time.Date(2024, time.May, 16, 0, 0, 0, 0, time.UTC)}, Shares: decimal.NewFromInt(10)})
+	}).Maybe()
+
+	lotRepositoryMock := mocks.NewLotRepository(t)
+	lotRepositoryMock.On(testutils.FunctionName(t, ports.LotRepository.StreamRealizedGainsByPortfolioID), context.Background(), "portfolio-a", mock.Anything).Return(func(_ context.Context, _ string, fn func(entities.RealizedGain) error) error {
+		return fn(entities.RealizedGain{BuyTransactionID: "transaction-c", SellTransactionID: "transaction-b", InstrumentID: "crypto-doge", InstrumentType: entities.EntityNameCryptoCurrency, Method: entities.CostBasisFIFO, Quantity: decimal.NewFromInt(100), AcquiredAt: buyDate, SoldAt: buyDate.AddDate(0, 1, 0), CostBasis: entities.NewMoney(decimal.NewFromInt(10), "USD"), Proceeds: entities.NewMoney(decimal.NewFromInt(20), "USD"), Gain: entities.NewMoney(decimal.NewFromInt(10), "USD")})
+	}).Maybe()
+
+	return &exportService{
+		config:                   config.Config{},
+		portfolioRepository:      portfolioRepositoryMock,
+		holdingRepository:        holdingRepositoryMock,
+		brokerRepository:         brokerRepositoryMock,
+		transactionRepository:    transactionRepositoryMock,
+		stockRepository:          stockRepositoryMock,
+		cryptoCurrencyRepository: cryptoCurrencyRepositoryMock,
+		dividendRepository:       dividendRepositoryMock,
+		lotRepository:            lotRepositoryMock,
+	}
+}
+
+// TestExport_CSV checks that the CSV format writes the requested type with a header line, decimals in full and dates as days
+func TestExport_CSV(t *testing.T) {
+	// Arrange
+	service := exportMocks(t)
+	var w bytes.Buffer
+
+	// Act
+	err := service.Export(context.Background(), "user-a", "portfolio-a", models.ExportReq{Format: models.ExportFormatCSV, Type: models.ExportTypeTransactions}, &w)
+
+	// Assert
+	assert.Nil(t, err)
+	expected := "transactionid,holdingid,transaction_date,transaction_type,symbol,isin,name,quantity,price,currency,extid\n" +
+		"transaction-a,holding-a,2024-03-05,BUY,AAPL,US0378331005,Apple Inc.,10,170.5,USD,T-1\n" +
+		"transaction-b,holding-a,2024-04-05,SELL,DOGE,,Dogecoin,-100,0.2,USD,\n"
+	assert.Equal(t, expected, w.String())
+}
+
+// TestExport_XLSX checks that the XLSX format writes a workbook with a sheet per type, with numbers and dates as such
+func TestExport_XLSX(t *testing.T) {
+	// Arrange
+	service := exportMocks(t)
+	var w bytes.Buffer
+
+	// Act
+	err := service.Export(context.Background(), "user-a", "portfolio-a", models.ExportReq{Format: models.ExportFormatXLSX}, &w)
+
+	// Assert
+	assert.Nil(t, err)
+	f, err := excelize.OpenReader(&w)
+	assert.Nil(t, err)
+	defer f.Close()
+	assert.Equal(t, []string{"Holdings", "Transactions", "Dividends", "Realized gains"}, f.GetSheetList())
+
+	rows, err := f.GetRows("Dividends")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{
+		{"dividendid", "symbol", "isin", "ex_date", "record_date", "pay_date", "dividend_per_share", "currency", "shares", "amount"},
+		{"dividend-a", "AAPL", "US0378331005", "05-10-24", "", "05-16-24", "0.24", "USD", "10", "2.4"},
+	}, rows)
+
+	rows, err = f.GetRows("Realized gains")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"transaction-b", "transaction-c", "DOGE", entities.EntityNameCryptoCurrency, "1", "03-05-24", "04-05-24", "100", "10", "20", "10", "USD"}, rows[1])
+}
+
+// TestExport_JSON checks that the JSON format writes an archive of the whole portfolio
+func TestExport_JSON(t *testing.T) {
+	// Arrange
+	service := exportMocks(t)
+	var w bytes.Buffer
+
+	// Act
+	err := service.Export(context.Background(), "user-a", "portfolio-a", models.ExportReq{Format: models.ExportFormatJSON}, &w)
+
+	// Assert
+	assert.Nil(t, err)
+	var archive struct {
+		Version       int                          `json:"version"`
+		Portfolio     models.ArchivePortfolio      `json:"portfolio"`
+		Holdings      []models.ArchiveHolding      `json:"holdings"`
+		Transactions  []models.ArchiveTransaction  `json:"transactions"`
+		Dividends     []models.ArchiveDividend     `json:"dividends"`
+		RealizedGains []models.ArchiveRealizedGain `json:"realized_gains"`
+	}
+	assert.Nil(t, json.Unmarshal(w.Bytes(), &archive))
+	assert.Equal(t, models.ArchiveVersion, archive.Version)
+	assert.Equal(t, "Retirement", archive.Portfolio.Name)
+	assert.Equal(t, "degiro", archive.Holdings[0].Broker)
+	assert.Len(t, archive.Transactions, 2)
+	assert.Equal(t, "US0378331005", archive.Transactions[0].ISIN)
+	assert.Equal(t, "crypto-doge", archive.Transactions[1].CryptocurrencyID)
+	assert.Equal(t, "2.4", archive.Dividends[0].Amount.Amount.String())
+	assert.Equal(t, "DOGE", archive.RealizedGains[0].Symbol)
+}
+
+// TestExport_PortfolioPerformance checks that the PP format writes a securities account per holding with its buys and sells settled
+// in the deposit account of their currency, the dividends net of withholding tax and the cash transactions into the deposit account of
+// their currency and the stored closes as quotes
+func TestExport_PortfolioPerformance(t *testing.T) {
+	// Arrange
+	service := exportMocks(t)
+	service.config.Withholding.Rates = map[string]decimal.Decimal{"US": decimal.RequireFromString("0.15")}
+	buyDate := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+	service.transactionRepository.(*mocks.TransactionRepository).On(testutils.FunctionName(t, ports.TransactionRepository.StreamByPortfolioID), context.Background(), "portfolio-a", mock.Anything).Unset()
+	service.transactionRepository.(*mocks.TransactionRepository).On(testutils.FunctionName(t, ports.TransactionRepository.StreamByPortfolioID), context.Background(), "portfolio-a", mock.Anything).Return(func(_ context.Context, _ string, fn func(entities.Transaction) error) error {
+		for _, transaction := range []entities.Transaction{
+			{TransactionID: "transaction-d", HoldingID: "holding-a", TransactionType: entities.TransactionTypeDeposit, TransactionPrice: entities.NewMoney(decimal.NewFromInt(2000), "USD"), TransactionDate: buyDate.AddDate(0, 0, -1)},
+			{TransactionID: "transaction-a", HoldingID: "holding-a", StockID: "stock-aapl", Quantity: decimal.NewFromInt(10), TransactionPrice: entities.NewMoney(decimal.RequireFromString("170.5"), "USD"), TransactionDate: buyDate, Extid: "T-1"},
+			{TransactionID: "transaction-b", HoldingID: "holding-a", CryptocurrencyID: "crypto-doge", Quantity: decimal.NewFromInt(-100), TransactionPrice: entities.NewMoney(decimal.RequireFromString("0.2"), "USD"), TransactionDate: buyDate.AddDate(0, 1, 0)},
+		} {
+			if err := fn(transaction); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Once()
+	service.stockRepository.(*mocks.StockRepository).On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-aapl").Unset()
+	service.stockRepository.(*mocks.StockRepository).On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-aapl").Return(&entities.Stock{StockID: "stock-aapl", TickerSymbol: "AAPL", Extid: "US0378331005", CompanyName: "Apple Inc.", Country: "US"}, nil).Once()
+
+	priceRepositoryMock := mocks.NewPriceRepository(t)
+	priceRepositoryMock.On(testutils.FunctionName(t, ports.PriceRepository.GetRange), context.Background(), "stock-aapl", time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), mock.Anything).Return([]interface{}{
+		&entities.Price{InstrumentID: "stock-aapl", PriceDate: time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), Close: decimal.RequireFromString("171.25"), Currency: "USD"},
+	}, nil).Once()
+	priceRepositoryMock.On(testutils.FunctionName(t, ports.PriceRepository.GetRange), context.Background(), "crypto-doge", mock.Anything, mock.Anything).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	service.priceRepository = priceRepositoryMock
+
+	var client entities.PPClient
+	ppCodecMock := mocks.NewPortfolioPerformanceCodec(t)
+	ppCodecMock.On(testutils.FunctionName(t, ports.PortfolioPerformanceCodec.Encode), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		client = args.Get(1).(entities.PPClient)
+	}).Return(nil).Once()
+	service.ppCodec = ppCodecMock
+
+	// Act
+	err := service.Export(context.Background(), "user-a", "portfolio-a", models.ExportReq{Format: models.ExportFormatPP}, &bytes.Buffer{})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "EUR", client.BaseCurrency)
+
+	assert.Len(t, client.Securities, 2)
+	apple := client.Securities[0]
+	assert.Equal(t, entities.PPSecurity{UUID: entities.NewPPUUID("stock-aapl"), Name: "Apple Inc.", CurrencyCode: "USD", ISIN: "US0378331005", TickerSymbol: "AAPL", Feed: entities.PPFeedManual, Prices: apple.Prices}, apple)
+	assert.Equal(t, "171.25", apple.Prices[0].Value.String())
+	assert.True(t, client.Securities[1].IsCrypto())
+
+	assert.Len(t, client.Portfolios, 1)
+	portfolio := client.Portfolios[0]
+	assert.Equal(t, "Degiro", portfolio.Name)
+	assert.Len(t, portfolio.Transactions, 2)
+	buy, sell := portfolio.Transactions[0], portfolio.Transactions[1]
+	assert.Equal(t, entities.PPTransactionBuy, buy.Type)
+	assert.Equal(t, "10", buy.Shares.String())
+	assert.Equal(t, "1705 USD", buy.Amount.String())
+	assert.Equal(t, entities.PPTransactionSell, sell.Type)
+	assert.Equal(t, "100", sell.Shares.String())
+
+	assert.Len(t, client.Accounts, 2)
+	usd, eur := client.Accounts[0], client.Accounts[1]
+	assert.Equal(t, "Retirement USD", usd.Name)
+	assert.Equal(t, eur.UUID, portfolio.ReferenceAccount)
+	assert.Len(t, usd.Transactions, 4)
+	deposit := usd.Transactions[0]
+	assert.Equal(t, entities.PPTransactionDeposit, deposit.Type)
+	assert.Equal(t, "2000 USD", deposit.Amount.String())
+	assert.Empty(t, deposit.Security)
+	buySettlement, sellSettlement := usd.Transactions[1], usd.Transactions[2]
+	assert.Equal(t, entities.PPTransactionBuy, buySettlement.Type)
+	assert.Equal(t, apple.UUID, buySettlement.Security)
+	assert.True(t, buySettlement.Shares.IsZero())
+	assert.Equal(t, "1705 USD", buySettlement.Amount.String())
+	assert.Equal(t, buy.UUID, buySettlement.CrossEntry)
+	assert.Equal(t, buySettlement.UUID, buy.CrossEntry)
+	assert.Equal(t, entities.PPTransactionSell, sellSettlement.Type)
+	assert.Equal(t, "20 USD", sellSettlement.Amount.String())
+	assert.Equal(t, sell.UUID, sellSettlement.CrossEntry)
+	dividend := usd.Transactions[3]
+	assert.Equal(t, entities.PPTransactionDividends, dividend.Type)
+	assert.Equal(t, apple.UUID, dividend.Security)
+	assert.Equal(t, "2.04 USD", dividend.Amount.String())
+	assert.Equal(t, "0.36 USD", dividend.Taxes.String())
+	assert.Equal(t, time.Date(2024, time.May, 16, 0, 0, 0, 0, time.UTC), dividend.Date)
+}
+
+// TestExport_InvalidRequest checks that Export returns a validation error without reading anything when the request is not valid
+func TestExport_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := &exportService{config: config.Config{}}
+
+	// Act
+	err := service.Export(context.Background(), "user-a", "portfolio-a", models.ExportReq{Format: models.ExportFormatCSV}, &bytes.Buffer{})
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
+
+// TestExport_PortfolioNotFound checks that Export returns a non existent error when the portfolio does not exist
+func TestExport_PortfolioNotFound(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	service := &exportService{config: config.Config{}, portfolioRepository: portfolioRepositoryMock}
+	var w bytes.Buffer
+
+	// Act
+	err := service.Export(context.Background(), "user-a", "portfolio-a", models.ExportReq{Format: models.ExportFormatJSON}, &w)
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(fmt.Errorf("ID portfolio-a not found")), err)
+	assert.Empty(t, w.String())
+}
+
+// TestExport_OtherUser checks that Export returns an unauthorized error without writing anything when the portfolio belongs to another user
+func TestExport_OtherUser(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+	service := &exportService{config: config.Config{}, portfolioRepository: portfolioRepositoryMock}
+	var w bytes.Buffer
+
+	// Act
+	err := service.Export(context.Background(), "user-b", "portfolio-a", models.ExportReq{Format: models.ExportFormatJSON}, &w)
+
+	// Assert
+	assert.Equal(t, wrappers.NewUnauthorizedErr(fmt.Errorf("portfolio portfolio-a belongs to another user")), err)
+	assert.Empty(t, w.String())
+}
+
+// restoreArchive is an archive of a portfolio with a holding at Degiro, a buy of a known stock, a buy of a stock unknown to this
+// server, a dividend of each and a realized gain
+const restoreArchive = `{"version":1,"exported_at":"2024-06-01T00:00:00Z",
+"portfolio":{"name":"Retirement","base_currency":"EUR","performence_calculation":1},
+"holdings":[{"holdingid":"old-holding","brokerid":"old-broker","broker":"degiro","name":"Degiro"}],
+"transactions":[
+	{"transactionid":"t1","holdingid":"old-holding","stockid":"stock-aapl","symbol":"AAPL","quantity":"10","transaction_price":{"amount":"170","currency":"USD"},"transaction_date":"2024-03-05T00:00:00Z"},
+	{"transactionid":"t2","holdingid":"old-holding","stockid":"old-stock","symbol":"IWDA","isin":"IE00B4L5Y983","name":"iShares Core MSCI World","quantity":"4","transaction_price":{"amount":"80","currency":"EUR"},"transaction_date":"2024-03-06T00:00:00Z"}
+],
+"dividends":[
+	{"dividendid":"d1","stockid":"stock-aapl","dividend_per_share":{"amount":"0.24","currency":"USD"},"ex_date":"2024-05-10T00:00:00Z","pay_date":"2024-05-16T00:00:00Z"},
+	{"dividendid":"d2","stockid":"old-stock","dividend_per_share":{"amount":"0.5","currency":"EUR"},"ex_date":"2024-05-10T00:00:00Z"}
+],
+"realized_gains":[{"buy_transactionid":"t1","sell_transactionid":"t3"}]}`
+
+// restoreMocks returns an export service with the repositories read by the validation of restoreArchive
+func restoreMocks(t *testing.T) (*exportService, *mocks.PortfolioRepository, *mocks.HoldingRepository, *mocks.TransactionRepository, *mocks.StockRepository, *mocks.DividendRepository, *mocks.LotRepository) {
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "user-b").Return(&entities.User{UserID: "user-b"}, nil).Once()
+
+	brokerRepositoryMock := mocks.NewBrokerRepository(t)
+	brokerRepositoryMock.On(testutils.FunctionName(t, ports.BrokerRepository.GetByID), context.Background(), "old-broker").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	brokerRepositoryMock.On(testutils.FunctionName(t, ports.BrokerRepository.GetBySlug), context.Background(), "degiro").Return(&entities.Broker{BrokerID: "broker-a", Slug: "degiro"}, nil).Once()
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	dividendRepositoryMock := mocks.NewDividendRepository(t)
+	lotRepositoryMock := mocks.NewLotRepository(t)
+
+	service := &exportService{
+		config:                   config.Config{},
+		userRepository:           userRepositoryMock,
+		portfolioRepository:      portfolioRepositoryMock,
+		holdingRepository:        holdingRepositoryMock,
+		brokerRepository:         brokerRepositoryMock,
+		transactionRepository:    transactionRepositoryMock,
+		stockRepository:          stockRepositoryMock,
+		cryptoCurrencyRepository: mocks.NewCryptoCurrencyRepository(t),
+		dividendRepository:       dividendRepositoryMock,
+		lotRepository:            lotRepositoryMock,
+	}
+	return service, portfolioRepositoryMock, holdingRepositoryMock, transactionRepositoryMock, stockRepositoryMock, dividendRepositoryMock, lotRepositoryMock
+}
+
+// TestRestore_Ok checks that Restore creates the portfolio of an archive for the user with its holdings, transactions and new
+// dividends, creating the stocks not found and rebuilding the ledger
+func TestRestore_Ok(t *testing.T) {
+	// Arrange
+	service, portfolioRepositoryMock, holdingRepositoryMock, transactionRepositoryMock, stockRepositoryMock, dividendRepositoryMock, lotRepositoryMock := restoreMocks(t)
+
+	var portfolio entities.Portfolio
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.Create), context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+		portfolio = args.Get(1).(entities.Portfolio)
+	}).Return("portfolio-b", nil).Once()
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-b").Return(&entities.Portfolio{PortfolioID: "portfolio-b", PerformenceCalculation: entities.CostBasisFIFO}, nil).Once()
+
+	var holding entities.Holding
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.Create), context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+		holding = args.Get(1).(entities.Holding)
+	}).Return("holding-b", nil).Once()
+
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-aapl").Return(&entities.Stock{StockID: "stock-aapl"}, nil).Once()
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "old-stock").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return([]interface{}{&entities.Stock{StockID: "stock-aapl", TickerSymbol: "AAPL"}}, nil).Once()
+	var stock entities.Stock
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.Create), context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+		stock = args.Get(1).(entities.Stock)
+	}).Return("stock-iwda", nil).Once()
+
+	var transactions []interface{}
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.CreateMany), context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+		transactions = append([]interface{}{}, args.Get(1).([]interface{})...)
+	}).Return([]string{"transaction-1", "transaction-2", "transaction-3"}, nil).Once()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-b").Return([]interface{}{
+		&entities.Transaction{TransactionID: "transaction-1", StockID: "stock-aapl", Quantity: decimal.NewFromInt(10), TransactionPrice: entities.NewMoney(decimal.NewFromInt(170), "USD"), TransactionDate: time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)},
+	}, nil).Once()
+
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-aapl"}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Dividend{StockID: "stock-aapl", ExDate: time.Date(2024, time.May, 10, 0, 0, 0, 0, time.UTC), PayDate: time.Date(2024, time.May, 16, 0, 0, 0, 0, time.UTC)},
+	}, nil).Once()
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-iwda"}, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	var dividend entities.Dividend
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Create), context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+		dividend = args.Get(1).(entities.Dividend)
+	}).Return("dividend-b", nil).Once()
+
+	lotRepositoryMock.On(testutils.FunctionName(t, ports.LotRepository.ReplaceLedger), context.Background(), "portfolio-b", mock.Anything, mock.Anything).Return(nil).Once()
+
+	// Act
+	resp, err := service.Restore(context.Background(), "user-b", models.RestoreReq{Format: models.ExportFormatJSON}, strings.NewReader(restoreArchive))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.ArchiveRestoreResp{PortfolioID: "portfolio-b", Holdings: 1, Transactions: 2, Dividends: 1, DuplicateDividends: 1, NewStocks: []string{"IWDA"}, NewCryptocurrencies: []string{}}, resp)
+	assert.Equal(t, "user-b", portfolio.Userid)
+	assert.Equal(t, "Retirement", portfolio.Name)
+	assert.Equal(t, "portfolio-b", holding.PortfolioID)
+	assert.Equal(t, "broker-a", holding.BrokerID)
+	assert.Equal(t, entities.Stock{HoldingID: "holding-b", Extid: "IE00B4L5Y983", TickerSymbol: "IWDA", CompanyName: "iShares Core MSCI World", Slug: "iwda", CreatedAt: stock.CreatedAt, UpdatedAt: stock.UpdatedAt}, stock)
+	assert.Len(t, transactions, 2)
+	assert.Equal(t, "holding-b", transactions[0].(entities.Transaction).HoldingID)
+	assert.Equal(t, "stock-aapl", transactions[0].(entities.Transaction).StockID)
+	assert.Equal(t, "stock-iwda", transactions[1].(entities.Transaction).StockID)
+	assert.Equal(t, "stock-iwda", dividend.StockID)
+	assert.Equal(t, "0.5", dividend.DividendPerShare.Amount.String())
+}
+
+// TestRestore_PortfolioPerformance checks that Restore converts a Portfolio Performance file into a portfolio with a holding per
+// securities account at the broker it is named after, its trades priced in the currency of their security, its dividends gross of
+// taxes, the cash transactions of its deposit account and its transfers skipped
+func TestRestore_PortfolioPerformance(t *testing.T) {
+	// Arrange
+	date := time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC)
+	client := entities.PPClient{
+		Version:      57,
+		BaseCurrency: "EUR",
+		Securities: []entities.PPSecurity{
+			{UUID: "sec-apple", Name: "Apple Inc.", CurrencyCode: "USD", ISIN: "US0378331005", TickerSymbol: "AAPL", Feed: "YAHOO"},
+			{UUID: "sec-btc", Name: "Bitcoin", CurrencyCode: "EUR", TickerSymbol: "BTC", Feed: entities.PPFeedCoinGecko},
+		},
+		Accounts: []entities.PPAccount{{
+			UUID: "acc-cash", Name: "Cash", CurrencyCode: "EUR",
+			Transactions: []entities.PPTransaction{
+				{UUID: "tx-div", Type: entities.PPTransactionDividends, Date: date.AddDate(0, 2, 0), Security: "sec-apple", Shares: decimal.NewFromInt(10), Amount: entities.NewMoney(decimal.RequireFromString("17.85"), "EUR"), Taxes: entities.NewMoney(decimal.RequireFromString("3.15"), "EUR")},
+				{UUID: "tx-fees", Type: entities.PPTransactionFees, Date: date.AddDate(0, 3, 0), Amount: entities.NewMoney(decimal.RequireFromString("2.5"), "EUR")},
+			},
+		}},
+		Portfolios: []entities.PPPortfolio{{
+			UUID: "pf-main", Name: "DEGIRO Depot", ReferenceAccount: "acc-cash",
+			Transactions: []entities.PPTransaction{
+				{UUID: "tx-buy", Type: entities.PPTransactionBuy, Date: date, Security: "sec-apple", Shares: decimal.NewFromInt(10), Amount: entities.NewMoney(decimal.NewFromInt(1705), "EUR"), Fees: entities.NewMoney(decimal.NewFromInt(5), "EUR"), GrossValue: entities.NewMoney(decimal.NewFromInt(1870), "USD")},
+				{UUID: "tx-btc", Type: entities.PPTransactionSell, Date: date, Security: "sec-btc", Shares: decimal.RequireFromString("0.5"), Amount: entities.NewMoney(decimal.NewFromInt(20000), "EUR")},
+				{UUID: "tx-transfer", Type: entities.PPTransactionTransferIn, Date: date, Security: "sec-apple", Shares: decimal.NewFromInt(1), Amount: entities.NewMoney(decimal.NewFromInt(170), "EUR")},
+			},
+		}},
+	}
+	ppCodecMock := mocks.NewPortfolioPerformanceCodec(t)
+	ppCodecMock.On(testutils.FunctionName(t, ports.PortfolioPerformanceCodec.Decode), mock.Anything).Return(client, nil).Once()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "user-b").Return(&entities.User{UserID: "user-b"}, nil).Once()
+
+	brokerRepositoryMock := mocks.NewBrokerRepository(t)
+	brokerRepositoryMock.On(testutils.FunctionName(t, ports.BrokerRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Broker{BrokerID: "broker-a", Name: "DEGIRO", Slug: "degiro"},
+		&entities.Broker{BrokerID: "broker-b", Name: "Revolut", Slug: "revolut"},
+	}, nil).Once()
+	brokerRepositoryMock.On(testutils.FunctionName(t, ports.BrokerRepository.GetBySlug), context.Background(), "degiro").Return(&entities.Broker{BrokerID: "broker-a", Slug: "degiro"}, nil).Once()
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	var portfolio entities.Portfolio
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.Create), context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+		portfolio = args.Get(1).(entities.Portfolio)
+	}).Return("portfolio-b", nil).Once()
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-b").Return(&entities.Portfolio{PortfolioID: "portfolio-b"}, nil).Once()
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	var holding entities.Holding
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.Create), context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+		holding = args.Get(1).(entities.Holding)
+	}).Return("holding-b", nil).Once()
+
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "sec-apple").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return([]interface{}{&entities.Stock{StockID: "stock-aapl", TickerSymbol: "AAPL", Extid: "US0378331005"}}, nil).Once()
+
+	cryptoCurrencyRepositoryMock := mocks.NewCryptoCurrencyRepository(t)
+	cryptoCurrencyRepositoryMock.On(testutils.FunctionName(t, ports.CryptoCurrencyRepository.GetByID), context.Background(), "sec-btc").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	cryptoCurrencyRepositoryMock.On(testutils.FunctionName(t, ports.CryptoCurrencyRepository.Get), context.Background(), map[string]interface{}{"symbol": "BTC"}, (*int)(nil), (*int)(nil)).Return([]interface{}{&entities.CryptoCurrency{CryptoCurrencyID: "crypto-btc", Symbol: "BTC"}}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	var transactions []interface{}
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.CreateMany), context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+		transactions = append([]interface{}{}, args.Get(1).([]interface{})...)
+	}).Return([]string{"transaction-1", "transaction-2", "transaction-3"}, nil).Once()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-b").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	dividendRepositoryMock := mocks.NewDividendRepository(t)
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-aapl"}, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	var dividend entities.Dividend
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Create), context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+		dividend = args.Get(1).(entities.Dividend)
+	}).Return("dividend-b", nil).Once()
+
+	lotRepositoryMock := mocks.NewLotRepository(t)
+	lotRepositoryMock.On(testutils.FunctionName(t, ports.LotRepository.ReplaceLedger), context.Background(), "portfolio-b", mock.Anything, mock.Anything).Return(nil).Once()
+
+	service := &exportService{
+		config:                   config.Config{},
+		userRepository:           userRepositoryMock,
+		portfolioRepository:      portfolioRepositoryMock,
+		holdingRepository:        holdingRepositoryMock,
+		brokerRepository:         brokerRepositoryMock,
+		transactionRepository:    transactionRepositoryMock,
+		stockRepository:          stockRepositoryMock,
+		cryptoCurrencyRepository: cryptoCurrencyRepositoryMock,
+		dividendRepository:       dividendRepositoryMock,
+		lotRepository:            lotRepositoryMock,
+		ppCodec:                  ppCodecMock,
+	}
+
+	// Act
+	resp, err := service.Restore(context.Background(), "user-b", models.RestoreReq{Format: models.ExportFormatPP}, strings.NewReader("<client/>"))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.ArchiveRestoreResp{PortfolioID: "portfolio-b", Holdings: 1, Transactions: 3, Dividends: 1, NewStocks: []string{}, NewCryptocurrencies: []string{}, SkippedTransfers: 1}, resp)
+	assert.Equal(t, "Portfolio Performance", portfolio.Name)
+	assert.Equal(t, "EUR", portfolio.BaseCurrency)
+	assert.Equal(t, "broker-a", holding.BrokerID)
+	assert.Equal(t, "DEGIRO Depot", holding.Name)
+
+	assert.Len(t, transactions, 3)
+	buy := transactions[0].(entities.Transaction)
+	assert.Equal(t, "stock-aapl", buy.StockID)
+	assert.Equal(t, "10", buy.Quantity.String())
+	assert.Equal(t, "187.55 USD", buy.TransactionPrice.String())
+	assert.Equal(t, "tx-buy", buy.Extid)
+	sale := transactions[1].(entities.Transaction)
+	assert.Equal(t, "crypto-btc", sale.CryptocurrencyID)
+	assert.Equal(t, "-0.5", sale.Quantity.String())
+	assert.Equal(t, "40000 EUR", sale.TransactionPrice.String())
+	fee := transactions[2].(entities.Transaction)
+	assert.Equal(t, entities.TransactionTypeFee, fee.TransactionType)
+	assert.Empty(t, fee.StockID)
+	assert.Equal(t, "2.5 EUR", fee.TransactionPrice.String())
+
+	assert.Equal(t, "stock-aapl", dividend.StockID)
+	assert.Equal(t, "2.1 EUR", dividend.DividendPerShare.String())
+	assert.Equal(t, date.AddDate(0, 2, 0), dividend.ExDate)
+}
+
+// TestRestore_PortfolioPerformanceNoBroker checks that Restore returns a validation error when a securities account of a Portfolio
+// Performance file is not named after a broker and the request has no broker
+func TestRestore_PortfolioPerformanceNoBroker(t *testing.T) {
+	// Arrange
+	ppCodecMock := mocks.NewPortfolioPerformanceCodec(t)
+	ppCodecMock.On(testutils.FunctionName(t, ports.PortfolioPerformanceCodec.Decode), mock.Anything).Return(entities.PPClient{Version: 57, Portfolios: []entities.PPPortfolio{{UUID: "pf", Name: "Savings"}}}, nil).Once()
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "user-b").Return(&entities.User{UserID: "user-b"}, nil).Once()
+	brokerRepositoryMock := mocks.NewBrokerRepository(t)
+	brokerRepositoryMock.On(testutils.FunctionName(t, ports.BrokerRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return([]interface{}{&entities.Broker{BrokerID: "broker-a", Name: "DEGIRO", Slug: "degiro"}}, nil).Once()
+	service := &exportService{config: config.Config{}, userRepository: userRepositoryMock, brokerRepository: brokerRepositoryMock, ppCodec: ppCodecMock}
+
+	// Act
+	_, err := service.Restore(context.Background(), "user-b", models.RestoreReq{Format: models.ExportFormatPP}, strings.NewReader("<client/>"))
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, "securities account Savings is not named after a broker, the broker of the restore must be provided", err.Error())
+}
+
+// TestRestore_InvalidArchive checks that Restore returns a validation error and creates nothing when the archive is not valid
+func TestRestore_InvalidArchive(t *testing.T) {
+	cases := map[string]string{
+		`{"version":2,"portfolio":{"name":"Retirement"}}`:                                    "unsupported archive version 2, expected 1",
+		`{"portfolio":{"name":"Retirement"}}`:                                                "archive version cannot be empty",
+		`{"version":1,"holdings":[]}`:                                                        "archive has no portfolio",
+		`{"version":1,"portfolio":{"name":"Retirement"},"transactions":[{"holdingid":"h"}]}`: "transaction 1: either stock id or cryptocurrency id must be provided",
+		`{"version":1,"portfolio":{"name":"Retirement"},"transactions":[{"holdingid":"h","stockid":"s","quantity":"1","transaction_price":{"amount":"1","currency":"USD"},"transaction_date":"2024-03-05T00:00:00Z"}]}`: "transaction 1: holding h not found in the archive",
+		`{"version":1,"portfolio":{"name":" "}}`:                                                      "portfolio name cannot be empty",
+		`{"version":1,"portfolio":{"name":"Retirement"},"holdings":[{"holdingid":"h","broker":"x"}]}`: "holding 1: broker x not found",
+		`[1,2]`: "invalid archive: expected {",
+	}
+
+	for archive, expectedError := range cases {
+		// Arrange
+		userRepositoryMock := mocks.NewUserRepository(t)
+		userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "user-b").Return(&entities.User{UserID: "user-b"}, nil).Once()
+		brokerRepositoryMock := mocks.NewBrokerRepository(t)
+		brokerRepositoryMock.On(testutils.FunctionName(t, ports.BrokerRepository.GetBySlug), context.Background(), "x").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Maybe()
+		service := &exportService{config: config.Config{}, userRepository: userRepositoryMock, brokerRepository: brokerRepositoryMock}
+
+		// Act
+		_, err := service.Restore(context.Background(), "user-b", models.RestoreReq{Format: models.ExportFormatJSON}, strings.NewReader(archive))
+
+		// Assert
+		assert.IsType(t, wrappers.ValidationErr, err)
+		if err != nil {
+			assert.Contains(t, err.Error(), expectedError)
+		}
+	}
+}
+
+// TestRestore_UndoOnError checks that Restore deletes what it created when writing the archive fails
+func TestRestore_UndoOnError(t *testing.T) {
+	// Arrange
+	service, portfolioRepositoryMock, holdingRepositoryMock, transactionRepositoryMock, stockRepositoryMock, dividendRepositoryMock, lotRepositoryMock := restoreMocks(t)
+
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.Create), context.Background(), mock.Anything).Return("portfolio-b", nil).Once()
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-b").Return(&entities.Portfolio{PortfolioID: "portfolio-b"}, nil).Once()
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.Delete), context.Background(), "portfolio-b").Return(nil).Once()
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.Create), context.Background(), mock.Anything).Return("holding-b", nil).Once()
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.Delete), context.Background(), "holding-b").Return(nil).Once()
+
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-aapl").Return(&entities.Stock{StockID: "stock-aapl"}, nil).Once()
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "old-stock").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.Create), context.Background(), mock.Anything).Return("stock-iwda", nil).Once()
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.Delete), context.Background(), "stock-iwda").Return(nil).Once()
+
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Get), context.Background(), mock.Anything, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Twice()
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Create), context.Background(), mock.Anything).Return("dividend-b", nil).Once()
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Create), context.Background(), mock.Anything).Return("dividend-c", nil).Once()
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Delete), context.Background(), "dividend-b").Return(nil).Once()
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Delete), context.Background(), "dividend-c").Return(nil).Once()
+
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.CreateMany), context.Background(), mock.Anything).Return([]string{"transaction-1", "transaction-2"}, nil).Once()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-b").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Delete), context.Background(), "transaction-1").Return(nil).Once()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Delete), context.Background(), "transaction-2").Return(nil).Once()
+
+	expectedError := "replace error"
+	lotRepositoryMock.On(testutils.FunctionName(t, ports.LotRepository.ReplaceLedger), context.Background(), "portfolio-b", mock.Anything, mock.Anything).Return(fmt.Errorf(expectedError)).Once()
+
+	// Act
+	_, err := service.Restore(context.Background(), "user-b", models.RestoreReq{Format: models.ExportFormatJSON}, strings.NewReader(restoreArchive))
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestRestore_UserNotFound checks that Restore returns a non existent error when the user does not exist
+func TestRestore_UserNotFound(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "user-b").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	service := &exportService{config: config.Config{}, userRepository: userRepositoryMock}
+
+	// Act
+	_, err := service.Restore(context.Background(), "user-b", models.RestoreReq{Format: models.ExportFormatJSON}, strings.NewReader(restoreArchive))
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(fmt.Errorf("ID user-b not found")), err)
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+const (
+	// defaultFxLookback is used when no lookback is configured, long enough to bridge weekends and holidays
+	defaultFxLookback = 7 * oneDay
+	// fxRatePlaces is the number of decimal places kept by inverted and crossed rates
+	fxRatePlaces = 10
+	// convertedAmountPlaces is the number of decimal places kept by converted amounts
+	convertedAmountPlaces = 4
+	// fxRateSourceDerived is the source of rates inverted or crossed from the stored ones
+	fxRateSourceDerived = "derived"
+)
+
+// fxRateService adapter of an fx rate service
+type fxRateService struct {
+	config     config.Config
+	repository ports.FxRateRepository
+}
+
+// NewFxRateService creates a new fx rate service
+func NewFxRateService(cfg config.Config, repo ports.FxRateRepository) ports.FxRateService {
+	return &fxRateService{
+		config:     cfg,
+		repository: repo,
+	}
+}
+
+// CreateMany fx rates
+func (s *fxRateService) CreateMany(ctx context.Context, rates []models.CreateFxRateReq) (resp models.MultiCreationResp, err error) {
+	var create []interface{}
+	now := time.Now().UTC()
+
+	for _, rate := range rates {
+		if err = rate.Validate(); err != nil {
+			return
+		}
+
+		rate.RateDate = startOfDay(rate.RateDate)
+		rate.CreatedAt = now
+		rate.UpdatedAt = now
+
+		create = append(create, entities.FxRate(rate))
+	}
+
+	insertedIDs, err := s.repository.CreateMany(ctx, create)
+	if err != nil {
+		return
+	}
+
+	resp = models.MultiCreationResp{
+		InsertedIDs: insertedIDs,
+	}
+	return
+}
+
+// GetRate returns the rate of one unit of the base currency in the quote currency on a date. When no rate was published
+// on that date the nearest previous one is used, and pairs without a stored rate are inverted or crossed through the pivot currency.
+func (s *fxRateService) GetRate(ctx context.Context, baseCurrency, quoteCurrency string, date time.Time) (resp models.FxRateResp, err error) {
+	if date.IsZero() {
+		date = time.Now().UTC()
+	}
+
+	rate, err := s.rate(ctx, baseCurrency, quoteCurrency, date)
+	if err != nil {
+		return
+	}
+
+	resp = models.FxRateResp(rate)
+	return
+}
+
+// Convert returns an amount of money in another currency at the rate of a date
+func (s *fxRateService) Convert(ctx context.Context, amount entities.Money, currency string, date time.Time) (entities.Money, error) {
+	if amount.Currency == currency || currency == "" {
+		return amount, nil
+	}
+	if amount.IsZero() && amount.Currency == "" {
+		return entities.NewMoney(decimal.Zero, currency), nil
+	}
+
+	rate, err := s.rate(ctx, amount.Currency, currency, date)
+	if err != nil {
+		return entities.Money{}, err
+	}
+
+	return entities.NewMoney(amount.Amount.Mul(rate.Rate).Round(convertedAmountPlaces), currency), nil
+}
+
+// rate returns the rate of from in to on a date, trying the stored pair, its inverse and finally a cross through the pivot currency
+func (s *fxRateService) rate(ctx context.Context, from, to string, date time.Time) (entities.FxRate, error) {
+	if from == to {
+		return entities.FxRate{BaseCurrency: from, QuoteCurrency: to, RateDate: startOfDay(date), Rate: decimal.NewFromInt(1), Source: fxRateSourceDerived}, nil
+	}
+
+	rate, err := s.pairRate(ctx, from, to, date)
+	if err == nil || !errors.Is(err, wrappers.NonExistentErr) {
+		return rate, err
+	}
+
+	pivot := s.config.FX.PivotCurrency
+	if pivot == "" || pivot == from || pivot == to {
+		return entities.FxRate{}, fxRateNotFound(from, to, date)
+	}
+
+	toPivot, err := s.pairRate(ctx, from, pivot, date)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = fxRateNotFound(from, to, date)
+		}
+		return entities.FxRate{}, err
+	}
+	fromPivot, err := s.pairRate(ctx, pivot, to, date)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = fxRateNotFound(from, to, date)
+		}
+		return entities.FxRate{}, err
+	}
+
+	rateDate := toPivot.RateDate
+	if fromPivot.RateDate.Before(rateDate) {
+		rateDate = fromPivot.RateDate
+	}
+	return entities.FxRate{
+		BaseCurrency:  from,
+		QuoteCurrency: to,
+		RateDate:      rateDate,
+		Rate:          toPivot.Rate.Mul(fromPivot.Rate).Round(fxRatePlaces),
+		Source:        fxRateSourceDerived,
+	}, nil
+}
+
+// pairRate returns the latest stored rate of a pair on or before a date, inverting the opposite pair when the pair itself
+// is not stored. Rates published longer than the configured lookback before the date are not used.
+func (s *fxRateService) pairRate(ctx context.Context, from, to string, date time.Time) (entities.FxRate, error) {
+	result, err := s.repository.GetLatest(ctx, from, to, date)
+	if err == nil {
+		if rate := *(result.(*entities.FxRate)); s.fresh(rate, date) {
+			return rate, nil
+		}
+	} else if !errors.Is(err, wrappers.NonExistentErr) {
+		return entities.FxRate{}, err
+	}
+
+	result, err = s.repository.GetLatest(ctx, to, from, date)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = fxRateNotFound(from, to, date)
+		}
+		return entities.FxRate{}, err
+	}
+
+	inverse := *(result.(*entities.FxRate))
+	if !s.fresh(inverse, date) {
+		return entities.FxRate{}, fxRateNotFound(from, to, date)
+	}
+	return entities.FxRate{
+		BaseCurrency:  from,
+		QuoteCurrency: to,
+		RateDate:      inverse.RateDate,
+		Rate:          decimal.NewFromInt(1).DivRound(inverse.Rate, fxRatePlaces),
+		Source:        fxRateSourceDerived,
+	}, nil
+}
+
+// fresh reports whether a rate was published within the configured lookback before the date it is used for
+func (s *fxRateService) fresh(rate entities.FxRate, date time.Time) bool {
+	lookback := s.config.FX.Lookback.Duration
+	if lookback <= 0 {
+		lookback = defaultFxLookback
+	}
+	return date.Sub(rate.RateDate) <= lookback
+}
+
+// fxRateNotFound returns the error of a missing rate
+func fxRateNotFound(from, to string, date time.Time) error {
+	return wrappers.NewNonExistentErr(fmt.Errorf("no %s/%s FX rate found on or before %s", from, to, date.Format("2006-01-02")))
+}
+
+// baseCurrency returns the currency a portfolio reports in: its own base currency, or else the base currency of its owner.
+// An empty currency means amounts are reported in the currency of each instrument.
+func baseCurrency(ctx context.Context, userRepo ports.UserRepository, portfolio entities.Portfolio) (string, error) {
+	if portfolio.BaseCurrency != "" || portfolio.Userid == "" {
+		return portfolio.BaseCurrency, nil
+	}
+
+	user, err := userRepo.GetByID(ctx, portfolio.Userid)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return "", err
+	}
+
+	return user.(*entities.User).BaseCurrency, nil
+}
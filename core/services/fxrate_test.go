@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestNewFxRateService_Ok checks that NewFxRateService creates a new fxRateService struct
+func TestNewFxRateService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	fxRateRepositoryMock := mocks.NewFxRateRepository(t)
+
+	// Act
+	service := NewFxRateService(cfg, fxRateRepositoryMock)
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestCreateManyFxRates_Ok checks that CreateMany does not return an error when a valid request is received
+func TestCreateManyFxRates_Ok(t *testing.T) {
+	// Arrange
+	req := []models.CreateFxRateReq{
+		{
+			BaseCurrency:  "EUR",
+			QuoteCurrency: "CZK",
+			RateDate:      time.Date(2023, time.August, 3, 15, 0, 0, 0, time.UTC),
+			Rate:          decimal.RequireFromString("24.105"),
+		},
+	}
+	expectedResponse := models.MultiCreationResp{
+		InsertedIDs: []string{"new-id"},
+	}
+
+	fxRateRepositoryMock := mocks.NewFxRateRepository(t)
+	fxRateRepositoryMock.On(testutils.FunctionName(t, ports.FxRateRepository.CreateMany), context.Background(), mock.MatchedBy(func(rates []interface{}) bool {
+		return len(rates) == 1 && rates[0].(entities.FxRate).RateDate.Equal(time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC))
+	})).Return(expectedResponse.InsertedIDs, nil).Once()
+
+	service := &fxRateService{
+		config:     config.Config{},
+		repository: fxRateRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.CreateMany(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResponse, resp)
+}
+
+// TestCreateManyFxRates_InvalidRequest checks that CreateMany returns an error when a rate is not valid
+func TestCreateManyFxRates_InvalidRequest(t *testing.T) {
+	// Arrange
+	req := []models.CreateFxRateReq{{}}
+
+	service := &fxRateService{
+		config: config.Config{},
+	}
+
+	// Act
+	_, err := service.CreateMany(context.Background(), req)
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
+
+// TestGetRate_Ok checks that GetRate returns the stored rate of the pair published on or before the date
+func TestGetRate_Ok(t *testing.T) {
+	// Arrange
+	date := time.Date(2023, time.August, 6, 0, 0, 0, 0, time.UTC)
+	stored := entities.FxRate{FxRateID: "rate-id", BaseCurrency: "EUR", QuoteCurrency: "CZK", RateDate: time.Date(2023, time.August, 4, 0, 0, 0, 0, time.UTC), Rate: decimal.RequireFromString("24.105"), Source: "ecb"}
+
+	fxRateRepositoryMock := mocks.NewFxRateRepository(t)
+	fxRateRepositoryMock.On(testutils.FunctionName(t, ports.FxRateRepository.GetLatest), context.Background(), "EUR", "CZK", date).Return(&stored, nil).Once()
+
+	service := &fxRateService{
+		config:     config.Config{},
+		repository: fxRateRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetRate(context.Background(), "EUR", "CZK", date)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.FxRateResp(stored), resp)
+}
+
+// TestGetRate_Inverse checks that GetRate inverts the opposite pair when the pair itself is not stored
+func TestGetRate_Inverse(t *testing.T) {
+	// Arrange
+	date := time.Date(2023, time.August, 4, 0, 0, 0, 0, time.UTC)
+
+	fxRateRepositoryMock := mocks.NewFxRateRepository(t)
+	fxRateRepositoryMock.On(testutils.FunctionName(t, ports.FxRateRepository.GetLatest), context.Background(), "CZK", "EUR", date).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	fxRateRepositoryMock.On(testutils.FunctionName(t, ports.FxRateRepository.GetLatest), context.Background(), "EUR", "CZK", date).Return(&entities.FxRate{BaseCurrency: "EUR", QuoteCurrency: "CZK", RateDate: date, Rate: decimal.NewFromInt(25)}, nil).Once()
+
+	service := &fxRateService{
+		config:     config.Config{},
+		repository: fxRateRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetRate(context.Background(), "CZK", "EUR", date)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "0.04", resp.Rate.String())
+	assert.Equal(t, fxRateSourceDerived, resp.Source)
+	assert.Equal(t, date, resp.RateDate)
+}
+
+// TestGetRate_CrossViaPivot checks that GetRate crosses the rates of both currencies against the pivot currency
+func TestGetRate_CrossViaPivot(t *testing.T) {
+	// Arrange
+	date := time.Date(2023, time.August, 4, 0, 0, 0, 0, time.UTC)
+	cfg := config.Config{}
+	cfg.FX.PivotCurrency = "EUR"
+
+	fxRateRepositoryMock := mocks.NewFxRateRepository(t)
+	fxRateRepositoryMock.On(testutils.FunctionName(t, ports.FxRateRepository.GetLatest), context.Background(), "USD", "CZK", date).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	fxRateRepositoryMock.On(testutils.FunctionName(t, ports.FxRateRepository.GetLatest), context.Background(), "CZK", "USD", date).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	fxRateRepositoryMock.On(testutils.FunctionName(t, ports.FxRateRepository.GetLatest), context.Background(), "USD", "EUR", date).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	fxRateRepositoryMock.On(testutils.FunctionName(t, ports.FxRateRepository.GetLatest), context.Background(), "EUR", "USD", date).Return(&entities.FxRate{BaseCurrency: "EUR", QuoteCurrency: "USD", RateDate: date.Add(-oneDay), Rate: decimal.RequireFromString("1.25")}, nil).Once()
+	fxRateRepositoryMock.On(testutils.FunctionName(t, ports.FxRateRepository.GetLatest), context.Background(), "EUR", "CZK", date).Return(&entities.FxRate{BaseCurrency: "EUR", QuoteCurrency: "CZK", RateDate: date, Rate: decimal.NewFromInt(25)}, nil).Once()
+
+	service := &fxRateService{
+		config:     cfg,
+		repository: fxRateRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetRate(context.Background(), "USD", "CZK", date)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "20", resp.Rate.String())
+	assert.Equal(t, date.Add(-oneDay), resp.RateDate)
+	assert.Equal(t, fxRateSourceDerived, resp.Source)
+}
+
+// TestGetRate_Stale checks that GetRate returns an error when the latest rate is older than the lookback
+func TestGetRate_Stale(t *testing.T) {
+	// Arrange
+	date := time.Date(2023, time.August, 30, 0, 0, 0, 0, time.UTC)
+	expectedError := "no EUR/CZK FX rate found on or before 2023-08-30"
+
+	fxRateRepositoryMock := mocks.NewFxRateRepository(t)
+	fxRateRepositoryMock.On(testutils.FunctionName(t, ports.FxRateRepository.GetLatest), context.Background(), "EUR", "CZK", date).Return(&entities.FxRate{BaseCurrency: "EUR", QuoteCurrency: "CZK", RateDate: time.Date(2023, time.August, 4, 0, 0, 0, 0, time.UTC), Rate: decimal.NewFromInt(25)}, nil).Once()
+	fxRateRepositoryMock.On(testutils.FunctionName(t, ports.FxRateRepository.GetLatest), context.Background(), "CZK", "EUR", date).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &fxRateService{
+		config:     config.Config{},
+		repository: fxRateRepositoryMock,
+	}
+
+	// Act
+	_, err := service.GetRate(context.Background(), "EUR", "CZK", date)
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetRate_GetLatestError checks that GetRate returns an error when the GetLatest function from the repository fails
+func TestGetRate_GetLatestError(t *testing.T) {
+	// Arrange
+	date := time.Date(2023, time.August, 4, 0, 0, 0, 0, time.UTC)
+	expectedError := "repository-error"
+
+	fxRateRepositoryMock := mocks.NewFxRateRepository(t)
+	fxRateRepositoryMock.On(testutils.FunctionName(t, ports.FxRateRepository.GetLatest), context.Background(), "EUR", "CZK", date).Return(nil, fmt.Errorf(expectedError)).Once()
+
+	service := &fxRateService{
+		config:     config.Config{},
+		repository: fxRateRepositoryMock,
+	}
+
+	// Act
+	_, err := service.GetRate(context.Background(), "EUR", "CZK", date)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestConvert_Ok checks that Convert returns the amount in the target currency at the rate of the date
+func TestConvert_Ok(t *testing.T) {
+	// Arrange
+	date := time.Date(2023, time.August, 4, 0, 0, 0, 0, time.UTC)
+
+	fxRateRepositoryMock := mocks.NewFxRateRepository(t)
+	fxRateRepositoryMock.On(testutils.FunctionName(t, ports.FxRateRepository.GetLatest), context.Background(), "EUR", "CZK", date).Return(&entities.FxRate{BaseCurrency: "EUR", QuoteCurrency: "CZK", RateDate: date, Rate: decimal.RequireFromString("24.105")}, nil).Once()
+
+	service := &fxRateService{
+		config:     config.Config{},
+		repository: fxRateRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Convert(context.Background(), entities.NewMoney(decimal.RequireFromString("10.5"), "EUR"), "CZK", date)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "253.1025 CZK", resp.String())
+}
+
+// TestConvert_SameCurrency checks that Convert returns the amount unchanged when it is already in the target currency
+func TestConvert_SameCurrency(t *testing.T) {
+	// Arrange
+	service := &fxRateService{
+		config: config.Config{},
+	}
+	amount := entities.NewMoney(decimal.RequireFromString("10.5"), "EUR")
+
+	// Act
+	resp, err := service.Convert(context.Background(), amount, "EUR", time.Now())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, amount, resp)
+}
+
+// TestBaseCurrency_FromUser checks that baseCurrency falls back to the base currency of the owner when the portfolio has none
+func TestBaseCurrency_FromUser(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "user-id").Return(&entities.User{UserID: "user-id", BaseCurrency: "CZK"}, nil).Once()
+
+	// Act
+	currency, err := baseCurrency(context.Background(), userRepositoryMock, entities.Portfolio{Userid: "user-id"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "CZK", currency)
+}
@@ -0,0 +1,844 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// importService adapter of a broker statement import service
+type importService struct {
+	config                config.Config
+	batchRepository       ports.ImportBatchRepository
+	brokerRepository      ports.BrokerRepository
+	portfolioRepository   ports.PortfolioRepository
+	holdingRepository     ports.HoldingRepository
+	transactionRepository ports.TransactionRepository
+	stockRepository       ports.StockRepository
+	dividendRepository    ports.DividendRepository
+	withholdingRepository ports.DividendWithholdingRepository
+	lotService            ports.LotService
+	parsers               map[string]ports.StatementParser
+}
+
+// NewImportService creates a new broker statement import service with the statement parsers by broker slug. The lot service refreshes
+// the lot ledger of a portfolio once an import into it is stored or rolled back.
+func NewImportService(cfg config.Config, batchRepo ports.ImportBatchRepository, brokerRepo ports.BrokerRepository, portfolioRepo ports.PortfolioRepository, holdingRepo ports.HoldingRepository, transactionRepo ports.TransactionRepository, stockRepo ports.StockRepository, dividendRepo ports.DividendRepository, withholdingRepo ports.DividendWithholdingRepository, lotService ports.LotService, parsers map[string]ports.StatementParser) ports.ImportService {
+	keyed := make(map[string]ports.StatementParser, len(parsers))
+	for slug, parser := range parsers {
+		keyed[brokerKey(slug)] = parser
+	}
+
+	return &importService{
+		config:                cfg,
+		batchRepository:       batchRepo,
+		brokerRepository:      brokerRepo,
+		portfolioRepository:   portfolioRepo,
+		holdingRepository:     holdingRepo,
+		transactionRepository: transactionRepo,
+		stockRepository:       stockRepo,
+		dividendRepository:    dividendRepo,
+		withholdingRepository: withholdingRepo,
+		lotService:            lotService,
+		parsers:               keyed,
+	}
+}
+
+// importPlan holds what importing the rows of a statement creates: the stocks not found, keyed by their ISIN or symbol, the
+// transactions and dividends, whose stock is named by that key until the stock is created, and the taxes withheld from the dividends
+type importPlan struct {
+	resolver     stockResolver
+	stocks       map[string]*entities.Stock
+	stockKeys    []string
+	transactions []entities.Transaction
+	dividends    []entities.Dividend
+	withholdings []plannedWithholding
+}
+
+// plannedWithholding is a tax withheld from a dividend the import creates, named by its index in the dividends of the plan, or from
+// a stored dividend, whose ID the withholding holds and whose index is -1
+type plannedWithholding struct {
+	withholding entities.DividendWithholding
+	dividend    int
+}
+
+// dividendPayment is the cash a dividend row of a statement paid into the holding, by the key of the dividend and the index of the row
+type dividendPayment struct {
+	key         string
+	row         int
+	transaction entities.Transaction
+}
+
+// paidDividend is a dividend of a stock paid on a day, either planned by the import, by its index in the dividends of the plan, or
+// stored, by its ID
+type paidDividend struct {
+	dividendID string
+	planned    int
+	currency   string
+}
+
+// stockResolver resolves the stocks of the rows and positions of a statement by their symbol or ISIN
+type stockResolver struct {
+	bySymbol map[string]string
+	byISIN   map[string]string
+}
+
+// resolve returns the ID of the stock with a symbol or else an ISIN, or the key the stock is created with when none is found
+func (r stockResolver) resolve(symbol, isin string) (string, bool) {
+	if stockID, ok := r.bySymbol[strings.ToUpper(symbol)]; ok && symbol != "" {
+		return stockID, true
+	}
+	if stockID, ok := r.byISIN[strings.ToUpper(isin)]; ok && isin != "" {
+		return stockID, true
+	}
+	key := strings.ToUpper(isin)
+	if key == "" {
+		key = strings.ToUpper(symbol)
+	}
+	return key, false
+}
+
+// Import reads a statement exported by a broker into a portfolio with the parser registered for the slug of the broker, or for the
+// format of the request when the statement was exported by a tracker such as Sharesight. Trades are stored as transactions of the
+// holding of the portfolio at the broker, their fees added to their cost when they are in the currency of their price, and the stocks
+// not found by their symbol or ISIN are created. Dividends are stored per share on their pay date, unless one is already recorded for
+// the stock on that day, and the taxes withheld from them are stored as withheld from the payment to the portfolio. The payments of
+// the dividends, net of the tax the statement withholds from them, and the other fees, deposits, withdrawals and interest are stored
+// as cash transactions of the holding, so that its cash balance includes them. A dry run returns the preview without storing
+// anything, and statements with invalid lines are not imported. The positions listed by the statement are reconciled with the shares
+// the holding at the broker will hold once the statement is imported.
+//
+// Every import is recorded as an import batch, which the transactions, dividends and withholdings it creates are linked to. A file
+// already imported into the portfolio is rejected, and the trades and cash transactions already recorded, by their reference at the
+// broker or by their content, are skipped. Only the user owning the portfolio can import into it.
+func (s *importService) Import(ctx context.Context, userID, portfolioID string, req models.ImportReq, file io.Reader) (resp models.ImportResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	result, err := s.brokerRepository.GetBySlug(ctx, strings.TrimSpace(req.Broker))
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("broker %s not found", req.Broker))
+		}
+		return
+	}
+	broker := *result.(*entities.Broker)
+	parser, ok := s.parsers[brokerKey(broker.Slug)]
+	if format := strings.TrimSpace(req.Format); format != "" {
+		if parser, ok = s.parsers[brokerKey(format)]; !ok {
+			err = wrappers.NewValidationErr(fmt.Errorf("no statement importer for format %s, available formats: %s", format, strings.Join(s.brokers(), ", ")))
+			return
+		}
+	}
+	if !ok {
+		err = wrappers.NewValidationErr(fmt.Errorf("no statement importer for broker %s, available brokers: %s", broker.Slug, strings.Join(s.brokers(), ", ")))
+		return
+	}
+
+	portfolio, transactions, err := loadPortfolioTransactions(ctx, s.portfolioRepository, s.transactionRepository, portfolioID)
+	if err != nil {
+		return
+	}
+	if portfolio.Userid != userID {
+		err = wrappers.NewUnauthorizedErr(fmt.Errorf("portfolio %s belongs to another user", portfolioID))
+		return
+	}
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return
+	}
+	fingerprint := fingerprintOf(content)
+	duplicateOf, err := s.importedBy(ctx, portfolioID, fingerprint)
+	if err != nil {
+		return
+	}
+
+	statement, err := parser.Parse(bytes.NewReader(content))
+	if err != nil {
+		return
+	}
+
+	resp = models.ImportResp{
+		PortfolioID:    portfolioID,
+		Broker:         broker.Slug,
+		DryRun:         req.DryRun,
+		DuplicateOf:    duplicateOf,
+		Parsed:         len(statement.Rows),
+		Skipped:        len(statement.Skipped),
+		Invalid:        len(statement.Invalid),
+		Rows:           []models.ImportRowResp{},
+		Issues:         importIssues(statement),
+		Positions:      []models.ImportPositionResp{},
+		NewStocks:      []string{},
+		TransactionIDs: []string{},
+		DividendIDs:    []string{},
+		WithholdingIDs: []string{},
+	}
+
+	plan, rows, err := s.plan(ctx, portfolioID, statement.Rows, transactions)
+	if err != nil {
+		return
+	}
+	resp.Rows = rows
+	resp.NewStocks = append(resp.NewStocks, plan.stockKeys...)
+	for _, row := range rows {
+		if row.Action == models.ImportActionDuplicate {
+			resp.Duplicates++
+		}
+	}
+	if len(statement.Positions) > 0 {
+		if resp.Positions, err = s.reconcile(ctx, portfolioID, broker, statement.Positions, transactions, plan); err != nil {
+			return
+		}
+		for _, position := range resp.Positions {
+			if position.Status == models.ImportPositionMismatched {
+				resp.Mismatches++
+			}
+		}
+	}
+
+	if req.DryRun {
+		return
+	}
+	if duplicateOf != "" {
+		err = wrappers.NewValidationErr(fmt.Errorf("statement already imported in batch %s", duplicateOf))
+		return
+	}
+	if resp.Invalid > 0 {
+		err = wrappers.NewValidationErr(fmt.Errorf("statement has %d invalid lines, run a dry run to list them", resp.Invalid))
+		return
+	}
+
+	now := time.Now().UTC()
+	resp.BatchID, err = s.batchRepository.Create(ctx, entities.ImportBatch{
+		PortfolioID: portfolioID,
+		BrokerID:    broker.BrokerID,
+		FileName:    strings.TrimSpace(req.FileName),
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		return
+	}
+
+	resp.TransactionIDs, resp.DividendIDs, resp.WithholdingIDs, err = s.store(ctx, portfolioID, resp.BatchID, broker, plan)
+	if err != nil {
+		// The batch links what was stored before the error, so rolling it back leaves the portfolio as it was
+		if _, rollbackErr := s.batchRepository.Rollback(ctx, resp.BatchID); rollbackErr != nil {
+			err = fmt.Errorf("%w, and rolling back import batch %s failed: %v", err, resp.BatchID, rollbackErr)
+		}
+		return
+	}
+
+	err = s.lotService.Refresh(ctx, portfolioID)
+	return
+}
+
+// Rollback deletes the transactions, dividends and withholdings an import batch created and the batch itself, when the batch was
+// imported into a portfolio of the user. The holding and the stocks the import created are kept, as the stocks may be held in other
+// portfolios by then, and so are the dividends of the stocks held in other portfolios.
+func (s *importService) Rollback(ctx context.Context, userID, batchID string) (resp models.ImportRollbackResp, err error) {
+	result, err := s.batchRepository.GetByID(ctx, batchID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("import batch %s not found", batchID))
+		}
+		return
+	}
+	batch := *result.(*entities.ImportBatch)
+
+	portfolio, err := s.portfolioRepository.GetByID(ctx, batch.PortfolioID)
+	if err != nil {
+		return
+	}
+	if portfolio.(*entities.Portfolio).Userid != userID {
+		err = wrappers.NewUnauthorizedErr(fmt.Errorf("import batch %s belongs to a portfolio of another user", batchID))
+		return
+	}
+
+	rollback, err := s.batchRepository.Rollback(ctx, batchID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("import batch %s not found", batchID))
+		}
+		return
+	}
+	if err = s.lotService.Refresh(ctx, batch.PortfolioID); err != nil {
+		return
+	}
+
+	resp = models.ImportRollbackResp{
+		BatchID:      batchID,
+		PortfolioID:  batch.PortfolioID,
+		Transactions: rollback.Transactions,
+		Dividends:    rollback.Dividends,
+		Withholdings: rollback.Withholdings,
+	}
+	return
+}
+
+// importCashTypes are the types of the transactions created for the fees and cash movements of a statement
+var importCashTypes = map[string]string{
+	entities.StatementRowFee:        entities.TransactionTypeFee,
+	entities.StatementRowDeposit:    entities.TransactionTypeDeposit,
+	entities.StatementRowWithdrawal: entities.TransactionTypeWithdrawal,
+	entities.StatementRowInterest:   entities.TransactionTypeInterest,
+}
+
+// plan decides what to create for each row of a statement, resolving the stocks by symbol or ISIN and the shares the dividends
+// without a quantity were paid on from the transactions of the portfolio and the trades of the statement. Trades matching a
+// transaction of the portfolio are duplicates, each transaction matching a single trade. Withholding taxes are matched to the
+// dividend of their stock paid on their day, planned or stored, and the ones of a dividend are added up. The payment of a dividend
+// row is a cash transaction of its net amount, created unless it is already recorded in the portfolio.
+func (s *importService) plan(ctx context.Context, portfolioID string, rows []entities.StatementRow, transactions []entities.Transaction) (plan importPlan, resp []models.ImportRowResp, err error) {
+	if plan.resolver.bySymbol, plan.resolver.byISIN, err = stockIndex(ctx, s.stockRepository); err != nil {
+		return
+	}
+	plan.stocks = map[string]*entities.Stock{}
+	resolve := func(row entities.StatementRow) (string, bool) {
+		return plan.resolver.resolve(row.Symbol, row.ISIN)
+	}
+
+	currencies := map[string]string{}
+	recordedTransactions := newTransactionIndex(transactions)
+	held := append([]entities.Transaction{}, transactions...)
+	for _, t := range transactions {
+		if t.StockID != "" && t.MovesShares() {
+			currencies[t.StockID] = t.TransactionPrice.Currency
+		}
+	}
+
+	sorted := make([]entities.StatementRow, len(rows))
+	copy(sorted, rows)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	paid := map[string]paidDividend{}
+	var payments []dividendPayment
+	var withholdingRows []int
+	now := time.Now().UTC()
+	resp = []models.ImportRowResp{}
+	for _, row := range sorted {
+		label := row.Symbol
+		if label == "" {
+			label = row.ISIN
+		}
+		r := models.ImportRowResp{Line: row.Line, Kind: row.Kind, Date: row.Date, Symbol: row.Symbol, ISIN: row.ISIN, Name: row.Name, Quantity: row.Quantity, Price: row.Price, Amount: row.Amount, Fee: row.Fee, Action: models.ImportActionSkip}
+
+		switch row.Kind {
+		case entities.StatementRowTrade:
+			stockID, found := resolve(row)
+			if currency, ok := currencies[stockID]; ok && currency != row.Price.Currency {
+				r.Reason = fmt.Sprintf("%s is traded in %s", label, currency)
+				break
+			}
+			if !found && plan.stocks[stockID] == nil {
+				plan.stocks[stockID] = &entities.Stock{Extid: row.ISIN, TickerSymbol: row.Symbol, CompanyName: row.Name, Slug: strings.ToLower(row.Symbol), CreatedAt: now, UpdatedAt: now}
+				plan.stockKeys = append(plan.stockKeys, stockID)
+			}
+			currencies[stockID] = row.Price.Currency
+
+			price := row.Price
+			if !row.Fee.IsZero() {
+				if row.Fee.Currency == price.Currency {
+					unitFee := row.Fee.Amount.Div(row.Quantity.Abs())
+					if row.Quantity.IsNegative() {
+						unitFee = unitFee.Neg()
+					}
+					price.Amount = price.Amount.Add(unitFee)
+				} else {
+					r.Reason = fmt.Sprintf("fee in %s not added to the cost in %s", row.Fee.Currency, price.Currency)
+				}
+			}
+			transactionType := entities.TransactionTypeBuy
+			if row.Quantity.IsNegative() {
+				transactionType = entities.TransactionTypeSell
+			}
+			transaction := entities.Transaction{StockID: stockID, TransactionType: transactionType, Quantity: row.Quantity, TransactionPrice: price, TransactionDate: row.Date, Extid: row.Reference, CreatedAt: now, UpdatedAt: now}
+			if found && recordedTransactions.match(transaction) {
+				r.Action = models.ImportActionDuplicate
+				r.Reason = "transaction already recorded in the portfolio"
+				break
+			}
+			plan.transactions = append(plan.transactions, transaction)
+			held = append(held, transaction)
+			r.Action = models.ImportActionCreate
+
+		case entities.StatementRowDividend:
+			stockID, found := resolve(row)
+			if !found && plan.stocks[stockID] == nil {
+				r.Reason = fmt.Sprintf("stock %s not found", label)
+				break
+			}
+			key := stockID + row.Date.Format("2006-01-02")
+			if _, ok := paid[key]; !ok && found {
+				stored, ok, storedErr := s.storedDividend(ctx, stockID, row.Date)
+				if storedErr != nil {
+					err = storedErr
+					return
+				}
+				if ok {
+					paid[key] = stored
+				}
+			}
+			payment := dividendPayment{key: key, row: len(resp), transaction: entities.Transaction{StockID: stockID, TransactionType: entities.TransactionTypeDividend, TransactionPrice: row.Amount, TransactionDate: row.Date, Extid: row.Reference, CreatedAt: now, UpdatedAt: now}}
+			if _, ok := paid[key]; ok {
+				payments = append(payments, payment)
+				r.Action = models.ImportActionDuplicate
+				r.Reason = "dividend already recorded for the stock on that day"
+				break
+			}
+
+			perShare := row.Price
+			if perShare.IsZero() {
+				shares := row.Quantity
+				if shares.IsZero() {
+					shares = sharesHeld(held, stockID, row.Date)
+				}
+				if !shares.IsPositive() {
+					r.Reason = fmt.Sprintf("no shares of %s held on %s", label, row.Date.Format("2006-01-02"))
+					break
+				}
+				perShare = entities.NewMoney(row.Amount.Amount.Div(shares), row.Amount.Currency)
+			}
+			plan.dividends = append(plan.dividends, entities.Dividend{StockID: stockID, DividendPerShare: perShare, ExDate: row.Date, PayDate: row.Date, CreatedAt: now, UpdatedAt: now})
+			paid[key] = paidDividend{planned: len(plan.dividends) - 1, currency: perShare.Currency}
+			payments = append(payments, payment)
+			r.Action = models.ImportActionCreate
+
+		case entities.StatementRowFee, entities.StatementRowDeposit, entities.StatementRowWithdrawal, entities.StatementRowInterest:
+			if !row.Amount.Amount.IsPositive() {
+				r.Reason = "amount must be positive"
+				break
+			}
+			transaction := entities.Transaction{TransactionType: importCashTypes[row.Kind], TransactionPrice: row.Amount, TransactionDate: row.Date, Extid: row.Reference, CreatedAt: now, UpdatedAt: now}
+			if recordedTransactions.match(transaction) {
+				r.Action = models.ImportActionDuplicate
+				r.Reason = "transaction already recorded in the portfolio"
+				break
+			}
+			plan.transactions = append(plan.transactions, transaction)
+			r.Action = models.ImportActionCreate
+
+		case entities.StatementRowWithholdingTax:
+			withholdingRows = append(withholdingRows, len(resp))
+		}
+
+		resp = append(resp, r)
+	}
+
+	// Statements may list a withholding tax before the dividend it is withheld from, so they are matched once every dividend is planned
+	var withheld map[string]bool
+	planned := map[string]int{}
+	statementWithheld := map[string]decimal.Decimal{}
+	for _, i := range withholdingRows {
+		r := &resp[i]
+		label := r.Symbol
+		if label == "" {
+			label = r.ISIN
+		}
+		stockID, found := plan.resolver.resolve(r.Symbol, r.ISIN)
+		key := stockID + r.Date.Format("2006-01-02")
+		dividend, ok := paid[key]
+		if !ok && found {
+			if dividend, ok, err = s.storedDividend(ctx, stockID, r.Date); err != nil {
+				return
+			}
+			if ok {
+				paid[key] = dividend
+			}
+		}
+
+		switch {
+		case !r.Amount.Amount.IsPositive():
+			r.Reason = "amount must be positive"
+			continue
+		case !ok:
+			r.Reason = fmt.Sprintf("no dividend of %s paid on %s", label, r.Date.Format("2006-01-02"))
+			continue
+		case r.Amount.Currency != dividend.currency:
+			r.Reason = fmt.Sprintf("withholding tax in %s, not in the currency of the dividend %s", r.Amount.Currency, dividend.currency)
+			continue
+		}
+		statementWithheld[key] = statementWithheld[key].Add(r.Amount.Amount)
+		if dividend.dividendID != "" {
+			if withheld == nil {
+				if withheld, err = s.withheldDividends(ctx, portfolioID); err != nil {
+					return
+				}
+			}
+			if withheld[dividend.dividendID] {
+				r.Action = models.ImportActionDuplicate
+				r.Reason = "withholding tax already recorded for the dividend"
+				continue
+			}
+		}
+
+		if j, ok := planned[key]; ok {
+			w := &plan.withholdings[j].withholding
+			w.Amount.Amount = w.Amount.Amount.Add(r.Amount.Amount)
+		} else {
+			planned[key] = len(plan.withholdings)
+			plan.withholdings = append(plan.withholdings, plannedWithholding{
+				withholding: entities.DividendWithholding{DividendID: dividend.dividendID, Amount: r.Amount, CreatedAt: now, UpdatedAt: now},
+				dividend:    dividend.planned,
+			})
+		}
+		r.Action = models.ImportActionCreate
+	}
+
+	// The dividends are paid into the holding net of the tax the statement withholds from them, and a row of a dividend already
+	// recorded creates its payment when the payment is not recorded in the portfolio
+	netted := map[string]bool{}
+	for _, payment := range payments {
+		t := payment.transaction
+		if amount, ok := statementWithheld[payment.key]; ok && !netted[payment.key] && t.TransactionPrice.Currency == paid[payment.key].currency {
+			t.TransactionPrice.Amount = t.TransactionPrice.Amount.Sub(amount)
+			netted[payment.key] = true
+		}
+		if recordedTransactions.match(t) {
+			continue
+		}
+		plan.transactions = append(plan.transactions, t)
+		resp[payment.row].Action = models.ImportActionCreate
+	}
+
+	sort.SliceStable(resp, func(i, j int) bool {
+		return resp[i].Line < resp[j].Line
+	})
+	return
+}
+
+// reconcile compares the positions listed by a statement with the shares of their stocks held, at the end of the day of the position,
+// by the transactions of the holding of the portfolio at the broker and the ones the import plans to create. Positions of stocks the
+// holding never traded are held in a quantity of zero.
+func (s *importService) reconcile(ctx context.Context, portfolioID string, broker entities.Broker, positions []entities.StatementPosition, transactions []entities.Transaction, plan importPlan) (resp []models.ImportPositionResp, err error) {
+	holdingID, err := s.holdingOf(ctx, portfolioID, broker.BrokerID)
+	if err != nil {
+		return
+	}
+	var held []entities.Transaction
+	for _, t := range transactions {
+		if holdingID != "" && t.HoldingID == holdingID {
+			held = append(held, t)
+		}
+	}
+	held = append(held, plan.transactions...)
+
+	resp = []models.ImportPositionResp{}
+	for _, position := range positions {
+		stockID, _ := plan.resolver.resolve(position.Symbol, position.ISIN)
+		shares := sharesHeld(held, stockID, position.Date.AddDate(0, 0, 1))
+		r := models.ImportPositionResp{
+			Line:       position.Line,
+			Date:       position.Date,
+			Symbol:     position.Symbol,
+			ISIN:       position.ISIN,
+			Name:       position.Name,
+			Statement:  position.Quantity,
+			Portfolio:  shares,
+			Difference: position.Quantity.Sub(shares),
+			Status:     models.ImportPositionMatched,
+		}
+		if !r.Difference.IsZero() {
+			r.Status = models.ImportPositionMismatched
+		}
+		resp = append(resp, r)
+	}
+	return
+}
+
+// stockIndex returns the IDs of the stocks by their ticker symbol and by their ISIN, which is stored as their external ID
+func stockIndex(ctx context.Context, stockRepo ports.StockRepository) (bySymbol, byISIN map[string]string, err error) {
+	bySymbol, byISIN = map[string]string{}, map[string]string{}
+	result, err := stockRepo.Get(ctx, map[string]interface{}{}, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	for _, v := range result {
+		stock := v.(*entities.Stock)
+		if symbol := strings.ToUpper(strings.TrimSpace(stock.TickerSymbol)); symbol != "" {
+			bySymbol[symbol] = stock.StockID
+		}
+		if isin := strings.ToUpper(strings.TrimSpace(stock.Extid)); isin != "" {
+			byISIN[isin] = stock.StockID
+		}
+	}
+	return
+}
+
+// storedDividend returns the dividend of a stock stored as paid on a day, reporting false when there is none
+func (s *importService) storedDividend(ctx context.Context, stockID string, date time.Time) (paidDividend, bool, error) {
+	result, err := s.dividendRepository.Get(ctx, map[string]interface{}{"stockid": stockID}, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			return paidDividend{}, false, nil
+		}
+		return paidDividend{}, false, err
+	}
+	for _, v := range result {
+		dividend := v.(*entities.Dividend)
+		if startOfDay(dividend.PaidOn()).Equal(startOfDay(date)) {
+			return paidDividend{dividendID: dividend.DividendID, planned: -1, currency: dividend.DividendPerShare.Currency}, true, nil
+		}
+	}
+	return paidDividend{}, false, nil
+}
+
+// withheldDividends returns the IDs of the dividends paid to a portfolio whose withholding tax is recorded
+func (s *importService) withheldDividends(ctx context.Context, portfolioID string) (map[string]bool, error) {
+	withheld := map[string]bool{}
+	result, err := s.withholdingRepository.GetByPortfolioID(ctx, portfolioID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			return withheld, nil
+		}
+		return nil, err
+	}
+	for _, v := range result {
+		withheld[v.(*entities.DividendWithholding).DividendID] = true
+	}
+	return withheld, nil
+}
+
+// store creates the stocks, transactions, dividends and withholdings of an import plan, linking the transactions, dividends and
+// withholdings to the import batch. Transactions go to the holding of the portfolio at the broker, which is created on the first
+// import, as are the stocks not found.
+func (s *importService) store(ctx context.Context, portfolioID, batchID string, broker entities.Broker, plan importPlan) (transactionIDs, dividendIDs, withholdingIDs []string, err error) {
+	transactionIDs, dividendIDs, withholdingIDs = []string{}, []string{}, []string{}
+	if len(plan.transactions) == 0 && len(plan.dividends) == 0 && len(plan.withholdings) == 0 {
+		return
+	}
+
+	holdingID, err := s.holding(ctx, portfolioID, broker)
+	if err != nil {
+		return
+	}
+
+	stockIDs := map[string]string{}
+	for _, key := range plan.stockKeys {
+		stock := *plan.stocks[key]
+		stock.HoldingID = holdingID
+		if stockIDs[key], err = s.stockRepository.Create(ctx, stock); err != nil {
+			return
+		}
+	}
+	stockID := func(key string) string {
+		if id, ok := stockIDs[key]; ok {
+			return id
+		}
+		return key
+	}
+
+	var transactions []interface{}
+	for _, t := range plan.transactions {
+		t.HoldingID = holdingID
+		t.StockID = stockID(t.StockID)
+		t.ImportBatchID = batchID
+		transactions = append(transactions, t)
+	}
+	if len(transactions) > 0 {
+		if transactionIDs, err = s.transactionRepository.CreateMany(ctx, transactions); err != nil {
+			return
+		}
+	}
+
+	var dividends []interface{}
+	for _, d := range plan.dividends {
+		d.StockID = stockID(d.StockID)
+		d.ImportBatchID = batchID
+		dividends = append(dividends, d)
+	}
+	if len(dividends) > 0 {
+		if dividendIDs, err = s.dividendRepository.CreateMany(ctx, dividends); err != nil {
+			return
+		}
+	}
+
+	var withholdings []interface{}
+	for _, w := range plan.withholdings {
+		if w.dividend >= 0 {
+			w.withholding.DividendID = dividendIDs[w.dividend]
+		}
+		w.withholding.PortfolioID = portfolioID
+		w.withholding.ImportBatchID = batchID
+		withholdings = append(withholdings, w.withholding)
+	}
+	if len(withholdings) > 0 {
+		withholdingIDs, err = s.withholdingRepository.CreateMany(ctx, withholdings)
+	}
+	return
+}
+
+// holding returns the ID of the holding of a portfolio at a broker, creating it when the portfolio has none
+func (s *importService) holding(ctx context.Context, portfolioID string, broker entities.Broker) (string, error) {
+	holdingID, err := s.holdingOf(ctx, portfolioID, broker.BrokerID)
+	if err != nil || holdingID != "" {
+		return holdingID, err
+	}
+
+	now := time.Now().UTC()
+	return s.holdingRepository.Create(ctx, entities.Holding{
+		PortfolioID: portfolioID,
+		BrokerID:    broker.BrokerID,
+		Name:        broker.Name,
+		Description: fmt.Sprintf("Imported from %s", broker.Name),
+		Slug:        broker.Slug,
+		TradeDate:   now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+}
+
+// holdingOf returns the ID of the holding of a portfolio at a broker, or empty when the portfolio has none
+func (s *importService) holdingOf(ctx context.Context, portfolioID, brokerID string) (string, error) {
+	result, err := s.holdingRepository.Get(ctx, map[string]interface{}{"portfolioid": portfolioID, "brokerid": brokerID}, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			return "", nil
+		}
+		return "", err
+	}
+	return result[0].(*entities.Holding).HoldingID, nil
+}
+
+// importedBy returns the ID of the import batch that already imported a statement file into a portfolio, or empty when none did
+func (s *importService) importedBy(ctx context.Context, portfolioID, fingerprint string) (string, error) {
+	result, err := s.batchRepository.GetByFingerprint(ctx, portfolioID, fingerprint)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			return "", nil
+		}
+		return "", err
+	}
+	return result.(*entities.ImportBatch).ImportBatchID, nil
+}
+
+// brokers returns the sorted slugs of the brokers with a statement parser
+func (s *importService) brokers() []string {
+	brokers := make([]string, 0, len(s.parsers))
+	for _, parser := range s.parsers {
+		brokers = append(brokers, parser.Broker())
+	}
+	sort.Strings(brokers)
+	return brokers
+}
+
+// sharesHeld returns the quantity of a stock bought and not sold before a day
+func sharesHeld(transactions []entities.Transaction, stockID string, date time.Time) decimal.Decimal {
+	shares := decimal.Zero
+	for _, t := range transactions {
+		if t.StockID == stockID && t.TransactionDate.Before(startOfDay(date)) {
+			shares = shares.Add(t.Quantity)
+		}
+	}
+	return shares
+}
+
+// brokerKey returns the slug of a broker in lower case without separators, so "Interactive Brokers" and "interactive-brokers" match
+func brokerKey(slug string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, strings.ToLower(slug))
+}
+
+// importIssues returns the lines of a statement that were skipped or could not be parsed, in the order of the file
+func importIssues(statement entities.Statement) []models.ImportIssueResp {
+	issues := []models.ImportIssueResp{}
+	for _, issue := range statement.Skipped {
+		issues = append(issues, models.ImportIssueResp{Line: issue.Line, Status: models.ImportIssueSkipped, Reason: issue.Reason})
+	}
+	for _, issue := range statement.Invalid {
+		issues = append(issues, models.ImportIssueResp{Line: issue.Line, Status: models.ImportIssueInvalid, Reason: issue.Reason})
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
+// transactionIndex indexes the transactions of a portfolio by their reference at the broker and by their content fingerprint, to
+// find the trades of a statement that are already recorded
+type transactionIndex struct {
+	transactions []entities.Transaction
+	matched      []bool
+	byExtid      map[string][]int
+	byContent    map[string][]int
+}
+
+// newTransactionIndex indexes transactions by their reference at the broker and by their content fingerprint
+func newTransactionIndex(transactions []entities.Transaction) *transactionIndex {
+	index := &transactionIndex{
+		transactions: transactions,
+		matched:      make([]bool, len(transactions)),
+		byExtid:      map[string][]int{},
+		byContent:    map[string][]int{},
+	}
+	for i, t := range transactions {
+		if t.Extid != "" {
+			index.byExtid[t.Extid] = append(index.byExtid[t.Extid], i)
+		}
+		fingerprint := transactionFingerprint(t)
+		index.byContent[fingerprint] = append(index.byContent[fingerprint], i)
+	}
+	return index
+}
+
+// match reports whether a transaction is already recorded, marking the recorded one as matched so it matches no other. A transaction
+// matches one with the same reference at the broker, or else one with the same content unless both have different references.
+func (index *transactionIndex) match(t entities.Transaction) bool {
+	if t.Extid != "" {
+		for _, i := range index.byExtid[t.Extid] {
+			if !index.matched[i] {
+				index.matched[i] = true
+				return true
+			}
+		}
+	}
+	for _, i := range index.byContent[transactionFingerprint(t)] {
+		if !index.matched[i] && (t.Extid == "" || index.transactions[i].Extid == "") {
+			index.matched[i] = true
+			return true
+		}
+	}
+	return false
+}
+
+// transactionFingerprint returns the hexadecimal SHA-256 hash of what identifies a transaction: its type, instrument, day, quantity and
+// price
+func transactionFingerprint(t entities.Transaction) string {
+	instrumentID, _ := instrumentOf(t)
+	content := fmt.Sprintf("%s|%s|%s|%s|%s", t.Type(), instrumentID, t.TransactionDate.UTC().Format("2006-01-02"), t.Quantity.String(), t.TransactionPrice.String())
+	return fingerprintOf([]byte(content))
+}
+
+// fingerprintOf returns the hexadecimal SHA-256 hash of a content, such as a statement file
+func fingerprintOf(content []byte) string {
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,738 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestNewImportService_Ok checks that NewImportService creates a new importService struct with the parsers keyed by broker slug
+func TestNewImportService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	parserMock := mocks.NewStatementParser(t)
+
+	// Act
+	service := NewImportService(cfg, mocks.NewImportBatchRepository(t), mocks.NewBrokerRepository(t), mocks.NewPortfolioRepository(t), mocks.NewHoldingRepository(t), mocks.NewTransactionRepository(t), mocks.NewStockRepository(t), mocks.NewDividendRepository(t), mocks.NewDividendWithholdingRepository(t), mocks.NewLotService(t), map[string]ports.StatementParser{"Interactive Brokers": parserMock})
+
+	// Assert
+	assert.NotEmpty(t, service)
+	assert.Equal(t, parserMock, service.(*importService).parsers["interactivebrokers"])
+}
+
+// importStatement returns a statement with a trade of a known stock with its fee, a trade of a new stock, a dividend without quantity,
+// a withholding tax, a deposit and a skipped and an invalid line
+func importStatement() entities.Statement {
+	return entities.Statement{
+		Rows: []entities.StatementRow{
+			{Line: 2, Kind: entities.StatementRowTrade, Date: time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), Symbol: "AAPL", Quantity: decimal.NewFromInt(10), Price: entities.NewMoney(decimal.NewFromInt(170), "USD"), Fee: entities.NewMoney(decimal.NewFromInt(1), "USD")},
+			{Line: 3, Kind: entities.StatementRowTrade, Date: time.Date(2024, time.March, 6, 0, 0, 0, 0, time.UTC), ISIN: "IE00B4L5Y983", Name: "iShares Core MSCI World", Quantity: decimal.NewFromInt(4), Price: entities.NewMoney(decimal.NewFromInt(80), "EUR"), Fee: entities.NewMoney(decimal.NewFromInt(2), "USD")},
+			{Line: 4, Kind: entities.StatementRowDividend, Date: time.Date(2024, time.May, 16, 0, 0, 0, 0, time.UTC), Symbol: "AAPL", Amount: entities.NewMoney(decimal.NewFromInt(3), "USD")},
+			{Line: 5, Kind: entities.StatementRowWithholdingTax, Date: time.Date(2024, time.May, 16, 0, 0, 0, 0, time.UTC), Symbol: "AAPL", Amount: entities.NewMoney(decimal.RequireFromString("0.45"), "USD")},
+			{Line: 6, Kind: entities.StatementRowDeposit, Date: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), Amount: entities.NewMoney(decimal.NewFromInt(5000), "USD")},
+		},
+		Skipped: []entities.StatementIssue{{Line: 7, Reason: `action "Currency conversion" not supported`}},
+	}
+}
+
+// importMocks returns the repositories and parser of a first import of importStatement into a portfolio holding 5 AAPL
+func importMocks(t *testing.T, statement entities.Statement) (*mocks.ImportBatchRepository, *mocks.BrokerRepository, *mocks.PortfolioRepository, *mocks.TransactionRepository, *mocks.StockRepository, *mocks.DividendRepository, *mocks.StatementParser) {
+	batchRepositoryMock := mocks.NewImportBatchRepository(t)
+	batchRepositoryMock.On(testutils.FunctionName(t, ports.ImportBatchRepository.GetByFingerprint), context.Background(), "portfolio-a", fingerprintOf([]byte(""))).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	brokerRepositoryMock := mocks.NewBrokerRepository(t)
+	brokerRepositoryMock.On(testutils.FunctionName(t, ports.BrokerRepository.GetBySlug), context.Background(), "degiro").Return(&entities.Broker{BrokerID: "broker-a", Name: "Degiro", Slug: "degiro"}, nil).Once()
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-a").Return([]interface{}{
+		&entities.Transaction{StockID: "stock-aapl", Quantity: decimal.NewFromInt(5), TransactionPrice: entities.NewMoney(decimal.NewFromInt(150), "USD"), TransactionDate: time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)},
+	}, nil).Once()
+
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Stock{StockID: "stock-aapl", TickerSymbol: "aapl", Extid: "US0378331005"},
+	}, nil).Once()
+
+	dividendRepositoryMock := mocks.NewDividendRepository(t)
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-aapl"}, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	parserMock := mocks.NewStatementParser(t)
+	parserMock.On(testutils.FunctionName(t, ports.StatementParser.Parse), mock.Anything).Return(statement, nil).Once()
+
+	return batchRepositoryMock, brokerRepositoryMock, portfolioRepositoryMock, transactionRepositoryMock, stockRepositoryMock, dividendRepositoryMock, parserMock
+}
+
+// TestImport_DryRun checks that a dry run previews what each row creates without storing anything
+func TestImport_DryRun(t *testing.T) {
+	// Arrange
+	batchRepositoryMock, brokerRepositoryMock, portfolioRepositoryMock, transactionRepositoryMock, stockRepositoryMock, dividendRepositoryMock, parserMock := importMocks(t, importStatement())
+
+	service := &importService{
+		config:                config.Config{},
+		batchRepository:       batchRepositoryMock,
+		brokerRepository:      brokerRepositoryMock,
+		portfolioRepository:   portfolioRepositoryMock,
+		holdingRepository:     mocks.NewHoldingRepository(t),
+		transactionRepository: transactionRepositoryMock,
+		stockRepository:       stockRepositoryMock,
+		dividendRepository:    dividendRepositoryMock,
+		parsers:               map[string]ports.StatementParser{"degiro": parserMock},
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), "user-a", "portfolio-a", models.ImportReq{Broker: "degiro", DryRun: true}, strings.NewReader(""))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, resp.DryRun)
+	assert.Equal(t, 5, resp.Parsed)
+	assert.Equal(t, 1, resp.Skipped)
+	assert.Equal(t, 0, resp.Invalid)
+	assert.Equal(t, []models.ImportIssueResp{{Line: 7, Status: models.ImportIssueSkipped, Reason: `action "Currency conversion" not supported`}}, resp.Issues)
+	assert.Equal(t, []string{"IE00B4L5Y983"}, resp.NewStocks)
+	assert.Empty(t, resp.TransactionIDs)
+	assert.Empty(t, resp.DividendIDs)
+
+	actions := []string{}
+	for _, row := range resp.Rows {
+		actions = append(actions, row.Action)
+	}
+	assert.Equal(t, []string{models.ImportActionCreate, models.ImportActionCreate, models.ImportActionCreate, models.ImportActionCreate, models.ImportActionCreate}, actions)
+	assert.Equal(t, "fee in USD not added to the cost in EUR", resp.Rows[1].Reason)
+	assert.Empty(t, resp.WithholdingIDs)
+}
+
+// TestImport_Ok checks that Import creates the import batch, the holding, the new stocks, and the transactions with their fees, the
+// cash movements, the dividends per share with their payments net of withholding tax and the taxes withheld from them linked to the
+// batch
+func TestImport_Ok(t *testing.T) {
+	// Arrange
+	batchRepositoryMock, brokerRepositoryMock, portfolioRepositoryMock, transactionRepositoryMock, stockRepositoryMock, dividendRepositoryMock, parserMock := importMocks(t, importStatement())
+	var batch entities.ImportBatch
+	batchRepositoryMock.On(testutils.FunctionName(t, ports.ImportBatchRepository.Create), context.Background(), mock.AnythingOfType("entities.ImportBatch")).Run(func(args mock.Arguments) {
+		batch = args.Get(1).(entities.ImportBatch)
+	}).Return("batch-a", nil).Once()
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.Get), context.Background(), map[string]interface{}{"portfolioid": "portfolio-a", "brokerid": "broker-a"}, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	var holding entities.Holding
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.Create), context.Background(), mock.AnythingOfType("entities.Holding")).Run(func(args mock.Arguments) {
+		holding = args.Get(1).(entities.Holding)
+	}).Return("holding-a", nil).Once()
+
+	var stock entities.Stock
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.Create), context.Background(), mock.AnythingOfType("entities.Stock")).Run(func(args mock.Arguments) {
+		stock = args.Get(1).(entities.Stock)
+	}).Return("stock-world", nil).Once()
+
+	var transactions, dividends []interface{}
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.CreateMany), context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+		transactions = args.Get(1).([]interface{})
+	}).Return([]string{"transaction-a", "transaction-b", "transaction-c", "transaction-d"}, nil).Once()
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.CreateMany), context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+		dividends = args.Get(1).([]interface{})
+	}).Return([]string{"dividend-a"}, nil).Once()
+	var withholdings []interface{}
+	withholdingRepositoryMock := mocks.NewDividendWithholdingRepository(t)
+	withholdingRepositoryMock.On(testutils.FunctionName(t, ports.DividendWithholdingRepository.CreateMany), context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+		withholdings = args.Get(1).([]interface{})
+	}).Return([]string{"withholding-a"}, nil).Once()
+
+	lotServiceMock := mocks.NewLotService(t)
+	lotServiceMock.On(testutils.FunctionName(t, ports.LotService.Refresh), context.Background(), "portfolio-a").Return(nil).Once()
+
+	service := &importService{
+		config:                config.Config{},
+		batchRepository:       batchRepositoryMock,
+		brokerRepository:      brokerRepositoryMock,
+		portfolioRepository:   portfolioRepositoryMock,
+		holdingRepository:     holdingRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		stockRepository:       stockRepositoryMock,
+		dividendRepository:    dividendRepositoryMock,
+		withholdingRepository: withholdingRepositoryMock,
+		parsers:               map[string]ports.StatementParser{"degiro": parserMock},
+		lotService:            lotServiceMock,
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), "user-a", "portfolio-a", models.ImportReq{Broker: "degiro", FileName: "Transactions.csv"}, strings.NewReader(""))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "batch-a", resp.BatchID)
+	assert.Equal(t, "portfolio-a", batch.PortfolioID)
+	assert.Equal(t, "broker-a", batch.BrokerID)
+	assert.Equal(t, "Transactions.csv", batch.FileName)
+	assert.Equal(t, fingerprintOf([]byte("")), batch.Fingerprint)
+	assert.Equal(t, []string{"transaction-a", "transaction-b", "transaction-c", "transaction-d"}, resp.TransactionIDs)
+	assert.Equal(t, []string{"dividend-a"}, resp.DividendIDs)
+
+	assert.Equal(t, "portfolio-a", holding.PortfolioID)
+	assert.Equal(t, "broker-a", holding.BrokerID)
+	assert.Equal(t, "holding-a", stock.HoldingID)
+	assert.Equal(t, "IE00B4L5Y983", stock.Extid)
+	assert.Equal(t, "iShares Core MSCI World", stock.CompanyName)
+
+	assert.Len(t, transactions, 4)
+	deposit := transactions[0].(entities.Transaction)
+	assert.Equal(t, entities.TransactionTypeDeposit, deposit.TransactionType)
+	assert.Empty(t, deposit.StockID)
+	assert.Equal(t, "5000 USD", deposit.TransactionPrice.String())
+	apple := transactions[1].(entities.Transaction)
+	assert.Equal(t, "holding-a", apple.HoldingID)
+	assert.Equal(t, "stock-aapl", apple.StockID)
+	assert.Equal(t, entities.TransactionTypeBuy, apple.TransactionType)
+	assert.Equal(t, "170.1 USD", apple.TransactionPrice.String())
+	assert.Equal(t, "batch-a", apple.ImportBatchID)
+	world := transactions[2].(entities.Transaction)
+	assert.Equal(t, "stock-world", world.StockID)
+	assert.Equal(t, "80 EUR", world.TransactionPrice.String())
+	payment := transactions[3].(entities.Transaction)
+	assert.Equal(t, entities.TransactionTypeDividend, payment.TransactionType)
+	assert.Equal(t, "stock-aapl", payment.StockID)
+	assert.Equal(t, "2.55 USD", payment.TransactionPrice.String())
+	assert.Equal(t, "batch-a", payment.ImportBatchID)
+
+	assert.Len(t, dividends, 1)
+	dividend := dividends[0].(entities.Dividend)
+	assert.Equal(t, "stock-aapl", dividend.StockID)
+	assert.Equal(t, "0.2 USD", dividend.DividendPerShare.String())
+	assert.Equal(t, "batch-a", dividend.ImportBatchID)
+	assert.Equal(t, time.Date(2024, time.May, 16, 0, 0, 0, 0, time.UTC), dividend.PayDate)
+
+	assert.Equal(t, []string{"withholding-a"}, resp.WithholdingIDs)
+	assert.Len(t, withholdings, 1)
+	withholding := withholdings[0].(entities.DividendWithholding)
+	assert.Equal(t, "portfolio-a", withholding.PortfolioID)
+	assert.Equal(t, "dividend-a", withholding.DividendID)
+	assert.Equal(t, "0.45 USD", withholding.Amount.String())
+	assert.Equal(t, "batch-a", withholding.ImportBatchID)
+}
+
+// TestImport_DividendPayments checks that a dry run creates the payment of a dividend already recorded for the stock when the payment
+// is not recorded in the portfolio, and marks the dividends whose payment net of withholding tax is recorded as duplicates
+func TestImport_DividendPayments(t *testing.T) {
+	// Arrange
+	may := time.Date(2024, time.May, 16, 0, 0, 0, 0, time.UTC)
+	august := time.Date(2024, time.August, 15, 0, 0, 0, 0, time.UTC)
+	statement := entities.Statement{
+		Rows: []entities.StatementRow{
+			{Line: 2, Kind: entities.StatementRowDividend, Date: may, Symbol: "AAPL", Amount: entities.NewMoney(decimal.NewFromInt(3), "USD")},
+			{Line: 3, Kind: entities.StatementRowWithholdingTax, Date: may, Symbol: "AAPL", Amount: entities.NewMoney(decimal.RequireFromString("0.45"), "USD")},
+			{Line: 4, Kind: entities.StatementRowDividend, Date: august, Symbol: "AAPL", Amount: entities.NewMoney(decimal.NewFromInt(3), "USD")},
+		},
+	}
+	batchRepositoryMock, brokerRepositoryMock, portfolioRepositoryMock, transactionRepositoryMock, stockRepositoryMock, dividendRepositoryMock, parserMock := importMocks(t, statement)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-a").Unset()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-a").Return([]interface{}{
+		&entities.Transaction{StockID: "stock-aapl", Quantity: decimal.NewFromInt(15), TransactionPrice: entities.NewMoney(decimal.NewFromInt(150), "USD"), TransactionDate: time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		&entities.Transaction{StockID: "stock-aapl", TransactionType: entities.TransactionTypeDividend, TransactionPrice: entities.NewMoney(decimal.RequireFromString("2.55"), "USD"), TransactionDate: may},
+	}, nil).Once()
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-aapl"}, (*int)(nil), (*int)(nil)).Unset()
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-aapl"}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Dividend{DividendID: "dividend-may", DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.2"), "USD"), ExDate: may.AddDate(0, 0, -6), PayDate: may},
+		&entities.Dividend{DividendID: "dividend-august", DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.2"), "USD"), ExDate: august.AddDate(0, 0, -3), PayDate: august},
+	}, nil).Times(2)
+	withholdingRepositoryMock := mocks.NewDividendWithholdingRepository(t)
+	withholdingRepositoryMock.On(testutils.FunctionName(t, ports.DividendWithholdingRepository.GetByPortfolioID), context.Background(), "portfolio-a").Return([]interface{}{
+		&entities.DividendWithholding{PortfolioID: "portfolio-a", DividendID: "dividend-may", Amount: entities.NewMoney(decimal.RequireFromString("0.45"), "USD")},
+	}, nil).Once()
+
+	service := &importService{
+		config:                config.Config{},
+		batchRepository:       batchRepositoryMock,
+		brokerRepository:      brokerRepositoryMock,
+		portfolioRepository:   portfolioRepositoryMock,
+		holdingRepository:     mocks.NewHoldingRepository(t),
+		transactionRepository: transactionRepositoryMock,
+		stockRepository:       stockRepositoryMock,
+		dividendRepository:    dividendRepositoryMock,
+		withholdingRepository: withholdingRepositoryMock,
+		parsers:               map[string]ports.StatementParser{"degiro": parserMock},
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), "user-a", "portfolio-a", models.ImportReq{Broker: "degiro", DryRun: true}, strings.NewReader(""))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 2, resp.Duplicates)
+
+	actions := []string{}
+	for _, row := range resp.Rows {
+		actions = append(actions, row.Action)
+	}
+	assert.Equal(t, []string{models.ImportActionDuplicate, models.ImportActionDuplicate, models.ImportActionCreate}, actions)
+	assert.Equal(t, "dividend already recorded for the stock on that day", resp.Rows[2].Reason)
+}
+
+// TestImport_WithholdingOfStoredDividends checks that a dry run matches withholding taxes to the stored dividends, adding up the ones
+// of a dividend, and marks the ones already recorded for the portfolio as duplicates
+func TestImport_WithholdingOfStoredDividends(t *testing.T) {
+	// Arrange
+	statement := entities.Statement{
+		Rows: []entities.StatementRow{
+			{Line: 2, Kind: entities.StatementRowWithholdingTax, Date: time.Date(2024, time.May, 16, 0, 0, 0, 0, time.UTC), Symbol: "AAPL", Amount: entities.NewMoney(decimal.RequireFromString("0.45"), "USD")},
+			{Line: 3, Kind: entities.StatementRowWithholdingTax, Date: time.Date(2024, time.May, 16, 0, 0, 0, 0, time.UTC), Symbol: "AAPL", Amount: entities.NewMoney(decimal.RequireFromString("0.05"), "USD")},
+			{Line: 4, Kind: entities.StatementRowWithholdingTax, Date: time.Date(2024, time.August, 15, 0, 0, 0, 0, time.UTC), Symbol: "AAPL", Amount: entities.NewMoney(decimal.RequireFromString("0.45"), "USD")},
+			{Line: 5, Kind: entities.StatementRowWithholdingTax, Date: time.Date(2024, time.November, 14, 0, 0, 0, 0, time.UTC), Symbol: "AAPL", Amount: entities.NewMoney(decimal.RequireFromString("0.45"), "EUR")},
+			{Line: 6, Kind: entities.StatementRowWithholdingTax, Date: time.Date(2024, time.December, 2, 0, 0, 0, 0, time.UTC), Symbol: "AAPL", Amount: entities.NewMoney(decimal.RequireFromString("0.45"), "USD")},
+		},
+	}
+	batchRepositoryMock, brokerRepositoryMock, portfolioRepositoryMock, transactionRepositoryMock, stockRepositoryMock, dividendRepositoryMock, parserMock := importMocks(t, statement)
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-aapl"}, (*int)(nil), (*int)(nil)).Unset()
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-aapl"}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Dividend{DividendID: "dividend-may", DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.24"), "USD"), ExDate: time.Date(2024, time.May, 10, 0, 0, 0, 0, time.UTC), PayDate: time.Date(2024, time.May, 16, 0, 0, 0, 0, time.UTC)},
+		&entities.Dividend{DividendID: "dividend-august", DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.25"), "USD"), ExDate: time.Date(2024, time.August, 12, 0, 0, 0, 0, time.UTC), PayDate: time.Date(2024, time.August, 15, 0, 0, 0, 0, time.UTC)},
+		&entities.Dividend{DividendID: "dividend-november", DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.25"), "USD"), ExDate: time.Date(2024, time.November, 8, 0, 0, 0, 0, time.UTC), PayDate: time.Date(2024, time.November, 14, 0, 0, 0, 0, time.UTC)},
+	}, nil).Times(4)
+	withholdingRepositoryMock := mocks.NewDividendWithholdingRepository(t)
+	withholdingRepositoryMock.On(testutils.FunctionName(t, ports.DividendWithholdingRepository.GetByPortfolioID), context.Background(), "portfolio-a").Return([]interface{}{
+		&entities.DividendWithholding{PortfolioID: "portfolio-a", DividendID: "dividend-august", Amount: entities.NewMoney(decimal.RequireFromString("0.45"), "USD")},
+	}, nil).Once()
+
+	service := &importService{
+		config:                config.Config{},
+		batchRepository:       batchRepositoryMock,
+		brokerRepository:      brokerRepositoryMock,
+		portfolioRepository:   portfolioRepositoryMock,
+		holdingRepository:     mocks.NewHoldingRepository(t),
+		transactionRepository: transactionRepositoryMock,
+		stockRepository:       stockRepositoryMock,
+		dividendRepository:    dividendRepositoryMock,
+		withholdingRepository: withholdingRepositoryMock,
+		parsers:               map[string]ports.StatementParser{"degiro": parserMock},
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), "user-a", "portfolio-a", models.ImportReq{Broker: "degiro", DryRun: true}, strings.NewReader(""))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, resp.Duplicates)
+
+	actions := []string{}
+	for _, row := range resp.Rows {
+		actions = append(actions, row.Action)
+	}
+	assert.Equal(t, []string{models.ImportActionCreate, models.ImportActionCreate, models.ImportActionDuplicate, models.ImportActionSkip, models.ImportActionSkip}, actions)
+	assert.Equal(t, "withholding tax already recorded for the dividend", resp.Rows[2].Reason)
+	assert.Equal(t, "withholding tax in EUR, not in the currency of the dividend USD", resp.Rows[3].Reason)
+	assert.Equal(t, "no dividend of AAPL paid on 2024-12-02", resp.Rows[4].Reason)
+}
+
+// TestImport_InvalidLines checks that Import does not store a statement with invalid lines
+func TestImport_InvalidLines(t *testing.T) {
+	// Arrange
+	statement := importStatement()
+	statement.Invalid = []entities.StatementIssue{{Line: 8, Reason: "ISIN cannot be empty"}}
+	batchRepositoryMock, brokerRepositoryMock, portfolioRepositoryMock, transactionRepositoryMock, stockRepositoryMock, dividendRepositoryMock, parserMock := importMocks(t, statement)
+
+	service := &importService{
+		config:                config.Config{},
+		batchRepository:       batchRepositoryMock,
+		brokerRepository:      brokerRepositoryMock,
+		portfolioRepository:   portfolioRepositoryMock,
+		holdingRepository:     mocks.NewHoldingRepository(t),
+		transactionRepository: transactionRepositoryMock,
+		stockRepository:       stockRepositoryMock,
+		dividendRepository:    dividendRepositoryMock,
+		parsers:               map[string]ports.StatementParser{"degiro": parserMock},
+	}
+
+	// Act
+	_, err := service.Import(context.Background(), "user-a", "portfolio-a", models.ImportReq{Broker: "degiro"}, strings.NewReader(""))
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, "statement has 1 invalid lines, run a dry run to list them", err.Error())
+}
+
+// TestImport_Duplicates checks that a dry run marks the trades already recorded, by their reference or by their content, as duplicates
+func TestImport_Duplicates(t *testing.T) {
+	// Arrange
+	statement := entities.Statement{
+		Rows: []entities.StatementRow{
+			{Line: 2, Kind: entities.StatementRowTrade, Date: time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), Symbol: "AAPL", Quantity: decimal.NewFromInt(10), Price: entities.NewMoney(decimal.NewFromInt(171), "USD"), Reference: "order-1"},
+			{Line: 3, Kind: entities.StatementRowTrade, Date: time.Date(2024, time.March, 6, 0, 0, 0, 0, time.UTC), Symbol: "AAPL", Quantity: decimal.NewFromInt(2), Price: entities.NewMoney(decimal.RequireFromString("172.50"), "USD")},
+			{Line: 4, Kind: entities.StatementRowTrade, Date: time.Date(2024, time.March, 6, 0, 0, 0, 0, time.UTC), Symbol: "AAPL", Quantity: decimal.NewFromInt(2), Price: entities.NewMoney(decimal.RequireFromString("172.5"), "USD")},
+			{Line: 5, Kind: entities.StatementRowTrade, Date: time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC), Symbol: "AAPL", Quantity: decimal.NewFromInt(1), Price: entities.NewMoney(decimal.NewFromInt(173), "USD"), Reference: "order-3"},
+		},
+	}
+	batchRepositoryMock, brokerRepositoryMock, portfolioRepositoryMock, transactionRepositoryMock, stockRepositoryMock, dividendRepositoryMock, parserMock := importMocks(t, statement)
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-aapl"}, (*int)(nil), (*int)(nil)).Unset()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-a").Unset()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-a").Return([]interface{}{
+		&entities.Transaction{StockID: "stock-aapl", Quantity: decimal.NewFromInt(10), TransactionPrice: entities.NewMoney(decimal.RequireFromString("170.1"), "USD"), TransactionDate: time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), Extid: "order-1"},
+		&entities.Transaction{StockID: "stock-aapl", Quantity: decimal.NewFromInt(2), TransactionPrice: entities.NewMoney(decimal.RequireFromString("172.5"), "USD"), TransactionDate: time.Date(2024, time.March, 6, 0, 0, 0, 0, time.UTC)},
+		&entities.Transaction{StockID: "stock-aapl", Quantity: decimal.NewFromInt(1), TransactionPrice: entities.NewMoney(decimal.NewFromInt(173), "USD"), TransactionDate: time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC), Extid: "order-2"},
+	}, nil).Once()
+
+	service := &importService{
+		config:                config.Config{},
+		batchRepository:       batchRepositoryMock,
+		brokerRepository:      brokerRepositoryMock,
+		portfolioRepository:   portfolioRepositoryMock,
+		holdingRepository:     mocks.NewHoldingRepository(t),
+		transactionRepository: transactionRepositoryMock,
+		stockRepository:       stockRepositoryMock,
+		dividendRepository:    dividendRepositoryMock,
+		parsers:               map[string]ports.StatementParser{"degiro": parserMock},
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), "user-a", "portfolio-a", models.ImportReq{Broker: "degiro", DryRun: true}, strings.NewReader(""))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 2, resp.Duplicates)
+
+	actions := []string{}
+	for _, row := range resp.Rows {
+		actions = append(actions, row.Action)
+	}
+	assert.Equal(t, []string{models.ImportActionDuplicate, models.ImportActionDuplicate, models.ImportActionCreate, models.ImportActionCreate}, actions)
+	assert.Equal(t, "transaction already recorded in the portfolio", resp.Rows[0].Reason)
+}
+
+// TestImport_AlreadyImported checks that Import rejects a file already imported into the portfolio, which a dry run reports
+func TestImport_AlreadyImported(t *testing.T) {
+	// Arrange
+	batchRepositoryMock, brokerRepositoryMock, portfolioRepositoryMock, transactionRepositoryMock, stockRepositoryMock, dividendRepositoryMock, parserMock := importMocks(t, importStatement())
+	batchRepositoryMock.On(testutils.FunctionName(t, ports.ImportBatchRepository.GetByFingerprint), context.Background(), "portfolio-a", fingerprintOf([]byte(""))).Unset()
+	batchRepositoryMock.On(testutils.FunctionName(t, ports.ImportBatchRepository.GetByFingerprint), context.Background(), "portfolio-a", fingerprintOf([]byte(""))).Return(&entities.ImportBatch{ImportBatchID: "batch-a"}, nil).Once()
+
+	service := &importService{
+		config:                config.Config{},
+		batchRepository:       batchRepositoryMock,
+		brokerRepository:      brokerRepositoryMock,
+		portfolioRepository:   portfolioRepositoryMock,
+		holdingRepository:     mocks.NewHoldingRepository(t),
+		transactionRepository: transactionRepositoryMock,
+		stockRepository:       stockRepositoryMock,
+		dividendRepository:    dividendRepositoryMock,
+		parsers:               map[string]ports.StatementParser{"degiro": parserMock},
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), "user-a", "portfolio-a", models.ImportReq{Broker: "degiro"}, strings.NewReader(""))
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, "statement already imported in batch batch-a", err.Error())
+	assert.Equal(t, "batch-a", resp.DuplicateOf)
+}
+
+// TestImport_StoreError checks that Import rolls back the import batch when storing what it creates fails
+func TestImport_StoreError(t *testing.T) {
+	// Arrange
+	batchRepositoryMock, brokerRepositoryMock, portfolioRepositoryMock, transactionRepositoryMock, stockRepositoryMock, dividendRepositoryMock, parserMock := importMocks(t, importStatement())
+	batchRepositoryMock.On(testutils.FunctionName(t, ports.ImportBatchRepository.Create), context.Background(), mock.AnythingOfType("entities.ImportBatch")).Return("batch-a", nil).Once()
+	batchRepositoryMock.On(testutils.FunctionName(t, ports.ImportBatchRepository.Rollback), context.Background(), "batch-a").Return(entities.ImportRollback{Transactions: 2}, nil).Once()
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.Get), context.Background(), map[string]interface{}{"portfolioid": "portfolio-a", "brokerid": "broker-a"}, (*int)(nil), (*int)(nil)).Return([]interface{}{&entities.Holding{HoldingID: "holding-a"}}, nil).Once()
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.Create), context.Background(), mock.AnythingOfType("entities.Stock")).Return("stock-world", nil).Once()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.CreateMany), context.Background(), mock.Anything).Return([]string{"transaction-a", "transaction-b"}, nil).Once()
+	expectedError := "insert error"
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.CreateMany), context.Background(), mock.Anything).Return(nil, fmt.Errorf(expectedError)).Once()
+
+	service := &importService{
+		config:                config.Config{},
+		batchRepository:       batchRepositoryMock,
+		brokerRepository:      brokerRepositoryMock,
+		portfolioRepository:   portfolioRepositoryMock,
+		holdingRepository:     holdingRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		stockRepository:       stockRepositoryMock,
+		dividendRepository:    dividendRepositoryMock,
+		parsers:               map[string]ports.StatementParser{"degiro": parserMock},
+	}
+
+	// Act
+	_, err := service.Import(context.Background(), "user-a", "portfolio-a", models.ImportReq{Broker: "degiro"}, strings.NewReader(""))
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestImport_StoreAndRollbackError checks that Import returns the error of the rollback with the one of the store when the import
+// batch cannot be rolled back either
+func TestImport_StoreAndRollbackError(t *testing.T) {
+	// Arrange
+	batchRepositoryMock, brokerRepositoryMock, portfolioRepositoryMock, transactionRepositoryMock, stockRepositoryMock, dividendRepositoryMock, parserMock := importMocks(t, importStatement())
+	batchRepositoryMock.On(testutils.FunctionName(t, ports.ImportBatchRepository.Create), context.Background(), mock.AnythingOfType("entities.ImportBatch")).Return("batch-a", nil).Once()
+	batchRepositoryMock.On(testutils.FunctionName(t, ports.ImportBatchRepository.Rollback), context.Background(), "batch-a").Return(entities.ImportRollback{}, fmt.Errorf("connection lost")).Once()
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.Get), context.Background(), map[string]interface{}{"portfolioid": "portfolio-a", "brokerid": "broker-a"}, (*int)(nil), (*int)(nil)).Return([]interface{}{&entities.Holding{HoldingID: "holding-a"}}, nil).Once()
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.Create), context.Background(), mock.AnythingOfType("entities.Stock")).Return("stock-world", nil).Once()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.CreateMany), context.Background(), mock.Anything).Return(nil, fmt.Errorf("insert error")).Once()
+	expectedError := "insert error, and rolling back import batch batch-a failed: connection lost"
+
+	service := &importService{
+		config:                config.Config{},
+		batchRepository:       batchRepositoryMock,
+		brokerRepository:      brokerRepositoryMock,
+		portfolioRepository:   portfolioRepositoryMock,
+		holdingRepository:     holdingRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		stockRepository:       stockRepositoryMock,
+		dividendRepository:    dividendRepositoryMock,
+		parsers:               map[string]ports.StatementParser{"degiro": parserMock},
+	}
+
+	// Act
+	_, err := service.Import(context.Background(), "user-a", "portfolio-a", models.ImportReq{Broker: "degiro"}, strings.NewReader(""))
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestImport_NoParser checks that Import returns a validation error when the broker has no statement parser
+func TestImport_NoParser(t *testing.T) {
+	// Arrange
+	brokerRepositoryMock := mocks.NewBrokerRepository(t)
+	brokerRepositoryMock.On(testutils.FunctionName(t, ports.BrokerRepository.GetBySlug), context.Background(), "xtb").Return(&entities.Broker{BrokerID: "broker-b", Slug: "xtb"}, nil).Once()
+
+	parserMock := mocks.NewStatementParser(t)
+	parserMock.On("Broker").Return("degiro")
+
+	service := &importService{
+		config:           config.Config{},
+		brokerRepository: brokerRepositoryMock,
+		parsers:          map[string]ports.StatementParser{"degiro": parserMock},
+	}
+
+	// Act
+	_, err := service.Import(context.Background(), "user-a", "portfolio-a", models.ImportReq{Broker: "xtb"}, strings.NewReader(""))
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, "no statement importer for broker xtb, available brokers: degiro", err.Error())
+}
+
+// TestImport_Format checks that Import reads the statement with the parser of the format of the request rather than of the broker
+func TestImport_Format(t *testing.T) {
+	// Arrange
+	batchRepositoryMock, brokerRepositoryMock, portfolioRepositoryMock, transactionRepositoryMock, stockRepositoryMock, dividendRepositoryMock, parserMock := importMocks(t, importStatement())
+
+	service := &importService{
+		config:                config.Config{},
+		batchRepository:       batchRepositoryMock,
+		brokerRepository:      brokerRepositoryMock,
+		portfolioRepository:   portfolioRepositoryMock,
+		holdingRepository:     mocks.NewHoldingRepository(t),
+		transactionRepository: transactionRepositoryMock,
+		stockRepository:       stockRepositoryMock,
+		dividendRepository:    dividendRepositoryMock,
+		parsers:               map[string]ports.StatementParser{"sharesight": parserMock},
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), "user-a", "portfolio-a", models.ImportReq{Broker: "degiro", Format: "Sharesight", DryRun: true}, strings.NewReader(""))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 5, resp.Parsed)
+}
+
+// TestImport_Positions checks that Import reconciles the positions of a statement with the shares the holding at the broker holds
+// once the statement is imported, which are only the planned ones when the portfolio has no holding at the broker yet
+func TestImport_Positions(t *testing.T) {
+	// Arrange
+	statement := importStatement()
+	date := time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)
+	statement.Positions = []entities.StatementPosition{
+		{Line: 8, Date: date, Symbol: "AAPL", Quantity: decimal.NewFromInt(10)},
+		{Line: 9, Date: date, ISIN: "IE00B4L5Y983", Quantity: decimal.NewFromInt(5)},
+	}
+	batchRepositoryMock, brokerRepositoryMock, portfolioRepositoryMock, transactionRepositoryMock, stockRepositoryMock, dividendRepositoryMock, parserMock := importMocks(t, statement)
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.Get), context.Background(), map[string]interface{}{"portfolioid": "portfolio-a", "brokerid": "broker-a"}, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &importService{
+		config:                config.Config{},
+		batchRepository:       batchRepositoryMock,
+		brokerRepository:      brokerRepositoryMock,
+		portfolioRepository:   portfolioRepositoryMock,
+		holdingRepository:     holdingRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		stockRepository:       stockRepositoryMock,
+		dividendRepository:    dividendRepositoryMock,
+		parsers:               map[string]ports.StatementParser{"ofx": parserMock},
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), "user-a", "portfolio-a", models.ImportReq{Broker: "degiro", Format: "ofx", DryRun: true}, strings.NewReader(""))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, resp.Mismatches)
+	assert.Len(t, resp.Positions, 2)
+	assert.Equal(t, models.ImportPositionMatched, resp.Positions[0].Status)
+	assert.Equal(t, "10", resp.Positions[0].Portfolio.String())
+	assert.Equal(t, models.ImportPositionMismatched, resp.Positions[1].Status)
+	assert.Equal(t, "4", resp.Positions[1].Portfolio.String())
+	assert.Equal(t, "1", resp.Positions[1].Difference.String())
+}
+
+// TestImport_UnknownFormat checks that Import returns a validation error when no statement parser has the format of the request
+func TestImport_UnknownFormat(t *testing.T) {
+	// Arrange
+	brokerRepositoryMock := mocks.NewBrokerRepository(t)
+	brokerRepositoryMock.On(testutils.FunctionName(t, ports.BrokerRepository.GetBySlug), context.Background(), "degiro").Return(&entities.Broker{BrokerID: "broker-a", Slug: "degiro"}, nil).Once()
+
+	parserMock := mocks.NewStatementParser(t)
+	parserMock.On("Broker").Return("degiro")
+
+	service := &importService{
+		config:           config.Config{},
+		brokerRepository: brokerRepositoryMock,
+		parsers:          map[string]ports.StatementParser{"degiro": parserMock},
+	}
+
+	// Act
+	_, err := service.Import(context.Background(), "user-a", "portfolio-a", models.ImportReq{Broker: "degiro", Format: "quicken"}, strings.NewReader(""))
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, "no statement importer for format quicken, available formats: degiro", err.Error())
+}
+
+// TestImport_BrokerNotFound checks that Import returns a non existent error when no broker has the slug
+func TestImport_BrokerNotFound(t *testing.T) {
+	// Arrange
+	brokerRepositoryMock := mocks.NewBrokerRepository(t)
+	brokerRepositoryMock.On(testutils.FunctionName(t, ports.BrokerRepository.GetBySlug), context.Background(), "degiro").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &importService{
+		config:           config.Config{},
+		brokerRepository: brokerRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Import(context.Background(), "user-a", "portfolio-a", models.ImportReq{Broker: "degiro"}, strings.NewReader(""))
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, "broker degiro not found", err.Error())
+}
+
+// TestImport_OtherUser checks that Import returns an unauthorized error and stores nothing when the portfolio belongs to another user
+func TestImport_OtherUser(t *testing.T) {
+	// Arrange
+	brokerRepositoryMock := mocks.NewBrokerRepository(t)
+	brokerRepositoryMock.On(testutils.FunctionName(t, ports.BrokerRepository.GetBySlug), context.Background(), "degiro").Return(&entities.Broker{BrokerID: "broker-a", Slug: "degiro"}, nil).Once()
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-b"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-a").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &importService{
+		config:                config.Config{},
+		brokerRepository:      brokerRepositoryMock,
+		portfolioRepository:   portfolioRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		parsers:               map[string]ports.StatementParser{"degiro": mocks.NewStatementParser(t)},
+	}
+
+	// Act
+	_, err := service.Import(context.Background(), "user-a", "portfolio-a", models.ImportReq{Broker: "degiro"}, strings.NewReader(""))
+
+	// Assert
+	assert.IsType(t, wrappers.UnauthorizedErr, err)
+	assert.Equal(t, "portfolio portfolio-a belongs to another user", err.Error())
+}
+
+// TestRollbackImport_Ok checks that Rollback returns what rolling back the import batch deleted
+func TestRollbackImport_Ok(t *testing.T) {
+	// Arrange
+	batchRepositoryMock := mocks.NewImportBatchRepository(t)
+	batchRepositoryMock.On(testutils.FunctionName(t, ports.ImportBatchRepository.GetByID), context.Background(), "batch-a").Return(&entities.ImportBatch{ImportBatchID: "batch-a", PortfolioID: "portfolio-a"}, nil).Once()
+	batchRepositoryMock.On(testutils.FunctionName(t, ports.ImportBatchRepository.Rollback), context.Background(), "batch-a").Return(entities.ImportRollback{Transactions: 2, Dividends: 1}, nil).Once()
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	lotServiceMock := mocks.NewLotService(t)
+	lotServiceMock.On(testutils.FunctionName(t, ports.LotService.Refresh), context.Background(), "portfolio-a").Return(nil).Once()
+
+	service := &importService{
+		config:              config.Config{},
+		batchRepository:     batchRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+		lotService:          lotServiceMock,
+	}
+
+	// Act
+	resp, err := service.Rollback(context.Background(), "user-a", "batch-a")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.ImportRollbackResp{BatchID: "batch-a", PortfolioID: "portfolio-a", Transactions: 2, Dividends: 1}, resp)
+}
+
+// TestRollbackImport_NotFound checks that Rollback returns a non existent error when the import batch does not exist
+func TestRollbackImport_NotFound(t *testing.T) {
+	// Arrange
+	batchRepositoryMock := mocks.NewImportBatchRepository(t)
+	batchRepositoryMock.On(testutils.FunctionName(t, ports.ImportBatchRepository.GetByID), context.Background(), "batch-a").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &importService{
+		config:          config.Config{},
+		batchRepository: batchRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Rollback(context.Background(), "user-a", "batch-a")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, "import batch batch-a not found", err.Error())
+}
+
+// TestRollbackImport_OtherUser checks that Rollback returns an unauthorized error and keeps the import batch when it was imported
+// into a portfolio of another user
+func TestRollbackImport_OtherUser(t *testing.T) {
+	// Arrange
+	batchRepositoryMock := mocks.NewImportBatchRepository(t)
+	batchRepositoryMock.On(testutils.FunctionName(t, ports.ImportBatchRepository.GetByID), context.Background(), "batch-a").Return(&entities.ImportBatch{ImportBatchID: "batch-a", PortfolioID: "portfolio-a"}, nil).Once()
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-b"}, nil).Once()
+
+	service := &importService{
+		config:              config.Config{},
+		batchRepository:     batchRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Rollback(context.Background(), "user-a", "batch-a")
+
+	// Assert
+	assert.IsType(t, wrappers.UnauthorizedErr, err)
+	assert.Equal(t, "import batch batch-a belongs to a portfolio of another user", err.Error())
+}
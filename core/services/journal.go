@@ -0,0 +1,765 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// averageCostPlaces is the number of decimal places of the cost of the units sold at the average cost, which is rounded so the
+// journal does not carry the remainder of dividing the cost of the lots by their units
+const averageCostPlaces = 8
+
+// journalService adapter of a plain-text accounting journal service
+type journalService struct {
+	config                   config.Config
+	portfolioRepository      ports.PortfolioRepository
+	transactionRepository    ports.TransactionRepository
+	userRepository           ports.UserRepository
+	stockRepository          ports.StockRepository
+	cryptoCurrencyRepository ports.CryptoCurrencyRepository
+	dividendRepository       ports.DividendRepository
+	priceRepository          ports.PriceRepository
+	priceSource              ports.PriceSource
+	fxRate                   ports.FxRateService
+}
+
+// NewJournalService creates a new plain-text accounting journal service
+func NewJournalService(cfg config.Config, portfolioRepo ports.PortfolioRepository, transactionRepo ports.TransactionRepository, userRepo ports.UserRepository, stockRepo ports.StockRepository, cryptoCurrencyRepo ports.CryptoCurrencyRepository, dividendRepo ports.DividendRepository, priceRepo ports.PriceRepository, priceSource ports.PriceSource, fxRate ports.FxRateService) ports.JournalService {
+	return &journalService{
+		config:                   cfg,
+		portfolioRepository:      portfolioRepo,
+		transactionRepository:    transactionRepo,
+		userRepository:           userRepo,
+		stockRepository:          stockRepo,
+		cryptoCurrencyRepository: cryptoCurrencyRepo,
+		dividendRepository:       dividendRepo,
+		priceRepository:          priceRepo,
+		priceSource:              priceSource,
+		fxRate:                   fxRate,
+	}
+}
+
+// Export writes the transactions and dividends of a portfolio as a Beancount or hledger journal. Buys add lots held at cost, paid from
+// the cash of the portfolio, and sells reduce the lots matched by the cost basis method of the portfolio, booking the realized gains,
+// so the balances at cost and the gains are the ones of the valuation. Contributions only move on deposits and withdrawals. Dividends
+// are booked on their pay date net of the tax withheld. The stored daily closes of the instruments, their latest prices and the rates
+// of the foreign currencies are written as price directives, so the journal values the portfolio as the valuation does. The portfolio
+// must belong to the user.
+func (s *journalService) Export(ctx context.Context, userID, portfolioID string, req models.JournalReq, w io.Writer) (err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	portfolio, transactions, err := loadPortfolioTransactions(ctx, s.portfolioRepository, s.transactionRepository, portfolioID)
+	if err != nil {
+		return
+	}
+	if err = checkOwner(portfolio, userID); err != nil {
+		return
+	}
+	currency, err := baseCurrency(ctx, s.userRepository, portfolio)
+	if err != nil {
+		return
+	}
+	method := costBasisMethod(portfolio)
+	ledger, err := buildLedger(transactions, method)
+	if err != nil {
+		return
+	}
+
+	b := newJournalBuilder(portfolio, currency, method, newExportLookup(nil, s.stockRepository, s.cryptoCurrencyRepository))
+	if err = s.trades(ctx, b, transactions, ledger.gains, ledger.transfers); err != nil {
+		return
+	}
+	if err = s.dividends(ctx, b, portfolio); err != nil {
+		return
+	}
+	if err = s.prices(ctx, b, transactions, ledger.positions); err != nil {
+		return
+	}
+
+	switch req.Format {
+	case models.JournalFormatBeancount:
+		err = writeBeancount(w, b.build())
+	case models.JournalFormatHledger:
+		err = writeHledger(w, b.build())
+	}
+	return
+}
+
+// trades adds an entry for every transaction of the portfolio in chronological order. The gains and transfers are the ones of the
+// ledger, in the same order as the sells and transfers out that consumed their lots. Transfers move shares at their cost from and to
+// Equity:Transfers, while fees, deposits, withdrawals and interest move cash only. Dividend transactions are left out, as dividends
+// are written from the dividends paid to the portfolio.
+func (s *journalService) trades(ctx context.Context, b *journalBuilder, transactions []entities.Transaction, gains, transfers []entities.RealizedGain) error {
+	sorted := make([]entities.Transaction, len(transactions))
+	copy(sorted, transactions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].TransactionDate.Before(sorted[j].TransactionDate)
+	})
+
+	for _, t := range sorted {
+		if !t.MovesShares() {
+			s.cash(b, t)
+			continue
+		}
+		if t.Quantity.IsZero() {
+			continue
+		}
+		instrumentID, instrumentType := instrumentOf(t)
+		commodity, err := b.instrument(ctx, instrumentID, instrumentType)
+		if err != nil {
+			return err
+		}
+		date := startOfDay(t.TransactionDate)
+		holding := b.account("Assets", commodityAccount(commodity))
+
+		if t.Quantity.IsPositive() {
+			cost := t.TransactionPrice.Mul(t.Quantity)
+			narration := fmt.Sprintf("Buy %s %s", t.Quantity, commodity)
+			counterpart := journalPosting{account: b.account("Equity", "Transfers"), units: entities.NewMoney(cost.Amount.Neg(), cost.Currency)}
+			if t.Type() == entities.TransactionTypeTransferIn {
+				narration = fmt.Sprintf("Transfer in %s %s", t.Quantity, commodity)
+			} else {
+				counterpart = b.payment(cost, date)
+			}
+			b.entry(date, narration,
+				journalPosting{account: holding, units: entities.NewMoney(t.Quantity, commodity), cost: t.TransactionPrice},
+				counterpart,
+			)
+			continue
+		}
+
+		if t.Type() == entities.TransactionTypeTransferOut {
+			var postings []journalPosting
+			postings, transfers = b.disposals(t, holding, commodity, transfers, entities.Money{})
+			var cost entities.Money
+			for _, p := range postings {
+				if cost, err = cost.Add(p.disposedCost(b.method)); err != nil {
+					return err
+				}
+			}
+			postings = append(postings, journalPosting{account: b.account("Equity", "Transfers"), units: cost})
+			b.entry(date, fmt.Sprintf("Transfer out %s %s", t.Quantity.Neg(), commodity), postings...)
+			continue
+		}
+
+		var postings []journalPosting
+		postings, gains = b.disposals(t, holding, commodity, gains, t.TransactionPrice)
+		proceeds := t.TransactionPrice.Mul(t.Quantity.Neg())
+		gain := proceeds
+		for _, p := range postings {
+			if gain, err = gain.Sub(p.disposedCost(b.method)); err != nil {
+				return err
+			}
+		}
+		postings = append(postings,
+			journalPosting{account: b.account("Assets", "Cash"), units: proceeds},
+			journalPosting{account: b.account("Income", "CapitalGains"), units: entities.NewMoney(gain.Amount.Neg(), t.TransactionPrice.Currency)},
+		)
+		b.entry(date, fmt.Sprintf("Sell %s %s", t.Quantity.Neg(), commodity), postings...)
+	}
+
+	return nil
+}
+
+// cash adds an entry for a fee, deposit, withdrawal or interest, moving its amount between Assets:Cash and the account it comes from
+// or goes to
+func (s *journalService) cash(b *journalBuilder, t entities.Transaction) {
+	var narration string
+	var counterpart string
+	switch t.Type() {
+	case entities.TransactionTypeFee:
+		narration, counterpart = "Fee", b.account("Expenses", "Fees")
+	case entities.TransactionTypeDeposit:
+		narration, counterpart = "Deposit", b.account("Equity", "Contributions")
+	case entities.TransactionTypeWithdrawal:
+		narration, counterpart = "Withdrawal", b.account("Equity", "Contributions")
+	case entities.TransactionTypeInterest:
+		narration, counterpart = "Interest", b.account("Income", "Interest")
+	default:
+		return
+	}
+
+	flow := t.CashFlow()
+	if flow.IsZero() {
+		return
+	}
+	b.entry(startOfDay(t.TransactionDate), narration,
+		journalPosting{account: b.account("Assets", "Cash"), units: flow},
+		journalPosting{account: counterpart, units: entities.NewMoney(flow.Amount.Neg(), flow.Currency)},
+	)
+}
+
+// payment returns the cash paying for a buy, taken from Assets:Cash in the currency of the trade as the cash balances of the portfolio
+// are. The rate of the trade date to the base currency is written as a price, so the cash paid is valued at it.
+func (b *journalBuilder) payment(cost entities.Money, date time.Time) journalPosting {
+	if b.currency != "" && b.currency != cost.Currency {
+		b.conversion(cost.Currency, date)
+	}
+	return journalPosting{account: b.account("Assets", "Cash"), units: entities.NewMoney(cost.Amount.Neg(), cost.Currency)}
+}
+
+// dividends adds an entry for every dividend paid to the portfolio, with the tax recorded as withheld from it or else the tax the
+// country of the stock withholds from a resident of the tax country of the portfolio
+func (s *journalService) dividends(ctx context.Context, b *journalBuilder, portfolio entities.Portfolio) error {
+	residence := strings.ToUpper(strings.TrimSpace(portfolio.TaxCountryID))
+	return s.dividendRepository.StreamByPortfolioID(ctx, portfolio.PortfolioID, func(d entities.PortfolioDividend) error {
+		if !d.Shares.IsPositive() {
+			return nil
+		}
+		commodity, err := b.instrument(ctx, d.StockID, entities.EntityNameStock)
+		if err != nil {
+			return err
+		}
+		stock, err := b.lookup.stock(ctx, d.StockID)
+		if err != nil {
+			return err
+		}
+
+		withheldRate, _ := withholdingRates(s.config.Withholding, strings.ToUpper(stock.Country), residence)
+		gross := d.DividendPerShare.Mul(d.Shares)
+		withholding := gross.Mul(withheldRate)
+		if !d.Withholding.IsZero() {
+			withholding = d.Withholding
+		}
+
+		postings := []journalPosting{{account: b.account("Assets", "Cash"), units: entities.NewMoney(gross.Amount.Sub(withholding.Amount), gross.Currency)}}
+		if !withholding.IsZero() {
+			postings = append(postings, journalPosting{account: b.account("Expenses", "Taxes", "Withholding"), units: withholding})
+		}
+		postings = append(postings, journalPosting{account: b.account("Income", "Dividends"), units: entities.NewMoney(gross.Amount.Neg(), gross.Currency)})
+		b.entry(startOfDay(d.PaidOn()), fmt.Sprintf("Dividend %s %s per share on %s shares", commodity, d.DividendPerShare, d.Shares), postings...)
+		return nil
+	})
+}
+
+// prices adds the daily closes stored for the instruments of the portfolio from their first trade on, the latest price of its open
+// positions and the rates of the foreign currencies on the days they were converted and today
+func (s *journalService) prices(ctx context.Context, b *journalBuilder, transactions []entities.Transaction, positions []position) error {
+	today := startOfDay(time.Now())
+
+	firstTrades := map[string]time.Time{}
+	var instruments []string
+	for _, t := range transactions {
+		if !t.MovesShares() {
+			continue
+		}
+		instrumentID, _ := instrumentOf(t)
+		first, ok := firstTrades[instrumentID]
+		if !ok {
+			instruments = append(instruments, instrumentID)
+		}
+		if !ok || t.TransactionDate.Before(first) {
+			firstTrades[instrumentID] = t.TransactionDate
+		}
+	}
+
+	for _, instrumentID := range instruments {
+		result, err := s.priceRepository.GetRange(ctx, instrumentID, startOfDay(firstTrades[instrumentID]), today)
+		if err != nil {
+			if errors.Is(err, wrappers.NonExistentErr) {
+				continue
+			}
+			return err
+		}
+		for _, v := range result {
+			p := v.(*entities.Price)
+			b.price(startOfDay(p.PriceDate), b.instruments[instrumentID], entities.NewMoney(p.Close, p.Currency))
+		}
+	}
+
+	for _, p := range positions {
+		price, err := s.priceSource.LatestPrice(ctx, p.instrumentID)
+		if err != nil {
+			if errors.Is(err, wrappers.NonExistentErr) {
+				continue
+			}
+			return err
+		}
+		b.price(today, b.instruments[p.instrumentID], price)
+	}
+
+	if b.currency == "" {
+		return nil
+	}
+	for _, currency := range b.currencies() {
+		if currency == b.currency {
+			continue
+		}
+		for _, date := range append(b.conversions[currency], today) {
+			rate, err := s.fxRate.GetRate(ctx, currency, b.currency, date)
+			if err != nil {
+				if errors.Is(err, wrappers.NonExistentErr) {
+					continue
+				}
+				return err
+			}
+			b.price(date, currency, entities.NewMoney(rate.Rate, b.currency))
+		}
+	}
+
+	return nil
+}
+
+// journal is a plain-text accounting journal of a portfolio
+type journal struct {
+	title       string
+	currency    string
+	booking     string
+	commodities []journalCommodity
+	accounts    []journalAccount
+	entries     []journalEntry
+	prices      []journalPrice
+}
+
+// journalCommodity is a commodity of a journal, declared on the day it is first used
+type journalCommodity struct {
+	date   time.Time
+	symbol string
+	name   string
+}
+
+// journalAccount is an account of a journal, opened on the day it is first used. Accounts holding an instrument only hold its
+// commodity.
+type journalAccount struct {
+	date      time.Time
+	name      string
+	commodity string
+}
+
+// journalEntry is a transaction of a journal, whose postings balance without any amount left to infer
+type journalEntry struct {
+	date      time.Time
+	narration string
+	postings  []journalPosting
+}
+
+// journalPosting is a posting of a journal entry, whose amounts are left out when they have no currency. Units held at cost have the cost per unit, and the date of the lot when they reduce
+// a lot picked by the cost basis method, or else the total cost of the units sold at the average cost. Price is the price per unit the
+// units were sold at, which does not take part in the balance, and totalPrice the amount of another currency the units were converted to.
+type journalPosting struct {
+	account    string
+	units      entities.Money
+	cost       entities.Money
+	totalCost  entities.Money
+	lotDate    time.Time
+	price      entities.Money
+	totalPrice entities.Money
+}
+
+// disposedCost returns the cost of the units a posting takes out of a holding
+func (p journalPosting) disposedCost(method int) entities.Money {
+	if method == entities.CostBasisAverage {
+		return p.totalCost
+	}
+	return p.cost.Mul(p.units.Amount.Neg())
+}
+
+// journalPrice is a price directive of a journal
+type journalPrice struct {
+	date      time.Time
+	commodity string
+	price     entities.Money
+}
+
+// journalBuilder collects the entries and prices of a journal, naming the accounts and commodities they use
+type journalBuilder struct {
+	root        string
+	title       string
+	currency    string
+	method      int
+	lookup      *exportLookup
+	instruments map[string]string
+	symbols     map[string]string
+	names       map[string]string
+	conversions map[string][]time.Time
+	entries     []journalEntry
+	prices      map[string]journalPrice
+}
+
+func newJournalBuilder(portfolio entities.Portfolio, currency string, method int, lookup *exportLookup) *journalBuilder {
+	title := strings.Join(strings.Fields(portfolio.Name), " ")
+	if title == "" {
+		title = portfolio.PortfolioID
+	}
+	return &journalBuilder{
+		root:        "Portflux:" + accountComponent(portfolio.Name, "Portfolio"),
+		title:       title,
+		currency:    currency,
+		method:      method,
+		lookup:      lookup,
+		instruments: map[string]string{},
+		symbols:     map[string]string{},
+		names:       map[string]string{},
+		conversions: map[string][]time.Time{},
+		prices:      map[string]journalPrice{},
+	}
+}
+
+// instrument returns the commodity of a stock or cryptocurrency, named after its symbol and unique within the journal
+func (b *journalBuilder) instrument(ctx context.Context, instrumentID, instrumentType string) (string, error) {
+	if commodity, ok := b.instruments[instrumentID]; ok {
+		return commodity, nil
+	}
+
+	symbol, name := instrumentID, ""
+	if instrumentType == entities.EntityNameStock {
+		stock, err := b.lookup.stock(ctx, instrumentID)
+		if err != nil {
+			return "", err
+		}
+		if stock.TickerSymbol != "" {
+			symbol, name = stock.TickerSymbol, stock.CompanyName
+		}
+	} else {
+		cryptoCurrency, err := b.lookup.cryptoCurrency(ctx, instrumentID)
+		if err != nil {
+			return "", err
+		}
+		if cryptoCurrency.Symbol != "" {
+			symbol, name = cryptoCurrency.Symbol, cryptoCurrency.Name
+		}
+	}
+
+	base := commodityName(symbol)
+	commodity := base
+	for i := 2; b.symbols[commodity] != ""; i++ {
+		suffix := "-" + strconv.Itoa(i)
+		if len(base)+len(suffix) > 24 {
+			base = base[:24-len(suffix)]
+		}
+		commodity = base + suffix
+	}
+	b.instruments[instrumentID] = commodity
+	b.symbols[commodity] = instrumentID
+	b.names[commodity] = name
+	return commodity, nil
+}
+
+// account returns the name of an account of the portfolio under a root account
+func (b *journalBuilder) account(root string, components ...string) string {
+	return strings.Join(append([]string{root, b.root}, components...), ":")
+}
+
+// conversion records the day an amount of a currency was converted to the base currency, so its rate is written as a price
+func (b *journalBuilder) conversion(currency string, date time.Time) {
+	dates := b.conversions[currency]
+	if len(dates) == 0 || !dates[len(dates)-1].Equal(date) {
+		b.conversions[currency] = append(dates, date)
+	}
+}
+
+// entry adds an entry to the journal
+func (b *journalBuilder) entry(date time.Time, narration string, postings ...journalPosting) {
+	b.entries = append(b.entries, journalEntry{date: date, narration: narration, postings: postings})
+}
+
+// disposals returns the postings taking the shares a sell or transfer out disposes of out of their holding, one per lot consumed or a
+// single one at the average cost, together with the consumed lots that are left. The price is the one the shares were sold at, if any.
+func (b *journalBuilder) disposals(t entities.Transaction, holding, commodity string, consumed []entities.RealizedGain, price entities.Money) ([]journalPosting, []entities.RealizedGain) {
+	disposed := t.Quantity.Neg()
+	postings := []journalPosting{}
+	for disposed.IsPositive() && len(consumed) > 0 {
+		g := consumed[0]
+		consumed = consumed[1:]
+		disposed = disposed.Sub(g.Quantity)
+
+		if b.method == entities.CostBasisAverage && len(postings) > 0 {
+			last := &postings[len(postings)-1]
+			last.units = entities.NewMoney(last.units.Amount.Sub(g.Quantity), commodity)
+			last.totalCost = entities.NewMoney(last.totalCost.Amount.Add(g.CostBasis.Amount), g.CostBasis.Currency)
+			continue
+		}
+		posting := journalPosting{account: holding, units: entities.NewMoney(g.Quantity.Neg(), commodity), price: price}
+		if b.method == entities.CostBasisAverage {
+			posting.totalCost = g.CostBasis
+		} else {
+			posting.cost = entities.NewMoney(g.CostBasis.Amount.Div(g.Quantity), g.CostBasis.Currency)
+			posting.lotDate = startOfDay(g.AcquiredAt)
+		}
+		postings = append(postings, posting)
+	}
+
+	if b.method == entities.CostBasisAverage {
+		for i := range postings {
+			postings[i].totalCost.Amount = postings[i].totalCost.Amount.Round(averageCostPlaces)
+		}
+	}
+	return postings, consumed
+}
+
+// price adds a price directive to the journal, replacing any price of the commodity added before for the same day
+func (b *journalBuilder) price(date time.Time, commodity string, price entities.Money) {
+	if commodity == "" || price.Currency == "" || price.Currency == commodity {
+		return
+	}
+	b.prices[date.Format("2006-01-02")+" "+commodity] = journalPrice{date: date, commodity: commodity, price: price}
+}
+
+// currencies returns the currencies of the amounts of the entries, sorted
+func (b *journalBuilder) currencies() []string {
+	seen := map[string]bool{}
+	var currencies []string
+	for _, e := range b.entries {
+		for _, p := range e.postings {
+			for _, c := range []string{p.units.Currency, p.cost.Currency, p.totalCost.Currency, p.price.Currency, p.totalPrice.Currency} {
+				if c != "" && b.symbols[c] == "" && !seen[c] {
+					seen[c] = true
+					currencies = append(currencies, c)
+				}
+			}
+		}
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// build returns the journal with its entries and prices in chronological order, declaring every commodity and opening every account
+// on the first day it is used
+func (b *journalBuilder) build() journal {
+	j := journal{title: b.title, currency: b.currency, booking: "STRICT"}
+	if b.method == entities.CostBasisAverage {
+		j.booking = "NONE"
+	}
+
+	j.entries = make([]journalEntry, len(b.entries))
+	copy(j.entries, b.entries)
+	sort.SliceStable(j.entries, func(i, k int) bool {
+		return j.entries[i].date.Before(j.entries[k].date)
+	})
+	for _, p := range b.prices {
+		j.prices = append(j.prices, p)
+	}
+	sort.Slice(j.prices, func(i, k int) bool {
+		if !j.prices[i].date.Equal(j.prices[k].date) {
+			return j.prices[i].date.Before(j.prices[k].date)
+		}
+		return j.prices[i].commodity < j.prices[k].commodity
+	})
+
+	commodities := map[string]int{}
+	useCommodity := func(date time.Time, symbol string) {
+		if symbol == "" {
+			return
+		}
+		if _, ok := commodities[symbol]; !ok {
+			commodities[symbol] = len(j.commodities)
+			j.commodities = append(j.commodities, journalCommodity{date: date, symbol: symbol, name: b.names[symbol]})
+		}
+	}
+	accounts := map[string]bool{}
+	for _, e := range j.entries {
+		for _, p := range e.postings {
+			for _, symbol := range []string{p.units.Currency, p.cost.Currency, p.totalCost.Currency, p.price.Currency, p.totalPrice.Currency} {
+				useCommodity(e.date, symbol)
+			}
+			if !accounts[p.account] {
+				accounts[p.account] = true
+				account := journalAccount{date: e.date, name: p.account}
+				if b.symbols[p.units.Currency] != "" {
+					account.commodity = p.units.Currency
+				}
+				j.accounts = append(j.accounts, account)
+			}
+		}
+	}
+	for _, p := range j.prices {
+		useCommodity(p.date, p.commodity)
+		useCommodity(p.date, p.price.Currency)
+	}
+	sort.SliceStable(j.commodities, func(i, k int) bool {
+		return j.commodities[i].date.Before(j.commodities[k].date)
+	})
+
+	return j
+}
+
+// writeBeancount writes a journal in the Beancount syntax. The accounts holding an instrument are opened with the booking method
+// matching the cost basis method: lots are picked explicitly by their cost and date, or reduced at the average cost without matching.
+func writeBeancount(w io.Writer, j journal) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "; Portflux journal of the portfolio %s\n", j.title)
+	if j.currency != "" {
+		fmt.Fprintf(bw, "option \"operating_currency\" %s\n", strconv.Quote(j.currency))
+	}
+	fmt.Fprintln(bw)
+
+	for _, c := range j.commodities {
+		fmt.Fprintf(bw, "%s commodity %s\n", journalDate(c.date), c.symbol)
+		if c.name != "" {
+			fmt.Fprintf(bw, "  name: %s\n", strconv.Quote(c.name))
+		}
+	}
+	fmt.Fprintln(bw)
+
+	for _, a := range j.accounts {
+		if a.commodity != "" {
+			fmt.Fprintf(bw, "%s open %s %s %s\n", journalDate(a.date), a.name, a.commodity, strconv.Quote(j.booking))
+			continue
+		}
+		fmt.Fprintf(bw, "%s open %s\n", journalDate(a.date), a.name)
+	}
+
+	for _, e := range j.entries {
+		fmt.Fprintf(bw, "\n%s * %s\n", journalDate(e.date), strconv.Quote(e.narration))
+		for _, p := range e.postings {
+			amount := beancountAmount(p.units)
+			switch {
+			case p.cost.Currency != "" && !p.lotDate.IsZero():
+				amount += fmt.Sprintf(" {%s, %s}", beancountAmount(p.cost), journalDate(p.lotDate))
+			case p.cost.Currency != "":
+				amount += fmt.Sprintf(" {%s}", beancountAmount(p.cost))
+			case p.totalCost.Currency != "":
+				amount += fmt.Sprintf(" {{%s}}", beancountAmount(p.totalCost))
+			}
+			if p.price.Currency != "" {
+				amount += " @ " + beancountAmount(p.price)
+			}
+			if p.totalPrice.Currency != "" {
+				amount += " @@ " + beancountAmount(p.totalPrice)
+			}
+			fmt.Fprintf(bw, "  %-60s %s\n", p.account, amount)
+		}
+	}
+
+	if len(j.prices) > 0 {
+		fmt.Fprintln(bw)
+	}
+	for _, p := range j.prices {
+		fmt.Fprintf(bw, "%s price %s %s\n", journalDate(p.date), p.commodity, beancountAmount(p.price))
+	}
+
+	return bw.Flush()
+}
+
+// writeHledger writes a journal in the hledger syntax. hledger does not track lots, so units held at cost are posted at their cost
+// per unit, which keeps the balances at cost and the gains equal to the Beancount ones, and the price of a sale is left as a comment.
+func writeHledger(w io.Writer, j journal) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "; Portflux journal of the portfolio %s\n\n", j.title)
+
+	for _, c := range j.commodities {
+		if c.name != "" {
+			fmt.Fprintf(bw, "; %s\n", c.name)
+		}
+		fmt.Fprintf(bw, "commodity %s\n", hledgerCommodity(c.symbol))
+	}
+	fmt.Fprintln(bw)
+
+	for _, a := range j.accounts {
+		fmt.Fprintf(bw, "account %s\n", a.name)
+	}
+
+	for _, e := range j.entries {
+		fmt.Fprintf(bw, "\n%s * %s\n", journalDate(e.date), e.narration)
+		for _, p := range e.postings {
+			amount := hledgerAmount(p.units)
+			switch {
+			case p.cost.Currency != "":
+				amount += " @ " + hledgerAmount(p.cost)
+			case p.totalCost.Currency != "":
+				amount += " @@ " + hledgerAmount(p.totalCost)
+			case p.totalPrice.Currency != "":
+				amount += " @@ " + hledgerAmount(p.totalPrice)
+			}
+			if p.price.Currency != "" {
+				amount += "  ; sold at " + hledgerAmount(p.price)
+			}
+			fmt.Fprintf(bw, "    %-60s %s\n", p.account, amount)
+		}
+	}
+
+	if len(j.prices) > 0 {
+		fmt.Fprintln(bw)
+	}
+	for _, p := range j.prices {
+		fmt.Fprintf(bw, "P %s %s %s\n", journalDate(p.date), hledgerCommodity(p.commodity), hledgerAmount(p.price))
+	}
+
+	return bw.Flush()
+}
+
+// journalDate returns a date of a journal
+func journalDate(date time.Time) string {
+	return date.UTC().Format("2006-01-02")
+}
+
+// beancountAmount returns an amount of a commodity in the Beancount syntax
+func beancountAmount(m entities.Money) string {
+	return m.Amount.String() + " " + m.Currency
+}
+
+// hledgerAmount returns an amount of a commodity in the hledger syntax
+func hledgerAmount(m entities.Money) string {
+	return m.Amount.String() + " " + hledgerCommodity(m.Currency)
+}
+
+// hledgerCommodity returns a commodity in the hledger syntax, which quotes symbols with anything other than letters
+func hledgerCommodity(symbol string) string {
+	for _, r := range symbol {
+		if !unicode.IsLetter(r) {
+			return strconv.Quote(symbol)
+		}
+	}
+	return symbol
+}
+
+// commodityName returns a commodity named after a symbol that is valid in Beancount and hledger: upper case letters, digits and the
+// characters ' . _ -, starting with a letter, ending with a letter or digit and between 2 and 24 characters long. Symbols not starting
+// with a letter or of a single character are prefixed with an X.
+func commodityName(symbol string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '\'', r == '.', r == '_', r == '-':
+			return r
+		default:
+			return '-'
+		}
+	}, strings.ToUpper(strings.TrimSpace(symbol)))
+
+	trimmed := strings.TrimRight(name, "'._-")
+	if len(trimmed) < 2 || trimmed[0] < 'A' || trimmed[0] > 'Z' {
+		trimmed = "X" + trimmed
+	}
+	if len(trimmed) > 24 {
+		trimmed = strings.TrimRight(trimmed[:24], "'._-")
+	}
+	return trimmed
+}
+
+// commodityAccount returns the account component of a commodity, which cannot have the characters ' . _
+func commodityAccount(commodity string) string {
+	return strings.NewReplacer("'", "-", ".", "-", "_", "-").Replace(commodity)
+}
+
+// accountComponent returns an account component made of the words of a name, each capitalized, or fallback when the name has no
+// letters or digits
+func accountComponent(name, fallback string) string {
+	var sb strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}) {
+		sb.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	if sb.Len() == 0 {
+		return fallback
+	}
+	return sb.String()
+}
@@ -0,0 +1,374 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// newJournalTestService returns a journal service for a portfolio that bought AAPL twice in USD and DOGE once in EUR, sold part of
+// its AAPL and was paid a dividend on the rest
+func newJournalTestService(t *testing.T, method int) *journalService {
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a", Name: "my retirement", BaseCurrency: "EUR", TaxCountryID: "CZ", PerformenceCalculation: method}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-a").Return([]interface{}{
+		&entities.Transaction{TransactionID: "transaction-a", StockID: "stock-aapl", Quantity: decimal.NewFromInt(10), TransactionPrice: entities.NewMoney(decimal.NewFromInt(170), "USD"), TransactionDate: time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)},
+		&entities.Transaction{TransactionID: "transaction-b", CryptocurrencyID: "crypto-doge", Quantity: decimal.NewFromInt(100), TransactionPrice: entities.NewMoney(decimal.RequireFromString("0.1"), "EUR"), TransactionDate: time.Date(2024, time.March, 6, 0, 0, 0, 0, time.UTC)},
+		&entities.Transaction{TransactionID: "transaction-c", StockID: "stock-aapl", Quantity: decimal.NewFromInt(5), TransactionPrice: entities.NewMoney(decimal.NewFromInt(180), "USD"), TransactionDate: time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)},
+		&entities.Transaction{TransactionID: "transaction-d", StockID: "stock-aapl", Quantity: decimal.NewFromInt(-12), TransactionPrice: entities.NewMoney(decimal.NewFromInt(200), "USD"), TransactionDate: time.Date(2024, time.April, 5, 0, 0, 0, 0, time.UTC)},
+	}, nil).Once()
+
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-aapl").Return(&entities.Stock{StockID: "stock-aapl", TickerSymbol: "AAPL", CompanyName: "Apple Inc.", Country: "US"}, nil).Once()
+
+	cryptoCurrencyRepositoryMock := mocks.NewCryptoCurrencyRepository(t)
+	cryptoCurrencyRepositoryMock.On(testutils.FunctionName(t, ports.CryptoCurrencyRepository.GetByID), context.Background(), "crypto-doge").Return(&entities.CryptoCurrency{CryptoCurrencyID: "crypto-doge", Symbol: "DOGE", Name: "Dogecoin"}, nil).Once()
+
+	dividendRepositoryMock := mocks.NewDividendRepository(t)
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.StreamByPortfolioID), context.Background(), "portfolio-a", mock.Anything).Return(func(_ context.Context, _ string, fn func(entities.PortfolioDividend) error) error {
+		return fn(entities.PortfolioDividend{Dividend: entities.Dividend{DividendID: "dividend-a", StockID: "stock-aapl", DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.24"), "USD"), ExDate: time.Date(2024, time.May, 10, 0, 0, 0, 0, time.UTC), PayDate: time.Date(2024, time.May, 16, 0, 0, 0, 0, time.UTC)}, Shares: decimal.NewFromInt(3)})
+	}).Once()
+
+	priceRepositoryMock := mocks.NewPriceRepository(t)
+	priceRepositoryMock.On(testutils.FunctionName(t, ports.PriceRepository.GetRange), context.Background(), "stock-aapl", time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), mock.AnythingOfType("time.Time")).Return([]interface{}{
+		&entities.Price{InstrumentID: "stock-aapl", PriceDate: time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), Close: decimal.NewFromInt(170), Currency: "USD"},
+		&entities.Price{InstrumentID: "stock-aapl", PriceDate: time.Date(2024, time.April, 5, 0, 0, 0, 0, time.UTC), Close: decimal.NewFromInt(200), Currency: "USD"},
+	}, nil).Once()
+	priceRepositoryMock.On(testutils.FunctionName(t, ports.PriceRepository.GetRange), context.Background(), "crypto-doge", time.Date(2024, time.March, 6, 0, 0, 0, 0, time.UTC), mock.AnythingOfType("time.Time")).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	priceSourceMock := mocks.NewPriceSource(t)
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.LatestPrice), context.Background(), "stock-aapl").Return(entities.NewMoney(decimal.NewFromInt(230), "USD"), nil).Once()
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.LatestPrice), context.Background(), "crypto-doge").Return(entities.Money{}, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	fxRateMock := mocks.NewFxRateService(t)
+	fxRateMock.On(testutils.FunctionName(t, ports.FxRateService.GetRate), context.Background(), "USD", "EUR", mock.AnythingOfType("time.Time")).Return(models.FxRateResp{BaseCurrency: "USD", QuoteCurrency: "EUR", Rate: decimal.RequireFromString("0.92")}, nil).Times(3)
+
+	cfg := config.Config{}
+	cfg.Withholding = config.Withholding{
+		Rates:          map[string]decimal.Decimal{"US": decimal.RequireFromString("0.30")},
+		TreatyRates:    map[string]map[string]decimal.Decimal{"US": {"CZ": decimal.RequireFromString("0.15")}},
+		ReliefAtSource: []string{"US"},
+	}
+
+	return &journalService{
+		config:                   cfg,
+		portfolioRepository:      portfolioRepositoryMock,
+		transactionRepository:    transactionRepositoryMock,
+		stockRepository:          stockRepositoryMock,
+		cryptoCurrencyRepository: cryptoCurrencyRepositoryMock,
+		dividendRepository:       dividendRepositoryMock,
+		priceRepository:          priceRepositoryMock,
+		priceSource:              priceSourceMock,
+		fxRate:                   fxRateMock,
+	}
+}
+
+// TestExportJournal_Beancount checks that Export writes a Beancount journal whose lots are picked by the cost basis method
+func TestExportJournal_Beancount(t *testing.T) {
+	// Arrange
+	service := newJournalTestService(t, entities.CostBasisFIFO)
+	var buf bytes.Buffer
+
+	// Act
+	err := service.Export(context.Background(), "user-a", "portfolio-a", models.JournalReq{Format: models.JournalFormatBeancount}, &buf)
+
+	// Assert
+	assert.Nil(t, err)
+	today := time.Now().UTC().Format("2006-01-02")
+	expected := `; Portflux journal of the portfolio my retirement
+option "operating_currency" "EUR"
+
+2024-03-05 commodity AAPL
+  name: "Apple Inc."
+2024-03-05 commodity USD
+2024-03-06 commodity DOGE
+  name: "Dogecoin"
+2024-03-06 commodity EUR
+
+2024-03-05 open Assets:Portflux:MyRetirement:AAPL AAPL "STRICT"
+2024-03-05 open Assets:Portflux:MyRetirement:Cash
+2024-03-06 open Assets:Portflux:MyRetirement:DOGE DOGE "STRICT"
+2024-04-05 open Income:Portflux:MyRetirement:CapitalGains
+2024-05-16 open Expenses:Portflux:MyRetirement:Taxes:Withholding
+2024-05-16 open Income:Portflux:MyRetirement:Dividends
+
+2024-03-05 * "Buy 10 AAPL"
+  Assets:Portflux:MyRetirement:AAPL                            10 AAPL {170 USD}
+  Assets:Portflux:MyRetirement:Cash                            -1700 USD
+
+2024-03-06 * "Buy 100 DOGE"
+  Assets:Portflux:MyRetirement:DOGE                            100 DOGE {0.1 EUR}
+  Assets:Portflux:MyRetirement:Cash                            -10 EUR
+
+2024-03-10 * "Buy 5 AAPL"
+  Assets:Portflux:MyRetirement:AAPL                            5 AAPL {180 USD}
+  Assets:Portflux:MyRetirement:Cash                            -900 USD
+
+2024-04-05 * "Sell 12 AAPL"
+  Assets:Portflux:MyRetirement:AAPL                            -10 AAPL {170 USD, 2024-03-05} @ 200 USD
+  Assets:Portflux:MyRetirement:AAPL                            -2 AAPL {180 USD, 2024-03-10} @ 200 USD
+  Assets:Portflux:MyRetirement:Cash                            2400 USD
+  Income:Portflux:MyRetirement:CapitalGains                    -340 USD
+
+2024-05-16 * "Dividend AAPL 0.24 USD per share on 3 shares"
+  Assets:Portflux:MyRetirement:Cash                            0.612 USD
+  Expenses:Portflux:MyRetirement:Taxes:Withholding             0.108 USD
+  Income:Portflux:MyRetirement:Dividends                       -0.72 USD
+
+2024-03-05 price AAPL 170 USD
+2024-03-05 price USD 0.92 EUR
+2024-03-10 price USD 0.92 EUR
+2024-04-05 price AAPL 200 USD
+` + today + ` price AAPL 230 USD
+` + today + ` price USD 0.92 EUR
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+// TestJournalTrades_Types checks that trades writes transfers at their cost against Equity:Transfers and the cash transactions against
+// the accounts their cash comes from or goes to, leaving dividend transactions out
+func TestJournalTrades_Types(t *testing.T) {
+	// Arrange
+	transactions := []entities.Transaction{
+		{TransactionID: "transaction-a", StockID: "stock-aapl", TransactionType: entities.TransactionTypeTransferIn, Quantity: decimal.NewFromInt(10), TransactionPrice: entities.NewMoney(decimal.NewFromInt(170), "USD"), TransactionDate: time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "transaction-b", TransactionType: entities.TransactionTypeDeposit, TransactionPrice: entities.NewMoney(decimal.NewFromInt(1000), "USD"), TransactionDate: time.Date(2024, time.March, 6, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "transaction-c", StockID: "stock-aapl", TransactionType: entities.TransactionTypeTransferOut, Quantity: decimal.NewFromInt(-4), TransactionPrice: entities.NewMoney(decimal.NewFromInt(200), "USD"), TransactionDate: time.Date(2024, time.April, 5, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "transaction-d", StockID: "stock-aapl", TransactionType: entities.TransactionTypeDividend, TransactionPrice: entities.NewMoney(decimal.NewFromInt(3), "USD"), TransactionDate: time.Date(2024, time.May, 16, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "transaction-e", TransactionType: entities.TransactionTypeFee, TransactionPrice: entities.NewMoney(decimal.NewFromInt(5), "USD"), TransactionDate: time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "transaction-f", TransactionType: entities.TransactionTypeInterest, TransactionPrice: entities.NewMoney(decimal.NewFromInt(2), "USD"), TransactionDate: time.Date(2024, time.June, 30, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "transaction-g", TransactionType: entities.TransactionTypeWithdrawal, TransactionPrice: entities.NewMoney(decimal.NewFromInt(100), "USD"), TransactionDate: time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	ledger, err := buildLedger(transactions, entities.CostBasisFIFO)
+	assert.Nil(t, err)
+
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-aapl").Return(&entities.Stock{StockID: "stock-aapl", TickerSymbol: "AAPL", CompanyName: "Apple Inc."}, nil).Once()
+
+	service := &journalService{}
+	b := newJournalBuilder(entities.Portfolio{Name: "my retirement"}, "", entities.CostBasisFIFO, newExportLookup(nil, stockRepositoryMock, nil))
+	var buf bytes.Buffer
+
+	// Act
+	err = service.trades(context.Background(), b, transactions, ledger.gains, ledger.transfers)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, writeBeancount(&buf, b.build()))
+	expected := `2024-03-05 * "Transfer in 10 AAPL"
+  Assets:Portflux:MyRetirement:AAPL                            10 AAPL {170 USD}
+  Equity:Portflux:MyRetirement:Transfers                       -1700 USD
+
+2024-03-06 * "Deposit"
+  Assets:Portflux:MyRetirement:Cash                            1000 USD
+  Equity:Portflux:MyRetirement:Contributions                   -1000 USD
+
+2024-04-05 * "Transfer out 4 AAPL"
+  Assets:Portflux:MyRetirement:AAPL                            -4 AAPL {170 USD, 2024-03-05}
+  Equity:Portflux:MyRetirement:Transfers                       680 USD
+
+2024-06-01 * "Fee"
+  Assets:Portflux:MyRetirement:Cash                            -5 USD
+  Expenses:Portflux:MyRetirement:Fees                          5 USD
+
+2024-06-30 * "Interest"
+  Assets:Portflux:MyRetirement:Cash                            2 USD
+  Income:Portflux:MyRetirement:Interest                        -2 USD
+
+2024-07-01 * "Withdrawal"
+  Assets:Portflux:MyRetirement:Cash                            -100 USD
+  Equity:Portflux:MyRetirement:Contributions                   100 USD
+`
+	assert.Contains(t, buf.String(), expected)
+}
+
+// TestJournalTrades_CashBalances checks that trades pays the buys from Assets:Cash in the currency of the trade, so the balance of
+// Assets:Cash in every currency is the cash balance of the portfolio in it
+func TestJournalTrades_CashBalances(t *testing.T) {
+	// Arrange
+	transactions := []entities.Transaction{
+		{TransactionID: "transaction-a", TransactionType: entities.TransactionTypeDeposit, TransactionPrice: entities.NewMoney(decimal.NewFromInt(2000), "USD"), TransactionDate: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "transaction-b", StockID: "stock-aapl", TransactionType: entities.TransactionTypeBuy, Quantity: decimal.NewFromInt(10), TransactionPrice: entities.NewMoney(decimal.NewFromInt(170), "USD"), TransactionDate: time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "transaction-c", StockID: "stock-aapl", TransactionType: entities.TransactionTypeSell, Quantity: decimal.NewFromInt(-4), TransactionPrice: entities.NewMoney(decimal.NewFromInt(200), "USD"), TransactionDate: time.Date(2024, time.April, 5, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "transaction-d", TransactionType: entities.TransactionTypeFee, TransactionPrice: entities.NewMoney(decimal.NewFromInt(5), "USD"), TransactionDate: time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	ledger, err := buildLedger(transactions, entities.CostBasisFIFO)
+	assert.Nil(t, err)
+
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-aapl").Return(&entities.Stock{StockID: "stock-aapl", TickerSymbol: "AAPL", CompanyName: "Apple Inc."}, nil).Once()
+
+	service := &journalService{}
+	b := newJournalBuilder(entities.Portfolio{Name: "my retirement"}, "EUR", entities.CostBasisFIFO, newExportLookup(nil, stockRepositoryMock, nil))
+
+	// Act
+	err = service.trades(context.Background(), b, transactions, ledger.gains, ledger.transfers)
+
+	// Assert
+	assert.Nil(t, err)
+	cash := map[string]decimal.Decimal{}
+	for _, e := range b.entries {
+		for _, p := range e.postings {
+			switch p.account {
+			case "Assets:Portflux:MyRetirement:Cash":
+				cash[p.units.Currency] = cash[p.units.Currency].Add(p.units.Amount)
+			case "Equity:Portflux:MyRetirement:Contributions":
+				assert.Equal(t, "Deposit", e.narration)
+			}
+		}
+	}
+	balances := cashBalances(transactions)
+	assert.Len(t, cash, len(balances))
+	for _, balance := range balances {
+		assert.True(t, balance.Balance.Amount.Equal(cash[balance.Currency]), "cash in %s: want=%s but got=%s", balance.Currency, balance.Balance.Amount, cash[balance.Currency])
+	}
+	assert.Equal(t, []time.Time{time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)}, b.conversions["USD"])
+}
+
+// TestExportJournal_Hledger checks that Export writes an hledger journal posting the units sold at the average cost
+func TestExportJournal_Hledger(t *testing.T) {
+	// Arrange
+	service := newJournalTestService(t, entities.CostBasisAverage)
+	var buf bytes.Buffer
+
+	// Act
+	err := service.Export(context.Background(), "user-a", "portfolio-a", models.JournalReq{Format: models.JournalFormatHledger}, &buf)
+
+	// Assert
+	assert.Nil(t, err)
+	today := time.Now().UTC().Format("2006-01-02")
+	expected := `; Portflux journal of the portfolio my retirement
+
+; Apple Inc.
+commodity AAPL
+commodity USD
+; Dogecoin
+commodity DOGE
+commodity EUR
+
+account Assets:Portflux:MyRetirement:AAPL
+account Assets:Portflux:MyRetirement:Cash
+account Assets:Portflux:MyRetirement:DOGE
+account Income:Portflux:MyRetirement:CapitalGains
+account Expenses:Portflux:MyRetirement:Taxes:Withholding
+account Income:Portflux:MyRetirement:Dividends
+
+2024-03-05 * Buy 10 AAPL
+    Assets:Portflux:MyRetirement:AAPL                            10 AAPL @ 170 USD
+    Assets:Portflux:MyRetirement:Cash                            -1700 USD
+
+2024-03-06 * Buy 100 DOGE
+    Assets:Portflux:MyRetirement:DOGE                            100 DOGE @ 0.1 EUR
+    Assets:Portflux:MyRetirement:Cash                            -10 EUR
+
+2024-03-10 * Buy 5 AAPL
+    Assets:Portflux:MyRetirement:AAPL                            5 AAPL @ 180 USD
+    Assets:Portflux:MyRetirement:Cash                            -900 USD
+
+2024-04-05 * Sell 12 AAPL
+    Assets:Portflux:MyRetirement:AAPL                            -12 AAPL @@ 2080 USD  ; sold at 200 USD
+    Assets:Portflux:MyRetirement:Cash                            2400 USD
+    Income:Portflux:MyRetirement:CapitalGains                    -320 USD
+
+2024-05-16 * Dividend AAPL 0.24 USD per share on 3 shares
+    Assets:Portflux:MyRetirement:Cash                            0.612 USD
+    Expenses:Portflux:MyRetirement:Taxes:Withholding             0.108 USD
+    Income:Portflux:MyRetirement:Dividends                       -0.72 USD
+
+P 2024-03-05 AAPL 170 USD
+P 2024-03-05 USD 0.92 EUR
+P 2024-03-10 USD 0.92 EUR
+P 2024-04-05 AAPL 200 USD
+P ` + today + ` AAPL 230 USD
+P ` + today + ` USD 0.92 EUR
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+// TestExportJournal_InvalidRequest checks that Export returns a validation error when the format is not supported
+func TestExportJournal_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := &journalService{}
+
+	// Act
+	err := service.Export(context.Background(), "user-a", "portfolio-a", models.JournalReq{Format: "ledger"}, &bytes.Buffer{})
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
+
+// TestExportJournal_PortfolioNotFound checks that Export returns a non existent error when the portfolio does not exist
+func TestExportJournal_PortfolioNotFound(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &journalService{portfolioRepository: portfolioRepositoryMock}
+	var buf bytes.Buffer
+
+	// Act
+	err := service.Export(context.Background(), "user-a", "portfolio-a", models.JournalReq{Format: models.JournalFormatBeancount}, &buf)
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, "ID portfolio-a not found", err.Error())
+	assert.Empty(t, buf.String())
+}
+
+// TestExportJournal_OtherUser checks that Export returns an unauthorized error without writing anything when the portfolio belongs
+// to another user
+func TestExportJournal_OtherUser(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-a").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &journalService{portfolioRepository: portfolioRepositoryMock, transactionRepository: transactionRepositoryMock}
+	var buf bytes.Buffer
+
+	// Act
+	err := service.Export(context.Background(), "user-b", "portfolio-a", models.JournalReq{Format: models.JournalFormatBeancount}, &buf)
+
+	// Assert
+	assert.Equal(t, wrappers.NewUnauthorizedErr(fmt.Errorf("portfolio portfolio-a belongs to another user")), err)
+	assert.Empty(t, buf.String())
+}
+
+// TestCommodityName_Ok checks that commodityName returns commodities valid in Beancount and hledger
+func TestCommodityName_Ok(t *testing.T) {
+	// Arrange
+	cases := map[string]string{
+		"AAPL":                         "AAPL",
+		"brk.b":                        "BRK.B",
+		"VWCE DE":                      "VWCE-DE",
+		"F":                            "XF",
+		"7203.T":                       "X7203.T",
+		"ETH/USD ":                     "ETH-USD",
+		"SPDR S&P 500 ETF Trust Units": "SPDR-S-P-500-ETF-TRUST-U",
+	}
+
+	for symbol, expected := range cases {
+		// Act
+		commodity := commodityName(symbol)
+
+		// Assert
+		assert.Equal(t, expected, commodity)
+	}
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// lotService adapter of a lot service
+type lotService struct {
+	config                config.Config
+	repository            ports.LotRepository
+	portfolioRepository   ports.PortfolioRepository
+	transactionRepository ports.TransactionRepository
+}
+
+// NewLotService creates a new lot service
+func NewLotService(cfg config.Config, repo ports.LotRepository, portfolioRepo ports.PortfolioRepository, transactionRepo ports.TransactionRepository) ports.LotService {
+	return &lotService{
+		config:                cfg,
+		repository:            repo,
+		portfolioRepository:   portfolioRepo,
+		transactionRepository: transactionRepo,
+	}
+}
+
+// GetLots of a portfolio of the user
+func (s *lotService) GetLots(ctx context.Context, userID, portfolioID string) (resp []models.LotResp, err error) {
+	if _, err = userPortfolio(ctx, s.portfolioRepository, userID, portfolioID); err != nil {
+		return
+	}
+	return s.lots(ctx, portfolioID)
+}
+
+// GetRealizedGains of a portfolio of the user
+func (s *lotService) GetRealizedGains(ctx context.Context, userID, portfolioID string) (resp []models.RealizedGainResp, err error) {
+	if _, err = userPortfolio(ctx, s.portfolioRepository, userID, portfolioID); err != nil {
+		return
+	}
+	return s.realizedGains(ctx, portfolioID)
+}
+
+// lots returns the stored lots of a portfolio
+func (s *lotService) lots(ctx context.Context, portfolioID string) (resp []models.LotResp, err error) {
+	result, err := s.repository.GetByPortfolioID(ctx, portfolioID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+			resp = []models.LotResp{}
+		}
+		return
+	}
+
+	resp = make([]models.LotResp, len(result))
+	for i, v := range result {
+		resp[i] = models.LotResp(*(v.(*entities.Lot)))
+	}
+
+	return
+}
+
+// realizedGains returns the stored realized gains of a portfolio
+func (s *lotService) realizedGains(ctx context.Context, portfolioID string) (resp []models.RealizedGainResp, err error) {
+	result, err := s.repository.GetRealizedGainsByPortfolioID(ctx, portfolioID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+			resp = []models.RealizedGainResp{}
+		}
+		return
+	}
+
+	resp = make([]models.RealizedGainResp, len(result))
+	for i, v := range result {
+		resp[i] = models.RealizedGainResp(*(v.(*entities.RealizedGain)))
+	}
+
+	return
+}
+
+// Rebuild recomputes the lot ledger of a portfolio of the user from its transactions with the portfolio's cost basis method
+func (s *lotService) Rebuild(ctx context.Context, userID, portfolioID string) (resp models.LedgerResp, err error) {
+	portfolio, transactions, err := loadPortfolioTransactions(ctx, s.portfolioRepository, s.transactionRepository, portfolioID)
+	if err != nil {
+		return
+	}
+	if err = checkOwner(portfolio, userID); err != nil {
+		return
+	}
+
+	method := costBasisMethod(portfolio)
+	err = replaceLedger(ctx, s.repository, portfolioID, method, transactions)
+	if err != nil {
+		return
+	}
+
+	resp = models.LedgerResp{
+		PortfolioID:     portfolioID,
+		CostBasisMethod: method,
+	}
+	resp.Lots, err = s.lots(ctx, portfolioID)
+	if err != nil {
+		return
+	}
+	resp.RealizedGains, err = s.realizedGains(ctx, portfolioID)
+
+	return
+}
+
+// UpdateCostBasisMethod switches the cost basis method of a portfolio of the user and rebuilds its lot ledger
+func (s *lotService) UpdateCostBasisMethod(ctx context.Context, userID, portfolioID string, req models.UpdateCostBasisMethodReq) (resp models.LedgerResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	portfolio, err := userPortfolio(ctx, s.portfolioRepository, userID, portfolioID)
+	if err != nil {
+		return
+	}
+
+	if portfolio.PerformenceCalculation != req.Method {
+		portfolio.PerformenceCalculation = req.Method
+		portfolio.UpdatedAt = time.Now().UTC()
+
+		err = s.portfolioRepository.Update(ctx, portfolioID, portfolio)
+		if err != nil {
+			return
+		}
+	}
+
+	return s.Rebuild(ctx, userID, portfolioID)
+}
+
+// Refresh rebuilds the lot ledger of a portfolio after its transactions or the corporate actions of its stocks changed, so the stored
+// lots and realized gains never fall behind them. When the transactions no longer make up a ledger, as when a sell is entered before
+// its buy, the stored one is cleared rather than left stale, and Rebuild reports why.
+func (s *lotService) Refresh(ctx context.Context, portfolioID string) error {
+	portfolio, transactions, err := loadPortfolioTransactions(ctx, s.portfolioRepository, s.transactionRepository, portfolioID)
+	if err != nil {
+		return err
+	}
+
+	ledger, err := buildLedger(transactions, costBasisMethod(portfolio))
+	if err != nil {
+		return s.repository.ReplaceLedger(ctx, portfolioID, nil, nil)
+	}
+	return storeLedger(ctx, s.repository, portfolioID, ledger)
+}
+
+// replaceLedger rebuilds the lots and realized gains of a portfolio from its transactions with a cost basis method
+func replaceLedger(ctx context.Context, repo ports.LotRepository, portfolioID string, method int, transactions []entities.Transaction) error {
+	ledger, err := buildLedger(transactions, method)
+	if err != nil {
+		return err
+	}
+	return storeLedger(ctx, repo, portfolioID, ledger)
+}
+
+// storeLedger replaces the lots and realized gains of a portfolio with the ones of a ledger
+func storeLedger(ctx context.Context, repo ports.LotRepository, portfolioID string, ledger ledger) error {
+	now := time.Now().UTC()
+	lots := make([]interface{}, len(ledger.lots))
+	for i, l := range ledger.lots {
+		l.PortfolioID = portfolioID
+		l.CreatedAt = now
+		l.UpdatedAt = now
+		lots[i] = l
+	}
+	gains := make([]interface{}, len(ledger.gains))
+	for i, g := range ledger.gains {
+		g.PortfolioID = portfolioID
+		g.CreatedAt = now
+		g.UpdatedAt = now
+		gains[i] = g
+	}
+
+	return repo.ReplaceLedger(ctx, portfolioID, lots, gains)
+}
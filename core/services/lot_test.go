@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestNewLotService_Ok checks that NewLotService creates a new lotService struct
+func TestNewLotService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	lotRepositoryMock := mocks.NewLotRepository(t)
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+
+	// Act
+	service := NewLotService(cfg, lotRepositoryMock, portfolioRepositoryMock, transactionRepositoryMock)
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestGetLots_Ok checks that GetLots returns the expected response when a valid portfolio ID is received
+func TestGetLots_Ok(t *testing.T) {
+	// Arrange
+	expectedResponse := []models.LotResp{{LotID: "lot-id", PortfolioID: "portfolio-id", Quantity: decimal.NewFromInt(10), RemainingQuantity: decimal.NewFromInt(5)}}
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-id").Return(&entities.Portfolio{PortfolioID: "portfolio-id", Userid: "user-id"}, nil).Once()
+
+	lotRepositoryMock := mocks.NewLotRepository(t)
+	lotRepositoryMock.On(testutils.FunctionName(t, ports.LotRepository.GetByPortfolioID), context.Background(), "portfolio-id").Return([]interface{}{&entities.Lot{LotID: "lot-id", PortfolioID: "portfolio-id", Quantity: decimal.NewFromInt(10), RemainingQuantity: decimal.NewFromInt(5)}}, nil).Once()
+
+	service := &lotService{
+		config:              config.Config{},
+		repository:          lotRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetLots(context.Background(), "user-id", "portfolio-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResponse, resp)
+}
+
+// TestGetLots_NoLots checks that GetLots returns an empty response when the portfolio has no lots
+func TestGetLots_NoLots(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-id").Return(&entities.Portfolio{PortfolioID: "portfolio-id", Userid: "user-id"}, nil).Once()
+
+	lotRepositoryMock := mocks.NewLotRepository(t)
+	lotRepositoryMock.On(testutils.FunctionName(t, ports.LotRepository.GetByPortfolioID), context.Background(), "portfolio-id").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &lotService{
+		config:              config.Config{},
+		repository:          lotRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetLots(context.Background(), "user-id", "portfolio-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []models.LotResp{}, resp)
+}
+
+// TestGetRealizedGains_GetRealizedGainsByPortfolioIDError checks that GetRealizedGains returns an error when the repository fails
+func TestGetRealizedGains_GetRealizedGainsByPortfolioIDError(t *testing.T) {
+	// Arrange
+	expectedError := "repository-error"
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-id").Return(&entities.Portfolio{PortfolioID: "portfolio-id", Userid: "user-id"}, nil).Once()
+
+	lotRepositoryMock := mocks.NewLotRepository(t)
+	lotRepositoryMock.On(testutils.FunctionName(t, ports.LotRepository.GetRealizedGainsByPortfolioID), context.Background(), "portfolio-id").Return(nil, fmt.Errorf(expectedError)).Once()
+
+	service := &lotService{
+		config:              config.Config{},
+		repository:          lotRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+	}
+
+	// Act
+	_, err := service.GetRealizedGains(context.Background(), "user-id", "portfolio-id")
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetRealizedGains_OtherUser checks that GetRealizedGains returns an unauthorized error without reading the gains when the
+// portfolio belongs to another user
+func TestGetRealizedGains_OtherUser(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-id").Return(&entities.Portfolio{PortfolioID: "portfolio-id", Userid: "user-b"}, nil).Once()
+
+	service := &lotService{
+		config:              config.Config{},
+		repository:          mocks.NewLotRepository(t),
+		portfolioRepository: portfolioRepositoryMock,
+	}
+
+	// Act
+	_, err := service.GetRealizedGains(context.Background(), "user-id", "portfolio-id")
+
+	// Assert
+	assert.Equal(t, wrappers.NewUnauthorizedErr(fmt.Errorf("portfolio portfolio-id belongs to another user")), err)
+}
+
+// TestRebuild_Ok checks that Rebuild replaces the ledger of the portfolio with the one built from its transactions
+func TestRebuild_Ok(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-id", PerformenceCalculation: entities.CostBasisLIFO}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{
+		&entities.Transaction{TransactionID: "1", StockID: "stock-a", Quantity: decimal.RequireFromString("10"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("10"), "CZK"), TransactionDate: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		&entities.Transaction{TransactionID: "2", StockID: "stock-a", Quantity: decimal.RequireFromString("-4"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("15"), "CZK"), TransactionDate: time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC)},
+	}, nil).Once()
+
+	lotRepositoryMock := mocks.NewLotRepository(t)
+	lotRepositoryMock.On(testutils.FunctionName(t, ports.LotRepository.ReplaceLedger), context.Background(), portfolioID, mock.MatchedBy(func(lots []interface{}) bool {
+		return len(lots) == 1 && lots[0].(entities.Lot).RemainingQuantity.Equal(decimal.NewFromInt(6)) && lots[0].(entities.Lot).PortfolioID == portfolioID
+	}), mock.MatchedBy(func(gains []interface{}) bool {
+		return len(gains) == 1 && gains[0].(entities.RealizedGain).Gain.String() == "20 CZK" && gains[0].(entities.RealizedGain).Method == entities.CostBasisLIFO
+	})).Return(nil).Once()
+	lotRepositoryMock.On(testutils.FunctionName(t, ports.LotRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{&entities.Lot{LotID: "lot-id"}}, nil).Once()
+	lotRepositoryMock.On(testutils.FunctionName(t, ports.LotRepository.GetRealizedGainsByPortfolioID), context.Background(), portfolioID).Return([]interface{}{&entities.RealizedGain{RealizedGainID: "gain-id"}}, nil).Once()
+
+	service := &lotService{
+		config:                config.Config{},
+		repository:            lotRepositoryMock,
+		portfolioRepository:   portfolioRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Rebuild(context.Background(), "user-id", portfolioID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, entities.CostBasisLIFO, resp.CostBasisMethod)
+	assert.Equal(t, []models.LotResp{{LotID: "lot-id"}}, resp.Lots)
+	assert.Equal(t, []models.RealizedGainResp{{RealizedGainID: "gain-id"}}, resp.RealizedGains)
+}
+
+// TestRebuild_OversoldError checks that Rebuild returns an error and keeps the stored ledger when the transactions sell more than is held
+func TestRebuild_OversoldError(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+	expectedError := "transaction 1 sells 1 units of stock-a but only 0 are held"
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-id"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{
+		&entities.Transaction{TransactionID: "1", StockID: "stock-a", Quantity: decimal.RequireFromString("-1"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("10"), "CZK")},
+	}, nil).Once()
+
+	service := &lotService{
+		config:                config.Config{},
+		repository:            mocks.NewLotRepository(t),
+		portfolioRepository:   portfolioRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Rebuild(context.Background(), "user-id", portfolioID)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestRefresh_Ok checks that Refresh replaces the stored ledger with the one built from the transactions of the portfolio
+func TestRefresh_Ok(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{
+		&entities.Transaction{TransactionID: "1", StockID: "stock-a", Quantity: decimal.RequireFromString("10"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("10"), "CZK"), TransactionDate: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}, nil).Once()
+
+	lotRepositoryMock := mocks.NewLotRepository(t)
+	lotRepositoryMock.On(testutils.FunctionName(t, ports.LotRepository.ReplaceLedger), context.Background(), portfolioID, mock.MatchedBy(func(lots []interface{}) bool {
+		return len(lots) == 1 && lots[0].(entities.Lot).RemainingQuantity.Equal(decimal.NewFromInt(10))
+	}), mock.MatchedBy(func(gains []interface{}) bool {
+		return len(gains) == 0
+	})).Return(nil).Once()
+
+	service := &lotService{
+		config:                config.Config{},
+		repository:            lotRepositoryMock,
+		portfolioRepository:   portfolioRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+	}
+
+	// Act
+	err := service.Refresh(context.Background(), portfolioID)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestRefresh_OversoldClearsLedger checks that Refresh clears the stored ledger when the transactions sell more than is held
+func TestRefresh_OversoldClearsLedger(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{
+		&entities.Transaction{TransactionID: "1", StockID: "stock-a", Quantity: decimal.RequireFromString("-1"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("10"), "CZK")},
+	}, nil).Once()
+
+	lotRepositoryMock := mocks.NewLotRepository(t)
+	lotRepositoryMock.On(testutils.FunctionName(t, ports.LotRepository.ReplaceLedger), context.Background(), portfolioID, []interface{}(nil), []interface{}(nil)).Return(nil).Once()
+
+	service := &lotService{
+		config:                config.Config{},
+		repository:            lotRepositoryMock,
+		portfolioRepository:   portfolioRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+	}
+
+	// Act
+	err := service.Refresh(context.Background(), portfolioID)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestUpdateCostBasisMethod_InvalidRequest checks that UpdateCostBasisMethod returns an error when the received request is not valid
+func TestUpdateCostBasisMethod_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := &lotService{
+		config: config.Config{},
+	}
+	expectedError := "method cannot be zero"
+
+	// Act
+	_, err := service.UpdateCostBasisMethod(context.Background(), "user-id", "portfolio-id", models.UpdateCostBasisMethodReq{})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestUpdateCostBasisMethod_UpdateError checks that UpdateCostBasisMethod returns an error when the portfolio cannot be updated
+func TestUpdateCostBasisMethod_UpdateError(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+	expectedError := "update-error"
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-id", PerformenceCalculation: entities.CostBasisFIFO}, nil).Once()
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.Update), context.Background(), portfolioID, mock.MatchedBy(func(p entities.Portfolio) bool {
+		return p.PerformenceCalculation == entities.CostBasisAverage
+	})).Return(fmt.Errorf(expectedError)).Once()
+
+	service := &lotService{
+		config:              config.Config{},
+		portfolioRepository: portfolioRepositoryMock,
+	}
+
+	// Act
+	_, err := service.UpdateCostBasisMethod(context.Background(), "user-id", portfolioID, models.UpdateCostBasisMethodReq{Method: entities.CostBasisAverage})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestUpdateCostBasisMethod_OtherUser checks that UpdateCostBasisMethod returns an unauthorized error and leaves the portfolio as it
+// was when it belongs to another user
+func TestUpdateCostBasisMethod_OtherUser(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-b", PerformenceCalculation: entities.CostBasisFIFO}, nil).Once()
+
+	service := &lotService{
+		config:              config.Config{},
+		portfolioRepository: portfolioRepositoryMock,
+	}
+
+	// Act
+	_, err := service.UpdateCostBasisMethod(context.Background(), "user-id", portfolioID, models.UpdateCostBasisMethodReq{Method: entities.CostBasisAverage})
+
+	// Assert
+	assert.IsType(t, wrappers.UnauthorizedErr, err)
+	assert.Equal(t, "portfolio portfolio-id belongs to another user", err.Error())
+}
@@ -0,0 +1,554 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+const (
+	oneDay            = 24 * time.Hour
+	daysPerYear       = 365.0
+	xirrMaxIterations = 200
+	xirrTolerance     = 1e-9
+)
+
+// performanceService adapter of a performance service
+type performanceService struct {
+	config                config.Config
+	portfolioRepository   ports.PortfolioRepository
+	holdingRepository     ports.HoldingRepository
+	transactionRepository ports.TransactionRepository
+	dividendRepository    ports.DividendRepository
+	userRepository        ports.UserRepository
+	priceSource           ports.PriceSource
+	converter             ports.CurrencyConverter
+}
+
+// NewPerformanceService creates a new performance service
+func NewPerformanceService(cfg config.Config, portfolioRepo ports.PortfolioRepository, holdingRepo ports.HoldingRepository, transactionRepo ports.TransactionRepository, dividendRepo ports.DividendRepository, userRepo ports.UserRepository, priceSource ports.PriceSource, converter ports.CurrencyConverter) ports.PerformanceService {
+	return &performanceService{
+		config:                cfg,
+		portfolioRepository:   portfolioRepo,
+		holdingRepository:     holdingRepo,
+		transactionRepository: transactionRepo,
+		dividendRepository:    dividendRepo,
+		userRepository:        userRepo,
+		priceSource:           priceSource,
+		converter:             converter,
+	}
+}
+
+// GetPortfolioPerformance returns the time-weighted and money-weighted returns of a portfolio over the requested range,
+// in the base currency of the portfolio when it has one. The portfolio must belong to the user.
+func (s *performanceService) GetPortfolioPerformance(ctx context.Context, userID, portfolioID string, req models.PerformanceReq) (resp models.PerformanceResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	portfolio, transactions, err := loadPortfolioTransactions(ctx, s.portfolioRepository, s.transactionRepository, portfolioID)
+	if err != nil {
+		return
+	}
+	if err = checkOwner(portfolio, userID); err != nil {
+		return
+	}
+
+	currency, err := baseCurrency(ctx, s.userRepository, portfolio)
+	if err != nil {
+		return
+	}
+
+	resp, err = s.performance(ctx, transactions, req, currency)
+	resp.PortfolioID = portfolioID
+
+	return
+}
+
+// GetHoldingPerformance returns the time-weighted and money-weighted returns of a holding over the requested range,
+// in the base currency of its portfolio when it has one. The portfolio of the holding must belong to the user.
+func (s *performanceService) GetHoldingPerformance(ctx context.Context, userID, holdingID string, req models.PerformanceReq) (resp models.PerformanceResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	holding, err := s.holdingRepository.GetByID(ctx, holdingID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", holdingID))
+		}
+		return
+	}
+
+	portfolio, err := userPortfolio(ctx, s.portfolioRepository, userID, holding.(*entities.Holding).PortfolioID)
+	if err != nil {
+		return
+	}
+
+	currency, err := baseCurrency(ctx, s.userRepository, portfolio)
+	if err != nil {
+		return
+	}
+
+	var transactions []entities.Transaction
+	result, err := s.transactionRepository.Get(ctx, map[string]interface{}{"holdingid": holdingID}, nil, nil)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return
+	}
+	for _, v := range result {
+		transactions = append(transactions, *(v.(*entities.Transaction)))
+	}
+
+	resp, err = s.performance(ctx, transactions, req, currency)
+	resp.HoldingID = holdingID
+
+	return
+}
+
+// trade is a parsed transaction used to replay the holdings day by day. External trades are the cash flows of the investor,
+// contributing their contribution, and cash is the cash they move within the portfolio when it is tracked.
+type trade struct {
+	instrumentID   string
+	instrumentType string
+	quantity       decimal.Decimal
+	price          entities.Money
+	date           time.Time
+	cash           entities.Money
+	contribution   entities.Money
+	external       bool
+	dividend       bool
+}
+
+// cashFlow is an amount invested (negative) or returned (positive) on a date, seen from the investor
+type cashFlow struct {
+	date   time.Time
+	amount float64
+}
+
+// performanceRun keeps the state of the holdings and the cash while transactions are replayed over a date range
+type performanceRun struct {
+	service   *performanceService
+	currency  string
+	holdings  map[string]decimal.Decimal
+	lastPrice map[string]entities.Money
+	cash      map[string]decimal.Decimal
+}
+
+// performance replays transactions day by day over the requested range. Every day with a transaction or a dividend
+// closes a sub-period of the time-weighted return, whose growth is the value before that day's external cash flows plus the
+// dividends paid over the value left by the previous day. When the transactions record deposits or withdrawals, the value includes
+// the cash of the portfolio and deposits, withdrawals and transfers are the external cash flows, while trades, fees, interest and the
+// payments of dividends only move value within the portfolio. Otherwise buys, sells and transfers are the external cash flows and the
+// stored dividends of the stocks are income. The money-weighted return is the XIRR of the starting value, the external cash flows,
+// the dividends paid out and the ending value. Days are UTC calendar days and every value is taken at the close of its day. With a
+// currency every amount is converted at the rate of the day it is taken on, so currency movements are part of the returns.
+func (s *performanceService) performance(ctx context.Context, transactions []entities.Transaction, req models.PerformanceReq, currency string) (resp models.PerformanceResp, err error) {
+	withCash := false
+	for _, t := range transactions {
+		if t.Type() == entities.TransactionTypeDeposit || t.Type() == entities.TransactionTypeWithdrawal {
+			withCash = true
+			break
+		}
+	}
+
+	trades := make([]trade, 0, len(transactions))
+	for _, t := range transactions {
+		tr := trade{date: t.TransactionDate, external: !withCash}
+		if t.MovesShares() {
+			tr.instrumentID, tr.instrumentType = instrumentOf(t)
+			tr.quantity = t.Quantity
+			tr.price = t.TransactionPrice
+			tr.contribution = t.TransactionPrice.Mul(t.Quantity)
+		}
+		if withCash {
+			switch t.Type() {
+			case entities.TransactionTypeDeposit, entities.TransactionTypeWithdrawal:
+				tr.external = true
+				tr.contribution = t.CashFlow()
+			case entities.TransactionTypeTransferIn, entities.TransactionTypeTransferOut:
+				tr.external = true
+			}
+			tr.cash = t.CashFlow()
+			tr.dividend = t.Type() == entities.TransactionTypeDividend
+		}
+		if tr.instrumentID == "" && tr.cash.IsZero() {
+			continue
+		}
+		trades = append(trades, tr)
+	}
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].date.Before(trades[j].date)
+	})
+
+	to := startOfDay(req.To)
+	if req.To.IsZero() {
+		to = startOfDay(time.Now().UTC())
+	}
+	from := startOfDay(req.From)
+	if req.From.IsZero() {
+		from = to
+		if len(trades) > 0 {
+			from = startOfDay(trades[0].date)
+		}
+	}
+	resp.From = from
+	resp.To = to
+
+	dividends := map[time.Time][]entities.Dividend{}
+	if !withCash {
+		if dividends, err = s.dividendsByDay(ctx, trades, from, to); err != nil {
+			return
+		}
+	}
+
+	run := &performanceRun{
+		service:   s,
+		currency:  currency,
+		holdings:  map[string]decimal.Decimal{},
+		lastPrice: map[string]entities.Money{},
+		cash:      map[string]decimal.Decimal{},
+	}
+
+	i := 0
+	for ; i < len(trades) && trades[i].date.Before(from); i++ {
+		run.apply(trades[i])
+	}
+
+	resp.StartValue, err = run.value(ctx, from.Add(-oneDay))
+	if err != nil {
+		return
+	}
+
+	var flows []cashFlow
+	if resp.StartValue.Amount.IsPositive() {
+		flows = append(flows, cashFlow{date: from, amount: -resp.StartValue.Amount.InexactFloat64()})
+	}
+
+	days := map[time.Time]bool{}
+	for _, t := range trades[i:] {
+		if d := startOfDay(t.date); !d.After(to) {
+			days[d] = true
+		}
+	}
+	for d := range dividends {
+		days[d] = true
+	}
+	eventDays := make([]time.Time, 0, len(days))
+	for d := range days {
+		eventDays = append(eventDays, d)
+	}
+	sort.Slice(eventDays, func(i, j int) bool {
+		return eventDays[i].Before(eventDays[j])
+	})
+
+	growth := 1.0
+	base := resp.StartValue
+	for _, d := range eventDays {
+		var day []trade
+		for ; i < len(trades) && startOfDay(trades[i].date).Equal(d); i++ {
+			day = append(day, trades[i])
+		}
+
+		for _, t := range day {
+			if t.external {
+				continue
+			}
+			if t.dividend {
+				amount, convertErr := run.convert(ctx, t.cash, d)
+				if convertErr != nil {
+					err = convertErr
+					return
+				}
+				if resp.Dividends, err = resp.Dividends.Add(amount); err != nil {
+					return
+				}
+			}
+			run.apply(t)
+		}
+
+		before, valueErr := run.value(ctx, d)
+		if valueErr != nil {
+			err = valueErr
+			return
+		}
+
+		var paid entities.Money
+		for _, dividend := range dividends[d] {
+			amount, convertErr := run.convert(ctx, dividend.DividendPerShare.Mul(sharesEntitled(transactions, dividend)), d)
+			if convertErr != nil {
+				err = convertErr
+				return
+			}
+			if paid, err = paid.Add(amount); err != nil {
+				return
+			}
+		}
+		if base.Amount.IsPositive() {
+			after, addErr := before.Add(paid)
+			if addErr != nil {
+				err = addErr
+				return
+			}
+			growth *= after.Amount.Div(base.Amount).InexactFloat64()
+		}
+		if !paid.IsZero() {
+			if resp.Dividends, err = resp.Dividends.Add(paid); err != nil {
+				return
+			}
+			flows = append(flows, cashFlow{date: d, amount: paid.Amount.InexactFloat64()})
+		}
+
+		for _, t := range day {
+			if !t.external {
+				continue
+			}
+			amount, convertErr := run.convert(ctx, t.contribution, d)
+			if convertErr != nil {
+				err = convertErr
+				return
+			}
+			if resp.NetContributions, err = resp.NetContributions.Add(amount); err != nil {
+				return
+			}
+			flows = append(flows, cashFlow{date: d, amount: -amount.Amount.InexactFloat64()})
+			run.apply(t)
+		}
+
+		base, err = run.value(ctx, d)
+		if err != nil {
+			return
+		}
+	}
+
+	resp.EndValue, err = run.value(ctx, to)
+	if err != nil {
+		return
+	}
+	if base.Amount.IsPositive() {
+		growth *= resp.EndValue.Amount.Div(base.Amount).InexactFloat64()
+	}
+	if resp.EndValue.Amount.IsPositive() {
+		flows = append(flows, cashFlow{date: to, amount: resp.EndValue.Amount.InexactFloat64()})
+	}
+
+	twr := growth - 1
+	resp.TimeWeightedReturnPct = twr * 100
+	if years := to.Sub(from).Hours() / 24 / daysPerYear; years > 0 {
+		resp.TimeWeightedReturnAnnualizedPct = (math.Pow(growth, 1/years) - 1) * 100
+	}
+
+	if hasInflowsAndOutflows(flows) {
+		rate, xirrErr := xirr(flows)
+		if xirrErr != nil {
+			err = xirrErr
+			return
+		}
+		resp.MoneyWeightedReturnAnnualizedPct = rate * 100
+		years := flows[len(flows)-1].date.Sub(flows[0].date).Hours() / 24 / daysPerYear
+		resp.MoneyWeightedReturnPct = (math.Pow(1+rate, years) - 1) * 100
+	}
+
+	return
+}
+
+// dividendsByDay returns the dividends of the traded stocks paid within the range, grouped by day
+func (s *performanceService) dividendsByDay(ctx context.Context, trades []trade, from, to time.Time) (map[time.Time][]entities.Dividend, error) {
+	dividends := map[time.Time][]entities.Dividend{}
+	seen := map[string]bool{}
+	for _, t := range trades {
+		if t.instrumentType != entities.EntityNameStock || seen[t.instrumentID] {
+			continue
+		}
+		seen[t.instrumentID] = true
+
+		result, err := s.dividendRepository.Get(ctx, map[string]interface{}{"stockid": t.instrumentID}, nil, nil)
+		if err != nil {
+			if errors.Is(err, wrappers.NonExistentErr) {
+				continue
+			}
+			return nil, err
+		}
+
+		for _, v := range result {
+			dividend := *(v.(*entities.Dividend))
+			d := startOfDay(dividend.PaidOn())
+			if d.Before(from) || d.After(to) {
+				continue
+			}
+			dividends[d] = append(dividends[d], dividend)
+		}
+	}
+
+	return dividends, nil
+}
+
+// sharesEntitled returns the shares of a stock held before the ex-date of its dividend, which are the ones the dividend is paid on.
+// The transactions are counted as they were on the ex-date, so corporate actions taking effect later do not change the shares the
+// dividend per share was paid on.
+func sharesEntitled(transactions []entities.Transaction, dividend entities.Dividend) decimal.Decimal {
+	exDate := startOfDay(dividend.ExDate)
+	var shares decimal.Decimal
+	for _, t := range transactions {
+		if !startOfDay(t.TransactionDate).Before(exDate) {
+			continue
+		}
+		if stockID, quantity := t.SharesOn(exDate); stockID == dividend.StockID {
+			shares = shares.Add(quantity)
+		}
+	}
+	return shares
+}
+
+// apply adds a trade to the holdings and its cash to the cash of its currency
+func (r *performanceRun) apply(t trade) {
+	if t.instrumentID != "" {
+		r.holdings[t.instrumentID] = r.holdings[t.instrumentID].Add(t.quantity)
+		r.lastPrice[t.instrumentID] = t.price
+	}
+	if !t.cash.IsZero() {
+		r.cash[t.cash.Currency] = r.cash[t.cash.Currency].Add(t.cash.Amount)
+	}
+}
+
+// value returns the value of the holdings and the cash at the close of the given day. Instruments without a price
+// at that day are valued at their last traded price.
+func (r *performanceRun) value(ctx context.Context, d time.Time) (entities.Money, error) {
+	ids := make([]string, 0, len(r.holdings))
+	for id, quantity := range r.holdings {
+		if quantity.IsPositive() {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var total entities.Money
+	for _, id := range ids {
+		price, err := r.service.priceSource.PriceAt(ctx, id, d.Add(oneDay-time.Nanosecond))
+		if err != nil {
+			if !errors.Is(err, wrappers.NonExistentErr) {
+				return entities.Money{}, err
+			}
+			price = r.lastPrice[id]
+		}
+
+		value, err := r.convert(ctx, price.Mul(r.holdings[id]), d)
+		if err != nil {
+			return entities.Money{}, err
+		}
+		if total, err = total.Add(value); err != nil {
+			return entities.Money{}, err
+		}
+	}
+
+	currencies := make([]string, 0, len(r.cash))
+	for currency, amount := range r.cash {
+		if !amount.IsZero() {
+			currencies = append(currencies, currency)
+		}
+	}
+	sort.Strings(currencies)
+
+	for _, currency := range currencies {
+		value, err := r.convert(ctx, entities.NewMoney(r.cash[currency], currency), d)
+		if err != nil {
+			return entities.Money{}, err
+		}
+		if total, err = total.Add(value); err != nil {
+			return entities.Money{}, err
+		}
+	}
+
+	return total, nil
+}
+
+// convert returns an amount in the currency of the run at the rate of the given day, or the amount itself when the run has no currency
+func (r *performanceRun) convert(ctx context.Context, amount entities.Money, d time.Time) (entities.Money, error) {
+	if r.currency == "" {
+		return amount, nil
+	}
+	return r.service.converter.Convert(ctx, amount, r.currency, d)
+}
+
+// hasInflowsAndOutflows reports whether the cash flows contain at least one negative and one positive amount
+func hasInflowsAndOutflows(flows []cashFlow) bool {
+	var in, out bool
+	for _, f := range flows {
+		in = in || f.amount < 0
+		out = out || f.amount > 0
+	}
+	return in && out
+}
+
+// xirr returns the annual rate that makes the net present value of the chronologically sorted cash flows zero.
+// It uses Newton's method and falls back to bisection when Newton does not converge.
+func xirr(flows []cashFlow) (float64, error) {
+	years := make([]float64, len(flows))
+	for i, f := range flows {
+		years[i] = f.date.Sub(flows[0].date).Hours() / 24 / daysPerYear
+	}
+	npv := func(rate float64) (value, derivative float64) {
+		for i, f := range flows {
+			discount := math.Pow(1+rate, years[i])
+			value += f.amount / discount
+			derivative -= years[i] * f.amount / (discount * (1 + rate))
+		}
+		return
+	}
+
+	rate := 0.1
+	for i := 0; i < xirrMaxIterations; i++ {
+		value, derivative := npv(rate)
+		if derivative == 0 {
+			break
+		}
+		next := rate - value/derivative
+		if next <= -1 || math.IsNaN(next) || math.IsInf(next, 0) {
+			break
+		}
+		if math.Abs(next-rate) < xirrTolerance {
+			return next, nil
+		}
+		rate = next
+	}
+
+	low, high := -1+xirrTolerance, 1.0
+	lowValue, _ := npv(low)
+	highValue, _ := npv(high)
+	for lowValue*highValue > 0 && high < 1e6 {
+		high *= 2
+		highValue, _ = npv(high)
+	}
+	if lowValue*highValue > 0 {
+		return 0, fmt.Errorf("money-weighted return does not converge for the given cash flows")
+	}
+
+	for i := 0; i < xirrMaxIterations; i++ {
+		mid := (low + high) / 2
+		midValue, _ := npv(mid)
+		if midValue == 0 || (high-low)/2 < xirrTolerance {
+			return mid, nil
+		}
+		if midValue*lowValue < 0 {
+			high = mid
+		} else {
+			low, lowValue = mid, midValue
+		}
+	}
+
+	return (low + high) / 2, nil
+}
+
+// startOfDay truncates a time to the start of its UTC day
+func startOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(oneDay)
+}
@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestNewPerformanceService_Ok checks that NewPerformanceService creates a new performanceService struct
+func TestNewPerformanceService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+
+	// Act
+	service := NewPerformanceService(cfg, mocks.NewPortfolioRepository(t), mocks.NewHoldingRepository(t), mocks.NewTransactionRepository(t), mocks.NewDividendRepository(t), mocks.NewUserRepository(t), mocks.NewPriceSource(t), mocks.NewCurrencyConverter(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestGetPortfolioPerformance_Ok checks that GetPortfolioPerformance neutralizes contributions in the time-weighted return
+func TestGetPortfolioPerformance_Ok(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+	midYear := time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC)
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-a"}, nil).Once()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "user-a").Return(&entities.User{UserID: "user-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{
+		&entities.Transaction{TransactionID: "1", StockID: "stock-a", Quantity: decimal.RequireFromString("10"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("10"), "CZK"), TransactionDate: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		&entities.Transaction{TransactionID: "2", StockID: "stock-a", Quantity: decimal.RequireFromString("10"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("20"), "CZK"), TransactionDate: midYear},
+	}, nil).Once()
+
+	dividendRepositoryMock := mocks.NewDividendRepository(t)
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-a"}, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	priceSourceMock := mocks.NewPriceSource(t)
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.PriceAt), context.Background(), "stock-a", mock.Anything).Return(func(_ context.Context, _ string, date time.Time) (entities.Money, error) {
+		if date.Before(midYear) {
+			return entities.NewMoney(decimal.NewFromInt(10), "CZK"), nil
+		}
+		return entities.NewMoney(decimal.NewFromInt(20), "CZK"), nil
+	})
+
+	service := &performanceService{
+		config:                config.Config{},
+		portfolioRepository:   portfolioRepositoryMock,
+		userRepository:        userRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		dividendRepository:    dividendRepositoryMock,
+		priceSource:           priceSourceMock,
+	}
+	req := models.PerformanceReq{To: time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC)}
+
+	// Act
+	resp, err := service.GetPortfolioPerformance(context.Background(), "user-a", portfolioID, req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, portfolioID, resp.PortfolioID)
+	assert.Equal(t, time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC), resp.From)
+	assert.True(t, resp.StartValue.IsZero())
+	assert.Equal(t, "400 CZK", resp.EndValue.String())
+	assert.Equal(t, "300 CZK", resp.NetContributions.String())
+	assert.InDelta(t, 100.0, resp.TimeWeightedReturnPct, 1e-9)
+	assert.Greater(t, resp.MoneyWeightedReturnPct, 0.0)
+	assert.Less(t, resp.MoneyWeightedReturnPct, resp.TimeWeightedReturnPct)
+}
+
+// TestGetPortfolioPerformance_Dividends checks that GetPortfolioPerformance pays dividends on their pay date for the shares held before the ex-date
+func TestGetPortfolioPerformance_Dividends(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+	exDate := time.Date(2023, time.August, 11, 0, 0, 0, 0, time.UTC)
+	payDate := time.Date(2023, time.August, 17, 0, 0, 0, 0, time.UTC)
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-a"}, nil).Once()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "user-a").Return(&entities.User{UserID: "user-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{
+		&entities.Transaction{TransactionID: "1", StockID: "stock-a", Quantity: decimal.RequireFromString("10"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("10"), "USD"), TransactionDate: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		&entities.Transaction{TransactionID: "2", StockID: "stock-a", Quantity: decimal.RequireFromString("10"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("10"), "USD"), TransactionDate: exDate},
+	}, nil).Once()
+
+	dividendRepositoryMock := mocks.NewDividendRepository(t)
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-a"}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Dividend{DividendID: "dividend-a", StockID: "stock-a", DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.5"), "USD"), ExDate: exDate, PayDate: payDate},
+	}, nil).Once()
+
+	priceSourceMock := mocks.NewPriceSource(t)
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.PriceAt), context.Background(), "stock-a", mock.Anything).Return(entities.NewMoney(decimal.NewFromInt(10), "USD"), nil)
+
+	service := &performanceService{
+		config:                config.Config{},
+		portfolioRepository:   portfolioRepositoryMock,
+		userRepository:        userRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		dividendRepository:    dividendRepositoryMock,
+		priceSource:           priceSourceMock,
+	}
+	req := models.PerformanceReq{To: time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC)}
+
+	// Act
+	resp, err := service.GetPortfolioPerformance(context.Background(), "user-a", portfolioID, req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "5 USD", resp.Dividends.String())
+	assert.Equal(t, "200 USD", resp.EndValue.String())
+}
+
+// TestGetPortfolioPerformance_Deposits checks that GetPortfolioPerformance values the cash of a portfolio recording deposits and
+// neutralizes the deposits instead of the trades in the time-weighted return
+func TestGetPortfolioPerformance_Deposits(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+	start := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	midYear := time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC)
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-a"}, nil).Once()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "user-a").Return(&entities.User{UserID: "user-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{
+		&entities.Transaction{TransactionID: "1", TransactionType: entities.TransactionTypeDeposit, TransactionPrice: entities.NewMoney(decimal.RequireFromString("1000"), "CZK"), TransactionDate: start},
+		&entities.Transaction{TransactionID: "2", TransactionType: entities.TransactionTypeBuy, StockID: "stock-a", Quantity: decimal.RequireFromString("10"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("100"), "CZK"), TransactionDate: start},
+		&entities.Transaction{TransactionID: "3", TransactionType: entities.TransactionTypeDeposit, TransactionPrice: entities.NewMoney(decimal.RequireFromString("1000"), "CZK"), TransactionDate: midYear},
+	}, nil).Once()
+
+	priceSourceMock := mocks.NewPriceSource(t)
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.PriceAt), context.Background(), "stock-a", mock.Anything).Return(func(_ context.Context, _ string, date time.Time) (entities.Money, error) {
+		if date.Before(midYear) {
+			return entities.NewMoney(decimal.NewFromInt(100), "CZK"), nil
+		}
+		return entities.NewMoney(decimal.NewFromInt(120), "CZK"), nil
+	})
+
+	service := &performanceService{
+		config:                config.Config{},
+		portfolioRepository:   portfolioRepositoryMock,
+		userRepository:        userRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		priceSource:           priceSourceMock,
+	}
+	req := models.PerformanceReq{To: time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC)}
+
+	// Act
+	resp, err := service.GetPortfolioPerformance(context.Background(), "user-a", portfolioID, req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, start, resp.From)
+	assert.Equal(t, "2200 CZK", resp.EndValue.String())
+	assert.Equal(t, "2000 CZK", resp.NetContributions.String())
+	assert.InDelta(t, 20.0, resp.TimeWeightedReturnPct, 1e-9)
+	assert.Greater(t, resp.MoneyWeightedReturnPct, 0.0)
+}
+
+// TestGetPortfolioPerformance_OtherUser checks that GetPortfolioPerformance returns an unauthorized error when the portfolio belongs to
+// another user
+func TestGetPortfolioPerformance_OtherUser(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &performanceService{
+		config:                config.Config{},
+		portfolioRepository:   portfolioRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+	}
+
+	// Act
+	_, err := service.GetPortfolioPerformance(context.Background(), "user-b", portfolioID, models.PerformanceReq{})
+
+	// Assert
+	assert.Equal(t, wrappers.NewUnauthorizedErr(fmt.Errorf("portfolio %s belongs to another user", portfolioID)), err)
+}
+
+// TestGetPortfolioPerformance_InvalidRequest checks that GetPortfolioPerformance returns an error when the range is not valid
+func TestGetPortfolioPerformance_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := &performanceService{
+		config: config.Config{},
+	}
+	req := models.PerformanceReq{From: time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	expectedError := "to cannot be before from"
+
+	// Act
+	_, err := service.GetPortfolioPerformance(context.Background(), "user-a", "portfolio-id", req)
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetHoldingPerformance_NotFound checks that GetHoldingPerformance returns an error when the holding does not exist
+func TestGetHoldingPerformance_NotFound(t *testing.T) {
+	// Arrange
+	holdingID := "holding-id"
+	expectedError := fmt.Sprintf("ID %s not found", holdingID)
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.GetByID), context.Background(), holdingID).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &performanceService{
+		config:            config.Config{},
+		holdingRepository: holdingRepositoryMock,
+	}
+
+	// Act
+	_, err := service.GetHoldingPerformance(context.Background(), "user-a", holdingID, models.PerformanceReq{})
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetHoldingPerformance_OtherUser checks that GetHoldingPerformance returns an unauthorized error without reading the transactions
+// when the portfolio of the holding belongs to another user
+func TestGetHoldingPerformance_OtherUser(t *testing.T) {
+	// Arrange
+	holdingID := "holding-id"
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.GetByID), context.Background(), holdingID).Return(&entities.Holding{HoldingID: holdingID, PortfolioID: "portfolio-id"}, nil).Once()
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-id").Return(&entities.Portfolio{PortfolioID: "portfolio-id", Userid: "user-a"}, nil).Once()
+
+	service := &performanceService{
+		config:              config.Config{},
+		holdingRepository:   holdingRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+	}
+
+	// Act
+	_, err := service.GetHoldingPerformance(context.Background(), "user-b", holdingID, models.PerformanceReq{})
+
+	// Assert
+	assert.Equal(t, wrappers.NewUnauthorizedErr(fmt.Errorf("portfolio portfolio-id belongs to another user")), err)
+}
+
+// TestXIRR_Ok checks that xirr returns the annual rate of a one year investment
+func TestXIRR_Ok(t *testing.T) {
+	// Arrange
+	start := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	flows := []cashFlow{
+		{date: start, amount: -100},
+		{date: start.AddDate(0, 0, 365), amount: 110},
+	}
+
+	// Act
+	rate, err := xirr(flows)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.1, rate, 1e-6)
+}
+
+// TestXIRR_HeavyLoss checks that xirr converges when most of the investment is lost
+func TestXIRR_HeavyLoss(t *testing.T) {
+	// Arrange
+	start := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	flows := []cashFlow{
+		{date: start, amount: -1000},
+		{date: start.AddDate(0, 0, 365), amount: 50},
+	}
+
+	// Act
+	rate, err := xirr(flows)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.InDelta(t, -0.95, rate, 1e-6)
+}
+
+// TestSharesEntitled_DividendBeforeSplit checks that sharesEntitled counts the shares held on the ex-date of a dividend paid before a
+// split, and the split shares for a dividend paid after it
+func TestSharesEntitled_DividendBeforeSplit(t *testing.T) {
+	// Arrange
+	transactions := adjustTransactions([]entities.Transaction{
+		{TransactionID: "t1", StockID: "stock-a", TransactionType: entities.TransactionTypeBuy, Quantity: decimal.NewFromInt(100), TransactionPrice: entities.NewMoney(decimal.NewFromInt(50), "USD"), TransactionDate: time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)},
+	}, []entities.CorporateAction{
+		{StockID: "stock-a", ActionType: entities.CorporateActionSplit, EffectiveDate: time.Date(2024, time.June, 10, 0, 0, 0, 0, time.UTC), Numerator: decimal.NewFromInt(2), Denominator: decimal.NewFromInt(1)},
+	})
+	before := entities.Dividend{StockID: "stock-a", ExDate: time.Date(2023, time.May, 2, 0, 0, 0, 0, time.UTC)}
+	after := entities.Dividend{StockID: "stock-a", ExDate: time.Date(2024, time.September, 2, 0, 0, 0, 0, time.UTC)}
+
+	// Act
+	sharesBefore := sharesEntitled(transactions, before)
+	sharesAfter := sharesEntitled(transactions, after)
+
+	// Assert
+	assert.Equal(t, "100", sharesBefore.String())
+	assert.Equal(t, "200", sharesAfter.String())
+}
+
+// TestSharesEntitled_MergerAndSpinOff checks that sharesEntitled counts merged and spun-off shares for the dividends of their new
+// stock only from the day the action took effect
+func TestSharesEntitled_MergerAndSpinOff(t *testing.T) {
+	// Arrange
+	transactions := adjustTransactions([]entities.Transaction{
+		{TransactionID: "t1", StockID: "stock-a", TransactionType: entities.TransactionTypeBuy, Quantity: decimal.NewFromInt(30), TransactionPrice: entities.NewMoney(decimal.NewFromInt(90), "USD"), TransactionDate: time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)},
+	}, []entities.CorporateAction{
+		{StockID: "stock-a", ActionType: entities.CorporateActionSpinOff, EffectiveDate: time.Date(2023, time.April, 3, 0, 0, 0, 0, time.UTC), Numerator: decimal.NewFromInt(1), Denominator: decimal.NewFromInt(3), TargetStockID: "stock-b", CostAllocation: decimal.RequireFromString("0.2")},
+		{StockID: "stock-a", ActionType: entities.CorporateActionMerger, EffectiveDate: time.Date(2024, time.June, 10, 0, 0, 0, 0, time.UTC), Numerator: decimal.NewFromInt(1), Denominator: decimal.NewFromInt(2), TargetStockID: "stock-c"},
+	})
+
+	// Act
+	parentBeforeMerger := sharesEntitled(transactions, entities.Dividend{StockID: "stock-a", ExDate: time.Date(2023, time.May, 2, 0, 0, 0, 0, time.UTC)})
+	spunOffBefore := sharesEntitled(transactions, entities.Dividend{StockID: "stock-b", ExDate: time.Date(2023, time.January, 2, 0, 0, 0, 0, time.UTC)})
+	spunOffAfter := sharesEntitled(transactions, entities.Dividend{StockID: "stock-b", ExDate: time.Date(2023, time.May, 2, 0, 0, 0, 0, time.UTC)})
+	acquirerBefore := sharesEntitled(transactions, entities.Dividend{StockID: "stock-c", ExDate: time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC)})
+	acquirerAfter := sharesEntitled(transactions, entities.Dividend{StockID: "stock-c", ExDate: time.Date(2024, time.September, 2, 0, 0, 0, 0, time.UTC)})
+
+	// Assert
+	assert.Equal(t, "30", parentBeforeMerger.String())
+	assert.Equal(t, "0", spunOffBefore.String())
+	assert.Equal(t, "10", spunOffAfter.String())
+	assert.Equal(t, "0", acquirerBefore.String())
+	assert.Equal(t, "15", acquirerAfter.String())
+}
@@ -0,0 +1,479 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+)
+
+// ppPortfolioName is the name of the portfolios restored from a Portfolio Performance file, which has no name of its own
+const ppPortfolioName = "Portfolio Performance"
+
+// ppExport builds the Portfolio Performance file of a portfolio, with a securities account per holding and a deposit account per
+// currency
+type ppExport struct {
+	portfolio  entities.Portfolio
+	lookup     *exportLookup
+	client     entities.PPClient
+	securities map[string]int
+	firstTrade map[string]time.Time
+	accounts   map[string]int
+	holdings   map[string]int
+}
+
+// writePortfolioPerformance writes a portfolio as a Portfolio Performance XML file. Buys and sells are written as purchases and sales of
+// the securities account of their holding settled in the deposit account of their currency, so they move it as they move the cash
+// balance of the portfolio, and transfers and splits as deliveries into and out of the securities account. Dividends are written
+// as dividends into the deposit account of their currency, net of the tax recorded as withheld from them or else the tax withheld in
+// the issuer country of their stock.
+// Deposits, withdrawals, fees and interest are written as the account transactions of the deposit account of their currency. The daily closes
+// stored for the instruments are written as their quotes. Portfolios without a base currency report in the currency of their first
+// security.
+func (s *exportService) writePortfolioPerformance(ctx context.Context, portfolio entities.Portfolio, lookup *exportLookup, w io.Writer) error {
+	currency, err := baseCurrency(ctx, s.userRepository, portfolio)
+	if err != nil {
+		return err
+	}
+	e := &ppExport{
+		portfolio:  portfolio,
+		lookup:     lookup,
+		client:     entities.PPClient{BaseCurrency: currency},
+		securities: map[string]int{},
+		firstTrade: map[string]time.Time{},
+		accounts:   map[string]int{},
+		holdings:   map[string]int{},
+	}
+
+	err = s.holdingRepository.StreamByPortfolioID(ctx, portfolio.PortfolioID, func(h entities.Holding) error {
+		broker, err := lookup.broker(ctx, h.BrokerID)
+		if err != nil {
+			return err
+		}
+		name := broker.Name
+		if name == "" {
+			name = broker.Slug
+		}
+		e.holdings[h.HoldingID] = len(e.client.Portfolios)
+		e.client.Portfolios = append(e.client.Portfolios, entities.PPPortfolio{UUID: entities.NewPPUUID(h.HoldingID), Name: name})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.transactionRepository.StreamByPortfolioID(ctx, portfolio.PortfolioID, func(t entities.Transaction) error {
+		n, ok := e.holdings[t.HoldingID]
+		if !ok {
+			return fmt.Errorf("holding %s of transaction %s not found", t.HoldingID, t.TransactionID)
+		}
+		if !t.MovesShares() {
+			e.cash(t)
+			return nil
+		}
+		instrumentID, instrumentType := instrumentOf(t)
+		security, err := e.security(ctx, instrumentID, instrumentType, t.TransactionPrice.Currency)
+		if err != nil {
+			return err
+		}
+		if first, ok := e.firstTrade[instrumentID]; !ok || t.TransactionDate.Before(first) {
+			e.firstTrade[instrumentID] = t.TransactionDate
+		}
+
+		transaction := entities.PPTransaction{
+			UUID:     entities.NewPPUUID(t.TransactionID),
+			Type:     entities.PPTransactionDeliveryInbound,
+			Date:     t.TransactionDate,
+			Security: security,
+			Shares:   t.Quantity.Abs(),
+			Amount:   t.TransactionPrice.Mul(t.Quantity.Abs()),
+		}
+		if t.Quantity.IsNegative() {
+			transaction.Type = entities.PPTransactionDeliveryOutbound
+		}
+		if kind, ok := ppTradeTypes[t.Type()]; ok {
+			transaction.Type = kind
+			settlement := transaction
+			settlement.UUID, settlement.Shares, settlement.CrossEntry = entities.NewPPUUID(t.TransactionID, t.TransactionPrice.Currency), decimal.Zero, transaction.UUID
+			transaction.CrossEntry = settlement.UUID
+			a := e.account(t.TransactionPrice.Currency)
+			e.client.Accounts[a].Transactions = append(e.client.Accounts[a].Transactions, settlement)
+		}
+		e.client.Portfolios[n].Transactions = append(e.client.Portfolios[n].Transactions, transaction)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	residence := strings.ToUpper(strings.TrimSpace(portfolio.TaxCountryID))
+	err = s.dividendRepository.StreamByPortfolioID(ctx, portfolio.PortfolioID, func(d entities.PortfolioDividend) error {
+		if !d.Shares.IsPositive() {
+			return nil
+		}
+		security, err := e.security(ctx, d.StockID, entities.EntityNameStock, d.DividendPerShare.Currency)
+		if err != nil {
+			return err
+		}
+		stock, err := lookup.stock(ctx, d.StockID)
+		if err != nil {
+			return err
+		}
+
+		withheldRate, _ := withholdingRates(s.config.Withholding, strings.ToUpper(stock.Country), residence)
+		gross := d.DividendPerShare.Mul(d.Shares)
+		withholding := gross.Mul(withheldRate)
+		if !d.Withholding.IsZero() {
+			withholding = d.Withholding
+		}
+		n := e.account(gross.Currency)
+		e.client.Accounts[n].Transactions = append(e.client.Accounts[n].Transactions, entities.PPTransaction{
+			UUID:     entities.NewPPUUID(portfolio.PortfolioID, d.DividendID),
+			Type:     entities.PPTransactionDividends,
+			Date:     d.PaidOn(),
+			ExDate:   d.ExDate,
+			Security: security,
+			Shares:   d.Shares,
+			Amount:   entities.NewMoney(gross.Amount.Sub(withholding.Amount), gross.Currency),
+			Taxes:    withholding,
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if e.client.BaseCurrency == "" && len(e.client.Securities) > 0 {
+		e.client.BaseCurrency = e.client.Securities[0].CurrencyCode
+	}
+	if e.client.BaseCurrency != "" {
+		reference := e.client.Accounts[e.account(e.client.BaseCurrency)].UUID
+		for i := range e.client.Portfolios {
+			e.client.Portfolios[i].ReferenceAccount = reference
+		}
+	}
+
+	if err = s.portfolioPerformancePrices(ctx, e); err != nil {
+		return err
+	}
+	return s.ppCodec.Encode(w, e.client)
+}
+
+// portfolioPerformancePrices adds the daily closes stored for the instruments of the portfolio from their first trade on as the quotes
+// of their securities
+func (s *exportService) portfolioPerformancePrices(ctx context.Context, e *ppExport) error {
+	today := startOfDay(time.Now())
+	for instrumentID, n := range e.securities {
+		first, ok := e.firstTrade[instrumentID]
+		if !ok {
+			continue
+		}
+		result, err := s.priceRepository.GetRange(ctx, instrumentID, startOfDay(first), today)
+		if err != nil {
+			if errors.Is(err, wrappers.NonExistentErr) {
+				continue
+			}
+			return err
+		}
+		for _, v := range result {
+			p := v.(*entities.Price)
+			e.client.Securities[n].Prices = append(e.client.Securities[n].Prices, entities.PPPrice{Date: startOfDay(p.PriceDate), Value: p.Close})
+		}
+	}
+	return nil
+}
+
+// account returns the position of the deposit account of a currency, adding it on its first use
+func (e *ppExport) account(currency string) int {
+	if n, ok := e.accounts[currency]; ok {
+		return n
+	}
+	e.accounts[currency] = len(e.client.Accounts)
+	e.client.Accounts = append(e.client.Accounts, entities.PPAccount{
+		UUID:         entities.NewPPUUID(e.portfolio.PortfolioID, currency),
+		Name:         strings.TrimSpace(e.portfolio.Name + " " + currency),
+		CurrencyCode: currency,
+	})
+	return e.accounts[currency]
+}
+
+// ppTradeTypes are the types of the transactions written for the buys and sells, on both the securities and the deposit account
+var ppTradeTypes = map[string]string{
+	entities.TransactionTypeBuy:  entities.PPTransactionBuy,
+	entities.TransactionTypeSell: entities.PPTransactionSell,
+}
+
+// ppCashTypes are the types of the deposit account transactions written for the transactions that move cash only
+var ppCashTypes = map[string]string{
+	entities.TransactionTypeDeposit:    entities.PPTransactionDeposit,
+	entities.TransactionTypeWithdrawal: entities.PPTransactionRemoval,
+	entities.TransactionTypeFee:        entities.PPTransactionFees,
+	entities.TransactionTypeInterest:   entities.PPTransactionInterest,
+}
+
+// cash adds a deposit, withdrawal, fee or interest to the deposit account of its currency. Dividend transactions are left out, as the
+// dividends are written from the dividends paid to the portfolio.
+func (e *ppExport) cash(t entities.Transaction) {
+	kind, ok := ppCashTypes[t.Type()]
+	if !ok {
+		return
+	}
+	n := e.account(t.TransactionPrice.Currency)
+	e.client.Accounts[n].Transactions = append(e.client.Accounts[n].Transactions, entities.PPTransaction{
+		UUID:   entities.NewPPUUID(t.TransactionID),
+		Type:   kind,
+		Date:   t.TransactionDate,
+		Amount: t.TransactionPrice,
+	})
+}
+
+// security returns the UUID of the security of an instrument, adding it on its first use in the currency it is first traded in
+func (e *ppExport) security(ctx context.Context, instrumentID, instrumentType, currency string) (string, error) {
+	if n, ok := e.securities[instrumentID]; ok {
+		return e.client.Securities[n].UUID, nil
+	}
+
+	security := entities.PPSecurity{UUID: entities.NewPPUUID(instrumentID), CurrencyCode: currency}
+	if instrumentType == entities.EntityNameStock {
+		stock, err := e.lookup.stock(ctx, instrumentID)
+		if err != nil {
+			return "", err
+		}
+		security.Name, security.ISIN, security.TickerSymbol, security.Feed = stock.CompanyName, stock.Extid, stock.TickerSymbol, entities.PPFeedManual
+	} else {
+		cryptoCurrency, err := e.lookup.cryptoCurrency(ctx, instrumentID)
+		if err != nil {
+			return "", err
+		}
+		security.Name, security.TickerSymbol, security.Feed = cryptoCurrency.Name, cryptoCurrency.Symbol, entities.PPFeedCoinGecko
+	}
+	if security.Name == "" {
+		security.Name = security.TickerSymbol
+	}
+
+	e.securities[instrumentID] = len(e.client.Securities)
+	e.client.Securities = append(e.client.Securities, security)
+	return security.UUID, nil
+}
+
+// portfolioPerformanceArchive converts a Portfolio Performance XML file into a JSON archive, so it is validated and restored as one.
+// Each securities account becomes a holding at the broker it is named after, or else at the broker of the request. Buys and inbound
+// deliveries become purchases and sells and outbound deliveries sales, priced at their settled amount per share in the currency of
+// their security. Transfers between securities accounts are skipped and counted, and dividends are restored gross of their taxes and
+// fees. Deposits, removals, fees and interest of a deposit account become the cash transactions of the first holding whose securities
+// account refers to it. Securities are stocks unless their quote feed is one of the cryptocurrency exchanges.
+func (s *exportService) portfolioPerformanceArchive(ctx context.Context, req models.RestoreReq, file io.Reader) (archive *bytes.Reader, skippedTransfers int, err error) {
+	client, err := s.ppCodec.Decode(file)
+	if err != nil {
+		return
+	}
+
+	brokers, err := s.brokerRepository.Get(ctx, map[string]interface{}{}, nil, nil)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return
+	}
+	accounts := map[string]string{}
+	for _, a := range client.Accounts {
+		accounts[a.UUID] = a.Name
+	}
+	securities := map[string]entities.PPSecurity{}
+	for _, security := range client.Securities {
+		securities[security.UUID] = security
+	}
+
+	content := struct {
+		Version      int                         `json:"version"`
+		Portfolio    models.ArchivePortfolio     `json:"portfolio"`
+		Holdings     []models.ArchiveHolding     `json:"holdings"`
+		Transactions []models.ArchiveTransaction `json:"transactions"`
+		Dividends    []models.ArchiveDividend    `json:"dividends"`
+	}{
+		Version:      models.ArchiveVersion,
+		Portfolio:    models.ArchivePortfolio{Name: ppPortfolioName, BaseCurrency: client.BaseCurrency},
+		Holdings:     []models.ArchiveHolding{},
+		Transactions: []models.ArchiveTransaction{},
+		Dividends:    []models.ArchiveDividend{},
+	}
+
+	traded := map[string]bool{}
+	for _, p := range client.Portfolios {
+		broker := ppBroker(brokers, p.Name, accounts[p.ReferenceAccount])
+		if broker == "" {
+			broker = strings.TrimSpace(req.Broker)
+		}
+		if broker == "" {
+			err = wrappers.NewValidationErr(fmt.Errorf("securities account %s is not named after a broker, the broker of the restore must be provided", p.Name))
+			return
+		}
+		content.Holdings = append(content.Holdings, models.ArchiveHolding{HoldingID: p.UUID, Broker: broker, Name: p.Name})
+
+		for _, t := range p.Transactions {
+			quantity := t.Shares.Abs()
+			switch t.Type {
+			case entities.PPTransactionBuy, entities.PPTransactionDeliveryInbound:
+			case entities.PPTransactionSell, entities.PPTransactionDeliveryOutbound:
+				quantity = quantity.Neg()
+			case entities.PPTransactionTransferIn, entities.PPTransactionTransferOut:
+				skippedTransfers++
+				continue
+			default:
+				continue
+			}
+			if quantity.IsZero() {
+				continue
+			}
+			security, ok := securities[t.Security]
+			if !ok {
+				err = wrappers.NewValidationErr(fmt.Errorf("transaction %s: security %s not found", t.UUID, t.Security))
+				return
+			}
+
+			item := models.ArchiveTransaction{
+				TransactionID:    t.UUID,
+				HoldingID:        p.UUID,
+				Symbol:           ppSymbol(security),
+				ISIN:             security.ISIN,
+				Name:             security.Name,
+				Quantity:         quantity,
+				TransactionPrice: ppPerShare(ppSecurityAmount(t, security), t.Shares.Abs()),
+				TransactionDate:  t.Date,
+				Extid:            t.UUID,
+			}
+			if security.IsCrypto() {
+				item.CryptocurrencyID = security.UUID
+			} else {
+				item.StockID = security.UUID
+				traded[security.UUID] = true
+			}
+			content.Transactions = append(content.Transactions, item)
+		}
+	}
+
+	for _, a := range client.Accounts {
+		for _, t := range a.Transactions {
+			if t.Type != entities.PPTransactionDividends || !t.Shares.IsPositive() || !traded[t.Security] {
+				continue
+			}
+			security := securities[t.Security]
+			gross := entities.NewMoney(t.Amount.Amount.Add(ppCharges(t)), t.Amount.Currency)
+			exDate := t.ExDate
+			if exDate.IsZero() {
+				exDate = t.Date
+			}
+			content.Dividends = append(content.Dividends, models.ArchiveDividend{
+				DividendID:       t.UUID,
+				StockID:          security.UUID,
+				Symbol:           ppSymbol(security),
+				ISIN:             security.ISIN,
+				DividendPerShare: ppPerShare(gross, t.Shares),
+				ExDate:           exDate,
+				PayDate:          t.Date,
+				Shares:           t.Shares,
+				Amount:           gross,
+			})
+		}
+	}
+
+	for _, a := range client.Accounts {
+		holding := ""
+		for _, p := range client.Portfolios {
+			if p.ReferenceAccount == a.UUID {
+				holding = p.UUID
+				break
+			}
+		}
+		if holding == "" {
+			continue
+		}
+		for _, t := range a.Transactions {
+			kind := ""
+			for transactionType, ppType := range ppCashTypes {
+				if ppType == t.Type {
+					kind = transactionType
+				}
+			}
+			if kind == "" || !t.Amount.Amount.IsPositive() {
+				continue
+			}
+			content.Transactions = append(content.Transactions, models.ArchiveTransaction{
+				TransactionID:    t.UUID,
+				HoldingID:        holding,
+				TransactionType:  kind,
+				TransactionPrice: t.Amount,
+				TransactionDate:  t.Date,
+				Extid:            t.UUID,
+			})
+		}
+	}
+
+	b, err := json.Marshal(content)
+	if err != nil {
+		return
+	}
+	archive = bytes.NewReader(b)
+	return
+}
+
+// ppBroker returns the slug of the broker a securities account or its deposit account is named after, preferring the longest name
+// so "Interactive Brokers" is not taken for a broker named "Interactive"
+func ppBroker(brokers []interface{}, names ...string) string {
+	slug, matched := "", 0
+	for _, name := range names {
+		key := brokerKey(name)
+		for _, v := range brokers {
+			broker := v.(*entities.Broker)
+			for _, brokerName := range []string{broker.Slug, broker.Name} {
+				if k := brokerKey(brokerName); k != "" && len(k) > matched && strings.Contains(key, k) {
+					slug, matched = broker.Slug, len(k)
+				}
+			}
+		}
+	}
+	return slug
+}
+
+// ppSymbol returns the symbol a security is found or created by, its ticker or else its ISIN, WKN or name
+func ppSymbol(security entities.PPSecurity) string {
+	for _, symbol := range []string{security.TickerSymbol, security.ISIN, security.WKN, security.Name} {
+		if symbol != "" {
+			return symbol
+		}
+	}
+	return ""
+}
+
+// ppSecurityAmount returns the settled amount of a transaction in the currency of its security, converted at the exchange rate of its
+// gross value when it was settled in another currency
+func ppSecurityAmount(t entities.PPTransaction, security entities.PPSecurity) entities.Money {
+	if t.GrossValue.Currency == "" || t.GrossValue.Currency == t.Amount.Currency || t.GrossValue.Currency != security.CurrencyCode {
+		return t.Amount
+	}
+
+	gross := t.Amount.Amount.Sub(ppCharges(t))
+	if t.Type == entities.PPTransactionSell || t.Type == entities.PPTransactionDeliveryOutbound {
+		gross = t.Amount.Amount.Add(ppCharges(t))
+	}
+	if gross.IsZero() {
+		return t.Amount
+	}
+	return entities.NewMoney(t.Amount.Amount.Mul(t.GrossValue.Amount).Div(gross), t.GrossValue.Currency)
+}
+
+// ppCharges returns the fees and taxes of a transaction, which are in the currency of the transaction
+func ppCharges(t entities.PPTransaction) decimal.Decimal {
+	return t.Fees.Amount.Add(t.Taxes.Amount)
+}
+
+// ppPerShare returns an amount divided by a number of shares
+func ppPerShare(amount entities.Money, shares decimal.Decimal) entities.Money {
+	return entities.NewMoney(amount.Amount.Div(shares), amount.Currency)
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// position is the open holding of one instrument folded from a portfolio's transactions
+type position struct {
+	instrumentID   string
+	instrumentType string
+	currency       string
+	quantity       decimal.Decimal
+	costBasis      decimal.Decimal
+	lastTradePrice decimal.Decimal
+	lastTradeDate  time.Time
+}
+
+// averageCost returns the cost of a single unit of the position
+func (p position) averageCost() decimal.Decimal {
+	if p.quantity.IsZero() {
+		return decimal.Zero
+	}
+	return p.costBasis.Div(p.quantity)
+}
+
+// ledger is the result of matching a portfolio's sells against its buy lots
+type ledger struct {
+	positions []position
+	lots      []entities.Lot
+	gains     []entities.RealizedGain
+	// transfers are the lots consumed by transfers out, whose proceeds are the value transferred and whose gain is not realized
+	transfers []entities.RealizedGain
+}
+
+// realizedGain returns the sum of the gains realized by all the sales of the ledger, or an error when they were realized in different
+// currencies
+func (l ledger) realizedGain() (total entities.Money, err error) {
+	for _, g := range l.gains {
+		if total, err = total.Add(g.Gain); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// instrumentLots keeps the lots of one instrument while the transactions are folded
+type instrumentLots struct {
+	position position
+	lots     []*entities.Lot
+	// poolCost is the remaining cost of all the lots, used by the average cost method
+	poolCost decimal.Decimal
+}
+
+// loadPortfolioTransactions returns a portfolio together with all of its transactions
+func loadPortfolioTransactions(ctx context.Context, portfolioRepo ports.PortfolioRepository, transactionRepo ports.TransactionRepository, portfolioID string) (entities.Portfolio, []entities.Transaction, error) {
+	portfolio, err := portfolioRepo.GetByID(ctx, portfolioID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", portfolioID))
+		}
+		return entities.Portfolio{}, nil, err
+	}
+
+	result, err := transactionRepo.GetByPortfolioID(ctx, portfolioID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return *(portfolio.(*entities.Portfolio)), nil, err
+	}
+
+	transactions := make([]entities.Transaction, len(result))
+	for i, v := range result {
+		transactions[i] = *(v.(*entities.Transaction))
+	}
+
+	return *(portfolio.(*entities.Portfolio)), transactions, nil
+}
+
+// userPortfolio returns a portfolio of a user, or an unauthorized error when it belongs to another user
+func userPortfolio(ctx context.Context, portfolioRepo ports.PortfolioRepository, userID, portfolioID string) (entities.Portfolio, error) {
+	result, err := portfolioRepo.GetByID(ctx, portfolioID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", portfolioID))
+		}
+		return entities.Portfolio{}, err
+	}
+
+	portfolio := *(result.(*entities.Portfolio))
+	if err = checkOwner(portfolio, userID); err != nil {
+		return entities.Portfolio{}, err
+	}
+	return portfolio, nil
+}
+
+// checkOwner returns an unauthorized error when a portfolio belongs to another user
+func checkOwner(portfolio entities.Portfolio, userID string) error {
+	if portfolio.Userid != userID {
+		return wrappers.NewUnauthorizedErr(fmt.Errorf("portfolio %s belongs to another user", portfolio.PortfolioID))
+	}
+	return nil
+}
+
+// instrumentOf returns the ID and type of the instrument a transaction refers to
+func instrumentOf(t entities.Transaction) (string, string) {
+	if t.StockID != "" {
+		return t.StockID, entities.EntityNameStock
+	}
+	return t.CryptocurrencyID, entities.EntityNameCryptoCurrency
+}
+
+// validCostBasisMethod reports whether method is one of the supported cost basis methods
+func validCostBasisMethod(method int) bool {
+	return method == entities.CostBasisFIFO || method == entities.CostBasisLIFO || method == entities.CostBasisAverage
+}
+
+// buildLedger folds transactions in chronological order into buy lots, matches every sell against them
+// with the given cost basis method and returns the resulting lots, realized gains and open positions.
+// A positive quantity is a buy and a negative quantity is a sell, and all the transactions of an instrument must share a currency. Transactions on the same date keep their order,
+// so the result is deterministic for a given set of transactions and method.
+// Transfers in open lots at their price like buys and transfers out consume lots like sells without realizing a gain. Split
+// transactions are applied to the earlier lots as adjustTransactions does, and the transactions that move cash only are left out.
+func buildLedger(transactions []entities.Transaction, method int) (ledger, error) {
+	if !validCostBasisMethod(method) {
+		return ledger{}, fmt.Errorf("cost basis method %d not supported", method)
+	}
+
+	var sorted []entities.Transaction
+	for _, t := range adjustTransactions(transactions, nil) {
+		if t.MovesShares() {
+			sorted = append(sorted, t)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].TransactionDate.Before(sorted[j].TransactionDate)
+	})
+
+	var result ledger
+	instruments := map[string]*instrumentLots{}
+	for _, t := range sorted {
+		quantity := t.Quantity
+		price := t.TransactionPrice.Amount
+
+		instrumentID, instrumentType := instrumentOf(t)
+		in, ok := instruments[instrumentID]
+		if !ok {
+			in = &instrumentLots{position: position{instrumentID: instrumentID, instrumentType: instrumentType, currency: t.TransactionPrice.Currency}}
+			instruments[instrumentID] = in
+		}
+		if t.TransactionPrice.Currency != in.position.currency {
+			return ledger{}, fmt.Errorf("transaction %s is priced in %q but %s is traded in %q", t.TransactionID, t.TransactionPrice.Currency, instrumentID, in.position.currency)
+		}
+
+		if !quantity.IsNegative() {
+			in.lots = append(in.lots, &entities.Lot{
+				TransactionID:     t.TransactionID,
+				InstrumentID:      instrumentID,
+				InstrumentType:    instrumentType,
+				AcquiredAt:        t.TransactionDate,
+				Quantity:          quantity,
+				RemainingQuantity: quantity,
+				CostPerUnit:       t.TransactionPrice,
+			})
+			in.poolCost = in.poolCost.Add(quantity.Mul(price))
+			in.position.quantity = in.position.quantity.Add(quantity)
+		} else {
+			gains, err := in.sell(t, quantity.Neg(), price, method)
+			if err != nil {
+				return ledger{}, err
+			}
+			if t.Type() == entities.TransactionTypeTransferOut {
+				result.transfers = append(result.transfers, gains...)
+			} else {
+				result.gains = append(result.gains, gains...)
+			}
+		}
+
+		in.position.lastTradePrice = price
+		in.position.lastTradeDate = t.TransactionDate
+	}
+
+	ids := make([]string, 0, len(instruments))
+	for id := range instruments {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		in := instruments[id]
+		for _, l := range in.lots {
+			result.lots = append(result.lots, *l)
+		}
+		if in.position.quantity.IsPositive() {
+			in.position.costBasis = in.costBasis(method)
+			result.positions = append(result.positions, in.position)
+		}
+	}
+
+	return result, nil
+}
+
+// sell consumes quantity units from the open lots following the cost basis method and returns one realized gain per consumed lot
+func (in *instrumentLots) sell(t entities.Transaction, quantity, price decimal.Decimal, method int) ([]entities.RealizedGain, error) {
+	if quantity.GreaterThan(in.position.quantity) {
+		return nil, fmt.Errorf("transaction %s sells %s units of %s but only %s are held", t.TransactionID, quantity, in.position.instrumentID, in.position.quantity)
+	}
+
+	averageCost := in.poolCost.Div(in.position.quantity)
+
+	order := make([]*entities.Lot, len(in.lots))
+	copy(order, in.lots)
+	if method == entities.CostBasisLIFO {
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+
+	var gains []entities.RealizedGain
+	remaining := quantity
+	for _, l := range order {
+		if !remaining.IsPositive() {
+			break
+		}
+		if !l.RemainingQuantity.IsPositive() {
+			continue
+		}
+
+		consumed := decimal.Min(l.RemainingQuantity, remaining)
+		l.RemainingQuantity = l.RemainingQuantity.Sub(consumed)
+		remaining = remaining.Sub(consumed)
+
+		costPerUnit := l.CostPerUnit.Amount
+		if method == entities.CostBasisAverage {
+			costPerUnit = averageCost
+		}
+
+		gain := entities.RealizedGain{
+			BuyTransactionID:  l.TransactionID,
+			SellTransactionID: t.TransactionID,
+			InstrumentID:      l.InstrumentID,
+			InstrumentType:    l.InstrumentType,
+			Method:            method,
+			Quantity:          consumed,
+			AcquiredAt:        l.AcquiredAt,
+			SoldAt:            t.TransactionDate,
+			CostBasis:         entities.NewMoney(consumed.Mul(costPerUnit), in.position.currency),
+			Proceeds:          entities.NewMoney(consumed.Mul(price), in.position.currency),
+		}
+		gain.Gain = entities.NewMoney(gain.Proceeds.Amount.Sub(gain.CostBasis.Amount), in.position.currency)
+		gains = append(gains, gain)
+	}
+
+	in.position.quantity = in.position.quantity.Sub(quantity)
+	in.poolCost = in.poolCost.Sub(quantity.Mul(averageCost))
+	if in.position.quantity.IsZero() {
+		in.poolCost = decimal.Zero
+	}
+
+	return gains, nil
+}
+
+// costBasis returns the remaining cost of the open lots following the cost basis method
+func (in *instrumentLots) costBasis(method int) decimal.Decimal {
+	if method == entities.CostBasisAverage {
+		return in.poolCost
+	}
+
+	cost := decimal.Zero
+	for _, l := range in.lots {
+		cost = cost.Add(l.RemainingQuantity.Mul(l.CostPerUnit.Amount))
+	}
+	return cost
+}
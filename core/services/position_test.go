@@ -0,0 +1,214 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// ledgerTransactions returns two buys and a partial sell of one stock plus a buy of one cryptocurrency, unordered
+func ledgerTransactions() []entities.Transaction {
+	return []entities.Transaction{
+		{TransactionID: "3", StockID: "stock-a", Quantity: decimal.RequireFromString("-15"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("30"), "CZK"), TransactionDate: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "1", StockID: "stock-a", Quantity: decimal.RequireFromString("10"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("10"), "CZK"), TransactionDate: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "2", StockID: "stock-a", Quantity: decimal.RequireFromString("10"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("20"), "CZK"), TransactionDate: time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "4", CryptocurrencyID: "crypto-b", Quantity: decimal.RequireFromString("0.5"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("20000"), "CZK"), TransactionDate: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}
+}
+
+// TestBuildLedger_FIFO checks that buildLedger sells the oldest lots first
+func TestBuildLedger_FIFO(t *testing.T) {
+	// Arrange
+	transactions := ledgerTransactions()
+
+	// Act
+	result, err := buildLedger(transactions, entities.CostBasisFIFO)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, result.positions, 2)
+	assert.Equal(t, "crypto-b", result.positions[0].instrumentID)
+	assert.Equal(t, entities.EntityNameCryptoCurrency, result.positions[0].instrumentType)
+	assert.Equal(t, "CZK", result.positions[0].currency)
+	assert.Equal(t, "0.5", result.positions[0].quantity.String())
+	assert.Equal(t, "10000", result.positions[0].costBasis.String())
+	assert.Equal(t, "20000", result.positions[0].lastTradePrice.String())
+	assert.Equal(t, "stock-a", result.positions[1].instrumentID)
+	assert.Equal(t, entities.EntityNameStock, result.positions[1].instrumentType)
+	assert.Equal(t, "5", result.positions[1].quantity.String())
+	assert.Equal(t, "100", result.positions[1].costBasis.String())
+	assert.Equal(t, "30", result.positions[1].lastTradePrice.String())
+	assert.Equal(t, time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC), result.positions[1].lastTradeDate)
+	assert.Len(t, result.lots, 3)
+	assert.Equal(t, "0", result.lots[1].RemainingQuantity.String())
+	assert.Equal(t, "5", result.lots[2].RemainingQuantity.String())
+	assert.Len(t, result.gains, 2)
+	assert.Equal(t, "1", result.gains[0].BuyTransactionID)
+	assert.Equal(t, "10", result.gains[0].Quantity.String())
+	assert.Equal(t, "200 CZK", result.gains[0].Gain.String())
+	assert.Equal(t, "2", result.gains[1].BuyTransactionID)
+	assert.Equal(t, "5", result.gains[1].Quantity.String())
+	assert.Equal(t, "50 CZK", result.gains[1].Gain.String())
+	realizedGain, err := result.realizedGain()
+	assert.Nil(t, err)
+	assert.Equal(t, "250 CZK", realizedGain.String())
+}
+
+// TestBuildLedger_LIFO checks that buildLedger sells the newest lots first
+func TestBuildLedger_LIFO(t *testing.T) {
+	// Arrange
+	transactions := ledgerTransactions()
+
+	// Act
+	result, err := buildLedger(transactions, entities.CostBasisLIFO)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "50", result.positions[1].costBasis.String())
+	assert.Equal(t, "5", result.lots[1].RemainingQuantity.String())
+	assert.Equal(t, "0", result.lots[2].RemainingQuantity.String())
+	assert.Len(t, result.gains, 2)
+	assert.Equal(t, "2", result.gains[0].BuyTransactionID)
+	assert.Equal(t, "100 CZK", result.gains[0].Gain.String())
+	assert.Equal(t, "1", result.gains[1].BuyTransactionID)
+	assert.Equal(t, "100 CZK", result.gains[1].Gain.String())
+	realizedGain, err := result.realizedGain()
+	assert.Nil(t, err)
+	assert.Equal(t, "200 CZK", realizedGain.String())
+}
+
+// TestBuildLedger_Average checks that buildLedger sells at the average cost of all the open lots
+func TestBuildLedger_Average(t *testing.T) {
+	// Arrange
+	transactions := ledgerTransactions()
+
+	// Act
+	result, err := buildLedger(transactions, entities.CostBasisAverage)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "75", result.positions[1].costBasis.String())
+	assert.Equal(t, "15", result.positions[1].averageCost().String())
+	assert.Len(t, result.gains, 2)
+	assert.Equal(t, "150 CZK", result.gains[0].CostBasis.String())
+	assert.Equal(t, "75 CZK", result.gains[1].CostBasis.String())
+	assert.Equal(t, entities.CostBasisAverage, result.gains[1].Method)
+	realizedGain, err := result.realizedGain()
+	assert.Nil(t, err)
+	assert.Equal(t, "225 CZK", realizedGain.String())
+}
+
+// TestBuildLedger_ClosedPosition checks that buildLedger leaves out fully sold positions
+func TestBuildLedger_ClosedPosition(t *testing.T) {
+	// Arrange
+	transactions := []entities.Transaction{
+		{TransactionID: "1", StockID: "stock-a", Quantity: decimal.RequireFromString("10"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("10"), "CZK"), TransactionDate: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "2", StockID: "stock-a", Quantity: decimal.RequireFromString("-10"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("12"), "CZK"), TransactionDate: time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	// Act
+	result, err := buildLedger(transactions, entities.CostBasisFIFO)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Empty(t, result.positions)
+	realizedGain, err := result.realizedGain()
+	assert.Nil(t, err)
+	assert.Equal(t, "20 CZK", realizedGain.String())
+}
+
+// TestBuildLedger_Types checks that buildLedger opens lots for transfers in, consumes them for transfers out without realizing a gain,
+// applies split transactions and leaves out the transactions that move cash only
+func TestBuildLedger_Types(t *testing.T) {
+	// Arrange
+	transactions := []entities.Transaction{
+		{TransactionID: "1", StockID: "stock-a", TransactionType: entities.TransactionTypeTransferIn, Quantity: decimal.RequireFromString("10"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("10"), "CZK"), TransactionDate: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "2", StockID: "stock-a", TransactionType: entities.TransactionTypeSplit, Quantity: decimal.RequireFromString("10"), TransactionDate: time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "3", StockID: "stock-a", TransactionType: entities.TransactionTypeTransferOut, Quantity: decimal.RequireFromString("-4"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("8"), "CZK"), TransactionDate: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "4", StockID: "stock-a", TransactionType: entities.TransactionTypeSell, Quantity: decimal.RequireFromString("-6"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("7"), "CZK"), TransactionDate: time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "5", StockID: "stock-a", TransactionType: entities.TransactionTypeDividend, TransactionPrice: entities.NewMoney(decimal.RequireFromString("3"), "EUR"), TransactionDate: time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "6", TransactionType: entities.TransactionTypeDeposit, TransactionPrice: entities.NewMoney(decimal.RequireFromString("100"), "CZK"), TransactionDate: time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	// Act
+	result, err := buildLedger(transactions, entities.CostBasisFIFO)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, result.positions, 1)
+	assert.Equal(t, "10", result.positions[0].quantity.String())
+	assert.Equal(t, "50", result.positions[0].costBasis.String())
+	assert.Len(t, result.transfers, 1)
+	assert.Equal(t, "3", result.transfers[0].SellTransactionID)
+	assert.Equal(t, "4", result.transfers[0].Quantity.String())
+	assert.Len(t, result.gains, 1)
+	realizedGain, err := result.realizedGain()
+	assert.Nil(t, err)
+	assert.Equal(t, "12 CZK", realizedGain.String())
+}
+
+// TestBuildLedger_Oversold checks that buildLedger returns an error when a sell exceeds the held quantity
+func TestBuildLedger_Oversold(t *testing.T) {
+	// Arrange
+	transactions := []entities.Transaction{
+		{TransactionID: "1", StockID: "stock-a", Quantity: decimal.RequireFromString("-1"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("10"), "CZK"), TransactionDate: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	expectedError := "transaction 1 sells 1 units of stock-a but only 0 are held"
+
+	// Act
+	_, err := buildLedger(transactions, entities.CostBasisFIFO)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestBuildLedger_CurrencyMismatch checks that buildLedger returns an error when the transactions of an instrument are priced in different currencies
+func TestBuildLedger_CurrencyMismatch(t *testing.T) {
+	// Arrange
+	transactions := []entities.Transaction{
+		{TransactionID: "1", StockID: "stock-a", Quantity: decimal.RequireFromString("10"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("10"), "CZK"), TransactionDate: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "2", StockID: "stock-a", Quantity: decimal.RequireFromString("5"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("1"), "EUR"), TransactionDate: time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	expectedError := `transaction 2 is priced in "EUR" but stock-a is traded in "CZK"`
+
+	// Act
+	_, err := buildLedger(transactions, entities.CostBasisFIFO)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestBuildLedger_ExactDecimals checks that buildLedger keeps fractional quantities and prices exact
+func TestBuildLedger_ExactDecimals(t *testing.T) {
+	// Arrange
+	transactions := []entities.Transaction{
+		{TransactionID: "1", CryptocurrencyID: "crypto-b", Quantity: decimal.RequireFromString("0.1"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("0.1"), "CZK"), TransactionDate: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "2", CryptocurrencyID: "crypto-b", Quantity: decimal.RequireFromString("0.2"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("0.2"), "CZK"), TransactionDate: time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{TransactionID: "3", CryptocurrencyID: "crypto-b", Quantity: decimal.RequireFromString("-0.3"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("0.3"), "CZK"), TransactionDate: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	// Act
+	result, err := buildLedger(transactions, entities.CostBasisFIFO)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Empty(t, result.positions)
+	realizedGain, err := result.realizedGain()
+	assert.Nil(t, err)
+	assert.Equal(t, "0.04 CZK", realizedGain.String())
+}
+
+// TestBuildLedger_UnsupportedMethod checks that buildLedger returns an error when the cost basis method is unknown
+func TestBuildLedger_UnsupportedMethod(t *testing.T) {
+	// Arrange
+	expectedError := "cost basis method 9 not supported"
+
+	// Act
+	_, err := buildLedger(nil, 9)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// lastTradePriceSource adapter of a price source that quotes instruments at their most recent transaction price
+type lastTradePriceSource struct {
+	config     config.Config
+	repository ports.TransactionRepository
+}
+
+// NewLastTradePriceSource creates a new price source backed by the transactions repository
+func NewLastTradePriceSource(cfg config.Config, repo ports.TransactionRepository) ports.PriceSource {
+	return &lastTradePriceSource{
+		config:     cfg,
+		repository: repo,
+	}
+}
+
+// LatestPrice returns the price of the most recent transaction of the given stock or cryptocurrency
+func (s *lastTradePriceSource) LatestPrice(ctx context.Context, instrumentID string) (entities.Money, error) {
+	return s.PriceAt(ctx, instrumentID, time.Time{})
+}
+
+// PriceAt returns the price of the most recent trade or transfer of the given stock or cryptocurrency made on or before date.
+// A zero date is not bounded.
+func (s *lastTradePriceSource) PriceAt(ctx context.Context, instrumentID string, date time.Time) (price entities.Money, err error) {
+	var latest *entities.Transaction
+	for _, column := range []string{"stockid", "cryptocurrencyid"} {
+		result, getErr := s.repository.Get(ctx, map[string]interface{}{column: instrumentID}, nil, nil)
+		if getErr != nil {
+			if errors.Is(getErr, wrappers.NonExistentErr) {
+				continue
+			}
+			err = getErr
+			return
+		}
+
+		for _, v := range result {
+			t := v.(*entities.Transaction)
+			if !t.MovesShares() || t.Type() == entities.TransactionTypeSplit {
+				continue
+			}
+			if !date.IsZero() && t.TransactionDate.After(date) {
+				continue
+			}
+			if latest == nil || t.TransactionDate.After(latest.TransactionDate) {
+				latest = t
+			}
+		}
+	}
+
+	if latest == nil {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("no price found for instrument %s", instrumentID))
+		return
+	}
+
+	price = latest.TransactionPrice
+
+	return
+}
+
+// storedPriceSource adapter of a price source that quotes instruments from the stored price history,
+// falling back to another source for the instruments without stored prices
+type storedPriceSource struct {
+	config     config.Config
+	repository ports.PriceRepository
+	fallback   ports.PriceSource
+}
+
+// NewStoredPriceSource creates a new price source backed by the prices repository
+func NewStoredPriceSource(cfg config.Config, repo ports.PriceRepository, fallback ports.PriceSource) ports.PriceSource {
+	return &storedPriceSource{
+		config:     cfg,
+		repository: repo,
+		fallback:   fallback,
+	}
+}
+
+// LatestPrice returns the intraday last price of the given instrument unless its latest daily close is from a later day,
+// in which case that close is returned
+func (s *storedPriceSource) LatestPrice(ctx context.Context, instrumentID string) (entities.Money, error) {
+	daily, dailyErr := s.dailyPrice(ctx, instrumentID, time.Now().UTC())
+	if dailyErr != nil && !errors.Is(dailyErr, wrappers.NonExistentErr) {
+		return entities.Money{}, dailyErr
+	}
+
+	result, err := s.repository.GetLastPrice(ctx, instrumentID)
+	switch {
+	case err == nil:
+		last := result.(*entities.LastPrice)
+		if dailyErr != nil || !startOfDay(last.PricedAt).Before(daily.PriceDate) {
+			return last.Price, nil
+		}
+	case !errors.Is(err, wrappers.NonExistentErr):
+		return entities.Money{}, err
+	}
+
+	if dailyErr == nil {
+		return entities.NewMoney(daily.Close, daily.Currency), nil
+	}
+	return s.fallback.LatestPrice(ctx, instrumentID)
+}
+
+// PriceAt returns the latest daily close of the given instrument on or before date. A zero date is the current time.
+func (s *storedPriceSource) PriceAt(ctx context.Context, instrumentID string, date time.Time) (entities.Money, error) {
+	if date.IsZero() {
+		date = time.Now().UTC()
+	}
+
+	daily, err := s.dailyPrice(ctx, instrumentID, date)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			return s.fallback.PriceAt(ctx, instrumentID, date)
+		}
+		return entities.Money{}, err
+	}
+
+	return entities.NewMoney(daily.Close, daily.Currency), nil
+}
+
+// dailyPrice returns the latest daily price of an instrument on or before date
+func (s *storedPriceSource) dailyPrice(ctx context.Context, instrumentID string, date time.Time) (entities.Price, error) {
+	result, err := s.repository.GetLatest(ctx, instrumentID, date)
+	if err != nil {
+		return entities.Price{}, err
+	}
+	return *(result.(*entities.Price)), nil
+}
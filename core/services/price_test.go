@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestNewLastTradePriceSource_Ok checks that NewLastTradePriceSource creates a new lastTradePriceSource struct
+func TestNewLastTradePriceSource_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+
+	// Act
+	source := NewLastTradePriceSource(cfg, transactionRepositoryMock)
+
+	// Assert
+	assert.NotEmpty(t, source)
+}
+
+// TestLatestPrice_Ok checks that LatestPrice returns the price of the most recent transaction of the instrument
+func TestLatestPrice_Ok(t *testing.T) {
+	// Arrange
+	instrumentID := "stock-a"
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Get), context.Background(), map[string]interface{}{"stockid": instrumentID}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Transaction{TransactionID: "2", TransactionPrice: entities.NewMoney(decimal.RequireFromString("12.5"), "CZK"), TransactionDate: time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		&entities.Transaction{TransactionID: "1", TransactionPrice: entities.NewMoney(decimal.RequireFromString("10"), "CZK"), TransactionDate: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}, nil).Once()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Get), context.Background(), map[string]interface{}{"cryptocurrencyid": instrumentID}, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	source := &lastTradePriceSource{
+		config:     config.Config{},
+		repository: transactionRepositoryMock,
+	}
+
+	// Act
+	price, err := source.LatestPrice(context.Background(), instrumentID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "12.5 CZK", price.String())
+}
+
+// TestLatestPrice_NotFound checks that LatestPrice returns an error when the instrument has no transactions
+func TestLatestPrice_NotFound(t *testing.T) {
+	// Arrange
+	instrumentID := "stock-a"
+	expectedError := fmt.Sprintf("no price found for instrument %s", instrumentID)
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Get), context.Background(), map[string]interface{}{"stockid": instrumentID}, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Get), context.Background(), map[string]interface{}{"cryptocurrencyid": instrumentID}, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	source := &lastTradePriceSource{
+		config:     config.Config{},
+		repository: transactionRepositoryMock,
+	}
+
+	// Act
+	_, err := source.LatestPrice(context.Background(), instrumentID)
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestLatestPrice_GetError checks that LatestPrice returns an error when the Get function from the repository fails
+func TestLatestPrice_GetError(t *testing.T) {
+	// Arrange
+	instrumentID := "stock-a"
+	expectedError := "repository-error"
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Get), context.Background(), map[string]interface{}{"stockid": instrumentID}, (*int)(nil), (*int)(nil)).Return(nil, fmt.Errorf(expectedError)).Once()
+
+	source := &lastTradePriceSource{
+		config:     config.Config{},
+		repository: transactionRepositoryMock,
+	}
+
+	// Act
+	_, err := source.LatestPrice(context.Background(), instrumentID)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestPriceAt_Ok checks that PriceAt ignores the transactions made after the given date
+func TestPriceAt_Ok(t *testing.T) {
+	// Arrange
+	instrumentID := "stock-a"
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Get), context.Background(), map[string]interface{}{"stockid": instrumentID}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Transaction{TransactionID: "2", TransactionPrice: entities.NewMoney(decimal.RequireFromString("12.5"), "CZK"), TransactionDate: time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		&entities.Transaction{TransactionID: "1", TransactionPrice: entities.NewMoney(decimal.RequireFromString("10"), "CZK"), TransactionDate: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}, nil).Once()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Get), context.Background(), map[string]interface{}{"cryptocurrencyid": instrumentID}, (*int)(nil), (*int)(nil)).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	source := &lastTradePriceSource{
+		config:     config.Config{},
+		repository: transactionRepositoryMock,
+	}
+
+	// Act
+	price, err := source.PriceAt(context.Background(), instrumentID, time.Date(2023, time.January, 15, 0, 0, 0, 0, time.UTC))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "10 CZK", price.String())
+}
+
+// TestNewStoredPriceSource_Ok checks that NewStoredPriceSource creates a new storedPriceSource struct
+func TestNewStoredPriceSource_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+
+	// Act
+	source := NewStoredPriceSource(cfg, mocks.NewPriceRepository(t), mocks.NewPriceSource(t))
+
+	// Assert
+	assert.NotEmpty(t, source)
+}
+
+// TestStoredLatestPrice_LastPrice checks that LatestPrice returns the intraday last price when it is not older than the latest close
+func TestStoredLatestPrice_LastPrice(t *testing.T) {
+	// Arrange
+	instrumentID := "stock-a"
+
+	priceRepositoryMock := mocks.NewPriceRepository(t)
+	priceRepositoryMock.On(testutils.FunctionName(t, ports.PriceRepository.GetLatest), context.Background(), instrumentID, mock.AnythingOfType("time.Time")).Return(&entities.Price{Close: decimal.NewFromInt(10), Currency: "USD", PriceDate: time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC)}, nil).Once()
+	priceRepositoryMock.On(testutils.FunctionName(t, ports.PriceRepository.GetLastPrice), context.Background(), instrumentID).Return(&entities.LastPrice{Price: entities.NewMoney(decimal.RequireFromString("10.5"), "USD"), PricedAt: time.Date(2023, time.August, 4, 15, 0, 0, 0, time.UTC)}, nil).Once()
+
+	source := &storedPriceSource{
+		config:     config.Config{},
+		repository: priceRepositoryMock,
+	}
+
+	// Act
+	price, err := source.LatestPrice(context.Background(), instrumentID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "10.5 USD", price.String())
+}
+
+// TestStoredLatestPrice_StaleLastPrice checks that LatestPrice returns the latest close when the intraday last price is older
+func TestStoredLatestPrice_StaleLastPrice(t *testing.T) {
+	// Arrange
+	instrumentID := "stock-a"
+
+	priceRepositoryMock := mocks.NewPriceRepository(t)
+	priceRepositoryMock.On(testutils.FunctionName(t, ports.PriceRepository.GetLatest), context.Background(), instrumentID, mock.AnythingOfType("time.Time")).Return(&entities.Price{Close: decimal.NewFromInt(11), Currency: "USD", PriceDate: time.Date(2023, time.August, 4, 0, 0, 0, 0, time.UTC)}, nil).Once()
+	priceRepositoryMock.On(testutils.FunctionName(t, ports.PriceRepository.GetLastPrice), context.Background(), instrumentID).Return(&entities.LastPrice{Price: entities.NewMoney(decimal.RequireFromString("10.5"), "USD"), PricedAt: time.Date(2023, time.August, 3, 15, 0, 0, 0, time.UTC)}, nil).Once()
+
+	source := &storedPriceSource{
+		config:     config.Config{},
+		repository: priceRepositoryMock,
+	}
+
+	// Act
+	price, err := source.LatestPrice(context.Background(), instrumentID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "11 USD", price.String())
+}
+
+// TestStoredPriceAt_Fallback checks that PriceAt asks the fallback source when the instrument has no stored prices
+func TestStoredPriceAt_Fallback(t *testing.T) {
+	// Arrange
+	instrumentID := "stock-a"
+	date := time.Date(2023, time.August, 4, 0, 0, 0, 0, time.UTC)
+
+	priceRepositoryMock := mocks.NewPriceRepository(t)
+	priceRepositoryMock.On(testutils.FunctionName(t, ports.PriceRepository.GetLatest), context.Background(), instrumentID, date).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	fallbackMock := mocks.NewPriceSource(t)
+	fallbackMock.On(testutils.FunctionName(t, ports.PriceSource.PriceAt), context.Background(), instrumentID, date).Return(entities.NewMoney(decimal.NewFromInt(9), "USD"), nil).Once()
+
+	source := &storedPriceSource{
+		config:     config.Config{},
+		repository: priceRepositoryMock,
+		fallback:   fallbackMock,
+	}
+
+	// Act
+	price, err := source.PriceAt(context.Background(), instrumentID, date)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "9 USD", price.String())
+}
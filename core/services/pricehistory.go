@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+const (
+	// importBatchSize is the number of rows stored at once while importing files
+	importBatchSize = 1000
+	// priceSourceCSV is the source of the prices imported from CSV files that do not name one
+	priceSourceCSV = "csv"
+)
+
+// priceCSVDateLayouts are the date layouts of the Yahoo Finance and Stooq CSV files
+var priceCSVDateLayouts = []string{"2006-01-02", "20060102"}
+
+// priceService adapter of a price service
+type priceService struct {
+	config          config.Config
+	repository      ports.PriceRepository
+	stockRepository ports.StockRepository
+}
+
+// NewPriceService creates a new price service
+func NewPriceService(cfg config.Config, repo ports.PriceRepository, stockRepo ports.StockRepository) ports.PriceService {
+	return &priceService{
+		config:          cfg,
+		repository:      repo,
+		stockRepository: stockRepo,
+	}
+}
+
+// CreateMany daily prices, replacing the prices already stored for the same instrument and day
+func (s *priceService) CreateMany(ctx context.Context, prices []models.CreatePriceReq) (resp models.MultiCreationResp, err error) {
+	var create []interface{}
+	now := time.Now().UTC()
+
+	for _, price := range prices {
+		if err = price.Validate(); err != nil {
+			return
+		}
+
+		price.PriceDate = startOfDay(price.PriceDate)
+		price.CreatedAt = now
+		price.UpdatedAt = now
+
+		create = append(create, entities.Price(price))
+	}
+
+	insertedIDs, err := s.repository.CreateMany(ctx, create)
+	if err != nil {
+		return
+	}
+
+	resp = models.MultiCreationResp{
+		InsertedIDs: insertedIDs,
+	}
+	return
+}
+
+// ImportCSV loads the daily prices of an instrument from a CSV file in the Yahoo Finance or Stooq download format.
+// Columns are found by their header, rows without a close price such as the null rows of Yahoo are skipped, and
+// prices already stored for the same day are replaced, so a file can be imported again.
+func (s *priceService) ImportCSV(ctx context.Context, req models.ImportPricesReq, file io.Reader) (resp models.PriceImportResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	source := req.Source
+	if source == "" {
+		source = priceSourceCSV
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		err = wrappers.NewValidationErr(fmt.Errorf("invalid price CSV file: %w", err))
+		return
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")))] = i
+	}
+	if _, ok := columns["date"]; !ok {
+		err = wrappers.NewValidationErr(fmt.Errorf("invalid price CSV file: a Date column is required"))
+		return
+	}
+	if _, ok := columns["close"]; !ok {
+		err = wrappers.NewValidationErr(fmt.Errorf("invalid price CSV file: a Close column is required"))
+		return
+	}
+
+	resp.InstrumentID = req.InstrumentID
+	var batch []models.CreatePriceReq
+	for line := 2; ; line++ {
+		record, readErr := reader.Read()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			err = wrappers.NewValidationErr(fmt.Errorf("invalid price CSV file: %w", readErr))
+			return
+		}
+
+		price, ok, parseErr := parsePriceRecord(record, columns)
+		if parseErr == nil && ok {
+			price.InstrumentID = req.InstrumentID
+			price.InstrumentType = req.InstrumentType
+			price.Currency = req.Currency
+			price.Source = source
+			parseErr = price.Validate()
+		}
+		if parseErr != nil {
+			err = wrappers.NewValidationErr(fmt.Errorf("line %d: %s", line, parseErr))
+			return
+		}
+		if !ok {
+			resp.Skipped++
+			continue
+		}
+
+		if resp.From.IsZero() || price.PriceDate.Before(resp.From) {
+			resp.From = price.PriceDate
+		}
+		if price.PriceDate.After(resp.To) {
+			resp.To = price.PriceDate
+		}
+		resp.Imported++
+
+		batch = append(batch, price)
+		if len(batch) >= importBatchSize {
+			if _, err = s.CreateMany(ctx, batch); err != nil {
+				return
+			}
+			batch = batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		_, err = s.CreateMany(ctx, batch)
+	}
+
+	return
+}
+
+// SetLastPrice stores the intraday last price of an instrument, replacing the previous one
+func (s *priceService) SetLastPrice(ctx context.Context, req models.SetLastPriceReq) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	req.PricedAt = req.PricedAt.UTC()
+	req.UpdatedAt = time.Now().UTC()
+
+	return s.repository.SetLastPrice(ctx, entities.LastPrice(req))
+}
+
+// GetStockPrices returns the daily prices of a stock over a date range grouped by the requested interval, together with
+// the runs of business days in the range without a daily price and the intraday last price of the stock
+func (s *priceService) GetStockPrices(ctx context.Context, stockID string, req models.PriceHistoryReq) (resp models.PriceHistoryResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	_, err = s.stockRepository.GetByID(ctx, stockID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", stockID))
+		}
+		return
+	}
+
+	from := startOfDay(req.From)
+	to := startOfDay(req.To)
+	if req.To.IsZero() {
+		to = startOfDay(time.Now().UTC())
+	}
+
+	var prices []entities.Price
+	result, err := s.repository.GetRange(ctx, stockID, from, to)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return
+	}
+	err = nil
+	for _, v := range result {
+		prices = append(prices, *(v.(*entities.Price)))
+	}
+
+	resp = models.PriceHistoryResp{
+		InstrumentID: stockID,
+		Interval:     req.Interval,
+		From:         from,
+		To:           to,
+		Prices:       priceBars(prices, req.Interval),
+		Gaps:         []models.PriceGapResp{},
+	}
+	if resp.Interval == "" {
+		resp.Interval = models.PriceIntervalDay
+	}
+
+	if len(prices) > 0 {
+		resp.Currency = prices[0].Currency
+		if req.From.IsZero() {
+			resp.From = startOfDay(prices[0].PriceDate)
+		}
+		gapsTo := resp.To
+		if req.To.IsZero() {
+			gapsTo = startOfDay(prices[len(prices)-1].PriceDate)
+		}
+		resp.Gaps = priceGaps(prices, resp.From, gapsTo)
+	} else if !req.From.IsZero() && !req.To.IsZero() {
+		resp.Gaps = priceGaps(nil, from, to)
+	}
+
+	lastPrice, err := s.repository.GetLastPrice(ctx, stockID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+	last := models.LastPriceResp(*(lastPrice.(*entities.LastPrice)))
+	resp.LastPrice = &last
+
+	return
+}
+
+// parsePriceRecord parses a row of a price CSV file, reporting false for the rows without a close price
+func parsePriceRecord(record []string, columns map[string]int) (price models.CreatePriceReq, ok bool, err error) {
+	field := func(name string) string {
+		i, found := columns[name]
+		if !found || i >= len(record) {
+			return ""
+		}
+		value := strings.TrimSpace(record[i])
+		if strings.EqualFold(value, "null") {
+			return ""
+		}
+		return value
+	}
+
+	value := field("date")
+	for _, layout := range priceCSVDateLayouts {
+		if price.PriceDate, err = time.Parse(layout, value); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("invalid date %q", value)
+		return
+	}
+
+	if field("close") == "" {
+		return
+	}
+
+	for name, target := range map[string]*decimal.Decimal{"open": &price.Open, "high": &price.High, "low": &price.Low, "close": &price.Close, "volume": &price.Volume} {
+		value := field(name)
+		if value == "" {
+			continue
+		}
+		if *target, err = decimal.NewFromString(value); err != nil {
+			err = fmt.Errorf("invalid %s %q", name, value)
+			return
+		}
+	}
+
+	ok = true
+	return
+}
+
+// priceBars groups chronologically sorted daily prices into bars of the interval, each dated on the first day of its week or month
+func priceBars(prices []entities.Price, interval string) []models.PriceBarResp {
+	bars := []models.PriceBarResp{}
+	for _, p := range prices {
+		date := periodStart(p.PriceDate, interval)
+		if n := len(bars); n > 0 && bars[n-1].Date.Equal(date) {
+			bar := &bars[n-1]
+			if p.High.GreaterThan(bar.High) {
+				bar.High = p.High
+			}
+			if !p.Low.IsZero() && (bar.Low.IsZero() || p.Low.LessThan(bar.Low)) {
+				bar.Low = p.Low
+			}
+			bar.Close = p.Close
+			bar.Volume = bar.Volume.Add(p.Volume)
+			continue
+		}
+		bars = append(bars, models.PriceBarResp{Date: date, Open: p.Open, High: p.High, Low: p.Low, Close: p.Close, Volume: p.Volume})
+	}
+	return bars
+}
+
+// periodStart returns the first day of the interval a day belongs to, weeks starting on Monday
+func periodStart(d time.Time, interval string) time.Time {
+	d = startOfDay(d)
+	switch interval {
+	case models.PriceIntervalWeek:
+		return d.AddDate(0, 0, -(int(d.Weekday())+6)%7)
+	case models.PriceIntervalMonth:
+		return time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return d
+	}
+}
+
+// priceGaps returns the runs of business days between from and to, both included, without a daily price
+func priceGaps(prices []entities.Price, from, to time.Time) []models.PriceGapResp {
+	priced := map[time.Time]bool{}
+	for _, p := range prices {
+		priced[startOfDay(p.PriceDate)] = true
+	}
+
+	gaps := []models.PriceGapResp{}
+	var gap *models.PriceGapResp
+	for d := from; !d.After(to); d = d.Add(oneDay) {
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			continue
+		}
+		if priced[d] {
+			if gap != nil {
+				gaps = append(gaps, *gap)
+				gap = nil
+			}
+			continue
+		}
+		if gap == nil {
+			gap = &models.PriceGapResp{From: d}
+		}
+		gap.To = d
+		gap.Days++
+	}
+	if gap != nil {
+		gaps = append(gaps, *gap)
+	}
+
+	return gaps
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestNewPriceService_Ok checks that NewPriceService creates a new priceService struct
+func TestNewPriceService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+
+	// Act
+	service := NewPriceService(cfg, mocks.NewPriceRepository(t), mocks.NewStockRepository(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestImportPricesCSV_Yahoo checks that ImportCSV stores the rows of a Yahoo Finance file and skips its null rows
+func TestImportPricesCSV_Yahoo(t *testing.T) {
+	// Arrange
+	file := "Date,Open,High,Low,Close,Adj Close,Volume\n" +
+		"2023-08-03,10,12,9.5,11,10.9,1000\n" +
+		"2023-08-04,null,null,null,null,null,null\n" +
+		"2023-08-07,11,13,10.5,12.5,12.4,2000\n"
+	req := models.ImportPricesReq{InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Currency: "USD"}
+
+	priceRepositoryMock := mocks.NewPriceRepository(t)
+	priceRepositoryMock.On(testutils.FunctionName(t, ports.PriceRepository.CreateMany), context.Background(), mock.MatchedBy(func(prices []interface{}) bool {
+		first := prices[0].(entities.Price)
+		return len(prices) == 2 && first.InstrumentID == "stock-a" && first.Close.String() == "11" && first.Volume.String() == "1000" && first.Source == priceSourceCSV
+	})).Return([]string{"1", "2"}, nil).Once()
+
+	service := &priceService{
+		config:     config.Config{},
+		repository: priceRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.ImportCSV(context.Background(), req, strings.NewReader(file))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.PriceImportResp{InstrumentID: "stock-a", From: time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC), To: time.Date(2023, time.August, 7, 0, 0, 0, 0, time.UTC), Imported: 2, Skipped: 1}, resp)
+}
+
+// TestImportPricesCSV_InvalidRow checks that ImportCSV returns a validation error naming the line of an invalid row
+func TestImportPricesCSV_InvalidRow(t *testing.T) {
+	// Arrange
+	file := "Date,Open,High,Low,Close,Volume\n20230803,10,12,9.5,-11,1000\n"
+	req := models.ImportPricesReq{InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Currency: "USD"}
+	expectedError := "line 2: close must be positive"
+
+	service := &priceService{
+		config: config.Config{},
+	}
+
+	// Act
+	_, err := service.ImportCSV(context.Background(), req, strings.NewReader(file))
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestImportPricesCSV_MissingClose checks that ImportCSV returns a validation error when the file has no Close column
+func TestImportPricesCSV_MissingClose(t *testing.T) {
+	// Arrange
+	file := "Date,Open\n2023-08-03,10\n"
+	req := models.ImportPricesReq{InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Currency: "USD"}
+	expectedError := "invalid price CSV file: a Close column is required"
+
+	service := &priceService{
+		config: config.Config{},
+	}
+
+	// Act
+	_, err := service.ImportCSV(context.Background(), req, strings.NewReader(file))
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetStockPrices_Weekly checks that GetStockPrices groups the daily prices by week and reports the business days without a price
+func TestGetStockPrices_Weekly(t *testing.T) {
+	// Arrange
+	stockID := "stock-a"
+	from := time.Date(2023, time.July, 31, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, time.August, 11, 0, 0, 0, 0, time.UTC)
+	req := models.PriceHistoryReq{From: from, To: to, Interval: models.PriceIntervalWeek}
+
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), stockID).Return(&entities.Stock{StockID: stockID}, nil).Once()
+
+	day := func(d int, open, high, low, close, volume int64) interface{} {
+		return &entities.Price{InstrumentID: stockID, PriceDate: time.Date(2023, time.August, d, 0, 0, 0, 0, time.UTC), Open: decimal.NewFromInt(open), High: decimal.NewFromInt(high), Low: decimal.NewFromInt(low), Close: decimal.NewFromInt(close), Volume: decimal.NewFromInt(volume), Currency: "USD"}
+	}
+	priceRepositoryMock := mocks.NewPriceRepository(t)
+	priceRepositoryMock.On(testutils.FunctionName(t, ports.PriceRepository.GetRange), context.Background(), stockID, from, to).Return([]interface{}{
+		day(1, 10, 12, 9, 11, 100),
+		day(2, 11, 15, 10, 14, 200),
+		day(4, 14, 14, 8, 9, 300),
+		day(7, 9, 10, 9, 10, 400),
+		day(11, 10, 11, 10, 11, 500),
+	}, nil).Once()
+	priceRepositoryMock.On(testutils.FunctionName(t, ports.PriceRepository.GetLastPrice), context.Background(), stockID).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &priceService{
+		config:          config.Config{},
+		repository:      priceRepositoryMock,
+		stockRepository: stockRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetStockPrices(context.Background(), stockID, req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "USD", resp.Currency)
+	assert.Len(t, resp.Prices, 2)
+	assert.Equal(t, from, resp.Prices[0].Date)
+	assert.Equal(t, "10", resp.Prices[0].Open.String())
+	assert.Equal(t, "15", resp.Prices[0].High.String())
+	assert.Equal(t, "8", resp.Prices[0].Low.String())
+	assert.Equal(t, "9", resp.Prices[0].Close.String())
+	assert.Equal(t, "600", resp.Prices[0].Volume.String())
+	assert.Equal(t, time.Date(2023, time.August, 7, 0, 0, 0, 0, time.UTC), resp.Prices[1].Date)
+	assert.Equal(t, []models.PriceGapResp{
+		{From: from, To: from, Days: 1},
+		{From: time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC), To: time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC), Days: 1},
+		{From: time.Date(2023, time.August, 8, 0, 0, 0, 0, time.UTC), To: time.Date(2023, time.August, 10, 0, 0, 0, 0, time.UTC), Days: 3},
+	}, resp.Gaps)
+	assert.Nil(t, resp.LastPrice)
+}
+
+// TestGetStockPrices_StockNotFound checks that GetStockPrices returns an error when the stock does not exist
+func TestGetStockPrices_StockNotFound(t *testing.T) {
+	// Arrange
+	stockID := "stock-a"
+	expectedError := fmt.Sprintf("ID %s not found", stockID)
+
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), stockID).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &priceService{
+		config:          config.Config{},
+		stockRepository: stockRepositoryMock,
+	}
+
+	// Act
+	_, err := service.GetStockPrices(context.Background(), stockID, models.PriceHistoryReq{})
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestSetLastPrice_Ok checks that SetLastPrice stores a valid last price
+func TestSetLastPrice_Ok(t *testing.T) {
+	// Arrange
+	req := models.SetLastPriceReq{InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Price: entities.NewMoney(decimal.RequireFromString("10.5"), "USD"), PricedAt: time.Date(2023, time.August, 4, 15, 0, 0, 0, time.UTC)}
+
+	priceRepositoryMock := mocks.NewPriceRepository(t)
+	priceRepositoryMock.On(testutils.FunctionName(t, ports.PriceRepository.SetLastPrice), context.Background(), mock.AnythingOfType("entities.LastPrice")).Return(nil).Once()
+
+	service := &priceService{
+		config:     config.Config{},
+		repository: priceRepositoryMock,
+	}
+
+	// Act
+	err := service.SetLastPrice(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+}
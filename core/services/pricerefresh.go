@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+const (
+	// defaultPriceRefreshRuns is the number of price refresh runs returned when no limit is requested
+	defaultPriceRefreshRuns = 20
+	// maxPriceRefreshRuns is the largest number of price refresh runs returned at once
+	maxPriceRefreshRuns = 100
+)
+
+// priceRefreshService adapter of a price refresh service
+type priceRefreshService struct {
+	config                   config.Config
+	repository               ports.PriceRefreshRunRepository
+	provider                 ports.MarketDataProvider
+	priceService             ports.PriceService
+	transactionRepository    ports.TransactionRepository
+	stockRepository          ports.StockRepository
+	cryptoCurrencyRepository ports.CryptoCurrencyRepository
+}
+
+// NewPriceRefreshService creates a new price refresh service
+func NewPriceRefreshService(cfg config.Config, repo ports.PriceRefreshRunRepository, provider ports.MarketDataProvider, priceService ports.PriceService, transactionRepo ports.TransactionRepository, stockRepo ports.StockRepository, cryptoCurrencyRepo ports.CryptoCurrencyRepository) ports.PriceRefreshService {
+	return &priceRefreshService{
+		config:                   cfg,
+		repository:               repo,
+		provider:                 provider,
+		priceService:             priceService,
+		transactionRepository:    transactionRepo,
+		stockRepository:          stockRepo,
+		cryptoCurrencyRepository: cryptoCurrencyRepo,
+	}
+}
+
+// openInstrument is an instrument held in an open position
+type openInstrument struct {
+	id             string
+	instrumentType string
+}
+
+// Refresh fetches from the market data provider the quote of every instrument held in an open position and stores it as
+// its last price. An instrument that cannot be refreshed does not stop the others, it is reported in the failures of the run.
+// Every run is stored with its stats.
+func (s *priceRefreshService) Refresh(ctx context.Context) (resp models.PriceRefreshRunResp, err error) {
+	run := entities.PriceRefreshRun{
+		Provider:  s.provider.Name(),
+		StartedAt: time.Now().UTC(),
+		Failures:  []entities.PriceRefreshFailure{},
+	}
+
+	instruments, err := openInstruments(ctx, s.transactionRepository)
+	if err != nil {
+		return
+	}
+	run.Instruments = len(instruments)
+
+	for _, instrument := range instruments {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		symbol, refreshErr := instrumentSymbol(ctx, s.stockRepository, s.cryptoCurrencyRepository, instrument)
+		if refreshErr == nil {
+			refreshErr = s.refresh(ctx, instrument, symbol)
+		}
+		if refreshErr != nil {
+			run.Failed++
+			run.Failures = append(run.Failures, entities.PriceRefreshFailure{
+				InstrumentID:   instrument.id,
+				InstrumentType: instrument.instrumentType,
+				Symbol:         symbol,
+				Error:          refreshErr.Error(),
+			})
+			continue
+		}
+		run.Updated++
+	}
+
+	run.FinishedAt = time.Now().UTC()
+	run.DurationMs = run.FinishedAt.Sub(run.StartedAt).Milliseconds()
+
+	run.PriceRefreshRunID, err = s.repository.Create(ctx, run)
+	if err != nil {
+		return
+	}
+
+	resp = models.PriceRefreshRunResp(run)
+	return
+}
+
+// GetRuns returns the most recent price refresh runs, newest first
+func (s *priceRefreshService) GetRuns(ctx context.Context, limit int) (resp []models.PriceRefreshRunResp, err error) {
+	if limit < 0 {
+		err = wrappers.NewValidationErr(fmt.Errorf("limit cannot be negative"))
+		return
+	}
+	if limit == 0 {
+		limit = defaultPriceRefreshRuns
+	}
+	if limit > maxPriceRefreshRuns {
+		limit = maxPriceRefreshRuns
+	}
+
+	resp = []models.PriceRefreshRunResp{}
+	result, err := s.repository.GetRecent(ctx, limit)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	for _, v := range result {
+		resp = append(resp, models.PriceRefreshRunResp(*v.(*entities.PriceRefreshRun)))
+	}
+	return
+}
+
+// openInstruments returns the instruments with a positive quantity in at least one holding, sorted by ID
+func openInstruments(ctx context.Context, transactionRepo ports.TransactionRepository) ([]openInstrument, error) {
+	result, err := transactionRepo.Get(ctx, map[string]interface{}{}, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	type holdingInstrument struct {
+		holdingID    string
+		instrumentID string
+	}
+	quantities := map[holdingInstrument]decimal.Decimal{}
+	types := map[string]string{}
+	for _, v := range result {
+		t := v.(*entities.Transaction)
+		instrumentID, instrumentType := instrumentOf(*t)
+		if instrumentID == "" {
+			continue
+		}
+		key := holdingInstrument{holdingID: t.HoldingID, instrumentID: instrumentID}
+		quantities[key] = quantities[key].Add(t.Quantity)
+		types[instrumentID] = instrumentType
+	}
+
+	open := map[string]bool{}
+	for key, quantity := range quantities {
+		if quantity.IsPositive() {
+			open[key.instrumentID] = true
+		}
+	}
+
+	instruments := make([]openInstrument, 0, len(open))
+	for id := range open {
+		instruments = append(instruments, openInstrument{id: id, instrumentType: types[id]})
+	}
+	sort.Slice(instruments, func(i, j int) bool {
+		return instruments[i].id < instruments[j].id
+	})
+	return instruments, nil
+}
+
+// instrumentSymbol returns the ticker symbol of a stock or the symbol of a cryptocurrency
+func instrumentSymbol(ctx context.Context, stockRepo ports.StockRepository, cryptoCurrencyRepo ports.CryptoCurrencyRepository, instrument openInstrument) (string, error) {
+	var symbol string
+	if instrument.instrumentType == entities.EntityNameStock {
+		stock, err := stockRepo.GetByID(ctx, instrument.id)
+		if err != nil {
+			return "", err
+		}
+		symbol = stock.(*entities.Stock).TickerSymbol
+	} else {
+		cryptoCurrency, err := cryptoCurrencyRepo.GetByID(ctx, instrument.id)
+		if err != nil {
+			return "", err
+		}
+		symbol = cryptoCurrency.(*entities.CryptoCurrency).Symbol
+	}
+
+	if symbol == "" {
+		return "", fmt.Errorf("%s %s has no symbol", instrument.instrumentType, instrument.id)
+	}
+	return symbol, nil
+}
+
+// refresh stores the quote of a symbol as the last price of an instrument
+func (s *priceRefreshService) refresh(ctx context.Context, instrument openInstrument, symbol string) error {
+	quote, err := s.provider.Quote(ctx, symbol)
+	if err != nil {
+		return err
+	}
+
+	return s.priceService.SetLastPrice(ctx, models.SetLastPriceReq{
+		InstrumentID:   instrument.id,
+		InstrumentType: instrument.instrumentType,
+		Price:          quote.Price,
+		PricedAt:       quote.QuotedAt,
+		Source:         s.provider.Name(),
+	})
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestNewPriceRefreshService_Ok checks that NewPriceRefreshService creates a new priceRefreshService struct
+func TestNewPriceRefreshService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+
+	// Act
+	service := NewPriceRefreshService(cfg, mocks.NewPriceRefreshRunRepository(t), mocks.NewMarketDataProvider(t), mocks.NewPriceService(t), mocks.NewTransactionRepository(t), mocks.NewStockRepository(t), mocks.NewCryptoCurrencyRepository(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestRefresh_OpenPositions checks that Refresh only refreshes the instruments of open positions, reports the failed ones and stores the run
+func TestRefresh_OpenPositions(t *testing.T) {
+	// Arrange
+	quotedAt := time.Date(2023, time.August, 4, 20, 0, 0, 0, time.UTC)
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Transaction{HoldingID: "holding-a", StockID: "stock-a", Quantity: decimal.NewFromInt(10)},
+		&entities.Transaction{HoldingID: "holding-a", StockID: "stock-b", Quantity: decimal.NewFromInt(5)},
+		&entities.Transaction{HoldingID: "holding-a", StockID: "stock-b", Quantity: decimal.NewFromInt(-5)},
+		&entities.Transaction{HoldingID: "holding-b", CryptocurrencyID: "crypto-a", Quantity: decimal.RequireFromString("0.5")},
+	}, nil).Once()
+
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-a").Return(&entities.Stock{StockID: "stock-a", TickerSymbol: "AAPL"}, nil).Once()
+	cryptoCurrencyRepositoryMock := mocks.NewCryptoCurrencyRepository(t)
+	cryptoCurrencyRepositoryMock.On(testutils.FunctionName(t, ports.CryptoCurrencyRepository.GetByID), context.Background(), "crypto-a").Return(&entities.CryptoCurrency{CryptoCurrencyID: "crypto-a", Symbol: "DOGE"}, nil).Once()
+
+	providerMock := mocks.NewMarketDataProvider(t)
+	providerMock.On(testutils.FunctionName(t, ports.MarketDataProvider.Name)).Return("file")
+	providerMock.On(testutils.FunctionName(t, ports.MarketDataProvider.Quote), context.Background(), "AAPL").Return(entities.Quote{Symbol: "AAPL", Price: entities.NewMoney(decimal.RequireFromString("181.99"), "USD"), QuotedAt: quotedAt}, nil).Once()
+	providerMock.On(testutils.FunctionName(t, ports.MarketDataProvider.Quote), context.Background(), "DOGE").Return(entities.Quote{}, errors.New("timeout")).Once()
+
+	priceServiceMock := mocks.NewPriceService(t)
+	priceServiceMock.On(testutils.FunctionName(t, ports.PriceService.SetLastPrice), context.Background(), mock.MatchedBy(func(req models.SetLastPriceReq) bool {
+		return req.InstrumentID == "stock-a" && req.InstrumentType == entities.EntityNameStock && req.Price.String() == "181.99 USD" && req.PricedAt.Equal(quotedAt) && req.Source == "file"
+	})).Return(nil).Once()
+
+	expectedFailures := []entities.PriceRefreshFailure{{InstrumentID: "crypto-a", InstrumentType: entities.EntityNameCryptoCurrency, Symbol: "DOGE", Error: "timeout"}}
+	runRepositoryMock := mocks.NewPriceRefreshRunRepository(t)
+	runRepositoryMock.On(testutils.FunctionName(t, ports.PriceRefreshRunRepository.Create), context.Background(), mock.MatchedBy(func(run entities.PriceRefreshRun) bool {
+		return run.Provider == "file" && run.Instruments == 2 && run.Updated == 1 && run.Failed == 1 && !run.FinishedAt.Before(run.StartedAt)
+	})).Return("run-id", nil).Once()
+
+	service := &priceRefreshService{
+		config:                   config.Config{},
+		repository:               runRepositoryMock,
+		provider:                 providerMock,
+		priceService:             priceServiceMock,
+		transactionRepository:    transactionRepositoryMock,
+		stockRepository:          stockRepositoryMock,
+		cryptoCurrencyRepository: cryptoCurrencyRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Refresh(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "run-id", resp.PriceRefreshRunID)
+	assert.Equal(t, 2, resp.Instruments)
+	assert.Equal(t, 1, resp.Updated)
+	assert.Equal(t, expectedFailures, resp.Failures)
+}
+
+// TestRefresh_NoSymbol checks that Refresh reports the instruments without a symbol as failures
+func TestRefresh_NoSymbol(t *testing.T) {
+	// Arrange
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Transaction{HoldingID: "holding-a", StockID: "stock-a", Quantity: decimal.NewFromInt(10)},
+	}, nil).Once()
+
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-a").Return(&entities.Stock{StockID: "stock-a"}, nil).Once()
+
+	providerMock := mocks.NewMarketDataProvider(t)
+	providerMock.On(testutils.FunctionName(t, ports.MarketDataProvider.Name)).Return("file").Once()
+
+	runRepositoryMock := mocks.NewPriceRefreshRunRepository(t)
+	runRepositoryMock.On(testutils.FunctionName(t, ports.PriceRefreshRunRepository.Create), context.Background(), mock.AnythingOfType("entities.PriceRefreshRun")).Return("run-id", nil).Once()
+
+	service := &priceRefreshService{
+		config:                config.Config{},
+		repository:            runRepositoryMock,
+		provider:              providerMock,
+		transactionRepository: transactionRepositoryMock,
+		stockRepository:       stockRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Refresh(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, resp.Failed)
+	assert.Equal(t, "stock stock-a has no symbol", resp.Failures[0].Error)
+}
+
+// TestRefresh_TransactionsError checks that Refresh returns an error when the transactions cannot be loaded
+func TestRefresh_TransactionsError(t *testing.T) {
+	// Arrange
+	expectedError := "transactions error"
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Get), context.Background(), map[string]interface{}{}, (*int)(nil), (*int)(nil)).Return(nil, errors.New(expectedError)).Once()
+
+	providerMock := mocks.NewMarketDataProvider(t)
+	providerMock.On(testutils.FunctionName(t, ports.MarketDataProvider.Name)).Return("file").Once()
+
+	service := &priceRefreshService{
+		config:                config.Config{},
+		provider:              providerMock,
+		transactionRepository: transactionRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Refresh(context.Background())
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetPriceRefreshRuns_Ok checks that GetRuns returns the most recent runs with the default limit
+func TestGetPriceRefreshRuns_Ok(t *testing.T) {
+	// Arrange
+	runRepositoryMock := mocks.NewPriceRefreshRunRepository(t)
+	runRepositoryMock.On(testutils.FunctionName(t, ports.PriceRefreshRunRepository.GetRecent), context.Background(), defaultPriceRefreshRuns).Return([]interface{}{
+		&entities.PriceRefreshRun{PriceRefreshRunID: "run-id", Updated: 3},
+	}, nil).Once()
+
+	service := &priceRefreshService{
+		config:     config.Config{},
+		repository: runRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetRuns(context.Background(), 0)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []models.PriceRefreshRunResp{{PriceRefreshRunID: "run-id", Updated: 3}}, resp)
+}
+
+// TestGetPriceRefreshRuns_NoRuns checks that GetRuns returns an empty list when no run is stored
+func TestGetPriceRefreshRuns_NoRuns(t *testing.T) {
+	// Arrange
+	runRepositoryMock := mocks.NewPriceRefreshRunRepository(t)
+	runRepositoryMock.On(testutils.FunctionName(t, ports.PriceRefreshRunRepository.GetRecent), context.Background(), maxPriceRefreshRuns).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &priceRefreshService{
+		config:     config.Config{},
+		repository: runRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetRuns(context.Background(), 500)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []models.PriceRefreshRunResp{}, resp)
+}
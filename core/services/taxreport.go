@@ -0,0 +1,453 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// taxReportService adapter of a tax report service
+type taxReportService struct {
+	config                config.Config
+	portfolioRepository   ports.PortfolioRepository
+	transactionRepository ports.TransactionRepository
+	stockRepository       ports.StockRepository
+	dividendRepository    ports.DividendRepository
+	withholdingRepository ports.DividendWithholdingRepository
+	converter             ports.CurrencyConverter
+	rules                 map[string]ports.TaxRule
+}
+
+// NewTaxReportService creates a new tax report service with the tax rules by country
+func NewTaxReportService(cfg config.Config, portfolioRepo ports.PortfolioRepository, transactionRepo ports.TransactionRepository, stockRepo ports.StockRepository, dividendRepo ports.DividendRepository, withholdingRepo ports.DividendWithholdingRepository, converter ports.CurrencyConverter, rules map[string]ports.TaxRule) ports.TaxReportService {
+	return &taxReportService{
+		config:                cfg,
+		portfolioRepository:   portfolioRepo,
+		transactionRepository: transactionRepo,
+		stockRepository:       stockRepo,
+		dividendRepository:    dividendRepo,
+		withholdingRepository: withholdingRepo,
+		converter:             converter,
+		rules:                 rules,
+	}
+}
+
+// GetReport returns the gains realized by a portfolio in a financial year as assessed by the tax rule of the portfolio's tax country.
+// Financial years start on the month and day of the portfolio's financial year and are named by the year they start in, the one in
+// progress being returned when no year is requested. Amounts are reported in the currency of the rule. The portfolio must belong to
+// the user.
+func (s *taxReportService) GetReport(ctx context.Context, userID, portfolioID string, req models.TaxReportReq) (resp models.TaxReportResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	portfolio, transactions, err := loadPortfolioTransactions(ctx, s.portfolioRepository, s.transactionRepository, portfolioID)
+	if err != nil {
+		return
+	}
+	if err = checkOwner(portfolio, userID); err != nil {
+		return
+	}
+
+	country, rule, err := s.rule(portfolio)
+	if err != nil {
+		return
+	}
+
+	method := rule.CostBasisMethod()
+	if method == 0 {
+		method = costBasisMethod(portfolio)
+	}
+	ledger, err := buildLedger(transactions, method)
+	if err != nil {
+		return
+	}
+
+	year := req.Year
+	if year == 0 {
+		year = financialYearOf(portfolio.FinancialYear, time.Now().UTC())
+	}
+	from, to := financialYear(portfolio.FinancialYear, year)
+
+	currency := rule.Currency()
+	var sales []entities.TaxSale
+	for _, g := range ledger.gains {
+		if sold := startOfDay(g.SoldAt); sold.Before(from) || sold.After(to) {
+			continue
+		}
+
+		sale := entities.TaxSale{
+			BuyTransactionID:  g.BuyTransactionID,
+			SellTransactionID: g.SellTransactionID,
+			InstrumentID:      g.InstrumentID,
+			InstrumentType:    g.InstrumentType,
+			Quantity:          g.Quantity,
+			AcquiredAt:        g.AcquiredAt,
+			SoldAt:            g.SoldAt,
+			HoldingDays:       int(startOfDay(g.SoldAt).Sub(startOfDay(g.AcquiredAt)) / oneDay),
+		}
+		if sale.CostBasis, err = s.converter.Convert(ctx, g.CostBasis, currency, g.AcquiredAt); err != nil {
+			return
+		}
+		if sale.Proceeds, err = s.converter.Convert(ctx, g.Proceeds, currency, g.SoldAt); err != nil {
+			return
+		}
+		if sale.Gain, err = sale.Proceeds.Sub(sale.CostBasis); err != nil {
+			return
+		}
+		sales = append(sales, sale)
+	}
+
+	assessment := rule.Assess(sales, from, to)
+
+	zero := entities.NewMoney(decimal.Zero, currency)
+	resp = models.TaxReportResp{
+		PortfolioID:     portfolioID,
+		Country:         country,
+		Year:            year,
+		From:            from,
+		To:              to,
+		Currency:        currency,
+		CostBasisMethod: method,
+		Proceeds:        zero,
+		CostBasis:       zero,
+		Gain:            zero,
+		ExemptGain:      zero,
+		Allowance:       assessment.Allowance,
+		TaxableGain:     zero,
+		Treatments:      []models.TaxTreatmentResp{},
+		Sales:           []models.TaxSaleResp{},
+	}
+
+	treatments := map[string]int{}
+	for _, sale := range assessment.Sales {
+		resp.Sales = append(resp.Sales, models.TaxSaleResp(sale))
+		var exemptGain entities.Money
+		if exemptGain, err = sale.Gain.Sub(sale.TaxableGain); err != nil {
+			return
+		}
+		if resp.Proceeds, err = resp.Proceeds.Add(sale.Proceeds); err != nil {
+			return
+		}
+		if resp.CostBasis, err = resp.CostBasis.Add(sale.CostBasis); err != nil {
+			return
+		}
+		if resp.Gain, err = resp.Gain.Add(sale.Gain); err != nil {
+			return
+		}
+		if resp.ExemptGain, err = resp.ExemptGain.Add(exemptGain); err != nil {
+			return
+		}
+		if resp.TaxableGain, err = resp.TaxableGain.Add(sale.TaxableGain); err != nil {
+			return
+		}
+
+		i, ok := treatments[sale.Treatment]
+		if !ok {
+			i = len(resp.Treatments)
+			treatments[sale.Treatment] = i
+			resp.Treatments = append(resp.Treatments, models.TaxTreatmentResp{Treatment: sale.Treatment, Proceeds: zero, Gain: zero, TaxableGain: zero})
+		}
+		treatment := &resp.Treatments[i]
+		treatment.Sales++
+		if treatment.Proceeds, err = treatment.Proceeds.Add(sale.Proceeds); err != nil {
+			return
+		}
+		if treatment.Gain, err = treatment.Gain.Add(sale.Gain); err != nil {
+			return
+		}
+		if treatment.TaxableGain, err = treatment.TaxableGain.Add(sale.TaxableGain); err != nil {
+			return
+		}
+	}
+	resp.TaxableGain, err = resp.TaxableGain.Sub(resp.Allowance)
+
+	return
+}
+
+// GetDividends returns the dividends paid to a portfolio in a financial year with the tax withheld from them and the foreign tax
+// credit they give in the portfolio's tax country. Dividends are paid on the shares held before their ex-date and belong to the
+// financial year of their pay date. The tax withheld is the one recorded for the payment to the portfolio, such as the one reported
+// by the broker statement it was imported from. Otherwise it is estimated at the treaty rate for the issuer countries granting relief
+// at source and at their statutory rate for the others. The credit of a foreign dividend is limited to the treaty rate, the tax withheld over it
+// being reclaimable from the issuer country, and domestic dividends give no credit. Amounts are reported in the currency of the
+// tax rule, converted at the rates of the pay dates. The portfolio must belong to the user.
+func (s *taxReportService) GetDividends(ctx context.Context, userID, portfolioID string, req models.TaxReportReq) (resp models.DividendTaxReportResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	portfolio, transactions, err := loadPortfolioTransactions(ctx, s.portfolioRepository, s.transactionRepository, portfolioID)
+	if err != nil {
+		return
+	}
+	if err = checkOwner(portfolio, userID); err != nil {
+		return
+	}
+
+	country, rule, err := s.rule(portfolio)
+	if err != nil {
+		return
+	}
+
+	year := req.Year
+	if year == 0 {
+		year = financialYearOf(portfolio.FinancialYear, time.Now().UTC())
+	}
+	from, to := financialYear(portfolio.FinancialYear, year)
+
+	currency := rule.Currency()
+	zero := entities.NewMoney(decimal.Zero, currency)
+	resp = models.DividendTaxReportResp{
+		PortfolioID:      portfolioID,
+		Country:          country,
+		Year:             year,
+		From:             from,
+		To:               to,
+		Currency:         currency,
+		Gross:            zero,
+		Withholding:      zero,
+		Net:              zero,
+		ForeignTaxCredit: zero,
+		Reclaimable:      zero,
+		Countries:        []models.DividendCountryResp{},
+		Payments:         []models.DividendPaymentResp{},
+	}
+
+	withheld, err := s.withheld(ctx, portfolioID)
+	if err != nil {
+		return
+	}
+
+	seen := map[string]bool{}
+	for _, t := range transactions {
+		if t.StockID == "" || seen[t.StockID] {
+			continue
+		}
+		seen[t.StockID] = true
+
+		var payments []models.DividendPaymentResp
+		if payments, err = s.dividendPayments(ctx, transactions, withheld, t.StockID, country, currency, from, to); err != nil {
+			return
+		}
+		resp.Payments = append(resp.Payments, payments...)
+	}
+	sort.SliceStable(resp.Payments, func(i, j int) bool {
+		return resp.Payments[i].PayDate.Before(resp.Payments[j].PayDate)
+	})
+
+	countries := map[string]int{}
+	for _, p := range resp.Payments {
+		if resp.Gross, err = resp.Gross.Add(p.Gross); err != nil {
+			return
+		}
+		if resp.Withholding, err = resp.Withholding.Add(p.Withholding); err != nil {
+			return
+		}
+		if resp.Net, err = resp.Net.Add(p.Net); err != nil {
+			return
+		}
+		if resp.ForeignTaxCredit, err = resp.ForeignTaxCredit.Add(p.ForeignTaxCredit); err != nil {
+			return
+		}
+		if resp.Reclaimable, err = resp.Reclaimable.Add(p.Reclaimable); err != nil {
+			return
+		}
+
+		i, ok := countries[p.Country]
+		if !ok {
+			i = len(resp.Countries)
+			countries[p.Country] = i
+			resp.Countries = append(resp.Countries, models.DividendCountryResp{Country: p.Country, Gross: zero, Withholding: zero, Net: zero, ForeignTaxCredit: zero, Reclaimable: zero})
+		}
+		c := &resp.Countries[i]
+		c.Payments++
+		if c.Gross, err = c.Gross.Add(p.Gross); err != nil {
+			return
+		}
+		if c.Withholding, err = c.Withholding.Add(p.Withholding); err != nil {
+			return
+		}
+		if c.Net, err = c.Net.Add(p.Net); err != nil {
+			return
+		}
+		if c.ForeignTaxCredit, err = c.ForeignTaxCredit.Add(p.ForeignTaxCredit); err != nil {
+			return
+		}
+		if c.Reclaimable, err = c.Reclaimable.Add(p.Reclaimable); err != nil {
+			return
+		}
+	}
+	sort.SliceStable(resp.Countries, func(i, j int) bool {
+		return resp.Countries[i].Country < resp.Countries[j].Country
+	})
+
+	return
+}
+
+// dividendPayments returns the dividends of a stock paid within the range to a resident of a country, converted to the currency, with
+// the tax withheld from them by dividend ID when it is recorded
+func (s *taxReportService) dividendPayments(ctx context.Context, transactions []entities.Transaction, withheld map[string]entities.Money, stockID, residence, currency string, from, to time.Time) ([]models.DividendPaymentResp, error) {
+	result, err := s.dividendRepository.Get(ctx, map[string]interface{}{"stockid": stockID}, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	stock, err := s.stockRepository.GetByID(ctx, stockID)
+	if err != nil {
+		return nil, err
+	}
+	issuer := strings.ToUpper(stock.(*entities.Stock).Country)
+
+	var payments []models.DividendPaymentResp
+	for _, v := range result {
+		dividend := *(v.(*entities.Dividend))
+		paid := startOfDay(dividend.PaidOn())
+		if paid.Before(from) || paid.After(to) {
+			continue
+		}
+		shares := sharesEntitled(transactions, dividend)
+		if !shares.IsPositive() {
+			continue
+		}
+
+		gross, err := s.converter.Convert(ctx, dividend.DividendPerShare.Mul(shares), currency, paid)
+		if err != nil {
+			return nil, err
+		}
+
+		withheldRate, treatyRate := withholdingRates(s.config.Withholding, issuer, residence)
+		withholding := gross.Mul(withheldRate)
+		recorded, ok := withheld[dividend.DividendID]
+		if ok {
+			if withholding, err = s.converter.Convert(ctx, recorded, currency, paid); err != nil {
+				return nil, err
+			}
+			withheldRate = decimal.Zero
+			if gross.Amount.IsPositive() {
+				withheldRate = withholding.Amount.Div(gross.Amount).Round(4)
+			}
+		}
+		credit := entities.NewMoney(decimal.Zero, currency)
+		reclaimable := credit
+		if issuer != residence {
+			credit = gross.Mul(treatyRate)
+			if withholding.Amount.LessThan(credit.Amount) {
+				credit = withholding
+			}
+			if reclaimable, err = withholding.Sub(credit); err != nil {
+				return nil, err
+			}
+		}
+		net, err := gross.Sub(withholding)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, models.DividendPaymentResp{
+			DividendID:          dividend.DividendID,
+			StockID:             stockID,
+			Country:             issuer,
+			ExDate:              dividend.ExDate,
+			PayDate:             paid,
+			Shares:              shares,
+			DividendPerShare:    dividend.DividendPerShare,
+			Gross:               gross,
+			WithholdingRate:     withheldRate,
+			Withholding:         withholding,
+			WithholdingRecorded: ok,
+			Net:                 net,
+			TreatyRate:          treatyRate,
+			ForeignTaxCredit:    credit,
+			Reclaimable:         reclaimable,
+		})
+	}
+
+	return payments, nil
+}
+
+// withheld returns the taxes recorded as withheld from the dividends paid to a portfolio by dividend ID
+func (s *taxReportService) withheld(ctx context.Context, portfolioID string) (map[string]entities.Money, error) {
+	withheld := map[string]entities.Money{}
+	result, err := s.withholdingRepository.GetByPortfolioID(ctx, portfolioID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			return withheld, nil
+		}
+		return nil, err
+	}
+	for _, v := range result {
+		w := v.(*entities.DividendWithholding)
+		withheld[w.DividendID] = w.Amount
+	}
+	return withheld, nil
+}
+
+// rule returns the tax country of a portfolio and its tax rule
+func (s *taxReportService) rule(portfolio entities.Portfolio) (string, ports.TaxRule, error) {
+	country := strings.ToUpper(strings.TrimSpace(portfolio.TaxCountryID))
+	rule, ok := s.rules[country]
+	if !ok {
+		return "", nil, wrappers.NewValidationErr(fmt.Errorf("tax country %q not supported, available countries: %s", portfolio.TaxCountryID, strings.Join(taxCountries(s.rules), ", ")))
+	}
+	return country, rule, nil
+}
+
+// withholdingRates returns the rate withheld from the dividends of an issuer country paid to a resident of another country
+// and the rate of the treaty between them. Without a treaty the treaty rate is the statutory rate of the issuer country.
+func withholdingRates(cfg config.Withholding, issuer, residence string) (withheld, treaty decimal.Decimal) {
+	withheld = cfg.Rates[issuer]
+	treaty, ok := cfg.TreatyRates[issuer][residence]
+	if !ok || issuer == residence {
+		return withheld, withheld
+	}
+	for _, country := range cfg.ReliefAtSource {
+		if strings.EqualFold(country, issuer) {
+			withheld = treaty
+			break
+		}
+	}
+	return withheld, treaty
+}
+
+// financialYear returns the first and last day of the financial year starting in a year on the month and day of start,
+// which defaults to the 1st of January
+func financialYear(start time.Time, year int) (time.Time, time.Time) {
+	month, day := time.January, 1
+	if !start.IsZero() {
+		month, day = start.Month(), start.Day()
+	}
+	from := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return from, from.AddDate(1, 0, 0).Add(-oneDay)
+}
+
+// financialYearOf returns the year the financial year in progress on a date started in
+func financialYearOf(start time.Time, date time.Time) int {
+	year := date.Year()
+	if from, _ := financialYear(start, year); date.Before(from) {
+		year--
+	}
+	return year
+}
+
+// taxCountries returns the sorted countries of the tax rules
+func taxCountries(rules map[string]ports.TaxRule) []string {
+	countries := make([]string, 0, len(rules))
+	for country := range rules {
+		countries = append(countries, country)
+	}
+	sort.Strings(countries)
+	return countries
+}
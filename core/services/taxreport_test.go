@@ -0,0 +1,391 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/core/taxrules"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestNewTaxReportService_Ok checks that NewTaxReportService creates a new taxReportService struct
+func TestNewTaxReportService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+
+	// Act
+	service := NewTaxReportService(cfg, mocks.NewPortfolioRepository(t), mocks.NewTransactionRepository(t), mocks.NewStockRepository(t), mocks.NewDividendRepository(t), mocks.NewDividendWithholdingRepository(t), mocks.NewCurrencyConverter(t), taxrules.Rules())
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestGetTaxReport_Ok checks that GetReport assesses the sales of the financial year converted to the currency of the rule
+func TestGetTaxReport_Ok(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+	buyDate := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	sellDate := time.Date(2024, time.August, 15, 0, 0, 0, 0, time.UTC)
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-a", TaxCountryID: "us", FinancialYear: time.Date(2020, time.July, 1, 0, 0, 0, 0, time.UTC), PerformenceCalculation: entities.CostBasisFIFO}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{
+		&entities.Transaction{TransactionID: "1", StockID: "stock-a", Quantity: decimal.NewFromInt(10), TransactionPrice: entities.NewMoney(decimal.NewFromInt(100), "EUR"), TransactionDate: buyDate},
+		&entities.Transaction{TransactionID: "2", StockID: "stock-a", Quantity: decimal.NewFromInt(-2), TransactionPrice: entities.NewMoney(decimal.NewFromInt(120), "EUR"), TransactionDate: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		&entities.Transaction{TransactionID: "3", StockID: "stock-a", Quantity: decimal.NewFromInt(-4), TransactionPrice: entities.NewMoney(decimal.NewFromInt(150), "EUR"), TransactionDate: sellDate},
+	}, nil).Once()
+
+	converterMock := mocks.NewCurrencyConverter(t)
+	converterMock.On(testutils.FunctionName(t, ports.CurrencyConverter.Convert), context.Background(), entities.NewMoney(decimal.NewFromInt(400), "EUR"), "USD", buyDate).Return(entities.NewMoney(decimal.NewFromInt(430), "USD"), nil).Once()
+	converterMock.On(testutils.FunctionName(t, ports.CurrencyConverter.Convert), context.Background(), entities.NewMoney(decimal.NewFromInt(600), "EUR"), "USD", sellDate).Return(entities.NewMoney(decimal.NewFromInt(660), "USD"), nil).Once()
+
+	service := &taxReportService{
+		config:                config.Config{},
+		portfolioRepository:   portfolioRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		converter:             converterMock,
+		rules:                 taxrules.Rules(),
+	}
+
+	// Act
+	resp, err := service.GetReport(context.Background(), "user-a", portfolioID, models.TaxReportReq{Year: 2024})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "US", resp.Country)
+	assert.Equal(t, time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC), resp.From)
+	assert.Equal(t, time.Date(2025, time.June, 30, 0, 0, 0, 0, time.UTC), resp.To)
+	assert.Equal(t, "USD", resp.Currency)
+	assert.Len(t, resp.Sales, 1)
+	assert.Equal(t, entities.TaxTreatmentLongTerm, resp.Sales[0].Treatment)
+	assert.Equal(t, 533, resp.Sales[0].HoldingDays)
+	assert.Equal(t, "230 USD", resp.Gain.String())
+	assert.Equal(t, "230 USD", resp.TaxableGain.String())
+	assert.Equal(t, "0 USD", resp.ExemptGain.String())
+	assert.Equal(t, []models.TaxTreatmentResp{{Treatment: entities.TaxTreatmentLongTerm, Sales: 1, Proceeds: entities.NewMoney(decimal.NewFromInt(660), "USD"), Gain: resp.Gain, TaxableGain: resp.Gain}}, resp.Treatments)
+}
+
+// TestGetTaxReport_RuleCostBasisMethod checks that GetReport matches the lots with the cost basis method required by the rule and deducts its allowance
+func TestGetTaxReport_RuleCostBasisMethod(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+	sellDate := time.Date(2024, time.May, 2, 0, 0, 0, 0, time.UTC)
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-a", TaxCountryID: "DE", PerformenceCalculation: entities.CostBasisLIFO}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{
+		&entities.Transaction{TransactionID: "1", StockID: "stock-a", Quantity: decimal.NewFromInt(10), TransactionPrice: entities.NewMoney(decimal.NewFromInt(100), "EUR"), TransactionDate: time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		&entities.Transaction{TransactionID: "2", StockID: "stock-a", Quantity: decimal.NewFromInt(10), TransactionPrice: entities.NewMoney(decimal.NewFromInt(300), "EUR"), TransactionDate: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		&entities.Transaction{TransactionID: "3", StockID: "stock-a", Quantity: decimal.NewFromInt(-10), TransactionPrice: entities.NewMoney(decimal.NewFromInt(350), "EUR"), TransactionDate: sellDate},
+	}, nil).Once()
+
+	converterMock := mocks.NewCurrencyConverter(t)
+	converterMock.On(testutils.FunctionName(t, ports.CurrencyConverter.Convert), context.Background(), mock.AnythingOfType("entities.Money"), "EUR", mock.AnythingOfType("time.Time")).Return(func(ctx context.Context, amount entities.Money, currency string, date time.Time) (entities.Money, error) {
+		return amount, nil
+	}).Times(2)
+
+	service := &taxReportService{
+		config:                config.Config{},
+		portfolioRepository:   portfolioRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		converter:             converterMock,
+		rules:                 taxrules.Rules(),
+	}
+
+	// Act
+	resp, err := service.GetReport(context.Background(), "user-a", portfolioID, models.TaxReportReq{Year: 2024})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, entities.CostBasisFIFO, resp.CostBasisMethod)
+	assert.Equal(t, "1", resp.Sales[0].BuyTransactionID)
+	assert.Equal(t, "2500 EUR", resp.Gain.String())
+	assert.Equal(t, "1000 EUR", resp.Allowance.String())
+	assert.Equal(t, "1500 EUR", resp.TaxableGain.String())
+}
+
+// TestGetTaxReport_CountryNotSupported checks that GetReport returns a validation error when there is no rule for the tax country of the portfolio
+func TestGetTaxReport_CountryNotSupported(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-a", TaxCountryID: "xx"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{}, nil).Once()
+
+	service := &taxReportService{
+		config:                config.Config{},
+		portfolioRepository:   portfolioRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		rules:                 taxrules.Rules(),
+	}
+	expectedError := `tax country "xx" not supported, available countries: CZ, DE, US`
+
+	// Act
+	_, err := service.GetReport(context.Background(), "user-a", portfolioID, models.TaxReportReq{})
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetTaxReport_OtherUser checks that GetReport returns an unauthorized error when the portfolio belongs to another user
+func TestGetTaxReport_OtherUser(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-a", TaxCountryID: "CZ"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{}, nil).Once()
+
+	service := &taxReportService{
+		config:                config.Config{},
+		portfolioRepository:   portfolioRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		rules:                 taxrules.Rules(),
+	}
+
+	// Act
+	_, err := service.GetReport(context.Background(), "user-b", portfolioID, models.TaxReportReq{})
+
+	// Assert
+	assert.Equal(t, wrappers.NewUnauthorizedErr(fmt.Errorf("portfolio %s belongs to another user", portfolioID)), err)
+}
+
+// TestGetDividendTaxReport_Ok checks that GetDividends withholds the tax of the issuer countries and limits the foreign tax credit to the treaty rates
+func TestGetDividendTaxReport_Ok(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+	bought := time.Date(2024, time.January, 10, 0, 0, 0, 0, time.UTC)
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-a", TaxCountryID: "CZ"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{
+		&entities.Transaction{TransactionID: "1", StockID: "stock-us", Quantity: decimal.NewFromInt(10), TransactionPrice: entities.NewMoney(decimal.NewFromInt(100), "USD"), TransactionDate: bought},
+		&entities.Transaction{TransactionID: "2", StockID: "stock-fr", Quantity: decimal.NewFromInt(20), TransactionPrice: entities.NewMoney(decimal.NewFromInt(50), "EUR"), TransactionDate: bought},
+		&entities.Transaction{TransactionID: "3", StockID: "stock-cz", Quantity: decimal.NewFromInt(100), TransactionPrice: entities.NewMoney(decimal.NewFromInt(40), "CZK"), TransactionDate: bought},
+		&entities.Transaction{TransactionID: "4", StockID: "stock-cz", Quantity: decimal.NewFromInt(-50), TransactionPrice: entities.NewMoney(decimal.NewFromInt(45), "CZK"), TransactionDate: time.Date(2024, time.June, 5, 0, 0, 0, 0, time.UTC)},
+	}, nil).Once()
+
+	dividendRepositoryMock := mocks.NewDividendRepository(t)
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-us"}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Dividend{DividendID: "us-2023", StockID: "stock-us", DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.5"), "USD"), ExDate: time.Date(2023, time.November, 9, 0, 0, 0, 0, time.UTC), PayDate: time.Date(2023, time.November, 15, 0, 0, 0, 0, time.UTC)},
+		&entities.Dividend{DividendID: "us-before-buy", StockID: "stock-us", DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.5"), "USD"), ExDate: time.Date(2024, time.January, 5, 0, 0, 0, 0, time.UTC), PayDate: time.Date(2024, time.January, 20, 0, 0, 0, 0, time.UTC)},
+		&entities.Dividend{DividendID: "us-feb", StockID: "stock-us", DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.5"), "USD"), ExDate: time.Date(2024, time.February, 9, 0, 0, 0, 0, time.UTC), PayDate: time.Date(2024, time.February, 15, 0, 0, 0, 0, time.UTC)},
+	}, nil).Once()
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-fr"}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Dividend{DividendID: "fr-may", StockID: "stock-fr", DividendPerShare: entities.NewMoney(decimal.NewFromInt(1), "EUR"), ExDate: time.Date(2024, time.May, 2, 0, 0, 0, 0, time.UTC), PayDate: time.Date(2024, time.May, 6, 0, 0, 0, 0, time.UTC)},
+	}, nil).Once()
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-cz"}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Dividend{DividendID: "cz-jun", StockID: "stock-cz", DividendPerShare: entities.NewMoney(decimal.NewFromInt(2), "CZK"), ExDate: time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC), PayDate: time.Date(2024, time.June, 20, 0, 0, 0, 0, time.UTC)},
+	}, nil).Once()
+
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-us").Return(&entities.Stock{StockID: "stock-us", Country: "US"}, nil).Once()
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-fr").Return(&entities.Stock{StockID: "stock-fr", Country: "FR"}, nil).Once()
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-cz").Return(&entities.Stock{StockID: "stock-cz", Country: "CZ"}, nil).Once()
+
+	rates := map[string]decimal.Decimal{"USD": decimal.NewFromInt(20), "EUR": decimal.NewFromInt(25), "CZK": decimal.NewFromInt(1)}
+	converterMock := mocks.NewCurrencyConverter(t)
+	converterMock.On(testutils.FunctionName(t, ports.CurrencyConverter.Convert), context.Background(), mock.AnythingOfType("entities.Money"), "CZK", mock.AnythingOfType("time.Time")).Return(func(ctx context.Context, amount entities.Money, currency string, date time.Time) (entities.Money, error) {
+		return entities.NewMoney(amount.Amount.Mul(rates[amount.Currency]), currency), nil
+	}).Times(3)
+
+	withholdingRepositoryMock := mocks.NewDividendWithholdingRepository(t)
+	withholdingRepositoryMock.On(testutils.FunctionName(t, ports.DividendWithholdingRepository.GetByPortfolioID), context.Background(), portfolioID).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	cfg := config.Config{}
+	cfg.Withholding = config.Withholding{
+		Rates:          map[string]decimal.Decimal{"CZ": decimal.RequireFromString("0.15"), "FR": decimal.RequireFromString("0.128"), "US": decimal.RequireFromString("0.30")},
+		TreatyRates:    map[string]map[string]decimal.Decimal{"FR": {"CZ": decimal.RequireFromString("0.10")}, "US": {"CZ": decimal.RequireFromString("0.15")}},
+		ReliefAtSource: []string{"US"},
+	}
+	service := &taxReportService{
+		config:                cfg,
+		portfolioRepository:   portfolioRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		stockRepository:       stockRepositoryMock,
+		dividendRepository:    dividendRepositoryMock,
+		withholdingRepository: withholdingRepositoryMock,
+		converter:             converterMock,
+		rules:                 taxrules.Rules(),
+	}
+
+	// Act
+	resp, err := service.GetDividends(context.Background(), "user-a", portfolioID, models.TaxReportReq{Year: 2024})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "CZ", resp.Country)
+	assert.Equal(t, "CZK", resp.Currency)
+	assert.Len(t, resp.Payments, 3)
+	assert.Equal(t, []string{"us-feb", "fr-may", "cz-jun"}, []string{resp.Payments[0].DividendID, resp.Payments[1].DividendID, resp.Payments[2].DividendID})
+	assert.Equal(t, "100", resp.Payments[2].Shares.String())
+	assert.Equal(t, "800 CZK", resp.Gross.String())
+	assert.Equal(t, "109 CZK", resp.Withholding.String())
+	assert.Equal(t, "691 CZK", resp.Net.String())
+	assert.Equal(t, "65 CZK", resp.ForeignTaxCredit.String())
+	assert.Equal(t, "14 CZK", resp.Reclaimable.String())
+
+	assert.Len(t, resp.Countries, 3)
+	assert.Equal(t, "CZ", resp.Countries[0].Country)
+	assert.Equal(t, "0 CZK", resp.Countries[0].ForeignTaxCredit.String())
+	assert.Equal(t, "FR", resp.Countries[1].Country)
+	assert.Equal(t, "64 CZK", resp.Countries[1].Withholding.String())
+	assert.Equal(t, "50 CZK", resp.Countries[1].ForeignTaxCredit.String())
+	assert.Equal(t, "14 CZK", resp.Countries[1].Reclaimable.String())
+	assert.Equal(t, "US", resp.Countries[2].Country)
+	assert.Equal(t, "15 CZK", resp.Countries[2].Withholding.String())
+	assert.Equal(t, "15 CZK", resp.Countries[2].ForeignTaxCredit.String())
+}
+
+// TestGetDividendTaxReport_RecordedWithholding checks that GetDividends reports the tax recorded as withheld from a payment instead of
+// the one of the issuer country, limiting its foreign tax credit to the treaty rate
+func TestGetDividendTaxReport_RecordedWithholding(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-a", TaxCountryID: "CZ"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{
+		&entities.Transaction{TransactionID: "1", StockID: "stock-us", Quantity: decimal.NewFromInt(10), TransactionPrice: entities.NewMoney(decimal.NewFromInt(100), "USD"), TransactionDate: time.Date(2024, time.January, 10, 0, 0, 0, 0, time.UTC)},
+	}, nil).Once()
+
+	dividendRepositoryMock := mocks.NewDividendRepository(t)
+	dividendRepositoryMock.On(testutils.FunctionName(t, ports.DividendRepository.Get), context.Background(), map[string]interface{}{"stockid": "stock-us"}, (*int)(nil), (*int)(nil)).Return([]interface{}{
+		&entities.Dividend{DividendID: "us-feb", StockID: "stock-us", DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.5"), "USD"), ExDate: time.Date(2024, time.February, 9, 0, 0, 0, 0, time.UTC), PayDate: time.Date(2024, time.February, 15, 0, 0, 0, 0, time.UTC)},
+	}, nil).Once()
+
+	withholdingRepositoryMock := mocks.NewDividendWithholdingRepository(t)
+	withholdingRepositoryMock.On(testutils.FunctionName(t, ports.DividendWithholdingRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{
+		&entities.DividendWithholding{PortfolioID: portfolioID, DividendID: "us-feb", Amount: entities.NewMoney(decimal.RequireFromString("1.5"), "USD")},
+	}, nil).Once()
+
+	stockRepositoryMock := mocks.NewStockRepository(t)
+	stockRepositoryMock.On(testutils.FunctionName(t, ports.StockRepository.GetByID), context.Background(), "stock-us").Return(&entities.Stock{StockID: "stock-us", Country: "US"}, nil).Once()
+
+	converterMock := mocks.NewCurrencyConverter(t)
+	converterMock.On(testutils.FunctionName(t, ports.CurrencyConverter.Convert), context.Background(), mock.AnythingOfType("entities.Money"), "CZK", mock.AnythingOfType("time.Time")).Return(func(ctx context.Context, amount entities.Money, currency string, date time.Time) (entities.Money, error) {
+		return entities.NewMoney(amount.Amount.Mul(decimal.NewFromInt(20)), currency), nil
+	}).Times(2)
+
+	cfg := config.Config{}
+	cfg.Withholding = config.Withholding{
+		Rates:          map[string]decimal.Decimal{"US": decimal.RequireFromString("0.15")},
+		TreatyRates:    map[string]map[string]decimal.Decimal{"US": {"CZ": decimal.RequireFromString("0.15")}},
+		ReliefAtSource: []string{"US"},
+	}
+	service := &taxReportService{
+		config:                cfg,
+		portfolioRepository:   portfolioRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		stockRepository:       stockRepositoryMock,
+		dividendRepository:    dividendRepositoryMock,
+		withholdingRepository: withholdingRepositoryMock,
+		converter:             converterMock,
+		rules:                 taxrules.Rules(),
+	}
+
+	// Act
+	resp, err := service.GetDividends(context.Background(), "user-a", portfolioID, models.TaxReportReq{Year: 2024})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp.Payments, 1)
+	assert.True(t, resp.Payments[0].WithholdingRecorded)
+	assert.Equal(t, "0.3", resp.Payments[0].WithholdingRate.String())
+	assert.Equal(t, "100 CZK", resp.Gross.String())
+	assert.Equal(t, "30 CZK", resp.Withholding.String())
+	assert.Equal(t, "70 CZK", resp.Net.String())
+	assert.Equal(t, "15 CZK", resp.ForeignTaxCredit.String())
+	assert.Equal(t, "15 CZK", resp.Reclaimable.String())
+}
+
+// TestGetDividendTaxReport_OtherUser checks that GetDividends returns an unauthorized error when the portfolio belongs to another user
+func TestGetDividendTaxReport_OtherUser(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-a", TaxCountryID: "CZ"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{}, nil).Once()
+
+	service := &taxReportService{
+		config:                config.Config{},
+		portfolioRepository:   portfolioRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		rules:                 taxrules.Rules(),
+	}
+
+	// Act
+	_, err := service.GetDividends(context.Background(), "user-b", portfolioID, models.TaxReportReq{Year: 2024})
+
+	// Assert
+	assert.Equal(t, wrappers.NewUnauthorizedErr(fmt.Errorf("portfolio %s belongs to another user", portfolioID)), err)
+}
+
+// TestWithholdingRates_Ok checks that withholdingRates applies the treaty rate at source only for the countries granting relief at source
+func TestWithholdingRates_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Withholding{
+		Rates:          map[string]decimal.Decimal{"CH": decimal.RequireFromString("0.35"), "US": decimal.RequireFromString("0.30")},
+		TreatyRates:    map[string]map[string]decimal.Decimal{"CH": {"DE": decimal.RequireFromString("0.15")}, "US": {"DE": decimal.RequireFromString("0.15")}},
+		ReliefAtSource: []string{"us"},
+	}
+
+	cases := []struct {
+		issuer, residence string
+		withheld, treaty  string
+	}{
+		{"US", "DE", "0.15", "0.15"},
+		{"CH", "DE", "0.35", "0.15"},
+		{"US", "CZ", "0.3", "0.3"},
+		{"XX", "DE", "0", "0"},
+	}
+
+	for _, c := range cases {
+		// Act
+		withheld, treaty := withholdingRates(cfg, c.issuer, c.residence)
+
+		// Assert
+		assert.Equal(t, c.withheld, withheld.String(), c.issuer+"-"+c.residence)
+		assert.Equal(t, c.treaty, treaty.String(), c.issuer+"-"+c.residence)
+	}
+}
+
+// TestFinancialYearOf_Ok checks that financialYearOf returns the year the financial year in progress started in
+func TestFinancialYearOf_Ok(t *testing.T) {
+	// Arrange
+	start := time.Date(2020, time.April, 6, 0, 0, 0, 0, time.UTC)
+
+	// Act & Assert
+	assert.Equal(t, 2023, financialYearOf(start, time.Date(2024, time.April, 5, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 2024, financialYearOf(start, time.Date(2024, time.April, 6, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 2024, financialYearOf(time.Time{}, time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// transactionService adapter of a transaction service
+type transactionService struct {
+	config              config.Config
+	repository          ports.TransactionRepository
+	portfolioRepository ports.PortfolioRepository
+	holdingRepository   ports.HoldingRepository
+	lotService          ports.LotService
+}
+
+// NewTransactionService creates a new transaction service. The repository is the one storing the transactions as they were entered,
+// not adjusted by the corporate actions. The lot service refreshes the lot ledger of a portfolio whenever its transactions change.
+func NewTransactionService(cfg config.Config, repo ports.TransactionRepository, portfolioRepo ports.PortfolioRepository, holdingRepo ports.HoldingRepository, lotService ports.LotService) ports.TransactionService {
+	return &transactionService{
+		config:              cfg,
+		repository:          repo,
+		portfolioRepository: portfolioRepo,
+		holdingRepository:   holdingRepo,
+		lotService:          lotService,
+	}
+}
+
+// Create creates a transaction in a holding of a portfolio of the user. The quantity of sells and transfers out is stored negative and
+// the one of buys and transfers in positive, whatever its sign in the request.
+func (s *transactionService) Create(ctx context.Context, userID, portfolioID string, transaction models.CreateTransactionReq) (resp models.CreationResp, err error) {
+	if err = transaction.Validate(); err != nil {
+		return
+	}
+	if _, err = userPortfolio(ctx, s.portfolioRepository, userID, portfolioID); err != nil {
+		return
+	}
+	if err = s.checkHolding(ctx, portfolioID, transaction.HoldingID); err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	insertedID, err := s.repository.Create(ctx, entities.Transaction{
+		HoldingID:        transaction.HoldingID,
+		StockID:          transaction.StockID,
+		CryptocurrencyID: transaction.CryptocurrencyID,
+		TransactionType:  transaction.TransactionType,
+		Quantity:         signedQuantity(transaction.TransactionType, transaction.Quantity),
+		TransactionPrice: transaction.TransactionPrice,
+		TransactionDate:  transaction.TransactionDate,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	})
+	if err != nil {
+		return
+	}
+	s.refreshLots(ctx, portfolioID)
+
+	resp = models.CreationResp{
+		InsertedID: insertedID,
+	}
+	return
+}
+
+// GetAll returns the transactions of a portfolio of the user
+func (s *transactionService) GetAll(ctx context.Context, userID, portfolioID string) (resp []models.TransactionResp, err error) {
+	resp = []models.TransactionResp{}
+	if _, err = userPortfolio(ctx, s.portfolioRepository, userID, portfolioID); err != nil {
+		return
+	}
+	result, err := s.repository.GetByPortfolioID(ctx, portfolioID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	for _, v := range result {
+		resp = append(resp, transactionResp(*(v.(*entities.Transaction))))
+	}
+	return
+}
+
+// GetByID returns a transaction of a portfolio of the user
+func (s *transactionService) GetByID(ctx context.Context, userID, portfolioID, ID string) (resp models.TransactionResp, err error) {
+	if _, err = userPortfolio(ctx, s.portfolioRepository, userID, portfolioID); err != nil {
+		return
+	}
+	return s.get(ctx, portfolioID, ID)
+}
+
+// get returns a transaction of a portfolio
+func (s *transactionService) get(ctx context.Context, portfolioID, ID string) (resp models.TransactionResp, err error) {
+	result, err := s.repository.GetByID(ctx, ID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", ID))
+		}
+		return
+	}
+
+	transaction := *(result.(*entities.Transaction))
+	holding, err := s.holdingRepository.GetByID(ctx, transaction.HoldingID)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return
+	}
+	if err != nil || holding.(*entities.Holding).PortfolioID != portfolioID {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", ID))
+		return
+	}
+
+	resp = transactionResp(transaction)
+	return
+}
+
+// Update replaces a transaction of a portfolio of the user, which may be moved to another holding of the portfolio
+func (s *transactionService) Update(ctx context.Context, userID, portfolioID, ID string, transaction models.UpdateTransactionReq) (err error) {
+	dbTransaction, err := s.GetByID(ctx, userID, portfolioID, ID)
+	if err != nil {
+		return
+	}
+	if err = transaction.Validate(); err != nil {
+		return
+	}
+	if err = s.checkHolding(ctx, portfolioID, transaction.HoldingID); err != nil {
+		return
+	}
+
+	err = s.repository.Update(ctx, ID, entities.Transaction{
+		HoldingID:        transaction.HoldingID,
+		StockID:          transaction.StockID,
+		CryptocurrencyID: transaction.CryptocurrencyID,
+		TransactionType:  transaction.TransactionType,
+		Quantity:         signedQuantity(transaction.TransactionType, transaction.Quantity),
+		TransactionPrice: transaction.TransactionPrice,
+		TransactionDate:  transaction.TransactionDate,
+		Extid:            dbTransaction.Extid,
+		ImportBatchID:    dbTransaction.ImportBatchID,
+		CreatedAt:        dbTransaction.CreatedAt,
+		UpdatedAt:        time.Now().UTC(),
+	})
+	if err != nil {
+		return
+	}
+
+	s.refreshLots(ctx, portfolioID)
+	return
+}
+
+// Delete deletes a transaction of a portfolio of the user
+func (s *transactionService) Delete(ctx context.Context, userID, portfolioID, ID string) (err error) {
+	if _, err = s.GetByID(ctx, userID, portfolioID, ID); err != nil {
+		return
+	}
+
+	err = s.repository.Delete(ctx, ID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", ID))
+		}
+		return
+	}
+
+	s.refreshLots(ctx, portfolioID)
+	return
+}
+
+// GetCashBalances returns the cash balance of a portfolio of the user in each currency its transactions moved cash in, sorted by currency. The
+// balance adds up the deposits, sales, dividends and interest and takes away the withdrawals, purchases and fees, so it is negative
+// when the purchases were paid with cash not deposited in the portfolio. Transfers and splits move no cash.
+func (s *transactionService) GetCashBalances(ctx context.Context, userID, portfolioID string) (resp models.CashBalancesResp, err error) {
+	portfolio, transactions, err := loadPortfolioTransactions(ctx, s.portfolioRepository, s.repository, portfolioID)
+	if err != nil {
+		return
+	}
+	if err = checkOwner(portfolio, userID); err != nil {
+		return
+	}
+
+	resp = models.CashBalancesResp{PortfolioID: portfolio.PortfolioID, Balances: cashBalances(transactions)}
+	return
+}
+
+// refreshLots refreshes the lot ledger of a portfolio after its transactions changed. The change is already stored, so a failure is
+// logged rather than returned, and the ledger is refreshed again by the next change or rebuild.
+func (s *transactionService) refreshLots(ctx context.Context, portfolioID string) {
+	if err := s.lotService.Refresh(ctx, portfolioID); err != nil {
+		log.Printf("cannot refresh the lot ledger of portfolio %s, error: %s", portfolioID, err)
+	}
+}
+
+// checkHolding checks that the holding of a transaction belongs to the portfolio
+func (s *transactionService) checkHolding(ctx context.Context, portfolioID, holdingID string) error {
+	if holdingID == "" {
+		return wrappers.NewValidationErr(fmt.Errorf("holding id cannot be empty"))
+	}
+	holding, err := s.holdingRepository.GetByID(ctx, holdingID)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return err
+	}
+	if err != nil || holding.(*entities.Holding).PortfolioID != portfolioID {
+		return wrappers.NewValidationErr(fmt.Errorf("holding %s not found in portfolio %s", holdingID, portfolioID))
+	}
+	return nil
+}
+
+// signedQuantity returns the quantity of a transaction with the sign of its type, negative for sells and transfers out and positive
+// for buys and transfers in
+func signedQuantity(transactionType string, quantity decimal.Decimal) decimal.Decimal {
+	switch transactionType {
+	case entities.TransactionTypeSell, entities.TransactionTypeTransferOut:
+		return quantity.Abs().Neg()
+	case entities.TransactionTypeBuy, entities.TransactionTypeTransferIn:
+		return quantity.Abs()
+	}
+	return quantity
+}
+
+// transactionResp returns the response of a transaction, with the type of the transactions stored before they had one
+func transactionResp(t entities.Transaction) models.TransactionResp {
+	return models.TransactionResp{
+		TransactionID:    t.TransactionID,
+		HoldingID:        t.HoldingID,
+		StockID:          t.StockID,
+		CryptocurrencyID: t.CryptocurrencyID,
+		TransactionType:  t.Type(),
+		Quantity:         t.Quantity,
+		TransactionPrice: t.TransactionPrice,
+		TransactionDate:  t.TransactionDate,
+		Extid:            t.Extid,
+		ImportBatchID:    t.ImportBatchID,
+		CreatedAt:        t.CreatedAt,
+		UpdatedAt:        t.UpdatedAt,
+	}
+}
+
+// cashBalances adds up the cash moved by transactions into a balance per currency, sorted by currency
+func cashBalances(transactions []entities.Transaction) []models.CashBalanceResp {
+	balances := map[string]*models.CashBalanceResp{}
+	for _, t := range transactions {
+		flow := t.CashFlow()
+		if flow.IsZero() {
+			continue
+		}
+
+		currency := t.TransactionPrice.Currency
+		b, ok := balances[currency]
+		if !ok {
+			zero := entities.NewMoney(decimal.Zero, currency)
+			b = &models.CashBalanceResp{Currency: currency, Balance: zero, Deposits: zero, Withdrawals: zero, Bought: zero, Sold: zero, Dividends: zero, Interest: zero, Fees: zero}
+			balances[currency] = b
+		}
+
+		// the balances are kept per currency, so their amounts can be added up directly
+		b.Balance.Amount = b.Balance.Amount.Add(flow.Amount)
+		amount := flow.Amount.Abs()
+		switch t.Type() {
+		case entities.TransactionTypeBuy:
+			b.Bought.Amount = b.Bought.Amount.Add(amount)
+		case entities.TransactionTypeSell:
+			b.Sold.Amount = b.Sold.Amount.Add(amount)
+		case entities.TransactionTypeDividend:
+			b.Dividends.Amount = b.Dividends.Amount.Add(amount)
+		case entities.TransactionTypeFee:
+			b.Fees.Amount = b.Fees.Amount.Add(amount)
+		case entities.TransactionTypeDeposit:
+			b.Deposits.Amount = b.Deposits.Amount.Add(amount)
+		case entities.TransactionTypeWithdrawal:
+			b.Withdrawals.Amount = b.Withdrawals.Amount.Add(amount)
+		case entities.TransactionTypeInterest:
+			b.Interest.Amount = b.Interest.Amount.Add(amount)
+		}
+	}
+
+	resp := []models.CashBalanceResp{}
+	for _, b := range balances {
+		resp = append(resp, *b)
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].Currency < resp[j].Currency
+	})
+	return resp
+}
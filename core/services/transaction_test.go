@@ -0,0 +1,385 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestNewTransactionService_Ok checks that NewTransactionService creates a new transactionService struct
+func TestNewTransactionService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+
+	// Act
+	service := NewTransactionService(cfg, mocks.NewTransactionRepository(t), mocks.NewPortfolioRepository(t), mocks.NewHoldingRepository(t), mocks.NewLotService(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestCreateTransaction_Ok checks that Create stores the quantity of a sell negative whatever its sign in the request and refreshes
+// the lot ledger of the portfolio
+func TestCreateTransaction_Ok(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.GetByID), context.Background(), "holding-a").Return(&entities.Holding{HoldingID: "holding-a", PortfolioID: "portfolio-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Create), context.Background(), mock.MatchedBy(func(transaction entities.Transaction) bool {
+		return transaction.HoldingID == "holding-a" && transaction.TransactionType == entities.TransactionTypeSell && transaction.Quantity.Equal(decimal.NewFromInt(-5)) && !transaction.CreatedAt.IsZero()
+	})).Return("transaction-a", nil).Once()
+
+	lotServiceMock := mocks.NewLotService(t)
+	lotServiceMock.On(testutils.FunctionName(t, ports.LotService.Refresh), context.Background(), "portfolio-a").Return(nil).Once()
+
+	service := &transactionService{
+		config:              config.Config{},
+		repository:          transactionRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+		holdingRepository:   holdingRepositoryMock,
+		lotService:          lotServiceMock,
+	}
+	req := models.CreateTransactionReq{HoldingID: "holding-a", StockID: "stock-a", TransactionType: entities.TransactionTypeSell, Quantity: decimal.NewFromInt(5), TransactionPrice: entities.NewMoney(decimal.NewFromInt(120), "USD"), TransactionDate: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)}
+
+	// Act
+	resp, err := service.Create(context.Background(), "user-a", "portfolio-a", req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "transaction-a", resp.InsertedID)
+}
+
+// TestCreateTransaction_RefreshError checks that Create returns the ID of the stored transaction when refreshing the lot ledger fails,
+// so the client does not create it again
+func TestCreateTransaction_RefreshError(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.GetByID), context.Background(), "holding-a").Return(&entities.Holding{HoldingID: "holding-a", PortfolioID: "portfolio-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Create), context.Background(), mock.AnythingOfType("entities.Transaction")).Return("transaction-a", nil).Once()
+
+	lotServiceMock := mocks.NewLotService(t)
+	lotServiceMock.On(testutils.FunctionName(t, ports.LotService.Refresh), context.Background(), "portfolio-a").Return(assert.AnError).Once()
+
+	service := &transactionService{
+		config:              config.Config{},
+		repository:          transactionRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+		holdingRepository:   holdingRepositoryMock,
+		lotService:          lotServiceMock,
+	}
+	req := models.CreateTransactionReq{HoldingID: "holding-a", TransactionType: entities.TransactionTypeDeposit, TransactionPrice: entities.NewMoney(decimal.NewFromInt(1000), "USD"), TransactionDate: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)}
+
+	// Act
+	resp, err := service.Create(context.Background(), "user-a", "portfolio-a", req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "transaction-a", resp.InsertedID)
+}
+
+// TestDeleteTransaction_RefreshError checks that Delete succeeds when the transaction is deleted but refreshing the lot ledger fails
+func TestDeleteTransaction_RefreshError(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByID), context.Background(), "transaction-a").Return(&entities.Transaction{TransactionID: "transaction-a", HoldingID: "holding-a"}, nil).Once()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Delete), context.Background(), "transaction-a").Return(nil).Once()
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.GetByID), context.Background(), "holding-a").Return(&entities.Holding{HoldingID: "holding-a", PortfolioID: "portfolio-a"}, nil).Once()
+
+	lotServiceMock := mocks.NewLotService(t)
+	lotServiceMock.On(testutils.FunctionName(t, ports.LotService.Refresh), context.Background(), "portfolio-a").Return(assert.AnError).Once()
+
+	service := &transactionService{
+		config:              config.Config{},
+		repository:          transactionRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+		holdingRepository:   holdingRepositoryMock,
+		lotService:          lotServiceMock,
+	}
+
+	// Act
+	err := service.Delete(context.Background(), "user-a", "portfolio-a", "transaction-a")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestCreateTransaction_PortfolioNotFound checks that Create returns a non existent error when the portfolio does not exist
+func TestCreateTransaction_PortfolioNotFound(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &transactionService{
+		config:              config.Config{},
+		portfolioRepository: portfolioRepositoryMock,
+	}
+	req := models.CreateTransactionReq{HoldingID: "holding-a", TransactionType: entities.TransactionTypeDeposit, TransactionPrice: entities.NewMoney(decimal.NewFromInt(1000), "USD"), TransactionDate: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)}
+	expectedError := "ID portfolio-a not found"
+
+	// Act
+	_, err := service.Create(context.Background(), "user-a", "portfolio-a", req)
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestCreateTransaction_OtherPortfolioHolding checks that Create returns a validation error when the holding belongs to another portfolio
+func TestCreateTransaction_OtherPortfolioHolding(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.GetByID), context.Background(), "holding-b").Return(&entities.Holding{HoldingID: "holding-b", PortfolioID: "portfolio-b"}, nil).Once()
+
+	service := &transactionService{
+		config:              config.Config{},
+		portfolioRepository: portfolioRepositoryMock,
+		holdingRepository:   holdingRepositoryMock,
+	}
+	req := models.CreateTransactionReq{HoldingID: "holding-b", TransactionType: entities.TransactionTypeFee, TransactionPrice: entities.NewMoney(decimal.NewFromInt(5), "USD"), TransactionDate: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)}
+	expectedError := "holding holding-b not found in portfolio portfolio-a"
+
+	// Act
+	_, err := service.Create(context.Background(), "user-a", "portfolio-a", req)
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestCreateTransaction_OtherUser checks that Create returns an unauthorized error and stores nothing when the portfolio belongs to
+// another user
+func TestCreateTransaction_OtherUser(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-b"}, nil).Once()
+
+	service := &transactionService{
+		config:              config.Config{},
+		repository:          mocks.NewTransactionRepository(t),
+		portfolioRepository: portfolioRepositoryMock,
+	}
+	req := models.CreateTransactionReq{HoldingID: "holding-a", TransactionType: entities.TransactionTypeDeposit, TransactionPrice: entities.NewMoney(decimal.NewFromInt(1000), "USD"), TransactionDate: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)}
+	expectedError := "portfolio portfolio-a belongs to another user"
+
+	// Act
+	_, err := service.Create(context.Background(), "user-a", "portfolio-a", req)
+
+	// Assert
+	assert.IsType(t, wrappers.UnauthorizedErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestCreateTransaction_InvalidRequest checks that Create returns a validation error when a cash movement has a quantity
+func TestCreateTransaction_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := &transactionService{config: config.Config{}}
+	req := models.CreateTransactionReq{HoldingID: "holding-a", TransactionType: entities.TransactionTypeDeposit, Quantity: decimal.NewFromInt(1), TransactionPrice: entities.NewMoney(decimal.NewFromInt(1000), "USD"), TransactionDate: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)}
+
+	// Act
+	_, err := service.Create(context.Background(), "user-a", "portfolio-a", req)
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
+
+// TestGetTransaction_OtherPortfolio checks that GetByID returns a non existent error when the transaction belongs to another portfolio
+func TestGetTransaction_OtherPortfolio(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByID), context.Background(), "transaction-a").Return(&entities.Transaction{TransactionID: "transaction-a", HoldingID: "holding-b"}, nil).Once()
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.GetByID), context.Background(), "holding-b").Return(&entities.Holding{HoldingID: "holding-b", PortfolioID: "portfolio-b"}, nil).Once()
+
+	service := &transactionService{
+		config:              config.Config{},
+		repository:          transactionRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+		holdingRepository:   holdingRepositoryMock,
+	}
+	expectedError := "ID transaction-a not found"
+
+	// Act
+	_, err := service.GetByID(context.Background(), "user-a", "portfolio-a", "transaction-a")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetTransactions_Ok checks that GetAll returns the transactions of the portfolio with the type of the ones stored without it
+func TestGetTransactions_Ok(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-a").Return([]interface{}{
+		&entities.Transaction{TransactionID: "transaction-a", HoldingID: "holding-a", StockID: "stock-a", Quantity: decimal.NewFromInt(-2)},
+		&entities.Transaction{TransactionID: "transaction-b", HoldingID: "holding-a", TransactionType: entities.TransactionTypeDeposit},
+	}, nil).Once()
+
+	service := &transactionService{
+		config:              config.Config{},
+		repository:          transactionRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetAll(context.Background(), "user-a", "portfolio-a")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp, 2)
+	assert.Equal(t, entities.TransactionTypeSell, resp[0].TransactionType)
+	assert.Equal(t, entities.TransactionTypeDeposit, resp[1].TransactionType)
+}
+
+// TestUpdateTransaction_Ok checks that Update replaces the transaction keeping its import references and creation date
+func TestUpdateTransaction_Ok(t *testing.T) {
+	// Arrange
+	createdAt := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByID), context.Background(), "transaction-a").Return(&entities.Transaction{TransactionID: "transaction-a", HoldingID: "holding-a", Extid: "ext-a", ImportBatchID: "batch-a", CreatedAt: createdAt}, nil).Once()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Update), context.Background(), "transaction-a", mock.MatchedBy(func(transaction entities.Transaction) bool {
+		return transaction.TransactionType == entities.TransactionTypeInterest && transaction.Extid == "ext-a" && transaction.ImportBatchID == "batch-a" && transaction.CreatedAt.Equal(createdAt) && !transaction.UpdatedAt.IsZero()
+	})).Return(nil).Once()
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.GetByID), context.Background(), "holding-a").Return(&entities.Holding{HoldingID: "holding-a", PortfolioID: "portfolio-a"}, nil).Twice()
+
+	lotServiceMock := mocks.NewLotService(t)
+	lotServiceMock.On(testutils.FunctionName(t, ports.LotService.Refresh), context.Background(), "portfolio-a").Return(nil).Once()
+
+	service := &transactionService{
+		config:              config.Config{},
+		repository:          transactionRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+		holdingRepository:   holdingRepositoryMock,
+		lotService:          lotServiceMock,
+	}
+	req := models.UpdateTransactionReq{HoldingID: "holding-a", TransactionType: entities.TransactionTypeInterest, TransactionPrice: entities.NewMoney(decimal.NewFromInt(3), "USD"), TransactionDate: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)}
+
+	// Act
+	err := service.Update(context.Background(), "user-a", "portfolio-a", "transaction-a", req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestDeleteTransaction_Ok checks that Delete deletes a transaction of the portfolio
+func TestDeleteTransaction_Ok(t *testing.T) {
+	// Arrange
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByID), context.Background(), "transaction-a").Return(&entities.Transaction{TransactionID: "transaction-a", HoldingID: "holding-a"}, nil).Once()
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.Delete), context.Background(), "transaction-a").Return(nil).Once()
+
+	holdingRepositoryMock := mocks.NewHoldingRepository(t)
+	holdingRepositoryMock.On(testutils.FunctionName(t, ports.HoldingRepository.GetByID), context.Background(), "holding-a").Return(&entities.Holding{HoldingID: "holding-a", PortfolioID: "portfolio-a"}, nil).Once()
+
+	lotServiceMock := mocks.NewLotService(t)
+	lotServiceMock.On(testutils.FunctionName(t, ports.LotService.Refresh), context.Background(), "portfolio-a").Return(nil).Once()
+
+	service := &transactionService{
+		config:              config.Config{},
+		repository:          transactionRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+		holdingRepository:   holdingRepositoryMock,
+		lotService:          lotServiceMock,
+	}
+
+	// Act
+	err := service.Delete(context.Background(), "user-a", "portfolio-a", "transaction-a")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestGetCashBalances_Ok checks that GetCashBalances adds up the cash moved by the transactions of the portfolio in each currency
+func TestGetCashBalances_Ok(t *testing.T) {
+	// Arrange
+	date := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), "portfolio-a").Return(&entities.Portfolio{PortfolioID: "portfolio-a", Userid: "user-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), "portfolio-a").Return([]interface{}{
+		&entities.Transaction{HoldingID: "holding-a", TransactionType: entities.TransactionTypeDeposit, TransactionPrice: entities.NewMoney(decimal.NewFromInt(1000), "USD"), TransactionDate: date},
+		&entities.Transaction{HoldingID: "holding-a", StockID: "stock-a", Quantity: decimal.NewFromInt(5), TransactionPrice: entities.NewMoney(decimal.NewFromInt(100), "USD"), TransactionDate: date},
+		&entities.Transaction{HoldingID: "holding-a", StockID: "stock-a", TransactionType: entities.TransactionTypeSell, Quantity: decimal.NewFromInt(-2), TransactionPrice: entities.NewMoney(decimal.NewFromInt(110), "USD"), TransactionDate: date},
+		&entities.Transaction{HoldingID: "holding-a", StockID: "stock-a", TransactionType: entities.TransactionTypeDividend, TransactionPrice: entities.NewMoney(decimal.NewFromInt(7), "USD"), TransactionDate: date},
+		&entities.Transaction{HoldingID: "holding-a", TransactionType: entities.TransactionTypeFee, TransactionPrice: entities.NewMoney(decimal.NewFromInt(2), "USD"), TransactionDate: date},
+		&entities.Transaction{HoldingID: "holding-a", StockID: "stock-a", TransactionType: entities.TransactionTypeSplit, Quantity: decimal.NewFromInt(3), TransactionDate: date},
+		&entities.Transaction{HoldingID: "holding-b", TransactionType: entities.TransactionTypeInterest, TransactionPrice: entities.NewMoney(decimal.NewFromInt(40), "CZK"), TransactionDate: date},
+		&entities.Transaction{HoldingID: "holding-b", TransactionType: entities.TransactionTypeWithdrawal, TransactionPrice: entities.NewMoney(decimal.NewFromInt(15), "CZK"), TransactionDate: date},
+	}, nil).Once()
+
+	service := &transactionService{
+		config:              config.Config{},
+		repository:          transactionRepositoryMock,
+		portfolioRepository: portfolioRepositoryMock,
+	}
+	czk := func(amount int64) entities.Money { return entities.NewMoney(decimal.NewFromInt(amount), "CZK") }
+	usd := func(amount int64) entities.Money { return entities.NewMoney(decimal.NewFromInt(amount), "USD") }
+
+	// Act
+	resp, err := service.GetCashBalances(context.Background(), "user-a", "portfolio-a")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "portfolio-a", resp.PortfolioID)
+	if assert.Len(t, resp.Balances, 2) {
+		assert.Equal(t, "CZK", resp.Balances[0].Currency)
+		assert.True(t, czk(25).Amount.Equal(resp.Balances[0].Balance.Amount))
+		assert.True(t, czk(40).Amount.Equal(resp.Balances[0].Interest.Amount))
+		assert.True(t, czk(15).Amount.Equal(resp.Balances[0].Withdrawals.Amount))
+		assert.True(t, resp.Balances[0].Bought.Amount.IsZero())
+
+		assert.Equal(t, "USD", resp.Balances[1].Currency)
+		assert.True(t, usd(725).Amount.Equal(resp.Balances[1].Balance.Amount))
+		assert.True(t, usd(1000).Amount.Equal(resp.Balances[1].Deposits.Amount))
+		assert.True(t, usd(500).Amount.Equal(resp.Balances[1].Bought.Amount))
+		assert.True(t, usd(220).Amount.Equal(resp.Balances[1].Sold.Amount))
+		assert.True(t, usd(7).Amount.Equal(resp.Balances[1].Dividends.Amount))
+		assert.True(t, usd(2).Amount.Equal(resp.Balances[1].Fees.Amount))
+		assert.Equal(t, "USD", resp.Balances[1].Fees.Currency)
+	}
+}
@@ -205,6 +205,10 @@ func (s *userService) GetByID(ctx context.Context, ID string) (resp models.UserR
 
 // Update user
 func (s *userService) Update(ctx context.Context, ID string, user models.UpdateUserReq) (err error) {
+	if err = user.Validate(); err != nil {
+		return
+	}
+
 	dbUser, err := s.GetByID(ctx, ID)
 	if err != nil {
 		return
@@ -232,6 +236,9 @@ func (s *userService) Update(ctx context.Context, ID string, user models.UpdateU
 
 		dbUser.PasswordHash = *user.NewPassword
 	}
+	if user.BaseCurrency != nil {
+		dbUser.BaseCurrency = *user.BaseCurrency
+	}
 
 	dbUser.UserID = ""
 	dbUser.UpdatedAt = time.Now().UTC()
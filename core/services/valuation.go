@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/models"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// valuationService adapter of a valuation service
+type valuationService struct {
+	config                  config.Config
+	portfolioRepository     ports.PortfolioRepository
+	transactionRepository   ports.TransactionRepository
+	userRepository          ports.UserRepository
+	brokerAccountRepository ports.BrokerAccountRepository
+	priceSource             ports.PriceSource
+	converter               ports.CurrencyConverter
+}
+
+// NewValuationService creates a new valuation service
+func NewValuationService(cfg config.Config, portfolioRepo ports.PortfolioRepository, transactionRepo ports.TransactionRepository, userRepo ports.UserRepository, brokerAccountRepo ports.BrokerAccountRepository, priceSource ports.PriceSource, converter ports.CurrencyConverter) ports.ValuationService {
+	return &valuationService{
+		config:                  cfg,
+		portfolioRepository:     portfolioRepo,
+		transactionRepository:   transactionRepo,
+		userRepository:          userRepo,
+		brokerAccountRepository: brokerAccountRepo,
+		priceSource:             priceSource,
+		converter:               converter,
+	}
+}
+
+// GetPortfolioValuation folds the transactions of a portfolio into its open positions with the portfolio's
+// cost basis method and values them at the latest prices. When the portfolio has a base currency the totals are
+// reported in it, converting costs at the rates of the purchase dates and market values at the latest rates. The portfolio must
+// belong to the user.
+func (s *valuationService) GetPortfolioValuation(ctx context.Context, userID, portfolioID string) (resp models.ValuationResp, err error) {
+	portfolio, transactions, err := loadPortfolioTransactions(ctx, s.portfolioRepository, s.transactionRepository, portfolioID)
+	if err != nil {
+		return
+	}
+	if err = checkOwner(portfolio, userID); err != nil {
+		return
+	}
+
+	currency, err := baseCurrency(ctx, s.userRepository, portfolio)
+	if err != nil {
+		return
+	}
+
+	resp, err = s.valuation(ctx, transactions, costBasisMethod(portfolio), currency)
+	resp.PortfolioID = portfolioID
+	return
+}
+
+// GetBrokerAccountValuation values the open positions of the holdings attached to a broker account of a user at the latest prices,
+// with the totals in the base currency of the account. The lots are matched first in, first out, as the holdings of an account may
+// belong to portfolios with different cost basis methods. The cash the transactions of the holdings moved is reported per currency
+// and its value at the latest rates is added to the market value of the positions in the total value of the account.
+func (s *valuationService) GetBrokerAccountValuation(ctx context.Context, userID, brokerAccountID string) (resp models.BrokerAccountValuationResp, err error) {
+	result, err := s.brokerAccountRepository.GetByID(ctx, brokerAccountID)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return
+	}
+	if err != nil || result.(*entities.BrokerAccount).UserID != userID {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", brokerAccountID))
+		return
+	}
+	account := *result.(*entities.BrokerAccount)
+
+	var transactions []entities.Transaction
+	stored, err := s.transactionRepository.GetByBrokerAccountID(ctx, brokerAccountID)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return
+	}
+	for _, v := range stored {
+		transactions = append(transactions, *(v.(*entities.Transaction)))
+	}
+
+	valuation, err := s.valuation(ctx, transactions, entities.CostBasisFIFO, account.BaseCurrency)
+	if err != nil {
+		return
+	}
+
+	resp = models.BrokerAccountValuationResp{
+		BrokerAccountID:   account.BrokerAccountID,
+		BrokerID:          account.BrokerID,
+		AccountLabel:      account.AccountLabel,
+		CostBasisMethod:   valuation.CostBasisMethod,
+		BaseCurrency:      valuation.BaseCurrency,
+		Positions:         valuation.Positions,
+		CostBasis:         valuation.CostBasis,
+		MarketValue:       valuation.MarketValue,
+		UnrealizedGain:    valuation.UnrealizedGain,
+		UnrealizedGainPct: valuation.UnrealizedGainPct,
+		RealizedGain:      valuation.RealizedGain,
+		Cash:              cashBalances(transactions),
+		CashValue:         entities.NewMoney(decimal.Zero, account.BaseCurrency),
+		ValuedAt:          valuation.ValuedAt,
+	}
+	for _, b := range resp.Cash {
+		value, convertErr := s.converter.Convert(ctx, b.Balance, account.BaseCurrency, resp.ValuedAt)
+		if convertErr != nil {
+			err = convertErr
+			return
+		}
+		if resp.CashValue, err = resp.CashValue.Add(value); err != nil {
+			return
+		}
+	}
+	resp.TotalValue, err = resp.MarketValue.Add(resp.CashValue)
+	return
+}
+
+// valuation folds transactions into their open positions with a cost basis method and values them at the latest prices. When a
+// currency is given the totals are reported in it, converting costs at the rates of the purchase dates and market values at the
+// latest rates. Without one, positions in different currencies cannot be totalled and a validation error is returned.
+func (s *valuationService) valuation(ctx context.Context, transactions []entities.Transaction, method int, currency string) (resp models.ValuationResp, err error) {
+	resp = models.ValuationResp{
+		CostBasisMethod: method,
+		BaseCurrency:    currency,
+		Positions:       []models.PositionResp{},
+		ValuedAt:        time.Now().UTC(),
+	}
+
+	ledger, err := buildLedger(transactions, method)
+	if err != nil {
+		return
+	}
+	resp.RealizedGain, err = realizedGainIn(ctx, s.converter, ledger.gains, currency)
+	if err != nil {
+		return
+	}
+
+	var pricedCostBasis entities.Money
+	for _, p := range ledger.positions {
+		position := models.PositionResp{
+			InstrumentID:   p.instrumentID,
+			InstrumentType: p.instrumentType,
+			Quantity:       p.quantity,
+			AverageCost:    entities.NewMoney(p.averageCost(), p.currency),
+			CostBasis:      entities.NewMoney(p.costBasis, p.currency),
+			LastTradeDate:  p.lastTradeDate,
+		}
+
+		price, priceErr := s.priceSource.LatestPrice(ctx, p.instrumentID)
+		switch {
+		case priceErr == nil:
+			position.Priced = true
+			position.MarketPrice = price
+			position.MarketValue = price.Mul(p.quantity)
+			if position.UnrealizedGain, err = position.MarketValue.Sub(position.CostBasis); err != nil {
+				return
+			}
+			position.UnrealizedGainPct = percentage(position.UnrealizedGain.Amount, position.CostBasis.Amount)
+		case errors.Is(priceErr, wrappers.NonExistentErr):
+			// the position is reported without a market value until a price is available
+		default:
+			err = priceErr
+			return
+		}
+
+		costBasis, marketValue, unrealizedGain := position.CostBasis, position.MarketValue, position.UnrealizedGain
+		if currency != "" {
+			position.BaseCostBasis, err = s.baseCostBasis(ctx, p, ledger.lots, method, currency)
+			if err != nil {
+				return
+			}
+			if position.Priced {
+				position.BaseMarketValue, err = s.converter.Convert(ctx, position.MarketValue, currency, resp.ValuedAt)
+				if err != nil {
+					return
+				}
+				if position.BaseUnrealizedGain, err = position.BaseMarketValue.Sub(position.BaseCostBasis); err != nil {
+					return
+				}
+			}
+			costBasis, marketValue, unrealizedGain = position.BaseCostBasis, position.BaseMarketValue, position.BaseUnrealizedGain
+		}
+
+		resp.Positions = append(resp.Positions, position)
+		if resp.CostBasis, err = resp.CostBasis.Add(costBasis); err != nil {
+			return
+		}
+		if position.Priced {
+			if pricedCostBasis, err = pricedCostBasis.Add(costBasis); err != nil {
+				return
+			}
+			if resp.MarketValue, err = resp.MarketValue.Add(marketValue); err != nil {
+				return
+			}
+			if resp.UnrealizedGain, err = resp.UnrealizedGain.Add(unrealizedGain); err != nil {
+				return
+			}
+		}
+	}
+	resp.UnrealizedGainPct = percentage(resp.UnrealizedGain.Amount, pricedCostBasis.Amount)
+
+	return
+}
+
+// baseCostBasis returns the cost of the open lots of a position in the base currency, each converted at the rate of its purchase date
+func (s *valuationService) baseCostBasis(ctx context.Context, p position, lots []entities.Lot, method int, currency string) (entities.Money, error) {
+	total := entities.NewMoney(decimal.Zero, currency)
+	for _, l := range lots {
+		if l.InstrumentID != p.instrumentID || !l.RemainingQuantity.IsPositive() {
+			continue
+		}
+
+		cost := l.CostPerUnit.Mul(l.RemainingQuantity)
+		if method == entities.CostBasisAverage {
+			cost = entities.NewMoney(p.averageCost().Mul(l.RemainingQuantity), p.currency)
+		}
+
+		converted, err := s.converter.Convert(ctx, cost, currency, l.AcquiredAt)
+		if err != nil {
+			return entities.Money{}, err
+		}
+		if total, err = total.Add(converted); err != nil {
+			return entities.Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// realizedGainIn returns the sum of realized gains in a currency, converting proceeds at the rates of the sale dates and costs at the
+// rates of the purchase dates. An empty currency sums the gains in the currency they were realized in, and cannot sum gains realized
+// in different currencies.
+func realizedGainIn(ctx context.Context, converter ports.CurrencyConverter, gains []entities.RealizedGain, currency string) (entities.Money, error) {
+	if currency == "" {
+		return ledger{gains: gains}.realizedGain()
+	}
+
+	total := entities.NewMoney(decimal.Zero, currency)
+	for _, g := range gains {
+		proceeds, err := converter.Convert(ctx, g.Proceeds, currency, g.SoldAt)
+		if err != nil {
+			return entities.Money{}, err
+		}
+		cost, err := converter.Convert(ctx, g.CostBasis, currency, g.AcquiredAt)
+		if err != nil {
+			return entities.Money{}, err
+		}
+		gain, err := proceeds.Sub(cost)
+		if err == nil {
+			total, err = total.Add(gain)
+		}
+		if err != nil {
+			return entities.Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// costBasisMethod returns the cost basis method selected by the portfolio, falling back to FIFO when none is set
+func costBasisMethod(portfolio entities.Portfolio) int {
+	if !validCostBasisMethod(portfolio.PerformenceCalculation) {
+		return entities.CostBasisFIFO
+	}
+	return portfolio.PerformenceCalculation
+}
+
+// percentage returns part as a percentage of total, or zero when total is zero
+func percentage(part, total decimal.Decimal) float64 {
+	if total.IsZero() {
+		return 0
+	}
+	return part.Div(total).Mul(decimal.NewFromInt(100)).InexactFloat64()
+}
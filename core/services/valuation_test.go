@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestNewValuationService_Ok checks that NewValuationService creates a new valuationService struct
+func TestNewValuationService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	priceSourceMock := mocks.NewPriceSource(t)
+
+	// Act
+	service := NewValuationService(cfg, portfolioRepositoryMock, transactionRepositoryMock, mocks.NewUserRepository(t), mocks.NewBrokerAccountRepository(t), priceSourceMock, mocks.NewCurrencyConverter(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestGetPortfolioValuation_Ok checks that GetPortfolioValuation returns the valued open positions of the portfolio
+func TestGetPortfolioValuation_Ok(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+	tradeDate := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-a"}, nil).Once()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "user-a").Return(&entities.User{UserID: "user-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{
+		&entities.Transaction{TransactionID: "1", StockID: "stock-a", Quantity: decimal.RequireFromString("10"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("10"), "CZK"), TransactionDate: tradeDate},
+		&entities.Transaction{TransactionID: "2", StockID: "stock-b", Quantity: decimal.RequireFromString("2"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("50"), "CZK"), TransactionDate: tradeDate},
+	}, nil).Once()
+
+	priceSourceMock := mocks.NewPriceSource(t)
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.LatestPrice), context.Background(), "stock-a").Return(entities.NewMoney(decimal.NewFromInt(15), "CZK"), nil).Once()
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.LatestPrice), context.Background(), "stock-b").Return(entities.Money{}, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &valuationService{
+		config:                config.Config{},
+		portfolioRepository:   portfolioRepositoryMock,
+		userRepository:        userRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		priceSource:           priceSourceMock,
+	}
+
+	// Act
+	resp, err := service.GetPortfolioValuation(context.Background(), "user-a", portfolioID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, portfolioID, resp.PortfolioID)
+	assert.Len(t, resp.Positions, 2)
+	assert.Equal(t, "stock-a", resp.Positions[0].InstrumentID)
+	assert.Equal(t, entities.EntityNameStock, resp.Positions[0].InstrumentType)
+	assert.Equal(t, "10", resp.Positions[0].Quantity.String())
+	assert.Equal(t, "10 CZK", resp.Positions[0].AverageCost.String())
+	assert.Equal(t, "100 CZK", resp.Positions[0].CostBasis.String())
+	assert.Equal(t, "15 CZK", resp.Positions[0].MarketPrice.String())
+	assert.Equal(t, "150 CZK", resp.Positions[0].MarketValue.String())
+	assert.Equal(t, "50 CZK", resp.Positions[0].UnrealizedGain.String())
+	assert.Equal(t, 50.0, resp.Positions[0].UnrealizedGainPct)
+	assert.True(t, resp.Positions[0].Priced)
+	assert.Equal(t, tradeDate, resp.Positions[0].LastTradeDate)
+	assert.Equal(t, "stock-b", resp.Positions[1].InstrumentID)
+	assert.Equal(t, "2", resp.Positions[1].Quantity.String())
+	assert.Equal(t, "50 CZK", resp.Positions[1].AverageCost.String())
+	assert.Equal(t, "100 CZK", resp.Positions[1].CostBasis.String())
+	assert.True(t, resp.Positions[1].MarketValue.IsZero())
+	assert.False(t, resp.Positions[1].Priced)
+	assert.Equal(t, "200 CZK", resp.CostBasis.String())
+	assert.Equal(t, "150 CZK", resp.MarketValue.String())
+	assert.Equal(t, "50 CZK", resp.UnrealizedGain.String())
+	assert.Equal(t, 50.0, resp.UnrealizedGainPct)
+	assert.Equal(t, entities.CostBasisFIFO, resp.CostBasisMethod)
+	assert.True(t, resp.RealizedGain.IsZero())
+}
+
+// TestGetPortfolioValuation_BaseCurrency checks that GetPortfolioValuation converts costs at the purchase date and market values at the latest rate
+func TestGetPortfolioValuation_BaseCurrency(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+	tradeDate := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-a", BaseCurrency: "CZK"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{
+		&entities.Transaction{TransactionID: "1", StockID: "stock-a", Quantity: decimal.RequireFromString("10"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("10"), "EUR"), TransactionDate: tradeDate},
+	}, nil).Once()
+
+	priceSourceMock := mocks.NewPriceSource(t)
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.LatestPrice), context.Background(), "stock-a").Return(entities.NewMoney(decimal.NewFromInt(12), "EUR"), nil).Once()
+
+	converterMock := mocks.NewCurrencyConverter(t)
+	converterMock.On(testutils.FunctionName(t, ports.CurrencyConverter.Convert), context.Background(), mock.MatchedBy(func(m entities.Money) bool { return m.String() == "100 EUR" }), "CZK", tradeDate).Return(entities.NewMoney(decimal.NewFromInt(2400), "CZK"), nil).Once()
+	converterMock.On(testutils.FunctionName(t, ports.CurrencyConverter.Convert), context.Background(), mock.MatchedBy(func(m entities.Money) bool { return m.String() == "120 EUR" }), "CZK", mock.AnythingOfType("time.Time")).Return(entities.NewMoney(decimal.NewFromInt(3000), "CZK"), nil).Once()
+
+	service := &valuationService{
+		config:                config.Config{},
+		portfolioRepository:   portfolioRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		priceSource:           priceSourceMock,
+		converter:             converterMock,
+	}
+
+	// Act
+	resp, err := service.GetPortfolioValuation(context.Background(), "user-a", portfolioID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "CZK", resp.BaseCurrency)
+	assert.Len(t, resp.Positions, 1)
+	assert.Equal(t, "100 EUR", resp.Positions[0].CostBasis.String())
+	assert.Equal(t, "2400 CZK", resp.Positions[0].BaseCostBasis.String())
+	assert.Equal(t, "3000 CZK", resp.Positions[0].BaseMarketValue.String())
+	assert.Equal(t, "600 CZK", resp.Positions[0].BaseUnrealizedGain.String())
+	assert.Equal(t, "2400 CZK", resp.CostBasis.String())
+	assert.Equal(t, "3000 CZK", resp.MarketValue.String())
+	assert.Equal(t, "600 CZK", resp.UnrealizedGain.String())
+	assert.Equal(t, 25.0, resp.UnrealizedGainPct)
+}
+
+// TestGetPortfolioValuation_NoTransactions checks that GetPortfolioValuation returns an empty valuation when the portfolio has no transactions
+func TestGetPortfolioValuation_NoTransactions(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-a"}, nil).Once()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "user-a").Return(&entities.User{UserID: "user-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &valuationService{
+		config:                config.Config{},
+		portfolioRepository:   portfolioRepositoryMock,
+		userRepository:        userRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetPortfolioValuation(context.Background(), "user-a", portfolioID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Empty(t, resp.Positions)
+	assert.True(t, resp.MarketValue.IsZero())
+}
+
+// TestGetPortfolioValuation_MixedCurrencies checks that GetPortfolioValuation returns a validation error instead of totalling positions
+// in different currencies when the portfolio has no base currency
+func TestGetPortfolioValuation_MixedCurrencies(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+	tradeDate := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-a"}, nil).Once()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "user-a").Return(&entities.User{UserID: "user-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{
+		&entities.Transaction{TransactionID: "1", StockID: "stock-a", Quantity: decimal.RequireFromString("10"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("10"), "CZK"), TransactionDate: tradeDate},
+		&entities.Transaction{TransactionID: "2", StockID: "stock-b", Quantity: decimal.RequireFromString("2"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("50"), "USD"), TransactionDate: tradeDate},
+	}, nil).Once()
+
+	priceSourceMock := mocks.NewPriceSource(t)
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.LatestPrice), context.Background(), "stock-a").Return(entities.NewMoney(decimal.NewFromInt(15), "CZK"), nil).Once()
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.LatestPrice), context.Background(), "stock-b").Return(entities.NewMoney(decimal.NewFromInt(60), "USD"), nil).Once()
+
+	service := &valuationService{
+		config:                config.Config{},
+		portfolioRepository:   portfolioRepositoryMock,
+		userRepository:        userRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		priceSource:           priceSourceMock,
+	}
+	expectedError := "cannot add 100 USD to 100 CZK, amounts in different currencies must be converted to a common currency first"
+
+	// Act
+	_, err := service.GetPortfolioValuation(context.Background(), "user-a", portfolioID)
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetPortfolioValuation_PortfolioNotFound checks that GetPortfolioValuation returns an error when the portfolio does not exist
+func TestGetPortfolioValuation_PortfolioNotFound(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+	expectedError := fmt.Sprintf("ID %s not found", portfolioID)
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &valuationService{
+		config:              config.Config{},
+		portfolioRepository: portfolioRepositoryMock,
+	}
+
+	// Act
+	_, err := service.GetPortfolioValuation(context.Background(), "user-a", portfolioID)
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetPortfolioValuation_OtherUser checks that GetPortfolioValuation returns an unauthorized error when the portfolio belongs to
+// another user
+func TestGetPortfolioValuation_OtherUser(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return(nil, wrappers.NewNonExistentErr(sql.ErrNoRows)).Once()
+
+	service := &valuationService{
+		config:                config.Config{},
+		portfolioRepository:   portfolioRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+	}
+
+	// Act
+	_, err := service.GetPortfolioValuation(context.Background(), "user-b", portfolioID)
+
+	// Assert
+	assert.Equal(t, wrappers.NewUnauthorizedErr(fmt.Errorf("portfolio %s belongs to another user", portfolioID)), err)
+}
+
+// TestGetPortfolioValuation_PriceSourceError checks that GetPortfolioValuation returns an error when the price source fails
+func TestGetPortfolioValuation_PriceSourceError(t *testing.T) {
+	// Arrange
+	portfolioID := "portfolio-id"
+	expectedError := "price-source-error"
+
+	portfolioRepositoryMock := mocks.NewPortfolioRepository(t)
+	portfolioRepositoryMock.On(testutils.FunctionName(t, ports.PortfolioRepository.GetByID), context.Background(), portfolioID).Return(&entities.Portfolio{PortfolioID: portfolioID, Userid: "user-a"}, nil).Once()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "user-a").Return(&entities.User{UserID: "user-a"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByPortfolioID), context.Background(), portfolioID).Return([]interface{}{
+		&entities.Transaction{TransactionID: "1", StockID: "stock-a", Quantity: decimal.RequireFromString("10"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("10"), "CZK")},
+	}, nil).Once()
+
+	priceSourceMock := mocks.NewPriceSource(t)
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.LatestPrice), context.Background(), "stock-a").Return(entities.Money{}, fmt.Errorf(expectedError)).Once()
+
+	service := &valuationService{
+		config:                config.Config{},
+		portfolioRepository:   portfolioRepositoryMock,
+		userRepository:        userRepositoryMock,
+		transactionRepository: transactionRepositoryMock,
+		priceSource:           priceSourceMock,
+	}
+
+	// Act
+	_, err := service.GetPortfolioValuation(context.Background(), "user-a", portfolioID)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetBrokerAccountValuation_Ok checks that GetBrokerAccountValuation adds the value of the cash left by the transactions of the
+// account to the market value of its positions
+func TestGetBrokerAccountValuation_Ok(t *testing.T) {
+	// Arrange
+	accountID := "account-id"
+	tradeDate := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	brokerAccountRepositoryMock := mocks.NewBrokerAccountRepository(t)
+	brokerAccountRepositoryMock.On(testutils.FunctionName(t, ports.BrokerAccountRepository.GetByID), context.Background(), accountID).Return(&entities.BrokerAccount{BrokerAccountID: accountID, UserID: "user-a", BrokerID: "broker-a", AccountLabel: "ISA", BaseCurrency: "GBP"}, nil).Once()
+
+	transactionRepositoryMock := mocks.NewTransactionRepository(t)
+	transactionRepositoryMock.On(testutils.FunctionName(t, ports.TransactionRepository.GetByBrokerAccountID), context.Background(), accountID).Return([]interface{}{
+		&entities.Transaction{TransactionID: "1", TransactionType: entities.TransactionTypeDeposit, TransactionPrice: entities.NewMoney(decimal.RequireFromString("1000"), "GBP"), TransactionDate: tradeDate},
+		&entities.Transaction{TransactionID: "2", StockID: "stock-a", TransactionType: entities.TransactionTypeBuy, Quantity: decimal.RequireFromString("10"), TransactionPrice: entities.NewMoney(decimal.RequireFromString("50"), "GBP"), TransactionDate: tradeDate},
+	}, nil).Once()
+
+	priceSourceMock := mocks.NewPriceSource(t)
+	priceSourceMock.On(testutils.FunctionName(t, ports.PriceSource.LatestPrice), context.Background(), "stock-a").Return(entities.NewMoney(decimal.NewFromInt(60), "GBP"), nil).Once()
+
+	converterMock := mocks.NewCurrencyConverter(t)
+	converterMock.On(testutils.FunctionName(t, ports.CurrencyConverter.Convert), context.Background(), mock.MatchedBy(func(m entities.Money) bool { return m.String() == "500 GBP" }), "GBP", mock.AnythingOfType("time.Time")).Return(entities.NewMoney(decimal.NewFromInt(500), "GBP"), nil).Twice()
+	converterMock.On(testutils.FunctionName(t, ports.CurrencyConverter.Convert), context.Background(), mock.MatchedBy(func(m entities.Money) bool { return m.String() == "600 GBP" }), "GBP", mock.AnythingOfType("time.Time")).Return(entities.NewMoney(decimal.NewFromInt(600), "GBP"), nil).Once()
+
+	service := &valuationService{
+		config:                  config.Config{},
+		transactionRepository:   transactionRepositoryMock,
+		brokerAccountRepository: brokerAccountRepositoryMock,
+		priceSource:             priceSourceMock,
+		converter:               converterMock,
+	}
+
+	// Act
+	resp, err := service.GetBrokerAccountValuation(context.Background(), "user-a", accountID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, accountID, resp.BrokerAccountID)
+	assert.Equal(t, "broker-a", resp.BrokerID)
+	assert.Equal(t, "GBP", resp.BaseCurrency)
+	assert.Equal(t, entities.CostBasisFIFO, resp.CostBasisMethod)
+	assert.Len(t, resp.Positions, 1)
+	assert.Equal(t, "500 GBP", resp.CostBasis.String())
+	assert.Equal(t, "600 GBP", resp.MarketValue.String())
+	assert.Len(t, resp.Cash, 1)
+	assert.Equal(t, "500 GBP", resp.Cash[0].Balance.String())
+	assert.Equal(t, "500 GBP", resp.CashValue.String())
+	assert.Equal(t, "1100 GBP", resp.TotalValue.String())
+}
+
+// TestGetBrokerAccountValuation_OtherUser checks that GetBrokerAccountValuation returns a non existent error when the account belongs
+// to another user
+func TestGetBrokerAccountValuation_OtherUser(t *testing.T) {
+	// Arrange
+	accountID := "account-id"
+	expectedError := fmt.Sprintf("ID %s not found", accountID)
+
+	brokerAccountRepositoryMock := mocks.NewBrokerAccountRepository(t)
+	brokerAccountRepositoryMock.On(testutils.FunctionName(t, ports.BrokerAccountRepository.GetByID), context.Background(), accountID).Return(&entities.BrokerAccount{BrokerAccountID: accountID, UserID: "user-b"}, nil).Once()
+
+	service := &valuationService{
+		config:                  config.Config{},
+		brokerAccountRepository: brokerAccountRepositoryMock,
+	}
+
+	// Act
+	_, err := service.GetBrokerAccountValuation(context.Background(), "user-a", accountID)
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
@@ -0,0 +1,70 @@
+package taxrules
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+const (
+	// czechTimeTestYears is the holding period after which the sale of securities is exempt
+	czechTimeTestYears = 3
+)
+
+var (
+	// czechExemptProceeds is the total of the proceeds of a year up to which the sales not exempt by the time test are exempt
+	czechExemptProceeds = decimal.NewFromInt(100000)
+	// czechCryptoTimeTestSince is the first day of the sales of cryptocurrencies the time test applies to
+	czechCryptoTimeTestSince = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// czechRule adapter of the capital gains tax rule of the Czech Republic
+type czechRule struct{}
+
+// NewCzechRule creates the capital gains tax rule of the Czech Republic. Sales of lots held for more than 3 years are exempt by the
+// time test, which covers cryptocurrencies sold since 2025, and the rest are exempt when their proceeds in the year do not exceed 100,000 CZK.
+func NewCzechRule() ports.TaxRule {
+	return &czechRule{}
+}
+
+func (r *czechRule) Country() string {
+	return "CZ"
+}
+
+func (r *czechRule) Currency() string {
+	return "CZK"
+}
+
+// CostBasisMethod leaves the cost basis method to the portfolio
+func (r *czechRule) CostBasisMethod() int {
+	return 0
+}
+
+func (r *czechRule) Assess(sales []entities.TaxSale, from, to time.Time) entities.TaxAssessment {
+	assessed := make([]entities.TaxSale, len(sales))
+	timeTested := make([]bool, len(sales))
+	proceeds := decimal.Zero
+	for i, s := range sales {
+		timeTested[i] = heldLongerThan(s.AcquiredAt, s.SoldAt, czechTimeTestYears) &&
+			(s.InstrumentType != entities.EntityNameCryptoCurrency || !s.SoldAt.Before(czechCryptoTimeTestSince))
+		if !timeTested[i] {
+			proceeds = proceeds.Add(s.Proceeds.Amount)
+		}
+	}
+
+	for i, s := range sales {
+		switch {
+		case timeTested[i]:
+			assessed[i] = assess(s, entities.TaxTreatmentTimeTestExempt, fmt.Sprintf("held for more than %d years", czechTimeTestYears), false)
+		case !proceeds.GreaterThan(czechExemptProceeds):
+			assessed[i] = assess(s, entities.TaxTreatmentThresholdExempt, fmt.Sprintf("proceeds of the year not exceeding %s CZK", czechExemptProceeds), false)
+		default:
+			assessed[i] = assess(s, entities.TaxTreatmentTaxable, fmt.Sprintf("held for %d years or less and proceeds of the year exceeding %s CZK", czechTimeTestYears, czechExemptProceeds), true)
+		}
+	}
+
+	return entities.TaxAssessment{Sales: assessed, Allowance: entities.NewMoney(decimal.Zero, r.Currency())}
+}
@@ -0,0 +1,58 @@
+package taxrules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestCzechRuleAssess_TimeTest checks that Assess exempts the sales held for more than 3 years and taxes the rest when their proceeds exceed the threshold
+func TestCzechRuleAssess_TimeTest(t *testing.T) {
+	// Arrange
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)
+	sales := []entities.TaxSale{
+		sale(entities.EntityNameStock, time.Date(2020, time.May, 4, 0, 0, 0, 0, time.UTC), time.Date(2024, time.June, 3, 0, 0, 0, 0, time.UTC), 200000, 350000, "CZK"),
+		sale(entities.EntityNameStock, time.Date(2023, time.May, 4, 0, 0, 0, 0, time.UTC), time.Date(2024, time.June, 3, 0, 0, 0, 0, time.UTC), 80000, 120000, "CZK"),
+		sale(entities.EntityNameCryptoCurrency, time.Date(2019, time.May, 4, 0, 0, 0, 0, time.UTC), time.Date(2024, time.June, 3, 0, 0, 0, 0, time.UTC), 10000, 30000, "CZK"),
+	}
+
+	// Act
+	assessment := NewCzechRule().Assess(sales, from, to)
+
+	// Assert
+	assert.Len(t, assessment.Sales, 3)
+	assert.Equal(t, entities.TaxTreatmentTimeTestExempt, assessment.Sales[0].Treatment)
+	assert.True(t, assessment.Sales[0].TaxableGain.IsZero())
+	assert.Equal(t, entities.TaxTreatmentTaxable, assessment.Sales[1].Treatment)
+	assert.True(t, decimal.NewFromInt(40000).Equal(assessment.Sales[1].TaxableGain.Amount))
+	assert.Equal(t, entities.TaxTreatmentTaxable, assessment.Sales[2].Treatment, "the time test covers cryptocurrencies sold since 2025 only")
+	assert.True(t, assessment.Allowance.IsZero())
+}
+
+// TestCzechRuleAssess_Threshold checks that Assess exempts the sales not exempt by the time test when their proceeds do not exceed 100,000 CZK
+func TestCzechRuleAssess_Threshold(t *testing.T) {
+	// Arrange
+	from := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)
+	sales := []entities.TaxSale{
+		sale(entities.EntityNameStock, time.Date(2024, time.May, 4, 0, 0, 0, 0, time.UTC), time.Date(2025, time.June, 3, 0, 0, 0, 0, time.UTC), 40000, 60000, "CZK"),
+		sale(entities.EntityNameCryptoCurrency, time.Date(2024, time.May, 4, 0, 0, 0, 0, time.UTC), time.Date(2025, time.June, 3, 0, 0, 0, 0, time.UTC), 30000, 40000, "CZK"),
+		sale(entities.EntityNameCryptoCurrency, time.Date(2021, time.May, 4, 0, 0, 0, 0, time.UTC), time.Date(2025, time.June, 3, 0, 0, 0, 0, time.UTC), 100000, 900000, "CZK"),
+	}
+
+	// Act
+	assessment := NewCzechRule().Assess(sales, from, to)
+
+	// Assert
+	assert.Equal(t, entities.TaxTreatmentThresholdExempt, assessment.Sales[0].Treatment)
+	assert.Equal(t, entities.TaxTreatmentThresholdExempt, assessment.Sales[1].Treatment)
+	assert.Equal(t, entities.TaxTreatmentTimeTestExempt, assessment.Sales[2].Treatment)
+	for _, s := range assessment.Sales {
+		assert.True(t, s.TaxableGain.IsZero())
+		assert.NotEmpty(t, s.Rule)
+	}
+}
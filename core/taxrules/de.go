@@ -0,0 +1,85 @@
+package taxrules
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+const (
+	// germanPrivateSaleYears is the holding period after which the private sale of cryptocurrencies is exempt
+	germanPrivateSaleYears = 1
+)
+
+// germanRule adapter of the capital gains tax rule of Germany
+type germanRule struct{}
+
+// NewGermanRule creates the capital gains tax rule of Germany. Gains on stocks are capital income subject to the flat-rate withholding
+// tax, reduced by the saver's allowance, and lots are matched first in first out as the law requires. Cryptocurrencies are private sales,
+// exempt after a year and exempt altogether when the gains of the year stay below the exemption limit.
+func NewGermanRule() ports.TaxRule {
+	return &germanRule{}
+}
+
+func (r *germanRule) Country() string {
+	return "DE"
+}
+
+func (r *germanRule) Currency() string {
+	return "EUR"
+}
+
+// CostBasisMethod returns FIFO, which the law requires for securities held in custody
+func (r *germanRule) CostBasisMethod() int {
+	return entities.CostBasisFIFO
+}
+
+func (r *germanRule) Assess(sales []entities.TaxSale, from, to time.Time) entities.TaxAssessment {
+	allowanceLimit, exemptionLimit := germanLimits(from.Year())
+
+	privateSaleGains := decimal.Zero
+	for _, s := range sales {
+		if s.InstrumentType == entities.EntityNameCryptoCurrency && !heldLongerThan(s.AcquiredAt, s.SoldAt, germanPrivateSaleYears) {
+			privateSaleGains = privateSaleGains.Add(s.Gain.Amount)
+		}
+	}
+
+	assessed := make([]entities.TaxSale, len(sales))
+	capitalIncome := decimal.Zero
+	for i, s := range sales {
+		switch {
+		case s.InstrumentType != entities.EntityNameCryptoCurrency:
+			assessed[i] = assess(s, entities.TaxTreatmentTaxable, "capital income subject to the flat-rate withholding tax", true)
+			capitalIncome = capitalIncome.Add(s.Gain.Amount)
+		case heldLongerThan(s.AcquiredAt, s.SoldAt, germanPrivateSaleYears):
+			assessed[i] = assess(s, entities.TaxTreatmentHoldingPeriodExempt, fmt.Sprintf("private sale held for more than %d year", germanPrivateSaleYears), false)
+		case privateSaleGains.LessThan(exemptionLimit):
+			assessed[i] = assess(s, entities.TaxTreatmentThresholdExempt, fmt.Sprintf("private sale gains of the year below %s EUR", exemptionLimit), false)
+		default:
+			assessed[i] = assess(s, entities.TaxTreatmentPrivateSale, fmt.Sprintf("private sale held for %d year or less", germanPrivateSaleYears), true)
+		}
+	}
+
+	allowance := decimal.Zero
+	if capitalIncome.IsPositive() {
+		allowance = decimal.Min(capitalIncome, allowanceLimit)
+	}
+
+	return entities.TaxAssessment{Sales: assessed, Allowance: entities.NewMoney(allowance, r.Currency())}
+}
+
+// germanLimits returns the saver's allowance and the exemption limit of private sales of a year, both raised in 2023 and 2024
+func germanLimits(year int) (allowance, exemptionLimit decimal.Decimal) {
+	allowance = decimal.NewFromInt(1000)
+	if year < 2023 {
+		allowance = decimal.NewFromInt(801)
+	}
+	exemptionLimit = decimal.NewFromInt(1000)
+	if year < 2024 {
+		exemptionLimit = decimal.NewFromInt(600)
+	}
+	return
+}
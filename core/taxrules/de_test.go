@@ -0,0 +1,54 @@
+package taxrules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestGermanRuleAssess_Ok checks that Assess taxes stock gains less the saver's allowance and the private sales of cryptocurrencies held for up to a year
+func TestGermanRuleAssess_Ok(t *testing.T) {
+	// Arrange
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)
+	sales := []entities.TaxSale{
+		sale(entities.EntityNameStock, time.Date(2015, time.May, 4, 0, 0, 0, 0, time.UTC), time.Date(2024, time.June, 3, 0, 0, 0, 0, time.UTC), 5000, 8000, "EUR"),
+		sale(entities.EntityNameCryptoCurrency, time.Date(2024, time.January, 4, 0, 0, 0, 0, time.UTC), time.Date(2024, time.June, 3, 0, 0, 0, 0, time.UTC), 2000, 3500, "EUR"),
+		sale(entities.EntityNameCryptoCurrency, time.Date(2023, time.January, 4, 0, 0, 0, 0, time.UTC), time.Date(2024, time.June, 3, 0, 0, 0, 0, time.UTC), 2000, 9000, "EUR"),
+	}
+
+	// Act
+	assessment := NewGermanRule().Assess(sales, from, to)
+
+	// Assert
+	assert.Equal(t, entities.TaxTreatmentTaxable, assessment.Sales[0].Treatment)
+	assert.True(t, decimal.NewFromInt(3000).Equal(assessment.Sales[0].TaxableGain.Amount))
+	assert.Equal(t, entities.TaxTreatmentPrivateSale, assessment.Sales[1].Treatment)
+	assert.True(t, decimal.NewFromInt(1500).Equal(assessment.Sales[1].TaxableGain.Amount))
+	assert.Equal(t, entities.TaxTreatmentHoldingPeriodExempt, assessment.Sales[2].Treatment)
+	assert.True(t, assessment.Sales[2].TaxableGain.IsZero())
+	assert.Equal(t, entities.NewMoney(decimal.NewFromInt(1000), "EUR"), assessment.Allowance)
+}
+
+// TestGermanRuleAssess_ExemptionLimit checks that Assess exempts the private sales when their gains stay below the limit of the year
+func TestGermanRuleAssess_ExemptionLimit(t *testing.T) {
+	// Arrange
+	from := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC)
+	sales := []entities.TaxSale{
+		sale(entities.EntityNameStock, time.Date(2023, time.January, 4, 0, 0, 0, 0, time.UTC), time.Date(2023, time.June, 3, 0, 0, 0, 0, time.UTC), 500, 300, "EUR"),
+		sale(entities.EntityNameCryptoCurrency, time.Date(2023, time.January, 4, 0, 0, 0, 0, time.UTC), time.Date(2023, time.June, 3, 0, 0, 0, 0, time.UTC), 2000, 2599, "EUR"),
+	}
+
+	// Act
+	assessment := NewGermanRule().Assess(sales, from, to)
+
+	// Assert
+	assert.Equal(t, entities.TaxTreatmentThresholdExempt, assessment.Sales[1].Treatment)
+	assert.True(t, assessment.Sales[1].TaxableGain.IsZero())
+	assert.True(t, assessment.Allowance.IsZero(), "a loss uses no allowance")
+	assert.Equal(t, entities.CostBasisFIFO, NewGermanRule().CostBasisMethod())
+}
@@ -0,0 +1,80 @@
+// Package taxrules holds the capital gains tax rules of the countries the portfolios can be taxed in.
+// Each rule is registered by its ISO 3166-1 alpha-2 country code, which portfolios name in their tax country.
+package taxrules
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]ports.TaxRule{}
+)
+
+func init() {
+	Register(NewCzechRule())
+	Register(NewGermanRule())
+	Register(NewUSRule())
+}
+
+// Register makes a tax rule selectable by the tax country of the portfolios, replacing any rule registered for the same country
+func Register(rule ports.TaxRule) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[strings.ToUpper(rule.Country())] = rule
+}
+
+// Countries returns the sorted countries with a registered tax rule
+func Countries() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	countries := make([]string, 0, len(registry))
+	for country := range registry {
+		countries = append(countries, country)
+	}
+	sort.Strings(countries)
+	return countries
+}
+
+// Rules returns the registered tax rules by country
+func Rules() map[string]ports.TaxRule {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	rules := make(map[string]ports.TaxRule, len(registry))
+	for country, rule := range registry {
+		rules[country] = rule
+	}
+	return rules
+}
+
+// heldLongerThan reports whether a lot sold at sold was held for longer than the given years since acquired
+func heldLongerThan(acquired, sold time.Time, years int) bool {
+	return startOfDay(sold).After(startOfDay(acquired).AddDate(years, 0, 0))
+}
+
+// startOfDay returns midnight UTC of the day of t
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// assess returns the sale with a treatment, the rule that applied and its taxable gain
+func assess(sale entities.TaxSale, treatment, rule string, taxable bool) entities.TaxSale {
+	sale.Treatment = treatment
+	sale.Rule = rule
+	sale.TaxableGain = entities.NewMoney(decimal.Zero, sale.Gain.Currency)
+	if taxable {
+		sale.TaxableGain = sale.Gain
+	}
+	return sale
+}
@@ -0,0 +1,67 @@
+package taxrules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestRules_Ok checks that the rules of the Czech Republic, Germany and the United States are registered by their country
+func TestRules_Ok(t *testing.T) {
+	// Act
+	rules := Rules()
+
+	// Assert
+	assert.Equal(t, []string{"CZ", "DE", "US"}, Countries())
+	assert.Len(t, rules, 3)
+	for country, rule := range rules {
+		assert.Equal(t, country, rule.Country())
+	}
+}
+
+// TestRegister_Ok checks that a registered rule is returned for its country in upper case
+func TestRegister_Ok(t *testing.T) {
+	// Arrange
+	ruleMock := mocks.NewTaxRule(t)
+	ruleMock.On("Country").Return("at")
+	Register(ruleMock)
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "AT")
+		registryMu.Unlock()
+	}()
+
+	// Act
+	rules := Rules()
+
+	// Assert
+	assert.Equal(t, ruleMock, rules["AT"])
+}
+
+// TestHeldLongerThan_Ok checks that heldLongerThan counts a holding period from the day after the purchase
+func TestHeldLongerThan_Ok(t *testing.T) {
+	// Arrange
+	acquired := time.Date(2021, time.March, 15, 16, 30, 0, 0, time.UTC)
+
+	// Act & Assert
+	assert.False(t, heldLongerThan(acquired, time.Date(2024, time.March, 15, 9, 0, 0, 0, time.UTC), 3))
+	assert.True(t, heldLongerThan(acquired, time.Date(2024, time.March, 16, 9, 0, 0, 0, time.UTC), 3))
+}
+
+// sale returns a sale of a lot with its amounts in a currency
+func sale(instrumentType string, acquiredAt, soldAt time.Time, costBasis, proceeds int64, currency string) entities.TaxSale {
+	return entities.TaxSale{
+		InstrumentID:   "instrument-a",
+		InstrumentType: instrumentType,
+		Quantity:       decimal.NewFromInt(1),
+		AcquiredAt:     acquiredAt,
+		SoldAt:         soldAt,
+		CostBasis:      entities.NewMoney(decimal.NewFromInt(costBasis), currency),
+		Proceeds:       entities.NewMoney(decimal.NewFromInt(proceeds), currency),
+		Gain:           entities.NewMoney(decimal.NewFromInt(proceeds-costBasis), currency),
+	}
+}
@@ -0,0 +1,50 @@
+package taxrules
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+const (
+	// usLongTermYears is the holding period after which gains are long-term
+	usLongTermYears = 1
+)
+
+// usRule adapter of the capital gains tax rule of the United States
+type usRule struct{}
+
+// NewUSRule creates the capital gains tax rule of the United States. Every gain is taxable, short-term when the lot was held for
+// one year or less and long-term otherwise.
+func NewUSRule() ports.TaxRule {
+	return &usRule{}
+}
+
+func (r *usRule) Country() string {
+	return "US"
+}
+
+func (r *usRule) Currency() string {
+	return "USD"
+}
+
+// CostBasisMethod leaves the cost basis method to the portfolio
+func (r *usRule) CostBasisMethod() int {
+	return 0
+}
+
+func (r *usRule) Assess(sales []entities.TaxSale, from, to time.Time) entities.TaxAssessment {
+	assessed := make([]entities.TaxSale, len(sales))
+	for i, s := range sales {
+		if heldLongerThan(s.AcquiredAt, s.SoldAt, usLongTermYears) {
+			assessed[i] = assess(s, entities.TaxTreatmentLongTerm, fmt.Sprintf("held for more than %d year", usLongTermYears), true)
+		} else {
+			assessed[i] = assess(s, entities.TaxTreatmentShortTerm, fmt.Sprintf("held for %d year or less", usLongTermYears), true)
+		}
+	}
+
+	return entities.TaxAssessment{Sales: assessed, Allowance: entities.NewMoney(decimal.Zero, r.Currency())}
+}
@@ -0,0 +1,30 @@
+package taxrules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestUSRuleAssess_Ok checks that Assess splits the taxable gains into short-term and long-term ones by the holding period
+func TestUSRuleAssess_Ok(t *testing.T) {
+	// Arrange
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)
+	sales := []entities.TaxSale{
+		sale(entities.EntityNameStock, time.Date(2023, time.June, 3, 0, 0, 0, 0, time.UTC), time.Date(2024, time.June, 3, 0, 0, 0, 0, time.UTC), 1000, 1500, "USD"),
+		sale(entities.EntityNameStock, time.Date(2023, time.June, 3, 0, 0, 0, 0, time.UTC), time.Date(2024, time.June, 4, 0, 0, 0, 0, time.UTC), 1000, 700, "USD"),
+	}
+
+	// Act
+	assessment := NewUSRule().Assess(sales, from, to)
+
+	// Assert
+	assert.Equal(t, entities.TaxTreatmentShortTerm, assessment.Sales[0].Treatment)
+	assert.Equal(t, sales[0].Gain, assessment.Sales[0].TaxableGain)
+	assert.Equal(t, entities.TaxTreatmentLongTerm, assessment.Sales[1].Treatment)
+	assert.Equal(t, sales[1].Gain, assessment.Sales[1].TaxableGain)
+	assert.True(t, assessment.Allowance.IsZero())
+}
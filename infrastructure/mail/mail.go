@@ -0,0 +1,31 @@
+package mail
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// Names of the mailers selectable by Mail.Adapter
+const (
+	SMTPAdapterName   = "smtp"
+	OutboxAdapterName = "outbox"
+)
+
+// New creates the mailer selected in the configuration
+func New(cfg config.Config) (ports.Mailer, error) {
+	if cfg.Mail.From == "" {
+		return nil, fmt.Errorf("mail sender address not configured")
+	}
+
+	switch strings.ToLower(cfg.Mail.Adapter) {
+	case SMTPAdapterName:
+		return NewSMTPMailer(cfg)
+	case OutboxAdapterName:
+		return NewOutboxMailer(cfg)
+	default:
+		return nil, fmt.Errorf("mail adapter %q not supported, available adapters: %s, %s", cfg.Mail.Adapter, OutboxAdapterName, SMTPAdapterName)
+	}
+}
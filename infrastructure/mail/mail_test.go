@@ -0,0 +1,75 @@
+package mail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/config"
+)
+
+// TestNew_OutboxAdapter checks that New creates the outbox mailer when it is selected in the configuration
+func TestNew_OutboxAdapter(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Mail.Adapter = "Outbox"
+	cfg.Mail.From = "noreply@portflux.com"
+	cfg.Mail.OutboxDir = t.TempDir()
+
+	// Act
+	mailer, err := New(cfg)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.IsType(t, &outboxMailer{}, mailer)
+}
+
+// TestNew_SMTPAdapter checks that New creates the SMTP mailer with the address of the server when it is selected in the configuration
+func TestNew_SMTPAdapter(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Mail.Adapter = SMTPAdapterName
+	cfg.Mail.From = "noreply@portflux.com"
+	cfg.Mail.Host = "smtp.example.com"
+	cfg.Mail.Port = 587
+	cfg.Mail.Username = "portflux"
+
+	// Act
+	mailer, err := New(cfg)
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.IsType(t, &smtpMailer{}, mailer) {
+		assert.Equal(t, "smtp.example.com:587", mailer.(*smtpMailer).addr)
+		assert.NotNil(t, mailer.(*smtpMailer).auth)
+	}
+}
+
+// TestNew_UnknownAdapter checks that New returns an error when the selected adapter is not supported
+func TestNew_UnknownAdapter(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Mail.Adapter = "pigeon"
+	cfg.Mail.From = "noreply@portflux.com"
+	expectedError := `mail adapter "pigeon" not supported, available adapters: outbox, smtp`
+
+	// Act
+	_, err := New(cfg)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestNew_MissingSender checks that New returns an error when the sender address is not configured
+func TestNew_MissingSender(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Mail.Adapter = OutboxAdapterName
+	cfg.Mail.OutboxDir = t.TempDir()
+	expectedError := "mail sender address not configured"
+
+	// Act
+	_, err := New(cfg)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
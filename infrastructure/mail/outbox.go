@@ -0,0 +1,61 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// outboxMailer adapter of a mailer that writes the emails to files instead of sending them
+type outboxMailer struct {
+	dir  string
+	from string
+}
+
+// NewOutboxMailer creates a mailer writing every email as an .eml file to the outbox directory of the configuration,
+// meant for local use and tests
+func NewOutboxMailer(cfg config.Config) (ports.Mailer, error) {
+	if cfg.Mail.OutboxDir == "" {
+		return nil, fmt.Errorf("mail outbox directory not configured")
+	}
+
+	return &outboxMailer{
+		dir:  cfg.Mail.OutboxDir,
+		from: cfg.Mail.From,
+	}, nil
+}
+
+// Send writes a mail to a new file of the outbox directory, named after the time it was written
+func (m *outboxMailer) Send(ctx context.Context, mail entities.Mail) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if mail.To == "" {
+		return fmt.Errorf("mail has no recipient")
+	}
+
+	now := time.Now()
+	msg, err := compose(m.from, mail, now)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(m.dir, now.UTC().Format("20060102T150405")+"-*.eml")
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(msg); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
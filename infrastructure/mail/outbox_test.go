@@ -0,0 +1,93 @@
+package mail
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestOutboxSend_Ok checks that Send writes the mail to the outbox as a MIME message with the plain text and HTML alternatives
+func TestOutboxSend_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Mail.From = "noreply@portflux.com"
+	cfg.Mail.OutboxDir = filepath.Join(t.TempDir(), "outbox")
+	mailer, err := NewOutboxMailer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedMail := entities.Mail{To: "jane@example.com", Subject: "Your weekly summary of Škoda", Text: "Hello Jane", HTML: "<p>Hello Jane</p>"}
+
+	// Act
+	err = mailer.Send(context.Background(), expectedMail)
+
+	// Assert
+	assert.Nil(t, err)
+	files, err := filepath.Glob(filepath.Join(cfg.Mail.OutboxDir, "*.eml"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("unexpected outbox files %v: %v", files, err)
+	}
+	file, err := os.Open(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	msg, err := mail.ReadMessage(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "noreply@portflux.com", msg.Header.Get("From"))
+	assert.Equal(t, "jane@example.com", msg.Header.Get("To"))
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	assert.Equal(t, expectedMail.Subject, subject)
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	assert.Nil(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	var bodies []string
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(part)
+		bodies = append(bodies, part.Header.Get("Content-Type")+": "+strings.TrimSpace(string(body)))
+	}
+	assert.Equal(t, []string{"text/plain; charset=UTF-8: Hello Jane", "text/html; charset=UTF-8: <p>Hello Jane</p>"}, bodies)
+}
+
+// TestOutboxSend_NoRecipient checks that Send returns an error and writes nothing when the mail has no recipient
+func TestOutboxSend_NoRecipient(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Mail.From = "noreply@portflux.com"
+	cfg.Mail.OutboxDir = t.TempDir()
+	mailer, err := NewOutboxMailer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedError := "mail has no recipient"
+
+	// Act
+	err = mailer.Send(context.Background(), entities.Mail{Subject: "Summary"})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+	files, _ := os.ReadDir(cfg.Mail.OutboxDir)
+	assert.Empty(t, files)
+}
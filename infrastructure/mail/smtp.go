@@ -0,0 +1,54 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// smtpMailer adapter of a mailer that sends the emails through an SMTP server
+type smtpMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer creates a mailer sending the emails through the SMTP server of the configuration, with STARTTLS when the server offers it
+func NewSMTPMailer(cfg config.Config) (ports.Mailer, error) {
+	if cfg.Mail.Host == "" {
+		return nil, fmt.Errorf("SMTP host not configured")
+	}
+
+	m := &smtpMailer{
+		addr: net.JoinHostPort(cfg.Mail.Host, strconv.Itoa(cfg.Mail.Port)),
+		from: cfg.Mail.From,
+	}
+	if cfg.Mail.Username != "" {
+		m.auth = smtp.PlainAuth("", cfg.Mail.Username, cfg.Mail.Password, cfg.Mail.Host)
+	}
+	return m, nil
+}
+
+// Send sends a mail through the SMTP server
+func (m *smtpMailer) Send(ctx context.Context, mail entities.Mail) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if mail.To == "" {
+		return fmt.Errorf("mail has no recipient")
+	}
+
+	msg, err := compose(m.from, mail, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{mail.To}, msg)
+}
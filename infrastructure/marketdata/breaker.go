@@ -0,0 +1,108 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// ErrCircuitOpen is returned without calling the provider while its circuit breaker is open
+var ErrCircuitOpen = errors.New("market data provider unavailable, circuit breaker open")
+
+// circuitBreakerProvider adapter of a market data provider that stops calling another provider for a while after repeated failures
+type circuitBreakerProvider struct {
+	next      ports.MarketDataProvider
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// NewCircuitBreakerProvider creates a market data provider that fails fast for cooldown once threshold consecutive calls to next
+// have failed, and then lets a single call through to probe whether next has recovered. It returns next unchanged when threshold
+// is not positive.
+func NewCircuitBreakerProvider(next ports.MarketDataProvider, threshold int, cooldown time.Duration) ports.MarketDataProvider {
+	if threshold <= 0 {
+		return next
+	}
+	return &circuitBreakerProvider{
+		next:      next,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+func (p *circuitBreakerProvider) Name() string {
+	return p.next.Name()
+}
+
+func (p *circuitBreakerProvider) Quote(ctx context.Context, symbol string) (quote entities.Quote, err error) {
+	err = p.do(func() (err error) {
+		quote, err = p.next.Quote(ctx, symbol)
+		return
+	})
+	return
+}
+
+func (p *circuitBreakerProvider) Bars(ctx context.Context, symbol string, from, to time.Time) (bars []entities.Bar, err error) {
+	err = p.do(func() (err error) {
+		bars, err = p.next.Bars(ctx, symbol, from, to)
+		return
+	})
+	return
+}
+
+func (p *circuitBreakerProvider) Dividends(ctx context.Context, symbol string, from, to time.Time) (dividends []entities.DividendEvent, err error) {
+	err = p.do(func() (err error) {
+		dividends, err = p.next.Dividends(ctx, symbol, from, to)
+		return
+	})
+	return
+}
+
+func (p *circuitBreakerProvider) Splits(ctx context.Context, symbol string, from, to time.Time) (splits []entities.Split, err error) {
+	err = p.do(func() (err error) {
+		splits, err = p.next.Splits(ctx, symbol, from, to)
+		return
+	})
+	return
+}
+
+// do runs a provider call unless the circuit is open. Unknown symbols, invalid requests and cancelled calls are answers
+// of a healthy provider, so they do not count as failures.
+func (p *circuitBreakerProvider) do(call func() error) error {
+	p.mu.Lock()
+	if p.failures >= p.threshold {
+		if p.probing || p.now().Before(p.openUntil) {
+			p.mu.Unlock()
+			return fmt.Errorf("%s: %w", p.next.Name(), ErrCircuitOpen)
+		}
+		p.probing = true
+	}
+	p.mu.Unlock()
+
+	err := call()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.probing = false
+	if err == nil || errors.Is(err, wrappers.NonExistentErr) || errors.Is(err, wrappers.ValidationErr) || errors.Is(err, context.Canceled) {
+		p.failures = 0
+		return err
+	}
+	p.failures++
+	if p.failures >= p.threshold {
+		p.openUntil = p.now().Add(p.cooldown)
+	}
+	return err
+}
@@ -0,0 +1,116 @@
+package marketdata
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// cachedProvider adapter of a market data provider that reuses the successful responses of another provider for a while
+type cachedProvider struct {
+	next    ports.MarketDataProvider
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// cacheEntry is a cached response and the time it stops being reused
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// NewCachedProvider creates a market data provider caching the responses of next for ttl, returning next unchanged when ttl is not positive
+func NewCachedProvider(next ports.MarketDataProvider, ttl time.Duration) ports.MarketDataProvider {
+	if ttl <= 0 {
+		return next
+	}
+	return &cachedProvider{
+		next:    next,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]cacheEntry{},
+	}
+}
+
+func (p *cachedProvider) Name() string {
+	return p.next.Name()
+}
+
+func (p *cachedProvider) Quote(ctx context.Context, symbol string) (entities.Quote, error) {
+	value, err := p.get(fmt.Sprintf("quote|%s", symbol), func() (interface{}, error) {
+		return p.next.Quote(ctx, symbol)
+	})
+	if err != nil {
+		return entities.Quote{}, err
+	}
+	return value.(entities.Quote), nil
+}
+
+func (p *cachedProvider) Bars(ctx context.Context, symbol string, from, to time.Time) ([]entities.Bar, error) {
+	value, err := p.get(rangeKey("bars", symbol, from, to), func() (interface{}, error) {
+		return p.next.Bars(ctx, symbol, from, to)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]entities.Bar), nil
+}
+
+func (p *cachedProvider) Dividends(ctx context.Context, symbol string, from, to time.Time) ([]entities.DividendEvent, error) {
+	value, err := p.get(rangeKey("dividends", symbol, from, to), func() (interface{}, error) {
+		return p.next.Dividends(ctx, symbol, from, to)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]entities.DividendEvent), nil
+}
+
+func (p *cachedProvider) Splits(ctx context.Context, symbol string, from, to time.Time) ([]entities.Split, error) {
+	value, err := p.get(rangeKey("splits", symbol, from, to), func() (interface{}, error) {
+		return p.next.Splits(ctx, symbol, from, to)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]entities.Split), nil
+}
+
+// get returns the cached value of a key, loading and caching it when missing or expired. Errors are not cached.
+func (p *cachedProvider) get(key string, load func() (interface{}, error)) (interface{}, error) {
+	now := p.now()
+
+	p.mu.Lock()
+	entry, ok := p.entries[key]
+	p.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.value, nil
+	}
+
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	for k, e := range p.entries {
+		if !now.Before(e.expires) {
+			delete(p.entries, k)
+		}
+	}
+	p.entries[key] = cacheEntry{value: value, expires: now.Add(p.ttl)}
+	p.mu.Unlock()
+
+	return value, nil
+}
+
+// rangeKey returns the cache key of a call over a date range
+func rangeKey(kind, symbol string, from, to time.Time) string {
+	return fmt.Sprintf("%s|%s|%s|%s", kind, symbol, from.Format(fixtureDateLayout), to.Format(fixtureDateLayout))
+}
@@ -0,0 +1,115 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/testutils"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestCachedQuote_Cached checks that the cached provider calls the wrapped provider once while the response is fresh
+func TestCachedQuote_Cached(t *testing.T) {
+	// Arrange
+	expectedQuote := entities.Quote{Symbol: "AAPL", QuotedAt: time.Date(2023, time.August, 4, 20, 0, 0, 0, time.UTC)}
+	providerMock := mocks.NewMarketDataProvider(t)
+	providerMock.On(testutils.FunctionName(t, ports.MarketDataProvider.Quote), context.Background(), "AAPL").Return(expectedQuote, nil).Once()
+
+	provider := NewCachedProvider(providerMock, time.Minute)
+
+	// Act
+	first, firstErr := provider.Quote(context.Background(), "AAPL")
+	second, secondErr := provider.Quote(context.Background(), "AAPL")
+
+	// Assert
+	assert.Nil(t, firstErr)
+	assert.Nil(t, secondErr)
+	assert.Equal(t, expectedQuote, first)
+	assert.Equal(t, expectedQuote, second)
+}
+
+// TestCachedQuote_Expired checks that the cached provider calls the wrapped provider again once the response expires
+func TestCachedQuote_Expired(t *testing.T) {
+	// Arrange
+	providerMock := mocks.NewMarketDataProvider(t)
+	providerMock.On(testutils.FunctionName(t, ports.MarketDataProvider.Quote), context.Background(), "AAPL").Return(entities.Quote{Symbol: "AAPL"}, nil).Twice()
+
+	now := time.Date(2023, time.August, 4, 20, 0, 0, 0, time.UTC)
+	provider := NewCachedProvider(providerMock, time.Minute).(*cachedProvider)
+	provider.now = func() time.Time { return now }
+
+	// Act
+	provider.Quote(context.Background(), "AAPL")
+	now = now.Add(time.Minute)
+	_, err := provider.Quote(context.Background(), "AAPL")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestRateLimitedQuote_ContextDone checks that the rate limited provider returns the context error instead of waiting for a slot
+func TestRateLimitedQuote_ContextDone(t *testing.T) {
+	// Arrange
+	providerMock := mocks.NewMarketDataProvider(t)
+	providerMock.On(testutils.FunctionName(t, ports.MarketDataProvider.Quote), context.Background(), "AAPL").Return(entities.Quote{}, nil).Once()
+
+	provider := NewRateLimitedProvider(providerMock, 1)
+	provider.Quote(context.Background(), "AAPL")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Act
+	_, err := provider.Quote(ctx, "AAPL")
+
+	// Assert
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+// TestCircuitBreakerQuote_Opens checks that the circuit breaker fails fast after the failure threshold and probes again after the cooldown
+func TestCircuitBreakerQuote_Opens(t *testing.T) {
+	// Arrange
+	providerMock := mocks.NewMarketDataProvider(t)
+	providerMock.On(testutils.FunctionName(t, ports.MarketDataProvider.Name)).Return("test")
+	providerMock.On(testutils.FunctionName(t, ports.MarketDataProvider.Quote), context.Background(), "AAPL").Return(entities.Quote{}, errors.New("timeout")).Twice()
+	providerMock.On(testutils.FunctionName(t, ports.MarketDataProvider.Quote), context.Background(), "AAPL").Return(entities.Quote{Symbol: "AAPL"}, nil).Once()
+
+	now := time.Date(2023, time.August, 4, 20, 0, 0, 0, time.UTC)
+	provider := NewCircuitBreakerProvider(providerMock, 2, time.Minute).(*circuitBreakerProvider)
+	provider.now = func() time.Time { return now }
+
+	// Act
+	provider.Quote(context.Background(), "AAPL")
+	provider.Quote(context.Background(), "AAPL")
+	_, openErr := provider.Quote(context.Background(), "AAPL")
+	now = now.Add(time.Minute)
+	quote, probeErr := provider.Quote(context.Background(), "AAPL")
+
+	// Assert
+	assert.ErrorIs(t, openErr, ErrCircuitOpen)
+	assert.Nil(t, probeErr)
+	assert.Equal(t, "AAPL", quote.Symbol)
+	assert.Equal(t, 0, provider.failures)
+}
+
+// TestCircuitBreakerQuote_UnknownSymbol checks that unknown symbols do not count as failures of the provider
+func TestCircuitBreakerQuote_UnknownSymbol(t *testing.T) {
+	// Arrange
+	providerMock := mocks.NewMarketDataProvider(t)
+	providerMock.On(testutils.FunctionName(t, ports.MarketDataProvider.Quote), context.Background(), "MSFT").Return(entities.Quote{}, wrappers.NewNonExistentErr(errors.New("symbol MSFT not found"))).Twice()
+
+	provider := NewCircuitBreakerProvider(providerMock, 1, time.Minute)
+
+	// Act
+	provider.Quote(context.Background(), "MSFT")
+	_, err := provider.Quote(context.Background(), "MSFT")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
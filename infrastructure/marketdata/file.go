@@ -0,0 +1,193 @@
+package marketdata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// FileProviderName is the name the file provider is registered with
+const FileProviderName = "file"
+
+// fixtureDateLayout is the layout of the dates in the symbol files
+const fixtureDateLayout = "2006-01-02"
+
+// fileProvider adapter of a market data provider that reads each symbol from a JSON file named after it, for local use and tests
+type fileProvider struct {
+	dir string
+}
+
+// NewFileProvider creates a market data provider reading the symbol files of the configured fixtures directory
+func NewFileProvider(cfg config.Config) (ports.MarketDataProvider, error) {
+	if cfg.MarketData.FixturesDir == "" {
+		return nil, fmt.Errorf("fixtures directory cannot be empty")
+	}
+	return &fileProvider{
+		dir: cfg.MarketData.FixturesDir,
+	}, nil
+}
+
+// fixture is the layout of a symbol file
+type fixture struct {
+	Currency string `json:"currency"`
+	Quote    struct {
+		Price    decimal.Decimal `json:"price"`
+		QuotedAt time.Time       `json:"quoted_at"`
+	} `json:"quote"`
+	Bars []struct {
+		Date   string          `json:"date"`
+		Open   decimal.Decimal `json:"open"`
+		High   decimal.Decimal `json:"high"`
+		Low    decimal.Decimal `json:"low"`
+		Close  decimal.Decimal `json:"close"`
+		Volume decimal.Decimal `json:"volume"`
+	} `json:"bars"`
+	Dividends []struct {
+		ExDate  string          `json:"ex_date"`
+		PayDate string          `json:"pay_date"`
+		Amount  decimal.Decimal `json:"amount"`
+	} `json:"dividends"`
+	Splits []struct {
+		Date        string          `json:"date"`
+		Numerator   decimal.Decimal `json:"numerator"`
+		Denominator decimal.Decimal `json:"denominator"`
+	} `json:"splits"`
+}
+
+func (p *fileProvider) Name() string {
+	return FileProviderName
+}
+
+// Quote returns the quote of the symbol file
+func (p *fileProvider) Quote(ctx context.Context, symbol string) (entities.Quote, error) {
+	f, err := p.load(symbol)
+	if err != nil {
+		return entities.Quote{}, err
+	}
+	if f.Quote.QuotedAt.IsZero() {
+		return entities.Quote{}, wrappers.NewNonExistentErr(fmt.Errorf("no quote found for symbol %s", symbol))
+	}
+
+	return entities.Quote{
+		Symbol:   symbol,
+		Price:    entities.NewMoney(f.Quote.Price, f.Currency),
+		QuotedAt: f.Quote.QuotedAt,
+	}, nil
+}
+
+// Bars returns the daily bars of the symbol file between two days, both included
+func (p *fileProvider) Bars(ctx context.Context, symbol string, from, to time.Time) ([]entities.Bar, error) {
+	f, err := p.load(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	bars := []entities.Bar{}
+	for _, b := range f.Bars {
+		date, err := parseFixtureDate(symbol, b.Date)
+		if err != nil {
+			return nil, err
+		}
+		if !within(date, from, to) {
+			continue
+		}
+		bars = append(bars, entities.Bar{Date: date, Open: b.Open, High: b.High, Low: b.Low, Close: b.Close, Volume: b.Volume, Currency: f.Currency})
+	}
+	return bars, nil
+}
+
+// Dividends returns the dividends of the symbol file with an ex-date between two days, both included
+func (p *fileProvider) Dividends(ctx context.Context, symbol string, from, to time.Time) ([]entities.DividendEvent, error) {
+	f, err := p.load(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	dividends := []entities.DividendEvent{}
+	for _, d := range f.Dividends {
+		exDate, err := parseFixtureDate(symbol, d.ExDate)
+		if err != nil {
+			return nil, err
+		}
+		if !within(exDate, from, to) {
+			continue
+		}
+		var payDate time.Time
+		if d.PayDate != "" {
+			if payDate, err = parseFixtureDate(symbol, d.PayDate); err != nil {
+				return nil, err
+			}
+		}
+		dividends = append(dividends, entities.DividendEvent{ExDate: exDate, PayDate: payDate, Amount: entities.NewMoney(d.Amount, f.Currency)})
+	}
+	return dividends, nil
+}
+
+// Splits returns the splits of the symbol file between two days, both included
+func (p *fileProvider) Splits(ctx context.Context, symbol string, from, to time.Time) ([]entities.Split, error) {
+	f, err := p.load(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	splits := []entities.Split{}
+	for _, s := range f.Splits {
+		date, err := parseFixtureDate(symbol, s.Date)
+		if err != nil {
+			return nil, err
+		}
+		if !within(date, from, to) {
+			continue
+		}
+		splits = append(splits, entities.Split{Date: date, Numerator: s.Numerator, Denominator: s.Denominator})
+	}
+	return splits, nil
+}
+
+// load reads the file of a symbol, named after the symbol in upper case
+func (p *fileProvider) load(symbol string) (fixture, error) {
+	var f fixture
+	name := strings.ToUpper(strings.TrimSpace(symbol))
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return f, wrappers.NewValidationErr(fmt.Errorf("invalid symbol %q", symbol))
+	}
+
+	data, err := os.ReadFile(filepath.Join(p.dir, name+".json"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return f, wrappers.NewNonExistentErr(fmt.Errorf("symbol %s not found", symbol))
+		}
+		return f, err
+	}
+
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("invalid market data file for symbol %s: %w", symbol, err)
+	}
+	return f, nil
+}
+
+// parseFixtureDate parses a date of a symbol file
+func parseFixtureDate(symbol, value string) (time.Time, error) {
+	date, err := time.Parse(fixtureDateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q in market data file for symbol %s", value, symbol)
+	}
+	return date, nil
+}
+
+// within reports whether a date is between from and to, both included, a zero bound not limiting the range
+func within(date, from, to time.Time) bool {
+	return (from.IsZero() || !date.Before(from)) && (to.IsZero() || !date.After(to))
+}
@@ -0,0 +1,107 @@
+package marketdata
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/config"
+)
+
+// newTestFileProvider returns a file provider reading the files of the testdata directory
+func newTestFileProvider(t *testing.T) *fileProvider {
+	cfg := config.Config{}
+	cfg.MarketData.FixturesDir = "testdata"
+	provider, err := NewFileProvider(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return provider.(*fileProvider)
+}
+
+// TestNewFileProvider_EmptyDir checks that NewFileProvider returns an error when no fixtures directory is configured
+func TestNewFileProvider_EmptyDir(t *testing.T) {
+	// Act
+	_, err := NewFileProvider(config.Config{})
+
+	// Assert
+	assert.Equal(t, "fixtures directory cannot be empty", err.Error())
+}
+
+// TestFileQuote_Ok checks that Quote returns the quote of the symbol file
+func TestFileQuote_Ok(t *testing.T) {
+	// Arrange
+	provider := newTestFileProvider(t)
+
+	// Act
+	quote, err := provider.Quote(context.Background(), "aapl")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "181.99 USD", quote.Price.String())
+	assert.Equal(t, time.Date(2023, time.August, 4, 20, 0, 0, 0, time.UTC), quote.QuotedAt)
+}
+
+// TestFileQuote_UnknownSymbol checks that Quote returns a non existent error when the symbol has no file
+func TestFileQuote_UnknownSymbol(t *testing.T) {
+	// Arrange
+	provider := newTestFileProvider(t)
+
+	// Act
+	_, err := provider.Quote(context.Background(), "MSFT")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
+
+// TestFileQuote_InvalidSymbol checks that Quote returns a validation error when the symbol is a path
+func TestFileQuote_InvalidSymbol(t *testing.T) {
+	// Arrange
+	provider := newTestFileProvider(t)
+
+	// Act
+	_, err := provider.Quote(context.Background(), "../AAPL")
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
+
+// TestFileBars_Range checks that Bars returns the bars of the symbol file in the range only
+func TestFileBars_Range(t *testing.T) {
+	// Arrange
+	provider := newTestFileProvider(t)
+	from := time.Date(2023, time.August, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	bars, err := provider.Bars(context.Background(), "AAPL", from, to)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, bars, 2)
+	assert.Equal(t, from, bars[0].Date)
+	assert.Equal(t, "192.58", bars[0].Close.String())
+	assert.Equal(t, "USD", bars[0].Currency)
+}
+
+// TestFileDividendsAndSplits_Ok checks that Dividends and Splits return the events of the symbol file
+func TestFileDividendsAndSplits_Ok(t *testing.T) {
+	// Arrange
+	provider := newTestFileProvider(t)
+	from := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	dividends, dividendsErr := provider.Dividends(context.Background(), "AAPL", from, time.Time{})
+	splits, splitsErr := provider.Splits(context.Background(), "AAPL", time.Time{}, time.Time{})
+
+	// Assert
+	assert.Nil(t, dividendsErr)
+	assert.Len(t, dividends, 2)
+	assert.Equal(t, "0.24 USD", dividends[1].Amount.String())
+	assert.Equal(t, time.Date(2023, time.August, 17, 0, 0, 0, 0, time.UTC), dividends[1].PayDate)
+	assert.Nil(t, splitsErr)
+	assert.Len(t, splits, 1)
+	assert.Equal(t, "4", splits[0].Numerator.String())
+}
@@ -0,0 +1,87 @@
+package marketdata
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// rateLimitedProvider adapter of a market data provider that spaces out the calls to another provider evenly
+type rateLimitedProvider struct {
+	next     ports.MarketDataProvider
+	interval time.Duration
+	mu       sync.Mutex
+	slot     time.Time
+}
+
+// NewRateLimitedProvider creates a market data provider making at most requestsPerMinute calls per minute to next,
+// returning next unchanged when requestsPerMinute is not positive
+func NewRateLimitedProvider(next ports.MarketDataProvider, requestsPerMinute int) ports.MarketDataProvider {
+	if requestsPerMinute <= 0 {
+		return next
+	}
+	return &rateLimitedProvider{
+		next:     next,
+		interval: time.Minute / time.Duration(requestsPerMinute),
+	}
+}
+
+func (p *rateLimitedProvider) Name() string {
+	return p.next.Name()
+}
+
+func (p *rateLimitedProvider) Quote(ctx context.Context, symbol string) (entities.Quote, error) {
+	if err := p.wait(ctx); err != nil {
+		return entities.Quote{}, err
+	}
+	return p.next.Quote(ctx, symbol)
+}
+
+func (p *rateLimitedProvider) Bars(ctx context.Context, symbol string, from, to time.Time) ([]entities.Bar, error) {
+	if err := p.wait(ctx); err != nil {
+		return nil, err
+	}
+	return p.next.Bars(ctx, symbol, from, to)
+}
+
+func (p *rateLimitedProvider) Dividends(ctx context.Context, symbol string, from, to time.Time) ([]entities.DividendEvent, error) {
+	if err := p.wait(ctx); err != nil {
+		return nil, err
+	}
+	return p.next.Dividends(ctx, symbol, from, to)
+}
+
+func (p *rateLimitedProvider) Splits(ctx context.Context, symbol string, from, to time.Time) ([]entities.Split, error) {
+	if err := p.wait(ctx); err != nil {
+		return nil, err
+	}
+	return p.next.Splits(ctx, symbol, from, to)
+}
+
+// wait blocks until the next free call slot, or returns the error of the context when it is done first
+func (p *rateLimitedProvider) wait(ctx context.Context) error {
+	p.mu.Lock()
+	now := time.Now()
+	if p.slot.Before(now) {
+		p.slot = now
+	}
+	delay := p.slot.Sub(now)
+	p.slot = p.slot.Add(p.interval)
+	p.mu.Unlock()
+
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
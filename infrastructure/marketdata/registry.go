@@ -0,0 +1,65 @@
+package marketdata
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// Factory creates a market data provider from the configuration
+type Factory func(cfg config.Config) (ports.MarketDataProvider, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+func init() {
+	Register(FileProviderName, NewFileProvider)
+}
+
+// Register makes a market data adapter selectable by name in the configuration, replacing any adapter registered with the same name
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[strings.ToLower(name)] = factory
+}
+
+// Providers returns the sorted names of the registered market data adapters
+func Providers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the market data provider selected in the configuration. Its calls are served from a cache when possible,
+// and otherwise go through a circuit breaker and a rate limiter, so a failing or slow source cannot flood nor block the API.
+func New(cfg config.Config) (ports.MarketDataProvider, error) {
+	registryMu.RLock()
+	factory, ok := registry[strings.ToLower(cfg.MarketData.Provider)]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("market data provider %q not registered, available providers: %s", cfg.MarketData.Provider, strings.Join(Providers(), ", "))
+	}
+
+	provider, err := factory(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create market data provider %q: %w", cfg.MarketData.Provider, err)
+	}
+
+	provider = NewRateLimitedProvider(provider, cfg.MarketData.RequestsPerMinute)
+	provider = NewCircuitBreakerProvider(provider, cfg.MarketData.FailureThreshold, cfg.MarketData.BreakerCooldown.Duration)
+	provider = NewCachedProvider(provider, cfg.MarketData.CacheTTL.Duration)
+	return provider, nil
+}
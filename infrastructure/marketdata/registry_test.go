@@ -0,0 +1,66 @@
+package marketdata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/config"
+	"github.com/tkudlicka/portflux-api/core/ports"
+	"github.com/tkudlicka/portflux-api/test/mocks"
+)
+
+// TestNew_FileProvider checks that New creates the file provider wrapped by the cache when it is selected in the configuration
+func TestNew_FileProvider(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.MarketData.Provider = "File"
+	cfg.MarketData.FixturesDir = "testdata"
+	cfg.MarketData.CacheTTL.Duration = time.Minute
+
+	// Act
+	provider, err := New(cfg)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.IsType(t, &cachedProvider{}, provider)
+	assert.Equal(t, FileProviderName, provider.Name())
+}
+
+// TestNew_UnknownProvider checks that New returns an error when the selected provider is not registered
+func TestNew_UnknownProvider(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.MarketData.Provider = "unknown"
+	expectedError := `market data provider "unknown" not registered, available providers: file`
+
+	// Act
+	_, err := New(cfg)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestRegister_Ok checks that a registered provider can be selected in the configuration
+func TestRegister_Ok(t *testing.T) {
+	// Arrange
+	providerMock := mocks.NewMarketDataProvider(t)
+	Register("test", func(cfg config.Config) (ports.MarketDataProvider, error) {
+		return providerMock, nil
+	})
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "test")
+		registryMu.Unlock()
+	}()
+
+	cfg := config.Config{}
+	cfg.MarketData.Provider = "test"
+
+	// Act
+	provider, err := New(cfg)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, providerMock, provider)
+}
@@ -0,0 +1,27 @@
+package notification
+
+import (
+	"context"
+	"log"
+
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// logNotifier adapter of a notifier that writes the notifications to the log
+type logNotifier struct{}
+
+// NewLogNotifier creates a notifier that writes the notifications to the log, used until users can be reached by other channels
+func NewLogNotifier() ports.Notifier {
+	return logNotifier{}
+}
+
+// Notify logs a notification
+func (logNotifier) Notify(ctx context.Context, notification entities.Notification) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	log.Printf("notification to user %s: %s - %s", notification.UserID, notification.Subject, notification.Message)
+	return nil
+}
@@ -0,0 +1,376 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// alertRuleRepository adapter of an alert rule repository for postgres
+type alertRuleRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewAlertRuleRepository creates an alert rule repository for postgres
+func NewAlertRuleRepository(db *sql.DB) ports.AlertRuleRepository {
+	return &alertRuleRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+// selectAlertRule selects the columns of an alert rule in the order scanned by scanAlertRules
+const selectAlertRule = `SELECT alert_ruleid, portfolioid, instrumentid, instrument_type, condition, threshold, active, created_at, updated_at FROM alert_rule`
+
+func (r *alertRuleRepository) Create(ctx context.Context, rule interface{}) (string, error) {
+	q := `
+	INSERT INTO alert_rule (portfolioid, instrumentid, instrument_type, condition, threshold, active, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING alert_ruleid;
+    `
+
+	a := rule.(entities.AlertRule)
+	row := r.DB.QueryRowContext(
+		ctx, q, a.PortfolioID, a.InstrumentID, a.InstrumentType, a.Condition, a.Threshold, a.Active, a.CreatedAt, a.UpdatedAt,
+	)
+
+	err := row.Scan(&a.AlertRuleID)
+	if err != nil {
+		return "", err
+	}
+
+	return a.AlertRuleID, nil
+}
+
+func (r *alertRuleRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	var where string
+	for k, v := range filter {
+		if where == "" {
+			where = "WHERE"
+		} else {
+			where = fmt.Sprintf("%s AND", where)
+		}
+		where = fmt.Sprintf("%s %s = '%v'", where, k, v)
+	}
+	if skip != nil {
+		where = fmt.Sprintf("%s OFFSET %d", where, *skip)
+	}
+	if take != nil {
+		where = fmt.Sprintf("%s LIMIT %d", where, *take)
+	}
+
+	q := fmt.Sprintf("%s %s;", selectAlertRule, where)
+
+	rows, err := r.DB.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanAlertRules(rows)
+}
+
+func (r *alertRuleRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	q := fmt.Sprintf("%s WHERE alert_ruleid = $1;", selectAlertRule)
+
+	row := r.DB.QueryRowContext(ctx, q, ID)
+
+	var a entities.AlertRule
+	err := row.Scan(&a.AlertRuleID, &a.PortfolioID, &a.InstrumentID, &a.InstrumentType, &a.Condition, &a.Threshold, &a.Active, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// GetByPortfolioID returns the alert rules of a portfolio, oldest first
+func (r *alertRuleRepository) GetByPortfolioID(ctx context.Context, portfolioID string) ([]interface{}, error) {
+	q := fmt.Sprintf("%s WHERE portfolioid = $1 ORDER BY created_at;", selectAlertRule)
+
+	rows, err := r.DB.QueryContext(ctx, q, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanAlertRules(rows)
+}
+
+func (r *alertRuleRepository) Update(ctx context.Context, ID string, rule interface{}) error {
+	q := `
+	UPDATE alert_rule set condition=$1, threshold=$2, active=$3, updated_at=$4
+	    WHERE alert_ruleid=$5;
+	`
+
+	a := rule.(entities.AlertRule)
+	result, err := r.DB.ExecContext(
+		ctx, q, a.Condition, a.Threshold, a.Active, a.UpdatedAt, ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *alertRuleRepository) Delete(ctx context.Context, ID string) error {
+	q := `DELETE FROM alert_rule WHERE alert_ruleid=$1;`
+
+	result, err := r.DB.ExecContext(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// scanAlertRules scans the rules selected with selectAlertRule, returning a non existent error when there are none
+func scanAlertRules(rows *sql.Rows) ([]interface{}, error) {
+	defer rows.Close()
+
+	var rules []interface{}
+	for rows.Next() {
+		var a entities.AlertRule
+		err := rows.Scan(&a.AlertRuleID, &a.PortfolioID, &a.InstrumentID, &a.InstrumentType, &a.Condition, &a.Threshold, &a.Active, &a.CreatedAt, &a.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, &a)
+	}
+
+	if len(rules) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return rules, nil
+}
+
+// alertRepository adapter of an alert repository for postgres
+type alertRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewAlertRepository creates an alert repository for postgres
+func NewAlertRepository(db *sql.DB) ports.AlertRepository {
+	return &alertRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+// selectAlert selects the columns of an alert in the order scanned by scanAlert
+const selectAlert = `SELECT alertid, alert_ruleid, portfolioid, instrumentid, instrument_type, condition, threshold, price, currency, value, trigger_date, triggered_at, notified_at FROM alert`
+
+// insertAlert inserts an alert, doing nothing when its rule already triggered on the same day
+const insertAlert = `
+	INSERT INTO alert (alert_ruleid, portfolioid, instrumentid, instrument_type, condition, threshold, price, currency, value, trigger_date, triggered_at, notified_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+        ON CONFLICT (alert_ruleid, trigger_date) DO NOTHING
+        RETURNING alertid;
+    `
+
+func (r *alertRepository) Create(ctx context.Context, alert interface{}) (string, error) {
+	ID, created, err := r.CreateUnique(ctx, alert)
+	if err != nil {
+		return "", err
+	}
+	if !created {
+		a := alert.(entities.Alert)
+		return "", wrappers.NewValidationErr(fmt.Errorf("alert rule %s already triggered on %s", a.AlertRuleID, a.TriggerDate.Format("2006-01-02")))
+	}
+	return ID, nil
+}
+
+// CreateUnique stores an alert unless its rule already triggered on the same day, reporting whether it was stored
+func (r *alertRepository) CreateUnique(ctx context.Context, alert interface{}) (string, bool, error) {
+	a := alert.(entities.Alert)
+	row := r.DB.QueryRowContext(
+		ctx, insertAlert, a.AlertRuleID, a.PortfolioID, a.InstrumentID, a.InstrumentType, a.Condition, a.Threshold, a.Price.Amount, a.Price.Currency, a.Value, a.TriggerDate, a.TriggeredAt, nullTime(a.NotifiedAt),
+	)
+
+	err := row.Scan(&a.AlertID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	return a.AlertID, true, nil
+}
+
+func (r *alertRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	var where string
+	for k, v := range filter {
+		if where == "" {
+			where = "WHERE"
+		} else {
+			where = fmt.Sprintf("%s AND", where)
+		}
+		where = fmt.Sprintf("%s %s = '%v'", where, k, v)
+	}
+	if skip != nil {
+		where = fmt.Sprintf("%s OFFSET %d", where, *skip)
+	}
+	if take != nil {
+		where = fmt.Sprintf("%s LIMIT %d", where, *take)
+	}
+
+	q := fmt.Sprintf("%s %s;", selectAlert, where)
+
+	rows, err := r.DB.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanAlerts(rows)
+}
+
+func (r *alertRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	q := fmt.Sprintf("%s WHERE alertid = $1;", selectAlert)
+
+	row := r.DB.QueryRowContext(ctx, q, ID)
+
+	a, err := scanAlert(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// GetByPortfolioID returns the alerts of a portfolio, newest first
+func (r *alertRepository) GetByPortfolioID(ctx context.Context, portfolioID string) ([]interface{}, error) {
+	q := fmt.Sprintf("%s WHERE portfolioid = $1 ORDER BY triggered_at DESC;", selectAlert)
+
+	rows, err := r.DB.QueryContext(ctx, q, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanAlerts(rows)
+}
+
+// GetPending returns the alerts not delivered yet, oldest first
+func (r *alertRepository) GetPending(ctx context.Context) ([]interface{}, error) {
+	q := fmt.Sprintf("%s WHERE notified_at IS NULL ORDER BY triggered_at;", selectAlert)
+
+	rows, err := r.DB.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanAlerts(rows)
+}
+
+// MarkNotified records when an alert was delivered
+func (r *alertRepository) MarkNotified(ctx context.Context, ID string, notifiedAt time.Time) error {
+	q := `UPDATE alert set notified_at=$1 WHERE alertid=$2;`
+
+	result, err := r.DB.ExecContext(ctx, q, notifiedAt, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *alertRepository) Update(ctx context.Context, ID string, alert interface{}) error {
+	a := alert.(entities.Alert)
+	return r.MarkNotified(ctx, ID, a.NotifiedAt)
+}
+
+func (r *alertRepository) Delete(ctx context.Context, ID string) error {
+	q := `DELETE FROM alert WHERE alertid=$1;`
+
+	result, err := r.DB.ExecContext(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// scanner is implemented by both sql.Row and sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAlert scans an alert selected with selectAlert
+func scanAlert(row scanner) (entities.Alert, error) {
+	var a entities.Alert
+	var notifiedAt sql.NullTime
+	err := row.Scan(&a.AlertID, &a.AlertRuleID, &a.PortfolioID, &a.InstrumentID, &a.InstrumentType, &a.Condition, &a.Threshold, &a.Price.Amount, &a.Price.Currency, &a.Value, &a.TriggerDate, &a.TriggeredAt, &notifiedAt)
+	if err != nil {
+		return entities.Alert{}, err
+	}
+	a.NotifiedAt = notifiedAt.Time
+	return a, nil
+}
+
+// scanAlerts scans the alerts selected with selectAlert, returning a non existent error when there are none
+func scanAlerts(rows *sql.Rows) ([]interface{}, error) {
+	defer rows.Close()
+
+	var alerts []interface{}
+	for rows.Next() {
+		a, err := scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, &a)
+	}
+
+	if len(alerts) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return alerts, nil
+}
+
+// nullTime returns a null timestamp for a zero time
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
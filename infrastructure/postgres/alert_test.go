@@ -0,0 +1,266 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// alertRuleColumns are the columns selected for an alert rule
+var alertRuleColumns = []string{"alert_ruleid", "portfolioid", "instrumentid", "instrument_type", "condition", "threshold", "active", "created_at", "updated_at"}
+
+// alertColumns are the columns selected for an alert
+var alertColumns = []string{"alertid", "alert_ruleid", "portfolioid", "instrumentid", "instrument_type", "condition", "threshold", "price", "currency", "value", "trigger_date", "triggered_at", "notified_at"}
+
+// TestNewAlertRuleRepository_Ok checks that NewAlertRuleRepository creates a new alertRuleRepository struct
+func TestNewAlertRuleRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewAlertRuleRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestCreateAlertRule_Ok checks that Create stores a rule and returns the new ID
+func TestCreateAlertRule_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &alertRuleRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	rule := entities.AlertRule{PortfolioID: "portfolio-a", InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Condition: entities.AlertConditionAbove, Threshold: decimal.NewFromInt(200), Active: true}
+	expectedID := "f8352727-231e-4de1-8257-c235a0af5c4a"
+	mock.ExpectQuery("INSERT INTO alert_rule").
+		WithArgs("portfolio-a", "stock-a", entities.EntityNameStock, entities.AlertConditionAbove, "200", true, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"alert_ruleid"}).AddRow(expectedID))
+
+	// Act
+	id, err := repo.Create(context.Background(), rule)
+
+	// Assert
+	assert.Equal(t, expectedID, id)
+	assert.Nil(t, err)
+}
+
+// TestGetAlertRulesByPortfolioID_Ok checks that GetByPortfolioID returns the rules of a portfolio
+func TestGetAlertRulesByPortfolioID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &alertRuleRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	createdAt := time.Date(2023, time.August, 4, 20, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT (.+) FROM alert_rule WHERE portfolioid = (.+) ORDER BY created_at").WithArgs("portfolio-a").WillReturnRows(sqlmock.NewRows(alertRuleColumns).
+		AddRow("rule-a", "portfolio-a", "stock-a", entities.EntityNameStock, entities.AlertConditionDayMove, "-5", true, createdAt, createdAt))
+
+	// Act
+	result, err := repo.GetByPortfolioID(context.Background(), "portfolio-a")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+	rule := result[0].(*entities.AlertRule)
+	assert.Equal(t, "rule-a", rule.AlertRuleID)
+	assert.Equal(t, "-5", rule.Threshold.String())
+	assert.True(t, rule.Active)
+}
+
+// TestGetAlertRuleByID_NotFound checks that GetByID returns a non existent error when the rule does not exist
+func TestGetAlertRuleByID_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &alertRuleRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM alert_rule WHERE alert_ruleid").WithArgs("rule-a").WillReturnError(sql.ErrNoRows)
+
+	// Act
+	_, err := repo.GetByID(context.Background(), "rule-a")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
+
+// TestUpdateAlertRule_NotFound checks that Update returns a non existent error when no rule is updated
+func TestUpdateAlertRule_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &alertRuleRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("UPDATE alert_rule").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.Update(context.Background(), "rule-a", entities.AlertRule{Condition: entities.AlertConditionAbove, Threshold: decimal.NewFromInt(1)})
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
+
+// TestNewAlertRepository_Ok checks that NewAlertRepository creates a new alertRepository struct
+func TestNewAlertRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewAlertRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestCreateUniqueAlert_Created checks that CreateUnique stores an alert not delivered yet and reports it was created
+func TestCreateUniqueAlert_Created(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &alertRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	triggeredAt := time.Date(2023, time.August, 4, 20, 0, 0, 0, time.UTC)
+	alert := entities.Alert{AlertRuleID: "rule-a", PortfolioID: "portfolio-a", InstrumentID: "stock-a", InstrumentType: entities.EntityNameStock, Condition: entities.AlertConditionAbove, Threshold: decimal.NewFromInt(180), Price: entities.NewMoney(decimal.NewFromInt(181), "USD"), Value: decimal.NewFromInt(181), TriggerDate: time.Date(2023, time.August, 4, 0, 0, 0, 0, time.UTC), TriggeredAt: triggeredAt}
+	expectedID := "f8352727-231e-4de1-8257-c235a0af5c4a"
+	mock.ExpectQuery("INSERT INTO alert (.+) ON CONFLICT \\(alert_ruleid, trigger_date\\) DO NOTHING").
+		WithArgs("rule-a", "portfolio-a", "stock-a", entities.EntityNameStock, entities.AlertConditionAbove, "180", "181", "USD", "181", alert.TriggerDate, triggeredAt, sql.NullTime{}).
+		WillReturnRows(sqlmock.NewRows([]string{"alertid"}).AddRow(expectedID))
+
+	// Act
+	id, created, err := repo.CreateUnique(context.Background(), alert)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, created)
+	assert.Equal(t, expectedID, id)
+}
+
+// TestCreateUniqueAlert_AlreadyTriggered checks that CreateUnique reports the alert was not created when the rule already triggered that day
+func TestCreateUniqueAlert_AlreadyTriggered(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &alertRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("INSERT INTO alert").WillReturnRows(sqlmock.NewRows([]string{"alertid"}))
+
+	// Act
+	id, created, err := repo.CreateUnique(context.Background(), entities.Alert{AlertRuleID: "rule-a"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.False(t, created)
+	assert.Empty(t, id)
+}
+
+// TestGetPendingAlerts_Ok checks that GetPending returns the alerts not delivered yet
+func TestGetPendingAlerts_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &alertRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	triggeredAt := time.Date(2023, time.August, 4, 20, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT (.+) FROM alert WHERE notified_at IS NULL ORDER BY triggered_at").WillReturnRows(sqlmock.NewRows(alertColumns).
+		AddRow("alert-a", "rule-a", "portfolio-a", "stock-a", entities.EntityNameStock, entities.AlertConditionAbove, "180", "181", "USD", "181", time.Date(2023, time.August, 4, 0, 0, 0, 0, time.UTC), triggeredAt, nil))
+
+	// Act
+	result, err := repo.GetPending(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+	alert := result[0].(*entities.Alert)
+	assert.Equal(t, "alert-a", alert.AlertID)
+	assert.Equal(t, "181 USD", alert.Price.String())
+	assert.True(t, alert.NotifiedAt.IsZero())
+}
+
+// TestGetPendingAlerts_NotFound checks that GetPending returns a non existent error when every alert was delivered
+func TestGetPendingAlerts_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &alertRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM alert WHERE notified_at IS NULL").WillReturnRows(sqlmock.NewRows(alertColumns))
+
+	// Act
+	_, err := repo.GetPending(context.Background())
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
+
+// TestMarkAlertNotified_Ok checks that MarkNotified records when the alert was delivered
+func TestMarkAlertNotified_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &alertRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	notifiedAt := time.Date(2023, time.August, 4, 20, 5, 0, 0, time.UTC)
+	mock.ExpectExec("UPDATE alert set notified_at").WithArgs(notifiedAt, "alert-a").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.MarkNotified(context.Background(), "alert-a", notifiedAt)
+
+	// Assert
+	assert.Nil(t, err)
+}
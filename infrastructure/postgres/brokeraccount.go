@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// brokerAccountRepository adapter of a broker account repository for postgres
+type brokerAccountRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewBrokerAccountRepository creates a broker account repository for postgres
+func NewBrokerAccountRepository(db *sql.DB) ports.BrokerAccountRepository {
+	return &brokerAccountRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+// selectBrokerAccount selects the columns of a broker account in the order scanned by scanBrokerAccount
+const selectBrokerAccount = `SELECT broker_accountid, userid, brokerid, account_label, account_number, base_currency, opened_at, closed_at, created_at, updated_at FROM broker_account`
+
+func (r *brokerAccountRepository) Create(ctx context.Context, account interface{}) (string, error) {
+	q := `
+	INSERT INTO broker_account (userid, brokerid, account_label, account_number, base_currency, opened_at, closed_at, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        RETURNING broker_accountid;
+    `
+
+	a := account.(entities.BrokerAccount)
+	row := r.DB.QueryRowContext(
+		ctx, q, a.UserID, a.BrokerID, a.AccountLabel, nullString(a.AccountNumber), a.BaseCurrency, a.OpenedAt, nullTime(a.ClosedAt), a.CreatedAt, a.UpdatedAt,
+	)
+
+	err := row.Scan(&a.BrokerAccountID)
+	if err != nil {
+		return "", err
+	}
+
+	return a.BrokerAccountID, nil
+}
+
+func (r *brokerAccountRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	var where string
+	for k, v := range filter {
+		if where == "" {
+			where = "WHERE"
+		} else {
+			where = fmt.Sprintf("%s AND", where)
+		}
+		where = fmt.Sprintf("%s %s = '%v'", where, k, v)
+	}
+	if skip != nil {
+		where = fmt.Sprintf("%s OFFSET %d", where, *skip)
+	}
+	if take != nil {
+		where = fmt.Sprintf("%s LIMIT %d", where, *take)
+	}
+
+	q := fmt.Sprintf("%s %s;", selectBrokerAccount, where)
+
+	rows, err := r.DB.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanBrokerAccounts(rows)
+}
+
+func (r *brokerAccountRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	q := fmt.Sprintf("%s WHERE broker_accountid = $1;", selectBrokerAccount)
+
+	row := r.DB.QueryRowContext(ctx, q, ID)
+
+	a, err := scanBrokerAccount(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// GetByUserID returns the broker accounts of a user, oldest first
+func (r *brokerAccountRepository) GetByUserID(ctx context.Context, userID string) ([]interface{}, error) {
+	q := fmt.Sprintf("%s WHERE userid = $1 ORDER BY opened_at, created_at;", selectBrokerAccount)
+
+	rows, err := r.DB.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanBrokerAccounts(rows)
+}
+
+func (r *brokerAccountRepository) Update(ctx context.Context, ID string, account interface{}) error {
+	q := `
+	UPDATE broker_account set account_label=$1, account_number=$2, base_currency=$3, opened_at=$4, closed_at=$5, updated_at=$6
+	    WHERE broker_accountid=$7;
+	`
+
+	a := account.(entities.BrokerAccount)
+	result, err := r.DB.ExecContext(
+		ctx, q, a.AccountLabel, nullString(a.AccountNumber), a.BaseCurrency, a.OpenedAt, nullTime(a.ClosedAt), a.UpdatedAt, ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *brokerAccountRepository) Delete(ctx context.Context, ID string) error {
+	q := `DELETE FROM broker_account WHERE broker_accountid=$1;`
+
+	result, err := r.DB.ExecContext(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// scanBrokerAccount scans a broker account selected with selectBrokerAccount, whose account number is empty and closing date zero
+// when null
+func scanBrokerAccount(row scanner) (entities.BrokerAccount, error) {
+	var a entities.BrokerAccount
+	var accountNumber sql.NullString
+	var closedAt sql.NullTime
+	err := row.Scan(&a.BrokerAccountID, &a.UserID, &a.BrokerID, &a.AccountLabel, &accountNumber, &a.BaseCurrency, &a.OpenedAt, &closedAt, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return entities.BrokerAccount{}, err
+	}
+	a.AccountNumber = accountNumber.String
+	a.ClosedAt = closedAt.Time
+	return a, nil
+}
+
+// scanBrokerAccounts scans the broker accounts selected with selectBrokerAccount, returning a non existent error when there are none
+func scanBrokerAccounts(rows *sql.Rows) ([]interface{}, error) {
+	defer rows.Close()
+
+	var accounts []interface{}
+	for rows.Next() {
+		a, err := scanBrokerAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, &a)
+	}
+
+	if len(accounts) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return accounts, nil
+}
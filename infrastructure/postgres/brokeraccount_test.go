@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// brokerAccountColumns are the columns selected for a broker account
+var brokerAccountColumns = []string{"broker_accountid", "userid", "brokerid", "account_label", "account_number", "base_currency", "opened_at", "closed_at", "created_at", "updated_at"}
+
+// TestNewBrokerAccountRepository_Ok checks that NewBrokerAccountRepository creates a new brokerAccountRepository struct
+func TestNewBrokerAccountRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewBrokerAccountRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestCreateBrokerAccount_Ok checks that Create stores an open account, with a null closing date, and returns the new ID
+func TestCreateBrokerAccount_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &brokerAccountRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	openedAt := time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)
+	account := entities.BrokerAccount{UserID: "user-a", BrokerID: "broker-a", AccountLabel: "ISA", AccountNumber: "****5678", BaseCurrency: "GBP", OpenedAt: openedAt}
+	expectedID := "f8352727-231e-4de1-8257-c235a0af5c4a"
+	mock.ExpectQuery("INSERT INTO broker_account").
+		WithArgs("user-a", "broker-a", "ISA", sql.NullString{String: "****5678", Valid: true}, "GBP", openedAt, sql.NullTime{}, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"broker_accountid"}).AddRow(expectedID))
+
+	// Act
+	id, err := repo.Create(context.Background(), account)
+
+	// Assert
+	assert.Equal(t, expectedID, id)
+	assert.Nil(t, err)
+}
+
+// TestGetBrokerAccountsByUserID_Ok checks that GetByUserID returns the accounts of a user, with an empty number and zero closing
+// date when they are null
+func TestGetBrokerAccountsByUserID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &brokerAccountRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	openedAt := time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)
+	expectedAccount := entities.BrokerAccount{BrokerAccountID: "account-a", UserID: "user-a", BrokerID: "broker-a", AccountLabel: "ISA", BaseCurrency: "GBP", OpenedAt: openedAt, CreatedAt: openedAt, UpdatedAt: openedAt}
+	mock.ExpectQuery("SELECT (.+) FROM broker_account WHERE userid = (.+) ORDER BY opened_at").WithArgs("user-a").WillReturnRows(sqlmock.NewRows(brokerAccountColumns).
+		AddRow("account-a", "user-a", "broker-a", "ISA", nil, "GBP", openedAt, nil, openedAt, openedAt))
+
+	// Act
+	result, err := repo.GetByUserID(context.Background(), "user-a")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, expectedAccount, *(result[0].(*entities.BrokerAccount)))
+}
+
+// TestGetBrokerAccountsByUserID_NoResourcesFound checks that GetByUserID returns an error when the user has no accounts
+func TestGetBrokerAccountsByUserID_NoResourcesFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &brokerAccountRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM broker_account").WillReturnRows(sqlmock.NewRows(brokerAccountColumns))
+
+	// Act
+	_, err := repo.GetByUserID(context.Background(), "user-a")
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
+}
+
+// TestGetBrokerAccountByID_NotFound checks that GetByID returns a non existent error when the account does not exist
+func TestGetBrokerAccountByID_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &brokerAccountRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM broker_account WHERE broker_accountid").WithArgs("account-a").WillReturnError(sql.ErrNoRows)
+
+	// Act
+	_, err := repo.GetByID(context.Background(), "account-a")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
+
+// TestUpdateBrokerAccount_Ok checks that Update stores the closing date of an account
+func TestUpdateBrokerAccount_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &brokerAccountRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	openedAt := time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)
+	closedAt := time.Date(2023, time.June, 30, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec("UPDATE broker_account").
+		WithArgs("ISA", sql.NullString{}, "GBP", openedAt, sql.NullTime{Time: closedAt, Valid: true}, sqlmock.AnyArg(), "account-a").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.Update(context.Background(), "account-a", entities.BrokerAccount{AccountLabel: "ISA", BaseCurrency: "GBP", OpenedAt: openedAt, ClosedAt: closedAt})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestDeleteBrokerAccount_NotFound checks that Delete returns a non existent error when no account is deleted
+func TestDeleteBrokerAccount_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &brokerAccountRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("DELETE FROM broker_account").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.Delete(context.Background(), "account-a")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
@@ -0,0 +1,208 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// calendarFeedRepository adapter of a calendar feed repository for postgres
+type calendarFeedRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewCalendarFeedRepository creates a calendar feed repository for postgres
+func NewCalendarFeedRepository(db *sql.DB) ports.CalendarFeedRepository {
+	return &calendarFeedRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+// selectCalendarFeed selects the columns of a calendar feed in the order scanned by scanCalendarFeed
+const selectCalendarFeed = `SELECT calendar_feedid, userid, token_hash, created_at, updated_at FROM calendar_feed`
+
+func (r *calendarFeedRepository) Create(ctx context.Context, feed interface{}) (string, error) {
+	q := `
+	INSERT INTO calendar_feed (userid, token_hash, created_at, updated_at)
+        VALUES ($1, $2, $3, $4)
+        RETURNING calendar_feedid;
+    `
+
+	f := feed.(entities.CalendarFeed)
+	row := r.DB.QueryRowContext(ctx, q, f.UserID, f.TokenHash, f.CreatedAt, f.UpdatedAt)
+
+	err := row.Scan(&f.CalendarFeedID)
+	if err != nil {
+		return "", err
+	}
+
+	return f.CalendarFeedID, nil
+}
+
+// Upsert stores the calendar feed of a user, replacing the token of the feed the user already has
+func (r *calendarFeedRepository) Upsert(ctx context.Context, feed interface{}) (string, error) {
+	q := `
+	INSERT INTO calendar_feed (userid, token_hash, created_at, updated_at)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (userid) DO UPDATE SET token_hash=EXCLUDED.token_hash, updated_at=EXCLUDED.updated_at
+        RETURNING calendar_feedid;
+    `
+
+	f := feed.(entities.CalendarFeed)
+	row := r.DB.QueryRowContext(ctx, q, f.UserID, f.TokenHash, f.CreatedAt, f.UpdatedAt)
+
+	err := row.Scan(&f.CalendarFeedID)
+	if err != nil {
+		return "", err
+	}
+
+	return f.CalendarFeedID, nil
+}
+
+func (r *calendarFeedRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	var where string
+	for k, v := range filter {
+		if where == "" {
+			where = "WHERE"
+		} else {
+			where = fmt.Sprintf("%s AND", where)
+		}
+		where = fmt.Sprintf("%s %s = '%v'", where, k, v)
+	}
+	if skip != nil {
+		where = fmt.Sprintf("%s OFFSET %d", where, *skip)
+	}
+	if take != nil {
+		where = fmt.Sprintf("%s LIMIT %d", where, *take)
+	}
+
+	q := fmt.Sprintf("%s %s;", selectCalendarFeed, where)
+
+	rows, err := r.DB.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var feeds []interface{}
+	for rows.Next() {
+		f, err := scanCalendarFeed(rows)
+		if err != nil {
+			return nil, err
+		}
+		feeds = append(feeds, &f)
+	}
+
+	if len(feeds) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return feeds, nil
+}
+
+func (r *calendarFeedRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	q := fmt.Sprintf("%s WHERE calendar_feedid = $1;", selectCalendarFeed)
+
+	row := r.DB.QueryRowContext(ctx, q, ID)
+
+	f, err := scanCalendarFeed(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// GetByTokenHash returns the calendar feed whose token has the given SHA-256 hash
+func (r *calendarFeedRepository) GetByTokenHash(ctx context.Context, tokenHash string) (interface{}, error) {
+	q := fmt.Sprintf("%s WHERE token_hash = $1;", selectCalendarFeed)
+
+	row := r.DB.QueryRowContext(ctx, q, tokenHash)
+
+	f, err := scanCalendarFeed(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+func (r *calendarFeedRepository) Update(ctx context.Context, ID string, feed interface{}) error {
+	q := `
+	UPDATE calendar_feed set token_hash=$1, updated_at=$2
+	    WHERE calendar_feedid=$3;
+	`
+
+	f := feed.(entities.CalendarFeed)
+	result, err := r.DB.ExecContext(ctx, q, f.TokenHash, f.UpdatedAt, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *calendarFeedRepository) Delete(ctx context.Context, ID string) error {
+	q := `DELETE FROM calendar_feed WHERE calendar_feedid=$1;`
+
+	result, err := r.DB.ExecContext(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// DeleteByUserID deletes the calendar feed of a user
+func (r *calendarFeedRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	q := `DELETE FROM calendar_feed WHERE userid=$1;`
+
+	result, err := r.DB.ExecContext(ctx, q, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// scanCalendarFeed scans a calendar feed selected with selectCalendarFeed
+func scanCalendarFeed(row scanner) (entities.CalendarFeed, error) {
+	var f entities.CalendarFeed
+	err := row.Scan(&f.CalendarFeedID, &f.UserID, &f.TokenHash, &f.CreatedAt, &f.UpdatedAt)
+	return f, err
+}
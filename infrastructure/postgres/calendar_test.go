@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestNewCalendarFeedRepository_Ok checks that NewCalendarFeedRepository creates a new calendarFeedRepository struct
+func TestNewCalendarFeedRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewCalendarFeedRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestUpsertCalendarFeed_Ok checks that Upsert replaces the token of the feed of the user and returns its ID
+func TestUpsertCalendarFeed_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &calendarFeedRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	feed := entities.CalendarFeed{UserID: "user-a", TokenHash: "hash-a", CreatedAt: now, UpdatedAt: now}
+	expectedID := "9c7e0f4a-3b1d-4e52-8f6a-1d2c3b4a5e6f"
+	mock.ExpectQuery("INSERT INTO calendar_feed (.+) ON CONFLICT \\(userid\\) DO UPDATE").
+		WithArgs("user-a", "hash-a", now, now).
+		WillReturnRows(sqlmock.NewRows([]string{"calendar_feedid"}).AddRow(expectedID))
+
+	// Act
+	id, err := repo.Upsert(context.Background(), feed)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedID, id)
+}
+
+// TestGetCalendarFeedByTokenHash_Ok checks that GetByTokenHash returns the feed whose token has the hash
+func TestGetCalendarFeedByTokenHash_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &calendarFeedRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	expectedFeed := &entities.CalendarFeed{CalendarFeedID: "feed-a", UserID: "user-a", TokenHash: "hash-a", CreatedAt: now, UpdatedAt: now}
+	mock.ExpectQuery("SELECT (.+) FROM calendar_feed WHERE token_hash = \\$1").
+		WithArgs("hash-a").
+		WillReturnRows(sqlmock.NewRows([]string{"calendar_feedid", "userid", "token_hash", "created_at", "updated_at"}).AddRow("feed-a", "user-a", "hash-a", now, now))
+
+	// Act
+	feed, err := repo.GetByTokenHash(context.Background(), "hash-a")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedFeed, feed)
+}
+
+// TestGetCalendarFeedByTokenHash_NotFound checks that GetByTokenHash returns a non existent error when no feed has the hash
+func TestGetCalendarFeedByTokenHash_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &calendarFeedRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM calendar_feed WHERE token_hash = \\$1").
+		WithArgs("hash-a").
+		WillReturnRows(sqlmock.NewRows([]string{"calendar_feedid", "userid", "token_hash", "created_at", "updated_at"}))
+
+	// Act
+	_, err := repo.GetByTokenHash(context.Background(), "hash-a")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
+
+// TestDeleteCalendarFeedByUserID_NotFound checks that DeleteByUserID returns a non existent error when the user has no feed
+func TestDeleteCalendarFeedByUserID_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &calendarFeedRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("DELETE FROM calendar_feed WHERE userid=\\$1").
+		WithArgs("user-a").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.DeleteByUserID(context.Background(), "user-a")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
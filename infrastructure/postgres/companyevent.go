@@ -0,0 +1,256 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// companyEventRepository adapter of a company event repository for postgres
+type companyEventRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewCompanyEventRepository creates a company event repository for postgres
+func NewCompanyEventRepository(db *sql.DB) ports.CompanyEventRepository {
+	return &companyEventRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+// selectCompanyEvent selects the columns of a company event in the order scanned by scanCompanyEvent
+const selectCompanyEvent = `SELECT company_eventid, stockid, event_type, event_date, amount, currency, split_numerator, split_denominator, description, source, created_at, updated_at FROM company_event`
+
+func (r *companyEventRepository) Create(ctx context.Context, event interface{}) (string, error) {
+	q := `
+	INSERT INTO company_event (stockid, event_type, event_date, amount, currency, split_numerator, split_denominator, description, source, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        RETURNING company_eventid;
+    `
+
+	e := event.(entities.CompanyEvent)
+	row := r.DB.QueryRowContext(
+		ctx, q, e.StockID, e.EventType, e.EventDate, e.Amount.Amount, e.Amount.Currency, e.SplitNumerator, e.SplitDenominator, e.Description, e.Source, e.CreatedAt, e.UpdatedAt,
+	)
+
+	err := row.Scan(&e.CompanyEventID)
+	if err != nil {
+		return "", err
+	}
+
+	return e.CompanyEventID, nil
+}
+
+// Upsert stores an event, replacing the event of the same stock and type already stored for the same day
+func (r *companyEventRepository) Upsert(ctx context.Context, event interface{}) (string, error) {
+	q := `
+	INSERT INTO company_event (stockid, event_type, event_date, amount, currency, split_numerator, split_denominator, description, source, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        ON CONFLICT (stockid, event_type, event_date) DO UPDATE SET amount=EXCLUDED.amount, currency=EXCLUDED.currency, split_numerator=EXCLUDED.split_numerator,
+            split_denominator=EXCLUDED.split_denominator, description=EXCLUDED.description, source=EXCLUDED.source, updated_at=EXCLUDED.updated_at
+        RETURNING company_eventid;
+    `
+
+	e := event.(entities.CompanyEvent)
+	row := r.DB.QueryRowContext(
+		ctx, q, e.StockID, e.EventType, e.EventDate, e.Amount.Amount, e.Amount.Currency, e.SplitNumerator, e.SplitDenominator, e.Description, e.Source, e.CreatedAt, e.UpdatedAt,
+	)
+
+	err := row.Scan(&e.CompanyEventID)
+	if err != nil {
+		return "", err
+	}
+
+	return e.CompanyEventID, nil
+}
+
+func (r *companyEventRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	var where string
+	for k, v := range filter {
+		if where == "" {
+			where = "WHERE"
+		} else {
+			where = fmt.Sprintf("%s AND", where)
+		}
+		where = fmt.Sprintf("%s %s = '%v'", where, k, v)
+	}
+	if skip != nil {
+		where = fmt.Sprintf("%s OFFSET %d", where, *skip)
+	}
+	if take != nil {
+		where = fmt.Sprintf("%s LIMIT %d", where, *take)
+	}
+
+	q := fmt.Sprintf("%s %s;", selectCompanyEvent, where)
+
+	rows, err := r.DB.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanCompanyEvents(rows)
+}
+
+func (r *companyEventRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	q := fmt.Sprintf("%s WHERE company_eventid = $1;", selectCompanyEvent)
+
+	row := r.DB.QueryRowContext(ctx, q, ID)
+
+	e, err := scanCompanyEvent(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// GetByStockID returns the events of a stock between two dates, both included and unbounded when zero, oldest first
+func (r *companyEventRepository) GetByStockID(ctx context.Context, stockID string, from, to time.Time) ([]interface{}, error) {
+	conditions := []string{"stockid = $1"}
+	args := []interface{}{stockID}
+	if !from.IsZero() {
+		args = append(args, from)
+		conditions = append(conditions, fmt.Sprintf("event_date >= $%d", len(args)))
+	}
+	if !to.IsZero() {
+		args = append(args, to)
+		conditions = append(conditions, fmt.Sprintf("event_date <= $%d", len(args)))
+	}
+
+	q := fmt.Sprintf("%s WHERE %s ORDER BY event_date, event_type;", selectCompanyEvent, strings.Join(conditions, " AND "))
+
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanCompanyEvents(rows)
+}
+
+// GetRange returns the events of every stock between two dates, both included, oldest first
+func (r *companyEventRepository) GetRange(ctx context.Context, from, to time.Time) ([]interface{}, error) {
+	q := fmt.Sprintf("%s WHERE event_date >= $1 AND event_date <= $2 ORDER BY event_date, stockid, event_type;", selectCompanyEvent)
+
+	rows, err := r.DB.QueryContext(ctx, q, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanCompanyEvents(rows)
+}
+
+// GetNotifiedPortfolios returns the IDs of the portfolios already notified about an event
+func (r *companyEventRepository) GetNotifiedPortfolios(ctx context.Context, eventID string) ([]string, error) {
+	q := `SELECT portfolioid FROM company_event_notification WHERE company_eventid = $1;`
+
+	rows, err := r.DB.QueryContext(ctx, q, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var portfolioIDs []string
+	for rows.Next() {
+		var portfolioID string
+		if err := rows.Scan(&portfolioID); err != nil {
+			return nil, err
+		}
+		portfolioIDs = append(portfolioIDs, portfolioID)
+	}
+
+	return portfolioIDs, rows.Err()
+}
+
+// MarkNotified records that a portfolio was notified about an event
+func (r *companyEventRepository) MarkNotified(ctx context.Context, eventID, portfolioID string, notifiedAt time.Time) error {
+	q := `
+	INSERT INTO company_event_notification (company_eventid, portfolioid, notified_at)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (company_eventid, portfolioid) DO NOTHING;
+    `
+
+	_, err := r.DB.ExecContext(ctx, q, eventID, portfolioID, notifiedAt)
+	return err
+}
+
+func (r *companyEventRepository) Update(ctx context.Context, ID string, event interface{}) error {
+	q := `
+	UPDATE company_event set event_type=$1, event_date=$2, amount=$3, currency=$4, split_numerator=$5, split_denominator=$6, description=$7, source=$8, updated_at=$9
+	    WHERE company_eventid=$10;
+	`
+
+	e := event.(entities.CompanyEvent)
+	result, err := r.DB.ExecContext(
+		ctx, q, e.EventType, e.EventDate, e.Amount.Amount, e.Amount.Currency, e.SplitNumerator, e.SplitDenominator, e.Description, e.Source, e.UpdatedAt, ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *companyEventRepository) Delete(ctx context.Context, ID string) error {
+	q := `DELETE FROM company_event WHERE company_eventid=$1;`
+
+	result, err := r.DB.ExecContext(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// scanCompanyEvent scans an event selected with selectCompanyEvent
+func scanCompanyEvent(row scanner) (entities.CompanyEvent, error) {
+	var e entities.CompanyEvent
+	err := row.Scan(&e.CompanyEventID, &e.StockID, &e.EventType, &e.EventDate, &e.Amount.Amount, &e.Amount.Currency, &e.SplitNumerator, &e.SplitDenominator, &e.Description, &e.Source, &e.CreatedAt, &e.UpdatedAt)
+	return e, err
+}
+
+// scanCompanyEvents scans the events selected with selectCompanyEvent, returning a non existent error when there are none
+func scanCompanyEvents(rows *sql.Rows) ([]interface{}, error) {
+	defer rows.Close()
+
+	var events []interface{}
+	for rows.Next() {
+		e, err := scanCompanyEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+
+	if len(events) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return events, nil
+}
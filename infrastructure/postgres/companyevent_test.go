@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// companyEventColumns are the columns selected for a company event
+var companyEventColumns = []string{"company_eventid", "stockid", "event_type", "event_date", "amount", "currency", "split_numerator", "split_denominator", "description", "source", "created_at", "updated_at"}
+
+// TestNewCompanyEventRepository_Ok checks that NewCompanyEventRepository creates a new companyEventRepository struct
+func TestNewCompanyEventRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewCompanyEventRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestUpsertCompanyEvent_Ok checks that Upsert stores an event replacing the one of the same stock, type and day and returns its ID
+func TestUpsertCompanyEvent_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &companyEventRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	eventDate := time.Date(2023, time.August, 11, 0, 0, 0, 0, time.UTC)
+	event := entities.CompanyEvent{StockID: "stock-a", EventType: entities.CompanyEventExDividend, EventDate: eventDate, Amount: entities.NewMoney(decimal.RequireFromString("0.24"), "USD"), Source: "file"}
+	expectedID := "f8352727-231e-4de1-8257-c235a0af5c4a"
+	mock.ExpectQuery("INSERT INTO company_event (.+) ON CONFLICT \\(stockid, event_type, event_date\\) DO UPDATE").
+		WithArgs("stock-a", entities.CompanyEventExDividend, eventDate, "0.24", "USD", "0", "0", "", "file", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"company_eventid"}).AddRow(expectedID))
+
+	// Act
+	id, err := repo.Upsert(context.Background(), event)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedID, id)
+}
+
+// TestGetCompanyEventsByStockID_Unbounded checks that GetByStockID only filters by the stock when the dates are empty
+func TestGetCompanyEventsByStockID_Unbounded(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &companyEventRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	eventDate := time.Date(2023, time.September, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT (.+) FROM company_event WHERE stockid = \\$1 ORDER BY event_date").WithArgs("stock-a").WillReturnRows(sqlmock.NewRows(companyEventColumns).
+		AddRow("event-a", "stock-a", entities.CompanyEventSplit, eventDate, "0", "", "4", "1", "", "csv", eventDate, eventDate))
+
+	// Act
+	result, err := repo.GetByStockID(context.Background(), "stock-a", time.Time{}, time.Time{})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "4", result[0].(*entities.CompanyEvent).SplitNumerator.String())
+}
+
+// TestGetCompanyEventsByStockID_NotFound checks that GetByStockID returns a non existent error when there are no events in the range
+func TestGetCompanyEventsByStockID_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &companyEventRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	from := time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, time.August, 31, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT (.+) FROM company_event WHERE stockid = \\$1 AND event_date >= \\$2 AND event_date <= \\$3").WithArgs("stock-a", from, to).WillReturnRows(sqlmock.NewRows(companyEventColumns))
+
+	// Act
+	_, err := repo.GetByStockID(context.Background(), "stock-a", from, to)
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
+
+// TestGetNotifiedPortfolios_Ok checks that GetNotifiedPortfolios returns the IDs of the portfolios already notified about an event
+func TestGetNotifiedPortfolios_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &companyEventRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT portfolioid FROM company_event_notification").WithArgs("event-a").WillReturnRows(sqlmock.NewRows([]string{"portfolioid"}).AddRow("portfolio-a").AddRow("portfolio-b"))
+
+	// Act
+	result, err := repo.GetNotifiedPortfolios(context.Background(), "event-a")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"portfolio-a", "portfolio-b"}, result)
+}
+
+// TestMarkCompanyEventNotified_Ok checks that MarkNotified records the notification of a portfolio
+func TestMarkCompanyEventNotified_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &companyEventRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	notifiedAt := time.Date(2023, time.August, 8, 9, 0, 0, 0, time.UTC)
+	mock.ExpectExec("INSERT INTO company_event_notification (.+) ON CONFLICT").WithArgs("event-a", "portfolio-a", notifiedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.MarkNotified(context.Background(), "event-a", "portfolio-a", notifiedAt)
+
+	// Assert
+	assert.Nil(t, err)
+}
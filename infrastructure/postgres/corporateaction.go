@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// corporateActionRepository adapter of a corporate action repository for postgres
+type corporateActionRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewCorporateActionRepository creates a corporate action repository for postgres
+func NewCorporateActionRepository(db *sql.DB) ports.CorporateActionRepository {
+	return &corporateActionRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+// selectCorporateAction selects the columns of a corporate action in the order scanned by scanCorporateAction
+const selectCorporateAction = `SELECT corporate_actionid, stockid, action_type, effective_date, numerator, denominator, target_stockid, cost_allocation, old_symbol, new_symbol, new_isin, description, created_at, updated_at FROM corporate_action`
+
+func (r *corporateActionRepository) Create(ctx context.Context, action interface{}) (string, error) {
+	q := `
+	INSERT INTO corporate_action (stockid, action_type, effective_date, numerator, denominator, target_stockid, cost_allocation, old_symbol, new_symbol, new_isin, description, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+        RETURNING corporate_actionid;
+    `
+
+	a := action.(entities.CorporateAction)
+	row := r.DB.QueryRowContext(
+		ctx, q, a.StockID, a.ActionType, a.EffectiveDate, a.Numerator, a.Denominator, nullString(a.TargetStockID), a.CostAllocation, a.OldSymbol, a.NewSymbol, a.NewISIN, a.Description, a.CreatedAt, a.UpdatedAt,
+	)
+
+	err := row.Scan(&a.CorporateActionID)
+	if err != nil {
+		return "", err
+	}
+
+	return a.CorporateActionID, nil
+}
+
+func (r *corporateActionRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	var where string
+	for k, v := range filter {
+		if where == "" {
+			where = "WHERE"
+		} else {
+			where = fmt.Sprintf("%s AND", where)
+		}
+		where = fmt.Sprintf("%s %s = '%v'", where, k, v)
+	}
+	where = fmt.Sprintf("%s ORDER BY effective_date, created_at", where)
+	if skip != nil {
+		where = fmt.Sprintf("%s OFFSET %d", where, *skip)
+	}
+	if take != nil {
+		where = fmt.Sprintf("%s LIMIT %d", where, *take)
+	}
+
+	q := fmt.Sprintf("%s %s;", selectCorporateAction, where)
+
+	rows, err := r.DB.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanCorporateActions(rows)
+}
+
+func (r *corporateActionRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	q := fmt.Sprintf("%s WHERE corporate_actionid = $1;", selectCorporateAction)
+
+	row := r.DB.QueryRowContext(ctx, q, ID)
+
+	a, err := scanCorporateAction(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// GetByStockID returns the corporate actions of a stock, including the mergers and spin-offs into it, oldest first
+func (r *corporateActionRepository) GetByStockID(ctx context.Context, stockID string) ([]interface{}, error) {
+	q := fmt.Sprintf("%s WHERE stockid = $1 OR target_stockid = $1 ORDER BY effective_date, created_at;", selectCorporateAction)
+
+	rows, err := r.DB.QueryContext(ctx, q, stockID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanCorporateActions(rows)
+}
+
+func (r *corporateActionRepository) Update(ctx context.Context, ID string, action interface{}) error {
+	q := `
+	UPDATE corporate_action set action_type=$1, effective_date=$2, numerator=$3, denominator=$4, target_stockid=$5, cost_allocation=$6, old_symbol=$7, new_symbol=$8, new_isin=$9, description=$10, updated_at=$11
+	    WHERE corporate_actionid=$12;
+	`
+
+	a := action.(entities.CorporateAction)
+	result, err := r.DB.ExecContext(
+		ctx, q, a.ActionType, a.EffectiveDate, a.Numerator, a.Denominator, nullString(a.TargetStockID), a.CostAllocation, a.OldSymbol, a.NewSymbol, a.NewISIN, a.Description, a.UpdatedAt, ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *corporateActionRepository) Delete(ctx context.Context, ID string) error {
+	q := `DELETE FROM corporate_action WHERE corporate_actionid=$1;`
+
+	result, err := r.DB.ExecContext(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// scanCorporateAction scans a corporate action selected with selectCorporateAction
+func scanCorporateAction(row scanner) (entities.CorporateAction, error) {
+	var a entities.CorporateAction
+	var targetStockID sql.NullString
+	err := row.Scan(&a.CorporateActionID, &a.StockID, &a.ActionType, &a.EffectiveDate, &a.Numerator, &a.Denominator, &targetStockID, &a.CostAllocation, &a.OldSymbol, &a.NewSymbol, &a.NewISIN, &a.Description, &a.CreatedAt, &a.UpdatedAt)
+	a.TargetStockID = targetStockID.String
+	return a, err
+}
+
+// scanCorporateActions scans the corporate actions selected with selectCorporateAction, returning a non existent error when there are none
+func scanCorporateActions(rows *sql.Rows) ([]interface{}, error) {
+	defer rows.Close()
+
+	var actions []interface{}
+	for rows.Next() {
+		a, err := scanCorporateAction(rows)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, &a)
+	}
+
+	if len(actions) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return actions, nil
+}
+
+// nullString returns a null string for an empty one
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
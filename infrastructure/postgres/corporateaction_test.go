@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// corporateActionColumns are the columns selected for a corporate action
+var corporateActionColumns = []string{"corporate_actionid", "stockid", "action_type", "effective_date", "numerator", "denominator", "target_stockid", "cost_allocation", "old_symbol", "new_symbol", "new_isin", "description", "created_at", "updated_at"}
+
+// TestNewCorporateActionRepository_Ok checks that NewCorporateActionRepository creates a new corporateActionRepository struct
+func TestNewCorporateActionRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewCorporateActionRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestCreateCorporateAction_Ok checks that Create stores an action without a target stock as a null one and returns its ID
+func TestCreateCorporateAction_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &corporateActionRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	effectiveDate := time.Date(2024, time.June, 10, 0, 0, 0, 0, time.UTC)
+	action := entities.CorporateAction{StockID: "stock-a", ActionType: entities.CorporateActionSplit, EffectiveDate: effectiveDate, Numerator: decimal.NewFromInt(10), Denominator: decimal.NewFromInt(1)}
+	expectedID := "0c0c1c0e-74a7-4dc5-9c6b-2b7c1c0c4f0e"
+	mock.ExpectQuery("INSERT INTO corporate_action").
+		WithArgs("stock-a", entities.CorporateActionSplit, effectiveDate, "10", "1", nil, "0", "", "", "", "", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"corporate_actionid"}).AddRow(expectedID))
+
+	// Act
+	id, err := repo.Create(context.Background(), action)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedID, id)
+}
+
+// TestGetCorporateActionsByStockID_Ok checks that GetByStockID returns the actions of the stock and the ones into it
+func TestGetCorporateActionsByStockID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &corporateActionRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	effectiveDate := time.Date(2024, time.June, 10, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT (.+) FROM corporate_action WHERE stockid = \\$1 OR target_stockid = \\$1 ORDER BY effective_date").WithArgs("stock-b").WillReturnRows(sqlmock.NewRows(corporateActionColumns).
+		AddRow("action-a", "stock-a", entities.CorporateActionSplit, effectiveDate, "10", "1", nil, "0", "", "", "", "", effectiveDate, effectiveDate).
+		AddRow("action-b", "stock-a", entities.CorporateActionSpinOff, effectiveDate, "1", "3", "stock-b", "0.2", "", "", "", "", effectiveDate, effectiveDate))
+
+	// Act
+	result, err := repo.GetByStockID(context.Background(), "stock-b")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "", result[0].(*entities.CorporateAction).TargetStockID)
+	assert.Equal(t, "stock-b", result[1].(*entities.CorporateAction).TargetStockID)
+	assert.True(t, decimal.RequireFromString("0.2").Equal(result[1].(*entities.CorporateAction).CostAllocation))
+}
+
+// TestGetCorporateActionsByStockID_NoResourcesFound checks that GetByStockID returns a non existent error when the stock has no actions
+func TestGetCorporateActionsByStockID_NoResourcesFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &corporateActionRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM corporate_action").WithArgs("stock-a").WillReturnRows(sqlmock.NewRows(corporateActionColumns))
+
+	// Act
+	_, err := repo.GetByStockID(context.Background(), "stock-a")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
+
+// TestDeleteCorporateAction_NotDeletedError checks that Delete returns a non existent error when no action was deleted
+func TestDeleteCorporateAction_NotDeletedError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &corporateActionRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("DELETE FROM corporate_action").WithArgs("action-a").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.Delete(context.Background(), "action-a")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
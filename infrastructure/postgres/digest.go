@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// digestRepository adapter of a digest repository for postgres
+type digestRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewDigestRepository creates a digest repository for postgres
+func NewDigestRepository(db *sql.DB) ports.DigestRepository {
+	return &digestRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+// selectDigest selects the columns of a digest in the order scanned by scanDigest
+const selectDigest = `SELECT digestid, portfolioid, period, period_start, period_end, sent_to, sent_at FROM digest`
+
+func (r *digestRepository) Create(ctx context.Context, digest interface{}) (string, error) {
+	q := `
+	INSERT INTO digest (portfolioid, period, period_start, period_end, sent_to, sent_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING digestid;
+    `
+
+	d := digest.(entities.Digest)
+	row := r.DB.QueryRowContext(
+		ctx, q, d.PortfolioID, d.Period, d.PeriodStart, d.PeriodEnd, d.SentTo, d.SentAt,
+	)
+
+	err := row.Scan(&d.DigestID)
+	if err != nil {
+		return "", err
+	}
+
+	return d.DigestID, nil
+}
+
+func (r *digestRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	var where string
+	for k, v := range filter {
+		if where == "" {
+			where = "WHERE"
+		} else {
+			where = fmt.Sprintf("%s AND", where)
+		}
+		where = fmt.Sprintf("%s %s = '%v'", where, k, v)
+	}
+	if skip != nil {
+		where = fmt.Sprintf("%s OFFSET %d", where, *skip)
+	}
+	if take != nil {
+		where = fmt.Sprintf("%s LIMIT %d", where, *take)
+	}
+
+	q := fmt.Sprintf("%s %s;", selectDigest, where)
+
+	rows, err := r.DB.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var digests []interface{}
+	for rows.Next() {
+		d, err := scanDigest(rows)
+		if err != nil {
+			return nil, err
+		}
+		digests = append(digests, &d)
+	}
+
+	if len(digests) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return digests, nil
+}
+
+func (r *digestRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	q := fmt.Sprintf("%s WHERE digestid = $1;", selectDigest)
+
+	row := r.DB.QueryRowContext(ctx, q, ID)
+
+	d, err := scanDigest(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// Exists reports whether the digest of a portfolio for the period ending on a day was already sent
+func (r *digestRepository) Exists(ctx context.Context, portfolioID, period string, periodEnd time.Time) (bool, error) {
+	q := `SELECT EXISTS (SELECT 1 FROM digest WHERE portfolioid = $1 AND period = $2 AND period_end = $3);`
+
+	var exists bool
+	err := r.DB.QueryRowContext(ctx, q, portfolioID, period, periodEnd).Scan(&exists)
+	return exists, err
+}
+
+func (r *digestRepository) Update(ctx context.Context, ID string, digest interface{}) error {
+	q := `
+	UPDATE digest set sent_to=$1, sent_at=$2
+	    WHERE digestid=$3;
+	`
+
+	d := digest.(entities.Digest)
+	result, err := r.DB.ExecContext(ctx, q, d.SentTo, d.SentAt, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *digestRepository) Delete(ctx context.Context, ID string) error {
+	q := `DELETE FROM digest WHERE digestid=$1;`
+
+	result, err := r.DB.ExecContext(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// scanDigest scans a digest selected with selectDigest
+func scanDigest(row scanner) (entities.Digest, error) {
+	var d entities.Digest
+	err := row.Scan(&d.DigestID, &d.PortfolioID, &d.Period, &d.PeriodStart, &d.PeriodEnd, &d.SentTo, &d.SentAt)
+	return d, err
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestNewDigestRepository_Ok checks that NewDigestRepository creates a new digestRepository struct
+func TestNewDigestRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewDigestRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestCreateDigest_Ok checks that Create records a sent digest and returns its ID
+func TestCreateDigest_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &digestRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	from := time.Date(2023, time.August, 7, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, time.August, 13, 0, 0, 0, 0, time.UTC)
+	digest := entities.Digest{PortfolioID: "portfolio-a", Period: entities.DigestPeriodWeekly, PeriodStart: from, PeriodEnd: to, SentTo: "jane@example.com", SentAt: to.Add(time.Hour)}
+	expectedID := "2b2b1e8c-6f0e-4bd8-a3c2-0f5e3c6b6d51"
+	mock.ExpectQuery("INSERT INTO digest").
+		WithArgs("portfolio-a", entities.DigestPeriodWeekly, from, to, "jane@example.com", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"digestid"}).AddRow(expectedID))
+
+	// Act
+	id, err := repo.Create(context.Background(), digest)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedID, id)
+}
+
+// TestDigestExists_Ok checks that Exists reports whether the digest of the portfolio for the period was sent
+func TestDigestExists_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &digestRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	to := time.Date(2023, time.August, 13, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM digest WHERE portfolioid = \\$1 AND period = \\$2 AND period_end = \\$3\\)").
+		WithArgs("portfolio-a", entities.DigestPeriodWeekly, to).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	// Act
+	exists, err := repo.Exists(context.Background(), "portfolio-a", entities.DigestPeriodWeekly, to)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, exists)
+}
+
+// TestGetDigestByID_NotFound checks that GetByID returns a non existent error when the digest does not exist
+func TestGetDigestByID_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &digestRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM digest WHERE digestid = \\$1").
+		WithArgs("digest-a").
+		WillReturnRows(sqlmock.NewRows([]string{"digestid", "portfolioid", "period", "period_start", "period_end", "sent_to", "sent_at"}))
+
+	// Act
+	_, err := repo.GetByID(context.Background(), "digest-a")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
 	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
 	"github.com/tkudlicka/portflux-api/core/entities"
 	"github.com/tkudlicka/portflux-api/core/ports"
 )
@@ -28,14 +29,14 @@ func NewDividendRepository(db *sql.DB) ports.DividendRepository {
 
 func (r *dividendRepository) Create(ctx context.Context, dividend interface{}) (string, error) {
 	q := `
-	INSERT INTO dividend (stockid, dividend_per_share, dividend_date, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6)
+	INSERT INTO dividend (stockid, dividend_per_share, dividend_currency, ex_date, record_date, pay_date, import_batchid, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
         RETURNING dividendid;
     `
 
 	d := dividend.(entities.Dividend)
 	row := r.DB.QueryRowContext(
-		ctx, q, d.StockID, d.DividendPerShare, d.DividendDate, d.CreatedAt, d.UpdatedAt,
+		ctx, q, d.StockID, d.DividendPerShare.Amount, d.DividendPerShare.Currency, d.ExDate, nullTime(d.RecordDate), nullTime(d.PayDate), nullString(d.ImportBatchID), d.CreatedAt, d.UpdatedAt,
 	)
 
 	err := row.Scan(&d.DividendID)
@@ -64,7 +65,7 @@ func (r *dividendRepository) Get(ctx context.Context, filter map[string]interfac
 	}
 
 	q := fmt.Sprintf(`
-	SELECT dividendid, stockid, dividend_per_share, dividend_date, created_at, updated_at
+	SELECT dividendid, stockid, dividend_per_share, dividend_currency, ex_date, record_date, pay_date, import_batchid, created_at, updated_at
 	    FROM dividend %s;
 	`, where)
 
@@ -77,8 +78,7 @@ func (r *dividendRepository) Get(ctx context.Context, filter map[string]interfac
 
 	var dividends []interface{}
 	for rows.Next() {
-		var c entities.Dividend
-		err := rows.Scan(&c.DividendID, &c.StockID, &c.DividendPerShare, &c.DividendDate, &c.CreatedAt, &c.UpdatedAt)
+		c, err := scanDividend(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -94,14 +94,13 @@ func (r *dividendRepository) Get(ctx context.Context, filter map[string]interfac
 
 func (r *dividendRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
 	q := `
-    SELECT dividendid, stockid, dividend_per_share, dividend_date, created_at, updated_at
+    SELECT dividendid, stockid, dividend_per_share, dividend_currency, ex_date, record_date, pay_date, import_batchid, created_at, updated_at
         FROM dividend WHERE dividendid = $1;
     `
 
 	row := r.DB.QueryRowContext(ctx, q, ID)
 
-	var c entities.Dividend
-	err := row.Scan(&c.DividendID, &c.StockID, &c.DividendPerShare, &c.DividendDate, &c.CreatedAt, &c.UpdatedAt)
+	c, err := scanDividend(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = wrappers.NewNonExistentErr(err)
@@ -112,16 +111,59 @@ func (r *dividendRepository) GetByID(ctx context.Context, ID string) (interface{
 	return &c, nil
 }
 
+// StreamByPortfolioID calls fn with each dividend of the stocks traded in a portfolio that the portfolio held shares of on its
+// ex-date, with the tax recorded as withheld from the payment to the portfolio, in the order of their ex-dates, reading them one
+// at a time. It stops at the first error returned by fn.
+func (r *dividendRepository) StreamByPortfolioID(ctx context.Context, portfolioID string, fn func(entities.PortfolioDividend) error) error {
+	q := `
+    SELECT * FROM (
+        SELECT d.dividendid, d.stockid, d.dividend_per_share, d.dividend_currency, d.ex_date, d.record_date, d.pay_date, d.import_batchid, d.created_at, d.updated_at,
+            (SELECT COALESCE(SUM(t.quantity), 0)
+                FROM transaction t INNER JOIN holding h ON h.holdingid = t.holdingid
+                WHERE h.portfolioid = $1 AND t.stockid = d.stockid AND t.transaction_date < d.ex_date) AS shares,
+            w.amount AS withholding, w.currency AS withholding_currency
+            FROM dividend d
+            LEFT JOIN dividend_withholding w ON w.dividendid = d.dividendid AND w.portfolioid = $1
+            WHERE d.stockid IN (SELECT t.stockid FROM transaction t INNER JOIN holding h ON h.holdingid = t.holdingid WHERE h.portfolioid = $1)
+    ) d WHERE d.shares > 0
+        ORDER BY d.ex_date, d.stockid;
+    `
+
+	rows, err := r.DB.QueryContext(ctx, q, portfolioID)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var d entities.PortfolioDividend
+		var withholding decimal.NullDecimal
+		var withholdingCurrency sql.NullString
+		d.Dividend, err = scanDividend(rows, &d.Shares, &withholding, &withholdingCurrency)
+		if err != nil {
+			return err
+		}
+		if withholding.Valid {
+			d.Withholding = entities.NewMoney(withholding.Decimal, withholdingCurrency.String)
+		}
+		if err := fn(d); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 func (r *dividendRepository) GetBySymbol(ctx context.Context, Symbol string) (interface{}, error) {
 	q := `
-    SELECT dividendid, stockid, dividend_per_share, dividend_date, created_at, updated_at
+    SELECT dividendid, stockid, dividend_per_share, dividend_currency, ex_date, record_date, pay_date, import_batchid, created_at, updated_at
 	FROM dividend WHERE symbol = $1;
     `
 
 	row := r.DB.QueryRowContext(ctx, q, Symbol)
 
-	var c entities.Dividend
-	err := row.Scan(&c.DividendID, &c.StockID, &c.DividendPerShare, &c.DividendDate, &c.CreatedAt, &c.UpdatedAt)
+	c, err := scanDividend(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = wrappers.NewNonExistentErr(err)
@@ -134,14 +176,13 @@ func (r *dividendRepository) GetBySymbol(ctx context.Context, Symbol string) (in
 
 func (r *dividendRepository) GetByCode(ctx context.Context, Code string) (interface{}, error) {
 	q := `
-    SELECT dividendid, stockid, dividend_per_share, dividend_date, created_at, updated_at
+    SELECT dividendid, stockid, dividend_per_share, dividend_currency, ex_date, record_date, pay_date, import_batchid, created_at, updated_at
         FROM dividend WHERE code = $1;
     `
 
 	row := r.DB.QueryRowContext(ctx, q, Code)
 
-	var c entities.Dividend
-	err := row.Scan(&c.DividendID, &c.StockID, &c.DividendPerShare, &c.DividendDate, &c.CreatedAt, &c.UpdatedAt)
+	c, err := scanDividend(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = wrappers.NewNonExistentErr(err)
@@ -154,13 +195,13 @@ func (r *dividendRepository) GetByCode(ctx context.Context, Code string) (interf
 
 func (r *dividendRepository) Update(ctx context.Context, ID string, dividend interface{}) error {
 	q := `
-	UPDATE dividend set dividend_per_share=$1, dividend_date=$2, updated_at=$3
-	    WHERE dividendid=$4;
+	UPDATE dividend set dividend_per_share=$1, dividend_currency=$2, ex_date=$3, record_date=$4, pay_date=$5, updated_at=$6
+	    WHERE dividendid=$7;
 	`
 
 	b := dividend.(entities.Dividend)
 	result, err := r.DB.ExecContext(
-		ctx, q, b.DividendPerShare, b.DividendDate, b.UpdatedAt, ID,
+		ctx, q, b.DividendPerShare.Amount, b.DividendPerShare.Currency, b.ExDate, nullTime(b.RecordDate), nullTime(b.PayDate), b.UpdatedAt, ID,
 	)
 	if err != nil {
 		return err
@@ -206,13 +247,13 @@ func (r *dividendRepository) CreateMany(ctx context.Context, dividend_models []i
 		d := entity.(entities.Dividend)
 
 		q := `
-		INSERT INTO dividend (stockid, dividend_per_share, dividend_date, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO dividend (stockid, dividend_per_share, dividend_currency, ex_date, record_date, pay_date, import_batchid, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 			RETURNING dividendid;`
 
 		// Here, the query is executed on the transaction instance, and not applied to the database yet
 		row := tx.QueryRowContext(
-			ctx, q, d.StockID, d.DividendPerShare, d.DividendDate, d.CreatedAt, d.UpdatedAt,
+			ctx, q, d.StockID, d.DividendPerShare.Amount, d.DividendPerShare.Currency, d.ExDate, nullTime(d.RecordDate), nullTime(d.PayDate), nullString(d.ImportBatchID), d.CreatedAt, d.UpdatedAt,
 		)
 		err := row.Scan(&d.DividendID)
 		if err != nil {
@@ -230,3 +271,20 @@ func (r *dividendRepository) CreateMany(ctx context.Context, dividend_models []i
 	}
 	return result, nil
 }
+
+// scanDividend scans a dividend, whose record and pay dates are zero and import batch empty when null, followed by the extra
+// columns selected after it
+func scanDividend(row scanner, extra ...interface{}) (entities.Dividend, error) {
+	var d entities.Dividend
+	var recordDate, payDate sql.NullTime
+	var importBatchID sql.NullString
+	dest := []interface{}{&d.DividendID, &d.StockID, &d.DividendPerShare.Amount, &d.DividendPerShare.Currency, &d.ExDate, &recordDate, &payDate, &importBatchID, &d.CreatedAt, &d.UpdatedAt}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return entities.Dividend{}, err
+	}
+	d.RecordDate = recordDate.Time
+	d.PayDate = payDate.Time
+	d.ImportBatchID = importBatchID.String
+	return d, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
 	"github.com/sergicanet9/scv-go-tools/v3/mocks"
 	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/tkudlicka/portflux-api/core/entities"
 )
@@ -89,13 +90,14 @@ func TestGetDividend_Ok(t *testing.T) {
 	}
 
 	expecteddividend := entities.Dividend{
-		DividendID: "f8352727-231e-4de1-8257-c235a0af5c4a",
+		DividendID:       "f8352727-231e-4de1-8257-c235a0af5c4a",
+		DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.24"), "USD"),
 	}
 	filter := map[string]interface{}{"name": "test-name", "holdingid": "1"}
 	skip := 1
 	take := 1
-	mock.ExpectQuery("SELECT (.+) FROM dividend").WillReturnRows(sqlmock.NewRows([]string{"dividendid", "stockid", "dividend_per_share", "dividend_date", "created_at", "updated_at"}).
-		AddRow(expecteddividend.DividendID, expecteddividend.StockID, expecteddividend.DividendPerShare, expecteddividend.DividendDate, expecteddividend.CreatedAt, expecteddividend.UpdatedAt))
+	mock.ExpectQuery("SELECT (.+) FROM dividend").WillReturnRows(sqlmock.NewRows([]string{"dividendid", "stockid", "dividend_per_share", "dividend_currency", "ex_date", "record_date", "pay_date", "import_batchid", "created_at", "updated_at"}).
+		AddRow(expecteddividend.DividendID, expecteddividend.StockID, expecteddividend.DividendPerShare.Amount, expecteddividend.DividendPerShare.Currency, expecteddividend.ExDate, nil, nil, nil, expecteddividend.CreatedAt, expecteddividend.UpdatedAt))
 
 	// Act
 	result, err := repo.Get(context.Background(), filter, &skip, &take)
@@ -162,10 +164,11 @@ func TestGetdividendByID_Ok(t *testing.T) {
 	}
 
 	expecteddividend := entities.Dividend{
-		DividendID: "f8352727-231e-4de1-8257-c235a0af5c4a",
+		DividendID:       "f8352727-231e-4de1-8257-c235a0af5c4a",
+		DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.24"), "USD"),
 	}
-	mock.ExpectQuery("SELECT (.+) FROM dividend").WillReturnRows(sqlmock.NewRows([]string{"dividendid", "stockid", "dividend_per_share", "dividend_date", "created_at", "updated_at"}).
-		AddRow(expecteddividend.DividendID, expecteddividend.StockID, expecteddividend.DividendPerShare, expecteddividend.DividendDate, expecteddividend.CreatedAt, expecteddividend.UpdatedAt))
+	mock.ExpectQuery("SELECT (.+) FROM dividend").WillReturnRows(sqlmock.NewRows([]string{"dividendid", "stockid", "dividend_per_share", "dividend_currency", "ex_date", "record_date", "pay_date", "import_batchid", "created_at", "updated_at"}).
+		AddRow(expecteddividend.DividendID, expecteddividend.StockID, expecteddividend.DividendPerShare.Amount, expecteddividend.DividendPerShare.Currency, expecteddividend.ExDate, nil, nil, nil, expecteddividend.CreatedAt, expecteddividend.UpdatedAt))
 
 	// Act
 	result, err := repo.GetByID(context.Background(), expecteddividend.DividendID)
@@ -209,7 +212,7 @@ func TestGetdividendByID_ResourceNotFound(t *testing.T) {
 			DB: db,
 		},
 	}
-	mock.ExpectQuery("SELECT (.+) FROM dividend").WillReturnRows(sqlmock.NewRows([]string{"dividendid", "stockid", "dividend_per_share", "dividend_date", "created_at", "updated_at"}))
+	mock.ExpectQuery("SELECT (.+) FROM dividend").WillReturnRows(sqlmock.NewRows([]string{"dividendid", "stockid", "dividend_per_share", "dividend_currency", "ex_date", "record_date", "pay_date", "import_batchid", "created_at", "updated_at"}))
 
 	// Act
 	_, err := repo.GetByID(context.Background(), "")
@@ -218,6 +221,42 @@ func TestGetdividendByID_ResourceNotFound(t *testing.T) {
 	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
 }
 
+// TestStreamDividendsByPortfolioID_Ok checks that StreamByPortfolioID calls fn with each dividend, the shares held on its ex-date and the tax withheld from it
+func TestStreamDividendsByPortfolioID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &dividendRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedDividend := entities.PortfolioDividend{
+		Dividend: entities.Dividend{
+			DividendID:       "f8352727-231e-4de1-8257-c235a0af5c4a",
+			StockID:          "5d0b2a9e-3c1f-4a7b-8e6d-2f9c1b4a7e30",
+			DividendPerShare: entities.NewMoney(decimal.RequireFromString("0.24"), "USD"),
+		},
+		Shares:      decimal.NewFromInt(10),
+		Withholding: entities.NewMoney(decimal.RequireFromString("0.36"), "USD"),
+	}
+	mock.ExpectQuery("SELECT (.+) FROM dividend d (.+) WHERE d.shares > 0").WithArgs("portfolio-id").WillReturnRows(sqlmock.NewRows([]string{"dividendid", "stockid", "dividend_per_share", "dividend_currency", "ex_date", "record_date", "pay_date", "import_batchid", "created_at", "updated_at", "shares", "withholding", "withholding_currency"}).
+		AddRow(expectedDividend.DividendID, expectedDividend.StockID, expectedDividend.DividendPerShare.Amount, expectedDividend.DividendPerShare.Currency, expectedDividend.ExDate, nil, nil, nil, expectedDividend.CreatedAt, expectedDividend.UpdatedAt, expectedDividend.Shares, expectedDividend.Withholding.Amount, expectedDividend.Withholding.Currency))
+
+	// Act
+	var result []entities.PortfolioDividend
+	err := repo.StreamByPortfolioID(context.Background(), "portfolio-id", func(d entities.PortfolioDividend) error {
+		result = append(result, d)
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []entities.PortfolioDividend{expectedDividend}, result)
+}
+
 // TestUpdateDividend_Ok checks that Update does not return an error when the received ID has a valid format
 func TestUpdateDividend_Ok(t *testing.T) {
 	// Arrange
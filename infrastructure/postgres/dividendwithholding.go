@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// dividendWithholdingRepository adapter of a dividend withholding repository for postgres
+type dividendWithholdingRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewDividendWithholdingRepository creates a dividend withholding repository for postgres
+func NewDividendWithholdingRepository(db *sql.DB) ports.DividendWithholdingRepository {
+	return &dividendWithholdingRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+// selectDividendWithholding selects the columns of a dividend withholding in the order scanned by scanDividendWithholding
+const selectDividendWithholding = `SELECT dividend_withholdingid, portfolioid, dividendid, amount, currency, import_batchid, created_at, updated_at FROM dividend_withholding`
+
+func (r *dividendWithholdingRepository) Create(ctx context.Context, withholding interface{}) (string, error) {
+	q := `
+	INSERT INTO dividend_withholding (portfolioid, dividendid, amount, currency, import_batchid, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING dividend_withholdingid;
+    `
+
+	w := withholding.(entities.DividendWithholding)
+	row := r.DB.QueryRowContext(ctx, q, w.PortfolioID, w.DividendID, w.Amount.Amount, w.Amount.Currency, nullString(w.ImportBatchID), w.CreatedAt, w.UpdatedAt)
+
+	err := row.Scan(&w.DividendWithholdingID)
+	if err != nil {
+		return "", err
+	}
+
+	return w.DividendWithholdingID, nil
+}
+
+func (r *dividendWithholdingRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	var where string
+	for k, v := range filter {
+		if where == "" {
+			where = "WHERE"
+		} else {
+			where = fmt.Sprintf("%s AND", where)
+		}
+		where = fmt.Sprintf("%s %s = '%v'", where, k, v)
+	}
+	if skip != nil {
+		where = fmt.Sprintf("%s OFFSET %d", where, *skip)
+	}
+	if take != nil {
+		where = fmt.Sprintf("%s LIMIT %d", where, *take)
+	}
+
+	q := fmt.Sprintf("%s %s;", selectDividendWithholding, where)
+
+	rows, err := r.DB.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanDividendWithholdings(rows)
+}
+
+func (r *dividendWithholdingRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	q := fmt.Sprintf("%s WHERE dividend_withholdingid = $1;", selectDividendWithholding)
+
+	row := r.DB.QueryRowContext(ctx, q, ID)
+
+	w, err := scanDividendWithholding(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+// GetByPortfolioID returns the taxes withheld from the dividends paid to a portfolio
+func (r *dividendWithholdingRepository) GetByPortfolioID(ctx context.Context, portfolioID string) ([]interface{}, error) {
+	q := fmt.Sprintf("%s WHERE portfolioid = $1 ORDER BY dividendid;", selectDividendWithholding)
+
+	rows, err := r.DB.QueryContext(ctx, q, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanDividendWithholdings(rows)
+}
+
+func (r *dividendWithholdingRepository) Update(ctx context.Context, ID string, withholding interface{}) error {
+	q := `
+	UPDATE dividend_withholding set amount=$1, currency=$2, updated_at=$3
+	    WHERE dividend_withholdingid=$4;
+	`
+
+	w := withholding.(entities.DividendWithholding)
+	result, err := r.DB.ExecContext(ctx, q, w.Amount.Amount, w.Amount.Currency, w.UpdatedAt, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *dividendWithholdingRepository) Delete(ctx context.Context, ID string) error {
+	q := `DELETE FROM dividend_withholding WHERE dividend_withholdingid=$1;`
+
+	result, err := r.DB.ExecContext(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// CreateMany creates dividend withholdings in one database transaction
+func (r *dividendWithholdingRepository) CreateMany(ctx context.Context, withholdings []interface{}) ([]string, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := `
+	INSERT INTO dividend_withholding (portfolioid, dividendid, amount, currency, import_batchid, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING dividend_withholdingid;
+    `
+
+	var result []string
+	for _, entity := range withholdings {
+		w := entity.(entities.DividendWithholding)
+		row := tx.QueryRowContext(ctx, q, w.PortfolioID, w.DividendID, w.Amount.Amount, w.Amount.Currency, nullString(w.ImportBatchID), w.CreatedAt, w.UpdatedAt)
+		if err := row.Scan(&w.DividendWithholdingID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		result = append(result, w.DividendWithholdingID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// scanDividendWithholdings scans the dividend withholdings selected with selectDividendWithholding
+func scanDividendWithholdings(rows *sql.Rows) ([]interface{}, error) {
+	var withholdings []interface{}
+	for rows.Next() {
+		w, err := scanDividendWithholding(rows)
+		if err != nil {
+			return nil, err
+		}
+		withholdings = append(withholdings, &w)
+	}
+
+	if len(withholdings) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return withholdings, nil
+}
+
+// scanDividendWithholding scans a dividend withholding selected with selectDividendWithholding, whose import batch is empty when null
+func scanDividendWithholding(row scanner) (entities.DividendWithholding, error) {
+	var w entities.DividendWithholding
+	var importBatchID sql.NullString
+	err := row.Scan(&w.DividendWithholdingID, &w.PortfolioID, &w.DividendID, &w.Amount.Amount, &w.Amount.Currency, &importBatchID, &w.CreatedAt, &w.UpdatedAt)
+	w.ImportBatchID = importBatchID.String
+	return w, err
+}
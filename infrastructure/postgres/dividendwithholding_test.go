@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestNewDividendWithholdingRepository_Ok checks that NewDividendWithholdingRepository creates a new dividendWithholdingRepository struct
+func TestNewDividendWithholdingRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewDividendWithholdingRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestCreateDividendWithholding_Ok checks that Create stores the withholding with its import batch and returns its ID
+func TestCreateDividendWithholding_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &dividendWithholdingRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	withholding := entities.DividendWithholding{PortfolioID: "portfolio-a", DividendID: "dividend-a", Amount: entities.NewMoney(decimal.RequireFromString("0.36"), "USD"), ImportBatchID: "batch-a", CreatedAt: now, UpdatedAt: now}
+	expectedID := "7c1d2e3f-4a5b-4c6d-8e9f-0a1b2c3d4e5f"
+	mock.ExpectQuery("INSERT INTO dividend_withholding").
+		WithArgs("portfolio-a", "dividend-a", withholding.Amount.Amount, "USD", sql.NullString{String: "batch-a", Valid: true}, now, now).
+		WillReturnRows(sqlmock.NewRows([]string{"dividend_withholdingid"}).AddRow(expectedID))
+
+	// Act
+	id, err := repo.Create(context.Background(), withholding)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedID, id)
+}
+
+// TestGetDividendWithholdingsByPortfolioID_Ok checks that GetByPortfolioID returns the withholdings of the portfolio
+func TestGetDividendWithholdingsByPortfolioID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &dividendWithholdingRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	expectedWithholding := &entities.DividendWithholding{DividendWithholdingID: "withholding-a", PortfolioID: "portfolio-a", DividendID: "dividend-a", Amount: entities.NewMoney(decimal.RequireFromString("0.36"), "USD"), CreatedAt: now, UpdatedAt: now}
+	mock.ExpectQuery("SELECT (.+) FROM dividend_withholding WHERE portfolioid = \\$1").
+		WithArgs("portfolio-a").
+		WillReturnRows(sqlmock.NewRows([]string{"dividend_withholdingid", "portfolioid", "dividendid", "amount", "currency", "import_batchid", "created_at", "updated_at"}).
+			AddRow("withholding-a", "portfolio-a", "dividend-a", expectedWithholding.Amount.Amount, "USD", nil, now, now))
+
+	// Act
+	withholdings, err := repo.GetByPortfolioID(context.Background(), "portfolio-a")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{expectedWithholding}, withholdings)
+}
+
+// TestGetDividendWithholdingsByPortfolioID_NoResourcesFound checks that GetByPortfolioID returns a non existent error when the portfolio has no withholdings
+func TestGetDividendWithholdingsByPortfolioID_NoResourcesFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &dividendWithholdingRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM dividend_withholding").WillReturnRows(sqlmock.NewRows([]string{"dividend_withholdingid", "portfolioid", "dividendid", "amount", "currency", "import_batchid", "created_at", "updated_at"}))
+
+	// Act
+	_, err := repo.GetByPortfolioID(context.Background(), "portfolio-a")
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
+}
+
+// TestCreateManyDividendWithholdings_Ok checks that CreateMany stores every withholding in one transaction and returns their IDs
+func TestCreateManyDividendWithholdings_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &dividendWithholdingRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO dividend_withholding").WillReturnRows(sqlmock.NewRows([]string{"dividend_withholdingid"}).AddRow("withholding-a"))
+	mock.ExpectQuery("INSERT INTO dividend_withholding").WillReturnRows(sqlmock.NewRows([]string{"dividend_withholdingid"}).AddRow("withholding-b"))
+	mock.ExpectCommit()
+
+	// Act
+	ids, err := repo.CreateMany(context.Background(), []interface{}{entities.DividendWithholding{}, entities.DividendWithholding{}})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"withholding-a", "withholding-b"}, ids)
+}
+
+// TestCreateManyDividendWithholdings_InsertError checks that CreateMany rolls the transaction back when an insert fails
+func TestCreateManyDividendWithholdings_InsertError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &dividendWithholdingRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "insert error"
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO dividend_withholding").WillReturnError(fmt.Errorf(expectedError))
+	mock.ExpectRollback()
+
+	// Act
+	_, err := repo.CreateMany(context.Background(), []interface{}{entities.DividendWithholding{}})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestDeleteDividendWithholding_NotDeletedError checks that Delete returns a non existent error when no withholding has the ID
+func TestDeleteDividendWithholding_NotDeletedError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &dividendWithholdingRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("DELETE FROM dividend_withholding").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.Delete(context.Background(), "withholding-a")
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
+}
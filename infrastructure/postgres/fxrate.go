@@ -0,0 +1,209 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// fxRateRepository adapter of an fx rate repository for postgres
+type fxRateRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewFxRateRepository creates an fx rate repository for postgres
+func NewFxRateRepository(db *sql.DB) ports.FxRateRepository {
+	return &fxRateRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+// upsertFxRate inserts a rate or replaces the one already stored for the same pair and date
+const upsertFxRate = `
+	INSERT INTO fx_rate (base_currency, quote_currency, rate_date, rate, source, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (base_currency, quote_currency, rate_date)
+        DO UPDATE SET rate = EXCLUDED.rate, source = EXCLUDED.source, updated_at = EXCLUDED.updated_at
+        RETURNING fx_rateid;
+    `
+
+func (r *fxRateRepository) Create(ctx context.Context, fxRate interface{}) (string, error) {
+	f := fxRate.(entities.FxRate)
+	row := r.DB.QueryRowContext(
+		ctx, upsertFxRate, f.BaseCurrency, f.QuoteCurrency, f.RateDate, f.Rate, f.Source, f.CreatedAt, f.UpdatedAt,
+	)
+
+	err := row.Scan(&f.FxRateID)
+	if err != nil {
+		return "", err
+	}
+
+	return f.FxRateID, nil
+}
+
+func (r *fxRateRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	var where string
+	for k, v := range filter {
+		if where == "" {
+			where = "WHERE"
+		} else {
+			where = fmt.Sprintf("%s AND", where)
+		}
+		where = fmt.Sprintf("%s %s = '%v'", where, k, v)
+	}
+	if skip != nil {
+		where = fmt.Sprintf("%s OFFSET %d", where, *skip)
+	}
+	if take != nil {
+		where = fmt.Sprintf("%s LIMIT %d", where, *take)
+	}
+
+	q := fmt.Sprintf(`
+	SELECT fx_rateid, base_currency, quote_currency, rate_date, rate, source, created_at, updated_at
+	    FROM fx_rate %s;
+	`, where)
+
+	rows, err := r.DB.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var fxRates []interface{}
+	for rows.Next() {
+		var f entities.FxRate
+		err := rows.Scan(&f.FxRateID, &f.BaseCurrency, &f.QuoteCurrency, &f.RateDate, &f.Rate, &f.Source, &f.CreatedAt, &f.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		fxRates = append(fxRates, &f)
+	}
+
+	if len(fxRates) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return fxRates, nil
+}
+
+func (r *fxRateRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	q := `
+    SELECT fx_rateid, base_currency, quote_currency, rate_date, rate, source, created_at, updated_at
+        FROM fx_rate WHERE fx_rateid = $1;
+    `
+
+	row := r.DB.QueryRowContext(ctx, q, ID)
+
+	var f entities.FxRate
+	err := row.Scan(&f.FxRateID, &f.BaseCurrency, &f.QuoteCurrency, &f.RateDate, &f.Rate, &f.Source, &f.CreatedAt, &f.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// GetLatest returns the most recent rate of a pair published on or before a date
+func (r *fxRateRepository) GetLatest(ctx context.Context, baseCurrency, quoteCurrency string, date time.Time) (interface{}, error) {
+	q := `
+    SELECT fx_rateid, base_currency, quote_currency, rate_date, rate, source, created_at, updated_at
+        FROM fx_rate WHERE base_currency = $1 AND quote_currency = $2 AND rate_date <= $3
+        ORDER BY rate_date DESC LIMIT 1;
+    `
+
+	row := r.DB.QueryRowContext(ctx, q, baseCurrency, quoteCurrency, date)
+
+	var f entities.FxRate
+	err := row.Scan(&f.FxRateID, &f.BaseCurrency, &f.QuoteCurrency, &f.RateDate, &f.Rate, &f.Source, &f.CreatedAt, &f.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+func (r *fxRateRepository) Update(ctx context.Context, ID string, fxRate interface{}) error {
+	q := `
+	UPDATE fx_rate set rate=$1, source=$2, updated_at=$3
+	    WHERE fx_rateid=$4;
+	`
+
+	f := fxRate.(entities.FxRate)
+	result, err := r.DB.ExecContext(
+		ctx, q, f.Rate, f.Source, f.UpdatedAt, ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		fmt.Println("RowsAffected Error", err)
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *fxRateRepository) Delete(ctx context.Context, ID string) error {
+	q := `DELETE FROM fx_rate WHERE fx_rateid=$1;`
+
+	result, err := r.DB.ExecContext(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// CreateMany stores many rates atomically, replacing the ones already stored for the same pair and date
+func (r *fxRateRepository) CreateMany(ctx context.Context, fxRates []interface{}) ([]string, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, entity := range fxRates {
+		f := entity.(entities.FxRate)
+
+		row := tx.QueryRowContext(
+			ctx, upsertFxRate, f.BaseCurrency, f.QuoteCurrency, f.RateDate, f.Rate, f.Source, f.CreatedAt, f.UpdatedAt,
+		)
+		err := row.Scan(&f.FxRateID)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		result = append(result, f.FxRateID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
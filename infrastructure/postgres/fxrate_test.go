@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestNewFxRateRepository_Ok checks that NewFxRateRepository creates a new fxRateRepository struct
+func TestNewFxRateRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewFxRateRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestCreateFxRate_Ok checks that Create returns the expected response when a valid entity is received
+func TestCreateFxRate_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &fxRateRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	newFxRate := entities.FxRate{}
+
+	expectedID := "f8352727-231e-4de1-8257-c235a0af5c4a"
+	mock.ExpectQuery("INSERT INTO fx_rate (.+) ON CONFLICT").WillReturnRows(sqlmock.NewRows([]string{"fx_rateid"}).AddRow(expectedID))
+
+	// Act
+	id, err := repo.Create(context.Background(), newFxRate)
+
+	// Assert
+	assert.Equal(t, expectedID, id)
+	assert.Nil(t, err)
+}
+
+// TestGetLatestFxRate_Ok checks that GetLatest returns the expected response when a rate exists
+func TestGetLatestFxRate_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &fxRateRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	date := time.Date(2023, time.August, 6, 0, 0, 0, 0, time.UTC)
+	expectedFxRate := entities.FxRate{
+		FxRateID:      "f8352727-231e-4de1-8257-c235a0af5c4a",
+		BaseCurrency:  "EUR",
+		QuoteCurrency: "CZK",
+		RateDate:      time.Date(2023, time.August, 4, 0, 0, 0, 0, time.UTC),
+		Rate:          decimal.RequireFromString("24.105"),
+		Source:        "ecb",
+	}
+	mock.ExpectQuery("SELECT (.+) FROM fx_rate WHERE (.+) ORDER BY rate_date DESC LIMIT 1").WithArgs("EUR", "CZK", date).WillReturnRows(sqlmock.NewRows([]string{"fx_rateid", "base_currency", "quote_currency", "rate_date", "rate", "source", "created_at", "updated_at"}).
+		AddRow(expectedFxRate.FxRateID, expectedFxRate.BaseCurrency, expectedFxRate.QuoteCurrency, expectedFxRate.RateDate, expectedFxRate.Rate, expectedFxRate.Source, expectedFxRate.CreatedAt, expectedFxRate.UpdatedAt))
+
+	// Act
+	result, err := repo.GetLatest(context.Background(), "EUR", "CZK", date)
+
+	// Assert
+	assert.Nil(t, err)
+
+	entity := *(result.(*entities.FxRate))
+	assert.Equal(t, expectedFxRate.FxRateID, entity.FxRateID)
+	assert.Equal(t, expectedFxRate.RateDate, entity.RateDate)
+	assert.Equal(t, expectedFxRate.Rate.String(), entity.Rate.String())
+}
+
+// TestGetLatestFxRate_NotFound checks that GetLatest returns a non existent error when no rate exists
+func TestGetLatestFxRate_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &fxRateRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM fx_rate").WillReturnError(sql.ErrNoRows)
+
+	// Act
+	_, err := repo.GetLatest(context.Background(), "EUR", "CZK", time.Now())
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
+
+// TestCreateManyFxRates_Ok checks that CreateMany does not return an error when everything goes as expected
+func TestCreateManyFxRates_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &fxRateRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	newFxRates := []interface{}{entities.FxRate{}}
+	expectedID := "f8352727-231e-4de1-8257-c235a0af5c4a"
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO fx_rate").WillReturnRows(sqlmock.NewRows([]string{"fx_rateid"}).AddRow(expectedID))
+	mock.ExpectCommit()
+
+	// Act
+	ids, err := repo.CreateMany(context.Background(), newFxRates)
+
+	// Assert
+	assert.True(t, len(ids) == 1)
+	assert.Equal(t, expectedID, ids[0])
+	assert.Nil(t, err)
+}
+
+// TestCreateManyFxRates_InsertError checks that CreateMany returns an error when the insert statement fails
+func TestCreateManyFxRates_InsertError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &fxRateRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	newFxRates := []interface{}{entities.FxRate{}}
+	expectedError := "insert error"
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO fx_rate").WillReturnError(fmt.Errorf(expectedError))
+	mock.ExpectRollback()
+
+	// Act
+	_, err := repo.CreateMany(context.Background(), newFxRates)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
@@ -28,14 +28,14 @@ func NewHoldingRepository(db *sql.DB) ports.HoldingRepository {
 
 func (r *holdingRepository) Create(ctx context.Context, holding interface{}) (string, error) {
 	q := `
-	INSERT INTO holding (portfolioid,brokerid,extid,name,description,slug,trade_date,trade_type,quantity,share_price,exchange_rate,exhange_currencyid,brokerage_unit_price,brokerage_currency, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16)
+	INSERT INTO holding (portfolioid,brokerid,broker_accountid,extid,name,description,slug,trade_date,trade_type,quantity,share_price,share_price_currency,exchange_rate,exchange_currencyid,brokerage_unit_price,brokerage_currency, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18)
         RETURNING holdingid;
     `
 
 	c := holding.(entities.Holding)
 	row := r.DB.QueryRowContext(
-		ctx, q, c.PortfolioID, c.BrokerID, c.Extid, c.Name, c.Description, c.Slug, c.TradeDate, c.TradeType, c.Quantity, c.SharePrice, c.ExchangeRate, c.ExchangeCurrencyID, c.BrokerageUnitPrice, c.BrokerageCurrency, c.CreatedAt, c.UpdatedAt,
+		ctx, q, c.PortfolioID, c.BrokerID, nullString(c.BrokerAccountID), c.Extid, c.Name, c.Description, c.Slug, c.TradeDate, c.TradeType, c.Quantity, c.SharePrice.Amount, c.SharePrice.Currency, c.ExchangeRate, c.ExchangeCurrencyID, c.BrokerageUnitPrice.Amount, c.BrokerageUnitPrice.Currency, c.CreatedAt, c.UpdatedAt,
 	)
 
 	err := row.Scan(&c.HoldingID)
@@ -64,7 +64,7 @@ func (r *holdingRepository) Get(ctx context.Context, filter map[string]interface
 	}
 
 	q := fmt.Sprintf(`
-	SELECT holdingid,portfolioidbrokerid,extid,name,description,slug,trade_date,trade_type,quantity,share_price,exchange_rate,exchange_currencyid,brokerage_unit_price,brokerage_currency,created_at,updated_at
+	SELECT holdingid,portfolioid,brokerid,broker_accountid,extid,name,description,slug,trade_date,trade_type,quantity,share_price,share_price_currency,exchange_rate,exchange_currencyid,brokerage_unit_price,brokerage_currency,created_at,updated_at
 	    FROM holding %s;
 	`, where)
 
@@ -77,8 +77,7 @@ func (r *holdingRepository) Get(ctx context.Context, filter map[string]interface
 
 	var holdings []interface{}
 	for rows.Next() {
-		var c entities.Holding
-		err := rows.Scan(&c.HoldingID, &c.PortfolioID, &c.BrokerID, &c.Extid, &c.Name, &c.Description, &c.Slug, &c.TradeDate, &c.TradeType, &c.Quantity, &c.SharePrice, &c.ExchangeRate, &c.ExchangeCurrencyID, &c.BrokerageUnitPrice, &c.BrokerageCurrency, &c.CreatedAt, &c.UpdatedAt)
+		c, err := scanHolding(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -94,14 +93,13 @@ func (r *holdingRepository) Get(ctx context.Context, filter map[string]interface
 
 func (r *holdingRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
 	q := `
-    SELECT holdingid,portfolioidbrokerid,extid,name,description,slug,trade_date,trade_type,quantity,share_price,exchange_rate,exchange_currencyid,brokerage_unit_price,brokerage_currency,created_at,updated_at
+    SELECT holdingid,portfolioid,brokerid,broker_accountid,extid,name,description,slug,trade_date,trade_type,quantity,share_price,share_price_currency,exchange_rate,exchange_currencyid,brokerage_unit_price,brokerage_currency,created_at,updated_at
         FROM holding WHERE holdingid = $1;
     `
 
 	row := r.DB.QueryRowContext(ctx, q, ID)
 
-	var c entities.Holding
-	err := row.Scan(&c.HoldingID, &c.PortfolioID, &c.BrokerID, &c.Extid, &c.Name, &c.Description, &c.Slug, &c.TradeDate, &c.TradeType, &c.Quantity, &c.SharePrice, &c.ExchangeRate, &c.ExchangeCurrencyID, &c.BrokerageUnitPrice, &c.BrokerageCurrency, &c.CreatedAt, &c.UpdatedAt)
+	c, err := scanHolding(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = wrappers.NewNonExistentErr(err)
@@ -112,15 +110,44 @@ func (r *holdingRepository) GetByID(ctx context.Context, ID string) (interface{}
 	return &c, nil
 }
 
+// StreamByPortfolioID calls fn with each holding of a portfolio in the order they were created, reading them one at a time.
+// It stops at the first error returned by fn.
+func (r *holdingRepository) StreamByPortfolioID(ctx context.Context, portfolioID string, fn func(entities.Holding) error) error {
+	q := `
+    SELECT holdingid,portfolioid,brokerid,broker_accountid,extid,name,description,slug,trade_date,trade_type,quantity,share_price,share_price_currency,exchange_rate,exchange_currencyid,brokerage_unit_price,brokerage_currency,created_at,updated_at
+        FROM holding WHERE portfolioid = $1
+        ORDER BY created_at, holdingid;
+    `
+
+	rows, err := r.DB.QueryContext(ctx, q, portfolioID)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		c, err := scanHolding(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 func (r *holdingRepository) Update(ctx context.Context, ID string, holding interface{}) error {
 	q := `
-	UPDATE holding set brokerid=$1, name=$2, description=$3, slug=$4,trade_date=$5,trade_type=$6,quantity=$7,share_price=$8,exhcange_rate=$9,exchange_currencyid=$10,brokerage_unit_price=$11,brokerage_currency=$12,,updated_at=$13
-	    WHERE holdingid=$14;
+	UPDATE holding set brokerid=$1, broker_accountid=$2, name=$3, description=$4, slug=$5,trade_date=$6,trade_type=$7,quantity=$8,share_price=$9,share_price_currency=$10,exchange_rate=$11,exchange_currencyid=$12,brokerage_unit_price=$13,brokerage_currency=$14,updated_at=$15
+	    WHERE holdingid=$16;
 	`
 
 	c := holding.(entities.Holding)
 	result, err := r.DB.ExecContext(
-		ctx, q, c.BrokerID, c.Name, c.Description, c.Slug, c.TradeDate, c.TradeType, c.Quantity, c.SharePrice, c.ExchangeRate, c.ExchangeCurrencyID, c.BrokerageUnitPrice, c.BrokerageCurrency, c.UpdatedAt, ID,
+		ctx, q, c.BrokerID, nullString(c.BrokerAccountID), c.Name, c.Description, c.Slug, c.TradeDate, c.TradeType, c.Quantity, c.SharePrice.Amount, c.SharePrice.Currency, c.ExchangeRate, c.ExchangeCurrencyID, c.BrokerageUnitPrice.Amount, c.BrokerageUnitPrice.Currency, c.UpdatedAt, ID,
 	)
 	if err != nil {
 		return err
@@ -166,13 +193,13 @@ func (r *holdingRepository) CreateMany(ctx context.Context, holding_models []int
 		c := entity.(entities.Holding)
 
 		q := `
-		INSERT INTO holding (portfolioid,brokerid,extid,name,description,slug,trade_date,trade_type,quantity,share_price,exchange_rate,exhange_currencyid,brokerage_unit_price,brokerage_currency, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16)
+		INSERT INTO holding (portfolioid,brokerid,broker_accountid,extid,name,description,slug,trade_date,trade_type,quantity,share_price,share_price_currency,exchange_rate,exchange_currencyid,brokerage_unit_price,brokerage_currency, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18)
 			RETURNING holdingid;`
 
 		// Here, the query is executed on the transaction instance, and not applied to the database yet
 		row := tx.QueryRowContext(
-			ctx, q, c.PortfolioID, c.BrokerID, c.Extid, c.Name, c.Description, c.Slug, c.TradeDate, c.TradeType, c.Quantity, c.SharePrice, c.ExchangeRate, c.ExchangeCurrencyID, c.BrokerageUnitPrice, c.BrokerageCurrency, c.CreatedAt, c.UpdatedAt,
+			ctx, q, c.PortfolioID, c.BrokerID, nullString(c.BrokerAccountID), c.Extid, c.Name, c.Description, c.Slug, c.TradeDate, c.TradeType, c.Quantity, c.SharePrice.Amount, c.SharePrice.Currency, c.ExchangeRate, c.ExchangeCurrencyID, c.BrokerageUnitPrice.Amount, c.BrokerageUnitPrice.Currency, c.CreatedAt, c.UpdatedAt,
 		)
 		err := row.Scan(&c.HoldingID)
 		if err != nil {
@@ -190,3 +217,15 @@ func (r *holdingRepository) CreateMany(ctx context.Context, holding_models []int
 	}
 	return result, nil
 }
+
+// scanHolding scans a holding, whose broker account is empty when null
+func scanHolding(row scanner) (entities.Holding, error) {
+	var c entities.Holding
+	var brokerAccountID sql.NullString
+	err := row.Scan(&c.HoldingID, &c.PortfolioID, &c.BrokerID, &brokerAccountID, &c.Extid, &c.Name, &c.Description, &c.Slug, &c.TradeDate, &c.TradeType, &c.Quantity, &c.SharePrice.Amount, &c.SharePrice.Currency, &c.ExchangeRate, &c.ExchangeCurrencyID, &c.BrokerageUnitPrice.Amount, &c.BrokerageUnitPrice.Currency, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return entities.Holding{}, err
+	}
+	c.BrokerAccountID = brokerAccountID.String
+	return c, nil
+}
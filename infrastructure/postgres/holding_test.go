@@ -10,6 +10,7 @@ import (
 	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
 	"github.com/sergicanet9/scv-go-tools/v3/mocks"
 	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/tkudlicka/portflux-api/core/entities"
 )
@@ -87,13 +88,17 @@ func TestGetHolding_Ok(t *testing.T) {
 	}
 
 	expectedHolding := entities.Holding{
-		HoldingID: "f8352727-231e-4de1-8257-c235a0af5c4a",
+		HoldingID:          "f8352727-231e-4de1-8257-c235a0af5c4a",
+		Quantity:           decimal.RequireFromString("1.5"),
+		SharePrice:         entities.NewMoney(decimal.RequireFromString("101.25"), "USD"),
+		ExchangeRate:       decimal.RequireFromString("22.1"),
+		BrokerageUnitPrice: entities.NewMoney(decimal.NewFromInt(1), "USD"),
 	}
 	filter := map[string]interface{}{"name": "test-name", "holdingid": "1"}
 	skip := 1
 	take := 1
-	mock.ExpectQuery("SELECT (.+) FROM holding").WillReturnRows(sqlmock.NewRows([]string{"holdingid", "portfolioid", "brokerid", "extid", "name", "description", "slug", "trade_date", "trade_type", "quantity", "share_price", "exchange_rate", "exchange_currencyid", "brokerage_unit_price", "brokerage_currency", "created_at", "updated_at"}).
-		AddRow(expectedHolding.HoldingID, expectedHolding.PortfolioID, expectedHolding.BrokerID, expectedHolding.Extid, expectedHolding.Name, expectedHolding.Description, expectedHolding.Slug, expectedHolding.TradeDate, expectedHolding.TradeType, expectedHolding.Quantity, expectedHolding.SharePrice, expectedHolding.ExchangeRate, expectedHolding.ExchangeCurrencyID, expectedHolding.BrokerageUnitPrice, expectedHolding.BrokerageCurrency, expectedHolding.CreatedAt, expectedHolding.UpdatedAt))
+	mock.ExpectQuery("SELECT (.+) FROM holding").WillReturnRows(sqlmock.NewRows([]string{"holdingid", "portfolioid", "brokerid", "broker_accountid", "extid", "name", "description", "slug", "trade_date", "trade_type", "quantity", "share_price", "share_price_currency", "exchange_rate", "exchange_currencyid", "brokerage_unit_price", "brokerage_currency", "created_at", "updated_at"}).
+		AddRow(expectedHolding.HoldingID, expectedHolding.PortfolioID, expectedHolding.BrokerID, expectedHolding.BrokerAccountID, expectedHolding.Extid, expectedHolding.Name, expectedHolding.Description, expectedHolding.Slug, expectedHolding.TradeDate, expectedHolding.TradeType, expectedHolding.Quantity, expectedHolding.SharePrice.Amount, expectedHolding.SharePrice.Currency, expectedHolding.ExchangeRate, expectedHolding.ExchangeCurrencyID, expectedHolding.BrokerageUnitPrice.Amount, expectedHolding.BrokerageUnitPrice.Currency, expectedHolding.CreatedAt, expectedHolding.UpdatedAt))
 
 	// Act
 	result, err := repo.Get(context.Background(), filter, &skip, &take)
@@ -138,7 +143,7 @@ func TestGetHolding_NoResourcesFound(t *testing.T) {
 			DB: db,
 		},
 	}
-	mock.ExpectQuery("SELECT (.+) FROM holding").WillReturnRows(sqlmock.NewRows([]string{"holdingid", "portfolioid", "brokerid", "extid", "name", "description", "slug", "trade_date", "trade_type", "quantity", "share_price", "exchange_rate", "exchange_currencyid", "brokerage_unit_price", "brokerage_currency", "created_at", "updated_at"}))
+	mock.ExpectQuery("SELECT (.+) FROM holding").WillReturnRows(sqlmock.NewRows([]string{"holdingid", "portfolioid", "brokerid", "broker_accountid", "extid", "name", "description", "slug", "trade_date", "trade_type", "quantity", "share_price", "share_price_currency", "exchange_rate", "exchange_currencyid", "brokerage_unit_price", "brokerage_currency", "created_at", "updated_at"}))
 
 	// Act
 	_, err := repo.Get(context.Background(), map[string]interface{}{}, nil, nil)
@@ -160,10 +165,14 @@ func TestGetHoldingByID_Ok(t *testing.T) {
 	}
 
 	expectedHolding := entities.Holding{
-		HoldingID: "f8352727-231e-4de1-8257-c235a0af5c4a",
+		HoldingID:          "f8352727-231e-4de1-8257-c235a0af5c4a",
+		Quantity:           decimal.RequireFromString("1.5"),
+		SharePrice:         entities.NewMoney(decimal.RequireFromString("101.25"), "USD"),
+		ExchangeRate:       decimal.RequireFromString("22.1"),
+		BrokerageUnitPrice: entities.NewMoney(decimal.NewFromInt(1), "USD"),
 	}
-	mock.ExpectQuery("SELECT (.+) FROM holding").WillReturnRows(sqlmock.NewRows([]string{"holdingid", "portfolioid", "brokerid", "extid", "name", "description", "slug", "trade_date", "trade_type", "quantity", "share_price", "exchange_rate", "exchange_currencyid", "brokerage_unit_price", "brokerage_currency", "created_at", "updated_at"}).
-		AddRow(expectedHolding.HoldingID, expectedHolding.PortfolioID, expectedHolding.BrokerID, expectedHolding.Extid, expectedHolding.Name, expectedHolding.Description, expectedHolding.Slug, expectedHolding.TradeDate, expectedHolding.TradeType, expectedHolding.Quantity, expectedHolding.SharePrice, expectedHolding.ExchangeRate, expectedHolding.ExchangeCurrencyID, expectedHolding.BrokerageUnitPrice, expectedHolding.BrokerageCurrency, expectedHolding.CreatedAt, expectedHolding.UpdatedAt))
+	mock.ExpectQuery("SELECT (.+) FROM holding").WillReturnRows(sqlmock.NewRows([]string{"holdingid", "portfolioid", "brokerid", "broker_accountid", "extid", "name", "description", "slug", "trade_date", "trade_type", "quantity", "share_price", "share_price_currency", "exchange_rate", "exchange_currencyid", "brokerage_unit_price", "brokerage_currency", "created_at", "updated_at"}).
+		AddRow(expectedHolding.HoldingID, expectedHolding.PortfolioID, expectedHolding.BrokerID, expectedHolding.BrokerAccountID, expectedHolding.Extid, expectedHolding.Name, expectedHolding.Description, expectedHolding.Slug, expectedHolding.TradeDate, expectedHolding.TradeType, expectedHolding.Quantity, expectedHolding.SharePrice.Amount, expectedHolding.SharePrice.Currency, expectedHolding.ExchangeRate, expectedHolding.ExchangeCurrencyID, expectedHolding.BrokerageUnitPrice.Amount, expectedHolding.BrokerageUnitPrice.Currency, expectedHolding.CreatedAt, expectedHolding.UpdatedAt))
 
 	// Act
 	result, err := repo.GetByID(context.Background(), expectedHolding.HoldingID)
@@ -207,7 +216,7 @@ func TestGetHoldingByID_ResourceNotFound(t *testing.T) {
 			DB: db,
 		},
 	}
-	mock.ExpectQuery("SELECT (.+) FROM holding").WillReturnRows(sqlmock.NewRows([]string{"holdingid", "portfolioid", "brokerid", "extid", "name", "description", "slug", "trade_date", "trade_type", "quantity", "share_price", "exchange_rate", "exchange_currencyid", "brokerage_unit_price", "brokerage_currency", "created_at", "updated_at"}))
+	mock.ExpectQuery("SELECT (.+) FROM holding").WillReturnRows(sqlmock.NewRows([]string{"holdingid", "portfolioid", "brokerid", "broker_accountid", "extid", "name", "description", "slug", "trade_date", "trade_type", "quantity", "share_price", "share_price_currency", "exchange_rate", "exchange_currencyid", "brokerage_unit_price", "brokerage_currency", "created_at", "updated_at"}))
 
 	// Act
 	_, err := repo.GetByID(context.Background(), "")
@@ -216,6 +225,41 @@ func TestGetHoldingByID_ResourceNotFound(t *testing.T) {
 	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
 }
 
+// TestStreamHoldingsByPortfolioID_Ok checks that StreamByPortfolioID calls fn with each holding of the portfolio
+func TestStreamHoldingsByPortfolioID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &holdingRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedHolding := entities.Holding{
+		HoldingID:          "f8352727-231e-4de1-8257-c235a0af5c4a",
+		PortfolioID:        "portfolio-id",
+		Quantity:           decimal.RequireFromString("1.5"),
+		SharePrice:         entities.NewMoney(decimal.RequireFromString("101.25"), "USD"),
+		ExchangeRate:       decimal.RequireFromString("22.1"),
+		BrokerageUnitPrice: entities.NewMoney(decimal.NewFromInt(1), "USD"),
+	}
+	mock.ExpectQuery("SELECT (.+) FROM holding WHERE portfolioid = (.+) ORDER BY").WithArgs("portfolio-id").WillReturnRows(sqlmock.NewRows([]string{"holdingid", "portfolioid", "brokerid", "broker_accountid", "extid", "name", "description", "slug", "trade_date", "trade_type", "quantity", "share_price", "share_price_currency", "exchange_rate", "exchange_currencyid", "brokerage_unit_price", "brokerage_currency", "created_at", "updated_at"}).
+		AddRow(expectedHolding.HoldingID, expectedHolding.PortfolioID, expectedHolding.BrokerID, expectedHolding.BrokerAccountID, expectedHolding.Extid, expectedHolding.Name, expectedHolding.Description, expectedHolding.Slug, expectedHolding.TradeDate, expectedHolding.TradeType, expectedHolding.Quantity, expectedHolding.SharePrice.Amount, expectedHolding.SharePrice.Currency, expectedHolding.ExchangeRate, expectedHolding.ExchangeCurrencyID, expectedHolding.BrokerageUnitPrice.Amount, expectedHolding.BrokerageUnitPrice.Currency, expectedHolding.CreatedAt, expectedHolding.UpdatedAt))
+
+	// Act
+	var result []entities.Holding
+	err := repo.StreamByPortfolioID(context.Background(), "portfolio-id", func(h entities.Holding) error {
+		result = append(result, h)
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []entities.Holding{expectedHolding}, result)
+}
+
 // TestUpdateHolding_Ok checks that Update does not return an error when the received ID has a valid format
 func TestUpdateHolding_Ok(t *testing.T) {
 	// Arrange
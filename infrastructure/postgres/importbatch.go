@@ -0,0 +1,215 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// importBatchRepository adapter of an import batch repository for postgres
+type importBatchRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewImportBatchRepository creates an import batch repository for postgres
+func NewImportBatchRepository(db *sql.DB) ports.ImportBatchRepository {
+	return &importBatchRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+// selectImportBatch selects the columns of an import batch in the order scanned by scanImportBatch
+const selectImportBatch = `SELECT import_batchid, portfolioid, brokerid, file_name, fingerprint, created_at, updated_at FROM import_batch`
+
+func (r *importBatchRepository) Create(ctx context.Context, batch interface{}) (string, error) {
+	q := `
+	INSERT INTO import_batch (portfolioid, brokerid, file_name, fingerprint, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING import_batchid;
+    `
+
+	b := batch.(entities.ImportBatch)
+	row := r.DB.QueryRowContext(ctx, q, b.PortfolioID, b.BrokerID, nullString(b.FileName), b.Fingerprint, b.CreatedAt, b.UpdatedAt)
+
+	err := row.Scan(&b.ImportBatchID)
+	if err != nil {
+		return "", err
+	}
+
+	return b.ImportBatchID, nil
+}
+
+func (r *importBatchRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	var where string
+	for k, v := range filter {
+		if where == "" {
+			where = "WHERE"
+		} else {
+			where = fmt.Sprintf("%s AND", where)
+		}
+		where = fmt.Sprintf("%s %s = '%v'", where, k, v)
+	}
+	if skip != nil {
+		where = fmt.Sprintf("%s OFFSET %d", where, *skip)
+	}
+	if take != nil {
+		where = fmt.Sprintf("%s LIMIT %d", where, *take)
+	}
+
+	q := fmt.Sprintf("%s %s;", selectImportBatch, where)
+
+	rows, err := r.DB.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var batches []interface{}
+	for rows.Next() {
+		b, err := scanImportBatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, &b)
+	}
+
+	if len(batches) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return batches, nil
+}
+
+func (r *importBatchRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	q := fmt.Sprintf("%s WHERE import_batchid = $1;", selectImportBatch)
+
+	row := r.DB.QueryRowContext(ctx, q, ID)
+
+	b, err := scanImportBatch(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+// GetByFingerprint returns the import batch of a portfolio whose statement file has the given SHA-256 hash
+func (r *importBatchRepository) GetByFingerprint(ctx context.Context, portfolioID, fingerprint string) (interface{}, error) {
+	q := fmt.Sprintf("%s WHERE portfolioid = $1 AND fingerprint = $2;", selectImportBatch)
+
+	row := r.DB.QueryRowContext(ctx, q, portfolioID, fingerprint)
+
+	b, err := scanImportBatch(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+func (r *importBatchRepository) Update(ctx context.Context, ID string, batch interface{}) error {
+	q := `
+	UPDATE import_batch set file_name=$1, updated_at=$2
+	    WHERE import_batchid=$3;
+	`
+
+	b := batch.(entities.ImportBatch)
+	result, err := r.DB.ExecContext(ctx, q, nullString(b.FileName), b.UpdatedAt, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *importBatchRepository) Delete(ctx context.Context, ID string) error {
+	q := `DELETE FROM import_batch WHERE import_batchid=$1;`
+
+	result, err := r.DB.ExecContext(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// Rollback deletes the dividend withholdings, dividends and transactions created by an import batch and the batch itself in one
+// database transaction, so either everything the batch created is gone or nothing is. Dividends are shared by every portfolio holding their stock, so
+// the ones of a stock traded in another portfolio are kept, unlinked from the batch.
+func (r *importBatchRepository) Rollback(ctx context.Context, ID string) (entities.ImportRollback, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return entities.ImportRollback{}, err
+	}
+
+	var rollback entities.ImportRollback
+	var batches int64
+	for _, statement := range []struct {
+		q       string
+		deleted *int64
+	}{
+		{`DELETE FROM dividend_withholding WHERE import_batchid=$1;`, &rollback.Withholdings},
+		{`DELETE FROM dividend d WHERE d.import_batchid=$1 AND NOT EXISTS (
+		    SELECT 1 FROM transaction t
+		        JOIN holding h ON h.holdingid = t.holdingid
+		        JOIN import_batch b ON b.import_batchid = $1
+		    WHERE t.stockid = d.stockid AND h.portfolioid <> b.portfolioid);`, &rollback.Dividends},
+		{`DELETE FROM transaction WHERE import_batchid=$1;`, &rollback.Transactions},
+		{`DELETE FROM import_batch WHERE import_batchid=$1;`, &batches},
+	} {
+		result, err := tx.ExecContext(ctx, statement.q, ID)
+		if err == nil {
+			*statement.deleted, err = result.RowsAffected()
+		}
+		if err != nil {
+			tx.Rollback()
+			return entities.ImportRollback{}, err
+		}
+	}
+	if batches < 1 {
+		tx.Rollback()
+		return entities.ImportRollback{}, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entities.ImportRollback{}, err
+	}
+	return rollback, nil
+}
+
+// scanImportBatch scans an import batch selected with selectImportBatch, whose file name is empty when null
+func scanImportBatch(row scanner) (entities.ImportBatch, error) {
+	var b entities.ImportBatch
+	var fileName sql.NullString
+	err := row.Scan(&b.ImportBatchID, &b.PortfolioID, &b.BrokerID, &fileName, &b.Fingerprint, &b.CreatedAt, &b.UpdatedAt)
+	b.FileName = fileName.String
+	return b, err
+}
@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// TestNewImportBatchRepository_Ok checks that NewImportBatchRepository creates a new importBatchRepository struct
+func TestNewImportBatchRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewImportBatchRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestCreateImportBatch_Ok checks that Create stores the batch with its fingerprint and returns its ID
+func TestCreateImportBatch_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &importBatchRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	batch := entities.ImportBatch{PortfolioID: "portfolio-a", BrokerID: "broker-a", FileName: "Transactions.csv", Fingerprint: "hash-a", CreatedAt: now, UpdatedAt: now}
+	expectedID := "0e5c7f1a-8b2d-4c3e-9f6a-1d2b3c4e5f60"
+	mock.ExpectQuery("INSERT INTO import_batch").
+		WithArgs("portfolio-a", "broker-a", sql.NullString{String: "Transactions.csv", Valid: true}, "hash-a", now, now).
+		WillReturnRows(sqlmock.NewRows([]string{"import_batchid"}).AddRow(expectedID))
+
+	// Act
+	id, err := repo.Create(context.Background(), batch)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedID, id)
+}
+
+// TestGetImportBatchByFingerprint_Ok checks that GetByFingerprint returns the batch of the portfolio with the fingerprint
+func TestGetImportBatchByFingerprint_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &importBatchRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	expectedBatch := &entities.ImportBatch{ImportBatchID: "batch-a", PortfolioID: "portfolio-a", BrokerID: "broker-a", Fingerprint: "hash-a", CreatedAt: now, UpdatedAt: now}
+	mock.ExpectQuery("SELECT (.+) FROM import_batch WHERE portfolioid = \\$1 AND fingerprint = \\$2").
+		WithArgs("portfolio-a", "hash-a").
+		WillReturnRows(sqlmock.NewRows([]string{"import_batchid", "portfolioid", "brokerid", "file_name", "fingerprint", "created_at", "updated_at"}).AddRow("batch-a", "portfolio-a", "broker-a", nil, "hash-a", now, now))
+
+	// Act
+	batch, err := repo.GetByFingerprint(context.Background(), "portfolio-a", "hash-a")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedBatch, batch)
+}
+
+// TestGetImportBatchByFingerprint_NotFound checks that GetByFingerprint returns a non existent error when no batch has the fingerprint
+func TestGetImportBatchByFingerprint_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &importBatchRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM import_batch").WillReturnRows(sqlmock.NewRows([]string{"import_batchid", "portfolioid", "brokerid", "file_name", "fingerprint", "created_at", "updated_at"}))
+
+	// Act
+	_, err := repo.GetByFingerprint(context.Background(), "portfolio-a", "hash-a")
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
+}
+
+// TestRollbackImportBatch_Ok checks that Rollback deletes the withholdings, dividends, transactions and the batch in one transaction
+func TestRollbackImportBatch_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &importBatchRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM dividend_withholding WHERE import_batchid").WithArgs("batch-a").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM dividend d WHERE d.import_batchid").WithArgs("batch-a").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM transaction WHERE import_batchid").WithArgs("batch-a").WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("DELETE FROM import_batch WHERE import_batchid").WithArgs("batch-a").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	rollback, err := repo.Rollback(context.Background(), "batch-a")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, entities.ImportRollback{Transactions: 3, Dividends: 1, Withholdings: 2}, rollback)
+}
+
+// TestRollbackImportBatch_DeleteError checks that Rollback rolls the transaction back when a delete statement fails
+func TestRollbackImportBatch_DeleteError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &importBatchRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "delete error"
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM dividend_withholding WHERE import_batchid").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM dividend d WHERE d.import_batchid").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM transaction WHERE import_batchid").WillReturnError(fmt.Errorf(expectedError))
+	mock.ExpectRollback()
+
+	// Act
+	_, err := repo.Rollback(context.Background(), "batch-a")
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestRollbackImportBatch_NotFound checks that Rollback returns a non existent error and deletes nothing when the batch does not exist
+func TestRollbackImportBatch_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &importBatchRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM dividend_withholding WHERE import_batchid").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM dividend d WHERE d.import_batchid").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM transaction WHERE import_batchid").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM import_batch WHERE import_batchid").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	// Act
+	_, err := repo.Rollback(context.Background(), "batch-a")
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,344 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// lotRepository adapter of a lot repository for postgres
+type lotRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewLotRepository creates a lot repository for postgres
+func NewLotRepository(db *sql.DB) ports.LotRepository {
+	return &lotRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (r *lotRepository) Create(ctx context.Context, lot interface{}) (string, error) {
+	q := `
+	INSERT INTO lot (portfolioid, transactionid, instrumentid, instrument_type, acquired_at, quantity, remaining_quantity, cost_per_unit, currency, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        RETURNING lotid;
+    `
+
+	l := lot.(entities.Lot)
+	row := r.DB.QueryRowContext(
+		ctx, q, l.PortfolioID, l.TransactionID, l.InstrumentID, l.InstrumentType, l.AcquiredAt, l.Quantity, l.RemainingQuantity, l.CostPerUnit.Amount, l.CostPerUnit.Currency, l.CreatedAt, l.UpdatedAt,
+	)
+
+	err := row.Scan(&l.LotID)
+	if err != nil {
+		return "", err
+	}
+
+	return l.LotID, nil
+}
+
+func (r *lotRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	var where string
+	for k, v := range filter {
+		if where == "" {
+			where = "WHERE"
+		} else {
+			where = fmt.Sprintf("%s AND", where)
+		}
+		where = fmt.Sprintf("%s %s = '%v'", where, k, v)
+	}
+	if skip != nil {
+		where = fmt.Sprintf("%s OFFSET %d", where, *skip)
+	}
+	if take != nil {
+		where = fmt.Sprintf("%s LIMIT %d", where, *take)
+	}
+
+	q := fmt.Sprintf(`
+	SELECT lotid, portfolioid, transactionid, instrumentid, instrument_type, acquired_at, quantity, remaining_quantity, cost_per_unit, currency, created_at, updated_at
+	    FROM lot %s;
+	`, where)
+
+	rows, err := r.DB.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanLots(rows)
+}
+
+func (r *lotRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	q := `
+    SELECT lotid, portfolioid, transactionid, instrumentid, instrument_type, acquired_at, quantity, remaining_quantity, cost_per_unit, currency, created_at, updated_at
+        FROM lot WHERE lotid = $1;
+    `
+
+	row := r.DB.QueryRowContext(ctx, q, ID)
+
+	var l entities.Lot
+	err := row.Scan(&l.LotID, &l.PortfolioID, &l.TransactionID, &l.InstrumentID, &l.InstrumentType, &l.AcquiredAt, &l.Quantity, &l.RemainingQuantity, &l.CostPerUnit.Amount, &l.CostPerUnit.Currency, &l.CreatedAt, &l.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+func (r *lotRepository) GetByPortfolioID(ctx context.Context, portfolioID string) ([]interface{}, error) {
+	q := `
+    SELECT lotid, portfolioid, transactionid, instrumentid, instrument_type, acquired_at, quantity, remaining_quantity, cost_per_unit, currency, created_at, updated_at
+        FROM lot WHERE portfolioid = $1
+        ORDER BY instrumentid, acquired_at;
+    `
+
+	rows, err := r.DB.QueryContext(ctx, q, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanLots(rows)
+}
+
+func (r *lotRepository) GetRealizedGainsByPortfolioID(ctx context.Context, portfolioID string) ([]interface{}, error) {
+	q := `
+    SELECT realized_gainid, portfolioid, lotid, buy_transactionid, sell_transactionid, instrumentid, instrument_type, method, quantity, acquired_at, sold_at, cost_basis, proceeds, gain, currency, created_at, updated_at
+        FROM realized_gain WHERE portfolioid = $1
+        ORDER BY sold_at, instrumentid, acquired_at;
+    `
+
+	rows, err := r.DB.QueryContext(ctx, q, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var gains []interface{}
+	for rows.Next() {
+		var g entities.RealizedGain
+		var currency string
+		err := rows.Scan(&g.RealizedGainID, &g.PortfolioID, &g.LotID, &g.BuyTransactionID, &g.SellTransactionID, &g.InstrumentID, &g.InstrumentType, &g.Method, &g.Quantity, &g.AcquiredAt, &g.SoldAt, &g.CostBasis.Amount, &g.Proceeds.Amount, &g.Gain.Amount, &currency, &g.CreatedAt, &g.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		g.CostBasis.Currency = currency
+		g.Proceeds.Currency = currency
+		g.Gain.Currency = currency
+		gains = append(gains, &g)
+	}
+
+	if len(gains) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return gains, nil
+}
+
+// StreamRealizedGainsByPortfolioID calls fn with each realized gain of a portfolio in the order of GetRealizedGainsByPortfolioID,
+// reading them one at a time. It stops at the first error returned by fn.
+func (r *lotRepository) StreamRealizedGainsByPortfolioID(ctx context.Context, portfolioID string, fn func(entities.RealizedGain) error) error {
+	q := `
+    SELECT realized_gainid, portfolioid, lotid, buy_transactionid, sell_transactionid, instrumentid, instrument_type, method, quantity, acquired_at, sold_at, cost_basis, proceeds, gain, currency, created_at, updated_at
+        FROM realized_gain WHERE portfolioid = $1
+        ORDER BY sold_at, instrumentid, acquired_at;
+    `
+
+	rows, err := r.DB.QueryContext(ctx, q, portfolioID)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var g entities.RealizedGain
+		var currency string
+		err := rows.Scan(&g.RealizedGainID, &g.PortfolioID, &g.LotID, &g.BuyTransactionID, &g.SellTransactionID, &g.InstrumentID, &g.InstrumentType, &g.Method, &g.Quantity, &g.AcquiredAt, &g.SoldAt, &g.CostBasis.Amount, &g.Proceeds.Amount, &g.Gain.Amount, &currency, &g.CreatedAt, &g.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		g.CostBasis.Currency = currency
+		g.Proceeds.Currency = currency
+		g.Gain.Currency = currency
+		if err := fn(g); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *lotRepository) Update(ctx context.Context, ID string, lot interface{}) error {
+	q := `
+	UPDATE lot set remaining_quantity=$1, cost_per_unit=$2, currency=$3, updated_at=$4
+	    WHERE lotid=$5;
+	`
+
+	l := lot.(entities.Lot)
+	result, err := r.DB.ExecContext(
+		ctx, q, l.RemainingQuantity, l.CostPerUnit.Amount, l.CostPerUnit.Currency, l.UpdatedAt, ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		fmt.Println("RowsAffected Error", err)
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *lotRepository) Delete(ctx context.Context, ID string) error {
+	q := `DELETE FROM lot WHERE lotid=$1;`
+
+	result, err := r.DB.ExecContext(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *lotRepository) CreateMany(ctx context.Context, lots []interface{}) ([]string, error) {
+	// `tx` is an instance of `*sql.Tx` through which we can execute our queries
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, entity := range lots {
+		l := entity.(entities.Lot)
+
+		id, err := insertLot(ctx, tx, l)
+		if err != nil {
+			// Incase we find any error in the query execution, rollback the transaction
+			tx.Rollback()
+			return nil, err
+		}
+		result = append(result, id)
+	}
+
+	// Finally, if no errors are recieved from the queries, commit the transaction
+	// this applies the above changes to our database
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ReplaceLedger atomically swaps the lots and realized gains of a portfolio for the given ones.
+// Realized gains are linked to the lot created from their buy transaction for their instrument, as a buy of a stock that was spun
+// off creates a lot of both the parent and the spun-off stock.
+func (r *lotRepository) ReplaceLedger(ctx context.Context, portfolioID string, lots []interface{}, gains []interface{}) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, q := range []string{
+		`DELETE FROM realized_gain WHERE portfolioid=$1;`,
+		`DELETE FROM lot WHERE portfolioid=$1;`,
+	} {
+		if _, err := tx.ExecContext(ctx, q, portfolioID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	lotIDs := map[lotKey]string{}
+	for _, entity := range lots {
+		l := entity.(entities.Lot)
+		l.PortfolioID = portfolioID
+
+		id, err := insertLot(ctx, tx, l)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		lotIDs[lotKey{l.TransactionID, l.InstrumentID}] = id
+	}
+
+	for _, entity := range gains {
+		g := entity.(entities.RealizedGain)
+
+		q := `
+		INSERT INTO realized_gain (portfolioid, lotid, buy_transactionid, sell_transactionid, instrumentid, instrument_type, method, quantity, acquired_at, sold_at, cost_basis, proceeds, gain, currency, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16);`
+
+		_, err := tx.ExecContext(
+			ctx, q, portfolioID, lotIDs[lotKey{g.BuyTransactionID, g.InstrumentID}], g.BuyTransactionID, g.SellTransactionID, g.InstrumentID, g.InstrumentType, g.Method, g.Quantity, g.AcquiredAt, g.SoldAt, g.CostBasis.Amount, g.Proceeds.Amount, g.Gain.Amount, g.Gain.Currency, g.CreatedAt, g.UpdatedAt,
+		)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// lotKey identifies a lot of a ledger by the transaction that created it and its instrument
+type lotKey struct {
+	transactionID string
+	instrumentID  string
+}
+
+func insertLot(ctx context.Context, tx *sql.Tx, l entities.Lot) (string, error) {
+	q := `
+	INSERT INTO lot (portfolioid, transactionid, instrumentid, instrument_type, acquired_at, quantity, remaining_quantity, cost_per_unit, currency, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING lotid;`
+
+	// Here, the query is executed on the transaction instance, and not applied to the database yet
+	row := tx.QueryRowContext(
+		ctx, q, l.PortfolioID, l.TransactionID, l.InstrumentID, l.InstrumentType, l.AcquiredAt, l.Quantity, l.RemainingQuantity, l.CostPerUnit.Amount, l.CostPerUnit.Currency, l.CreatedAt, l.UpdatedAt,
+	)
+	err := row.Scan(&l.LotID)
+	return l.LotID, err
+}
+
+func scanLots(rows *sql.Rows) ([]interface{}, error) {
+	var lots []interface{}
+	for rows.Next() {
+		var l entities.Lot
+		err := rows.Scan(&l.LotID, &l.PortfolioID, &l.TransactionID, &l.InstrumentID, &l.InstrumentType, &l.AcquiredAt, &l.Quantity, &l.RemainingQuantity, &l.CostPerUnit.Amount, &l.CostPerUnit.Currency, &l.CreatedAt, &l.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		lots = append(lots, &l)
+	}
+
+	if len(lots) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return lots, nil
+}
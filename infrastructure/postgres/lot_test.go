@@ -0,0 +1,283 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+var lotColumns = []string{"lotid", "portfolioid", "transactionid", "instrumentid", "instrument_type", "acquired_at", "quantity", "remaining_quantity", "cost_per_unit", "currency", "created_at", "updated_at"}
+
+// TestNewLotRepository_Ok checks that NewLotRepository creates a new lotRepository struct
+func TestNewLotRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewLotRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestCreateLot_Ok checks that Create returns the expected response when a valid entity is received
+func TestCreateLot_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &lotRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedID := "f8352727-231e-4de1-8257-c235a0af5c4a"
+	mock.ExpectQuery("INSERT INTO lot").WillReturnRows(sqlmock.NewRows([]string{"lotid"}).AddRow(expectedID))
+
+	// Act
+	id, err := repo.Create(context.Background(), entities.Lot{})
+
+	// Assert
+	assert.Equal(t, expectedID, id)
+	assert.Nil(t, err)
+}
+
+// TestGetLotByID_NoResourcesFound checks that GetByID returns an error when no rows are found
+func TestGetLotByID_NoResourcesFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &lotRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+	mock.ExpectQuery("SELECT (.+) FROM lot").WillReturnRows(sqlmock.NewRows(lotColumns))
+
+	// Act
+	_, err := repo.GetByID(context.Background(), "")
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
+}
+
+// TestGetLotsByPortfolioID_Ok checks that GetByPortfolioID returns the expected response when the portfolio has lots
+func TestGetLotsByPortfolioID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &lotRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedLot := entities.Lot{
+		LotID:             "f8352727-231e-4de1-8257-c235a0af5c4a",
+		PortfolioID:       "portfolio-id",
+		Quantity:          decimal.NewFromInt(10),
+		RemainingQuantity: decimal.NewFromInt(4),
+		CostPerUnit:       entities.NewMoney(decimal.RequireFromString("12.5"), "CZK"),
+	}
+	mock.ExpectQuery("SELECT (.+) FROM lot WHERE portfolioid").WithArgs("portfolio-id").WillReturnRows(sqlmock.NewRows(lotColumns).
+		AddRow(expectedLot.LotID, expectedLot.PortfolioID, expectedLot.TransactionID, expectedLot.InstrumentID, expectedLot.InstrumentType, expectedLot.AcquiredAt, expectedLot.Quantity, expectedLot.RemainingQuantity, expectedLot.CostPerUnit.Amount, expectedLot.CostPerUnit.Currency, expectedLot.CreatedAt, expectedLot.UpdatedAt))
+
+	// Act
+	result, err := repo.GetByPortfolioID(context.Background(), "portfolio-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, len(result) == 1)
+
+	entity := *(result[0].(*entities.Lot))
+	assert.Equal(t, expectedLot, entity)
+}
+
+// TestStreamRealizedGainsByPortfolioID_Ok checks that StreamRealizedGainsByPortfolioID calls fn with each realized gain of the portfolio
+func TestStreamRealizedGainsByPortfolioID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &lotRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedGain := entities.RealizedGain{
+		RealizedGainID:    "f8352727-231e-4de1-8257-c235a0af5c4a",
+		PortfolioID:       "portfolio-id",
+		BuyTransactionID:  "buy-id",
+		SellTransactionID: "sell-id",
+		InstrumentID:      "stock-id",
+		InstrumentType:    entities.EntityNameStock,
+		Method:            entities.CostBasisFIFO,
+		Quantity:          decimal.NewFromInt(2),
+		CostBasis:         entities.NewMoney(decimal.NewFromInt(20), "USD"),
+		Proceeds:          entities.NewMoney(decimal.NewFromInt(30), "USD"),
+		Gain:              entities.NewMoney(decimal.NewFromInt(10), "USD"),
+	}
+	mock.ExpectQuery("SELECT (.+) FROM realized_gain WHERE portfolioid = (.+) ORDER BY").WithArgs("portfolio-id").WillReturnRows(sqlmock.NewRows([]string{"realized_gainid", "portfolioid", "lotid", "buy_transactionid", "sell_transactionid", "instrumentid", "instrument_type", "method", "quantity", "acquired_at", "sold_at", "cost_basis", "proceeds", "gain", "currency", "created_at", "updated_at"}).
+		AddRow(expectedGain.RealizedGainID, expectedGain.PortfolioID, expectedGain.LotID, expectedGain.BuyTransactionID, expectedGain.SellTransactionID, expectedGain.InstrumentID, expectedGain.InstrumentType, expectedGain.Method, expectedGain.Quantity, expectedGain.AcquiredAt, expectedGain.SoldAt, expectedGain.CostBasis.Amount, expectedGain.Proceeds.Amount, expectedGain.Gain.Amount, "USD", expectedGain.CreatedAt, expectedGain.UpdatedAt))
+
+	// Act
+	var result []entities.RealizedGain
+	err := repo.StreamRealizedGainsByPortfolioID(context.Background(), "portfolio-id", func(g entities.RealizedGain) error {
+		result = append(result, g)
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []entities.RealizedGain{expectedGain}, result)
+}
+
+// TestGetRealizedGainsByPortfolioID_NoResourcesFound checks that GetRealizedGainsByPortfolioID returns an error when the portfolio has no realized gains
+func TestGetRealizedGainsByPortfolioID_NoResourcesFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &lotRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+	mock.ExpectQuery("SELECT (.+) FROM realized_gain").WillReturnRows(sqlmock.NewRows([]string{"realized_gainid"}))
+
+	// Act
+	_, err := repo.GetRealizedGainsByPortfolioID(context.Background(), "portfolio-id")
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
+}
+
+// TestCreateManyLots_Ok checks that CreateMany does not return an error when everything goes as expected
+func TestCreateManyLots_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &lotRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedID := "f8352727-231e-4de1-8257-c235a0af5c4a"
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO lot").WillReturnRows(sqlmock.NewRows([]string{"lotid"}).AddRow(expectedID))
+	mock.ExpectCommit()
+
+	// Act
+	ids, err := repo.CreateMany(context.Background(), []interface{}{entities.Lot{}})
+
+	// Assert
+	assert.Equal(t, []string{expectedID}, ids)
+	assert.Nil(t, err)
+}
+
+// TestReplaceLedger_Ok checks that ReplaceLedger deletes the previous ledger and links every realized gain to the lot of its buy transaction
+func TestReplaceLedger_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &lotRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	lotID := "f8352727-231e-4de1-8257-c235a0af5c4a"
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM realized_gain").WithArgs("portfolio-id").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM lot").WithArgs("portfolio-id").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO lot").WillReturnRows(sqlmock.NewRows([]string{"lotid"}).AddRow(lotID))
+	mock.ExpectExec("INSERT INTO realized_gain").WithArgs("portfolio-id", lotID, "buy-id", "sell-id", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err := repo.ReplaceLedger(context.Background(), "portfolio-id",
+		[]interface{}{entities.Lot{TransactionID: "buy-id"}},
+		[]interface{}{entities.RealizedGain{BuyTransactionID: "buy-id", SellTransactionID: "sell-id"}},
+	)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestReplaceLedger_DeleteError checks that ReplaceLedger rolls back and returns an error when the delete statement fails
+func TestReplaceLedger_DeleteError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &lotRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "delete error"
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM realized_gain").WillReturnError(fmt.Errorf(expectedError))
+	mock.ExpectRollback()
+
+	// Act
+	err := repo.ReplaceLedger(context.Background(), "portfolio-id", nil, nil)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestReplaceLedger_SpinOff checks that ReplaceLedger links the realized gains of a spun-off stock to its own lot rather than to
+// the lot of the parent stock created from the same buy transaction
+func TestReplaceLedger_SpinOff(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &lotRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	parentLotID := "f8352727-231e-4de1-8257-c235a0af5c4a"
+	spunOffLotID := "0e5c7f1a-8b2d-4c3e-9f6a-1d2b3c4e5f60"
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM realized_gain").WithArgs("portfolio-id").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM lot").WithArgs("portfolio-id").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO lot").WillReturnRows(sqlmock.NewRows([]string{"lotid"}).AddRow(parentLotID))
+	mock.ExpectQuery("INSERT INTO lot").WillReturnRows(sqlmock.NewRows([]string{"lotid"}).AddRow(spunOffLotID))
+	mock.ExpectExec("INSERT INTO realized_gain").WithArgs("portfolio-id", parentLotID, "buy-id", "sell-a", "stock-a", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO realized_gain").WithArgs("portfolio-id", spunOffLotID, "buy-id", "sell-b", "stock-b", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err := repo.ReplaceLedger(context.Background(), "portfolio-id",
+		[]interface{}{entities.Lot{TransactionID: "buy-id", InstrumentID: "stock-a"}, entities.Lot{TransactionID: "buy-id", InstrumentID: "stock-b"}},
+		[]interface{}{
+			entities.RealizedGain{BuyTransactionID: "buy-id", SellTransactionID: "sell-a", InstrumentID: "stock-a"},
+			entities.RealizedGain{BuyTransactionID: "buy-id", SellTransactionID: "sell-b", InstrumentID: "stock-b"},
+		},
+	)
+
+	// Assert
+	assert.Nil(t, err)
+}
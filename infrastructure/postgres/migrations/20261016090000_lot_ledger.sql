@@ -0,0 +1,48 @@
+-- +goose Up
+CREATE TABLE public.lot (
+    lotid uuid DEFAULT uuid_generate_v4 (),
+    portfolioid uuid,
+    transactionid uuid,
+    instrumentid uuid,
+    instrument_type varchar,
+    acquired_at timestamp,
+    quantity numeric,
+    remaining_quantity numeric,
+    cost_per_unit numeric,
+    created_at timestamp,
+    updated_at timestamp,
+    PRIMARY KEY(lotid),
+    FOREIGN KEY(portfolioid) REFERENCES public.portfolio(portfolioid) ON DELETE CASCADE,
+    FOREIGN KEY(transactionid) REFERENCES public.transaction(transactionid) ON DELETE CASCADE
+);
+
+CREATE INDEX lot_portfolioid_idx ON public.lot (portfolioid);
+
+CREATE TABLE public.realized_gain (
+    realized_gainid uuid DEFAULT uuid_generate_v4 (),
+    portfolioid uuid,
+    lotid uuid,
+    buy_transactionid uuid,
+    sell_transactionid uuid,
+    instrumentid uuid,
+    instrument_type varchar,
+    method int,
+    quantity numeric,
+    acquired_at timestamp,
+    sold_at timestamp,
+    cost_basis numeric,
+    proceeds numeric,
+    gain numeric,
+    created_at timestamp,
+    updated_at timestamp,
+    PRIMARY KEY(realized_gainid),
+    FOREIGN KEY(portfolioid) REFERENCES public.portfolio(portfolioid) ON DELETE CASCADE,
+    FOREIGN KEY(lotid) REFERENCES public.lot(lotid) ON DELETE CASCADE,
+    FOREIGN KEY(sell_transactionid) REFERENCES public.transaction(transactionid) ON DELETE CASCADE
+);
+
+CREATE INDEX realized_gain_portfolioid_idx ON public.realized_gain (portfolioid);
+
+-- +goose Down
+DROP TABLE public.realized_gain;
+DROP TABLE public.lot;
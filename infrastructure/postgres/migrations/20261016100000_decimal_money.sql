@@ -0,0 +1,58 @@
+-- +goose Up
+ALTER TABLE public.holding
+    ALTER COLUMN quantity TYPE numeric USING quantity::numeric,
+    ALTER COLUMN share_price TYPE numeric USING share_price::numeric,
+    ALTER COLUMN exchange_rate TYPE numeric USING exchange_rate::numeric,
+    ALTER COLUMN brokerage_unit_price TYPE numeric USING brokerage_unit_price::numeric,
+    ADD COLUMN share_price_currency varchar(3) NOT NULL DEFAULT '',
+    ADD COLUMN brokerage_currency_code varchar(3) NOT NULL DEFAULT '';
+
+UPDATE public.holding h
+    SET brokerage_currency_code = c.code
+    FROM public.currency c
+    WHERE c.currencyid = h.brokerage_currency;
+
+ALTER TABLE public.holding DROP COLUMN brokerage_currency;
+ALTER TABLE public.holding RENAME COLUMN brokerage_currency_code TO brokerage_currency;
+
+ALTER TABLE public.transaction
+    ALTER COLUMN quantity TYPE numeric USING quantity::numeric,
+    ALTER COLUMN transaction_price TYPE numeric USING transaction_price::numeric,
+    ADD COLUMN transaction_currency varchar(3) NOT NULL DEFAULT '';
+
+ALTER TABLE public.dividend
+    ALTER COLUMN dividend_per_share TYPE numeric USING dividend_per_share::numeric,
+    ADD COLUMN dividend_currency varchar(3) NOT NULL DEFAULT '';
+
+ALTER TABLE public.lot ADD COLUMN currency varchar(3) NOT NULL DEFAULT '';
+ALTER TABLE public.realized_gain ADD COLUMN currency varchar(3) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE public.realized_gain DROP COLUMN currency;
+ALTER TABLE public.lot DROP COLUMN currency;
+
+ALTER TABLE public.dividend
+    DROP COLUMN dividend_currency,
+    ALTER COLUMN dividend_per_share TYPE float USING dividend_per_share::float;
+
+ALTER TABLE public.transaction
+    DROP COLUMN transaction_currency,
+    ALTER COLUMN transaction_price TYPE float USING transaction_price::float,
+    ALTER COLUMN quantity TYPE int USING round(quantity)::int;
+
+ALTER TABLE public.holding ADD COLUMN brokerage_currency_id uuid;
+
+UPDATE public.holding h
+    SET brokerage_currency_id = c.currencyid
+    FROM public.currency c
+    WHERE c.code = h.brokerage_currency;
+
+ALTER TABLE public.holding DROP COLUMN brokerage_currency;
+ALTER TABLE public.holding RENAME COLUMN brokerage_currency_id TO brokerage_currency;
+
+ALTER TABLE public.holding
+    DROP COLUMN share_price_currency,
+    ALTER COLUMN brokerage_unit_price TYPE float USING brokerage_unit_price::float,
+    ALTER COLUMN exchange_rate TYPE float USING exchange_rate::float,
+    ALTER COLUMN share_price TYPE float USING share_price::float,
+    ALTER COLUMN quantity TYPE int USING round(quantity)::int;
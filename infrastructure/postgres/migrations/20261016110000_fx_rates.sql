@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE public.fx_rate (
+    fx_rateid uuid DEFAULT uuid_generate_v4 (),
+    base_currency varchar(3) NOT NULL,
+    quote_currency varchar(3) NOT NULL,
+    rate_date date NOT NULL,
+    rate numeric NOT NULL,
+    source varchar NOT NULL DEFAULT '',
+    created_at timestamp,
+    updated_at timestamp,
+    PRIMARY KEY(fx_rateid),
+    UNIQUE(base_currency, quote_currency, rate_date)
+);
+
+ALTER TABLE public.portfolio ADD COLUMN base_currency varchar(3) NOT NULL DEFAULT '';
+ALTER TABLE public."user" ADD COLUMN base_currency varchar(3) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE public."user" DROP COLUMN base_currency;
+ALTER TABLE public.portfolio DROP COLUMN base_currency;
+DROP TABLE public.fx_rate;
@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE public.price (
+    priceid uuid DEFAULT uuid_generate_v4 (),
+    instrumentid uuid NOT NULL,
+    instrument_type varchar NOT NULL,
+    price_date date NOT NULL,
+    open numeric NOT NULL DEFAULT 0,
+    high numeric NOT NULL DEFAULT 0,
+    low numeric NOT NULL DEFAULT 0,
+    close numeric NOT NULL,
+    volume numeric NOT NULL DEFAULT 0,
+    currency varchar(3) NOT NULL,
+    source varchar NOT NULL DEFAULT '',
+    created_at timestamp,
+    updated_at timestamp,
+    PRIMARY KEY(priceid),
+    UNIQUE(instrumentid, price_date)
+);
+
+CREATE TABLE public.last_price (
+    instrumentid uuid,
+    instrument_type varchar NOT NULL,
+    price numeric NOT NULL,
+    currency varchar(3) NOT NULL,
+    priced_at timestamp NOT NULL,
+    source varchar NOT NULL DEFAULT '',
+    updated_at timestamp,
+    PRIMARY KEY(instrumentid)
+);
+
+-- +goose Down
+DROP TABLE public.last_price;
+DROP TABLE public.price;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE public.price_refresh_run (
+    price_refresh_runid uuid DEFAULT uuid_generate_v4 (),
+    provider varchar NOT NULL,
+    started_at timestamp NOT NULL,
+    finished_at timestamp NOT NULL,
+    duration_ms bigint NOT NULL DEFAULT 0,
+    instruments integer NOT NULL DEFAULT 0,
+    updated integer NOT NULL DEFAULT 0,
+    failed integer NOT NULL DEFAULT 0,
+    failures jsonb NOT NULL DEFAULT '[]',
+    PRIMARY KEY(price_refresh_runid)
+);
+
+CREATE INDEX price_refresh_run_started_at_idx ON public.price_refresh_run (started_at DESC);
+
+-- +goose Down
+DROP TABLE public.price_refresh_run;
@@ -0,0 +1,43 @@
+-- +goose Up
+CREATE TABLE public.alert_rule (
+    alert_ruleid uuid DEFAULT uuid_generate_v4 (),
+    portfolioid uuid NOT NULL,
+    instrumentid uuid NOT NULL,
+    instrument_type varchar NOT NULL,
+    condition varchar NOT NULL,
+    threshold numeric NOT NULL,
+    active boolean NOT NULL DEFAULT true,
+    created_at timestamp,
+    updated_at timestamp,
+    PRIMARY KEY(alert_ruleid),
+    FOREIGN KEY(portfolioid) REFERENCES public.portfolio(portfolioid) ON DELETE CASCADE
+);
+
+CREATE INDEX alert_rule_portfolioid_idx ON public.alert_rule (portfolioid);
+
+CREATE TABLE public.alert (
+    alertid uuid DEFAULT uuid_generate_v4 (),
+    alert_ruleid uuid NOT NULL,
+    portfolioid uuid NOT NULL,
+    instrumentid uuid NOT NULL,
+    instrument_type varchar NOT NULL,
+    condition varchar NOT NULL,
+    threshold numeric NOT NULL,
+    price numeric NOT NULL,
+    currency varchar NOT NULL DEFAULT '',
+    value numeric NOT NULL,
+    trigger_date date NOT NULL,
+    triggered_at timestamp NOT NULL,
+    notified_at timestamp,
+    PRIMARY KEY(alertid),
+    UNIQUE(alert_ruleid, trigger_date),
+    FOREIGN KEY(alert_ruleid) REFERENCES public.alert_rule(alert_ruleid) ON DELETE CASCADE,
+    FOREIGN KEY(portfolioid) REFERENCES public.portfolio(portfolioid) ON DELETE CASCADE
+);
+
+CREATE INDEX alert_portfolioid_idx ON public.alert (portfolioid);
+CREATE INDEX alert_pending_idx ON public.alert (triggered_at) WHERE notified_at IS NULL;
+
+-- +goose Down
+DROP TABLE public.alert;
+DROP TABLE public.alert_rule;
@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE public.company_event (
+    company_eventid uuid DEFAULT uuid_generate_v4 (),
+    stockid uuid NOT NULL,
+    event_type varchar NOT NULL,
+    event_date date NOT NULL,
+    amount numeric NOT NULL DEFAULT 0,
+    currency varchar NOT NULL DEFAULT '',
+    split_numerator numeric NOT NULL DEFAULT 0,
+    split_denominator numeric NOT NULL DEFAULT 0,
+    description varchar NOT NULL DEFAULT '',
+    source varchar NOT NULL DEFAULT '',
+    created_at timestamp,
+    updated_at timestamp,
+    PRIMARY KEY(company_eventid),
+    UNIQUE(stockid, event_type, event_date),
+    FOREIGN KEY(stockid) REFERENCES public.stock(stockid) ON DELETE CASCADE
+);
+
+CREATE INDEX company_event_event_date_idx ON public.company_event (event_date);
+
+CREATE TABLE public.company_event_notification (
+    company_eventid uuid NOT NULL,
+    portfolioid uuid NOT NULL,
+    notified_at timestamp NOT NULL,
+    PRIMARY KEY(company_eventid, portfolioid),
+    FOREIGN KEY(company_eventid) REFERENCES public.company_event(company_eventid) ON DELETE CASCADE,
+    FOREIGN KEY(portfolioid) REFERENCES public.portfolio(portfolioid) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE public.company_event_notification;
+DROP TABLE public.company_event;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE public.digest (
+    digestid uuid DEFAULT uuid_generate_v4 (),
+    portfolioid uuid NOT NULL,
+    period varchar NOT NULL,
+    period_start date NOT NULL,
+    period_end date NOT NULL,
+    sent_to varchar NOT NULL DEFAULT '',
+    sent_at timestamp NOT NULL,
+    PRIMARY KEY(digestid),
+    UNIQUE(portfolioid, period, period_end),
+    FOREIGN KEY(portfolioid) REFERENCES public.portfolio(portfolioid) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE public.digest;
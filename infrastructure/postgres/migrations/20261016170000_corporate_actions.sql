@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE public.corporate_action (
+    corporate_actionid uuid DEFAULT uuid_generate_v4 (),
+    stockid uuid NOT NULL,
+    action_type varchar NOT NULL,
+    effective_date date NOT NULL,
+    numerator numeric NOT NULL DEFAULT 0,
+    denominator numeric NOT NULL DEFAULT 0,
+    target_stockid uuid,
+    cost_allocation numeric NOT NULL DEFAULT 0,
+    old_symbol varchar NOT NULL DEFAULT '',
+    new_symbol varchar NOT NULL DEFAULT '',
+    new_isin varchar NOT NULL DEFAULT '',
+    description varchar NOT NULL DEFAULT '',
+    created_at timestamp,
+    updated_at timestamp,
+    PRIMARY KEY(corporate_actionid),
+    UNIQUE(stockid, action_type, effective_date),
+    FOREIGN KEY(stockid) REFERENCES public.stock(stockid) ON DELETE CASCADE,
+    FOREIGN KEY(target_stockid) REFERENCES public.stock(stockid) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE public.corporate_action;
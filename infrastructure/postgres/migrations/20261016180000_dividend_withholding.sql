@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE public.stock ADD COLUMN country varchar(2) NOT NULL DEFAULT '';
+
+ALTER TABLE public.dividend RENAME COLUMN dividend_date TO ex_date;
+ALTER TABLE public.dividend
+    ADD COLUMN record_date timestamp,
+    ADD COLUMN pay_date timestamp;
+
+UPDATE public.dividend SET pay_date = ex_date;
+
+-- +goose Down
+ALTER TABLE public.dividend
+    DROP COLUMN pay_date,
+    DROP COLUMN record_date;
+ALTER TABLE public.dividend RENAME COLUMN ex_date TO dividend_date;
+
+ALTER TABLE public.stock DROP COLUMN country;
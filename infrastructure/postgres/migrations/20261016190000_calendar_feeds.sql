@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE public.calendar_feed (
+    calendar_feedid uuid DEFAULT uuid_generate_v4 (),
+    userid uuid NOT NULL,
+    token_hash varchar(64) NOT NULL,
+    created_at timestamp,
+    updated_at timestamp,
+    PRIMARY KEY(calendar_feedid),
+    UNIQUE(userid),
+    UNIQUE(token_hash),
+    FOREIGN KEY(userid) REFERENCES public.user(userid) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE public.calendar_feed;
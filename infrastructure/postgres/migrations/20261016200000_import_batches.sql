@@ -0,0 +1,37 @@
+-- +goose Up
+CREATE TABLE public.import_batch (
+    import_batchid uuid DEFAULT uuid_generate_v4 (),
+    portfolioid uuid NOT NULL,
+    brokerid uuid NOT NULL,
+    file_name varchar(255),
+    fingerprint varchar(64) NOT NULL,
+    created_at timestamp,
+    updated_at timestamp,
+    PRIMARY KEY(import_batchid),
+    UNIQUE(portfolioid, fingerprint),
+    FOREIGN KEY(portfolioid) REFERENCES public.portfolio(portfolioid) ON DELETE CASCADE,
+    FOREIGN KEY(brokerid) REFERENCES public.broker(brokerid)
+);
+
+ALTER TABLE public.transaction
+    ADD COLUMN extid varchar(255),
+    ADD COLUMN import_batchid uuid REFERENCES public.import_batch(import_batchid) ON DELETE SET NULL;
+
+ALTER TABLE public.dividend
+    ADD COLUMN import_batchid uuid REFERENCES public.import_batch(import_batchid) ON DELETE SET NULL;
+
+CREATE INDEX transaction_import_batchid_idx ON public.transaction (import_batchid);
+CREATE INDEX dividend_import_batchid_idx ON public.dividend (import_batchid);
+
+-- +goose Down
+DROP INDEX public.dividend_import_batchid_idx;
+DROP INDEX public.transaction_import_batchid_idx;
+
+ALTER TABLE public.dividend
+    DROP COLUMN import_batchid;
+
+ALTER TABLE public.transaction
+    DROP COLUMN import_batchid,
+    DROP COLUMN extid;
+
+DROP TABLE public.import_batch;
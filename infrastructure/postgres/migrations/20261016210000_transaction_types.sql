@@ -0,0 +1,21 @@
+-- +goose Up
+UPDATE public.transaction
+    SET transaction_type = CASE WHEN quantity < 0 THEN 'SELL' ELSE 'BUY' END
+    WHERE transaction_type IS NULL OR transaction_type = '';
+
+ALTER TABLE public.transaction
+    ALTER COLUMN transaction_type TYPE varchar(16),
+    ALTER COLUMN transaction_type SET DEFAULT 'BUY',
+    ALTER COLUMN transaction_type SET NOT NULL,
+    ADD CONSTRAINT transaction_type_check CHECK (transaction_type IN ('BUY', 'SELL', 'DIVIDEND', 'FEE', 'DEPOSIT', 'WITHDRAWAL', 'INTEREST', 'TRANSFER_IN', 'TRANSFER_OUT', 'SPLIT'));
+
+CREATE INDEX transaction_holdingid_idx ON public.transaction (holdingid);
+
+-- +goose Down
+DROP INDEX public.transaction_holdingid_idx;
+
+ALTER TABLE public.transaction
+    DROP CONSTRAINT transaction_type_check,
+    ALTER COLUMN transaction_type DROP NOT NULL,
+    ALTER COLUMN transaction_type DROP DEFAULT,
+    ALTER COLUMN transaction_type TYPE varchar;
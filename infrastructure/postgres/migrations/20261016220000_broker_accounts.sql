@@ -0,0 +1,47 @@
+-- +goose Up
+CREATE TABLE public.broker_account (
+    broker_accountid uuid DEFAULT uuid_generate_v4 (),
+    userid uuid NOT NULL,
+    brokerid uuid NOT NULL,
+    account_label varchar(100) NOT NULL,
+    account_number varchar(64),
+    base_currency varchar(3) NOT NULL,
+    opened_at timestamp NOT NULL,
+    closed_at timestamp,
+    created_at timestamp,
+    updated_at timestamp,
+    PRIMARY KEY(broker_accountid),
+    UNIQUE(userid, brokerid, account_label),
+    FOREIGN KEY(userid) REFERENCES public."user"(userid) ON DELETE CASCADE,
+    FOREIGN KEY(brokerid) REFERENCES public.broker(brokerid)
+);
+
+ALTER TABLE public.holding
+    ADD COLUMN broker_accountid uuid REFERENCES public.broker_account(broker_accountid);
+
+CREATE INDEX holding_broker_accountid_idx ON public.holding (broker_accountid);
+
+-- Every user gets one account per broker they already hold at, in their base currency or else the one of their holdings there,
+-- and the existing holdings are attached to it
+INSERT INTO public.broker_account (userid, brokerid, account_label, base_currency, opened_at, created_at, updated_at)
+    SELECT p.userid, h.brokerid, COALESCE(NULLIF(b.name, ''), 'Account'),
+            COALESCE(NULLIF(u.base_currency, ''), NULLIF(MIN(h.share_price_currency), ''), 'EUR'),
+            COALESCE(MIN(h.trade_date), MIN(h.created_at), now()), now(), now()
+        FROM public.holding h
+        INNER JOIN public.portfolio p ON p.portfolioid = h.portfolioid
+        INNER JOIN public."user" u ON u.userid = p.userid
+        INNER JOIN public.broker b ON b.brokerid = h.brokerid
+        GROUP BY p.userid, h.brokerid, b.name, u.base_currency;
+
+UPDATE public.holding h
+    SET broker_accountid = a.broker_accountid
+    FROM public.portfolio p, public.broker_account a
+    WHERE p.portfolioid = h.portfolioid AND a.userid = p.userid AND a.brokerid = h.brokerid;
+
+-- +goose Down
+DROP INDEX public.holding_broker_accountid_idx;
+
+ALTER TABLE public.holding
+    DROP COLUMN broker_accountid;
+
+DROP TABLE public.broker_account;
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE public.dividend_withholding (
+    dividend_withholdingid uuid DEFAULT uuid_generate_v4 (),
+    portfolioid uuid NOT NULL,
+    dividendid uuid NOT NULL,
+    amount numeric NOT NULL,
+    currency varchar(3) NOT NULL,
+    import_batchid uuid,
+    created_at timestamp,
+    updated_at timestamp,
+    PRIMARY KEY(dividend_withholdingid),
+    UNIQUE(portfolioid, dividendid),
+    FOREIGN KEY(portfolioid) REFERENCES public.portfolio(portfolioid) ON DELETE CASCADE,
+    FOREIGN KEY(dividendid) REFERENCES public.dividend(dividendid) ON DELETE CASCADE,
+    FOREIGN KEY(import_batchid) REFERENCES public.import_batch(import_batchid) ON DELETE SET NULL
+);
+
+CREATE INDEX dividend_withholding_import_batchid_idx ON public.dividend_withholding (import_batchid);
+
+-- +goose Down
+DROP INDEX public.dividend_withholding_import_batchid_idx;
+
+DROP TABLE public.dividend_withholding;
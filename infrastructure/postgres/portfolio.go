@@ -28,14 +28,14 @@ func NewPortfolioRepository(db *sql.DB) ports.PortfolioRepository {
 
 func (r *portfolioRepository) Create(ctx context.Context, portfolio interface{}) (string, error) {
 	q := `
-	INSERT INTO portfolio (userid,name,extid,tax_countryid,financial_year,performence_calculation,summary,price_alert,company_event_alert,created_at,updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6,$7,$8,$9,$10,$11)
+	INSERT INTO portfolio (userid,name,extid,tax_countryid,financial_year,performence_calculation,summary,price_alert,company_event_alert,base_currency,created_at,updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6,$7,$8,$9,$10,$11,$12)
         RETURNING portfolioid;
     `
 
 	c := portfolio.(entities.Portfolio)
 	row := r.DB.QueryRowContext(
-		ctx, q, c.Userid, c.Name, c.Extid, c.TaxCountryID, c.FinancialYear, c.PerformenceCalculation, c.Summary, c.PriceAlert, c.CompanyEventAlert, c.CreatedAt, c.UpdatedAt,
+		ctx, q, c.Userid, c.Name, c.Extid, c.TaxCountryID, c.FinancialYear, c.PerformenceCalculation, c.Summary, c.PriceAlert, c.CompanyEventAlert, c.BaseCurrency, c.CreatedAt, c.UpdatedAt,
 	)
 
 	err := row.Scan(&c.PortfolioID)
@@ -64,7 +64,7 @@ func (r *portfolioRepository) Get(ctx context.Context, filter map[string]interfa
 	}
 
 	q := fmt.Sprintf(`
-		SELECT portfolioid,userid,name,extid,tax_countryid,financial_year,performence_calculation,summary,price_alert,company_event_alert,created_at,updated_at
+		SELECT portfolioid,userid,name,extid,tax_countryid,financial_year,performence_calculation,summary,price_alert,company_event_alert,base_currency,created_at,updated_at
 	    FROM portfolio %s;
 	`, where)
 
@@ -78,7 +78,7 @@ func (r *portfolioRepository) Get(ctx context.Context, filter map[string]interfa
 	var portfolios []interface{}
 	for rows.Next() {
 		var c entities.Portfolio
-		err := rows.Scan(&c.PortfolioID, &c.Userid, &c.Name, &c.Extid, &c.TaxCountryID, &c.FinancialYear, &c.PerformenceCalculation, &c.Summary, &c.PriceAlert, &c.CompanyEventAlert, &c.CreatedAt, &c.UpdatedAt)
+		err := rows.Scan(&c.PortfolioID, &c.Userid, &c.Name, &c.Extid, &c.TaxCountryID, &c.FinancialYear, &c.PerformenceCalculation, &c.Summary, &c.PriceAlert, &c.CompanyEventAlert, &c.BaseCurrency, &c.CreatedAt, &c.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -94,14 +94,14 @@ func (r *portfolioRepository) Get(ctx context.Context, filter map[string]interfa
 
 func (r *portfolioRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
 	q := `
-    	SELECT portfolioid,userid,name,extid,tax_countryid,financial_year,performence_calculation,summary,price_alert,company_event_alert,created_at,updated_at
+    	SELECT portfolioid,userid,name,extid,tax_countryid,financial_year,performence_calculation,summary,price_alert,company_event_alert,base_currency,created_at,updated_at
         FROM portfolio WHERE portfolioid = $1;
     `
 
 	row := r.DB.QueryRowContext(ctx, q, ID)
 
 	var c entities.Portfolio
-	err := row.Scan(&c.PortfolioID, &c.Userid, &c.Name, &c.Extid, &c.TaxCountryID, &c.FinancialYear, &c.PerformenceCalculation, &c.Summary, &c.PriceAlert, &c.CompanyEventAlert, &c.CreatedAt, &c.UpdatedAt)
+	err := row.Scan(&c.PortfolioID, &c.Userid, &c.Name, &c.Extid, &c.TaxCountryID, &c.FinancialYear, &c.PerformenceCalculation, &c.Summary, &c.PriceAlert, &c.CompanyEventAlert, &c.BaseCurrency, &c.CreatedAt, &c.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = wrappers.NewNonExistentErr(err)
@@ -114,14 +114,14 @@ func (r *portfolioRepository) GetByID(ctx context.Context, ID string) (interface
 
 func (r *portfolioRepository) GetBySymbol(ctx context.Context, Symbol string) (interface{}, error) {
 	q := `
-	SELECT portfolioid,userid,name,extid,tax_countryid,financial_year,performence_calculation,summary,price_alert,company_event_alert,created_at,updated_at
+	SELECT portfolioid,userid,name,extid,tax_countryid,financial_year,performence_calculation,summary,price_alert,company_event_alert,base_currency,created_at,updated_at
 	FROM portfolio WHERE symbol = $1;
     `
 
 	row := r.DB.QueryRowContext(ctx, q, Symbol)
 
 	var c entities.Portfolio
-	err := row.Scan(&c.PortfolioID, &c.Userid, &c.Name, &c.Extid, &c.TaxCountryID, &c.FinancialYear, &c.PerformenceCalculation, &c.Summary, &c.PriceAlert, &c.CompanyEventAlert, &c.CreatedAt, &c.UpdatedAt)
+	err := row.Scan(&c.PortfolioID, &c.Userid, &c.Name, &c.Extid, &c.TaxCountryID, &c.FinancialYear, &c.PerformenceCalculation, &c.Summary, &c.PriceAlert, &c.CompanyEventAlert, &c.BaseCurrency, &c.CreatedAt, &c.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = wrappers.NewNonExistentErr(err)
@@ -134,14 +134,14 @@ func (r *portfolioRepository) GetBySymbol(ctx context.Context, Symbol string) (i
 
 func (r *portfolioRepository) GetByCode(ctx context.Context, Code string) (interface{}, error) {
 	q := `
-		SELECT portfolioid,userid,name,extid,tax_countryid,financial_year,performence_calculation,summary,price_alert,company_event_alert,created_at,updated_at
+		SELECT portfolioid,userid,name,extid,tax_countryid,financial_year,performence_calculation,summary,price_alert,company_event_alert,base_currency,created_at,updated_at
         FROM portfolio WHERE code = $1;
     `
 
 	row := r.DB.QueryRowContext(ctx, q, Code)
 
 	var c entities.Portfolio
-	err := row.Scan(&c.PortfolioID, &c.Userid, &c.Name, &c.Extid, &c.TaxCountryID, &c.FinancialYear, &c.PerformenceCalculation, &c.Summary, &c.PriceAlert, &c.CompanyEventAlert, &c.CreatedAt, &c.UpdatedAt)
+	err := row.Scan(&c.PortfolioID, &c.Userid, &c.Name, &c.Extid, &c.TaxCountryID, &c.FinancialYear, &c.PerformenceCalculation, &c.Summary, &c.PriceAlert, &c.CompanyEventAlert, &c.BaseCurrency, &c.CreatedAt, &c.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = wrappers.NewNonExistentErr(err)
@@ -154,13 +154,13 @@ func (r *portfolioRepository) GetByCode(ctx context.Context, Code string) (inter
 
 func (r *portfolioRepository) Update(ctx context.Context, ID string, portfolio interface{}) error {
 	q := `
-	UPDATE portfolio set name=$1,tax_countryid=$2,financial_year=$3,performence_calculation=$4,summary=$5,price_alert=$6,company_event_alert=$7,updated_at=$8
-	    WHERE portfolioid=$9;
+	UPDATE portfolio set name=$1,tax_countryid=$2,financial_year=$3,performence_calculation=$4,summary=$5,price_alert=$6,company_event_alert=$7,base_currency=$8,updated_at=$9
+	    WHERE portfolioid=$10;
 	`
 
 	b := portfolio.(entities.Portfolio)
 	result, err := r.DB.ExecContext(
-		ctx, q, b.Name, b.TaxCountryID, b.FinancialYear, b.PerformenceCalculation, b.Summary, b.PriceAlert, b.CompanyEventAlert, b.UpdatedAt, ID,
+		ctx, q, b.Name, b.TaxCountryID, b.FinancialYear, b.PerformenceCalculation, b.Summary, b.PriceAlert, b.CompanyEventAlert, b.BaseCurrency, b.UpdatedAt, ID,
 	)
 	if err != nil {
 		return err
@@ -206,13 +206,13 @@ func (r *portfolioRepository) CreateMany(ctx context.Context, portfolio_models [
 		c := entity.(entities.Portfolio)
 
 		q := `
-		INSERT INTO portfolio (userid,name,extid,tax_countryid,financial_year,performence_calculation,summary,price_alert,company_event_alert,created_at,updated_at)
-        	VALUES ($1, $2, $3, $4, $5, $6,$7,$8,$9,$10,$11)
+		INSERT INTO portfolio (userid,name,extid,tax_countryid,financial_year,performence_calculation,summary,price_alert,company_event_alert,base_currency,created_at,updated_at)
+        	VALUES ($1, $2, $3, $4, $5, $6,$7,$8,$9,$10,$11,$12)
 			RETURNING portfolioid;`
 
 		// Here, the query is executed on the transaction instance, and not applied to the database yet
 		row := tx.QueryRowContext(
-			ctx, q, c.Userid, c.Name, c.Extid, c.TaxCountryID, c.FinancialYear, c.PerformenceCalculation, c.Summary, c.PriceAlert, c.CompanyEventAlert, c.CreatedAt, c.UpdatedAt,
+			ctx, q, c.Userid, c.Name, c.Extid, c.TaxCountryID, c.FinancialYear, c.PerformenceCalculation, c.Summary, c.PriceAlert, c.CompanyEventAlert, c.BaseCurrency, c.CreatedAt, c.UpdatedAt,
 		)
 		err := row.Scan(&c.PortfolioID)
 		if err != nil {
@@ -92,8 +92,8 @@ func TestGetPortfolio_Ok(t *testing.T) {
 	filter := map[string]interface{}{"name": "test-name", "holdingid": "1"}
 	skip := 1
 	take := 1
-	mock.ExpectQuery("SELECT (.+) FROM portfolio").WillReturnRows(sqlmock.NewRows([]string{"portfolioid", "userid", "name", "extid", "tax_countryid", "financial_year", "performence_calculation", "summary", "price_alert", "company_event_alert", "created_at", "updated_at"}).
-		AddRow(expectedPortfolio.PortfolioID, expectedPortfolio.Userid, expectedPortfolio.Name, expectedPortfolio.Extid, expectedPortfolio.TaxCountryID, expectedPortfolio.FinancialYear, expectedPortfolio.PerformenceCalculation, expectedPortfolio.Summary, expectedPortfolio.PriceAlert, expectedPortfolio.CompanyEventAlert, expectedPortfolio.CreatedAt, expectedPortfolio.UpdatedAt))
+	mock.ExpectQuery("SELECT (.+) FROM portfolio").WillReturnRows(sqlmock.NewRows([]string{"portfolioid", "userid", "name", "extid", "tax_countryid", "financial_year", "performence_calculation", "summary", "price_alert", "company_event_alert", "base_currency", "created_at", "updated_at"}).
+		AddRow(expectedPortfolio.PortfolioID, expectedPortfolio.Userid, expectedPortfolio.Name, expectedPortfolio.Extid, expectedPortfolio.TaxCountryID, expectedPortfolio.FinancialYear, expectedPortfolio.PerformenceCalculation, expectedPortfolio.Summary, expectedPortfolio.PriceAlert, expectedPortfolio.CompanyEventAlert, expectedPortfolio.BaseCurrency, expectedPortfolio.CreatedAt, expectedPortfolio.UpdatedAt))
 
 	// Act
 	result, err := repo.Get(context.Background(), filter, &skip, &take)
@@ -138,7 +138,7 @@ func TestGetPortfolio_NoResourcesFound(t *testing.T) {
 			DB: db,
 		},
 	}
-	mock.ExpectQuery("SELECT (.+) FROM portfolio").WillReturnRows(sqlmock.NewRows([]string{"portfolioid", "userid", "name", "extid", "tax_countryid", "financial_year", "performence_calculation", "summary", "price_alert", "company_event_alert", "created_at", "updated_at"}))
+	mock.ExpectQuery("SELECT (.+) FROM portfolio").WillReturnRows(sqlmock.NewRows([]string{"portfolioid", "userid", "name", "extid", "tax_countryid", "financial_year", "performence_calculation", "summary", "price_alert", "company_event_alert", "base_currency", "created_at", "updated_at"}))
 
 	// Act
 	_, err := repo.Get(context.Background(), map[string]interface{}{}, nil, nil)
@@ -162,8 +162,8 @@ func TestGetPortfolioByID_Ok(t *testing.T) {
 	expectedPortfolio := entities.Portfolio{
 		PortfolioID: "f8352727-231e-4de1-8257-c235a0af5c4a",
 	}
-	mock.ExpectQuery("SELECT (.+) FROM portfolio").WillReturnRows(sqlmock.NewRows([]string{"portfolioid", "userid", "name", "extid", "tax_countryid", "financial_year", "performence_calculation", "summary", "price_alert", "company_event_alert", "created_at", "updated_at"}).
-		AddRow(expectedPortfolio.PortfolioID, expectedPortfolio.Userid, expectedPortfolio.Name, expectedPortfolio.Extid, expectedPortfolio.TaxCountryID, expectedPortfolio.FinancialYear, expectedPortfolio.PerformenceCalculation, expectedPortfolio.Summary, expectedPortfolio.PriceAlert, expectedPortfolio.CompanyEventAlert, expectedPortfolio.CreatedAt, expectedPortfolio.UpdatedAt))
+	mock.ExpectQuery("SELECT (.+) FROM portfolio").WillReturnRows(sqlmock.NewRows([]string{"portfolioid", "userid", "name", "extid", "tax_countryid", "financial_year", "performence_calculation", "summary", "price_alert", "company_event_alert", "base_currency", "created_at", "updated_at"}).
+		AddRow(expectedPortfolio.PortfolioID, expectedPortfolio.Userid, expectedPortfolio.Name, expectedPortfolio.Extid, expectedPortfolio.TaxCountryID, expectedPortfolio.FinancialYear, expectedPortfolio.PerformenceCalculation, expectedPortfolio.Summary, expectedPortfolio.PriceAlert, expectedPortfolio.CompanyEventAlert, expectedPortfolio.BaseCurrency, expectedPortfolio.CreatedAt, expectedPortfolio.UpdatedAt))
 
 	// Act
 	result, err := repo.GetByID(context.Background(), expectedPortfolio.PortfolioID)
@@ -207,7 +207,7 @@ func TestGetPortfolioByID_ResourceNotFound(t *testing.T) {
 			DB: db,
 		},
 	}
-	mock.ExpectQuery("SELECT (.+) FROM portfolio").WillReturnRows(sqlmock.NewRows([]string{"portfolioid", "userid", "name", "extid", "tax_countryid", "financial_year", "performence_calculation", "summary", "price_alert", "company_event_alert", "created_at", "updated_at"}))
+	mock.ExpectQuery("SELECT (.+) FROM portfolio").WillReturnRows(sqlmock.NewRows([]string{"portfolioid", "userid", "name", "extid", "tax_countryid", "financial_year", "performence_calculation", "summary", "price_alert", "company_event_alert", "base_currency", "created_at", "updated_at"}))
 
 	// Act
 	_, err := repo.GetByID(context.Background(), "")
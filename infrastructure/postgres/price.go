@@ -0,0 +1,250 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// priceRepository adapter of a price repository for postgres
+type priceRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewPriceRepository creates a price repository for postgres
+func NewPriceRepository(db *sql.DB) ports.PriceRepository {
+	return &priceRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+// upsertPrice inserts a daily price or replaces the one already stored for the same instrument and day
+const upsertPrice = `
+	INSERT INTO price (instrumentid, instrument_type, price_date, open, high, low, close, volume, currency, source, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+        ON CONFLICT (instrumentid, price_date)
+        DO UPDATE SET instrument_type = EXCLUDED.instrument_type, open = EXCLUDED.open, high = EXCLUDED.high, low = EXCLUDED.low,
+            close = EXCLUDED.close, volume = EXCLUDED.volume, currency = EXCLUDED.currency, source = EXCLUDED.source, updated_at = EXCLUDED.updated_at
+        RETURNING priceid;
+    `
+
+// selectPrice selects the columns of a daily price in the order scanned by scanPrice
+const selectPrice = `SELECT priceid, instrumentid, instrument_type, price_date, open, high, low, close, volume, currency, source, created_at, updated_at FROM price`
+
+func (r *priceRepository) Create(ctx context.Context, price interface{}) (string, error) {
+	p := price.(entities.Price)
+	row := r.DB.QueryRowContext(
+		ctx, upsertPrice, p.InstrumentID, p.InstrumentType, p.PriceDate, p.Open, p.High, p.Low, p.Close, p.Volume, p.Currency, p.Source, p.CreatedAt, p.UpdatedAt,
+	)
+
+	err := row.Scan(&p.PriceID)
+	if err != nil {
+		return "", err
+	}
+
+	return p.PriceID, nil
+}
+
+func (r *priceRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	var where string
+	for k, v := range filter {
+		if where == "" {
+			where = "WHERE"
+		} else {
+			where = fmt.Sprintf("%s AND", where)
+		}
+		where = fmt.Sprintf("%s %s = '%v'", where, k, v)
+	}
+	if skip != nil {
+		where = fmt.Sprintf("%s OFFSET %d", where, *skip)
+	}
+	if take != nil {
+		where = fmt.Sprintf("%s LIMIT %d", where, *take)
+	}
+
+	q := fmt.Sprintf("%s %s;", selectPrice, where)
+
+	rows, err := r.DB.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanPrices(rows)
+}
+
+func (r *priceRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	q := fmt.Sprintf("%s WHERE priceid = $1;", selectPrice)
+
+	return scanPrice(r.DB.QueryRowContext(ctx, q, ID))
+}
+
+// GetRange returns the daily prices of an instrument between two days, both included, in chronological order
+func (r *priceRepository) GetRange(ctx context.Context, instrumentID string, from, to time.Time) ([]interface{}, error) {
+	q := fmt.Sprintf("%s WHERE instrumentid = $1 AND price_date >= $2 AND price_date <= $3 ORDER BY price_date;", selectPrice)
+
+	rows, err := r.DB.QueryContext(ctx, q, instrumentID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanPrices(rows)
+}
+
+// GetLatest returns the most recent daily price of an instrument on or before a date
+func (r *priceRepository) GetLatest(ctx context.Context, instrumentID string, date time.Time) (interface{}, error) {
+	q := fmt.Sprintf("%s WHERE instrumentid = $1 AND price_date <= $2 ORDER BY price_date DESC LIMIT 1;", selectPrice)
+
+	return scanPrice(r.DB.QueryRowContext(ctx, q, instrumentID, date))
+}
+
+func (r *priceRepository) Update(ctx context.Context, ID string, price interface{}) error {
+	q := `
+	UPDATE price set open=$1, high=$2, low=$3, close=$4, volume=$5, currency=$6, source=$7, updated_at=$8
+	    WHERE priceid=$9;
+	`
+
+	p := price.(entities.Price)
+	result, err := r.DB.ExecContext(
+		ctx, q, p.Open, p.High, p.Low, p.Close, p.Volume, p.Currency, p.Source, p.UpdatedAt, ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		fmt.Println("RowsAffected Error", err)
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *priceRepository) Delete(ctx context.Context, ID string) error {
+	q := `DELETE FROM price WHERE priceid=$1;`
+
+	result, err := r.DB.ExecContext(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// CreateMany stores many daily prices atomically, replacing the ones already stored for the same instrument and day
+func (r *priceRepository) CreateMany(ctx context.Context, prices []interface{}) ([]string, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, entity := range prices {
+		p := entity.(entities.Price)
+
+		row := tx.QueryRowContext(
+			ctx, upsertPrice, p.InstrumentID, p.InstrumentType, p.PriceDate, p.Open, p.High, p.Low, p.Close, p.Volume, p.Currency, p.Source, p.CreatedAt, p.UpdatedAt,
+		)
+		err := row.Scan(&p.PriceID)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		result = append(result, p.PriceID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetLastPrice stores the intraday last price of an instrument, replacing the previous one
+func (r *priceRepository) SetLastPrice(ctx context.Context, lastPrice interface{}) error {
+	q := `
+	INSERT INTO last_price (instrumentid, instrument_type, price, currency, priced_at, source, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (instrumentid)
+        DO UPDATE SET instrument_type = EXCLUDED.instrument_type, price = EXCLUDED.price, currency = EXCLUDED.currency,
+            priced_at = EXCLUDED.priced_at, source = EXCLUDED.source, updated_at = EXCLUDED.updated_at;
+    `
+
+	l := lastPrice.(entities.LastPrice)
+	_, err := r.DB.ExecContext(
+		ctx, q, l.InstrumentID, l.InstrumentType, l.Price.Amount, l.Price.Currency, l.PricedAt, l.Source, l.UpdatedAt,
+	)
+	return err
+}
+
+// GetLastPrice returns the intraday last price of an instrument
+func (r *priceRepository) GetLastPrice(ctx context.Context, instrumentID string) (interface{}, error) {
+	q := `
+    SELECT instrumentid, instrument_type, price, currency, priced_at, source, updated_at
+        FROM last_price WHERE instrumentid = $1;
+    `
+
+	row := r.DB.QueryRowContext(ctx, q, instrumentID)
+
+	var l entities.LastPrice
+	err := row.Scan(&l.InstrumentID, &l.InstrumentType, &l.Price.Amount, &l.Price.Currency, &l.PricedAt, &l.Source, &l.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+// scanPrice scans a daily price selected with selectPrice
+func scanPrice(row *sql.Row) (interface{}, error) {
+	var p entities.Price
+	err := row.Scan(&p.PriceID, &p.InstrumentID, &p.InstrumentType, &p.PriceDate, &p.Open, &p.High, &p.Low, &p.Close, &p.Volume, &p.Currency, &p.Source, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// scanPrices scans the daily prices selected with selectPrice, returning a non existent error when there are none
+func scanPrices(rows *sql.Rows) ([]interface{}, error) {
+	defer rows.Close()
+
+	var prices []interface{}
+	for rows.Next() {
+		var p entities.Price
+		err := rows.Scan(&p.PriceID, &p.InstrumentID, &p.InstrumentType, &p.PriceDate, &p.Open, &p.High, &p.Low, &p.Close, &p.Volume, &p.Currency, &p.Source, &p.CreatedAt, &p.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, &p)
+	}
+
+	if len(prices) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return prices, nil
+}
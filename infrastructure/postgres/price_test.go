@@ -0,0 +1,214 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// priceColumns are the columns selected for a daily price
+var priceColumns = []string{"priceid", "instrumentid", "instrument_type", "price_date", "open", "high", "low", "close", "volume", "currency", "source", "created_at", "updated_at"}
+
+// TestNewPriceRepository_Ok checks that NewPriceRepository creates a new priceRepository struct
+func TestNewPriceRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewPriceRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestCreatePrice_Ok checks that Create returns the expected response when a valid entity is received
+func TestCreatePrice_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &priceRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedID := "f8352727-231e-4de1-8257-c235a0af5c4a"
+	mock.ExpectQuery("INSERT INTO price (.+) ON CONFLICT").WillReturnRows(sqlmock.NewRows([]string{"priceid"}).AddRow(expectedID))
+
+	// Act
+	id, err := repo.Create(context.Background(), entities.Price{})
+
+	// Assert
+	assert.Equal(t, expectedID, id)
+	assert.Nil(t, err)
+}
+
+// TestGetPriceRange_Ok checks that GetRange returns the prices of the instrument in the range
+func TestGetPriceRange_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &priceRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	from := time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, time.August, 31, 0, 0, 0, 0, time.UTC)
+	expectedPrice := entities.Price{
+		PriceID:        "f8352727-231e-4de1-8257-c235a0af5c4a",
+		InstrumentID:   "stock-a",
+		InstrumentType: entities.EntityNameStock,
+		PriceDate:      time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC),
+		Open:           decimal.NewFromInt(10),
+		High:           decimal.NewFromInt(12),
+		Low:            decimal.NewFromInt(9),
+		Close:          decimal.RequireFromString("11.5"),
+		Volume:         decimal.NewFromInt(1000),
+		Currency:       "USD",
+		Source:         "csv",
+	}
+	mock.ExpectQuery("SELECT (.+) FROM price WHERE (.+) ORDER BY price_date").WithArgs("stock-a", from, to).WillReturnRows(sqlmock.NewRows(priceColumns).
+		AddRow(expectedPrice.PriceID, expectedPrice.InstrumentID, expectedPrice.InstrumentType, expectedPrice.PriceDate, expectedPrice.Open, expectedPrice.High, expectedPrice.Low, expectedPrice.Close, expectedPrice.Volume, expectedPrice.Currency, expectedPrice.Source, expectedPrice.CreatedAt, expectedPrice.UpdatedAt))
+
+	// Act
+	result, err := repo.GetRange(context.Background(), "stock-a", from, to)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+
+	entity := *(result[0].(*entities.Price))
+	assert.Equal(t, expectedPrice.PriceID, entity.PriceID)
+	assert.Equal(t, expectedPrice.PriceDate, entity.PriceDate)
+	assert.Equal(t, expectedPrice.Close.String(), entity.Close.String())
+}
+
+// TestGetPriceRange_NotFound checks that GetRange returns a non existent error when there are no prices in the range
+func TestGetPriceRange_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &priceRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM price").WillReturnRows(sqlmock.NewRows(priceColumns))
+
+	// Act
+	_, err := repo.GetRange(context.Background(), "stock-a", time.Now(), time.Now())
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
+
+// TestGetLatestPrice_NotFound checks that GetLatest returns a non existent error when no price exists
+func TestGetLatestPrice_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &priceRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM price WHERE (.+) ORDER BY price_date DESC LIMIT 1").WillReturnError(sql.ErrNoRows)
+
+	// Act
+	_, err := repo.GetLatest(context.Background(), "stock-a", time.Now())
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
+
+// TestCreateManyPrices_Ok checks that CreateMany does not return an error when everything goes as expected
+func TestCreateManyPrices_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &priceRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedID := "f8352727-231e-4de1-8257-c235a0af5c4a"
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO price").WillReturnRows(sqlmock.NewRows([]string{"priceid"}).AddRow(expectedID))
+	mock.ExpectCommit()
+
+	// Act
+	ids, err := repo.CreateMany(context.Background(), []interface{}{entities.Price{}})
+
+	// Assert
+	assert.Equal(t, []string{expectedID}, ids)
+	assert.Nil(t, err)
+}
+
+// TestSetLastPrice_Ok checks that SetLastPrice does not return an error when everything goes as expected
+func TestSetLastPrice_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &priceRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	lastPrice := entities.LastPrice{InstrumentID: "stock-a", Price: entities.NewMoney(decimal.RequireFromString("10.5"), "USD")}
+	mock.ExpectExec("INSERT INTO last_price (.+) ON CONFLICT").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.SetLastPrice(context.Background(), lastPrice)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestGetLastPrice_Ok checks that GetLastPrice returns the expected last price
+func TestGetLastPrice_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &priceRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	pricedAt := time.Date(2023, time.August, 3, 15, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT (.+) FROM last_price WHERE instrumentid = ").WithArgs("stock-a").WillReturnRows(sqlmock.NewRows([]string{"instrumentid", "instrument_type", "price", "currency", "priced_at", "source", "updated_at"}).
+		AddRow("stock-a", entities.EntityNameStock, "10.5", "USD", pricedAt, "manual", pricedAt))
+
+	// Act
+	result, err := repo.GetLastPrice(context.Background(), "stock-a")
+
+	// Assert
+	assert.Nil(t, err)
+
+	entity := *(result.(*entities.LastPrice))
+	assert.Equal(t, "10.5 USD", entity.Price.String())
+	assert.Equal(t, pricedAt, entity.PricedAt)
+}
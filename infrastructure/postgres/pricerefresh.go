@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/tkudlicka/portflux-api/core/entities"
+	"github.com/tkudlicka/portflux-api/core/ports"
+)
+
+// priceRefreshRunRepository adapter of a price refresh run repository for postgres
+type priceRefreshRunRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewPriceRefreshRunRepository creates a price refresh run repository for postgres
+func NewPriceRefreshRunRepository(db *sql.DB) ports.PriceRefreshRunRepository {
+	return &priceRefreshRunRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+// selectPriceRefreshRun selects the columns of a price refresh run in the order scanned by scanPriceRefreshRun
+const selectPriceRefreshRun = `SELECT price_refresh_runid, provider, started_at, finished_at, duration_ms, instruments, updated, failed, failures FROM price_refresh_run`
+
+func (r *priceRefreshRunRepository) Create(ctx context.Context, run interface{}) (string, error) {
+	q := `
+	INSERT INTO price_refresh_run (provider, started_at, finished_at, duration_ms, instruments, updated, failed, failures)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING price_refresh_runid;
+    `
+
+	p := run.(entities.PriceRefreshRun)
+	failures, err := json.Marshal(failuresOrEmpty(p.Failures))
+	if err != nil {
+		return "", err
+	}
+
+	row := r.DB.QueryRowContext(
+		ctx, q, p.Provider, p.StartedAt, p.FinishedAt, p.DurationMs, p.Instruments, p.Updated, p.Failed, failures,
+	)
+
+	err = row.Scan(&p.PriceRefreshRunID)
+	if err != nil {
+		return "", err
+	}
+
+	return p.PriceRefreshRunID, nil
+}
+
+func (r *priceRefreshRunRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
+	var where string
+	for k, v := range filter {
+		if where == "" {
+			where = "WHERE"
+		} else {
+			where = fmt.Sprintf("%s AND", where)
+		}
+		where = fmt.Sprintf("%s %s = '%v'", where, k, v)
+	}
+	if skip != nil {
+		where = fmt.Sprintf("%s OFFSET %d", where, *skip)
+	}
+	if take != nil {
+		where = fmt.Sprintf("%s LIMIT %d", where, *take)
+	}
+
+	q := fmt.Sprintf("%s %s;", selectPriceRefreshRun, where)
+
+	rows, err := r.DB.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanPriceRefreshRuns(rows)
+}
+
+func (r *priceRefreshRunRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+	q := fmt.Sprintf("%s WHERE price_refresh_runid = $1;", selectPriceRefreshRun)
+
+	row := r.DB.QueryRowContext(ctx, q, ID)
+
+	var p entities.PriceRefreshRun
+	var failures []byte
+	err := row.Scan(&p.PriceRefreshRunID, &p.Provider, &p.StartedAt, &p.FinishedAt, &p.DurationMs, &p.Instruments, &p.Updated, &p.Failed, &failures)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(failures, &p.Failures); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// GetRecent returns the most recent price refresh runs, newest first
+func (r *priceRefreshRunRepository) GetRecent(ctx context.Context, limit int) ([]interface{}, error) {
+	q := fmt.Sprintf("%s ORDER BY started_at DESC LIMIT $1;", selectPriceRefreshRun)
+
+	rows, err := r.DB.QueryContext(ctx, q, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanPriceRefreshRuns(rows)
+}
+
+func (r *priceRefreshRunRepository) Update(ctx context.Context, ID string, run interface{}) error {
+	q := `
+	UPDATE price_refresh_run set finished_at=$1, duration_ms=$2, instruments=$3, updated=$4, failed=$5, failures=$6
+	    WHERE price_refresh_runid=$7;
+	`
+
+	p := run.(entities.PriceRefreshRun)
+	failures, err := json.Marshal(failuresOrEmpty(p.Failures))
+	if err != nil {
+		return err
+	}
+
+	result, err := r.DB.ExecContext(
+		ctx, q, p.FinishedAt, p.DurationMs, p.Instruments, p.Updated, p.Failed, failures, ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *priceRefreshRunRepository) Delete(ctx context.Context, ID string) error {
+	q := `DELETE FROM price_refresh_run WHERE price_refresh_runid=$1;`
+
+	result, err := r.DB.ExecContext(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// scanPriceRefreshRuns scans the runs selected with selectPriceRefreshRun, returning a non existent error when there are none
+func scanPriceRefreshRuns(rows *sql.Rows) ([]interface{}, error) {
+	defer rows.Close()
+
+	var runs []interface{}
+	for rows.Next() {
+		var p entities.PriceRefreshRun
+		var failures []byte
+		err := rows.Scan(&p.PriceRefreshRunID, &p.Provider, &p.StartedAt, &p.FinishedAt, &p.DurationMs, &p.Instruments, &p.Updated, &p.Failed, &failures)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(failures, &p.Failures); err != nil {
+			return nil, err
+		}
+		runs = append(runs, &p)
+	}
+
+	if len(runs) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return runs, nil
+}
+
+// failuresOrEmpty returns the failures of a run, stored as an empty list rather than null when there are none
+func failuresOrEmpty(failures []entities.PriceRefreshFailure) []entities.PriceRefreshFailure {
+	if failures == nil {
+		return []entities.PriceRefreshFailure{}
+	}
+	return failures
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v3/mocks"
+	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/tkudlicka/portflux-api/core/entities"
+)
+
+// priceRefreshRunColumns are the columns selected for a price refresh run
+var priceRefreshRunColumns = []string{"price_refresh_runid", "provider", "started_at", "finished_at", "duration_ms", "instruments", "updated", "failed", "failures"}
+
+// TestNewPriceRefreshRunRepository_Ok checks that NewPriceRefreshRunRepository creates a new priceRefreshRunRepository struct
+func TestNewPriceRefreshRunRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewPriceRefreshRunRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestCreatePriceRefreshRun_Ok checks that Create stores the failures of the run as JSON and returns the new ID
+func TestCreatePriceRefreshRun_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &priceRefreshRunRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	run := entities.PriceRefreshRun{Provider: "file", Instruments: 1, Failed: 1, Failures: []entities.PriceRefreshFailure{{InstrumentID: "stock-a", Error: "timeout"}}}
+	expectedID := "f8352727-231e-4de1-8257-c235a0af5c4a"
+	mock.ExpectQuery("INSERT INTO price_refresh_run").
+		WithArgs("file", sqlmock.AnyArg(), sqlmock.AnyArg(), int64(0), 1, 0, 1, []byte(`[{"instrumentid":"stock-a","instrument_type":"","symbol":"","error":"timeout"}]`)).
+		WillReturnRows(sqlmock.NewRows([]string{"price_refresh_runid"}).AddRow(expectedID))
+
+	// Act
+	id, err := repo.Create(context.Background(), run)
+
+	// Assert
+	assert.Equal(t, expectedID, id)
+	assert.Nil(t, err)
+}
+
+// TestGetRecentPriceRefreshRuns_Ok checks that GetRecent returns the runs with their failures
+func TestGetRecentPriceRefreshRuns_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &priceRefreshRunRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	startedAt := time.Date(2023, time.August, 4, 20, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT (.+) FROM price_refresh_run ORDER BY started_at DESC LIMIT").WithArgs(5).WillReturnRows(sqlmock.NewRows(priceRefreshRunColumns).
+		AddRow("run-id", "file", startedAt, startedAt.Add(time.Second), 1000, 2, 1, 1, []byte(`[{"instrumentid":"stock-a","error":"timeout"}]`)))
+
+	// Act
+	result, err := repo.GetRecent(context.Background(), 5)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+
+	run := *(result[0].(*entities.PriceRefreshRun))
+	assert.Equal(t, "run-id", run.PriceRefreshRunID)
+	assert.Equal(t, int64(1000), run.DurationMs)
+	assert.Equal(t, []entities.PriceRefreshFailure{{InstrumentID: "stock-a", Error: "timeout"}}, run.Failures)
+}
+
+// TestGetRecentPriceRefreshRuns_NotFound checks that GetRecent returns a non existent error when no run is stored
+func TestGetRecentPriceRefreshRuns_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &priceRefreshRunRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM price_refresh_run").WillReturnRows(sqlmock.NewRows(priceRefreshRunColumns))
+
+	// Act
+	_, err := repo.GetRecent(context.Background(), 5)
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
@@ -28,14 +28,14 @@ func NewStockRepository(db *sql.DB) ports.StockRepository {
 
 func (r *stockRepository) Create(ctx context.Context, stock interface{}) (string, error) {
 	q := `
-	INSERT INTO stock (holdingid,extid, ticker_symbol,company_name,slug, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6,$7)
+	INSERT INTO stock (holdingid,extid, ticker_symbol,company_name,slug,country, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
         RETURNING stockid;
     `
 
 	c := stock.(entities.Stock)
 	row := r.DB.QueryRowContext(
-		ctx, q, c.HoldingID, c.Extid, c.TickerSymbol, c.CompanyName, c.Slug, c.CreatedAt, c.UpdatedAt,
+		ctx, q, c.HoldingID, c.Extid, c.TickerSymbol, c.CompanyName, c.Slug, c.Country, c.CreatedAt, c.UpdatedAt,
 	)
 
 	err := row.Scan(&c.StockID)
@@ -64,7 +64,7 @@ func (r *stockRepository) Get(ctx context.Context, filter map[string]interface{}
 	}
 
 	q := fmt.Sprintf(`
-	SELECT stockid, holdingid,extid, ticker_symbol,company_name,slug, created_at, updated_at
+	SELECT stockid, holdingid,extid, ticker_symbol,company_name,slug,country, created_at, updated_at
 	    FROM stock %s;
 	`, where)
 
@@ -78,7 +78,7 @@ func (r *stockRepository) Get(ctx context.Context, filter map[string]interface{}
 	var stocks []interface{}
 	for rows.Next() {
 		var c entities.Stock
-		err := rows.Scan(&c.StockID, &c.HoldingID, &c.Extid, &c.TickerSymbol, &c.CompanyName, &c.Slug, &c.CreatedAt, &c.UpdatedAt)
+		err := rows.Scan(&c.StockID, &c.HoldingID, &c.Extid, &c.TickerSymbol, &c.CompanyName, &c.Slug, &c.Country, &c.CreatedAt, &c.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -94,14 +94,14 @@ func (r *stockRepository) Get(ctx context.Context, filter map[string]interface{}
 
 func (r *stockRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
 	q := `
-    SELECT stockid, holdingid,extid, ticker_symbol,company_name,slug, created_at, updated_at
+    SELECT stockid, holdingid,extid, ticker_symbol,company_name,slug,country, created_at, updated_at
         FROM stock WHERE stockid = $1;
     `
 
 	row := r.DB.QueryRowContext(ctx, q, ID)
 
 	var c entities.Stock
-	err := row.Scan(&c.StockID, &c.HoldingID, &c.Extid, &c.TickerSymbol, &c.CompanyName, &c.Slug, &c.CreatedAt, &c.UpdatedAt)
+	err := row.Scan(&c.StockID, &c.HoldingID, &c.Extid, &c.TickerSymbol, &c.CompanyName, &c.Slug, &c.Country, &c.CreatedAt, &c.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = wrappers.NewNonExistentErr(err)
@@ -114,14 +114,14 @@ func (r *stockRepository) GetByID(ctx context.Context, ID string) (interface{},
 
 func (r *stockRepository) GetBySymbol(ctx context.Context, Symbol string) (interface{}, error) {
 	q := `
-    SELECT stockid, holdingid,extid, ticker_symbol,company_name,slug, created_at, updated_at
+    SELECT stockid, holdingid,extid, ticker_symbol,company_name,slug,country, created_at, updated_at
 	FROM stock WHERE symbol = $1;
     `
 
 	row := r.DB.QueryRowContext(ctx, q, Symbol)
 
 	var c entities.Stock
-	err := row.Scan(&c.StockID, &c.HoldingID, &c.Extid, &c.TickerSymbol, &c.CompanyName, &c.Slug, &c.CreatedAt, &c.UpdatedAt)
+	err := row.Scan(&c.StockID, &c.HoldingID, &c.Extid, &c.TickerSymbol, &c.CompanyName, &c.Slug, &c.Country, &c.CreatedAt, &c.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = wrappers.NewNonExistentErr(err)
@@ -134,14 +134,14 @@ func (r *stockRepository) GetBySymbol(ctx context.Context, Symbol string) (inter
 
 func (r *stockRepository) GetByCode(ctx context.Context, Code string) (interface{}, error) {
 	q := `
-    SELECT stockid, holdingid,extid, ticker_symbol,company_name,slug, created_at, updated_at
+    SELECT stockid, holdingid,extid, ticker_symbol,company_name,slug,country, created_at, updated_at
         FROM stock WHERE code = $1;
     `
 
 	row := r.DB.QueryRowContext(ctx, q, Code)
 
 	var c entities.Stock
-	err := row.Scan(&c.StockID, &c.HoldingID, &c.Extid, &c.TickerSymbol, &c.CompanyName, &c.Slug, &c.CreatedAt, &c.UpdatedAt)
+	err := row.Scan(&c.StockID, &c.HoldingID, &c.Extid, &c.TickerSymbol, &c.CompanyName, &c.Slug, &c.Country, &c.CreatedAt, &c.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = wrappers.NewNonExistentErr(err)
@@ -154,13 +154,13 @@ func (r *stockRepository) GetByCode(ctx context.Context, Code string) (interface
 
 func (r *stockRepository) Update(ctx context.Context, ID string, stock interface{}) error {
 	q := `
-	UPDATE stock set holdingid=$1, ticker_symbol=$2,company_name=$3,slug=$4, updated_at=$5
-	    WHERE stockid=$6;
+	UPDATE stock set holdingid=$1, ticker_symbol=$2,company_name=$3,slug=$4,country=$5, updated_at=$6
+	    WHERE stockid=$7;
 	`
 
 	b := stock.(entities.Stock)
 	result, err := r.DB.ExecContext(
-		ctx, q, b.HoldingID, b.HoldingID, b.TickerSymbol, b.CompanyName, b.Slug, b.UpdatedAt, ID,
+		ctx, q, b.HoldingID, b.TickerSymbol, b.CompanyName, b.Slug, b.Country, b.UpdatedAt, ID,
 	)
 	if err != nil {
 		return err
@@ -206,13 +206,13 @@ func (r *stockRepository) CreateMany(ctx context.Context, stock_models []interfa
 		c := entity.(entities.Stock)
 
 		q := `
-		INSERT INTO stock (holdingid,extid, ticker_symbol,company_name,slug, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6,$7)
+		INSERT INTO stock (holdingid,extid, ticker_symbol,company_name,slug,country, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 			RETURNING stockid;`
 
 		// Here, the query is executed on the transaction instance, and not applied to the database yet
 		row := tx.QueryRowContext(
-			ctx, q, c.HoldingID, c.Extid, c.TickerSymbol, c.CompanyName, c.Slug, c.CreatedAt, c.UpdatedAt,
+			ctx, q, c.HoldingID, c.Extid, c.TickerSymbol, c.CompanyName, c.Slug, c.Country, c.CreatedAt, c.UpdatedAt,
 		)
 		err := row.Scan(&c.StockID)
 		if err != nil {
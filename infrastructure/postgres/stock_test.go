@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
@@ -94,8 +95,8 @@ func TestGetStock_Ok(t *testing.T) {
 	filter := map[string]interface{}{"company_name": "test-name", "slug": "1"}
 	skip := 1
 	take := 1
-	mock.ExpectQuery("SELECT (.+) FROM stock").WillReturnRows(sqlmock.NewRows([]string{"stockid", "holdingid", "extid", "ticker_symbol", "company_name", "slug", "created_at", "updated_at"}).
-		AddRow(expectedStock.StockID, expectedStock.HoldingID, expectedStock.Extid, expectedStock.TickerSymbol, expectedStock.CompanyName, expectedStock.Slug, expectedStock.CreatedAt, expectedStock.UpdatedAt))
+	mock.ExpectQuery("SELECT (.+) FROM stock").WillReturnRows(sqlmock.NewRows([]string{"stockid", "holdingid", "extid", "ticker_symbol", "company_name", "slug", "country", "created_at", "updated_at"}).
+		AddRow(expectedStock.StockID, expectedStock.HoldingID, expectedStock.Extid, expectedStock.TickerSymbol, expectedStock.CompanyName, expectedStock.Slug, expectedStock.Country, expectedStock.CreatedAt, expectedStock.UpdatedAt))
 
 	// Act
 	result, err := repo.Get(context.Background(), filter, &skip, &take)
@@ -140,7 +141,7 @@ func TestGetStock_NoResourcesFound(t *testing.T) {
 			DB: db,
 		},
 	}
-	mock.ExpectQuery("SELECT (.+) FROM stock").WillReturnRows(sqlmock.NewRows([]string{"stockid", "holdingid", "extid", "ticker_symbol", "company_name", "slug", "created_at", "updated_at"}))
+	mock.ExpectQuery("SELECT (.+) FROM stock").WillReturnRows(sqlmock.NewRows([]string{"stockid", "holdingid", "extid", "ticker_symbol", "company_name", "slug", "country", "created_at", "updated_at"}))
 
 	// Act
 	_, err := repo.Get(context.Background(), map[string]interface{}{}, nil, nil)
@@ -164,8 +165,8 @@ func TestGetStockByID_Ok(t *testing.T) {
 	expectedStock := entities.Stock{
 		StockID: "f8352727-231e-4de1-8257-c235a0af5c4a",
 	}
-	mock.ExpectQuery("SELECT (.+) FROM stock").WillReturnRows(sqlmock.NewRows([]string{"stockid", "holdingid", "extid", "ticker_symbol", "company_name", "slug", "created_at", "updated_at"}).
-		AddRow(expectedStock.StockID, expectedStock.HoldingID, expectedStock.Extid, expectedStock.TickerSymbol, expectedStock.CompanyName, expectedStock.Slug, expectedStock.CreatedAt, expectedStock.UpdatedAt))
+	mock.ExpectQuery("SELECT (.+) FROM stock").WillReturnRows(sqlmock.NewRows([]string{"stockid", "holdingid", "extid", "ticker_symbol", "company_name", "slug", "country", "created_at", "updated_at"}).
+		AddRow(expectedStock.StockID, expectedStock.HoldingID, expectedStock.Extid, expectedStock.TickerSymbol, expectedStock.CompanyName, expectedStock.Slug, expectedStock.Country, expectedStock.CreatedAt, expectedStock.UpdatedAt))
 
 	// Act
 	result, err := repo.GetByID(context.Background(), expectedStock.StockID)
@@ -209,7 +210,7 @@ func TestGetStockByID_ResourceNotFound(t *testing.T) {
 			DB: db,
 		},
 	}
-	mock.ExpectQuery("SELECT (.+) FROM stock").WillReturnRows(sqlmock.NewRows([]string{"stockid", "holdingid", "extid", "ticker_symbol", "company_name", "slug", "created_at", "updated_at"}))
+	mock.ExpectQuery("SELECT (.+) FROM stock").WillReturnRows(sqlmock.NewRows([]string{"stockid", "holdingid", "extid", "ticker_symbol", "company_name", "slug", "country", "created_at", "updated_at"}))
 
 	// Act
 	_, err := repo.GetByID(context.Background(), "")
@@ -239,6 +240,30 @@ func TestUpdateStock_Ok(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+// TestUpdateStock_Args checks that Update passes each column of the stock once, in the order of the statement
+func TestUpdateStock_Args(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &stockRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	updatedAt := time.Date(2022, time.June, 9, 0, 0, 0, 0, time.UTC)
+	stock := entities.Stock{HoldingID: "holding-a", TickerSymbol: "META", CompanyName: "Meta Platforms", Slug: "meta", Country: "US", UpdatedAt: updatedAt}
+	mock.ExpectExec("UPDATE stock").WithArgs("holding-a", "META", "Meta Platforms", "meta", "US", updatedAt, "stock-a").WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Act
+	err := repo.Update(context.Background(), "stock-a", stock)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
 // TestUpdateStock_UpdateError checks that Update returns an error when the update statement fails
 func TestUpdateStock_UpdateError(t *testing.T) {
 	// Arrange
@@ -28,14 +28,14 @@ func NewTransactionRepository(db *sql.DB) ports.TransactionRepository {
 
 func (r *transactionRepository) Create(ctx context.Context, transaction interface{}) (string, error) {
 	q := `
-	INSERT INTO transaction (stockid, cryptocurrencyid, quantity,transaction_price,transaction_date, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6,$7)
+	INSERT INTO transaction (holdingid, stockid, cryptocurrencyid, transaction_type, quantity, transaction_price, transaction_currency, transaction_date, extid, import_batchid, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
         RETURNING transactionid;
     `
 
 	c := transaction.(entities.Transaction)
 	row := r.DB.QueryRowContext(
-		ctx, q, c.StockID, c.CryptocurrencyID, c.Quantity, c.TransactionPrice, c.TransactionDate, c.CreatedAt, c.UpdatedAt,
+		ctx, q, c.HoldingID, nullString(c.StockID), nullString(c.CryptocurrencyID), c.Type(), c.Quantity, c.TransactionPrice.Amount, c.TransactionPrice.Currency, c.TransactionDate, nullString(c.Extid), nullString(c.ImportBatchID), c.CreatedAt, c.UpdatedAt,
 	)
 
 	err := row.Scan(&c.TransactionID)
@@ -64,7 +64,7 @@ func (r *transactionRepository) Get(ctx context.Context, filter map[string]inter
 	}
 
 	q := fmt.Sprintf(`
-	SELECT transactionid, stockid, cryptocurrencyid, quantity,transaction_price,transaction_date, created_at, updated_at
+	SELECT transactionid, holdingid, stockid, cryptocurrencyid, transaction_type, quantity, transaction_price,transaction_currency,transaction_date, extid, import_batchid, created_at, updated_at
 	    FROM transaction %s;
 	`, where)
 
@@ -77,8 +77,7 @@ func (r *transactionRepository) Get(ctx context.Context, filter map[string]inter
 
 	var transactions []interface{}
 	for rows.Next() {
-		var c entities.Transaction
-		err := rows.Scan(&c.TransactionID, &c.StockID, &c.CryptocurrencyID, &c.Quantity, &c.TransactionPrice, &c.TransactionDate, &c.CreatedAt, &c.UpdatedAt)
+		c, err := scanTransaction(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -94,14 +93,13 @@ func (r *transactionRepository) Get(ctx context.Context, filter map[string]inter
 
 func (r *transactionRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
 	q := `
-    SELECT transactionid, stockid, cryptocurrencyid, quantity,transaction_price,transaction_date, created_at, updated_at
+    SELECT transactionid, holdingid, stockid, cryptocurrencyid, transaction_type, quantity, transaction_price,transaction_currency,transaction_date, extid, import_batchid, created_at, updated_at
         FROM transaction WHERE transactionid = $1;
     `
 
 	row := r.DB.QueryRowContext(ctx, q, ID)
 
-	var c entities.Transaction
-	err := row.Scan(&c.TransactionID, &c.StockID, &c.CryptocurrencyID, &c.Quantity, &c.TransactionPrice, &c.TransactionDate, &c.CreatedAt, &c.UpdatedAt)
+	c, err := scanTransaction(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = wrappers.NewNonExistentErr(err)
@@ -112,16 +110,108 @@ func (r *transactionRepository) GetByID(ctx context.Context, ID string) (interfa
 	return &c, nil
 }
 
+func (r *transactionRepository) GetByPortfolioID(ctx context.Context, portfolioID string) ([]interface{}, error) {
+	q := `
+    SELECT t.transactionid, t.holdingid, t.stockid, t.cryptocurrencyid, t.transaction_type, t.quantity, t.transaction_price, t.transaction_currency, t.transaction_date, t.extid, t.import_batchid, t.created_at, t.updated_at
+        FROM transaction t INNER JOIN holding h ON h.holdingid = t.holdingid
+        WHERE h.portfolioid = $1
+        ORDER BY t.transaction_date, t.created_at;
+    `
+
+	rows, err := r.DB.QueryContext(ctx, q, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var transactions []interface{}
+	for rows.Next() {
+		c, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, &c)
+	}
+
+	if len(transactions) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return transactions, nil
+}
+
+// GetByBrokerAccountID returns the transactions of the holdings attached to a broker account in the order they were made
+func (r *transactionRepository) GetByBrokerAccountID(ctx context.Context, brokerAccountID string) ([]interface{}, error) {
+	q := `
+    SELECT t.transactionid, t.holdingid, t.stockid, t.cryptocurrencyid, t.transaction_type, t.quantity, t.transaction_price, t.transaction_currency, t.transaction_date, t.extid, t.import_batchid, t.created_at, t.updated_at
+        FROM transaction t INNER JOIN holding h ON h.holdingid = t.holdingid
+        WHERE h.broker_accountid = $1
+        ORDER BY t.transaction_date, t.created_at;
+    `
+
+	rows, err := r.DB.QueryContext(ctx, q, brokerAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var transactions []interface{}
+	for rows.Next() {
+		c, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, &c)
+	}
+
+	if len(transactions) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return transactions, nil
+}
+
+// StreamByPortfolioID calls fn with each transaction of a portfolio in the order they were made, reading them one at a time.
+// It stops at the first error returned by fn.
+func (r *transactionRepository) StreamByPortfolioID(ctx context.Context, portfolioID string, fn func(entities.Transaction) error) error {
+	q := `
+    SELECT t.transactionid, t.holdingid, t.stockid, t.cryptocurrencyid, t.transaction_type, t.quantity, t.transaction_price, t.transaction_currency, t.transaction_date, t.extid, t.import_batchid, t.created_at, t.updated_at
+        FROM transaction t INNER JOIN holding h ON h.holdingid = t.holdingid
+        WHERE h.portfolioid = $1
+        ORDER BY t.transaction_date, t.created_at;
+    `
+
+	rows, err := r.DB.QueryContext(ctx, q, portfolioID)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		c, err := scanTransaction(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 func (r *transactionRepository) GetBySymbol(ctx context.Context, Symbol string) (interface{}, error) {
 	q := `
-    SELECT transactionid, stockid, cryptocurrencyid, quantity,transaction_price,transaction_date, created_at, updated_at
+    SELECT transactionid, holdingid, stockid, cryptocurrencyid, transaction_type, quantity, transaction_price,transaction_currency,transaction_date, extid, import_batchid, created_at, updated_at
 	FROM transaction WHERE symbol = $1;
     `
 
 	row := r.DB.QueryRowContext(ctx, q, Symbol)
 
-	var c entities.Transaction
-	err := row.Scan(&c.TransactionID, &c.StockID, &c.CryptocurrencyID, &c.Quantity, &c.TransactionPrice, &c.TransactionDate, &c.CreatedAt, &c.UpdatedAt)
+	c, err := scanTransaction(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = wrappers.NewNonExistentErr(err)
@@ -134,14 +224,13 @@ func (r *transactionRepository) GetBySymbol(ctx context.Context, Symbol string)
 
 func (r *transactionRepository) GetByCode(ctx context.Context, Code string) (interface{}, error) {
 	q := `
-    SELECT transactionid, stockid, cryptocurrencyid, quantity,transaction_price,transaction_date, created_at, updated_at
+    SELECT transactionid, holdingid, stockid, cryptocurrencyid, transaction_type, quantity, transaction_price,transaction_currency,transaction_date, extid, import_batchid, created_at, updated_at
         FROM transaction WHERE code = $1;
     `
 
 	row := r.DB.QueryRowContext(ctx, q, Code)
 
-	var c entities.Transaction
-	err := row.Scan(&c.TransactionID, &c.StockID, &c.CryptocurrencyID, &c.Quantity, &c.TransactionPrice, &c.TransactionDate, &c.CreatedAt, &c.UpdatedAt)
+	c, err := scanTransaction(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = wrappers.NewNonExistentErr(err)
@@ -154,13 +243,13 @@ func (r *transactionRepository) GetByCode(ctx context.Context, Code string) (int
 
 func (r *transactionRepository) Update(ctx context.Context, ID string, transaction interface{}) error {
 	q := `
-	UPDATE transaction set stockid=$1, cryptocurrencyid=$2, quantity=$3,transaction_price=$4,transaction_date=$5, updated_at=$6
-	    WHERE transactionid=$5;
+	UPDATE transaction set holdingid=$1, stockid=$2, cryptocurrencyid=$3, transaction_type=$4, quantity=$5, transaction_price=$6, transaction_currency=$7, transaction_date=$8, updated_at=$9
+	    WHERE transactionid=$10;
 	`
 
 	b := transaction.(entities.Transaction)
 	result, err := r.DB.ExecContext(
-		ctx, q, b.StockID, b.CryptocurrencyID, b.Quantity, b.TransactionPrice, b.TransactionDate, b.UpdatedAt, ID,
+		ctx, q, b.HoldingID, nullString(b.StockID), nullString(b.CryptocurrencyID), b.Type(), b.Quantity, b.TransactionPrice.Amount, b.TransactionPrice.Currency, b.TransactionDate, b.UpdatedAt, ID,
 	)
 	if err != nil {
 		return err
@@ -206,13 +295,13 @@ func (r *transactionRepository) CreateMany(ctx context.Context, transaction_mode
 		c := entity.(entities.Transaction)
 
 		q := `
-		INSERT INTO transaction (stockid, cryptocurrencyid, quantity,transaction_price,transaction_date, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6,$7)
+		INSERT INTO transaction (holdingid, stockid, cryptocurrencyid, transaction_type, quantity, transaction_price, transaction_currency, transaction_date, extid, import_batchid, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 			RETURNING transactionid;`
 
 		// Here, the query is executed on the transaction instance, and not applied to the database yet
 		row := tx.QueryRowContext(
-			ctx, q, c.StockID, c.CryptocurrencyID, c.Quantity, c.TransactionPrice, c.TransactionDate, c.CreatedAt, c.UpdatedAt,
+			ctx, q, c.HoldingID, nullString(c.StockID), nullString(c.CryptocurrencyID), c.Type(), c.Quantity, c.TransactionPrice.Amount, c.TransactionPrice.Currency, c.TransactionDate, nullString(c.Extid), nullString(c.ImportBatchID), c.CreatedAt, c.UpdatedAt,
 		)
 		err := row.Scan(&c.TransactionID)
 		if err != nil {
@@ -230,3 +319,19 @@ func (r *transactionRepository) CreateMany(ctx context.Context, transaction_mode
 	}
 	return result, nil
 }
+
+// scanTransaction scans a transaction, whose stock, cryptocurrency, type, external ID and import batch are empty when null
+func scanTransaction(row scanner) (entities.Transaction, error) {
+	var c entities.Transaction
+	var stockID, cryptocurrencyID, transactionType, extid, importBatchID sql.NullString
+	err := row.Scan(&c.TransactionID, &c.HoldingID, &stockID, &cryptocurrencyID, &transactionType, &c.Quantity, &c.TransactionPrice.Amount, &c.TransactionPrice.Currency, &c.TransactionDate, &extid, &importBatchID, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return entities.Transaction{}, err
+	}
+	c.StockID = stockID.String
+	c.CryptocurrencyID = cryptocurrencyID.String
+	c.TransactionType = transactionType.String
+	c.Extid = extid.String
+	c.ImportBatchID = importBatchID.String
+	return c, nil
+}
@@ -5,11 +5,13 @@ import (
 	"database/sql"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/sergicanet9/scv-go-tools/v3/infrastructure"
 	"github.com/sergicanet9/scv-go-tools/v3/mocks"
 	"github.com/sergicanet9/scv-go-tools/v3/wrappers"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/tkudlicka/portflux-api/core/entities"
 )
@@ -87,13 +89,15 @@ func TestGetTransaction_Ok(t *testing.T) {
 	}
 
 	expectedTransaction := entities.Transaction{
-		TransactionID: "f8352727-231e-4de1-8257-c235a0af5c4a",
+		TransactionID:    "f8352727-231e-4de1-8257-c235a0af5c4a",
+		Quantity:         decimal.RequireFromString("0.125"),
+		TransactionPrice: entities.NewMoney(decimal.RequireFromString("25000.5"), "EUR"),
 	}
 	filter := map[string]interface{}{"stockid": "test-STOCK", "cryptocurrencyid": "1"}
 	skip := 1
 	take := 1
-	mock.ExpectQuery("SELECT (.+) FROM transaction").WillReturnRows(sqlmock.NewRows([]string{"transactionid", "stockid", "cryptocurrencyid", "quantity", "transaction_price", "transaction_date", "created_at", "updated_at"}).
-		AddRow(expectedTransaction.TransactionID, expectedTransaction.StockID, expectedTransaction.CryptocurrencyID, expectedTransaction.Quantity, expectedTransaction.TransactionPrice, expectedTransaction.TransactionDate, expectedTransaction.CreatedAt, expectedTransaction.UpdatedAt))
+	mock.ExpectQuery("SELECT (.+) FROM transaction").WillReturnRows(sqlmock.NewRows([]string{"transactionid", "holdingid", "stockid", "cryptocurrencyid", "transaction_type", "quantity", "transaction_price", "transaction_currency", "transaction_date", "extid", "import_batchid", "created_at", "updated_at"}).
+		AddRow(expectedTransaction.TransactionID, expectedTransaction.HoldingID, expectedTransaction.StockID, expectedTransaction.CryptocurrencyID, expectedTransaction.TransactionType, expectedTransaction.Quantity, expectedTransaction.TransactionPrice.Amount, expectedTransaction.TransactionPrice.Currency, expectedTransaction.TransactionDate, expectedTransaction.Extid, expectedTransaction.ImportBatchID, expectedTransaction.CreatedAt, expectedTransaction.UpdatedAt))
 
 	// Act
 	result, err := repo.Get(context.Background(), filter, &skip, &take)
@@ -160,10 +164,12 @@ func TestGetTransactionByID_Ok(t *testing.T) {
 	}
 
 	expectedTransaction := entities.Transaction{
-		TransactionID: "f8352727-231e-4de1-8257-c235a0af5c4a",
+		TransactionID:    "f8352727-231e-4de1-8257-c235a0af5c4a",
+		Quantity:         decimal.RequireFromString("0.125"),
+		TransactionPrice: entities.NewMoney(decimal.RequireFromString("25000.5"), "EUR"),
 	}
-	mock.ExpectQuery("SELECT (.+) FROM transaction").WillReturnRows(sqlmock.NewRows([]string{"transactionid", "stockid", "cryptocurrencyid", "quantity", "transaction_price", "transaction_date", "created_at", "updated_at"}).
-		AddRow(expectedTransaction.TransactionID, expectedTransaction.StockID, expectedTransaction.CryptocurrencyID, expectedTransaction.Quantity, expectedTransaction.TransactionPrice, expectedTransaction.TransactionDate, expectedTransaction.CreatedAt, expectedTransaction.UpdatedAt))
+	mock.ExpectQuery("SELECT (.+) FROM transaction").WillReturnRows(sqlmock.NewRows([]string{"transactionid", "holdingid", "stockid", "cryptocurrencyid", "transaction_type", "quantity", "transaction_price", "transaction_currency", "transaction_date", "extid", "import_batchid", "created_at", "updated_at"}).
+		AddRow(expectedTransaction.TransactionID, expectedTransaction.HoldingID, expectedTransaction.StockID, expectedTransaction.CryptocurrencyID, expectedTransaction.TransactionType, expectedTransaction.Quantity, expectedTransaction.TransactionPrice.Amount, expectedTransaction.TransactionPrice.Currency, expectedTransaction.TransactionDate, expectedTransaction.Extid, expectedTransaction.ImportBatchID, expectedTransaction.CreatedAt, expectedTransaction.UpdatedAt))
 
 	// Act
 	result, err := repo.GetByID(context.Background(), expectedTransaction.TransactionID)
@@ -207,7 +213,7 @@ func TestGetTransactionByID_ResourceNotFound(t *testing.T) {
 			DB: db,
 		},
 	}
-	mock.ExpectQuery("SELECT (.+) FROM transaction").WillReturnRows(sqlmock.NewRows([]string{"transactionid", "stockid", "cryptocurrencyid", "quantity", "transaction_price", "transaction_date", "created_at", "updated_at"}))
+	mock.ExpectQuery("SELECT (.+) FROM transaction").WillReturnRows(sqlmock.NewRows([]string{"transactionid", "holdingid", "stockid", "cryptocurrencyid", "transaction_type", "quantity", "transaction_price", "transaction_currency", "transaction_date", "extid", "import_batchid", "created_at", "updated_at"}))
 
 	// Act
 	_, err := repo.GetByID(context.Background(), "")
@@ -216,6 +222,220 @@ func TestGetTransactionByID_ResourceNotFound(t *testing.T) {
 	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
 }
 
+// TestGetTransactionsByPortfolioID_Ok checks that GetByPortfolioID returns the expected response when the portfolio has transactions,
+// with an empty cryptocurrency ID when it is null
+func TestGetTransactionsByPortfolioID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &transactionRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedTransaction := entities.Transaction{
+		TransactionID:    "f8352727-231e-4de1-8257-c235a0af5c4a",
+		HoldingID:        "b3a1f3c2-9f45-4c61-9a8e-0b4d2a6f1e11",
+		StockID:          "5d0b2a9e-3c1f-4a7b-8e6d-2f9c1b4a7e30",
+		Quantity:         decimal.NewFromInt(-3),
+		TransactionPrice: entities.NewMoney(decimal.NewFromInt(12), "CZK"),
+		Extid:            "U1234567-20240305-1",
+		ImportBatchID:    "0e5c7f1a-8b2d-4c3e-9f6a-1d2b3c4e5f60",
+	}
+	mock.ExpectQuery("SELECT (.+) FROM transaction t INNER JOIN holding h").WithArgs("portfolio-id").WillReturnRows(sqlmock.NewRows([]string{"transactionid", "holdingid", "stockid", "cryptocurrencyid", "transaction_type", "quantity", "transaction_price", "transaction_currency", "transaction_date", "extid", "import_batchid", "created_at", "updated_at"}).
+		AddRow(expectedTransaction.TransactionID, expectedTransaction.HoldingID, expectedTransaction.StockID, nil, expectedTransaction.TransactionType, expectedTransaction.Quantity, expectedTransaction.TransactionPrice.Amount, expectedTransaction.TransactionPrice.Currency, expectedTransaction.TransactionDate, expectedTransaction.Extid, expectedTransaction.ImportBatchID, expectedTransaction.CreatedAt, expectedTransaction.UpdatedAt))
+
+	// Act
+	result, err := repo.GetByPortfolioID(context.Background(), "portfolio-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, len(result) == 1)
+
+	entity := *(result[0].(*entities.Transaction))
+	assert.Equal(t, expectedTransaction, entity)
+}
+
+// TestGetTransactionsByBrokerAccountID_Ok checks that GetByBrokerAccountID returns the transactions of the holdings attached to the account
+func TestGetTransactionsByBrokerAccountID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &transactionRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedTransaction := entities.Transaction{
+		TransactionID:    "f8352727-231e-4de1-8257-c235a0af5c4a",
+		HoldingID:        "b3a1f3c2-9f45-4c61-9a8e-0b4d2a6f1e11",
+		TransactionType:  entities.TransactionTypeDeposit,
+		Quantity:         decimal.NewFromInt(0),
+		TransactionPrice: entities.NewMoney(decimal.NewFromInt(1000), "EUR"),
+	}
+	mock.ExpectQuery("SELECT (.+) FROM transaction t INNER JOIN holding h (.+) WHERE h.broker_accountid = ").WithArgs("account-id").WillReturnRows(sqlmock.NewRows([]string{"transactionid", "holdingid", "stockid", "cryptocurrencyid", "transaction_type", "quantity", "transaction_price", "transaction_currency", "transaction_date", "extid", "import_batchid", "created_at", "updated_at"}).
+		AddRow(expectedTransaction.TransactionID, expectedTransaction.HoldingID, nil, nil, expectedTransaction.TransactionType, expectedTransaction.Quantity, expectedTransaction.TransactionPrice.Amount, expectedTransaction.TransactionPrice.Currency, expectedTransaction.TransactionDate, nil, nil, expectedTransaction.CreatedAt, expectedTransaction.UpdatedAt))
+
+	// Act
+	result, err := repo.GetByBrokerAccountID(context.Background(), "account-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, len(result) == 1)
+
+	entity := *(result[0].(*entities.Transaction))
+	assert.Equal(t, expectedTransaction, entity)
+}
+
+// TestGetTransactionsByBrokerAccountID_NoResourcesFound checks that GetByBrokerAccountID returns an error when the account has no transactions
+func TestGetTransactionsByBrokerAccountID_NoResourcesFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &transactionRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+	mock.ExpectQuery("SELECT (.+) FROM transaction").WillReturnRows(sqlmock.NewRows([]string{"transactionid", "holdingid", "stockid", "cryptocurrencyid", "transaction_type", "quantity", "transaction_price", "transaction_currency", "transaction_date", "extid", "import_batchid", "created_at", "updated_at"}))
+
+	// Act
+	_, err := repo.GetByBrokerAccountID(context.Background(), "")
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
+}
+
+// TestGetTransactionsByPortfolioID_SelectError checks that GetByPortfolioID returns an error when the select query fails
+func TestGetTransactionsByPortfolioID_SelectError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &transactionRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+	expectedError := "select error"
+	mock.ExpectQuery("SELECT (.+) FROM transaction").WillReturnError(fmt.Errorf(expectedError))
+
+	// Act
+	_, err := repo.GetByPortfolioID(context.Background(), "")
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetTransactionsByPortfolioID_NoResourcesFound checks that GetByPortfolioID returns an error when the portfolio has no transactions
+func TestGetTransactionsByPortfolioID_NoResourcesFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &transactionRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+	mock.ExpectQuery("SELECT (.+) FROM transaction").WillReturnRows(sqlmock.NewRows([]string{"transactionid", "holdingid", "stockid", "cryptocurrencyid", "transaction_type", "quantity", "transaction_price", "transaction_currency", "transaction_date", "extid", "import_batchid", "created_at", "updated_at"}))
+
+	// Act
+	_, err := repo.GetByPortfolioID(context.Background(), "")
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
+}
+
+// TestStreamTransactionsByPortfolioID_Ok checks that StreamByPortfolioID calls fn with each transaction of the portfolio in order
+func TestStreamTransactionsByPortfolioID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &transactionRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedTransactions := []entities.Transaction{
+		{TransactionID: "f8352727-231e-4de1-8257-c235a0af5c4a", StockID: "5d0b2a9e-3c1f-4a7b-8e6d-2f9c1b4a7e30", Quantity: decimal.NewFromInt(3), TransactionPrice: entities.NewMoney(decimal.NewFromInt(12), "CZK")},
+		{TransactionID: "0e5c7f1a-8b2d-4c3e-9f6a-1d2b3c4e5f60", CryptocurrencyID: "b3a1f3c2-9f45-4c61-9a8e-0b4d2a6f1e11", Quantity: decimal.NewFromInt(-1), TransactionPrice: entities.NewMoney(decimal.NewFromInt(20), "CZK")},
+	}
+	rows := sqlmock.NewRows([]string{"transactionid", "holdingid", "stockid", "cryptocurrencyid", "transaction_type", "quantity", "transaction_price", "transaction_currency", "transaction_date", "extid", "import_batchid", "created_at", "updated_at"})
+	for _, tr := range expectedTransactions {
+		rows.AddRow(tr.TransactionID, tr.HoldingID, tr.StockID, tr.CryptocurrencyID, tr.TransactionType, tr.Quantity, tr.TransactionPrice.Amount, tr.TransactionPrice.Currency, tr.TransactionDate, nil, nil, tr.CreatedAt, tr.UpdatedAt)
+	}
+	mock.ExpectQuery("SELECT (.+) FROM transaction t INNER JOIN holding h (.+) ORDER BY").WithArgs("portfolio-id").WillReturnRows(rows)
+
+	// Act
+	var result []entities.Transaction
+	err := repo.StreamByPortfolioID(context.Background(), "portfolio-id", func(tr entities.Transaction) error {
+		result = append(result, tr)
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedTransactions, result)
+}
+
+// TestStreamTransactionsByPortfolioID_FnError checks that StreamByPortfolioID stops at the first error returned by fn
+func TestStreamTransactionsByPortfolioID_FnError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &transactionRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM transaction").WillReturnRows(sqlmock.NewRows([]string{"transactionid", "holdingid", "stockid", "cryptocurrencyid", "transaction_type", "quantity", "transaction_price", "transaction_currency", "transaction_date", "extid", "import_batchid", "created_at", "updated_at"}).
+		AddRow("first", "", "stock-id", nil, "BUY", decimal.NewFromInt(1), decimal.NewFromInt(1), "CZK", now, nil, nil, now, now).
+		AddRow("second", "", "stock-id", nil, "BUY", decimal.NewFromInt(1), decimal.NewFromInt(1), "CZK", now, nil, nil, now, now))
+	expectedError := "write error"
+
+	// Act
+	calls := 0
+	err := repo.StreamByPortfolioID(context.Background(), "portfolio-id", func(entities.Transaction) error {
+		calls++
+		return fmt.Errorf(expectedError)
+	})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+	assert.Equal(t, 1, calls)
+}
+
+// TestStreamTransactionsByPortfolioID_SelectError checks that StreamByPortfolioID returns an error when the select query fails
+func TestStreamTransactionsByPortfolioID_SelectError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &transactionRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+	expectedError := "select error"
+	mock.ExpectQuery("SELECT (.+) FROM transaction").WillReturnError(fmt.Errorf(expectedError))
+
+	// Act
+	err := repo.StreamByPortfolioID(context.Background(), "", func(entities.Transaction) error { return nil })
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
 // TestUpdateTransaction_Ok checks that Update does not return an error when the received ID has a valid format
 func TestUpdateTransaction_Ok(t *testing.T) {
 	// Arrange
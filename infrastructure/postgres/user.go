@@ -28,14 +28,14 @@ func NewUserRepository(db *sql.DB) ports.UserRepository {
 
 func (r *userRepository) Create(ctx context.Context, user interface{}) (string, error) {
 	q := `
-	INSERT INTO "user" (firstname, lastname, email, password_hash, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6)
+	INSERT INTO "user" (firstname, lastname, email, password_hash, base_currency, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
         RETURNING userid;
     `
 
 	u := user.(entities.User)
 	row := r.DB.QueryRowContext(
-		ctx, q, u.Firstname, u.Lastname, u.Email, u.PasswordHash, u.CreatedAt, u.UpdatedAt,
+		ctx, q, u.Firstname, u.Lastname, u.Email, u.PasswordHash, u.BaseCurrency, u.CreatedAt, u.UpdatedAt,
 	)
 
 	err := row.Scan(&u.UserID)
@@ -64,7 +64,7 @@ func (r *userRepository) Get(ctx context.Context, filter map[string]interface{},
 	}
 
 	q := fmt.Sprintf(`
-	SELECT userid, firstname, lastname, email, password_hash, created_at, updated_at
+	SELECT userid, firstname, lastname, email, password_hash, base_currency, created_at, updated_at
 	    FROM "user" %s;
 	`, where)
 
@@ -78,7 +78,7 @@ func (r *userRepository) Get(ctx context.Context, filter map[string]interface{},
 	var users []interface{}
 	for rows.Next() {
 		var u entities.User
-		err = rows.Scan(&u.UserID, &u.Firstname, &u.Lastname, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt)
+		err = rows.Scan(&u.UserID, &u.Firstname, &u.Lastname, &u.Email, &u.PasswordHash, &u.BaseCurrency, &u.CreatedAt, &u.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -94,14 +94,14 @@ func (r *userRepository) Get(ctx context.Context, filter map[string]interface{},
 
 func (r *userRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
 	q := `
-    SELECT userid, firstname, lastname, email, password_hash, created_at, updated_at
+    SELECT userid, firstname, lastname, email, password_hash, base_currency, created_at, updated_at
         FROM "user" WHERE userid = $1;
     `
 
 	row := r.DB.QueryRowContext(ctx, q, ID)
 
 	var u entities.User
-	err := row.Scan(&u.UserID, &u.Firstname, &u.Lastname, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt)
+	err := row.Scan(&u.UserID, &u.Firstname, &u.Lastname, &u.Email, &u.PasswordHash, &u.BaseCurrency, &u.CreatedAt, &u.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = wrappers.NewNonExistentErr(err)
@@ -114,13 +114,13 @@ func (r *userRepository) GetByID(ctx context.Context, ID string) (interface{}, e
 
 func (r *userRepository) Update(ctx context.Context, ID string, user interface{}) error {
 	q := `
-	UPDATE "user" set firstname=$1, lastname=$2, email=$3, password_hash=$4, updated_at=$5
-	    WHERE userid=$6;
+	UPDATE "user" set firstname=$1, lastname=$2, email=$3, password_hash=$4, base_currency=$5, updated_at=$6
+	    WHERE userid=$7;
 	`
 
 	u := user.(entities.User)
 	result, err := r.DB.ExecContext(
-		ctx, q, u.Firstname, u.Lastname, u.Email, u.PasswordHash, u.UpdatedAt, ID,
+		ctx, q, u.Firstname, u.Lastname, u.Email, u.PasswordHash, u.BaseCurrency, u.UpdatedAt, ID,
 	)
 	if err != nil {
 		return err
@@ -166,13 +166,13 @@ func (r *userRepository) CreateMany(ctx context.Context, users []interface{}) ([
 		u := entity.(entities.User)
 
 		q := `
-		INSERT INTO "user" (firstname, lastname, email, password_hash, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO "user" (firstname, lastname, email, password_hash, base_currency, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
 			RETURNING userid;`
 
 		// Here, the query is executed on the transaction instance, and not applied to the database yet
 		row := tx.QueryRowContext(
-			ctx, q, u.Firstname, u.Lastname, u.Email, u.PasswordHash, u.CreatedAt, u.UpdatedAt,
+			ctx, q, u.Firstname, u.Lastname, u.Email, u.PasswordHash, u.BaseCurrency, u.CreatedAt, u.UpdatedAt,
 		)
 		err := row.Scan(&u.UserID)
 		if err != nil {
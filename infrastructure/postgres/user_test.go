@@ -92,8 +92,8 @@ func TestGetUser_Ok(t *testing.T) {
 	filter := map[string]interface{}{"email": "test-email", "name": "test-name"}
 	skip := 1
 	take := 1
-	mock.ExpectQuery("SELECT (.+) FROM \"user\"").WillReturnRows(sqlmock.NewRows([]string{"userid", "firstname", "lastname", "email", "password_hash", "created_at", "updated_at"}).
-		AddRow(expectedUser.UserID, expectedUser.Firstname, expectedUser.Lastname, expectedUser.Email, expectedUser.PasswordHash, expectedUser.CreatedAt, expectedUser.UpdatedAt))
+	mock.ExpectQuery("SELECT (.+) FROM \"user\"").WillReturnRows(sqlmock.NewRows([]string{"userid", "firstname", "lastname", "email", "password_hash", "base_currency", "created_at", "updated_at"}).
+		AddRow(expectedUser.UserID, expectedUser.Firstname, expectedUser.Lastname, expectedUser.Email, expectedUser.PasswordHash, expectedUser.BaseCurrency, expectedUser.CreatedAt, expectedUser.UpdatedAt))
 
 	// Act
 	result, err := repo.Get(context.Background(), filter, &skip, &take)
@@ -138,7 +138,7 @@ func TestGetUser_NoResourcesFound(t *testing.T) {
 			DB: db,
 		},
 	}
-	mock.ExpectQuery("SELECT (.+) FROM \"user\"").WillReturnRows(sqlmock.NewRows([]string{"userid", "firstname", "lastname", "email", "password_hash", "created_at", "updated_at"}))
+	mock.ExpectQuery("SELECT (.+) FROM \"user\"").WillReturnRows(sqlmock.NewRows([]string{"userid", "firstname", "lastname", "email", "password_hash", "base_currency", "created_at", "updated_at"}))
 
 	// Act
 	_, err := repo.Get(context.Background(), map[string]interface{}{}, nil, nil)
@@ -162,8 +162,8 @@ func TestGetUserByID_Ok(t *testing.T) {
 	expectedUser := entities.User{
 		UserID: "f8352727-231e-4de1-8257-c235a0af5c4a",
 	}
-	mock.ExpectQuery("SELECT (.+) FROM \"user\"").WillReturnRows(sqlmock.NewRows([]string{"userid", "firstname", "lastname", "email", "password_hash", "created_at", "updated_at"}).
-		AddRow(expectedUser.UserID, expectedUser.Firstname, expectedUser.Lastname, expectedUser.Email, expectedUser.PasswordHash, expectedUser.CreatedAt, expectedUser.UpdatedAt))
+	mock.ExpectQuery("SELECT (.+) FROM \"user\"").WillReturnRows(sqlmock.NewRows([]string{"userid", "firstname", "lastname", "email", "password_hash", "base_currency", "created_at", "updated_at"}).
+		AddRow(expectedUser.UserID, expectedUser.Firstname, expectedUser.Lastname, expectedUser.Email, expectedUser.PasswordHash, expectedUser.BaseCurrency, expectedUser.CreatedAt, expectedUser.UpdatedAt))
 
 	// Act
 	result, err := repo.GetByID(context.Background(), expectedUser.UserID)
@@ -207,7 +207,7 @@ func TestGetUserByID_ResourceNotFound(t *testing.T) {
 			DB: db,
 		},
 	}
-	mock.ExpectQuery("SELECT (.+) FROM \"user\"").WillReturnRows(sqlmock.NewRows([]string{"userid", "firstname", "lastname", "email", "password_hash", "created_at", "updated_at"}))
+	mock.ExpectQuery("SELECT (.+) FROM \"user\"").WillReturnRows(sqlmock.NewRows([]string{"userid", "firstname", "lastname", "email", "password_hash", "base_currency", "created_at", "updated_at"}))
 
 	// Act
 	_, err := repo.GetByID(context.Background(), "")
@@ -6,6 +6,7 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+	entities "github.com/tkudlicka/portflux-api/core/entities"
 )
 
 // HoldingRepository is an autogenerated mock type for the HoldingRepository type
@@ -37,17 +38,17 @@ func (_m *HoldingRepository) Create(ctx context.Context, entity interface{}) (st
 	return r0, r1
 }
 
-// CreateMany provides a mock function with given fields: ctx, entities
-func (_m *HoldingRepository) CreateMany(ctx context.Context, entities []interface{}) ([]string, error) {
-	ret := _m.Called(ctx, entities)
+// CreateMany provides a mock function with given fields: ctx, _a1
+func (_m *HoldingRepository) CreateMany(ctx context.Context, _a1 []interface{}) ([]string, error) {
+	ret := _m.Called(ctx, _a1)
 
 	var r0 []string
 	var r1 error
 	if rf, ok := ret.Get(0).(func(context.Context, []interface{}) ([]string, error)); ok {
-		return rf(ctx, entities)
+		return rf(ctx, _a1)
 	}
 	if rf, ok := ret.Get(0).(func(context.Context, []interface{}) []string); ok {
-		r0 = rf(ctx, entities)
+		r0 = rf(ctx, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]string)
@@ -55,7 +56,7 @@ func (_m *HoldingRepository) CreateMany(ctx context.Context, entities []interfac
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, []interface{}) error); ok {
-		r1 = rf(ctx, entities)
+		r1 = rf(ctx, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -129,6 +130,20 @@ func (_m *HoldingRepository) GetByID(ctx context.Context, ID string) (interface{
 	return r0, r1
 }
 
+// StreamByPortfolioID provides a mock function with given fields: ctx, portfolioID, fn
+func (_m *HoldingRepository) StreamByPortfolioID(ctx context.Context, portfolioID string, fn func(entities.Holding) error) error {
+	ret := _m.Called(ctx, portfolioID, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, func(entities.Holding) error) error); ok {
+		r0 = rf(ctx, portfolioID, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, ID, entity
 func (_m *HoldingRepository) Update(ctx context.Context, ID string, entity interface{}) error {
 	ret := _m.Called(ctx, ID, entity)
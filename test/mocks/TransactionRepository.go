@@ -6,6 +6,7 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+	entities "github.com/tkudlicka/portflux-api/core/entities"
 )
 
 // TransactionRepository is an autogenerated mock type for the TransactionRepository type
@@ -37,17 +38,17 @@ func (_m *TransactionRepository) Create(ctx context.Context, entity interface{})
 	return r0, r1
 }
 
-// CreateMany provides a mock function with given fields: ctx, entities
-func (_m *TransactionRepository) CreateMany(ctx context.Context, entities []interface{}) ([]string, error) {
-	ret := _m.Called(ctx, entities)
+// CreateMany provides a mock function with given fields: ctx, _a1
+func (_m *TransactionRepository) CreateMany(ctx context.Context, _a1 []interface{}) ([]string, error) {
+	ret := _m.Called(ctx, _a1)
 
 	var r0 []string
 	var r1 error
 	if rf, ok := ret.Get(0).(func(context.Context, []interface{}) ([]string, error)); ok {
-		return rf(ctx, entities)
+		return rf(ctx, _a1)
 	}
 	if rf, ok := ret.Get(0).(func(context.Context, []interface{}) []string); ok {
-		r0 = rf(ctx, entities)
+		r0 = rf(ctx, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]string)
@@ -55,7 +56,7 @@ func (_m *TransactionRepository) CreateMany(ctx context.Context, entities []inte
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, []interface{}) error); ok {
-		r1 = rf(ctx, entities)
+		r1 = rf(ctx, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -103,6 +104,32 @@ func (_m *TransactionRepository) Get(ctx context.Context, filter map[string]inte
 	return r0, r1
 }
 
+// GetByBrokerAccountID provides a mock function with given fields: ctx, brokerAccountID
+func (_m *TransactionRepository) GetByBrokerAccountID(ctx context.Context, brokerAccountID string) ([]interface{}, error) {
+	ret := _m.Called(ctx, brokerAccountID)
+
+	var r0 []interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]interface{}, error)); ok {
+		return rf(ctx, brokerAccountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []interface{}); ok {
+		r0 = rf(ctx, brokerAccountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, brokerAccountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByID provides a mock function with given fields: ctx, ID
 func (_m *TransactionRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
 	ret := _m.Called(ctx, ID)
@@ -129,6 +156,46 @@ func (_m *TransactionRepository) GetByID(ctx context.Context, ID string) (interf
 	return r0, r1
 }
 
+// GetByPortfolioID provides a mock function with given fields: ctx, portfolioID
+func (_m *TransactionRepository) GetByPortfolioID(ctx context.Context, portfolioID string) ([]interface{}, error) {
+	ret := _m.Called(ctx, portfolioID)
+
+	var r0 []interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]interface{}, error)); ok {
+		return rf(ctx, portfolioID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []interface{}); ok {
+		r0 = rf(ctx, portfolioID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, portfolioID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StreamByPortfolioID provides a mock function with given fields: ctx, portfolioID, fn
+func (_m *TransactionRepository) StreamByPortfolioID(ctx context.Context, portfolioID string, fn func(entities.Transaction) error) error {
+	ret := _m.Called(ctx, portfolioID, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, func(entities.Transaction) error) error); ok {
+		r0 = rf(ctx, portfolioID, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, ID, entity
 func (_m *TransactionRepository) Update(ctx context.Context, ID string, entity interface{}) error {
 	ret := _m.Called(ctx, ID, entity)
@@ -14,23 +14,23 @@ type TransactionService struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: ctx, user
-func (_m *TransactionService) Create(ctx context.Context, user models.CreateUserReq) (models.CreationResp, error) {
-	ret := _m.Called(ctx, user)
+// Create provides a mock function with given fields: ctx, userID, portfolioID, transaction
+func (_m *TransactionService) Create(ctx context.Context, userID string, portfolioID string, transaction models.CreateTransactionReq) (models.CreationResp, error) {
+	ret := _m.Called(ctx, userID, portfolioID, transaction)
 
 	var r0 models.CreationResp
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, models.CreateUserReq) (models.CreationResp, error)); ok {
-		return rf(ctx, user)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, models.CreateTransactionReq) (models.CreationResp, error)); ok {
+		return rf(ctx, userID, portfolioID, transaction)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, models.CreateUserReq) models.CreationResp); ok {
-		r0 = rf(ctx, user)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, models.CreateTransactionReq) models.CreationResp); ok {
+		r0 = rf(ctx, userID, portfolioID, transaction)
 	} else {
 		r0 = ret.Get(0).(models.CreationResp)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, models.CreateUserReq) error); ok {
-		r1 = rf(ctx, user)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, models.CreateTransactionReq) error); ok {
+		r1 = rf(ctx, userID, portfolioID, transaction)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -38,23 +38,39 @@ func (_m *TransactionService) Create(ctx context.Context, user models.CreateUser
 	return r0, r1
 }
 
-// CreateMany provides a mock function with given fields: ctx, users
-func (_m *TransactionService) CreateMany(ctx context.Context, users []models.CreateUserReq) (models.MultiCreationResp, error) {
-	ret := _m.Called(ctx, users)
+// Delete provides a mock function with given fields: ctx, userID, portfolioID, ID
+func (_m *TransactionService) Delete(ctx context.Context, userID string, portfolioID string, ID string) error {
+	ret := _m.Called(ctx, userID, portfolioID, ID)
 
-	var r0 models.MultiCreationResp
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, userID, portfolioID, ID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAll provides a mock function with given fields: ctx, userID, portfolioID
+func (_m *TransactionService) GetAll(ctx context.Context, userID string, portfolioID string) ([]models.TransactionResp, error) {
+	ret := _m.Called(ctx, userID, portfolioID)
+
+	var r0 []models.TransactionResp
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, []models.CreateUserReq) (models.MultiCreationResp, error)); ok {
-		return rf(ctx, users)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]models.TransactionResp, error)); ok {
+		return rf(ctx, userID, portfolioID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, []models.CreateUserReq) models.MultiCreationResp); ok {
-		r0 = rf(ctx, users)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []models.TransactionResp); ok {
+		r0 = rf(ctx, userID, portfolioID)
 	} else {
-		r0 = ret.Get(0).(models.MultiCreationResp)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.TransactionResp)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, []models.CreateUserReq) error); ok {
-		r1 = rf(ctx, users)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, portfolioID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -62,39 +78,23 @@ func (_m *TransactionService) CreateMany(ctx context.Context, users []models.Cre
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: ctx, ID
-func (_m *TransactionService) Delete(ctx context.Context, ID string) error {
-	ret := _m.Called(ctx, ID)
+// GetByID provides a mock function with given fields: ctx, userID, portfolioID, ID
+func (_m *TransactionService) GetByID(ctx context.Context, userID string, portfolioID string, ID string) (models.TransactionResp, error) {
+	ret := _m.Called(ctx, userID, portfolioID, ID)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, ID)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// GetAll provides a mock function with given fields: ctx
-func (_m *TransactionService) GetAll(ctx context.Context) ([]models.UserResp, error) {
-	ret := _m.Called(ctx)
-
-	var r0 []models.UserResp
+	var r0 models.TransactionResp
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]models.UserResp, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (models.TransactionResp, error)); ok {
+		return rf(ctx, userID, portfolioID, ID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []models.UserResp); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) models.TransactionResp); ok {
+		r0 = rf(ctx, userID, portfolioID, ID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.UserResp)
-		}
+		r0 = ret.Get(0).(models.TransactionResp)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, userID, portfolioID, ID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -102,23 +102,23 @@ func (_m *TransactionService) GetAll(ctx context.Context) ([]models.UserResp, er
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: ctx, ID
-func (_m *TransactionService) GetByID(ctx context.Context, ID string) (models.UserResp, error) {
-	ret := _m.Called(ctx, ID)
+// GetCashBalances provides a mock function with given fields: ctx, userID, portfolioID
+func (_m *TransactionService) GetCashBalances(ctx context.Context, userID string, portfolioID string) (models.CashBalancesResp, error) {
+	ret := _m.Called(ctx, userID, portfolioID)
 
-	var r0 models.UserResp
+	var r0 models.CashBalancesResp
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (models.UserResp, error)); ok {
-		return rf(ctx, ID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (models.CashBalancesResp, error)); ok {
+		return rf(ctx, userID, portfolioID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) models.UserResp); ok {
-		r0 = rf(ctx, ID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) models.CashBalancesResp); ok {
+		r0 = rf(ctx, userID, portfolioID)
 	} else {
-		r0 = ret.Get(0).(models.UserResp)
+		r0 = ret.Get(0).(models.CashBalancesResp)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, ID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, portfolioID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -126,13 +126,13 @@ func (_m *TransactionService) GetByID(ctx context.Context, ID string) (models.Us
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: ctx, ID, user
-func (_m *TransactionService) Update(ctx context.Context, ID string, user models.UpdateUserReq) error {
-	ret := _m.Called(ctx, ID, user)
+// Update provides a mock function with given fields: ctx, userID, portfolioID, ID, transaction
+func (_m *TransactionService) Update(ctx context.Context, userID string, portfolioID string, ID string, transaction models.UpdateTransactionReq) error {
+	ret := _m.Called(ctx, userID, portfolioID, ID, transaction)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, models.UpdateUserReq) error); ok {
-		r0 = rf(ctx, ID, user)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, models.UpdateTransactionReq) error); ok {
+		r0 = rf(ctx, userID, portfolioID, ID, transaction)
 	} else {
 		r0 = ret.Error(0)
 	}